@@ -808,13 +808,21 @@ func startInteractiveChat() {
 
 		fmt.Print("🤖 Assistant: ")
 
-		response, err := agent.Run(ctx, input)
+		events, err := agent.RunStream(ctx, input)
 		if err != nil {
 			fmt.Printf("❌ Error: %v\n", err)
 			continue
 		}
 
-		fmt.Println(response)
+		for event := range events {
+			switch event.Type {
+			case interfaces.AgentEventContent:
+				fmt.Print(event.Content)
+			case interfaces.AgentEventError:
+				fmt.Printf("\n❌ Error: %v", event.Error)
+			}
+		}
+		fmt.Println()
 	}
 }
 
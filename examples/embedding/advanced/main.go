@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -298,7 +297,7 @@ func main() {
 	}
 
 	// Calculate cosine similarity
-	similarity, err := calculateCosineSimilarity(vec1, vec2)
+	similarity, err := embedding.Similarity(vec1, vec2, embedding.MetricCosine)
 	if err != nil {
 		logger.Error(ctx, "Similarity calculation failed", map[string]interface{}{"error": err.Error()})
 		return
@@ -987,29 +986,6 @@ func validateWeaviateConnection(url string) error {
 	return nil
 }
 
-// calculateCosineSimilarity calculates the cosine similarity between two vectors
-func calculateCosineSimilarity(vec1, vec2 []float32) (float64, error) {
-	if len(vec1) != len(vec2) {
-		return 0, fmt.Errorf("vectors must have the same dimensions: %d != %d", len(vec1), len(vec2))
-	}
-
-	var dotProduct, magnitude1, magnitude2 float64
-	for i := 0; i < len(vec1); i++ {
-		dotProduct += float64(vec1[i] * vec2[i])
-		magnitude1 += float64(vec1[i] * vec1[i])
-		magnitude2 += float64(vec2[i] * vec2[i])
-	}
-
-	magnitude1 = float64(math.Sqrt(magnitude1))
-	magnitude2 = float64(math.Sqrt(magnitude2))
-
-	if magnitude1 == 0 || magnitude2 == 0 {
-		return 0, fmt.Errorf("vector magnitude is zero")
-	}
-
-	return dotProduct / (magnitude1 * magnitude2), nil
-}
-
 // validateDocumentsStored checks if documents were properly stored
 func validateDocumentsStored(ctx context.Context, logger logging.Logger, store interfaces.VectorStore, documents []interfaces.Document) bool {
 	logger.Info(ctx, "Validating document storage by retrieving sample documents...", nil)
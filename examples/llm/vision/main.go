@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/gemini"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/openai"
+)
+
+func main() {
+	imagePath := flag.String("image", "image.png", "Path of the image to ask about")
+	question := flag.String("question", "What is in this image?", "Question to ask about the image")
+	provider := flag.String("provider", "openai", "LLM provider: openai or gemini")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	// Read the image and detect its MIME type
+	data, err := os.ReadFile(*imagePath)
+	if err != nil {
+		log.Fatalf("Failed to read image: %v", err)
+	}
+	image := interfaces.ImageInput{
+		Data:     data,
+		MIMEType: http.DetectContentType(data),
+	}
+
+	var client interfaces.LLM
+	switch *provider {
+	case "openai":
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			log.Fatal("OPENAI_API_KEY environment variable is required")
+		}
+		client = openai.NewClient(apiKey, openai.WithModel("gpt-4o-mini"))
+	case "gemini":
+		apiKey := os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			log.Fatal("GEMINI_API_KEY environment variable is required")
+		}
+		client, err = gemini.NewClient(ctx, gemini.WithAPIKey(apiKey), gemini.WithModel(gemini.ModelGemini25Flash))
+		if err != nil {
+			log.Fatalf("Failed to create Gemini client: %v", err)
+		}
+	default:
+		log.Fatalf("Unknown provider %q", *provider)
+	}
+
+	fmt.Printf("Asking %s about %s (%s)...\n", client.Name(), *imagePath, image.MIMEType)
+
+	// Attach the image to the prompt
+	response, err := client.Generate(ctx, *question, interfaces.WithImages([]interfaces.ImageInput{image}))
+	if err != nil {
+		log.Fatalf("Failed to generate: %v", err)
+	}
+
+	fmt.Println(response)
+}
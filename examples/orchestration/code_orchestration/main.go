@@ -26,72 +26,6 @@ type userIDKey struct{}
 // Global logger instance
 var log logging.Logger
 
-// CustomCodeOrchestrator extends the default CodeOrchestrator to fix issues with task dependencies
-type CustomCodeOrchestrator struct {
-	registry *orchestration.AgentRegistry
-}
-
-// NewCustomCodeOrchestrator creates a new custom code orchestrator
-func NewCustomCodeOrchestrator(registry *orchestration.AgentRegistry) *CustomCodeOrchestrator {
-	return &CustomCodeOrchestrator{
-		registry: registry,
-	}
-}
-
-// ExecuteWorkflow executes a workflow with enhanced dependency handling
-func (o *CustomCodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *orchestration.Workflow) (string, error) {
-	log.Debug(ctx, "Using custom workflow executor with enhanced dependency handling", nil)
-
-	// Execute tasks in order (no parallelism for simplicity)
-	for _, task := range workflow.Tasks {
-		log.Debug(ctx, fmt.Sprintf("Executing task: %s (Agent: %s)", task.ID, task.AgentID), nil)
-
-		// Get the agent
-		agent, ok := o.registry.Get(task.AgentID)
-		if !ok {
-			err := fmt.Errorf("agent not found: %s", task.AgentID)
-			workflow.Errors[task.ID] = err
-			log.Error(ctx, fmt.Sprintf("Error: %v", err), nil)
-			continue
-		}
-
-		// Prepare input with results from dependencies
-		input := task.Input
-		for _, depID := range task.Dependencies {
-			if result, ok := workflow.Results[depID]; ok {
-				// Format the dependency result clearly
-				input = fmt.Sprintf("%s\n\n===== Result from %s =====\n%s\n=====\n",
-					input, depID, result)
-				log.Debug(ctx, fmt.Sprintf("Added dependency result from %s (length: %d)",
-					depID, len(result)), nil)
-			}
-		}
-
-		// Execute the agent
-		log.Debug(ctx, fmt.Sprintf("Running agent with input (length: %d)", len(input)), nil)
-		result, err := agent.Run(ctx, input)
-		if err != nil {
-			workflow.Errors[task.ID] = err
-			log.Error(ctx, fmt.Sprintf("Agent execution failed: %v", err), nil)
-			continue
-		}
-
-		// Store the result
-		workflow.Results[task.ID] = result
-		log.Debug(ctx, fmt.Sprintf("Task completed with result (length: %d)", len(result)), nil)
-	}
-
-	// Return the final result
-	if workflow.FinalTaskID != "" {
-		if result, ok := workflow.Results[workflow.FinalTaskID]; ok && result != "" {
-			return result, nil
-		}
-		return "", fmt.Errorf("final task result not found")
-	}
-
-	return "", nil
-}
-
 func main() {
 	// Initialize logger
 	log = logging.New()
@@ -129,7 +63,7 @@ func main() {
 	}
 
 	// Create code orchestrator for workflow execution
-	codeOrchestrator := NewCustomCodeOrchestrator(registry)
+	codeOrchestrator := orchestration.NewCodeOrchestrator(registry)
 
 	// Create context with a long timeout for the entire program
 	baseCtx, baseCancel := context.WithTimeout(context.Background(), 30*time.Minute)
@@ -209,47 +143,18 @@ func main() {
 
 		// Print results
 		log.Info(queryCtx, "Results:", nil)
-		hasResults := false
 		for taskID, taskResult := range workflow.Results {
 			if taskResult != "" {
-				hasResults = true
 				log.Info(queryCtx, fmt.Sprintf("- Task %s completed with result (%d chars)\n  Preview: %s",
 					taskID, len(taskResult), truncateString(taskResult, 70)), nil)
 			}
 		}
 
-		// Add detailed debugging information
-		debugWorkflowExecution(queryCtx, workflow)
+		// Report completed, failed and skipped tasks
+		log.Info(queryCtx, fmt.Sprintf("Workflow summary: %s", workflow.Summary()), nil)
 
 		if result.err != nil {
 			log.Error(queryCtx, fmt.Sprintf("Error: %v", result.err), nil)
-			printWorkflowErrors(queryCtx, workflow)
-
-			// If we have a "final task result not found" error but the final task's dependency has a result,
-			// try to use that result as the final response
-			if strings.Contains(result.err.Error(), "final task result not found") && hasResults {
-				log.Info(queryCtx, "Attempting to recover result from dependencies...", nil)
-
-				// Find the final task
-				var finalTask *orchestration.Task
-				for _, task := range workflow.Tasks {
-					if task.ID == workflow.FinalTaskID {
-						finalTask = task
-						break
-					}
-				}
-
-				// If the final task has dependencies with results, use the first one
-				if finalTask != nil && len(finalTask.Dependencies) > 0 {
-					depID := finalTask.Dependencies[0]
-					if depResult, ok := workflow.Results[depID]; ok && depResult != "" {
-						log.Info(queryCtx, fmt.Sprintf("Using result from dependency '%s' as final response:\n%s",
-							depID, depResult), nil)
-						continue
-					}
-				}
-			}
-
 			continue
 		}
 
@@ -412,73 +317,3 @@ func truncateString(s string, maxLen int) string {
 	}
 	return s[:maxLen] + "..."
 }
-
-// Helper function to print workflow errors
-func printWorkflowErrors(ctx context.Context, workflow *orchestration.Workflow) {
-	log.Info(ctx, "Workflow Errors:", nil)
-	for taskID, err := range workflow.Errors {
-		log.Error(ctx, fmt.Sprintf("- Task %s: %v", taskID, err), nil)
-	}
-
-	// Check for missing results
-	if workflow.FinalTaskID != "" && workflow.Results[workflow.FinalTaskID] == "" {
-		log.Debug(ctx, fmt.Sprintf("Final task '%s' has no result but no error was recorded", workflow.FinalTaskID), nil)
-
-		// Check if dependencies have results
-		for _, task := range workflow.Tasks {
-			if task.ID == workflow.FinalTaskID {
-				for _, depID := range task.Dependencies {
-					if result, ok := workflow.Results[depID]; ok {
-						log.Debug(ctx, fmt.Sprintf("Dependency '%s' has result: %s", depID, truncateString(result, 100)), nil)
-					} else {
-						log.Debug(ctx, fmt.Sprintf("Dependency '%s' has no result", depID), nil)
-					}
-				}
-			}
-		}
-	}
-}
-
-// Add this function to the file to debug and fix task execution issues
-func debugWorkflowExecution(ctx context.Context, workflow *orchestration.Workflow) {
-	log.Debug(ctx, "Workflow Execution Details:", nil)
-
-	// Print all tasks and their status
-	log.Debug(ctx, "Tasks Status:", nil)
-	for _, task := range workflow.Tasks {
-		statusStr := string(task.Status)
-		resultLen := 0
-		if result, ok := workflow.Results[task.ID]; ok {
-			resultLen = len(result)
-		}
-
-		log.Debug(ctx, fmt.Sprintf("- Task %s (Agent: %s): Status=%s, Result Length=%d",
-			task.ID, task.AgentID, statusStr, resultLen), nil)
-
-		// Print dependencies
-		if len(task.Dependencies) > 0 {
-			log.Debug(ctx, fmt.Sprintf("  Dependencies: %v", task.Dependencies), nil)
-			for _, depID := range task.Dependencies {
-				if depResult, ok := workflow.Results[depID]; ok {
-					log.Debug(ctx, fmt.Sprintf("  - Dependency %s has result of length %d", depID, len(depResult)), nil)
-					if len(depResult) > 0 {
-						log.Debug(ctx, fmt.Sprintf("    Preview: %s", truncateString(depResult, 50)), nil)
-					}
-				} else {
-					log.Debug(ctx, fmt.Sprintf("  - Dependency %s has no result", depID), nil)
-				}
-			}
-		}
-	}
-
-	// Print final task info
-	log.Debug(ctx, fmt.Sprintf("Final Task: %s", workflow.FinalTaskID), nil)
-	if result, ok := workflow.Results[workflow.FinalTaskID]; ok {
-		log.Debug(ctx, fmt.Sprintf("Final Task Result Length: %d", len(result)), nil)
-		if len(result) > 0 {
-			log.Debug(ctx, fmt.Sprintf("Final Task Result Preview: %s", truncateString(result, 50)), nil)
-		}
-	} else {
-		log.Debug(ctx, "Final Task has no result", nil)
-	}
-}
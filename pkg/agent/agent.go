@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
 	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/client"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/llm/openai"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"github.com/Ingenimax/agent-sdk-go/pkg/mcp"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tools"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
@@ -60,14 +63,35 @@ type Agent struct {
 	planStore            *executionplan.Store     // Store for execution plans
 	planGenerator        *executionplan.Generator // Generator for execution plans
 	planExecutor         *executionplan.Executor  // Executor for execution plans
+	auditSink            executionplan.AuditSink  // Sink recording the plan lifecycle for auditing
+	persistentPlans      PlanStore                // Store persisting execution plans across restarts
 	generatedAgentConfig *AgentConfig
 	generatedTaskConfigs TaskConfigs
 	responseFormat       *interfaces.ResponseFormat // Response format for the agent
 	llmConfig            *interfaces.LLMConfig
-	mcpServers           []interfaces.MCPServer   // MCP servers for the agent
-	lazyMCPConfigs       []LazyMCPConfig          // Lazy MCP server configurations
-	maxIterations        int                      // Maximum number of tool-calling iterations (default: 2)
-	streamConfig         *interfaces.StreamConfig // Streaming configuration for the agent
+	thinkingPolicy       interfaces.ThinkingPolicy   // Where thinking content of thinking models is surfaced
+	mcpServers           []interfaces.MCPServer      // MCP servers for the agent
+	lazyMCPConfigs       []LazyMCPConfig             // Lazy MCP server configurations
+	maxIterations        int                         // Maximum number of tool-calling iterations (default: 2)
+	streamConfig         *interfaces.StreamConfig    // Streaming configuration for the agent
+	autoGenerateTitle    bool                        // Whether to generate a conversation title after the first run
+	autoTagCategories    []string                    // Categories conversations are classified into after each run
+	validateToolArgs     bool                        // Whether tool call arguments are validated before executing tools
+	repairToolArgs       bool                        // Whether tool call arguments which are not valid JSON are repaired
+	skills               []Skill                     // Skills composed into the system prompt, tools and response format
+	toolApprovalHook     ToolApprovalFunc            // Hook consulted before every tool call
+	toolResultFormatter  ToolResultFormatter         // Formatter applied to tool outputs before they reach the model
+	toolNameSanitizer    ToolNameSanitizer           // Rewrites tool names the provider rejects (nil = SanitizeToolName)
+	toolsInPrompt        bool                        // Whether a human-readable tool list is appended to the system prompt
+	planAndExecute       bool                        // Whether runs plan and execute tool steps up front, see WithPlanAndExecute
+	planner              executionplan.PlanGenerator // Planner of the plan-and-execute mode (nil = built-in generator)
+	maxRevisions         int                         // Maximum number of revisions of the reflection step (0 = no reflection)
+	utilityLLM           interfaces.LLM              // LLM of internal calls such as titling, tagging and summarization (nil = the main LLM)
+	trimToContextWindow  bool                        // Whether the history is trimmed to the context window of the model
+	restrictToolContext  bool                        // Whether tools run with a context hiding the values of the run
+	pausedRuns           map[string]*pausedRun       // Runs paused by the tool approval hook, by run ID
+	pausedRunTTL         time.Duration               // Time a paused run can be resumed (0 = DefaultPausedRunTTL)
+	pausedRunsMu         sync.Mutex
 
 	// Remote agent fields
 	isRemote      bool                      // Whether this is a remote agent
@@ -90,6 +114,16 @@ func WithLLM(llm interfaces.LLM) Option {
 	}
 }
 
+// WithUtilityLLM sets a cheaper LLM for the internal calls of the agent that do not need the
+// main reasoning model: conversation titling, tagging and the summarization of summarizing
+// memories. Routers built with orchestration.NewAgentLLMRouter route with it as well. The main
+// LLM still produces the answers.
+func WithUtilityLLM(llm interfaces.LLM) Option {
+	return func(a *Agent) {
+		a.utilityLLM = llm
+	}
+}
+
 // WithMemory sets the memory for the agent
 func WithMemory(memory interfaces.Memory) Option {
 	return func(a *Agent) {
@@ -165,6 +199,9 @@ func WithAgentConfig(config AgentConfig, variables map[string]string) Option {
 	return func(a *Agent) {
 		systemPrompt := FormatSystemPromptFromConfig(config, variables)
 		a.systemPrompt = systemPrompt
+		if config.MaxIterations > 0 {
+			a.maxIterations = config.MaxIterations
+		}
 		// Add structured output if configured
 		if config.ResponseFormat != nil {
 			responseFormat, err := ConvertYAMLSchemaToResponseFormat(config.ResponseFormat)
@@ -216,6 +253,105 @@ func WithStreamConfig(config *interfaces.StreamConfig) Option {
 	}
 }
 
+// WithAutoGenerateTitle enables automatic conversation title generation after the first run.
+// The title is stored in the conversation metadata when the memory supports it.
+func WithAutoGenerateTitle(enabled bool) Option {
+	return func(a *Agent) {
+		a.autoGenerateTitle = enabled
+	}
+}
+
+// WithAutoTagging classifies the exchange into zero or more of the given categories after each run.
+// The tags accumulate across turns in the conversation metadata when the memory supports it.
+func WithAutoTagging(categories []string) Option {
+	return func(a *Agent) {
+		a.autoTagCategories = categories
+	}
+}
+
+// WithToolArgumentValidation validates tool call arguments against the tool parameters before
+// executing a tool. Invalid calls are answered with a JSON validation error the model can act on.
+func WithToolArgumentValidation(enabled bool) Option {
+	return func(a *Agent) {
+		a.validateToolArgs = enabled
+	}
+}
+
+// WithToolArgumentRepair repairs tool call arguments which are not valid JSON, such as truncated
+// objects or trailing commas, before executing a tool. Calls that can't be repaired are answered
+// with a JSON validation error asking the model to retry with valid JSON.
+func WithToolArgumentRepair(enabled bool) Option {
+	return func(a *Agent) {
+		a.repairToolArgs = enabled
+	}
+}
+
+// WithToolDescriptionsInPrompt sets whether a human-readable list of the available tools is
+// appended to the system prompt, in addition to the structured tool definitions sent to the
+// LLM. Some providers benefit from the reminder while others double-count the tools.
+func WithToolDescriptionsInPrompt(enabled bool) Option {
+	return func(a *Agent) {
+		a.toolsInPrompt = enabled
+	}
+}
+
+// WithContextWindowTrimming sets whether the oldest messages of the conversation history are
+// dropped before each LLM call until the request fits the context window of the model, see
+// memory.FitToWindow. Tokens are reserved for the system prompt and for the response, from
+// WithLLMConfig or else 4096. The LLM must report its model with a GetModel method.
+func WithContextWindowTrimming(enabled bool) Option {
+	return func(a *Agent) {
+		a.trimToContextWindow = enabled
+	}
+}
+
+// WithAuditSink records every plan creation, modification, approval, execution and cancellation to the sink
+func WithAuditSink(sink executionplan.AuditSink) Option {
+	return func(a *Agent) {
+		a.auditSink = sink
+	}
+}
+
+// WithThinkingPolicy sets where the thinking content of thinking models is surfaced:
+// hidden drops it, internal keeps it in traces and logs but strips it from the answer,
+// and visible includes it in the answer as a leading thinking block.
+func WithThinkingPolicy(policy interfaces.ThinkingPolicy) Option {
+	return func(a *Agent) {
+		a.thinkingPolicy = policy
+	}
+}
+
+// WithToolApprovalHook sets a hook consulted before every tool call.
+// The hook can approve, deny or pause a call; paused runs are resumed with ContinueWithToolResult.
+// The hook applies to runs without an execution plan, see WithRequirePlanApproval. The steps of
+// plan-and-execute runs can only be approved or denied, pausing them fails the run with
+// ErrToolCallPauseUnsupported.
+func WithToolApprovalHook(hook ToolApprovalFunc) Option {
+	return func(a *Agent) {
+		a.toolApprovalHook = hook
+	}
+}
+
+// WithPausedRunTTL sets how long a run paused by the tool approval hook can be resumed with
+// ContinueWithToolResult. Older paused runs are discarded. Defaults to DefaultPausedRunTTL.
+func WithPausedRunTTL(ttl time.Duration) Option {
+	return func(a *Agent) {
+		a.pausedRunTTL = ttl
+	}
+}
+
+// WithPlanAndExecute runs the agent in plan-and-execute mode: the planner first generates a
+// structured plan of tool steps, the steps are executed in sequence feeding outputs forward,
+// and the final result is synthesized from the step outputs. A nil planner uses the built-in
+// plan generator with the tools of the agent. Plans are executed without approval, see
+// RunPlanAndExecute to get the plan along with the result.
+func WithPlanAndExecute(planner executionplan.PlanGenerator) Option {
+	return func(a *Agent) {
+		a.planAndExecute = true
+		a.planner = planner
+	}
+}
+
 // WithURL creates a remote agent that communicates via gRPC
 func WithURL(url string) Option {
 	return func(a *Agent) {
@@ -273,6 +409,11 @@ func NewAgent(options ...Option) (*Agent, error) {
 		option(agent)
 	}
 
+	// Compose skills once every option is applied, so they extend the configured prompt and tools
+	if err := agent.applySkills(); err != nil {
+		return nil, fmt.Errorf("invalid skills: %w", err)
+	}
+
 	// Initialize default logger if none provided
 	if agent.logger == nil {
 		agent.logger = logging.New()
@@ -398,11 +539,50 @@ func NewAgentFromConfig(agentName string, configs AgentConfigs, variables map[st
 	// Combine all options
 	allOptions := append([]Option{configOption, nameOption}, options...)
 
-	return NewAgent(allOptions...)
+	agent, err := NewAgent(allOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report the tools the configuration expects but the agent was not given
+	for _, toolName := range config.Tools {
+		if !agent.hasTool(toolName) {
+			agent.logger.Warn(context.Background(), "Tool listed in agent configuration not provided", map[string]interface{}{
+				"agent": agentName,
+				"tool":  toolName,
+			})
+		}
+	}
+
+	return agent, nil
 }
 
-// CreateAgentForTask creates a new agent for a specific task
+// hasTool reports whether the agent has a tool with the given name
+func (a *Agent) hasTool(name string) bool {
+	for _, tool := range a.tools {
+		if tool.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAgentForTask creates a new agent for a specific task. The configuration of the task and
+// its agent is first checked with ValidateConfigs, see CreateAgentForTaskWithoutValidation to
+// skip the check.
 func CreateAgentForTask(taskName string, agentConfigs AgentConfigs, taskConfigs TaskConfigs, variables map[string]string, options ...Option) (*Agent, error) {
+	if _, exists := taskConfigs[taskName]; exists {
+		if err := validateTaskConfig(taskName, agentConfigs, taskConfigs, variables); err != nil {
+			return nil, err
+		}
+	}
+
+	return CreateAgentForTaskWithoutValidation(taskName, agentConfigs, taskConfigs, variables, options...)
+}
+
+// CreateAgentForTaskWithoutValidation creates a new agent for a specific task like
+// CreateAgentForTask, without validating the configuration
+func CreateAgentForTaskWithoutValidation(taskName string, agentConfigs AgentConfigs, taskConfigs TaskConfigs, variables map[string]string, options ...Option) (*Agent, error) {
 	agentName, err := GetAgentForTask(taskConfigs, taskName)
 	if err != nil {
 		return nil, err
@@ -433,7 +613,85 @@ func (a *Agent) Run(ctx context.Context, input string) (string, error) {
 	}
 
 	// Local agent execution
-	return a.runLocal(ctx, input)
+	var response string
+	if a.planAndExecute {
+		result, err := a.RunPlanAndExecute(ctx, input)
+		if err != nil {
+			return "", err
+		}
+		response = result.Result
+	} else {
+		var err error
+		response, err = a.runLocal(ctx, input)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	a.completeRun(ctx)
+
+	return response, nil
+}
+
+// completeRun updates the conversation metadata after a successful local run, whether it was
+// run with Run or RunStream
+func (a *Agent) completeRun(ctx context.Context) {
+	if a.autoGenerateTitle {
+		a.generateTitleIfMissing(ctx)
+	}
+	if len(a.autoTagCategories) > 0 {
+		a.tagConversation(ctx)
+	}
+}
+
+// generateTitleIfMissing generates and stores a conversation title if none exists yet
+func (a *Agent) generateTitleIfMissing(ctx context.Context) {
+	if a.memory == nil || a.llm == nil {
+		return
+	}
+
+	// Without metadata support the title cannot be persisted, so it would be regenerated on every run
+	if _, ok := a.memory.(interfaces.ConversationMetadataStore); !ok {
+		return
+	}
+
+	// If orgID is set on the agent, add it to the context
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	if _, ok := memory.GetTitle(ctx, a.memory); ok {
+		return
+	}
+
+	if _, err := memory.GenerateTitle(ctx, a.memory, a.GetUtilityLLM()); err != nil {
+		a.logger.Warn(ctx, "Failed to generate conversation title", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// tagConversation classifies the latest exchange and adds the matching tags to the conversation metadata
+func (a *Agent) tagConversation(ctx context.Context) {
+	if a.memory == nil || a.llm == nil {
+		return
+	}
+
+	// Without metadata support the tags cannot be stored
+	if _, ok := a.memory.(interfaces.ConversationMetadataStore); !ok {
+		return
+	}
+
+	// If orgID is set on the agent, add it to the context
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	if _, err := memory.TagConversation(ctx, a.memory, a.GetUtilityLLM(), a.autoTagCategories); err != nil {
+		a.logger.Warn(ctx, "Failed to tag conversation", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
 }
 
 // RunWithAuth executes the agent with an explicit auth token
@@ -444,7 +702,7 @@ func (a *Agent) RunWithAuth(ctx context.Context, input string, authToken string)
 	}
 
 	// For local agents, the auth token isn't used but we maintain compatibility
-	return a.runLocal(ctx, input)
+	return a.Run(ctx, input)
 }
 
 // RunStreamWithAuth executes the agent with streaming response and explicit auth token
@@ -509,6 +767,7 @@ func (a *Agent) runLocal(ctx context.Context, input string) (string, error) {
 	if a.orgID != "" {
 		ctx = multitenancy.WithOrgID(ctx, a.orgID)
 	}
+	ctx = a.withUtilityLLM(ctx)
 
 	// Start tracing if available
 	var span interfaces.Span
@@ -543,8 +802,8 @@ func (a *Agent) runLocal(ctx context.Context, input string) (string, error) {
 	}
 
 	// Check if the user is asking about the agent's role or identity
-	if a.systemPrompt != "" && a.isAskingAboutRole(input) {
-		response := a.generateRoleResponse()
+	if systemPrompt := a.runSystemPrompt(ctx); systemPrompt != "" && a.isAskingAboutRole(input) {
+		response := a.generateRoleResponse(systemPrompt)
 
 		// Add the role response to memory if available
 		if a.memory != nil {
@@ -559,6 +818,22 @@ func (a *Agent) runLocal(ctx context.Context, input string) (string, error) {
 		return response, nil
 	}
 
+	allTools, err := a.allowedTools(ctx, a.allTools(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	// If tools are available and plan approval is required, generate an execution plan
+	if (len(allTools) > 0) && a.requirePlanApproval {
+		return a.runWithExecutionPlan(ctx, a.runPlanGenerator(ctx, allTools), input)
+	}
+
+	// Otherwise, run without an execution plan
+	return a.runWithoutExecutionPlanWithTools(ctx, "", input, allTools)
+}
+
+// allTools returns the tools of the agent along with the tools of its MCP servers
+func (a *Agent) allTools(ctx context.Context) []interfaces.Tool {
 	allTools := a.tools
 
 	// Add MCP tools if available
@@ -577,14 +852,8 @@ func (a *Agent) runLocal(ctx context.Context, input string) (string, error) {
 		lazyMCPTools := a.createLazyMCPTools()
 		allTools = append(allTools, lazyMCPTools...)
 	}
-	// If tools are available and plan approval is required, generate an execution plan
-	if (len(allTools) > 0) && a.requirePlanApproval {
-		a.planGenerator = executionplan.NewGenerator(a.llm, allTools, a.systemPrompt)
-		return a.runWithExecutionPlan(ctx, input)
-	}
 
-	// Otherwise, run without an execution plan
-	return a.runWithoutExecutionPlanWithTools(ctx, input, allTools)
+	return allTools
 }
 
 // collectMCPTools collects tools from all MCP servers
@@ -640,8 +909,9 @@ func (a *Agent) createLazyMCPTools() []interfaces.Tool {
 	return lazyTools
 }
 
-// runWithoutExecutionPlanWithTools runs the agent without an execution plan but with the specified tools
-func (a *Agent) runWithoutExecutionPlanWithTools(ctx context.Context, input string, tools []interfaces.Tool) (string, error) {
+// runWithoutExecutionPlanWithTools runs the agent without an execution plan but with the specified tools.
+// runID identifies a run resumed after a tool approval pause and is empty for new runs.
+func (a *Agent) runWithoutExecutionPlanWithTools(ctx context.Context, runID string, input string, tools []interfaces.Tool) (string, error) {
 	// Get conversation history if memory is available
 	var prompt string
 	if a.memory != nil {
@@ -649,6 +919,9 @@ func (a *Agent) runWithoutExecutionPlanWithTools(ctx context.Context, input stri
 		if err != nil {
 			return "", fmt.Errorf("failed to get conversation history: %w", err)
 		}
+		if a.trimToContextWindow {
+			history = a.fitToContextWindow(ctx, history, tools)
+		}
 
 		// Format history into prompt
 		prompt = formatHistoryIntoPrompt(history)
@@ -656,14 +929,28 @@ func (a *Agent) runWithoutExecutionPlanWithTools(ctx context.Context, input stri
 		prompt = input
 	}
 
+	// Record the token usage of the LLM calls of the run on the stored response
+	usage := &runUsage{}
+	ctx = llm.WithCompletionObserver(ctx, usage.observe)
+
 	// Generate response with tools if available
 	var response string
 	var err error
 
+	// Gate tool calls behind the approval hook if configured
+	var run *approvalRun
+	ungatedTools := tools
+	tools = a.restrictToolContexts(tools)
+	if a.toolApprovalHook != nil && len(tools) > 0 {
+		ctx, run, tools = a.startApprovalRun(ctx, runID, tools)
+		defer run.cancel()
+	}
+	tools = a.formatToolResults(a.sanitizeToolNames(tools))
+
 	// Add system prompt as a generate option
 	generateOptions := []interfaces.GenerateOption{}
-	if a.systemPrompt != "" {
-		generateOptions = append(generateOptions, openai.WithSystemMessage(a.systemPrompt))
+	if systemMessage := a.systemMessage(ctx, tools); systemMessage != "" {
+		generateOptions = append(generateOptions, openai.WithSystemMessage(systemMessage))
 	}
 
 	// Add response format as a generate option if available
@@ -677,12 +964,27 @@ func (a *Agent) runWithoutExecutionPlanWithTools(ctx context.Context, input stri
 		})
 	}
 
+	if a.thinkingPolicy != "" {
+		generateOptions = append(generateOptions, interfaces.WithThinkingPolicy(a.thinkingPolicy))
+	}
+
+	if a.validateToolArgs {
+		generateOptions = append(generateOptions, interfaces.WithToolArgumentValidation())
+	}
+	if a.repairToolArgs {
+		generateOptions = append(generateOptions, interfaces.WithToolArgumentRepair())
+	}
+
 	// Add max iterations option
 	generateOptions = append(generateOptions, interfaces.WithMaxIterations(a.maxIterations))
 
 	// Pass memory to LLM for tool call storage
 	if a.memory != nil && len(tools) > 0 {
-		generateOptions = append(generateOptions, interfaces.WithMemory(a.memory))
+		llmMemory := a.memory
+		if run != nil {
+			llmMemory = run.memory(llmMemory)
+		}
+		generateOptions = append(generateOptions, interfaces.WithMemory(llmMemory))
 	}
 
 	if len(tools) > 0 {
@@ -691,10 +993,21 @@ func (a *Agent) runWithoutExecutionPlanWithTools(ctx context.Context, input stri
 		response, err = a.llm.Generate(ctx, prompt, generateOptions...)
 	}
 
+	if run != nil && run.pausedCall() != nil {
+		return "", a.savePausedRun(ctx, run, input, ungatedTools)
+	}
+
 	if err != nil {
 		return "", fmt.Errorf("failed to generate response: %w", err)
 	}
 
+	response = a.applyThinkingPolicy(ctx, response)
+
+	// Critique and revise the draft answer if reflection is enabled
+	if a.maxRevisions > 0 {
+		response = a.reflect(ctx, input, response)
+	}
+
 	// Apply guardrails to output if available
 	if a.guardrails != nil {
 		guardedResponse, err := a.guardrails.ProcessOutput(ctx, response)
@@ -707,8 +1020,9 @@ func (a *Agent) runWithoutExecutionPlanWithTools(ctx context.Context, input stri
 	// Add agent message to memory
 	if a.memory != nil {
 		if err := a.memory.AddMessage(ctx, interfaces.Message{
-			Role:    "assistant",
-			Content: response,
+			Role:     "assistant",
+			Content:  response,
+			Metadata: usage.metadata(),
 		}); err != nil {
 			return "", fmt.Errorf("failed to add agent message to memory: %w", err)
 		}
@@ -717,6 +1031,43 @@ func (a *Agent) runWithoutExecutionPlanWithTools(ctx context.Context, input stri
 	return response, nil
 }
 
+// systemMessage returns the system prompt of the agent, followed by the list of the tools
+// when WithToolDescriptionsInPrompt is enabled
+func (a *Agent) systemMessage(ctx context.Context, tools []interfaces.Tool) string {
+	systemPrompt := a.runSystemPrompt(ctx)
+	if !a.toolsInPrompt || len(tools) == 0 {
+		return systemPrompt
+	}
+
+	var sb strings.Builder
+	if systemPrompt != "" {
+		sb.WriteString(systemPrompt)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("Available tools:\n")
+	for _, tool := range tools {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", tool.Name(), tool.Description()))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// applyThinkingPolicy strips the thinking block from a response unless the thinking policy makes it visible.
+// With the internal policy the stripped thinking is logged.
+func (a *Agent) applyThinkingPolicy(ctx context.Context, response string) string {
+	if a.thinkingPolicy == "" || a.thinkingPolicy == interfaces.ThinkingPolicyVisible {
+		return response
+	}
+
+	thinking, answer := interfaces.SplitThinking(response)
+	if thinking != "" && a.thinkingPolicy == interfaces.ThinkingPolicyInternal {
+		a.logger.Debug(ctx, "Thinking content stripped from response", map[string]interface{}{
+			"agent":    a.name,
+			"thinking": thinking,
+		})
+	}
+	return answer
+}
+
 // extractPlanAction attempts to extract a plan action from the user input
 // Returns taskID, action, and remaining input
 func (a *Agent) extractPlanAction(input string) (string, string, string) {
@@ -727,7 +1078,7 @@ func (a *Agent) extractPlanAction(input string) (string, string, string) {
 
 // handlePlanAction handles actions related to an existing plan
 func (a *Agent) handlePlanAction(ctx context.Context, taskID, action, input string) (string, error) {
-	plan, exists := a.planStore.GetPlanByTaskID(taskID)
+	plan, exists := a.loadPlan(ctx, taskID)
 	if !exists {
 		return "", fmt.Errorf("plan with task ID %s not found", taskID)
 	}
@@ -738,7 +1089,7 @@ func (a *Agent) handlePlanAction(ctx context.Context, taskID, action, input stri
 	case "modify":
 		return a.modifyPlan(ctx, plan, input)
 	case "cancel":
-		return a.cancelPlan(plan)
+		return a.cancelPlan(ctx, plan)
 	case "status":
 		return a.getPlanStatus(plan)
 	default:
@@ -750,6 +1101,8 @@ func (a *Agent) handlePlanAction(ctx context.Context, taskID, action, input stri
 func (a *Agent) approvePlan(ctx context.Context, plan *executionplan.ExecutionPlan) (string, error) {
 	plan.UserApproved = true
 	plan.Status = executionplan.StatusApproved
+	a.updateStoredPlan(ctx, plan)
+	a.audit(ctx, executionplan.NewAuditRecord(ctx, executionplan.AuditPlanApproved, plan))
 
 	// Add the approval to memory
 	if a.memory != nil {
@@ -763,9 +1116,14 @@ func (a *Agent) approvePlan(ctx context.Context, plan *executionplan.ExecutionPl
 
 	// Execute the plan
 	result, err := a.planExecutor.ExecutePlan(ctx, plan)
+	a.updateStoredPlan(ctx, plan)
 	if err != nil {
+		record := executionplan.NewAuditRecord(ctx, executionplan.AuditPlanFailed, plan)
+		record.Error = err.Error()
+		a.audit(ctx, record)
 		return "", fmt.Errorf("failed to execute plan: %w", err)
 	}
+	a.audit(ctx, executionplan.NewAuditRecord(ctx, executionplan.AuditPlanExecuted, plan))
 
 	// Add the execution result to memory
 	if a.memory != nil {
@@ -780,8 +1138,20 @@ func (a *Agent) approvePlan(ctx context.Context, plan *executionplan.ExecutionPl
 	return result, nil
 }
 
+// runPlanGenerator returns a generator of execution plans for the tools and the system prompt
+// of a run. It is not stored on the agent, so that concurrent runs cannot see each other's
+// system prompt overrides.
+func (a *Agent) runPlanGenerator(ctx context.Context, tools []interfaces.Tool) *executionplan.Generator {
+	return executionplan.NewGenerator(a.llm, tools, a.runSystemPrompt(ctx))
+}
+
 // modifyPlan modifies a plan based on user input
 func (a *Agent) modifyPlan(ctx context.Context, plan *executionplan.ExecutionPlan, input string) (string, error) {
+	tools, err := a.allowedTools(ctx, a.allTools(ctx))
+	if err != nil {
+		return "", err
+	}
+
 	// Add the modification request to memory
 	if a.memory != nil {
 		if err := a.memory.AddMessage(ctx, interfaces.Message{
@@ -793,14 +1163,11 @@ func (a *Agent) modifyPlan(ctx context.Context, plan *executionplan.ExecutionPla
 	}
 
 	// Modify the plan
-	modifiedPlan, err := a.planGenerator.ModifyExecutionPlan(ctx, plan, input)
+	modifiedPlan, err := a.modifyExecutionPlan(ctx, a.runPlanGenerator(ctx, tools), plan, input)
 	if err != nil {
 		return "", fmt.Errorf("failed to modify plan: %w", err)
 	}
 
-	// Update the plan in the store
-	a.planStore.StorePlan(modifiedPlan)
-
 	// Format the modified plan
 	formattedPlan := executionplan.FormatExecutionPlan(modifiedPlan)
 
@@ -818,8 +1185,10 @@ func (a *Agent) modifyPlan(ctx context.Context, plan *executionplan.ExecutionPla
 }
 
 // cancelPlan cancels a plan
-func (a *Agent) cancelPlan(plan *executionplan.ExecutionPlan) (string, error) {
+func (a *Agent) cancelPlan(ctx context.Context, plan *executionplan.ExecutionPlan) (string, error) {
 	a.planExecutor.CancelPlan(plan)
+	a.updateStoredPlan(ctx, plan)
+	a.audit(ctx, executionplan.NewAuditRecord(ctx, executionplan.AuditPlanCancelled, plan))
 
 	return "Plan cancelled. What would you like to do instead?", nil
 }
@@ -833,16 +1202,13 @@ func (a *Agent) getPlanStatus(plan *executionplan.ExecutionPlan) (string, error)
 }
 
 // runWithExecutionPlan runs the agent with an execution plan
-func (a *Agent) runWithExecutionPlan(ctx context.Context, input string) (string, error) {
+func (a *Agent) runWithExecutionPlan(ctx context.Context, generator *executionplan.Generator, input string) (string, error) {
 	// Generate an execution plan
-	plan, err := a.planGenerator.GenerateExecutionPlan(ctx, input)
+	plan, err := a.generateExecutionPlan(ctx, generator, input)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate execution plan: %w", err)
 	}
 
-	// Store the plan
-	a.planStore.StorePlan(plan)
-
 	// Format the plan for display
 	formattedPlan := executionplan.FormatExecutionPlan(plan)
 
@@ -890,6 +1256,18 @@ func formatHistoryIntoPrompt(history []interfaces.Message) string {
 			content = convertToHumanReadable(content)
 		}
 
+		// Render tool calls so that tool-only assistant turns are not lost
+		if msg.Role == "assistant" && len(msg.ToolCalls) > 0 {
+			parts := []string{}
+			if content != "" {
+				parts = append(parts, content)
+			}
+			for _, toolCall := range msg.ToolCalls {
+				parts = append(parts, fmt.Sprintf("[Called tool %s with arguments: %s]", toolCall.Name, toolCall.Arguments))
+			}
+			content = strings.Join(parts, "\n")
+		}
+
 		// Add role marker and content
 		prompt += roleMarker + ": " + content
 
@@ -958,14 +1336,61 @@ func (a *Agent) ApproveExecutionPlan(ctx context.Context, plan *executionplan.Ex
 	return a.approvePlan(ctx, plan)
 }
 
-// ModifyExecutionPlan modifies an execution plan based on user input
+// ModifyExecutionPlan modifies an execution plan based on user input. The modified plan
+// replaces the original plan in the plan store.
 func (a *Agent) ModifyExecutionPlan(ctx context.Context, plan *executionplan.ExecutionPlan, modifications string) (*executionplan.ExecutionPlan, error) {
-	return a.planGenerator.ModifyExecutionPlan(ctx, plan, modifications)
+	return a.modifyExecutionPlan(ctx, a.planGenerator, plan, modifications)
 }
 
-// GenerateExecutionPlan generates an execution plan
+// modifyExecutionPlan modifies an execution plan with the given generator and stores it
+func (a *Agent) modifyExecutionPlan(ctx context.Context, generator *executionplan.Generator, plan *executionplan.ExecutionPlan, modifications string) (*executionplan.ExecutionPlan, error) {
+	modifiedPlan, err := generator.ModifyExecutionPlan(ctx, plan, modifications)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.storePlan(ctx, modifiedPlan); err != nil {
+		return nil, err
+	}
+
+	record := executionplan.NewAuditRecord(ctx, executionplan.AuditPlanModified, modifiedPlan)
+	record.Diff = executionplan.PlanDiff(plan, modifiedPlan)
+	a.audit(ctx, record)
+
+	return modifiedPlan, nil
+}
+
+// GenerateExecutionPlan generates an execution plan and adds it to the plan store
 func (a *Agent) GenerateExecutionPlan(ctx context.Context, input string) (*executionplan.ExecutionPlan, error) {
-	return a.planGenerator.GenerateExecutionPlan(ctx, input)
+	return a.generateExecutionPlan(ctx, a.planGenerator, input)
+}
+
+// generateExecutionPlan generates an execution plan with the given generator and stores it
+func (a *Agent) generateExecutionPlan(ctx context.Context, generator *executionplan.Generator, input string) (*executionplan.ExecutionPlan, error) {
+	plan, err := generator.GenerateExecutionPlan(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.storePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	a.audit(ctx, executionplan.NewAuditRecord(ctx, executionplan.AuditPlanCreated, plan))
+	return plan, nil
+}
+
+// audit records a plan lifecycle event if an audit sink is configured.
+// Failures are logged and do not interrupt the plan lifecycle.
+func (a *Agent) audit(ctx context.Context, record executionplan.AuditRecord) {
+	if a.auditSink == nil {
+		return
+	}
+	if err := a.auditSink.Record(ctx, record); err != nil {
+		a.logger.Warn(ctx, "Failed to record plan audit event", map[string]interface{}{
+			"action":  string(record.Action),
+			"task_id": record.TaskID,
+			"error":   err.Error(),
+		})
+	}
 }
 
 // isAskingAboutRole determines if the user is asking about the agent's role or identity
@@ -1007,9 +1432,9 @@ func (a *Agent) isAskingAboutRole(input string) bool {
 }
 
 // generateRoleResponse creates a response based on the agent's system prompt
-func (a *Agent) generateRoleResponse() string {
+func (a *Agent) generateRoleResponse(systemPrompt string) string {
 	// If the prompt is empty, return a generic response
-	if a.systemPrompt == "" || a.llm == nil {
+	if systemPrompt == "" || a.llm == nil {
 		return "I'm an AI assistant designed to help you with various tasks and answer your questions. How can I assist you today?"
 	}
 
@@ -1034,13 +1459,13 @@ Your response should:
 3. Mention 2-3 key areas you can help with
 4. End with a friendly question about how you can assist the user
 
-Response:`, agentName, a.systemPrompt, agentName)
+Response:`, agentName, systemPrompt, agentName)
 
 	// Generate a response using the LLM with the system prompt as context
 	generateOptions := []interfaces.GenerateOption{}
 
 	// Use the same system prompt to ensure consistent persona
-	generateOptions = append(generateOptions, openai.WithSystemMessage(a.systemPrompt))
+	generateOptions = append(generateOptions, openai.WithSystemMessage(systemPrompt))
 
 	// Generate the response
 	response, err := a.llm.Generate(context.Background(), prompt, generateOptions...)
@@ -1102,14 +1527,14 @@ func (a *Agent) GetGeneratedTaskConfigs() TaskConfigs {
 	return a.generatedTaskConfigs
 }
 
-// GetTaskByID returns a task by its ID
+// GetTaskByID returns a task by its ID, loading it from the plan store if it is not in memory
 func (a *Agent) GetTaskByID(taskID string) (*executionplan.ExecutionPlan, bool) {
-	return a.planStore.GetPlanByTaskID(taskID)
+	return a.loadPlan(context.Background(), taskID)
 }
 
-// ListTasks returns a list of all tasks
+// ListTasks returns a list of all tasks, including those in the plan store
 func (a *Agent) ListTasks() []*executionplan.ExecutionPlan {
-	return a.planStore.ListPlans()
+	return a.listPlans(context.Background())
 }
 
 // GetName returns the agent's name
@@ -1141,6 +1566,32 @@ func (a *Agent) GetLLM() interfaces.LLM {
 	return a.llm
 }
 
+// withUtilityLLM makes summarizing memories summarize with the utility LLM, if set
+func (a *Agent) withUtilityLLM(ctx context.Context) context.Context {
+	if a.utilityLLM == nil {
+		return ctx
+	}
+	return memory.WithSummaryLLM(ctx, a.utilityLLM)
+}
+
+// GetUtilityLLM returns the LLM of the internal calls of the agent, the utility LLM if set and the
+// main LLM otherwise
+func (a *Agent) GetUtilityLLM() interfaces.LLM {
+	if a.utilityLLM != nil {
+		return a.utilityLLM
+	}
+	return a.llm
+}
+
+// SetLLM replaces the LLM used by the agent, e.g. to wrap it with a shared rate limiter.
+// It is not safe to call while the agent runs, so it must be called before the first run.
+func (a *Agent) SetLLM(llm interfaces.LLM) {
+	a.llm = llm
+	if a.planGenerator != nil {
+		a.planGenerator = executionplan.NewGenerator(llm, a.tools, a.systemPrompt)
+	}
+}
+
 // GetMemory returns the memory instance (for use in custom functions)
 func (a *Agent) GetMemory() interfaces.Memory {
 	return a.memory
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestExecutionPlanAuditLog(t *testing.T) {
+	plans := []string{
+		`{"description": "Look up the weather", "steps": [{"toolName": "get_weather", "description": "Fetch weather", "input": "Paris"}]}`,
+		`{"description": "Look up the weather", "steps": [{"toolName": "get_weather", "description": "Fetch weather", "input": "London"}]}`,
+	}
+	var calls int
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			plan := plans[calls]
+			calls++
+			return plan, nil
+		},
+	}
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "sunny in " + input, nil
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := executionplan.NewFileAuditSink(path)
+	if err != nil {
+		t.Fatalf("failed to create audit sink: %v", err)
+	}
+	defer sink.Close()
+
+	agent, err := NewAgent(WithLLM(llm), WithTools(weather), WithAuditSink(sink))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "acme")
+	ctx = executionplan.WithActor(ctx, "alice")
+
+	plan, err := agent.GenerateExecutionPlan(ctx, "What's the weather?")
+	if err != nil {
+		t.Fatalf("failed to generate plan: %v", err)
+	}
+	plan, err = agent.ModifyExecutionPlan(ctx, plan, "Use London instead")
+	if err != nil {
+		t.Fatalf("failed to modify plan: %v", err)
+	}
+	result, err := agent.ApproveExecutionPlan(ctx, plan)
+	if err != nil {
+		t.Fatalf("failed to approve plan: %v", err)
+	}
+	if !strings.Contains(result, "sunny in London") {
+		t.Errorf("unexpected execution result: %q", result)
+	}
+
+	records, err := executionplan.ReadAuditLog(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+
+	expected := []struct {
+		action executionplan.AuditAction
+		status executionplan.ExecutionPlanStatus
+	}{
+		{executionplan.AuditPlanCreated, executionplan.StatusPendingApproval},
+		{executionplan.AuditPlanModified, executionplan.StatusPendingApproval},
+		{executionplan.AuditPlanApproved, executionplan.StatusApproved},
+		{executionplan.AuditPlanExecuted, executionplan.StatusCompleted},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d audit records, got %d", len(expected), len(records))
+	}
+
+	for i, record := range records {
+		if record.Action != expected[i].action || record.Status != expected[i].status {
+			t.Errorf("record %d: expected %s/%s, got %s/%s", i, expected[i].action, expected[i].status, record.Action, record.Status)
+		}
+		if record.Actor != "alice" || record.OrgID != "acme" || record.TaskID != plan.TaskID {
+			t.Errorf("record %d: unexpected actor/org/task: %+v", i, record)
+		}
+		if i > 0 && record.Timestamp.Before(records[i-1].Timestamp) {
+			t.Errorf("record %d is out of order", i)
+		}
+	}
+
+	diff := strings.Join(records[1].Diff, "\n")
+	if !strings.Contains(diff, `- step 1: {"ToolName":"get_weather","Input":"Paris"`) ||
+		!strings.Contains(diff, `+ step 1: {"ToolName":"get_weather","Input":"London"`) {
+		t.Errorf("unexpected modification diff: %s", diff)
+	}
+}
@@ -0,0 +1,166 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// checkpointVersion is the version of the checkpoint format written by Checkpoint
+const checkpointVersion = 1
+
+// CheckpointData is the portable state of an agent conversation produced by Checkpoint
+type CheckpointData struct {
+	// Version is the version of the checkpoint format
+	Version int `json:"version"`
+	// CreatedAt is the time the checkpoint was taken
+	CreatedAt time.Time `json:"created_at"`
+	// Messages is the conversation history of the conversation in context
+	Messages []interfaces.Message `json:"messages,omitempty"`
+	// State contains the conversation metadata, if the memory implements interfaces.ConversationMetadataStore
+	State map[string]interface{} `json:"state,omitempty"`
+	// Plans contains the execution plans of the conversation that have not finished yet
+	Plans []*executionplan.ExecutionPlan `json:"plans,omitempty"`
+}
+
+// Checkpoint serializes the memory, conversation state and pending execution plans of the
+// conversation in context, so they can be restored later with Restore. Only the plans of the
+// organization and conversation in context are included.
+func (a *Agent) Checkpoint(ctx context.Context) ([]byte, error) {
+	if a.isRemote {
+		return nil, fmt.Errorf("checkpoints are not supported for remote agents")
+	}
+
+	// If orgID is set on the agent, add it to the context
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	checkpoint := CheckpointData{
+		Version:   checkpointVersion,
+		CreatedAt: time.Now(),
+	}
+
+	if a.memory != nil {
+		messages, err := a.memory.GetMessages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get conversation history: %w", err)
+		}
+		checkpoint.Messages = messages
+
+		if store, ok := a.memory.(interfaces.ConversationMetadataStore); ok {
+			state, err := store.GetConversationMetadata(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get conversation metadata: %w", err)
+			}
+			checkpoint.State = state
+		}
+	}
+
+	if a.planStore != nil {
+		orgID, conversationID := planScope(ctx)
+		for _, plan := range a.planStore.ListPlans() {
+			// Plans of other organizations and conversations are not part of the checkpoint
+			if plan.OrgID != orgID || plan.ConversationID != conversationID {
+				continue
+			}
+			if isPendingPlan(plan) {
+				checkpoint.Plans = append(checkpoint.Plans, plan)
+			}
+		}
+		// Keep the output deterministic
+		sort.Slice(checkpoint.Plans, func(i, j int) bool {
+			return checkpoint.Plans[i].CreatedAt.Before(checkpoint.Plans[j].CreatedAt)
+		})
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	return data, nil
+}
+
+// Restore replaces the memory and conversation state of the conversation in context with the
+// contents of a checkpoint, and stores its pending execution plans. Checkpoints containing plans
+// of another organization or conversation are rejected.
+func (a *Agent) Restore(ctx context.Context, data []byte) error {
+	if a.isRemote {
+		return fmt.Errorf("checkpoints are not supported for remote agents")
+	}
+
+	var checkpoint CheckpointData
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+	if checkpoint.Version != checkpointVersion {
+		return fmt.Errorf("unsupported checkpoint version: %d", checkpoint.Version)
+	}
+
+	// If orgID is set on the agent, add it to the context
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	// Reject plans of other organizations or conversations before changing anything
+	orgID, conversationID := planScope(ctx)
+	for _, plan := range checkpoint.Plans {
+		if plan.OrgID != orgID || plan.ConversationID != conversationID {
+			return fmt.Errorf("checkpoint plan %s belongs to another organization or conversation", plan.TaskID)
+		}
+	}
+
+	if a.memory == nil {
+		if len(checkpoint.Messages) > 0 || len(checkpoint.State) > 0 {
+			return fmt.Errorf("cannot restore conversation history without memory")
+		}
+	} else {
+		if err := a.memory.Clear(ctx); err != nil {
+			return fmt.Errorf("failed to clear memory: %w", err)
+		}
+		for _, message := range checkpoint.Messages {
+			if err := a.memory.AddMessage(ctx, message); err != nil {
+				return fmt.Errorf("failed to restore message: %w", err)
+			}
+		}
+
+		if len(checkpoint.State) > 0 {
+			store, ok := a.memory.(interfaces.ConversationMetadataStore)
+			if !ok {
+				return fmt.Errorf("cannot restore conversation state: memory does not support conversation metadata")
+			}
+			for key, value := range checkpoint.State {
+				if err := store.SetConversationMetadata(ctx, key, value); err != nil {
+					return fmt.Errorf("failed to restore conversation metadata %s: %w", key, err)
+				}
+			}
+		}
+	}
+
+	if a.planStore == nil {
+		a.planStore = executionplan.NewStore()
+	}
+	for _, plan := range checkpoint.Plans {
+		if err := a.storePlan(ctx, plan); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isPendingPlan reports whether a plan still awaits approval or execution
+func isPendingPlan(plan *executionplan.ExecutionPlan) bool {
+	switch plan.Status {
+	case executionplan.StatusDraft, executionplan.StatusPendingApproval, executionplan.StatusApproved:
+		return true
+	default:
+		return false
+	}
+}
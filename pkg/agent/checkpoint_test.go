@@ -0,0 +1,174 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestCheckpointRestore(t *testing.T) {
+	var prompts []string
+	newLLM := func() *mockLLM {
+		return &mockLLM{
+			generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+				prompts = append(prompts, prompt)
+				return fmt.Sprintf("answer %d", len(prompts)), nil
+			},
+		}
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org")
+	ctx = memory.WithConversationID(ctx, "conv")
+
+	original, err := NewAgent(WithLLM(newLLM()), WithMemory(memory.NewConversationBuffer()))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	for _, input := range []string{"My name is Ada", "I live in London"} {
+		if _, err := original.Run(ctx, input); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+	}
+	store := original.GetMemory().(interfaces.ConversationMetadataStore)
+	if err := store.SetConversationMetadata(ctx, memory.TitleMetadataKey, "About Ada"); err != nil {
+		t.Fatalf("failed to set title: %v", err)
+	}
+
+	pending := executionplan.NewExecutionPlan("pending plan", []executionplan.ExecutionStep{{ToolName: "search", Input: "Ada"}})
+	pending.Status = executionplan.StatusPendingApproval
+	completed := executionplan.NewExecutionPlan("completed plan", nil)
+	completed.Status = executionplan.StatusCompleted
+	for _, plan := range []*executionplan.ExecutionPlan{pending, completed} {
+		if err := original.storePlan(ctx, plan); err != nil {
+			t.Fatalf("failed to store plan: %v", err)
+		}
+	}
+
+	data, err := original.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("failed to checkpoint: %v", err)
+	}
+
+	restoredMemory := memory.NewConversationBuffer()
+	restored, err := NewAgent(WithLLM(newLLM()), WithMemory(restoredMemory))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	if err := restored.Restore(ctx, data); err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+
+	response, err := restored.Run(ctx, "Where do I live?")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if response != "answer 3" {
+		t.Errorf("unexpected response: %q", response)
+	}
+
+	lastPrompt := prompts[len(prompts)-1]
+	for _, expected := range []string{"My name is Ada", "answer 1", "I live in London", "answer 2", "Where do I live?"} {
+		if !strings.Contains(lastPrompt, expected) {
+			t.Errorf("expected restored prompt to contain %q, got %q", expected, lastPrompt)
+		}
+	}
+
+	messages, err := restoredMemory.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	if len(messages) != 6 {
+		t.Errorf("expected 6 messages after restore and run, got %d", len(messages))
+	}
+
+	if title, ok := memory.GetTitle(ctx, restoredMemory); !ok || title != "About Ada" {
+		t.Errorf("expected restored title, got %q", title)
+	}
+
+	plan, ok := restored.GetTaskByID(pending.TaskID)
+	if !ok {
+		t.Fatal("expected pending plan to be restored")
+	}
+	if plan.Status != executionplan.StatusPendingApproval || len(plan.Steps) != 1 || plan.Steps[0].ToolName != "search" {
+		t.Errorf("unexpected restored plan: %+v", plan)
+	}
+	if _, ok := restored.GetTaskByID(completed.TaskID); ok {
+		t.Error("completed plans should not be checkpointed")
+	}
+}
+
+func TestCheckpointScopedToOrganization(t *testing.T) {
+	agent, err := NewAgent(WithLLM(&mockLLM{}), WithMemory(memory.NewConversationBuffer()))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctxA := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org-a"), "conv")
+	ctxB := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org-b"), "conv")
+
+	planA := executionplan.NewExecutionPlan("plan of org A", nil)
+	planA.Status = executionplan.StatusPendingApproval
+	planB := executionplan.NewExecutionPlan("plan of org B", nil)
+	planB.Status = executionplan.StatusPendingApproval
+	if err := agent.storePlan(ctxA, planA); err != nil {
+		t.Fatalf("failed to store plan: %v", err)
+	}
+	if err := agent.storePlan(ctxB, planB); err != nil {
+		t.Fatalf("failed to store plan: %v", err)
+	}
+
+	data, err := agent.Checkpoint(ctxA)
+	if err != nil {
+		t.Fatalf("failed to checkpoint: %v", err)
+	}
+	var checkpoint CheckpointData
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		t.Fatalf("failed to unmarshal checkpoint: %v", err)
+	}
+	if len(checkpoint.Plans) != 1 || checkpoint.Plans[0].TaskID != planA.TaskID {
+		t.Fatalf("expected only the plan of org A, got %+v", checkpoint.Plans)
+	}
+
+	// A checkpoint of org A can't be restored into org B
+	restored, err := NewAgent(WithLLM(&mockLLM{}), WithMemory(memory.NewConversationBuffer()))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	if err := restored.Restore(ctxB, data); err == nil {
+		t.Error("expected restoring plans of another organization to fail")
+	}
+	if _, ok := restored.GetTaskByID(planA.TaskID); ok {
+		t.Error("expected the plan of org A not to be restored into org B")
+	}
+	if err := restored.Restore(ctxA, data); err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+	if _, ok := restored.GetTaskByID(planA.TaskID); !ok {
+		t.Error("expected the plan of org A to be restored")
+	}
+}
+
+func TestRestoreInvalidCheckpoint(t *testing.T) {
+	agent, err := NewAgent(WithLLM(&mockLLM{}), WithMemory(memory.NewConversationBuffer()))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org")
+	ctx = memory.WithConversationID(ctx, "conv")
+
+	if err := agent.Restore(ctx, []byte("not json")); err == nil {
+		t.Error("expected error for malformed checkpoint")
+	}
+	if err := agent.Restore(ctx, []byte(`{"version": 99}`)); err == nil {
+		t.Error("expected error for unsupported checkpoint version")
+	}
+}
@@ -24,6 +24,12 @@ type AgentConfig struct {
 	Goal           string                `yaml:"goal"`
 	Backstory      string                `yaml:"backstory"`
 	ResponseFormat *ResponseFormatConfig `yaml:"response_format,omitempty"`
+	// Tools lists the names of the tools the agent expects, which are passed with WithTools
+	Tools []string `yaml:"tools,omitempty"`
+	// AllowDelegation records whether the agent may hand work off to other agents, see WithAgents
+	AllowDelegation bool `yaml:"allow_delegation,omitempty"`
+	// MaxIterations is the maximum number of tool-calling iterations, see WithMaxIterations
+	MaxIterations int `yaml:"max_iterations,omitempty"`
 }
 
 // TaskConfig represents a task definition loaded from YAML
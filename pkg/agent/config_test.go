@@ -166,3 +166,71 @@ func TestConvertYAMLSchemaToResponseFormat(t *testing.T) {
 		t.Fatal("Expected nil ResponseFormat for nil config")
 	}
 }
+
+func TestValidateConfigs(t *testing.T) {
+	agentConfigs := AgentConfigs{
+		"researcher": {Role: "{topic} researcher", Goal: "Research", Backstory: "Seasoned"},
+		"writer": {
+			Role:           "Writer",
+			ResponseFormat: &ResponseFormatConfig{Type: "json_object"},
+		},
+	}
+	taskConfigs := TaskConfigs{
+		"research": {Description: "Research {topic} in {year}", Agent: "researcher"},
+		"write":    {Description: "Write about {\"json\": true}", Agent: "editor"},
+		"review":   {Description: "Review", ResponseFormat: &ResponseFormatConfig{Type: "json_object", SchemaName: "Review"}},
+	}
+
+	errs := ValidateConfigs(agentConfigs, taskConfigs)
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	assert.Equal(t, []string{
+		"agent writer: response format has no schema_name",
+		"task research: variable {topic} in agent role is not provided",
+		"task research: variable {topic} in description is not provided",
+		"task research: variable {year} in description is not provided",
+		"task review: no agent is specified",
+		"task write: agent editor is not defined",
+	}, messages)
+
+	// Placeholders are resolved by the given variables
+	researchAgents := AgentConfigs{"researcher": agentConfigs["researcher"]}
+	research := TaskConfigs{"research": taskConfigs["research"]}
+	errs = ValidateConfigs(researchAgents, research, map[string]string{"topic": "AI"})
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "task research: variable {year} in description is not provided")
+
+	errs = ValidateConfigs(researchAgents, research, map[string]string{"year": "2025"})
+	assert.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "task research: variable {topic} in agent role is not provided")
+
+	assert.Empty(t, ValidateConfigs(researchAgents, research, map[string]string{"topic": "AI", "year": "2025"}))
+}
+
+func TestCreateAgentForTaskValidatesConfig(t *testing.T) {
+	agentConfigs := AgentConfigs{
+		"researcher": {Role: "Researcher", Goal: "Research {topic}", Backstory: "Seasoned"},
+	}
+	taskConfigs := TaskConfigs{
+		"research": {Description: "Research {topic}", Agent: "researcher"},
+		"broken":   {Description: "Broken", Agent: "missing"},
+	}
+
+	_, err := CreateAgentForTask("research", agentConfigs, taskConfigs, map[string]string{}, WithLLM(&mockLLM{}))
+	assert.ErrorContains(t, err, "variable {topic} in description is not provided")
+
+	// Other tasks don't prevent creating the agent of a valid task
+	agent, err := CreateAgentForTask("research", agentConfigs, taskConfigs, map[string]string{"topic": "AI"}, WithLLM(&mockLLM{}))
+	assert.NoError(t, err)
+	assert.NotNil(t, agent)
+
+	// Validation errors are reported before the agent is built
+	_, err = CreateAgentForTask("broken", agentConfigs, taskConfigs, map[string]string{})
+	assert.ErrorContains(t, err, "agent missing is not defined")
+
+	// Validation can be skipped
+	_, err = CreateAgentForTaskWithoutValidation("research", agentConfigs, taskConfigs, map[string]string{}, WithLLM(&mockLLM{}))
+	assert.NoError(t, err)
+}
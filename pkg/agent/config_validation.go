@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// configVariablePattern matches the {variable} placeholders of agent and task configurations
+var configVariablePattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ValidateConfigs checks that agent and task configurations are consistent: every task
+// references an existing agent, every response format declares a schema name and every
+// {variable} of the tasks and of the agents they reference is provided by the variables. Without
+// variables, every placeholder is reported. It returns all problems found, or nil if the
+// configurations are valid.
+func ValidateConfigs(agentConfigs AgentConfigs, taskConfigs TaskConfigs, variables ...map[string]string) []error {
+	var errs []error
+
+	for _, agentName := range sortedKeys(agentConfigs) {
+		if err := validateResponseFormat(agentConfigs[agentName].ResponseFormat); err != nil {
+			errs = append(errs, fmt.Errorf("agent %s: %w", agentName, err))
+		}
+	}
+
+	for _, taskName := range sortedKeys(taskConfigs) {
+		taskConfig := taskConfigs[taskName]
+		if err := validateResponseFormat(taskConfig.ResponseFormat); err != nil {
+			errs = append(errs, fmt.Errorf("task %s: %w", taskName, err))
+		}
+
+		agentConfig, agentExists := agentConfigs[taskConfig.Agent]
+		switch {
+		case taskConfig.Agent == "":
+			errs = append(errs, fmt.Errorf("task %s: no agent is specified", taskName))
+		case !agentExists:
+			errs = append(errs, fmt.Errorf("task %s: agent %s is not defined", taskName, taskConfig.Agent))
+		}
+
+		fields := map[string]string{
+			"description":     taskConfig.Description,
+			"expected_output": taskConfig.ExpectedOutput,
+			"output_file":     taskConfig.OutputFile,
+		}
+		if agentExists {
+			fields["agent role"] = agentConfig.Role
+			fields["agent goal"] = agentConfig.Goal
+			fields["agent backstory"] = agentConfig.Backstory
+		}
+		for _, field := range sortedKeys(fields) {
+			for _, variable := range missingVariables(fields[field], variables) {
+				errs = append(errs, fmt.Errorf("task %s: variable {%s} in %s is not provided", taskName, variable, field))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateTaskConfig validates the configuration of a task and its agent for CreateAgentForTask
+func validateTaskConfig(taskName string, agentConfigs AgentConfigs, taskConfigs TaskConfigs, variables map[string]string) error {
+	taskConfig := taskConfigs[taskName]
+	agents := AgentConfigs{}
+	if agentConfig, exists := agentConfigs[taskConfig.Agent]; exists {
+		agents[taskConfig.Agent] = agentConfig
+	}
+
+	if errs := ValidateConfigs(agents, TaskConfigs{taskName: taskConfig}, variables); len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// validateResponseFormat checks that a declared response format has a schema name
+func validateResponseFormat(config *ResponseFormatConfig) error {
+	if config != nil && config.SchemaName == "" {
+		return fmt.Errorf("response format has no schema_name")
+	}
+	return nil
+}
+
+// missingVariables returns the {variable} placeholders of text not provided by any of variables
+func missingVariables(text string, variables []map[string]string) []string {
+	var missing []string
+	seen := map[string]bool{}
+	for _, match := range configVariablePattern.FindAllStringSubmatch(text, -1) {
+		name := match[1]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		provided := false
+		for _, values := range variables {
+			if _, ok := values[name]; ok {
+				provided = true
+				break
+			}
+		}
+		if !provided {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
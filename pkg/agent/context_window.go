@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// defaultOutputReserve is the number of tokens reserved for the response when trimming the
+// history without an output limit in the LLM config
+const defaultOutputReserve = 4096
+
+// fitToContextWindow drops the oldest messages of the history until the request fits the
+// context window of the model, see WithContextWindowTrimming
+func (a *Agent) fitToContextWindow(ctx context.Context, history []interfaces.Message, tools []interfaces.Tool) []interfaces.Message {
+	modelLLM, ok := a.llm.(interface{ GetModel() string })
+	if !ok {
+		return history
+	}
+
+	reserve := defaultOutputReserve
+	if a.llmConfig != nil && max(a.llmConfig.MaxTokens, a.llmConfig.MaxCompletionTokens) > 0 {
+		reserve = max(a.llmConfig.MaxTokens, a.llmConfig.MaxCompletionTokens)
+	}
+	reserve += llm.EstimateTokens(a.systemMessage(ctx, tools))
+
+	fitted := memory.FitToWindow(history, modelLLM.GetModel(), reserve)
+	if len(fitted) < len(history) {
+		a.logger.Debug(ctx, "Trimmed conversation history to the context window", map[string]interface{}{
+			"model":            modelLLM.GetModel(),
+			"dropped_messages": len(history) - len(fitted),
+		})
+	}
+	return fitted
+}
+
+// windowedMemory is the memory handed to the LLM when trimming to the context window. The
+// history the LLM reads from it is trimmed, while messages are still stored in the memory.
+type windowedMemory struct {
+	interfaces.Memory
+	fit func(history []interfaces.Message) []interfaces.Message
+}
+
+// GetMessages returns the messages of the memory, trimmed to the context window
+func (m *windowedMemory) GetMessages(ctx context.Context, options ...interfaces.GetMessagesOption) ([]interfaces.Message, error) {
+	history, err := m.Memory.GetMessages(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	return m.fit(history), nil
+}
+
+// llmMemory returns the memory handed to the LLM, which providers read the history from when
+// streaming, trimming that history to the context window if configured
+func (a *Agent) llmMemory(ctx context.Context, tools []interfaces.Tool) interfaces.Memory {
+	if !a.trimToContextWindow {
+		return a.memory
+	}
+	return &windowedMemory{
+		Memory: a.memory,
+		fit: func(history []interfaces.Message) []interfaces.Message {
+			return a.fitToContextWindow(ctx, history, tools)
+		},
+	}
+}
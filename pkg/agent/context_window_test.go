@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestWithContextWindowTrimming(t *testing.T) {
+	llm.RegisterModelCapabilities("agent-window-test-model", llm.ModelCapabilities{MaxInputTokens: 1500})
+
+	var prompts []string
+	model := &modelLLM{
+		mockLLM: mockLLM{generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			prompts = append(prompts, prompt)
+			return "ok", nil
+		}},
+		model: "agent-window-test-model",
+	}
+
+	for _, trimming := range []bool{false, true} {
+		mem := memory.NewConversationBuffer()
+		ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "acme"), "conversation")
+		for _, content := range []string{"oldest question", "oldest answer"} {
+			_ = mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: content + strings.Repeat(" x", 1000)})
+		}
+
+		agent, err := NewAgent(
+			WithLLM(model),
+			WithMemory(mem),
+			WithLLMConfig(interfaces.LLMConfig{MaxTokens: 500}),
+			WithContextWindowTrimming(trimming),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+		if _, err := agent.Run(ctx, "latest question"); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+
+	if !strings.Contains(prompts[0], "oldest question") {
+		t.Error("Expected the history to be kept without trimming")
+	}
+	if strings.Contains(prompts[1], "oldest question") || !strings.Contains(prompts[1], "oldest answer") {
+		t.Error("Expected the oldest message to be dropped with trimming")
+	}
+	if !strings.Contains(prompts[1], "latest question") {
+		t.Error("Expected the input to be kept with trimming")
+	}
+}
+
+// historyStreamingLLM streams the contents of the history it reads from the memory option,
+// as streaming providers build their requests from it
+type historyStreamingLLM struct {
+	modelLLM
+}
+
+func (m *historyStreamingLLM) SupportsStreaming() bool { return true }
+
+func (m *historyStreamingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	params := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(params)
+	}
+
+	var contents []string
+	if params.Memory != nil {
+		history, err := params.Memory.GetMessages(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range history {
+			contents = append(contents, message.Content)
+		}
+	}
+
+	events := make(chan interfaces.StreamEvent, 1)
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: strings.Join(contents, "\n")}
+	close(events)
+	return events, nil
+}
+
+func (m *historyStreamingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return m.GenerateStream(ctx, prompt, options...)
+}
+
+func TestWithContextWindowTrimmingStream(t *testing.T) {
+	llm.RegisterModelCapabilities("agent-window-stream-test-model", llm.ModelCapabilities{MaxInputTokens: 1500})
+
+	mem := memory.NewConversationBuffer()
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "acme"), "conversation")
+	for _, content := range []string{"oldest question", "oldest answer"} {
+		_ = mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: content + strings.Repeat(" x", 1000)})
+	}
+
+	agent, err := NewAgent(
+		WithLLM(&historyStreamingLLM{modelLLM: modelLLM{model: "agent-window-stream-test-model"}}),
+		WithMemory(mem),
+		WithLLMConfig(interfaces.LLMConfig{MaxTokens: 500}),
+		WithContextWindowTrimming(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	events, err := agent.RunStream(ctx, "latest question")
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	var history strings.Builder
+	for event := range events {
+		if event.Type == interfaces.AgentEventContent {
+			history.WriteString(event.Content)
+		}
+	}
+
+	if strings.Contains(history.String(), "oldest question") || !strings.Contains(history.String(), "oldest answer") {
+		t.Error("Expected the oldest message to be dropped from the history read by the LLM")
+	}
+
+	// The memory itself keeps every message
+	stored, _ := mem.GetMessages(ctx)
+	if len(stored) < 3 || !strings.HasPrefix(stored[0].Content, "oldest question") {
+		t.Errorf("Expected the memory to keep the trimmed messages, got %d messages", len(stored))
+	}
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// EstimateRunCost estimates the worst-case cost in USD of the first LLM request Run would send
+// for the input, without sending it. The prompt tokens count the system message, the tool
+// definitions, the conversation history and the input; the response is assumed to use the
+// maximum number of output tokens, from WithLLMConfig or else the limit of the model.
+// Requests following tool calls are not included. The LLM must report its model with a
+// GetModel method and the model must have a price, see llm.EstimateCost.
+func (a *Agent) EstimateRunCost(ctx context.Context, input string) (float64, error) {
+	if a.isRemote {
+		return 0, fmt.Errorf("cost estimation is not supported for remote agents")
+	}
+
+	modelLLM, ok := a.llm.(interface{ GetModel() string })
+	if !ok {
+		return 0, fmt.Errorf("LLM %s does not report its model", a.llm.Name())
+	}
+	model := modelLLM.GetModel()
+
+	price, ok := llm.LookupModelPrice(model)
+	if !ok {
+		return 0, fmt.Errorf("no price known for model %q", model)
+	}
+
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	prompt := input
+	if a.memory != nil {
+		history, err := a.memory.GetMessages(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get conversation history: %w", err)
+		}
+		prompt = formatHistoryIntoPrompt(append(history, interfaces.Message{Role: "user", Content: input}))
+	}
+
+	tools, err := a.allowedTools(ctx, a.allTools(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(a.systemMessage(ctx, tools))
+	sb.WriteString(prompt)
+	for _, tool := range tools {
+		parameters, err := json.Marshal(tool.Parameters())
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal parameters of tool %s: %w", tool.Name(), err)
+		}
+		sb.WriteString(tool.Name())
+		sb.WriteString(tool.Description())
+		sb.Write(parameters)
+	}
+
+	maxOutputTokens := price.MaxOutputTokens
+	if a.llmConfig != nil && a.llmConfig.MaxTokens > 0 {
+		maxOutputTokens = a.llmConfig.MaxTokens
+	}
+
+	return llm.EstimateCost(model, llm.EstimateTokens(sb.String()), maxOutputTokens)
+}
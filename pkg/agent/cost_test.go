@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// modelLLM is a mock LLM reporting its model
+type modelLLM struct {
+	mockLLM
+	model string
+}
+
+func (m *modelLLM) GetModel() string { return m.model }
+
+func TestEstimateRunCost(t *testing.T) {
+	systemPrompt := strings.Repeat("a", 400)
+	input := strings.Repeat("b", 400)
+
+	agent, err := NewAgent(
+		WithLLM(&modelLLM{model: "gpt-4o"}),
+		WithSystemPrompt(systemPrompt),
+		WithLLMConfig(interfaces.LLMConfig{MaxTokens: 1000}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	cost, err := agent.EstimateRunCost(context.Background(), input)
+	if err != nil {
+		t.Fatalf("EstimateRunCost failed: %v", err)
+	}
+
+	// 200 prompt tokens at $2.50 and 1000 output tokens at $10 per million
+	expected := (200*2.50 + 1000*10) / 1e6
+	if math.Abs(cost-expected) > 1e-9 {
+		t.Errorf("Expected cost %v, got %v", expected, cost)
+	}
+
+	// Without a configured limit, the response is assumed to use the limit of the model
+	agent, err = NewAgent(WithLLM(&modelLLM{model: "gpt-4o"}), WithSystemPrompt(systemPrompt))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	cost, err = agent.EstimateRunCost(context.Background(), input)
+	if err != nil {
+		t.Fatalf("EstimateRunCost failed: %v", err)
+	}
+	expected = (200*2.50 + 16384*10) / 1e6
+	if math.Abs(cost-expected) > 1e-9 {
+		t.Errorf("Expected worst-case cost %v, got %v", expected, cost)
+	}
+}
+
+func TestEstimateRunCostUnknownModel(t *testing.T) {
+	agent, err := NewAgent(WithLLM(&modelLLM{model: "my-model"}))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := agent.EstimateRunCost(context.Background(), "hello"); err == nil {
+		t.Error("Expected an error for an unknown model")
+	}
+
+	agent, err = NewAgent(WithLLM(&mockLLM{}))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := agent.EstimateRunCost(context.Background(), "hello"); err == nil {
+		t.Error("Expected an error for an LLM not reporting its model")
+	}
+}
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadCrewAIConfigs imports the agents.yaml and tasks.yaml files of a CrewAI project. The
+// role, goal, backstory, tools, allow_delegation and max_iter of agents and the description,
+// expected_output, agent and output_file of tasks are mapped onto AgentConfig and TaskConfig.
+// Other CrewAI fields have no equivalent and are skipped; the returned warnings list them, so
+// they can be reviewed when migrating. The {placeholders} of CrewAI are kept as is, since
+// FormatSystemPromptFromConfig uses the same syntax.
+func LoadCrewAIConfigs(agentsPath, tasksPath string) (AgentConfigs, TaskConfigs, []string, error) {
+	var warnings []string
+
+	agentEntries, err := readCrewAIFile(agentsPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load CrewAI agents: %w", err)
+	}
+
+	agentConfigs := make(AgentConfigs, len(agentEntries))
+	for _, name := range sortedKeys(agentEntries) {
+		config, agentWarnings, err := crewAIAgentConfig(name, agentEntries[name])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		agentConfigs[name] = config
+		warnings = append(warnings, agentWarnings...)
+	}
+
+	taskEntries, err := readCrewAIFile(tasksPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load CrewAI tasks: %w", err)
+	}
+
+	taskConfigs := make(TaskConfigs, len(taskEntries))
+	for _, name := range sortedKeys(taskEntries) {
+		config, taskWarnings, err := crewAITaskConfig(name, taskEntries[name])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if _, exists := agentConfigs[config.Agent]; config.Agent != "" && !exists {
+			taskWarnings = append(taskWarnings, fmt.Sprintf("task %s: agent %s is not defined", name, config.Agent))
+		}
+		taskConfigs[name] = config
+		warnings = append(warnings, taskWarnings...)
+	}
+
+	return agentConfigs, taskConfigs, warnings, nil
+}
+
+// readCrewAIFile reads a CrewAI YAML file of named entries
+func readCrewAIFile(filePath string) (map[string]map[string]interface{}, error) {
+	if !isValidFilePath(filePath) {
+		return nil, fmt.Errorf("invalid file path")
+	}
+
+	data, err := os.ReadFile(filePath) // #nosec G304 - Path is validated with isValidFilePath() before use
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var entries map[string]map[string]interface{}
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", filePath, err)
+	}
+	return entries, nil
+}
+
+// crewAIAgentConfig maps a CrewAI agent onto an AgentConfig
+func crewAIAgentConfig(name string, fields map[string]interface{}) (AgentConfig, []string, error) {
+	var config AgentConfig
+	var warnings []string
+	var err error
+
+	for _, field := range sortedKeys(fields) {
+		value := fields[field]
+		switch field {
+		case "role":
+			config.Role, err = crewAIString(value)
+		case "goal":
+			config.Goal, err = crewAIString(value)
+		case "backstory":
+			config.Backstory, err = crewAIString(value)
+		case "tools":
+			config.Tools, err = crewAIStrings(value)
+		case "allow_delegation":
+			config.AllowDelegation, err = crewAIBool(value)
+		case "max_iter":
+			config.MaxIterations, err = crewAIInt(value)
+		default:
+			warnings = append(warnings, fmt.Sprintf("agent %s: field %s is not supported and was ignored", name, field))
+		}
+		if err != nil {
+			return AgentConfig{}, nil, fmt.Errorf("agent %s: invalid %s: %w", name, field, err)
+		}
+	}
+
+	return config, warnings, nil
+}
+
+// crewAITaskConfig maps a CrewAI task onto a TaskConfig
+func crewAITaskConfig(name string, fields map[string]interface{}) (TaskConfig, []string, error) {
+	var config TaskConfig
+	var warnings []string
+	var err error
+
+	for _, field := range sortedKeys(fields) {
+		value := fields[field]
+		switch field {
+		case "description":
+			config.Description, err = crewAIString(value)
+		case "expected_output":
+			config.ExpectedOutput, err = crewAIString(value)
+		case "agent":
+			config.Agent, err = crewAIString(value)
+		case "output_file":
+			config.OutputFile, err = crewAIString(value)
+		default:
+			warnings = append(warnings, fmt.Sprintf("task %s: field %s is not supported and was ignored", name, field))
+		}
+		if err != nil {
+			return TaskConfig{}, nil, fmt.Errorf("task %s: invalid %s: %w", name, field, err)
+		}
+	}
+
+	return config, warnings, nil
+}
+
+func crewAIString(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a string, got %T", value)
+	}
+	return s, nil
+}
+
+func crewAIStrings(value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list, got %T", value)
+	}
+	strs := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a list of strings, got an item of type %T", item)
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+func crewAIBool(value interface{}) (bool, error) {
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, got %T", value)
+	}
+	return b, nil
+}
+
+func crewAIInt(value interface{}) (int, error) {
+	i, ok := value.(int)
+	if !ok {
+		return 0, fmt.Errorf("expected an integer, got %T", value)
+	}
+	return i, nil
+}
+
+// sortedKeys returns the keys of a map in order, for deterministic warnings
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
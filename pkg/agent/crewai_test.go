@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const crewAIAgentsYAML = `researcher:
+  role: >
+    {topic} Senior Data Researcher
+  goal: >
+    Uncover cutting-edge developments in {topic}
+  backstory: >
+    You're a seasoned researcher.
+  tools:
+    - search
+    - scrape_website
+  allow_delegation: false
+  max_iter: 5
+  verbose: true
+  llm: openai/gpt-4o
+
+reporting_analyst:
+  role: Reporting Analyst
+  goal: Create detailed reports
+  backstory: You're a meticulous analyst.
+  allow_delegation: true
+`
+
+const crewAITasksYAML = `research_task:
+  description: >
+    Conduct a thorough research about {topic}
+  expected_output: >
+    A list with 10 bullet points
+  agent: researcher
+
+reporting_task:
+  description: Review the context you got
+  expected_output: A fully fledged report
+  agent: reporting_analyst
+  output_file: report.md
+  context:
+    - research_task
+  async_execution: false
+`
+
+func writeCrewAIConfigs(t *testing.T, agents, tasks string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	agentsPath := filepath.Join(dir, "agents.yaml")
+	tasksPath := filepath.Join(dir, "tasks.yaml")
+	require.NoError(t, os.WriteFile(agentsPath, []byte(agents), 0600))
+	require.NoError(t, os.WriteFile(tasksPath, []byte(tasks), 0600))
+	return agentsPath, tasksPath
+}
+
+func TestLoadCrewAIConfigs(t *testing.T) {
+	agentsPath, tasksPath := writeCrewAIConfigs(t, crewAIAgentsYAML, crewAITasksYAML)
+
+	agentConfigs, taskConfigs, warnings, err := LoadCrewAIConfigs(agentsPath, tasksPath)
+	require.NoError(t, err)
+
+	researcher := agentConfigs["researcher"]
+	assert.Equal(t, "{topic} Senior Data Researcher\n", researcher.Role)
+	assert.Equal(t, "Uncover cutting-edge developments in {topic}\n", researcher.Goal)
+	assert.Equal(t, "You're a seasoned researcher.\n", researcher.Backstory)
+	assert.Equal(t, []string{"search", "scrape_website"}, researcher.Tools)
+	assert.False(t, researcher.AllowDelegation)
+	assert.Equal(t, 5, researcher.MaxIterations)
+
+	analyst := agentConfigs["reporting_analyst"]
+	assert.Equal(t, "Reporting Analyst", analyst.Role)
+	assert.True(t, analyst.AllowDelegation)
+	assert.Empty(t, analyst.Tools)
+	assert.Zero(t, analyst.MaxIterations)
+
+	assert.Equal(t, TaskConfig{
+		Description:    "Conduct a thorough research about {topic}\n",
+		ExpectedOutput: "A list with 10 bullet points\n",
+		Agent:          "researcher",
+	}, taskConfigs["research_task"])
+	assert.Equal(t, TaskConfig{
+		Description:    "Review the context you got",
+		ExpectedOutput: "A fully fledged report",
+		Agent:          "reporting_analyst",
+		OutputFile:     "report.md",
+	}, taskConfigs["reporting_task"])
+
+	assert.Equal(t, []string{
+		"agent researcher: field llm is not supported and was ignored",
+		"agent researcher: field verbose is not supported and was ignored",
+		"task reporting_task: field async_execution is not supported and was ignored",
+		"task reporting_task: field context is not supported and was ignored",
+	}, warnings)
+}
+
+func TestLoadCrewAIConfigsInvalidField(t *testing.T) {
+	agentsPath, tasksPath := writeCrewAIConfigs(t, "researcher:\n  role: Researcher\n  max_iter: many\n", crewAITasksYAML)
+
+	_, _, _, err := LoadCrewAIConfigs(agentsPath, tasksPath)
+	assert.ErrorContains(t, err, "agent researcher: invalid max_iter")
+}
+
+func TestLoadCrewAIConfigsUndefinedAgent(t *testing.T) {
+	agentsPath, tasksPath := writeCrewAIConfigs(t, crewAIAgentsYAML, "writing_task:\n  description: Write\n  agent: writer\n")
+
+	_, _, warnings, err := LoadCrewAIConfigs(agentsPath, tasksPath)
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "task writing_task: agent writer is not defined")
+}
+
+func TestNewAgentFromCrewAIConfig(t *testing.T) {
+	agentsPath, tasksPath := writeCrewAIConfigs(t, crewAIAgentsYAML, crewAITasksYAML)
+	agentConfigs, _, _, err := LoadCrewAIConfigs(agentsPath, tasksPath)
+	require.NoError(t, err)
+
+	agent, err := NewAgentFromConfig("researcher", agentConfigs, map[string]string{"topic": "AI"},
+		WithLLM(&mockLLM{}),
+		WithTools(&mockTool{name: "search"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 5, agent.maxIterations)
+	assert.Contains(t, agent.GetSystemPrompt(), "AI Senior Data Researcher")
+	assert.True(t, agent.hasTool("search"))
+	assert.False(t, agent.hasTool("scrape_website"))
+}
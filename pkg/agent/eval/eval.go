@@ -0,0 +1,235 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Runnable is anything that can be evaluated, such as *agent.Agent
+type Runnable interface {
+	Run(ctx context.Context, input string) (string, error)
+}
+
+// TestCase represents a single evaluation input with its expected output
+type TestCase struct {
+	// ID identifies the test case in the report
+	ID string `json:"id"`
+	// Input is the input passed to the agent
+	Input string `json:"input"`
+	// Expected is the expected output used by the scorer
+	Expected string `json:"expected"`
+	// Metadata contains additional information about the test case
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Score is the result of scoring a single output
+type Score struct {
+	// Value is a normalized score between 0 and 1
+	Value float64 `json:"value"`
+	// Passed indicates whether the output is considered correct
+	Passed bool `json:"passed"`
+	// Reason optionally explains the score
+	Reason string `json:"reason,omitempty"`
+}
+
+// Scorer scores an agent output against a test case
+type Scorer interface {
+	Score(ctx context.Context, testCase TestCase, output string) (Score, error)
+}
+
+// ScorerFunc adapts a function to the Scorer interface
+type ScorerFunc func(ctx context.Context, testCase TestCase, output string) (Score, error)
+
+// Score calls f(ctx, testCase, output)
+func (f ScorerFunc) Score(ctx context.Context, testCase TestCase, output string) (Score, error) {
+	return f(ctx, testCase, output)
+}
+
+// Result is the outcome of running a single test case
+type Result struct {
+	TestCase TestCase      `json:"test_case"`
+	Output   string        `json:"output"`
+	Score    Score         `json:"score"`
+	Latency  time.Duration `json:"latency"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Report aggregates the results of an evaluation run
+type Report struct {
+	Results        []Result      `json:"results"`
+	Total          int           `json:"total"`
+	Passed         int           `json:"passed"`
+	Failed         int           `json:"failed"`
+	Errored        int           `json:"errored"`
+	PassRate       float64       `json:"pass_rate"`
+	AverageScore   float64       `json:"average_score"`
+	TotalLatency   time.Duration `json:"total_latency"`
+	AverageLatency time.Duration `json:"average_latency"`
+	P50Latency     time.Duration `json:"p50_latency"`
+	P95Latency     time.Duration `json:"p95_latency"`
+	MaxLatency     time.Duration `json:"max_latency"`
+}
+
+// Runner executes an agent over a set of test cases and scores the outputs
+type Runner struct {
+	agent       Runnable
+	scorer      Scorer
+	concurrency int
+	timeout     time.Duration
+}
+
+// Option represents an option for configuring a Runner
+type Option func(*Runner)
+
+// WithConcurrency sets the number of test cases executed in parallel
+func WithConcurrency(concurrency int) Option {
+	return func(r *Runner) {
+		r.concurrency = concurrency
+	}
+}
+
+// WithTimeout sets a per-test-case timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *Runner) {
+		r.timeout = timeout
+	}
+}
+
+// NewRunner creates a new evaluation runner
+func NewRunner(agent Runnable, scorer Scorer, options ...Option) *Runner {
+	runner := &Runner{
+		agent:       agent,
+		scorer:      scorer,
+		concurrency: 1,
+	}
+
+	for _, option := range options {
+		option(runner)
+	}
+
+	if runner.concurrency < 1 {
+		runner.concurrency = 1
+	}
+
+	return runner
+}
+
+// Run executes every test case and returns the aggregated report.
+// Agent and scorer errors are recorded per test case and do not abort the run.
+func (r *Runner) Run(ctx context.Context, testCases []TestCase) (*Report, error) {
+	if r.agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	if r.scorer == nil {
+		return nil, fmt.Errorf("scorer is required")
+	}
+
+	results := make([]Result, len(testCases))
+	sem := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+
+	for i, testCase := range testCases {
+		if err := ctx.Err(); err != nil {
+			wg.Wait()
+			return nil, err
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, testCase TestCase) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = r.runCase(ctx, testCase)
+		}(i, testCase)
+	}
+
+	wg.Wait()
+
+	return Aggregate(results), nil
+}
+
+// runCase executes and scores a single test case
+func (r *Runner) runCase(ctx context.Context, testCase TestCase) Result {
+	result := Result{TestCase: testCase}
+
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	output, err := r.agent.Run(ctx, testCase.Input)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Output = output
+
+	score, err := r.scorer.Score(ctx, testCase, output)
+	if err != nil {
+		result.Error = fmt.Sprintf("scorer error: %v", err)
+		return result
+	}
+	result.Score = score
+
+	return result
+}
+
+// Aggregate computes the report for a set of results
+func Aggregate(results []Result) *Report {
+	report := &Report{
+		Results: results,
+		Total:   len(results),
+	}
+	if len(results) == 0 {
+		return report
+	}
+
+	latencies := make([]time.Duration, 0, len(results))
+	var totalScore float64
+	for _, result := range results {
+		latencies = append(latencies, result.Latency)
+		report.TotalLatency += result.Latency
+
+		switch {
+		case result.Error != "":
+			report.Errored++
+		case result.Score.Passed:
+			report.Passed++
+		default:
+			report.Failed++
+		}
+		totalScore += result.Score.Value
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	report.PassRate = float64(report.Passed) / float64(report.Total)
+	report.AverageScore = totalScore / float64(report.Total)
+	report.AverageLatency = report.TotalLatency / time.Duration(report.Total)
+	report.P50Latency = percentile(latencies, 0.50)
+	report.P95Latency = percentile(latencies, 0.95)
+	report.MaxLatency = latencies[len(latencies)-1]
+
+	return report
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,143 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// mockAgent echoes its input in upper case, failing on inputs prefixed with "fail"
+type mockAgent struct {
+	delay   time.Duration
+	running int32
+	peak    int32
+}
+
+func (m *mockAgent) Run(ctx context.Context, input string) (string, error) {
+	current := atomic.AddInt32(&m.running, 1)
+	defer atomic.AddInt32(&m.running, -1)
+	for {
+		peak := atomic.LoadInt32(&m.peak)
+		if current <= peak || atomic.CompareAndSwapInt32(&m.peak, peak, current) {
+			break
+		}
+	}
+
+	time.Sleep(m.delay)
+	if strings.HasPrefix(input, "fail") {
+		return "", fmt.Errorf("agent failed")
+	}
+	return strings.ToUpper(input), nil
+}
+
+type mockJudge struct {
+	response string
+}
+
+func (m *mockJudge) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return m.response, nil
+}
+
+func (m *mockJudge) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.response, nil
+}
+
+func (m *mockJudge) Name() string            { return "mock-judge" }
+func (m *mockJudge) SupportsStreaming() bool { return false }
+
+func TestRunnerAggregatesMetrics(t *testing.T) {
+	agent := &mockAgent{delay: 5 * time.Millisecond}
+	runner := NewRunner(agent, NewExactMatchScorer(), WithConcurrency(2))
+
+	report, err := runner.Run(context.Background(), []TestCase{
+		{ID: "1", Input: "hello", Expected: "HELLO"},
+		{ID: "2", Input: "world", Expected: "WORLD"},
+		{ID: "3", Input: "foo", Expected: "bar"},
+		{ID: "4", Input: "fail now", Expected: "FAIL NOW"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Total != 4 || report.Passed != 2 || report.Failed != 1 || report.Errored != 1 {
+		t.Errorf("unexpected counts: total=%d passed=%d failed=%d errored=%d",
+			report.Total, report.Passed, report.Failed, report.Errored)
+	}
+	if report.PassRate != 0.5 {
+		t.Errorf("expected pass rate 0.5, got %v", report.PassRate)
+	}
+	if report.AverageScore != 0.5 {
+		t.Errorf("expected average score 0.5, got %v", report.AverageScore)
+	}
+	if report.AverageLatency < 5*time.Millisecond || report.MaxLatency < report.P50Latency {
+		t.Errorf("unexpected latencies: avg=%v p50=%v max=%v", report.AverageLatency, report.P50Latency, report.MaxLatency)
+	}
+	if report.Results[2].TestCase.ID != "3" || report.Results[2].Output != "FOO" {
+		t.Errorf("results should preserve test case order, got %+v", report.Results[2])
+	}
+	if agent.peak > 2 {
+		t.Errorf("expected at most 2 concurrent runs, got %d", agent.peak)
+	}
+}
+
+func TestStructuredFieldScorer(t *testing.T) {
+	scorer := NewStructuredFieldScorer("name", "age")
+	scorer.Threshold = 0.5
+
+	score, err := scorer.Score(context.Background(),
+		TestCase{Expected: `{"name": "Ada", "age": 36, "city": "London"}`},
+		`{"name": "Ada", "age": 37, "city": "Paris"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if score.Value != 0.5 || !score.Passed {
+		t.Errorf("expected passing score of 0.5, got %+v", score)
+	}
+	if !strings.Contains(score.Reason, "age") {
+		t.Errorf("expected reason to mention mismatched field, got %q", score.Reason)
+	}
+}
+
+func TestStructuredFieldScorerFencedOutput(t *testing.T) {
+	scorer := NewStructuredFieldScorer("name")
+
+	score, err := scorer.Score(context.Background(),
+		TestCase{Expected: `{"name": "Ada"}`},
+		"```json\n{\"name\": \"Ada\"}\n```")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if score.Value != 1 || !score.Passed {
+		t.Errorf("expected the fenced output to match, got %+v", score)
+	}
+}
+
+func TestLLMJudgeScorer(t *testing.T) {
+	scorer := NewLLMJudgeScorer(&mockJudge{response: `Sure: {"score": 0.8, "reason": "mostly right"}`}, "")
+
+	score, err := scorer.Score(context.Background(), TestCase{Input: "q", Expected: "a"}, "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if score.Value != 0.8 || !score.Passed || score.Reason != "mostly right" {
+		t.Errorf("unexpected score: %+v", score)
+	}
+}
+
+func TestLLMJudgeScorerFencedResponse(t *testing.T) {
+	response := "Here is my grade:\n```json\n{\"score\": 0.9, \"reason\": \"uses {braces} correctly\"}\n```\nHope this helps {really}"
+	scorer := NewLLMJudgeScorer(&mockJudge{response: response}, "")
+
+	score, err := scorer.Score(context.Background(), TestCase{Input: "q", Expected: "a"}, "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if score.Value != 0.9 || score.Reason != "uses {braces} correctly" {
+		t.Errorf("unexpected score: %+v", score)
+	}
+}
@@ -0,0 +1,195 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/jsontext"
+)
+
+// ExactMatchScorer passes when the output equals the expected output
+type ExactMatchScorer struct {
+	// CaseInsensitive compares outputs ignoring case
+	CaseInsensitive bool
+	// TrimSpace trims surrounding whitespace before comparing
+	TrimSpace bool
+}
+
+// NewExactMatchScorer creates a scorer that trims whitespace before comparing
+func NewExactMatchScorer() *ExactMatchScorer {
+	return &ExactMatchScorer{TrimSpace: true}
+}
+
+// Score implements Scorer
+func (s *ExactMatchScorer) Score(ctx context.Context, testCase TestCase, output string) (Score, error) {
+	expected := testCase.Expected
+	if s.TrimSpace {
+		expected = strings.TrimSpace(expected)
+		output = strings.TrimSpace(output)
+	}
+
+	var match bool
+	if s.CaseInsensitive {
+		match = strings.EqualFold(expected, output)
+	} else {
+		match = expected == output
+	}
+
+	if match {
+		return Score{Value: 1, Passed: true}, nil
+	}
+	return Score{Value: 0, Passed: false, Reason: "output does not match expected"}, nil
+}
+
+// StructuredFieldScorer compares JSON fields of the output with those of the expected output.
+// The score is the fraction of compared fields that match.
+type StructuredFieldScorer struct {
+	// Fields restricts the comparison to these top-level fields; all expected fields are compared when empty
+	Fields []string
+	// Threshold is the minimum score required to pass (default: 1)
+	Threshold float64
+}
+
+// NewStructuredFieldScorer creates a scorer comparing the given fields
+func NewStructuredFieldScorer(fields ...string) *StructuredFieldScorer {
+	return &StructuredFieldScorer{Fields: fields, Threshold: 1}
+}
+
+// Score implements Scorer
+func (s *StructuredFieldScorer) Score(ctx context.Context, testCase TestCase, output string) (Score, error) {
+	var expected map[string]interface{}
+	if err := json.Unmarshal([]byte(testCase.Expected), &expected); err != nil {
+		return Score{}, fmt.Errorf("failed to parse expected output as JSON: %w", err)
+	}
+
+	// Models often wrap JSON in a markdown code block
+	var actual map[string]interface{}
+	if err := json.Unmarshal([]byte(jsontext.StripCodeFences(output)), &actual); err != nil {
+		return Score{Value: 0, Passed: false, Reason: fmt.Sprintf("output is not valid JSON: %v", err)}, nil
+	}
+
+	fields := s.Fields
+	if len(fields) == 0 {
+		for key := range expected {
+			fields = append(fields, key)
+		}
+	}
+	if len(fields) == 0 {
+		return Score{Value: 1, Passed: true}, nil
+	}
+
+	var matched int
+	var mismatched []string
+	for _, field := range fields {
+		if reflect.DeepEqual(expected[field], actual[field]) {
+			matched++
+		} else {
+			mismatched = append(mismatched, field)
+		}
+	}
+
+	threshold := s.Threshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	value := float64(matched) / float64(len(fields))
+	score := Score{Value: value, Passed: value >= threshold}
+	if len(mismatched) > 0 {
+		score.Reason = "mismatched fields: " + strings.Join(mismatched, ", ")
+	}
+	return score, nil
+}
+
+// LLMJudgeScorer asks an LLM to grade the output against the expected answer
+type LLMJudgeScorer struct {
+	llm       interfaces.LLM
+	criteria  string
+	threshold float64
+}
+
+// NewLLMJudgeScorer creates a scorer that uses the given LLM as a judge
+func NewLLMJudgeScorer(llm interfaces.LLM, criteria string) *LLMJudgeScorer {
+	return &LLMJudgeScorer{
+		llm:       llm,
+		criteria:  criteria,
+		threshold: 0.5,
+	}
+}
+
+// WithThreshold sets the minimum judge score required to pass
+func (s *LLMJudgeScorer) WithThreshold(threshold float64) *LLMJudgeScorer {
+	s.threshold = threshold
+	return s
+}
+
+// Score implements Scorer
+func (s *LLMJudgeScorer) Score(ctx context.Context, testCase TestCase, output string) (Score, error) {
+	criteria := s.criteria
+	if criteria == "" {
+		criteria = "The actual output is correct and consistent with the expected output."
+	}
+
+	prompt := fmt.Sprintf(`You are grading the output of an AI agent.
+
+Criteria: %s
+
+Input:
+%s
+
+Expected output:
+%s
+
+Actual output:
+%s
+
+Respond with only a JSON object of the form {"score": <number between 0 and 1>, "reason": "<short explanation>"}.`,
+		criteria, testCase.Input, testCase.Expected, output)
+
+	response, err := s.llm.Generate(ctx, prompt, interfaces.WithTemperature(0))
+	if err != nil {
+		return Score{}, fmt.Errorf("judge LLM failed: %w", err)
+	}
+
+	value, reason, err := parseJudgeResponse(response)
+	if err != nil {
+		return Score{}, err
+	}
+
+	return Score{Value: value, Passed: value >= s.threshold, Reason: reason}, nil
+}
+
+// parseJudgeResponse extracts the score and reason from the judge response
+func parseJudgeResponse(response string) (float64, string, error) {
+	if text, ok := jsontext.ExtractObject(response); ok {
+		var parsed struct {
+			Score  float64 `json:"score"`
+			Reason string  `json:"reason"`
+		}
+		if err := json.Unmarshal([]byte(text), &parsed); err == nil {
+			return clamp(parsed.Score), parsed.Reason, nil
+		}
+	}
+
+	// Fall back to a bare number
+	value, err := strconv.ParseFloat(strings.TrimSpace(response), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to parse judge response: %s", response)
+	}
+	return clamp(value), "", nil
+}
+
+func clamp(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+	if value > 1 {
+		return 1
+	}
+	return value
+}
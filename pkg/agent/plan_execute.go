@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
+)
+
+// PlanAndExecuteResult is the result of a run in plan-and-execute mode
+type PlanAndExecuteResult struct {
+	Plan        *executionplan.ExecutionPlan // Plan of tool steps generated for the input
+	StepOutputs []string                     // Output of each step, in plan order
+	Result      string                       // Final answer synthesized from the step outputs
+}
+
+// RunPlanAndExecute runs the agent in plan-and-execute mode, see WithPlanAndExecute: it
+// generates a plan of tool steps for the input, executes the steps in sequence and
+// synthesizes the final result from their outputs. The plan is stored with the other
+// execution plans of the agent, see GetTaskByID.
+func (a *Agent) RunPlanAndExecute(ctx context.Context, input string) (*PlanAndExecuteResult, error) {
+	if a.isRemote {
+		return nil, fmt.Errorf("plan-and-execute mode is not supported for remote agents")
+	}
+
+	ctx = tracing.WithAgentName(ctx, a.name)
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+	ctx = a.withUtilityLLM(ctx)
+
+	if a.tracer != nil {
+		var span interfaces.Span
+		ctx, span = a.tracer.StartSpan(ctx, "agent.RunPlanAndExecute")
+		defer span.End()
+	}
+
+	// Add user message to memory
+	if a.memory != nil {
+		if err := a.memory.AddMessage(ctx, interfaces.Message{
+			Role:    "user",
+			Content: input,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add user message to memory: %w", err)
+		}
+	}
+
+	// Apply guardrails to input if available
+	if a.guardrails != nil {
+		guardedInput, err := a.guardrails.ProcessInput(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("guardrails error: %w", err)
+		}
+		input = guardedInput
+	}
+
+	tools, err := a.allowedTools(ctx, a.allTools(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	planner := a.planner
+	if planner == nil {
+		planner = executionplan.NewGenerator(a.llm, tools, a.runSystemPrompt(ctx))
+	}
+
+	plan, err := planner.GenerateExecutionPlan(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate execution plan: %w", err)
+	}
+	if err := a.storePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	a.audit(ctx, executionplan.NewAuditRecord(ctx, executionplan.AuditPlanCreated, plan))
+
+	a.logger.Debug(ctx, "Executing plan", map[string]interface{}{
+		"task_id": plan.TaskID,
+		"steps":   len(plan.Steps),
+	})
+
+	stepCtx, stepTools, done := a.planStepTools(ctx, tools)
+	outputs, err := executionplan.NewExecutor(stepTools).ExecuteSteps(stepCtx, plan)
+	done()
+	a.updateStoredPlan(ctx, plan)
+	if err != nil {
+		record := executionplan.NewAuditRecord(ctx, executionplan.AuditPlanFailed, plan)
+		record.Error = err.Error()
+		a.audit(ctx, record)
+		return nil, fmt.Errorf("failed to execute plan: %w", err)
+	}
+	a.audit(ctx, executionplan.NewAuditRecord(ctx, executionplan.AuditPlanExecuted, plan))
+
+	response, err := a.synthesizePlanResult(ctx, input, plan, outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add agent message to memory
+	if a.memory != nil {
+		if err := a.memory.AddMessage(ctx, interfaces.Message{
+			Role:    "assistant",
+			Content: response,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add agent message to memory: %w", err)
+		}
+	}
+
+	return &PlanAndExecuteResult{
+		Plan:        plan,
+		StepOutputs: outputs,
+		Result:      response,
+	}, nil
+}
+
+// planStepTools decorates the tools executing plan steps like the tools of Run: restricted
+// contexts, the approval hook, the tool result formatter and argument repair and validation.
+// Tool names are kept, since the plan refers to them. The returned function releases the run.
+// Plan steps cannot be resumed, a step paused by the approval hook fails with
+// ErrToolCallPauseUnsupported.
+func (a *Agent) planStepTools(ctx context.Context, tools []interfaces.Tool) (context.Context, []interfaces.Tool, func()) {
+	done := func() {}
+	tools = a.restrictToolContexts(tools)
+	if a.toolApprovalHook != nil && len(tools) > 0 {
+		var run *approvalRun
+		ctx, run, tools = a.startApprovalRun(ctx, "", tools)
+		run.pauseUnsupported = true
+		done = run.cancel
+	}
+	tools = a.formatToolResults(tools)
+	if a.validateToolArgs {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	if a.repairToolArgs {
+		tools = interfaces.RepairingTools(tools)
+	}
+	return ctx, tools, done
+}
+
+// synthesizePlanResult asks the LLM for the final answer to the input from the outputs of the plan steps
+func (a *Agent) synthesizePlanResult(ctx context.Context, input string, plan *executionplan.ExecutionPlan, outputs []string) (string, error) {
+	var steps strings.Builder
+	for i, output := range outputs {
+		step := plan.Steps[i]
+		steps.WriteString(fmt.Sprintf("Step %d (%s, using %s):\n%s\n\n", i+1, step.Description, step.ToolName, output))
+	}
+
+	prompt := fmt.Sprintf(`The following plan was executed to answer the user request.
+
+User request: %s
+
+Plan: %s
+
+Step results:
+%s
+Using these results, provide the final answer to the user request.`, input, plan.Description, steps.String())
+
+	generateOptions := []interfaces.GenerateOption{}
+	if systemPrompt := a.runSystemPrompt(ctx); systemPrompt != "" {
+		generateOptions = append(generateOptions, interfaces.WithSystemMessage(systemPrompt))
+	}
+	if a.responseFormat != nil {
+		generateOptions = append(generateOptions, interfaces.WithResponseFormat(*a.responseFormat))
+	}
+	if a.llmConfig != nil {
+		generateOptions = append(generateOptions, func(options *interfaces.GenerateOptions) {
+			options.LLMConfig = a.llmConfig
+		})
+	}
+	if a.thinkingPolicy != "" {
+		generateOptions = append(generateOptions, interfaces.WithThinkingPolicy(a.thinkingPolicy))
+	}
+
+	response, err := a.llm.Generate(ctx, prompt, generateOptions...)
+	if err != nil {
+		return "", fmt.Errorf("failed to synthesize plan result: %w", err)
+	}
+
+	response = a.applyThinkingPolicy(ctx, response)
+
+	// Apply guardrails to output if available
+	if a.guardrails != nil {
+		guardedResponse, err := a.guardrails.ProcessOutput(ctx, response)
+		if err != nil {
+			return "", fmt.Errorf("guardrails error: %w", err)
+		}
+		response = guardedResponse
+	}
+
+	return response, nil
+}
@@ -0,0 +1,232 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// staticPlanner returns the same plan for every input
+type staticPlanner struct {
+	steps []executionplan.ExecutionStep
+	input string
+}
+
+func (p *staticPlanner) GenerateExecutionPlan(ctx context.Context, input string) (*executionplan.ExecutionPlan, error) {
+	p.input = input
+	return executionplan.NewExecutionPlan("Find and summarize", p.steps), nil
+}
+
+func TestPlanAndExecute(t *testing.T) {
+	var calls []string
+	search := &mockTool{name: "search", description: "Searches the web", runFunc: func(ctx context.Context, input string) (string, error) {
+		calls = append(calls, "search:"+input)
+		return "Go 1.24 was released in February", nil
+	}}
+	summarize := &mockTool{name: "summarize", description: "Summarizes text", runFunc: func(ctx context.Context, input string) (string, error) {
+		calls = append(calls, "summarize:"+input)
+		return "Go 1.24 is out", nil
+	}}
+
+	planner := &staticPlanner{steps: []executionplan.ExecutionStep{
+		{ToolName: "search", Description: "Search for the release", Input: "go release"},
+		{ToolName: "summarize", Description: "Summarize the findings", Input: "{{previous}}"},
+	}}
+
+	var synthesisPrompt string
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			synthesisPrompt = prompt
+			return "The latest Go release is 1.24.", nil
+		},
+	}
+
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(WithLLM(llm), WithMemory(mem), WithTools(search, summarize), WithPlanAndExecute(planner))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "conv-1")
+	result, err := agent.RunPlanAndExecute(ctx, "What is the latest Go release?")
+	if err != nil {
+		t.Fatalf("RunPlanAndExecute failed: %v", err)
+	}
+
+	if planner.input != "What is the latest Go release?" {
+		t.Errorf("Expected the planner to receive the input, got %q", planner.input)
+	}
+
+	// Steps run in sequence, the output of the search feeding the summary
+	expectedCalls := []string{"search:go release", "summarize:Go 1.24 was released in February"}
+	if !reflect.DeepEqual(calls, expectedCalls) {
+		t.Errorf("Expected calls %v, got %v", expectedCalls, calls)
+	}
+
+	if result.Plan == nil || len(result.Plan.Steps) != 2 || result.Plan.Status != executionplan.StatusCompleted {
+		t.Fatalf("Expected the completed two step plan, got %+v", result.Plan)
+	}
+	if !reflect.DeepEqual(result.StepOutputs, []string{"Go 1.24 was released in February", "Go 1.24 is out"}) {
+		t.Errorf("Unexpected step outputs: %v", result.StepOutputs)
+	}
+	if result.Result != "The latest Go release is 1.24." {
+		t.Errorf("Unexpected result: %q", result.Result)
+	}
+
+	// The final answer is synthesized from the step outputs
+	for _, expected := range []string{"What is the latest Go release?", "Go 1.24 was released in February", "Go 1.24 is out"} {
+		if !strings.Contains(synthesisPrompt, expected) {
+			t.Errorf("Expected the synthesis prompt to contain %q, got %q", expected, synthesisPrompt)
+		}
+	}
+
+	if _, ok := agent.GetTaskByID(result.Plan.TaskID); !ok {
+		t.Error("Expected the plan to be stored")
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get messages: %v", err)
+	}
+	if len(messages) != 2 || messages[1].Content != "The latest Go release is 1.24." {
+		t.Errorf("Expected the input and the result in memory, got %+v", messages)
+	}
+
+	// Run returns the synthesized result
+	calls = nil
+	response, err := agent.Run(ctx, "What is the latest Go release?")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if response != "The latest Go release is 1.24." || len(calls) != 2 {
+		t.Errorf("Expected Run to plan and execute, got %q after calls %v", response, calls)
+	}
+
+	// So does RunWithAuth, which runs local agents like Run
+	calls = nil
+	response, err = agent.RunWithAuth(ctx, "What is the latest Go release?", "token")
+	if err != nil {
+		t.Fatalf("RunWithAuth failed: %v", err)
+	}
+	if response != "The latest Go release is 1.24." || len(calls) != 2 {
+		t.Errorf("Expected RunWithAuth to plan and execute, got %q after calls %v", response, calls)
+	}
+}
+
+func TestPlanAndExecuteStepFailure(t *testing.T) {
+	failing := &mockTool{name: "search", runFunc: func(ctx context.Context, input string) (string, error) {
+		return "", errors.New("search unavailable")
+	}}
+	var summarized bool
+	summarize := &mockTool{name: "summarize", runFunc: func(ctx context.Context, input string) (string, error) {
+		summarized = true
+		return "", nil
+	}}
+
+	planner := &staticPlanner{steps: []executionplan.ExecutionStep{
+		{ToolName: "search", Input: "go release"},
+		{ToolName: "summarize", Input: "{{step1}}"},
+	}}
+	agent, err := NewAgent(WithLLM(&mockLLM{}), WithTools(failing, summarize), WithPlanAndExecute(planner))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	if _, err := agent.RunPlanAndExecute(context.Background(), "What is the latest Go release?"); err == nil {
+		t.Fatal("Expected an error when a step fails")
+	}
+	if summarized {
+		t.Error("Expected the steps after a failure not to run")
+	}
+}
+
+func TestPlanAndExecutePausedStep(t *testing.T) {
+	var searched bool
+	search := &mockTool{name: "search", runFunc: func(ctx context.Context, input string) (string, error) {
+		searched = true
+		return "", nil
+	}}
+
+	planner := &staticPlanner{steps: []executionplan.ExecutionStep{{ToolName: "search", Input: "go release"}}}
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithTools(search),
+		WithPlanAndExecute(planner),
+		WithToolApprovalHook(func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error) {
+			return ToolApprovalPause, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	_, err = agent.RunPlanAndExecute(context.Background(), "What is the latest Go release?")
+	if !errors.Is(err, ErrToolCallPauseUnsupported) {
+		t.Fatalf("Expected the paused step to fail the run, got %v", err)
+	}
+	if searched {
+		t.Error("Expected the paused step not to run")
+	}
+}
+
+func TestPlanAndExecuteUsesToolChain(t *testing.T) {
+	var calls []string
+	search := &mockTool{name: "search", runFunc: func(ctx context.Context, input string) (string, error) {
+		calls = append(calls, "search:"+input)
+		return "results", nil
+	}}
+	deleteTool := &mockTool{name: "delete", runFunc: func(ctx context.Context, input string) (string, error) {
+		calls = append(calls, "delete:"+input)
+		return "deleted", nil
+	}}
+
+	planner := &staticPlanner{steps: []executionplan.ExecutionStep{
+		{ToolName: "search", Input: `{"input":"go"}`},
+		{ToolName: "delete", Input: `{"input":"{{previous}}"}`},
+		{ToolName: "search", Input: `{"input":1}`},
+	}}
+	var hookCalls []string
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithTools(search, deleteTool),
+		WithPlanAndExecute(planner),
+		WithToolArgumentValidation(true),
+		WithToolApprovalHook(func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error) {
+			hookCalls = append(hookCalls, toolCall.Name)
+			if toolCall.Name == "delete" {
+				return ToolApprovalDeny, nil
+			}
+			return ToolApprovalApprove, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	result, err := agent.RunPlanAndExecute(context.Background(), "Clean up")
+	if err != nil {
+		t.Fatalf("RunPlanAndExecute failed: %v", err)
+	}
+
+	// The denied and the invalid steps are not executed
+	if !reflect.DeepEqual(calls, []string{`search:{"input":"go"}`}) {
+		t.Errorf("Unexpected tool calls: %v", calls)
+	}
+	if !reflect.DeepEqual(hookCalls, []string{"search", "delete"}) {
+		t.Errorf("Expected the approval hook to be consulted for valid steps, got %v", hookCalls)
+	}
+	if !strings.Contains(result.StepOutputs[1], "denied") {
+		t.Errorf("Expected the denial as output of the delete step, got %q", result.StepOutputs[1])
+	}
+	if _, ok := interfaces.ParseToolValidationError(result.StepOutputs[2]); !ok {
+		t.Errorf("Expected a validation error as output of the invalid step, got %q", result.StepOutputs[2])
+	}
+}
@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// PlanStore persists execution plans, so that pending plans survive a restart of the process
+// and can still be approved by task ID. Load and Delete return an error wrapping
+// executionplan.ErrPlanNotFound for unknown task IDs. executionplan.FilePlanStore and
+// executionplan.RedisPlanStore implement PlanStore.
+type PlanStore interface {
+	// Save stores the plan, replacing any previous version with the same task ID
+	Save(ctx context.Context, plan *executionplan.ExecutionPlan) error
+	// Load returns the plan with the given task ID
+	Load(ctx context.Context, taskID string) (*executionplan.ExecutionPlan, error)
+	// List returns all stored plans
+	List(ctx context.Context) ([]*executionplan.ExecutionPlan, error)
+	// Delete removes the plan with the given task ID
+	Delete(ctx context.Context, taskID string) error
+}
+
+// WithPlanStore persists execution plans to the store when they are created, modified,
+// approved, executed or cancelled. Plans not known to the agent are loaded from the store
+// by GetTaskByID, so a plan created before a restart can still be approved.
+func WithPlanStore(store PlanStore) Option {
+	return func(a *Agent) {
+		a.persistentPlans = store
+	}
+}
+
+// storePlan keeps the plan in memory and saves it to the plan store, if configured
+func (a *Agent) storePlan(ctx context.Context, plan *executionplan.ExecutionPlan) error {
+	if plan.OrgID == "" && plan.ConversationID == "" {
+		plan.OrgID, plan.ConversationID = planScope(ctx)
+	}
+	a.planStore.StorePlan(plan)
+	if a.persistentPlans == nil {
+		return nil
+	}
+	if err := a.persistentPlans.Save(ctx, plan); err != nil {
+		return fmt.Errorf("failed to persist execution plan %s: %w", plan.TaskID, err)
+	}
+	return nil
+}
+
+// updateStoredPlan saves a status change of a plan. Failures are logged and do not
+// interrupt the plan lifecycle.
+func (a *Agent) updateStoredPlan(ctx context.Context, plan *executionplan.ExecutionPlan) {
+	if err := a.storePlan(ctx, plan); err != nil {
+		a.logger.Warn(ctx, "Failed to persist execution plan", map[string]interface{}{
+			"task_id": plan.TaskID,
+			"status":  string(plan.Status),
+			"error":   err.Error(),
+		})
+	}
+}
+
+// loadPlan returns a plan from memory or else from the plan store, if configured
+func (a *Agent) loadPlan(ctx context.Context, taskID string) (*executionplan.ExecutionPlan, bool) {
+	if plan, exists := a.planStore.GetPlanByTaskID(taskID); exists {
+		return plan, true
+	}
+	if a.persistentPlans == nil {
+		return nil, false
+	}
+
+	plan, err := a.persistentPlans.Load(ctx, taskID)
+	if err != nil {
+		if !errors.Is(err, executionplan.ErrPlanNotFound) {
+			a.logger.Warn(ctx, "Failed to load execution plan", map[string]interface{}{
+				"task_id": taskID,
+				"error":   err.Error(),
+			})
+		}
+		return nil, false
+	}
+	a.planStore.StorePlan(plan)
+	return plan, true
+}
+
+// listPlans returns the plans in memory and in the plan store, if configured
+func (a *Agent) listPlans(ctx context.Context) []*executionplan.ExecutionPlan {
+	plans := a.planStore.ListPlans()
+	if a.persistentPlans == nil {
+		return plans
+	}
+
+	stored, err := a.persistentPlans.List(ctx)
+	if err != nil {
+		a.logger.Warn(ctx, "Failed to list execution plans", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return plans
+	}
+
+	// Prefer the plans in memory, which may be further along than their stored version
+	for _, plan := range stored {
+		if _, exists := a.planStore.GetPlanByTaskID(plan.TaskID); !exists {
+			plans = append(plans, plan)
+		}
+	}
+	return plans
+}
+
+// planScope returns the organization and conversation in context, which plans are scoped to
+func planScope(ctx context.Context) (string, string) {
+	orgID, _ := multitenancy.GetOrgID(ctx)
+	conversationID, _ := memory.GetConversationID(ctx)
+	return orgID, conversationID
+}
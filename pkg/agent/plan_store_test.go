@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestPlanStoreSurvivesRestart(t *testing.T) {
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			return `{"description": "Look up the weather", "steps": [{"toolName": "get_weather", "description": "Fetch weather", "input": "Paris"}]}`, nil
+		},
+	}
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "sunny in " + input, nil
+		},
+	}
+
+	store, err := executionplan.NewFilePlanStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create plan store: %v", err)
+	}
+
+	first, err := NewAgent(WithLLM(llm), WithTools(weather), WithPlanStore(store))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	plan, err := first.GenerateExecutionPlan(context.Background(), "What's the weather?")
+	if err != nil {
+		t.Fatalf("failed to generate plan: %v", err)
+	}
+
+	// A new agent sharing the store stands in for the restarted process
+	second, err := NewAgent(WithLLM(llm), WithTools(weather), WithPlanStore(store))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	tasks := second.ListTasks()
+	if len(tasks) != 1 || tasks[0].TaskID != plan.TaskID {
+		t.Fatalf("expected the persisted plan to be listed, got %+v", tasks)
+	}
+
+	restored, ok := second.GetTaskByID(plan.TaskID)
+	if !ok {
+		t.Fatalf("expected plan %s to be loaded from the store", plan.TaskID)
+	}
+	if restored.Status != executionplan.StatusPendingApproval {
+		t.Errorf("expected a pending plan, got status %s", restored.Status)
+	}
+
+	result, err := second.ApproveExecutionPlan(context.Background(), restored)
+	if err != nil {
+		t.Fatalf("failed to approve plan: %v", err)
+	}
+	if !strings.Contains(result, "sunny in Paris") {
+		t.Errorf("unexpected execution result: %q", result)
+	}
+
+	stored, err := store.Load(context.Background(), plan.TaskID)
+	if err != nil {
+		t.Fatalf("failed to load plan: %v", err)
+	}
+	if stored.Status != executionplan.StatusCompleted || !stored.UserApproved {
+		t.Errorf("expected the completed plan in the store, got %+v", stored)
+	}
+
+	if _, ok := second.GetTaskByID("unknown"); ok {
+		t.Error("expected unknown task IDs not to be found")
+	}
+}
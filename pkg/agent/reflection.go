@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// reflectionApproved is the reply of the critique when the draft needs no revision
+const reflectionApproved = "APPROVED"
+
+// reflectionCritiquePrompt asks the LLM to critique a draft answer against the task
+const reflectionCritiquePrompt = `Review the draft answer to the task below.
+
+Task: %s
+
+Draft answer:
+%s
+
+Check that the draft fully and correctly answers the task: look for factual errors, missing parts, unclear statements and ignored instructions.
+If the draft needs no changes, reply with exactly ` + reflectionApproved + `. Otherwise, list the issues to fix, without rewriting the answer.`
+
+// reflectionRevisionPrompt asks the LLM to revise a draft answer following the critique
+const reflectionRevisionPrompt = `Revise the draft answer to the task below, fixing the issues raised in the review.
+
+Task: %s
+
+Draft answer:
+%s
+
+Review:
+%s
+
+Reply with the revised answer only.`
+
+// WithReflection makes the agent critique its draft answer against the task before returning
+// it. While the critique finds issues, the answer is revised, at most maxRevisions times.
+// Every critique and revision is an additional LLM call.
+func WithReflection(maxRevisions int) Option {
+	return func(a *Agent) {
+		a.maxRevisions = maxRevisions
+	}
+}
+
+// reflect critiques the draft answer to the input and revises it while issues are found, up to
+// the maximum number of revisions. If a critique or revision fails, the latest answer is kept.
+func (a *Agent) reflect(ctx context.Context, input, draft string) string {
+	for revision := 1; revision <= a.maxRevisions; revision++ {
+		critique, err := a.llm.Generate(ctx, fmt.Sprintf(reflectionCritiquePrompt, input, draft), a.reflectionOptions(ctx, false)...)
+		if err != nil {
+			a.logger.Warn(ctx, "Failed to critique draft answer", map[string]interface{}{
+				"agent":    a.name,
+				"revision": revision,
+				"error":    err.Error(),
+			})
+			return draft
+		}
+
+		critique = strings.TrimSpace(a.applyThinkingPolicy(ctx, critique))
+		if strings.HasPrefix(strings.ToUpper(critique), reflectionApproved) {
+			a.logger.Debug(ctx, "Draft answer approved by reflection", map[string]interface{}{
+				"agent":     a.name,
+				"revisions": revision - 1,
+			})
+			return draft
+		}
+
+		a.logger.Debug(ctx, "Revising draft answer", map[string]interface{}{
+			"agent":    a.name,
+			"revision": revision,
+			"critique": critique,
+		})
+
+		revised, err := a.llm.Generate(ctx, fmt.Sprintf(reflectionRevisionPrompt, input, draft, critique), a.reflectionOptions(ctx, true)...)
+		if err != nil {
+			a.logger.Warn(ctx, "Failed to revise draft answer", map[string]interface{}{
+				"agent":    a.name,
+				"revision": revision,
+				"error":    err.Error(),
+			})
+			return draft
+		}
+		draft = a.applyThinkingPolicy(ctx, revised)
+	}
+
+	return draft
+}
+
+// reflectionOptions returns the generate options of the reflection calls. Revisions follow the
+// system prompt and response format of the agent, critiques only its LLM configuration.
+func (a *Agent) reflectionOptions(ctx context.Context, revision bool) []interfaces.GenerateOption {
+	generateOptions := []interfaces.GenerateOption{}
+	if systemPrompt := a.runSystemPrompt(ctx); revision && systemPrompt != "" {
+		generateOptions = append(generateOptions, interfaces.WithSystemMessage(systemPrompt))
+	}
+	if revision && a.responseFormat != nil {
+		generateOptions = append(generateOptions, interfaces.WithResponseFormat(*a.responseFormat))
+	}
+	if a.llmConfig != nil {
+		generateOptions = append(generateOptions, func(options *interfaces.GenerateOptions) {
+			options.LLMConfig = a.llmConfig
+		})
+	}
+	if a.thinkingPolicy != "" {
+		generateOptions = append(generateOptions, interfaces.WithThinkingPolicy(a.thinkingPolicy))
+	}
+	return generateOptions
+}
@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestReflectionRevisesFlawedDraft(t *testing.T) {
+	var prompts []string
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			prompts = append(prompts, prompt)
+			switch {
+			case strings.HasPrefix(prompt, "Review the draft answer"):
+				if strings.Contains(prompt, "Paris is the capital of France.") {
+					return "APPROVED", nil
+				}
+				return "The draft names the wrong city.", nil
+			case strings.HasPrefix(prompt, "Revise the draft answer"):
+				return "Paris is the capital of France.", nil
+			default:
+				return "Lyon is the capital of France.", nil
+			}
+		},
+	}
+
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(WithLLM(llm), WithMemory(mem), WithReflection(2))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "conv-1")
+	response, err := agent.Run(ctx, "What is the capital of France?")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if response != "Paris is the capital of France." {
+		t.Errorf("Expected the revised answer, got %q", response)
+	}
+
+	// Draft, critique, revision and the critique approving the revision
+	if len(prompts) != 4 {
+		t.Fatalf("Expected 4 LLM calls, got %d: %v", len(prompts), prompts)
+	}
+	if !strings.Contains(prompts[1], "What is the capital of France?") || !strings.Contains(prompts[1], "Lyon is the capital of France.") {
+		t.Errorf("Expected the critique to include the task and the draft, got %q", prompts[1])
+	}
+	if !strings.Contains(prompts[2], "The draft names the wrong city.") {
+		t.Errorf("Expected the revision to include the critique, got %q", prompts[2])
+	}
+
+	// Only the final answer is stored
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get messages: %v", err)
+	}
+	last := messages[len(messages)-1]
+	if last.Role != "assistant" || last.Content != "Paris is the capital of France." {
+		t.Errorf("Expected the revised answer in memory, got %+v", last)
+	}
+}
+
+func TestReflectionMaxRevisions(t *testing.T) {
+	critiques := 0
+	revisions := 0
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			switch {
+			case strings.HasPrefix(prompt, "Review the draft answer"):
+				critiques++
+				return "Still not good enough.", nil
+			case strings.HasPrefix(prompt, "Revise the draft answer"):
+				revisions++
+				return "Revision " + string(rune('0'+revisions)), nil
+			default:
+				return "Draft", nil
+			}
+		},
+	}
+
+	agent, err := NewAgent(WithLLM(llm), WithReflection(2))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "Write a haiku")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if response != "Revision 2" {
+		t.Errorf("Expected the last revision, got %q", response)
+	}
+	if critiques != 2 || revisions != 2 {
+		t.Errorf("Expected 2 critiques and 2 revisions, got %d and %d", critiques, revisions)
+	}
+}
+
+func TestReflectionKeepsDraftOnFailure(t *testing.T) {
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			if strings.HasPrefix(prompt, "Review the draft answer") {
+				return "", errors.New("rate limited")
+			}
+			return "Draft", nil
+		},
+	}
+
+	agent, err := NewAgent(WithLLM(llm), WithReflection(1))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "Write a haiku")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if response != "Draft" {
+		t.Errorf("Expected the draft when the critique fails, got %q", response)
+	}
+}
@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// RunOption configures a single run of an agent, see RunWithOptions
+type RunOption func(*runOptions)
+
+// runOptions holds the settings of a single run
+type runOptions struct {
+	agent        *Agent
+	systemPrompt *string
+}
+
+// runOptionsKey is the context key of the run options
+type runOptionsKey struct{}
+
+// WithRunSystemPrompt replaces the system prompt of the agent for a single run, e.g. to inject
+// the current date or the preferences of the user. It takes precedence over WithSystemPrompt
+// and the system prompt of the agent config; the tool list of WithToolsInPrompt is still
+// appended. An empty prompt runs without a system prompt.
+func WithRunSystemPrompt(prompt string) RunOption {
+	return func(o *runOptions) {
+		o.systemPrompt = &prompt
+	}
+}
+
+// RunWithOptions runs the agent like Run, with settings for this run only. The configuration
+// and memory of the agent are left unchanged.
+func (a *Agent) RunWithOptions(ctx context.Context, input string, options ...RunOption) (string, error) {
+	return a.Run(a.withRunOptions(ctx, options), input)
+}
+
+// RunStreamWithOptions runs the agent like RunStream, with settings for this run only
+func (a *Agent) RunStreamWithOptions(ctx context.Context, input string, options ...RunOption) (<-chan interfaces.AgentStreamEvent, error) {
+	return a.RunStream(a.withRunOptions(ctx, options), input)
+}
+
+// withRunOptions stores the run options in the context. The options are bound to the agent, so
+// sub-agents called during the run keep their own settings.
+func (a *Agent) withRunOptions(ctx context.Context, options []RunOption) context.Context {
+	opts := &runOptions{agent: a}
+	for _, option := range options {
+		option(opts)
+	}
+	return context.WithValue(ctx, runOptionsKey{}, opts)
+}
+
+// runSystemPrompt returns the system prompt of the run in context
+func (a *Agent) runSystemPrompt(ctx context.Context) string {
+	if opts, ok := ctx.Value(runOptionsKey{}).(*runOptions); ok && opts.agent == a && opts.systemPrompt != nil {
+		return *opts.systemPrompt
+	}
+	return a.systemPrompt
+}
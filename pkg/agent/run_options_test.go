@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithRunSystemPrompt(t *testing.T) {
+	llm := &systemMessageLLM{}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithTools(&mockTool{name: "weather", description: "Gets the current weather"}),
+		WithSystemPrompt("You are a helpful assistant."),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	override := "You are a helpful assistant. Today is 2025-01-01."
+	if _, err := agent.RunWithOptions(context.Background(), "What's the weather?", WithRunSystemPrompt(override)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if llm.systemMessage != override {
+		t.Errorf("Expected the run system prompt, got %q", llm.systemMessage)
+	}
+
+	events, err := agent.RunStreamWithOptions(context.Background(), "What's the weather?", WithRunSystemPrompt(override))
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	for event := range events {
+		if event.Error != nil {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+	}
+	if llm.systemMessage != override {
+		t.Errorf("Expected the run system prompt when streaming, got %q", llm.systemMessage)
+	}
+
+	// Later runs use the system prompt of the agent again
+	if _, err := agent.Run(context.Background(), "What's the weather?"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if llm.systemMessage != "You are a helpful assistant." {
+		t.Errorf("Expected the system prompt of the agent, got %q", llm.systemMessage)
+	}
+	if agent.GetSystemPrompt() != "You are a helpful assistant." {
+		t.Errorf("Expected the agent configuration to be unchanged, got %q", agent.GetSystemPrompt())
+	}
+}
+
+func TestRunSystemPromptBoundToAgent(t *testing.T) {
+	parent, err := NewAgent(WithLLM(&mockLLM{}), WithSystemPrompt("parent"))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	child, err := NewAgent(WithLLM(&mockLLM{}), WithSystemPrompt("child"))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := parent.withRunOptions(context.Background(), []RunOption{WithRunSystemPrompt("override")})
+	if got := parent.runSystemPrompt(ctx); got != "override" {
+		t.Errorf("Expected the override for the agent run, got %q", got)
+	}
+	if got := child.runSystemPrompt(ctx); got != "child" {
+		t.Errorf("Expected sub-agents to keep their system prompt, got %q", got)
+	}
+}
+
+func TestRunSystemPromptKeepsPlanGenerator(t *testing.T) {
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithTools(&mockTool{name: "weather", description: "Gets the current weather"}),
+		WithSystemPrompt("You are a helpful assistant."),
+		WithRequirePlanApproval(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	// The plan of the run is generated with the override, without replacing the generator of
+	// the agent used by concurrent runs
+	generator := agent.planGenerator
+	_, _ = agent.RunWithOptions(context.Background(), "What's the weather?", WithRunSystemPrompt("override"))
+	if agent.planGenerator != generator {
+		t.Error("Expected the plan generator of the agent to be unchanged by the run")
+	}
+}
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Skill is a reusable bundle of instructions, tools and an optional response format, such as
+// "web research", that agents are composed from with WithSkills
+type Skill struct {
+	// Name identifies the skill
+	Name string
+	// SystemPromptSection is appended to the system prompt of the agent
+	SystemPromptSection string
+	// Tools are added to the tools of the agent
+	Tools []interfaces.Tool
+	// ResponseFormat is used when the agent has no response format of its own
+	ResponseFormat *interfaces.ResponseFormat
+}
+
+// WithSkills composes skills into the agent when it is created: their system prompt sections
+// are appended to the system prompt in order, and their tools are added to the tools of the
+// agent, skipping tools whose name is already taken. A response format of a skill is used when
+// the agent has none; skills may not declare different response formats.
+func WithSkills(skills ...Skill) Option {
+	return func(a *Agent) {
+		a.skills = append(a.skills, skills...)
+	}
+}
+
+// GetSkills returns the skills the agent is composed from
+func (a *Agent) GetSkills() []Skill {
+	return a.skills
+}
+
+// applySkills composes the skills of the agent into its system prompt, tools and response format
+func (a *Agent) applySkills() error {
+	if len(a.skills) == 0 {
+		return nil
+	}
+
+	sections := []string{}
+	if prompt := strings.TrimSpace(a.systemPrompt); prompt != "" {
+		sections = append(sections, prompt)
+	}
+
+	toolNames := map[string]bool{}
+	for _, tool := range a.tools {
+		toolNames[tool.Name()] = true
+	}
+
+	skillNames := map[string]bool{}
+	var formatSkill string
+	for _, skill := range a.skills {
+		if skill.Name == "" {
+			return fmt.Errorf("skill name is required")
+		}
+		if skillNames[skill.Name] {
+			return fmt.Errorf("skill %s is added more than once", skill.Name)
+		}
+		skillNames[skill.Name] = true
+
+		if section := strings.TrimSpace(skill.SystemPromptSection); section != "" {
+			sections = append(sections, section)
+		}
+
+		for _, tool := range skill.Tools {
+			if toolNames[tool.Name()] {
+				continue
+			}
+			toolNames[tool.Name()] = true
+			a.tools = append(a.tools, tool)
+		}
+
+		if skill.ResponseFormat == nil {
+			continue
+		}
+		switch {
+		case formatSkill != "":
+			if skill.ResponseFormat.Name != a.responseFormat.Name {
+				return fmt.Errorf("skills %s and %s declare different response formats", formatSkill, skill.Name)
+			}
+		case a.responseFormat == nil:
+			format := *skill.ResponseFormat
+			a.responseFormat = &format
+			formatSkill = skill.Name
+		}
+	}
+
+	a.systemPrompt = strings.Join(sections, "\n\n")
+	return nil
+}
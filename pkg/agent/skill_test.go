@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestWithSkillsComposesAgent(t *testing.T) {
+	search := &mockTool{name: "web_search", description: "Searches the web"}
+	fetch := &mockTool{name: "fetch_page", description: "Fetches a web page"}
+	calculator := &mockTool{name: "calculator", description: "Evaluates expressions"}
+	ownSearch := &mockTool{name: "web_search", description: "Searches the company wiki"}
+
+	research := Skill{
+		Name:                "web_research",
+		SystemPromptSection: "Research questions on the web and cite your sources.",
+		Tools:               []interfaces.Tool{search, fetch},
+	}
+	report := Skill{
+		Name:                "reporting",
+		SystemPromptSection: "Summarize findings as a short report.",
+		Tools:               []interfaces.Tool{calculator, fetch},
+		ResponseFormat: &interfaces.ResponseFormat{
+			Type: interfaces.ResponseFormatJSON,
+			Name: "Report",
+		},
+	}
+
+	var systemMessage string
+	llm := &mockLLM{generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+		params := &interfaces.GenerateOptions{}
+		for _, option := range options {
+			option(params)
+		}
+		systemMessage = params.SystemMessage
+		return `{"summary":"done"}`, nil
+	}}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithSkills(research, report),
+		WithSystemPrompt("You are an analyst."),
+		WithTools(ownSearch),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	var names []string
+	for _, tool := range agent.GetTools() {
+		names = append(names, tool.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"web_search", "fetch_page", "calculator"}) {
+		t.Errorf("Expected the combined tools of the agent and skills, got %v", names)
+	}
+	if agent.GetTools()[0] != ownSearch {
+		t.Error("Expected the tool of the agent to take precedence over a skill tool of the same name")
+	}
+
+	expectedPrompt := "You are an analyst.\n\n" +
+		"Research questions on the web and cite your sources.\n\n" +
+		"Summarize findings as a short report."
+	if prompt := agent.GetSystemPrompt(); prompt != expectedPrompt {
+		t.Errorf("Expected the merged prompt sections, got %q", prompt)
+	}
+	if agent.responseFormat == nil || agent.responseFormat.Name != "Report" {
+		t.Errorf("Expected the response format of the reporting skill, got %+v", agent.responseFormat)
+	}
+	if len(agent.GetSkills()) != 2 {
+		t.Errorf("Expected the agent to report its 2 skills, got %d", len(agent.GetSkills()))
+	}
+
+	if _, err := agent.Run(context.Background(), "How did the market do?"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(systemMessage, "cite your sources") || !strings.Contains(systemMessage, "short report") {
+		t.Errorf("Expected the LLM to receive the merged system prompt, got %q", systemMessage)
+	}
+}
+
+func TestWithSkillsErrors(t *testing.T) {
+	jsonFormat := func(name string) *interfaces.ResponseFormat {
+		return &interfaces.ResponseFormat{Type: interfaces.ResponseFormatJSON, Name: name}
+	}
+
+	tests := map[string][]Skill{
+		"missing name":       {{SystemPromptSection: "Be brief."}},
+		"duplicate skill":    {{Name: "research"}, {Name: "research"}},
+		"conflicting format": {{Name: "report", ResponseFormat: jsonFormat("Report")}, {Name: "table", ResponseFormat: jsonFormat("Table")}},
+	}
+	for name, skills := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewAgent(WithLLM(&mockLLM{}), WithSkills(skills...)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	// The response format of the agent takes precedence over the skills
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithResponseFormat(*jsonFormat("Answer")),
+		WithSkills(Skill{Name: "report", ResponseFormat: jsonFormat("Report")}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if agent.responseFormat.Name != "Answer" {
+		t.Errorf("Expected the response format of the agent, got %s", agent.responseFormat.Name)
+	}
+}
@@ -29,10 +29,10 @@ func (a *Agent) RunStream(ctx context.Context, input string) (<-chan interfaces.
 
 // runLocalStream executes a local agent with streaming
 func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interfaces.AgentStreamEvent, error) {
-	// Check if LLM supports streaming
+	// LLMs without streaming support run to completion and stream the final response
 	streamingLLM, ok := a.llm.(interfaces.StreamingLLM)
-	if !ok {
-		return nil, fmt.Errorf("LLM '%s' does not support streaming", a.llm.Name())
+	if !ok || !streamingLLM.SupportsStreaming() {
+		return a.runBufferedStream(ctx, input), nil
 	}
 
 	// Get buffer size from default config
@@ -52,6 +52,7 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 		if a.orgID != "" {
 			ctx = multitenancy.WithOrgID(ctx, a.orgID)
 		}
+		ctx = a.withUtilityLLM(ctx)
 
 		// Start tracing if available
 		var span interfaces.Span
@@ -112,8 +113,8 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 		}
 
 		// Check if the user is asking about the agent's role or identity
-		if a.systemPrompt != "" && a.isAskingAboutRole(processedInput) {
-			response := a.generateRoleResponse()
+		if systemPrompt := a.runSystemPrompt(ctx); systemPrompt != "" && a.isAskingAboutRole(processedInput) {
+			response := a.generateRoleResponse(systemPrompt)
 
 			// Add the role response to memory if available
 			if a.memory != nil {
@@ -156,11 +157,20 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 				allTools = append(allTools, mcpTools...)
 			}
 		}
+		allTools, err := a.allowedTools(ctx, allTools)
+		if err != nil {
+			eventChan <- interfaces.AgentStreamEvent{
+				Type:      interfaces.AgentEventError,
+				Error:     err,
+				Timestamp: time.Now(),
+			}
+			return
+		}
 
 		// If tools are available and plan approval is required, we can't stream execution plans yet
 		if (len(allTools) > 0) && a.requirePlanApproval {
 			// For now, fall back to non-streaming execution plan generation
-			result, err := a.runWithExecutionPlan(ctx, processedInput)
+			result, err := a.runWithExecutionPlan(ctx, a.runPlanGenerator(ctx, allTools), processedInput)
 			if err != nil {
 				eventChan <- interfaces.AgentStreamEvent{
 					Type:      interfaces.AgentEventError,
@@ -188,12 +198,52 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 				Error:     err,
 				Timestamp: time.Now(),
 			}
+			return
 		}
+
+		// The stream is closed once the conversation metadata is updated
+		a.completeRun(ctx)
 	}()
 
 	return eventChan, nil
 }
 
+// runBufferedStream runs the agent without streaming and sends its final response as a single
+// content event, followed by a completion event
+func (a *Agent) runBufferedStream(ctx context.Context, input string) <-chan interfaces.AgentStreamEvent {
+	eventChan := make(chan interfaces.AgentStreamEvent, 2)
+
+	go func() {
+		defer close(eventChan)
+
+		response, err := a.Run(ctx, input)
+		if ctx.Err() != nil {
+			sendCancelledEvent(ctx, eventChan)
+			return
+		}
+		if err != nil {
+			eventChan <- interfaces.AgentStreamEvent{
+				Type:      interfaces.AgentEventError,
+				Error:     err,
+				Timestamp: time.Now(),
+			}
+			return
+		}
+
+		eventChan <- interfaces.AgentStreamEvent{
+			Type:      interfaces.AgentEventContent,
+			Content:   response,
+			Timestamp: time.Now(),
+		}
+		eventChan <- interfaces.AgentStreamEvent{
+			Type:      interfaces.AgentEventComplete,
+			Timestamp: time.Now(),
+		}
+	}()
+
+	return eventChan
+}
+
 // runStreamingGeneration handles the core streaming generation logic
 func (a *Agent) runStreamingGeneration(
 	ctx context.Context,
@@ -202,13 +252,25 @@ func (a *Agent) runStreamingGeneration(
 	streamingLLM interfaces.StreamingLLM,
 	eventChan chan<- interfaces.AgentStreamEvent,
 ) error {
+	// Gate tool calls behind the approval hook if configured
+	var run *approvalRun
+	ungatedTools := tools
+	tools = a.restrictToolContexts(tools)
+	if a.toolApprovalHook != nil && len(tools) > 0 {
+		ctx, run, tools = a.startApprovalRun(ctx, "", tools)
+		defer run.cancel()
+	}
+
+	// Tools are named as the LLM sees them, sanitizing deterministically keeps the names in sync
+	tools = a.sanitizeToolNames(tools)
+
 	// Prepare generation options
 	options := []interfaces.GenerateOption{}
 
 	// Add system prompt if available
-	if a.systemPrompt != "" {
+	if systemMessage := a.systemMessage(ctx, tools); systemMessage != "" {
 		options = append(options, func(opts *interfaces.GenerateOptions) {
-			opts.SystemMessage = a.systemPrompt
+			opts.SystemMessage = systemMessage
 		})
 	}
 
@@ -233,7 +295,11 @@ func (a *Agent) runStreamingGeneration(
 
 	// Add memory if available
 	if a.memory != nil {
-		options = append(options, interfaces.WithMemory(a.memory))
+		llmMemory := a.llmMemory(ctx, tools)
+		if run != nil {
+			llmMemory = run.memory(llmMemory)
+		}
+		options = append(options, interfaces.WithMemory(llmMemory))
 	}
 
 	// Add stream config if available
@@ -241,12 +307,23 @@ func (a *Agent) runStreamingGeneration(
 		options = append(options, interfaces.WithStreamConfig(*a.streamConfig))
 	}
 
+	if a.thinkingPolicy != "" {
+		options = append(options, interfaces.WithThinkingPolicy(a.thinkingPolicy))
+	}
+
+	if a.validateToolArgs {
+		options = append(options, interfaces.WithToolArgumentValidation())
+	}
+	if a.repairToolArgs {
+		options = append(options, interfaces.WithToolArgumentRepair())
+	}
+
 	// Start LLM streaming
 	var llmEventChan <-chan interfaces.StreamEvent
 	var err error
 
 	if len(tools) > 0 {
-		llmEventChan, err = streamingLLM.GenerateWithToolsStream(ctx, input, tools, options...)
+		llmEventChan, err = streamingLLM.GenerateWithToolsStream(ctx, input, a.formatToolResults(tools), options...)
 	} else {
 		llmEventChan, err = streamingLLM.GenerateStream(ctx, input, options...)
 	}
@@ -255,39 +332,87 @@ func (a *Agent) runStreamingGeneration(
 		return fmt.Errorf("failed to start LLM streaming: %w", err)
 	}
 
-	// Track accumulated content for memory
+	// Track accumulated content and token usage for memory
 	var accumulatedContent strings.Builder
+	var streamUsage *interfaces.TokenUsage
 	var finalError error
 
 	// Forward LLM events as agent events
 	for llmEvent := range llmEventChan {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Usage events carry the cumulative usage of the stream, recorded with the response
+		// instead of being forwarded. Heartbeats only keep the LLM connection open.
+		if llmEvent.Type == interfaces.StreamEventUsage {
+			if llmEvent.Usage != nil {
+				streamUsage = llmEvent.Usage
+			}
+			continue
+		}
+		if llmEvent.Type == interfaces.StreamEventHeartbeat {
+			continue
+		}
+
+		// Only forward thinking to the user when the thinking policy makes it visible
+		if llmEvent.Type == interfaces.StreamEventThinking && a.thinkingPolicy != "" && a.thinkingPolicy != interfaces.ThinkingPolicyVisible {
+			if a.thinkingPolicy == interfaces.ThinkingPolicyInternal {
+				a.logger.Debug(ctx, "Thinking content withheld from stream", map[string]interface{}{
+					"agent":    a.name,
+					"thinking": llmEvent.Content,
+				})
+			}
+			continue
+		}
+
 		agentEvent := a.convertLLMEventToAgentEvent(llmEvent)
 
 		// Handle tool calls specially
 		if llmEvent.Type == interfaces.StreamEventToolUse && llmEvent.ToolCall != nil {
 			// Execute tool and send progress events
-			a.handleToolCallStreaming(ctx, llmEvent.ToolCall, tools, eventChan)
+			if !a.handleToolCallStreaming(ctx, llmEvent.ToolCall, tools, eventChan) {
+				break
+			}
 		}
 
 		// Accumulate content for memory
 		if llmEvent.Type == interfaces.StreamEventContentDelta {
 			accumulatedContent.WriteString(llmEvent.Content)
 		}
-
 		// Track errors
 		if llmEvent.Error != nil {
 			finalError = llmEvent.Error
 		}
 
 		// Send agent event
-		eventChan <- agentEvent
+		select {
+		case eventChan <- agentEvent:
+		case <-ctx.Done():
+		}
+	}
+
+	// A paused run is reported as an error and can be resumed with ContinueWithToolResult
+	if run != nil && run.pausedCall() != nil {
+		return a.savePausedRun(ctx, run, input, ungatedTools)
+	}
+
+	// A cancelled run ends with a cancellation event instead of a partial result
+	if ctx.Err() != nil {
+		sendCancelledEvent(ctx, eventChan)
+		return nil
 	}
 
 	// Add accumulated content to memory if available and no error occurred
 	if a.memory != nil && finalError == nil && accumulatedContent.Len() > 0 {
+		usage := &runUsage{}
+		if modelLLM, ok := a.llm.(interface{ GetModel() string }); ok && streamUsage != nil {
+			usage.add(modelLLM.GetModel(), *streamUsage)
+		}
 		if err := a.memory.AddMessage(ctx, interfaces.Message{
-			Role:    "assistant",
-			Content: accumulatedContent.String(),
+			Role:     "assistant",
+			Content:  accumulatedContent.String(),
+			Metadata: usage.metadata(),
 		}); err != nil {
 			// Warning: Failed to add assistant response to memory
 			fmt.Printf("Warning: Failed to add assistant response to memory: %v\n", err)
@@ -343,6 +468,17 @@ func (a *Agent) convertLLMEventToAgentEvent(llmEvent interfaces.StreamEvent) int
 			}
 		}
 
+	case interfaces.StreamEventToolCallDelta:
+		agentEvent.Type = interfaces.AgentEventToolCallDelta
+		if llmEvent.ToolCall != nil {
+			agentEvent.ToolCall = &interfaces.ToolCallEvent{
+				ID:        llmEvent.ToolCall.ID,
+				Name:      llmEvent.ToolCall.Name,
+				Arguments: llmEvent.ToolCall.Arguments,
+				Status:    "streaming",
+			}
+		}
+
 	case interfaces.StreamEventToolResult:
 		agentEvent.Type = interfaces.AgentEventToolResult
 		if llmEvent.ToolCall != nil {
@@ -371,13 +507,33 @@ func (a *Agent) convertLLMEventToAgentEvent(llmEvent interfaces.StreamEvent) int
 	return agentEvent
 }
 
-// handleToolCallStreaming executes a tool call and sends progress events
+// cancelledEventTimeout bounds how long the cancellation event waits for the consumer
+const cancelledEventTimeout = time.Second
+
+// sendCancelledEvent reports a cancelled run. The send waits for room in the channel up to
+// cancelledEventTimeout, then gives up, as the consumer may have stopped reading.
+func sendCancelledEvent(ctx context.Context, eventChan chan<- interfaces.AgentStreamEvent) {
+	timer := time.NewTimer(cancelledEventTimeout)
+	defer timer.Stop()
+
+	select {
+	case eventChan <- interfaces.AgentStreamEvent{
+		Type:      interfaces.AgentEventCancelled,
+		Error:     ctx.Err(),
+		Timestamp: time.Now(),
+	}:
+	case <-timer.C:
+	}
+}
+
+// handleToolCallStreaming executes a tool call and sends progress events.
+// It returns false if the run was cancelled during the tool execution, in which case no result is sent.
 func (a *Agent) handleToolCallStreaming(
 	ctx context.Context,
 	toolCall *interfaces.ToolCall,
 	tools []interfaces.Tool,
 	eventChan chan<- interfaces.AgentStreamEvent,
-) {
+) bool {
 	// Find the requested tool first to get its display name and internal flag
 	var selectedTool interfaces.Tool
 	for _, tool := range tools {
@@ -409,12 +565,18 @@ func (a *Agent) handleToolCallStreaming(
 		displayName = toolCall.Name
 	}
 
+	// Events carry the original name of tools renamed for the provider
+	toolName := toolCall.Name
+	if selectedTool != nil {
+		toolName = originalToolName(selectedTool)
+	}
+
 	// Send tool execution start event
 	eventChan <- interfaces.AgentStreamEvent{
 		Type: interfaces.AgentEventToolCall,
 		ToolCall: &interfaces.ToolCallEvent{
 			ID:          toolCall.ID,
-			Name:        toolCall.Name,
+			Name:        toolName,
 			DisplayName: displayName,
 			Internal:    internal,
 			Arguments:   toolCall.Arguments,
@@ -430,7 +592,7 @@ func (a *Agent) handleToolCallStreaming(
 			Type: interfaces.AgentEventToolResult,
 			ToolCall: &interfaces.ToolCallEvent{
 				ID:          toolCall.ID,
-				Name:        toolCall.Name,
+				Name:        toolName,
 				DisplayName: displayName,
 				Internal:    internal,
 				Arguments:   toolCall.Arguments,
@@ -440,18 +602,30 @@ func (a *Agent) handleToolCallStreaming(
 			Error:     fmt.Errorf("tool not found: %s", toolCall.Name),
 			Timestamp: time.Now(),
 		}
-		return
+		return true
+	}
+
+	if a.validateToolArgs {
+		selectedTool = interfaces.ValidatingTools([]interfaces.Tool{selectedTool})[0]
+	}
+	if a.repairToolArgs {
+		selectedTool = interfaces.RepairingTools([]interfaces.Tool{selectedTool})[0]
 	}
 
 	// Execute the tool
-	toolResult, err := selectedTool.Execute(ctx, toolCall.Arguments)
+	toolResult, err := interfaces.ExecuteTool(ctx, selectedTool, toolCall.Arguments)
+
+	// The result of a tool interrupted by cancellation is discarded
+	if ctx.Err() != nil {
+		return false
+	}
 
 	// Send tool result event
 	resultEvent := interfaces.AgentStreamEvent{
 		Type: interfaces.AgentEventToolResult,
 		ToolCall: &interfaces.ToolCallEvent{
 			ID:          toolCall.ID,
-			Name:        toolCall.Name,
+			Name:        toolName,
 			DisplayName: displayName,
 			Internal:    internal,
 			Arguments:   toolCall.Arguments,
@@ -469,6 +643,7 @@ func (a *Agent) handleToolCallStreaming(
 	}
 
 	eventChan <- resultEvent
+	return true
 }
 
 // runRemoteStream handles streaming for remote agents
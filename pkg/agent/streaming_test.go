@@ -0,0 +1,382 @@
+package agent
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// toolCallStreamingLLM streams a single tool call and then waits for the run to end
+type toolCallStreamingLLM struct {
+	mockLLM
+}
+
+func (m *toolCallStreamingLLM) SupportsStreaming() bool { return true }
+
+func (m *toolCallStreamingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return m.GenerateWithToolsStream(ctx, prompt, nil, options...)
+}
+
+func (m *toolCallStreamingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	events := make(chan interfaces.StreamEvent, 10)
+	go func() {
+		defer close(events)
+		events <- interfaces.StreamEvent{
+			Type:      interfaces.StreamEventToolUse,
+			ToolCall:  &interfaces.ToolCall{ID: "call_1", Name: "slow_tool", Arguments: "{}"},
+			Timestamp: time.Now(),
+		}
+		events <- interfaces.StreamEvent{
+			Type:      interfaces.StreamEventContentDelta,
+			Content:   "garbled",
+			Timestamp: time.Now(),
+		}
+		<-ctx.Done()
+	}()
+	return events, nil
+}
+
+func TestRunStreamCancelledDuringToolExecution(t *testing.T) {
+	toolStarted := make(chan struct{})
+	toolStopped := make(chan error, 1)
+	slowTool := &mockTool{
+		name: "slow_tool",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			close(toolStarted)
+			select {
+			case <-ctx.Done():
+				toolStopped <- ctx.Err()
+				return "partial", ctx.Err()
+			case <-time.After(10 * time.Second):
+				toolStopped <- nil
+				return "finished", nil
+			}
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(&toolCallStreamingLLM{}),
+		WithTools(slowTool),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := agent.RunStream(ctx, "run the slow tool")
+	if err != nil {
+		t.Fatalf("failed to start stream: %v", err)
+	}
+
+	select {
+	case <-toolStarted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool was not executed")
+	}
+	cancel()
+
+	select {
+	case err := <-toolStopped:
+		if err != context.Canceled {
+			t.Errorf("expected the tool to observe cancellation, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool did not stop after cancellation")
+	}
+
+	var received []interfaces.AgentStreamEvent
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				done = true
+				break
+			}
+			received = append(received, event)
+		case <-timeout:
+			t.Fatal("stream did not close after cancellation")
+		}
+	}
+
+	if len(received) == 0 || received[len(received)-1].Type != interfaces.AgentEventCancelled {
+		t.Fatalf("expected stream to end with a cancellation event, got %+v", received)
+	}
+	for _, event := range received {
+		switch {
+		case event.Type == interfaces.AgentEventToolResult:
+			t.Errorf("unexpected tool result after cancellation: %+v", event.ToolCall)
+		case event.Type == interfaces.AgentEventContent && event.Content == "garbled":
+			t.Error("unexpected content after cancellation")
+		case event.Type == interfaces.AgentEventComplete || event.Type == interfaces.AgentEventError:
+			t.Errorf("unexpected %s event after cancellation", event.Type)
+		}
+	}
+}
+
+func TestSendCancelledEventWaitsForSlowConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The consumer is busy when the run is cancelled and reads shortly after
+	events := make(chan interfaces.AgentStreamEvent)
+	received := make(chan interfaces.AgentStreamEvent, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		received <- <-events
+	}()
+
+	sendCancelledEvent(ctx, events)
+
+	select {
+	case event := <-received:
+		if event.Type != interfaces.AgentEventCancelled || event.Error != context.Canceled {
+			t.Errorf("expected a cancellation event, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the slow consumer to receive the cancellation event")
+	}
+}
+
+// toolThenAnswerStreamingLLM streams a tool call followed by an answer
+type toolThenAnswerStreamingLLM struct {
+	mockLLM
+}
+
+func (m *toolThenAnswerStreamingLLM) SupportsStreaming() bool { return true }
+
+func (m *toolThenAnswerStreamingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return m.GenerateWithToolsStream(ctx, prompt, nil, options...)
+}
+
+func (m *toolThenAnswerStreamingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	events := make(chan interfaces.StreamEvent, 10)
+	events <- interfaces.StreamEvent{
+		Type:     interfaces.StreamEventToolCallDelta,
+		ToolCall: &interfaces.ToolCall{ID: "call_1", Name: "get_weather", Arguments: "{"},
+	}
+	events <- interfaces.StreamEvent{
+		Type:     interfaces.StreamEventToolCallDelta,
+		ToolCall: &interfaces.ToolCall{ID: "call_1", Name: "get_weather", Arguments: "{}"},
+	}
+	events <- interfaces.StreamEvent{
+		Type:     interfaces.StreamEventToolUse,
+		ToolCall: &interfaces.ToolCall{ID: "call_1", Name: "get_weather", Arguments: "{}"},
+	}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "It is "}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "rainy"}
+	close(events)
+	return events, nil
+}
+
+// collectStream reads a stream until it is closed
+func collectStream(t *testing.T, events <-chan interfaces.AgentStreamEvent) []interfaces.AgentStreamEvent {
+	t.Helper()
+	var received []interfaces.AgentStreamEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return received
+			}
+			received = append(received, event)
+		case <-timeout:
+			t.Fatal("stream did not close")
+		}
+	}
+}
+
+func TestRunStreamInterleavesToolEvents(t *testing.T) {
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "rainy", nil
+		},
+	}
+	mem := memory.NewConversationBuffer()
+
+	agent, err := NewAgent(
+		WithLLM(&toolThenAnswerStreamingLLM{}),
+		WithTools(weather),
+		WithMemory(mem),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org"), "conv")
+	events, err := agent.RunStream(ctx, "What's the weather?")
+	if err != nil {
+		t.Fatalf("failed to start stream: %v", err)
+	}
+
+	var types []interfaces.AgentEventType
+	var content strings.Builder
+	for _, event := range collectStream(t, events) {
+		types = append(types, event.Type)
+		if event.Type == interfaces.AgentEventContent {
+			content.WriteString(event.Content)
+		}
+	}
+
+	toolCall, toolResult := slices.Index(types, interfaces.AgentEventToolCall), slices.Index(types, interfaces.AgentEventToolResult)
+	if toolCall < 0 || toolResult < toolCall {
+		t.Errorf("expected a tool call followed by its result, got %v", types)
+	}
+	if types[len(types)-1] != interfaces.AgentEventComplete {
+		t.Errorf("expected the stream to end with a completion event, got %v", types)
+	}
+	if content.String() != "It is rainy" {
+		t.Errorf("expected the streamed answer, got %q", content.String())
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	last := messages[len(messages)-1]
+	if last.Role != "assistant" || last.Content != "It is rainy" {
+		t.Errorf("expected the final answer to be recorded in memory, got %+v", last)
+	}
+}
+
+func TestRunStreamForwardsToolCallDeltas(t *testing.T) {
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "rainy", nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(&toolThenAnswerStreamingLLM{}),
+		WithTools(weather),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	events, err := agent.RunStream(context.Background(), "What's the weather?")
+	if err != nil {
+		t.Fatalf("failed to start stream: %v", err)
+	}
+
+	var arguments []string
+	toolCall := -1
+	for i, event := range collectStream(t, events) {
+		switch event.Type {
+		case interfaces.AgentEventToolCallDelta:
+			if toolCall >= 0 {
+				t.Errorf("expected the argument deltas before the tool call")
+			}
+			if event.ToolCall == nil || event.ToolCall.Name != "get_weather" || event.ToolCall.Status != "streaming" {
+				t.Fatalf("unexpected tool call delta: %+v", event.ToolCall)
+			}
+			arguments = append(arguments, event.ToolCall.Arguments)
+		case interfaces.AgentEventToolCall:
+			if toolCall < 0 {
+				toolCall = i
+			}
+		}
+	}
+
+	if !slices.Equal(arguments, []string{"{", "{}"}) {
+		t.Errorf("expected the partial arguments, got %v", arguments)
+	}
+	if toolCall < 0 {
+		t.Error("expected the completed tool call")
+	}
+}
+
+// keepaliveStreamingLLM streams a response between heartbeats, followed by its usage
+type keepaliveStreamingLLM struct {
+	mockLLM
+}
+
+func (m *keepaliveStreamingLLM) SupportsStreaming() bool { return true }
+
+func (m *keepaliveStreamingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	events := make(chan interfaces.StreamEvent, 5)
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventHeartbeat}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "Hello"}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventHeartbeat}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: " there"}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventUsage, Usage: &interfaces.TokenUsage{InputTokens: 10, OutputTokens: 2}}
+	close(events)
+	return events, nil
+}
+
+func (m *keepaliveStreamingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return m.GenerateStream(ctx, prompt, options...)
+}
+
+func TestRunStreamDropsHeartbeatAndUsageEvents(t *testing.T) {
+	agent, err := NewAgent(WithLLM(&keepaliveStreamingLLM{}))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	events, err := agent.RunStream(context.Background(), "Hi")
+	if err != nil {
+		t.Fatalf("failed to start stream: %v", err)
+	}
+
+	var types []interfaces.AgentEventType
+	for _, event := range collectStream(t, events) {
+		if event.Type == interfaces.AgentEventContent && event.Content == "" {
+			t.Errorf("unexpected empty content event: %+v", event)
+		}
+		types = append(types, event.Type)
+	}
+
+	expected := []interfaces.AgentEventType{interfaces.AgentEventContent, interfaces.AgentEventContent, interfaces.AgentEventComplete}
+	if !slices.Equal(types, expected) {
+		t.Errorf("expected only the content and the completion, got %v", types)
+	}
+}
+
+func TestRunStreamNonStreamingLLM(t *testing.T) {
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(
+		WithLLM(&toolLoopLLM{}),
+		WithMemory(mem),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org"), "conv")
+	events, err := agent.RunStream(ctx, "Hello")
+	if err != nil {
+		t.Fatalf("expected non-streaming LLMs to be supported, got %v", err)
+	}
+
+	received := collectStream(t, events)
+	if len(received) != 2 || received[0].Type != interfaces.AgentEventContent || received[1].Type != interfaces.AgentEventComplete {
+		t.Fatalf("expected a single content event followed by a completion event, got %+v", received)
+	}
+	if received[0].Content != "no tools" {
+		t.Errorf("expected the final response, got %q", received[0].Content)
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	if len(messages) != 2 || messages[1].Content != "no tools" {
+		t.Errorf("expected the exchange to be recorded in memory, got %+v", messages)
+	}
+}
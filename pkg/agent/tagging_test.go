@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestAutoTagging(t *testing.T) {
+	// The classifier tags each exchange by the keywords of the user message
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			if strings.Contains(prompt, "Classify the following exchange") {
+				var tags []string
+				if strings.Contains(prompt, "invoice") {
+					tags = append(tags, "billing")
+				}
+				if strings.Contains(prompt, "crash") {
+					tags = append(tags, "technical")
+				}
+				if len(tags) == 0 {
+					return "none", nil
+				}
+				return strings.Join(tags, ", "), nil
+			}
+			return "Let me look into it.", nil
+		},
+	}
+
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(WithLLM(llm), WithMemory(mem), WithAutoTagging([]string{"billing", "technical", "sales"}))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "test-conversation")
+
+	for _, turn := range []struct {
+		input    string
+		expected []string
+	}{
+		{"Hello there", nil},
+		{"My invoice is wrong", []string{"billing"}},
+		{"Also the app crashes on start", []string{"billing", "technical"}},
+		{"Thanks", []string{"billing", "technical"}},
+	} {
+		if _, err := agent.Run(ctx, turn.input); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if tags := memory.GetTags(ctx, mem); !reflect.DeepEqual(tags, turn.expected) {
+			t.Errorf("After %q expected tags %v, got %v", turn.input, turn.expected, tags)
+		}
+	}
+}
+
+func TestAutoTaggingRunStream(t *testing.T) {
+	llm := &answerStreamingLLM{mockLLM: mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			return "billing", nil
+		},
+	}}
+
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(WithLLM(llm), WithMemory(mem), WithAutoTagging([]string{"billing", "technical"}))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "test-conversation")
+
+	events, err := agent.RunStream(ctx, "My invoice is wrong")
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	collectStream(t, events)
+
+	if tags := memory.GetTags(ctx, mem); !reflect.DeepEqual(tags, []string{"billing"}) {
+		t.Errorf("Expected the billing tag once the stream is closed, got %v", tags)
+	}
+}
@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// thinkingLLM answers with a thinking block when the thinking policy asks for it
+type thinkingLLM struct{}
+
+func (m *thinkingLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	params := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(params)
+	}
+	if params.ThinkingPolicy.IncludesThinking() {
+		return interfaces.FormatThinking("The user greets me.", "Hello!"), nil
+	}
+	return "Hello!", nil
+}
+
+func (m *thinkingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *thinkingLLM) Name() string            { return "thinking-llm" }
+func (m *thinkingLLM) SupportsStreaming() bool { return false }
+
+// tracedLLM records the responses seen at the position of the tracing middleware
+type tracedLLM struct {
+	interfaces.LLM
+	traced []string
+}
+
+func (m *tracedLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	response, err := m.LLM.Generate(ctx, prompt, options...)
+	m.traced = append(m.traced, response)
+	return response, err
+}
+
+func TestThinkingPolicy(t *testing.T) {
+	withThinking := interfaces.FormatThinking("The user greets me.", "Hello!")
+
+	tests := []struct {
+		policy   interfaces.ThinkingPolicy
+		answer   string
+		traced   string
+		thinking string
+	}{
+		{policy: interfaces.ThinkingPolicyHidden, answer: "Hello!", traced: "Hello!"},
+		{policy: interfaces.ThinkingPolicyInternal, answer: "Hello!", traced: withThinking, thinking: "The user greets me."},
+		{policy: interfaces.ThinkingPolicyVisible, answer: withThinking, traced: withThinking, thinking: "The user greets me."},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			llm := &tracedLLM{LLM: &thinkingLLM{}}
+			agent, err := NewAgent(
+				WithLLM(llm),
+				WithRequirePlanApproval(false),
+				WithThinkingPolicy(tt.policy),
+			)
+			if err != nil {
+				t.Fatalf("failed to create agent: %v", err)
+			}
+
+			answer, err := agent.Run(context.Background(), "Hi")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if answer != tt.answer {
+				t.Errorf("expected answer %q, got %q", tt.answer, answer)
+			}
+
+			if len(llm.traced) != 1 || llm.traced[0] != tt.traced {
+				t.Fatalf("expected traced response %q, got %q", tt.traced, llm.traced)
+			}
+			if thinking, _ := interfaces.SplitThinking(llm.traced[0]); thinking != tt.thinking {
+				t.Errorf("expected traced thinking %q, got %q", tt.thinking, thinking)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestAutoGenerateTitle(t *testing.T) {
+	titleCalls := 0
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			if strings.Contains(prompt, "Generate a short, descriptive title") {
+				titleCalls++
+				return "Weather in Paris", nil
+			}
+			return "It is sunny.", nil
+		},
+	}
+
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(WithLLM(llm), WithMemory(mem), WithAutoGenerateTitle(true))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "test-conversation")
+
+	for i := 0; i < 2; i++ {
+		if _, err := agent.Run(ctx, "What's the weather in Paris?"); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+
+	title, ok := memory.GetTitle(ctx, mem)
+	if !ok || title != "Weather in Paris" {
+		t.Errorf("Expected title 'Weather in Paris' to be stored, got %q", title)
+	}
+	if titleCalls != 1 {
+		t.Errorf("Expected the title to be generated once, got %d calls", titleCalls)
+	}
+}
+
+// answerStreamingLLM streams a fixed answer and answers the title prompt with Generate
+type answerStreamingLLM struct {
+	mockLLM
+}
+
+func (m *answerStreamingLLM) SupportsStreaming() bool { return true }
+
+func (m *answerStreamingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	events := make(chan interfaces.StreamEvent, 1)
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "It is sunny."}
+	close(events)
+	return events, nil
+}
+
+func (m *answerStreamingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return m.GenerateStream(ctx, prompt, options...)
+}
+
+func TestAutoGenerateTitleStream(t *testing.T) {
+	llm := &answerStreamingLLM{mockLLM: mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			return "Weather in Paris", nil
+		},
+	}}
+
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(WithLLM(llm), WithMemory(mem), WithAutoGenerateTitle(true))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "test-conversation")
+
+	events, err := agent.RunStream(ctx, "What's the weather in Paris?")
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	collectStream(t, events)
+
+	title, ok := memory.GetTitle(ctx, mem)
+	if !ok || title != "Weather in Paris" {
+		t.Errorf("Expected title 'Weather in Paris' to be stored once the stream is closed, got %q", title)
+	}
+}
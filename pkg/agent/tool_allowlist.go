@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// SetToolAllowlist restricts the tools the agent may use in the conversation in context to the
+// named ones, from the next run on. The allowlist is stored in the conversation metadata, so the
+// memory of the agent must implement interfaces.ConversationMetadataStore.
+func (a *Agent) SetToolAllowlist(ctx context.Context, names ...string) error {
+	if a.memory == nil {
+		return fmt.Errorf("tool allowlists require a memory")
+	}
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	return memory.SetToolAllowlist(ctx, a.memory, names)
+}
+
+// GrantTools adds the named tools to the allowlist of the conversation in context. Without an
+// allowlist every tool is already allowed and nothing changes.
+func (a *Agent) GrantTools(ctx context.Context, names ...string) error {
+	if a.memory == nil {
+		return fmt.Errorf("tool allowlists require a memory")
+	}
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	allowed, ok, err := memory.GetToolAllowlist(ctx, a.memory)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	for _, name := range names {
+		if !containsString(allowed, name) {
+			allowed = append(allowed, name)
+		}
+	}
+
+	return memory.SetToolAllowlist(ctx, a.memory, allowed)
+}
+
+// RevokeTools removes the named tools from the allowlist of the conversation in context. Without
+// an allowlist, one is created with all the tools of the agent but the revoked ones.
+func (a *Agent) RevokeTools(ctx context.Context, names ...string) error {
+	if a.memory == nil {
+		return fmt.Errorf("tool allowlists require a memory")
+	}
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	allowed, ok, err := memory.GetToolAllowlist(ctx, a.memory)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		for _, tool := range a.allTools(ctx) {
+			allowed = append(allowed, tool.Name())
+		}
+	}
+
+	remaining := make([]string, 0, len(allowed))
+	for _, name := range allowed {
+		if !containsString(names, name) {
+			remaining = append(remaining, name)
+		}
+	}
+
+	return memory.SetToolAllowlist(ctx, a.memory, remaining)
+}
+
+// allowedTools returns the tools allowed in the conversation in context. It fails when the
+// allowlist cannot be read, rather than allowing every tool.
+func (a *Agent) allowedTools(ctx context.Context, tools []interfaces.Tool) ([]interfaces.Tool, error) {
+	if a.memory == nil {
+		return tools, nil
+	}
+
+	allowed, ok, err := memory.GetToolAllowlist(ctx, a.memory)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return tools, nil
+	}
+
+	filtered := make([]interfaces.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if containsString(allowed, tool.Name()) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered, nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// modifyingLLM calls the modify_account tool whenever it is offered and records the offered tools
+type modifyingLLM struct {
+	mockLLM
+	offered [][]string
+}
+
+func (m *modifyingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name())
+	}
+	m.offered = append(m.offered, names)
+
+	for _, tool := range tools {
+		if tool.Name() == "modify_account" {
+			return tool.Execute(ctx, `{}`)
+		}
+	}
+	return "cannot modify the account", nil
+}
+
+func TestToolAllowlistRevokedMidConversation(t *testing.T) {
+	modifications := 0
+	lookup := &mockTool{name: "lookup_account", description: "Looks up the account"}
+	modify := &mockTool{name: "modify_account", description: "Modifies the account", runFunc: func(ctx context.Context, input string) (string, error) {
+		modifications++
+		return "account modified", nil
+	}}
+
+	llm := &modifyingLLM{}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithMemory(memory.NewConversationBuffer()),
+		WithTools(lookup, modify),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "support-1")
+
+	// Without an allowlist every tool is available
+	response, err := agent.Run(ctx, "Change my address")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if response != "account modified" || modifications != 1 {
+		t.Errorf("Expected the first run to modify the account, got %q after %d modifications", response, modifications)
+	}
+
+	if err := agent.RevokeTools(ctx, "modify_account"); err != nil {
+		t.Fatalf("RevokeTools failed: %v", err)
+	}
+
+	response, err = agent.Run(ctx, "Change my address again")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if response != "cannot modify the account" || modifications != 1 {
+		t.Errorf("Expected the revoked tool to be unavailable, got %q after %d modifications", response, modifications)
+	}
+
+	// Other conversations keep their tools
+	other := memory.WithConversationID(ctx, "support-2")
+	if _, err := agent.Run(other, "Change my address"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if modifications != 2 {
+		t.Errorf("Expected the other conversation to keep the tool, got %d modifications", modifications)
+	}
+
+	// Granting the tool back makes it available again
+	if err := agent.GrantTools(ctx, "modify_account"); err != nil {
+		t.Fatalf("GrantTools failed: %v", err)
+	}
+	if _, err := agent.Run(ctx, "Now change it"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"lookup_account", "modify_account"},
+		{"lookup_account"},
+		{"lookup_account", "modify_account"},
+		{"lookup_account", "modify_account"},
+	}
+	if !reflect.DeepEqual(llm.offered, expected) {
+		t.Errorf("Expected offered tools %v, got %v", expected, llm.offered)
+	}
+}
+
+func TestSetToolAllowlist(t *testing.T) {
+	llm := &modifyingLLM{}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithMemory(memory.NewConversationBuffer()),
+		WithTools(&mockTool{name: "lookup_account"}, &mockTool{name: "modify_account"}),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "support-1")
+
+	// Account modification is only enabled after identity verification
+	if err := agent.SetToolAllowlist(ctx, "lookup_account"); err != nil {
+		t.Fatalf("SetToolAllowlist failed: %v", err)
+	}
+	if _, err := agent.Run(ctx, "Verify me"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if err := agent.GrantTools(ctx, "modify_account"); err != nil {
+		t.Fatalf("GrantTools failed: %v", err)
+	}
+	if _, err := agent.Run(ctx, "Change my address"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	expected := [][]string{{"lookup_account"}, {"lookup_account", "modify_account"}}
+	if !reflect.DeepEqual(llm.offered, expected) {
+		t.Errorf("Expected offered tools %v, got %v", expected, llm.offered)
+	}
+
+	noMemory, err := NewAgent(WithLLM(llm))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if err := noMemory.RevokeTools(ctx, "modify_account"); err == nil {
+		t.Error("Expected an error without memory")
+	}
+}
+
+// unreadableMetadataMemory is a memory whose conversation metadata cannot be read
+type unreadableMetadataMemory struct {
+	*memory.ConversationBuffer
+}
+
+func (m *unreadableMetadataMemory) GetConversationMetadata(ctx context.Context) (map[string]interface{}, error) {
+	return nil, errors.New("metadata store unavailable")
+}
+
+func TestToolAllowlistUnreadable(t *testing.T) {
+	modifications := 0
+	modify := &mockTool{name: "modify_account", description: "Modifies the account", runFunc: func(ctx context.Context, input string) (string, error) {
+		modifications++
+		return "account modified", nil
+	}}
+
+	llm := &modifyingLLM{}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithMemory(&unreadableMetadataMemory{ConversationBuffer: memory.NewConversationBuffer()}),
+		WithTools(modify),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "support-1")
+
+	// The run fails rather than offering tools the allowlist may deny
+	if _, err := agent.Run(ctx, "Change my address"); err == nil {
+		t.Error("Expected the run to fail when the allowlist cannot be read")
+	}
+	if modifications != 0 || len(llm.offered) != 0 {
+		t.Errorf("Expected no tool to be offered, got %v", llm.offered)
+	}
+}
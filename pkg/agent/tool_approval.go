@@ -0,0 +1,301 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/google/uuid"
+)
+
+// ToolApprovalDecision is the outcome of a tool approval hook
+type ToolApprovalDecision int
+
+const (
+	// ToolApprovalApprove executes the tool call
+	ToolApprovalApprove ToolApprovalDecision = iota
+	// ToolApprovalDeny skips the tool call and reports the denial to the model
+	ToolApprovalDeny
+	// ToolApprovalPause pauses the run until the result is supplied with ContinueWithToolResult
+	ToolApprovalPause
+)
+
+// ToolApprovalFunc is consulted before every tool call made by the agent
+type ToolApprovalFunc func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error)
+
+// PausedRunError is returned by Run, or sent as an error event by RunStream, when a tool call
+// was paused by the approval hook. The run can be resumed with ContinueWithToolResult.
+type PausedRunError struct {
+	RunID    string
+	ToolCall interfaces.ToolCall
+}
+
+// Error implements the error interface
+func (e *PausedRunError) Error() string {
+	return fmt.Sprintf("run %s paused awaiting result of tool call %s (%s)", e.RunID, e.ToolCall.ID, e.ToolCall.Name)
+}
+
+// ErrToolCallPauseUnsupported is returned for a tool call paused by the approval hook in a run
+// that cannot be resumed, such as the steps of a plan-and-execute run
+var ErrToolCallPauseUnsupported = errors.New("tool call cannot be paused in this run, approve or deny it")
+
+// errToolCallPaused is returned to the LLM tool loop when a tool call is paused
+var errToolCallPaused = errors.New("tool call paused awaiting external result")
+
+// DefaultPausedRunTTL is the default time a paused run can be resumed, see WithPausedRunTTL
+const DefaultPausedRunTTL = 24 * time.Hour
+
+// pausedRun holds the state needed to resume a paused run
+type pausedRun struct {
+	input    string
+	tools    []interfaces.Tool
+	toolCall interfaces.ToolCall
+	pausedAt time.Time
+
+	// resultMessageID is the ID of the tool message the LLM stored in memory for the
+	// paused call, replaced by the external result when the run is resumed
+	resultMessageID string
+}
+
+// approvalRun tracks the tool calls of a single run gated by the approval hook
+type approvalRun struct {
+	id     string
+	cancel context.CancelFunc
+
+	// pauseUnsupported fails paused tool calls for runs that cannot be resumed
+	pauseUnsupported bool
+
+	mu              sync.Mutex
+	paused          *interfaces.ToolCall
+	resultMessageID string
+}
+
+// pause records the paused tool call and stops the run
+func (r *approvalRun) pause(toolCall interfaces.ToolCall) {
+	r.mu.Lock()
+	if r.paused == nil {
+		r.paused = &toolCall
+	}
+	r.mu.Unlock()
+	r.cancel()
+}
+
+// pausedCall returns the paused tool call, if any
+func (r *approvalRun) pausedCall() *interfaces.ToolCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.paused
+}
+
+// pausedResultMessageID returns the ID of the tool message the LLM stored for the paused call
+func (r *approvalRun) pausedResultMessageID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.resultMessageID
+}
+
+// memory wraps the memory passed to the LLM to record the ID of the tool message it stores for
+// the paused call, so that resuming the run replaces that message
+func (r *approvalRun) memory(mem interfaces.Memory) interfaces.Memory {
+	return &approvalMemory{Memory: mem, run: r}
+}
+
+// approvalMemory records the tool message stored for the paused call of a run
+type approvalMemory struct {
+	interfaces.Memory
+	run *approvalRun
+}
+
+// AddMessage stores a message, identifying the result of the paused call
+func (m *approvalMemory) AddMessage(ctx context.Context, message interfaces.Message) error {
+	if message.Role == "tool" && message.ToolCallID != "" {
+		m.run.mu.Lock()
+		if m.run.paused != nil && m.run.paused.ID == message.ToolCallID && m.run.resultMessageID == "" {
+			if message.ID == "" {
+				message.ID = uuid.New().String()
+			}
+			m.run.resultMessageID = message.ID
+		}
+		m.run.mu.Unlock()
+	}
+	return m.Memory.AddMessage(ctx, message)
+}
+
+// approvalTool gates a tool behind the agent's approval hook
+type approvalTool struct {
+	interfaces.ToolWrapper
+	hook ToolApprovalFunc
+	run  *approvalRun
+}
+
+// Run executes the tool with the given input
+func (t *approvalTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// Execute consults the approval hook before executing the tool
+func (t *approvalTool) Execute(ctx context.Context, args string) (string, error) {
+	// The call takes the ID the LLM stores it under, if its tool loop reports it
+	id, ok := interfaces.ToolCallIDFromContext(ctx)
+	if !ok {
+		id = "call_" + uuid.New().String()
+	}
+	toolCall := interfaces.ToolCall{
+		ID:        id,
+		Name:      t.Name(),
+		Arguments: args,
+	}
+
+	decision, err := t.hook(ctx, toolCall)
+	if err != nil {
+		return "", fmt.Errorf("tool approval failed: %w", err)
+	}
+
+	switch decision {
+	case ToolApprovalApprove:
+		return interfaces.ExecuteTool(ctx, t.Tool, args)
+	case ToolApprovalDeny:
+		return fmt.Sprintf("Tool call to %s was denied by the user.", t.Name()), nil
+	case ToolApprovalPause:
+		if t.run.pauseUnsupported {
+			return "", ErrToolCallPauseUnsupported
+		}
+		t.run.pause(toolCall)
+		return "", errToolCallPaused
+	default:
+		return "", fmt.Errorf("unknown tool approval decision: %d", decision)
+	}
+}
+
+// startApprovalRun wraps the tools of a run with the approval hook.
+// An empty runID starts a new run.
+func (a *Agent) startApprovalRun(ctx context.Context, runID string, tools []interfaces.Tool) (context.Context, *approvalRun, []interfaces.Tool) {
+	if runID == "" {
+		runID = uuid.New().String()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	run := &approvalRun{id: runID, cancel: cancel}
+
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &approvalTool{ToolWrapper: interfaces.ToolWrapper{Tool: tool}, hook: a.toolApprovalHook, run: run}
+	}
+
+	return ctx, run, wrapped
+}
+
+// savePausedRun stores the state of a paused run and returns the error reported to the caller.
+// Paused runs that were not resumed within the paused run TTL are discarded.
+func (a *Agent) savePausedRun(ctx context.Context, run *approvalRun, input string, tools []interfaces.Tool) error {
+	paused := &pausedRun{
+		input:           input,
+		tools:           tools,
+		toolCall:        *run.pausedCall(),
+		pausedAt:        time.Now(),
+		resultMessageID: run.pausedResultMessageID(),
+	}
+
+	a.pausedRunsMu.Lock()
+	if a.pausedRuns == nil {
+		a.pausedRuns = make(map[string]*pausedRun)
+	}
+	for id, other := range a.pausedRuns {
+		if a.pausedRunExpired(other) {
+			delete(a.pausedRuns, id)
+		}
+	}
+	a.pausedRuns[run.id] = paused
+	a.pausedRunsMu.Unlock()
+
+	return &PausedRunError{RunID: run.id, ToolCall: paused.toolCall}
+}
+
+// pausedRunExpired reports whether a paused run can no longer be resumed
+func (a *Agent) pausedRunExpired(paused *pausedRun) bool {
+	ttl := a.pausedRunTTL
+	if ttl <= 0 {
+		ttl = DefaultPausedRunTTL
+	}
+	return time.Since(paused.pausedAt) > ttl
+}
+
+// CancelPausedRun discards a run paused by the tool approval hook, which can then no longer be
+// resumed
+func (a *Agent) CancelPausedRun(runID string) error {
+	a.pausedRunsMu.Lock()
+	defer a.pausedRunsMu.Unlock()
+
+	if _, ok := a.pausedRuns[runID]; !ok {
+		return fmt.Errorf("no paused run found: %s", runID)
+	}
+	delete(a.pausedRuns, runID)
+	return nil
+}
+
+// ContinueWithToolResult resumes a run paused by the tool approval hook.
+// The externally produced result is injected as the result of the paused tool call
+// and the run continues without executing the tool.
+func (a *Agent) ContinueWithToolResult(ctx context.Context, runID, toolCallID, result string) (string, error) {
+	a.pausedRunsMu.Lock()
+	paused, ok := a.pausedRuns[runID]
+	if ok && a.pausedRunExpired(paused) {
+		delete(a.pausedRuns, runID)
+		ok = false
+	}
+	if ok && paused.toolCall.ID == toolCallID {
+		delete(a.pausedRuns, runID)
+	}
+	a.pausedRunsMu.Unlock()
+
+	if !ok {
+		return "", fmt.Errorf("no paused run found: %s", runID)
+	}
+	if paused.toolCall.ID != toolCallID {
+		return "", fmt.Errorf("run %s is paused on tool call %s, not %s", runID, paused.toolCall.ID, toolCallID)
+	}
+
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+	ctx = a.withUtilityLLM(ctx)
+
+	input := paused.input
+	if a.memory != nil && paused.resultMessageID != "" {
+		// The paused call is already in memory, only its result is replaced
+		if err := a.memory.UpdateMessage(ctx, paused.resultMessageID, result); err != nil {
+			return "", fmt.Errorf("failed to update tool result in memory: %w", err)
+		}
+	} else if a.memory != nil {
+		if err := a.memory.AddMessage(ctx, interfaces.Message{
+			Role:      "assistant",
+			ToolCalls: []interfaces.ToolCall{paused.toolCall},
+		}); err != nil {
+			return "", fmt.Errorf("failed to add tool call to memory: %w", err)
+		}
+		if err := a.memory.AddMessage(ctx, interfaces.Message{
+			Role:       "tool",
+			Content:    result,
+			ToolCallID: toolCallID,
+			Metadata:   map[string]interface{}{"tool_name": paused.toolCall.Name},
+		}); err != nil {
+			return "", fmt.Errorf("failed to add tool result to memory: %w", err)
+		}
+	} else {
+		input += "\n\n" + formatHistoryIntoPrompt([]interfaces.Message{
+			{Role: "assistant", ToolCalls: []interfaces.ToolCall{paused.toolCall}},
+			{Role: "tool", Content: result, ToolCallID: toolCallID},
+		})
+	}
+
+	// Tools revoked while the run was paused are no longer available
+	tools, err := a.allowedTools(ctx, paused.tools)
+	if err != nil {
+		return "", err
+	}
+	return a.runWithoutExecutionPlanWithTools(ctx, runID, input, tools)
+}
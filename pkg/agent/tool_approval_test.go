@@ -0,0 +1,400 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// toolLoopLLM calls the first tool until a tool result appears in the prompt, then answers with it
+type toolLoopLLM struct {
+	prompts []string
+}
+
+func (m *toolLoopLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return m.GenerateWithTools(ctx, prompt, nil, options...)
+}
+
+func (m *toolLoopLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	m.prompts = append(m.prompts, prompt)
+
+	if idx := strings.LastIndex(prompt, "TOOL: "); idx >= 0 {
+		return "Weather is " + prompt[idx+len("TOOL: "):], nil
+	}
+	if len(tools) == 0 {
+		return "no tools", nil
+	}
+
+	result, err := tools[0].Execute(ctx, `{"city":"Paris"}`)
+	if err != nil {
+		return "", err
+	}
+	return "Weather is " + result, nil
+}
+
+func (m *toolLoopLLM) Name() string            { return "tool-loop-llm" }
+func (m *toolLoopLLM) SupportsStreaming() bool { return false }
+
+func TestContinueWithToolResult(t *testing.T) {
+	var executed bool
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			executed = true
+			return "rainy", nil
+		},
+	}
+
+	var approvals []interfaces.ToolCall
+	llm := &toolLoopLLM{}
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithMemory(mem),
+		WithTools(weather),
+		WithRequirePlanApproval(false),
+		WithToolApprovalHook(func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error) {
+			approvals = append(approvals, toolCall)
+			return ToolApprovalPause, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org")
+	ctx = memory.WithConversationID(ctx, "conv")
+
+	_, err = agent.Run(ctx, "What's the weather in Paris?")
+	var paused *PausedRunError
+	if !errors.As(err, &paused) {
+		t.Fatalf("expected run to pause, got %v", err)
+	}
+	if executed {
+		t.Fatal("paused tool should not be executed")
+	}
+	if len(approvals) != 1 || paused.ToolCall.ID != approvals[0].ID || paused.ToolCall.Name != "get_weather" {
+		t.Fatalf("unexpected paused tool call: %+v", paused.ToolCall)
+	}
+	if paused.ToolCall.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected arguments: %s", paused.ToolCall.Arguments)
+	}
+
+	if _, err := agent.ContinueWithToolResult(ctx, paused.RunID, "wrong-id", "sunny"); err == nil {
+		t.Error("expected error for mismatched tool call ID")
+	}
+
+	response, err := agent.ContinueWithToolResult(ctx, paused.RunID, paused.ToolCall.ID, "sunny")
+	if err != nil {
+		t.Fatalf("failed to continue run: %v", err)
+	}
+	if response != "Weather is sunny" {
+		t.Errorf("unexpected response: %q", response)
+	}
+	if executed {
+		t.Error("tool should not be executed when the result is supplied externally")
+	}
+
+	lastPrompt := llm.prompts[len(llm.prompts)-1]
+	if !strings.Contains(lastPrompt, "[Called tool get_weather with arguments: {\"city\":\"Paris\"}]") {
+		t.Errorf("expected continued prompt to contain the paused tool call, got %q", lastPrompt)
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	last := messages[len(messages)-1]
+	if last.Role != "assistant" || last.Content != "Weather is sunny" {
+		t.Errorf("expected final assistant message in memory, got %+v", last)
+	}
+
+	if _, err := agent.ContinueWithToolResult(ctx, paused.RunID, paused.ToolCall.ID, "sunny"); err == nil {
+		t.Error("expected error when continuing a run that is no longer paused")
+	}
+}
+
+// memoryToolLoopLLM behaves like toolLoopLLM but records tool calls in memory the way the providers do
+type memoryToolLoopLLM struct {
+	toolLoopLLM
+}
+
+func (m *memoryToolLoopLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	if strings.Contains(prompt, "TOOL: ") || len(tools) == 0 {
+		return m.toolLoopLLM.GenerateWithTools(ctx, prompt, tools, options...)
+	}
+	m.prompts = append(m.prompts, prompt)
+
+	params := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(params)
+	}
+
+	arguments := `{"city":"Paris"}`
+	result, err := tools[0].Execute(interfaces.WithToolCallID(ctx, "toolu_provider"), arguments)
+	if params.Memory != nil {
+		content := result
+		if err != nil {
+			content = fmt.Sprintf("Error: %v", err)
+		}
+		_ = params.Memory.AddMessage(ctx, interfaces.Message{
+			Role:      "assistant",
+			ToolCalls: []interfaces.ToolCall{{ID: "toolu_provider", Name: tools[0].Name(), Arguments: arguments}},
+		})
+		_ = params.Memory.AddMessage(ctx, interfaces.Message{
+			Role:       "tool",
+			Content:    content,
+			ToolCallID: "toolu_provider",
+			Metadata:   map[string]interface{}{"tool_name": tools[0].Name()},
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+	return "Weather is " + result, nil
+}
+
+func TestContinueWithToolResultReplacesStoredResult(t *testing.T) {
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "rainy", nil
+		},
+	}
+
+	llm := &memoryToolLoopLLM{}
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithMemory(mem),
+		WithTools(weather),
+		WithRequirePlanApproval(false),
+		WithToolApprovalHook(func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error) {
+			return ToolApprovalPause, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org"), "conv")
+
+	_, err = agent.Run(ctx, "What's the weather in Paris?")
+	var paused *PausedRunError
+	if !errors.As(err, &paused) {
+		t.Fatalf("expected run to pause, got %v", err)
+	}
+	if paused.ToolCall.ID != "toolu_provider" {
+		t.Fatalf("expected the paused call to keep the ID of the provider, got %q", paused.ToolCall.ID)
+	}
+
+	response, err := agent.ContinueWithToolResult(ctx, paused.RunID, paused.ToolCall.ID, "sunny")
+	if err != nil {
+		t.Fatalf("failed to continue run: %v", err)
+	}
+	if response != "Weather is sunny" {
+		t.Errorf("unexpected response: %q", response)
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	var calls, results int
+	for _, message := range messages {
+		for _, toolCall := range message.ToolCalls {
+			calls++
+			if toolCall.ID != "toolu_provider" {
+				t.Errorf("unexpected tool call in memory: %+v", toolCall)
+			}
+		}
+		if message.Role == "tool" {
+			results++
+			if message.Content != "sunny" || message.ToolCallID != "toolu_provider" {
+				t.Errorf("expected the stored result to be replaced, got %+v", message)
+			}
+		}
+	}
+	if calls != 1 || results != 1 {
+		t.Errorf("expected a single tool call and result in memory, got %d calls and %d results", calls, results)
+	}
+}
+
+func TestCancelPausedRun(t *testing.T) {
+	weather := &mockTool{name: "get_weather", runFunc: func(ctx context.Context, input string) (string, error) {
+		return "rainy", nil
+	}}
+	agent, err := NewAgent(
+		WithLLM(&toolLoopLLM{}),
+		WithTools(weather),
+		WithRequirePlanApproval(false),
+		WithPausedRunTTL(time.Minute),
+		WithToolApprovalHook(func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error) {
+			return ToolApprovalPause, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	ctx := context.Background()
+
+	var paused *PausedRunError
+	if _, err := agent.Run(ctx, "What's the weather in Paris?"); !errors.As(err, &paused) {
+		t.Fatalf("expected run to pause, got %v", err)
+	}
+	if err := agent.CancelPausedRun(paused.RunID); err != nil {
+		t.Fatalf("failed to cancel paused run: %v", err)
+	}
+	if err := agent.CancelPausedRun(paused.RunID); err == nil {
+		t.Error("expected error when cancelling a run that is no longer paused")
+	}
+	if _, err := agent.ContinueWithToolResult(ctx, paused.RunID, paused.ToolCall.ID, "sunny"); err == nil {
+		t.Error("expected error when continuing a cancelled run")
+	}
+
+	// Runs paused for longer than the TTL can't be resumed, and are discarded on the next pause
+	if _, err := agent.Run(ctx, "What's the weather in Paris?"); !errors.As(err, &paused) {
+		t.Fatalf("expected run to pause, got %v", err)
+	}
+	expired := paused.RunID
+	agent.pausedRunsMu.Lock()
+	agent.pausedRuns[expired].pausedAt = time.Now().Add(-2 * time.Minute)
+	agent.pausedRunsMu.Unlock()
+
+	if _, err := agent.Run(ctx, "What's the weather in Paris?"); !errors.As(err, &paused) {
+		t.Fatalf("expected run to pause, got %v", err)
+	}
+	agent.pausedRunsMu.Lock()
+	_, kept := agent.pausedRuns[expired]
+	count := len(agent.pausedRuns)
+	agent.pausedRunsMu.Unlock()
+	if kept || count != 1 {
+		t.Errorf("expected the expired run to be discarded, got %d paused runs", count)
+	}
+	if _, err := agent.ContinueWithToolResult(ctx, paused.RunID, paused.ToolCall.ID, "sunny"); err != nil {
+		t.Errorf("failed to continue run: %v", err)
+	}
+}
+
+func TestToolApprovalHookApproveAndDeny(t *testing.T) {
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "rainy", nil
+		},
+	}
+
+	tests := []struct {
+		decision ToolApprovalDecision
+		expected string
+	}{
+		{ToolApprovalApprove, "Weather is rainy"},
+		{ToolApprovalDeny, "Weather is Tool call to get_weather was denied by the user."},
+	}
+
+	for _, tt := range tests {
+		agent, err := NewAgent(
+			WithLLM(&toolLoopLLM{}),
+			WithTools(weather),
+			WithRequirePlanApproval(false),
+			WithToolApprovalHook(func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error) {
+				return tt.decision, nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create agent: %v", err)
+		}
+
+		response, err := agent.Run(context.Background(), "What's the weather?")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response != tt.expected {
+			t.Errorf("decision %d: expected %q, got %q", tt.decision, tt.expected, response)
+		}
+	}
+}
+
+func TestRunStreamToolApprovalHook(t *testing.T) {
+	var executed bool
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			executed = true
+			return "rainy", nil
+		},
+	}
+
+	t.Run("deny", func(t *testing.T) {
+		executed = false
+		agent, err := NewAgent(
+			WithLLM(&toolThenAnswerStreamingLLM{}),
+			WithTools(weather),
+			WithRequirePlanApproval(false),
+			WithToolApprovalHook(func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error) {
+				return ToolApprovalDeny, nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create agent: %v", err)
+		}
+
+		events, err := agent.RunStream(context.Background(), "What's the weather?")
+		if err != nil {
+			t.Fatalf("failed to start stream: %v", err)
+		}
+
+		var denied bool
+		for _, event := range collectStream(t, events) {
+			if event.Type == interfaces.AgentEventToolResult && strings.Contains(event.ToolCall.Result, "denied") {
+				denied = true
+			}
+		}
+		if executed {
+			t.Error("denied tool should not be executed")
+		}
+		if !denied {
+			t.Error("expected the denial to be reported as the tool result")
+		}
+	})
+
+	t.Run("pause", func(t *testing.T) {
+		executed = false
+		agent, err := NewAgent(
+			WithLLM(&toolThenAnswerStreamingLLM{}),
+			WithTools(weather),
+			WithRequirePlanApproval(false),
+			WithToolApprovalHook(func(ctx context.Context, toolCall interfaces.ToolCall) (ToolApprovalDecision, error) {
+				return ToolApprovalPause, nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create agent: %v", err)
+		}
+
+		events, err := agent.RunStream(context.Background(), "What's the weather?")
+		if err != nil {
+			t.Fatalf("failed to start stream: %v", err)
+		}
+
+		received := collectStream(t, events)
+		if executed {
+			t.Error("paused tool should not be executed")
+		}
+		last := received[len(received)-1]
+		var paused *PausedRunError
+		if last.Type != interfaces.AgentEventError || !errors.As(last.Error, &paused) {
+			t.Fatalf("expected the stream to end with a paused run error, got %+v", last)
+		}
+		if paused.ToolCall.Name != "get_weather" {
+			t.Errorf("unexpected paused tool call: %+v", paused.ToolCall)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
+)
+
+// WithRestrictedToolContext runs the tools of the agent with a restricted context, which keeps
+// the cancellation and deadline of the run but hides its values, such as the organization ID
+// or credentials of the tenant. Tools implementing interfaces.ContextScopedTool still see the
+// values they declare, and all tools see the tracing values of the run, so that their spans
+// and logs stay attached to it. This reduces what a buggy or malicious tool can leak.
+func WithRestrictedToolContext(enabled bool) Option {
+	return func(a *Agent) {
+		a.restrictToolContext = enabled
+	}
+}
+
+// RestrictContext returns a context with the cancellation and deadline of ctx, exposing only
+// the values of ctx stored under the given keys and its tracing values, see
+// tracing.WithTraceContext and logTraceIDKey
+func RestrictContext(ctx context.Context, keys ...interface{}) context.Context {
+	keys = append(keys[:len(keys):len(keys)], logTraceIDKey)
+	return tracing.WithTraceContext(&restrictedContext{parent: ctx, keys: keys}, ctx)
+}
+
+// logTraceIDKey is the key of the trace ID added to log entries by logging.Logger
+const logTraceIDKey = "trace_id"
+
+// restrictedContext forwards cancellation to its parent and filters its values
+type restrictedContext struct {
+	parent context.Context
+	keys   []interface{}
+}
+
+// Deadline returns the deadline of the parent context
+func (c *restrictedContext) Deadline() (time.Time, bool) {
+	return c.parent.Deadline()
+}
+
+// Done returns the done channel of the parent context
+func (c *restrictedContext) Done() <-chan struct{} {
+	return c.parent.Done()
+}
+
+// Err returns the error of the parent context
+func (c *restrictedContext) Err() error {
+	return c.parent.Err()
+}
+
+// Value returns the value of the parent context for the allowed keys and nil otherwise
+func (c *restrictedContext) Value(key interface{}) interface{} {
+	for _, allowed := range c.keys {
+		if allowed == key {
+			return c.parent.Value(key)
+		}
+	}
+	return nil
+}
+
+// scopedTool runs a tool with a restricted context
+type scopedTool struct {
+	interfaces.ToolWrapper
+	keys []interface{}
+}
+
+// Run executes the tool with the given input
+func (t *scopedTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Tool.Run(RestrictContext(ctx, t.keys...), input)
+}
+
+// Execute executes the tool with the given arguments, keeping the results of structured tools structured
+func (t *scopedTool) Execute(ctx context.Context, args string) (string, error) {
+	return interfaces.ExecuteTool(RestrictContext(ctx, t.keys...), t.Tool, args)
+}
+
+// restrictToolContexts wraps the tools to run with a restricted context, if configured
+func (a *Agent) restrictToolContexts(tools []interfaces.Tool) []interfaces.Tool {
+	if !a.restrictToolContext || len(tools) == 0 {
+		return tools
+	}
+
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		var keys []interface{}
+		if scoped, ok := tool.(interfaces.ContextScopedTool); ok {
+			keys = scoped.ContextKeys()
+		}
+		wrapped[i] = &scopedTool{ToolWrapper: interfaces.ToolWrapper{Tool: tool}, keys: keys}
+	}
+	return wrapped
+}
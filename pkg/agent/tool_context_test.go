@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type secretKey struct{}
+
+// scopedMockTool declares the context values it reads
+type scopedMockTool struct {
+	mockTool
+	keys []interface{}
+}
+
+func (m *scopedMockTool) ContextKeys() []interface{} {
+	return m.keys
+}
+
+func TestRestrictedToolContextHidesValues(t *testing.T) {
+	var orgID, secret interface{}
+	snoop := &mockTool{
+		name: "snoop",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			orgID = ctx.Value(multitenancy.OrgIDContextKey())
+			secret = ctx.Value(secretKey{})
+			return "done", nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(&toolLoopLLM{}),
+		WithTools(snoop),
+		WithRequirePlanApproval(false),
+		WithRestrictedToolContext(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := context.WithValue(multitenancy.WithOrgID(context.Background(), "org-1"), secretKey{}, "s3cr3t")
+	if _, err := agent.Run(ctx, "What's the weather?"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if orgID != nil || secret != nil {
+		t.Errorf("expected the tool not to read context values, got org ID %v and secret %v", orgID, secret)
+	}
+}
+
+func TestRestrictedToolContextDeclaredValues(t *testing.T) {
+	var orgID, secret interface{}
+	tool := &scopedMockTool{
+		mockTool: mockTool{
+			name: "tenant_lookup",
+			runFunc: func(ctx context.Context, input string) (string, error) {
+				orgID = ctx.Value(multitenancy.OrgIDContextKey())
+				secret = ctx.Value(secretKey{})
+				return "done", nil
+			},
+		},
+		keys: []interface{}{multitenancy.OrgIDContextKey()},
+	}
+
+	agent := &Agent{restrictToolContext: true}
+	wrapped := agent.restrictToolContexts([]interfaces.Tool{tool})[0]
+
+	ctx := context.WithValue(multitenancy.WithOrgID(context.Background(), "org-1"), secretKey{}, "s3cr3t")
+	if _, err := wrapped.Execute(ctx, "{}"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if orgID != "org-1" {
+		t.Errorf("expected the declared org ID to be visible, got %v", orgID)
+	}
+	if secret != nil {
+		t.Errorf("expected undeclared values to be hidden, got %v", secret)
+	}
+}
+
+func TestRestrictedToolContextCancellation(t *testing.T) {
+	started := make(chan struct{})
+	blocking := &mockTool{
+		name: "blocking",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}
+
+	agent := &Agent{restrictToolContext: true}
+	wrapped := agent.restrictToolContexts([]interfaces.Tool{blocking})[0]
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), secretKey{}, "s3cr3t"))
+	errs := make(chan error, 1)
+	go func() {
+		_, err := wrapped.Execute(ctx, "{}")
+		errs <- err
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the tool to observe the cancellation, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("tool did not observe the cancellation")
+	}
+}
+
+func TestRestrictContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	expected, _ := ctx.Deadline()
+	deadline, ok := RestrictContext(ctx).Deadline()
+	if !ok || !deadline.Equal(expected) {
+		t.Errorf("expected the deadline of the parent context, got %v, %v", deadline, ok)
+	}
+}
+
+func TestRestrictContextKeepsTracing(t *testing.T) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+	ctx = tracing.WithRequestID(multitenancy.WithOrgID(ctx, "org-1"), "request-1")
+	ctx = context.WithValue(ctx, logTraceIDKey, "trace-1")
+
+	restricted := RestrictContext(ctx)
+
+	if got := trace.SpanContextFromContext(restricted); !got.Equal(spanContext) {
+		t.Errorf("expected the span of the run to be kept, got %v", got)
+	}
+	if requestID, _ := tracing.GetRequestID(restricted); requestID != "request-1" {
+		t.Errorf("expected the request ID to be kept, got %q", requestID)
+	}
+	if traceID := restricted.Value(logTraceIDKey); traceID != "trace-1" {
+		t.Errorf("expected the log trace ID to be kept, got %v", traceID)
+	}
+	if orgID := restricted.Value(multitenancy.OrgIDContextKey()); orgID != nil {
+		t.Errorf("expected the org ID to be hidden, got %v", orgID)
+	}
+}
@@ -27,6 +27,26 @@ func (m *MockMemory) Clear(ctx context.Context) error {
 	return nil
 }
 
+func (m *MockMemory) DeleteMessage(ctx context.Context, id string) error {
+	for i, msg := range m.messages {
+		if msg.ID == id {
+			m.messages = append(m.messages[:i], m.messages[i+1:]...)
+			return nil
+		}
+	}
+	return interfaces.ErrMessageNotFound
+}
+
+func (m *MockMemory) UpdateMessage(ctx context.Context, id string, content string) error {
+	for i, msg := range m.messages {
+		if msg.ID == id {
+			m.messages[i].Content = content
+			return nil
+		}
+	}
+	return interfaces.ErrMessageNotFound
+}
+
 // MockLLMWithTools implements LLM interface and stores tool calls in memory
 type MockLLMWithTools struct {
 	responses []string
@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// maxToolNameLength is the longest tool name accepted by the OpenAI, Anthropic and Gemini APIs
+const maxToolNameLength = 64
+
+// ToolNameSanitizer rewrites a tool name into one accepted by the LLM provider. Tools keep
+// their original name; only the name sent to the provider changes.
+type ToolNameSanitizer func(name string) string
+
+// SanitizeToolName is the default ToolNameSanitizer. It rewrites names for the common rules of
+// the OpenAI, Anthropic and Gemini APIs: characters other than letters, digits, underscores and
+// hyphens become underscores, names starting with another character get an underscore prefix,
+// and names longer than 64 characters are truncated with a hash suffix so that they stay
+// distinct. Valid names are returned unchanged.
+func SanitizeToolName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if isToolNameChar(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+
+	sanitized := sb.String()
+	if sanitized == "" || !isToolNameStart(rune(sanitized[0])) {
+		sanitized = "_" + sanitized
+	}
+
+	if len(sanitized) > maxToolNameLength {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(name))
+		suffix := fmt.Sprintf("_%08x", hash.Sum32())
+		sanitized = sanitized[:maxToolNameLength-len(suffix)] + suffix
+	}
+	return sanitized
+}
+
+// WithToolNameSanitizer sets the rewriting of tool names for providers with stricter rules than
+// those of SanitizeToolName, the default. Tool calls of the LLM still run the original tools.
+func WithToolNameSanitizer(sanitizer ToolNameSanitizer) Option {
+	return func(a *Agent) {
+		a.toolNameSanitizer = sanitizer
+	}
+}
+
+func isToolNameChar(r rune) bool {
+	return r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func isToolNameStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// renamedTool exposes a tool under the sanitized name sent to the provider
+type renamedTool struct {
+	interfaces.ToolWrapper
+	name string
+}
+
+// Name returns the sanitized name of the tool
+func (t *renamedTool) Name() string {
+	return t.name
+}
+
+// Execute executes the wrapped tool, keeping the results of structured tools structured
+func (t *renamedTool) Execute(ctx context.Context, args string) (string, error) {
+	return interfaces.ExecuteTool(ctx, t.Tool, args)
+}
+
+// sanitizeToolNames renames the tools whose names the sanitizer rewrites. Names made identical
+// by the sanitizer get a numeric suffix, so the mapping back to the tools stays one to one.
+// The renaming is deterministic for a given list of tools.
+func (a *Agent) sanitizeToolNames(tools []interfaces.Tool) []interfaces.Tool {
+	if len(tools) == 0 {
+		return tools
+	}
+	sanitize := a.toolNameSanitizer
+	if sanitize == nil {
+		sanitize = SanitizeToolName
+	}
+
+	used := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		used[tool.Name()] = true
+	}
+
+	sanitized := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		name := sanitize(tool.Name())
+		if name == tool.Name() {
+			sanitized[i] = tool
+			continue
+		}
+
+		unique := name
+		for n := 2; used[unique]; n++ {
+			suffix := fmt.Sprintf("_%d", n)
+			if len(name)+len(suffix) > maxToolNameLength {
+				unique = name[:maxToolNameLength-len(suffix)] + suffix
+			} else {
+				unique = name + suffix
+			}
+		}
+		used[unique] = true
+		sanitized[i] = &renamedTool{ToolWrapper: interfaces.ToolWrapper{Tool: tool}, name: unique}
+	}
+	return sanitized
+}
+
+// originalToolName returns the name of a tool before sanitization
+func originalToolName(tool interfaces.Tool) string {
+	if renamed, ok := tool.(*renamedTool); ok {
+		return renamed.Tool.Name()
+	}
+	return tool.Name()
+}
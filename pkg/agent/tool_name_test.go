@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// toolNameLLM records the tool names it is given and calls the tool named callName
+type toolNameLLM struct {
+	callName  string
+	toolNames []string
+}
+
+func (m *toolNameLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return "no tools", nil
+}
+
+func (m *toolNameLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	for _, tool := range tools {
+		m.toolNames = append(m.toolNames, tool.Name())
+	}
+	for _, tool := range tools {
+		if tool.Name() == m.callName {
+			return tool.Execute(ctx, `{"query":"Ada"}`)
+		}
+	}
+	return "tool not found: " + m.callName, nil
+}
+
+func (m *toolNameLLM) Name() string            { return "tool-name-llm" }
+func (m *toolNameLLM) SupportsStreaming() bool { return false }
+
+func TestSanitizeToolName(t *testing.T) {
+	tests := map[string]string{
+		"get_weather":         "get_weather",
+		"crm.contacts.search": "crm_contacts_search",
+		"github/list-issues":  "github_list-issues",
+		"3d_render":           "_3d_render",
+		"":                    "_",
+	}
+	for name, expected := range tests {
+		if sanitized := SanitizeToolName(name); sanitized != expected {
+			t.Errorf("SanitizeToolName(%q) = %q, expected %q", name, sanitized, expected)
+		}
+	}
+
+	long := strings.Repeat("operation.", 10)
+	other := strings.Repeat("operation.", 9) + "different"
+	if len(SanitizeToolName(long)) != maxToolNameLength {
+		t.Errorf("Expected long names to be truncated to %d characters, got %q", maxToolNameLength, SanitizeToolName(long))
+	}
+	if SanitizeToolName(long) == SanitizeToolName(other) {
+		t.Errorf("Expected long names sharing a prefix to stay distinct, got %q", SanitizeToolName(long))
+	}
+}
+
+func TestToolNameSanitization(t *testing.T) {
+	var executed string
+	search := &mockTool{
+		name: "crm.contacts.search",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			executed = "crm.contacts.search"
+			return "Ada Lovelace", nil
+		},
+	}
+	collision := &mockTool{name: "crm_contacts_search"}
+
+	llm := &toolNameLLM{callName: "crm_contacts_search_2"}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithTools(collision, search),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "Find Ada")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The invalid name is sanitized without clashing with the tool already using it
+	if strings.Join(llm.toolNames, ",") != "crm_contacts_search,crm_contacts_search_2" {
+		t.Errorf("expected sanitized tool names, got %v", llm.toolNames)
+	}
+	if executed != "crm.contacts.search" || response != "Ada Lovelace" {
+		t.Errorf("expected the call to run the original tool, got executed %q and response %q", executed, response)
+	}
+}
+
+func TestWithToolNameSanitizer(t *testing.T) {
+	search := &mockTool{
+		name: "crm.contacts.search",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "Ada Lovelace", nil
+		},
+	}
+
+	llm := &toolNameLLM{callName: "crm-contacts-search"}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithTools(search),
+		WithRequirePlanApproval(false),
+		WithToolNameSanitizer(func(name string) string {
+			return strings.ReplaceAll(name, ".", "-")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "Find Ada")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != "Ada Lovelace" {
+		t.Errorf("expected the custom sanitized name to map back to the tool, got %q", response)
+	}
+}
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// systemMessageLLM records the system message of the last request
+type systemMessageLLM struct {
+	mockLLM
+	systemMessage string
+}
+
+func (m *systemMessageLLM) record(options []interfaces.GenerateOption) {
+	params := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(params)
+	}
+	m.systemMessage = params.SystemMessage
+}
+
+func (m *systemMessageLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	m.record(options)
+	return "done", nil
+}
+
+func (m *systemMessageLLM) SupportsStreaming() bool { return true }
+
+func (m *systemMessageLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return m.GenerateWithToolsStream(ctx, prompt, nil, options...)
+}
+
+func (m *systemMessageLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	m.record(options)
+	events := make(chan interfaces.StreamEvent, 1)
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "done", Timestamp: time.Now()}
+	close(events)
+	return events, nil
+}
+
+func TestToolDescriptionsInPrompt(t *testing.T) {
+	weather := &mockTool{name: "weather", description: "Gets the current weather"}
+	search := &mockTool{name: "search", description: "Searches the web"}
+
+	for _, enabled := range []bool{true, false} {
+		llm := &systemMessageLLM{}
+		agent, err := NewAgent(
+			WithLLM(llm),
+			WithTools(weather, search),
+			WithSystemPrompt("You are a helpful assistant."),
+			WithRequirePlanApproval(false),
+			WithToolDescriptionsInPrompt(enabled),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+
+		if _, err := agent.Run(context.Background(), "What's the weather?"); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		checkToolDescriptions(t, "Run", llm.systemMessage, enabled)
+
+		events, err := agent.RunStream(context.Background(), "What's the weather?")
+		if err != nil {
+			t.Fatalf("RunStream failed: %v", err)
+		}
+		for event := range events {
+			if event.Error != nil {
+				t.Fatalf("Unexpected stream error: %v", event.Error)
+			}
+		}
+		checkToolDescriptions(t, "RunStream", llm.systemMessage, enabled)
+	}
+}
+
+func checkToolDescriptions(t *testing.T, run, systemMessage string, enabled bool) {
+	t.Helper()
+
+	if !strings.HasPrefix(systemMessage, "You are a helpful assistant.") {
+		t.Errorf("%s: expected the system prompt to be kept, got %q", run, systemMessage)
+	}
+	for _, description := range []string{"- weather: Gets the current weather", "- search: Searches the web"} {
+		if strings.Contains(systemMessage, description) != enabled {
+			t.Errorf("%s: expected tool description %q in system message to be %v, got %q", run, description, enabled, systemMessage)
+		}
+	}
+}
+
+func TestToolDescriptionsInPromptWithoutTools(t *testing.T) {
+	agent, err := NewAgent(WithLLM(&mockLLM{}), WithSystemPrompt("You are a helpful assistant."), WithToolDescriptionsInPrompt(true))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	if got := agent.systemMessage(context.Background(), nil); got != "You are a helpful assistant." {
+		t.Errorf("Expected the system prompt alone without tools, got %q", got)
+	}
+}
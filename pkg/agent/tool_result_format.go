@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ToolResultFormatter rewrites the output of a tool before it is added to the conversation
+type ToolResultFormatter func(toolName, result string) string
+
+// RawToolResult passes tool outputs to the model unchanged. It is the default formatter.
+func RawToolResult(toolName, result string) string {
+	return result
+}
+
+// LabeledToolResult prefixes tool outputs with the name of the tool, which helps models
+// keep results apart when several tools are called in a row
+func LabeledToolResult(toolName, result string) string {
+	return fmt.Sprintf("Result of tool %s:\n%s", toolName, result)
+}
+
+// JSONToolResult wraps tool outputs in a JSON object with the name of the tool, e.g.
+// {"tool":"weather","result":"sunny"}. Outputs that are valid JSON are embedded as is.
+func JSONToolResult(toolName, result string) string {
+	var value interface{} = result
+	if json.Valid([]byte(result)) {
+		value = json.RawMessage(result)
+	}
+
+	formatted, err := json.Marshal(map[string]interface{}{
+		"tool":   toolName,
+		"result": value,
+	})
+	if err != nil {
+		return result
+	}
+	return string(formatted)
+}
+
+// WithToolResultFormatter sets the formatter applied to the outputs of tools before they
+// are added to the conversation, e.g. LabeledToolResult or JSONToolResult. The formatter gets
+// the name of the tool as registered, also when it is sanitized for the provider. Errors
+// returned by tools are not formatted.
+func WithToolResultFormatter(formatter ToolResultFormatter) Option {
+	return func(a *Agent) {
+		a.toolResultFormatter = formatter
+	}
+}
+
+// formattedTool applies the agent's tool result formatter to the outputs of a tool
+type formattedTool struct {
+	interfaces.ToolWrapper
+	format ToolResultFormatter
+}
+
+// Run executes the tool with the given input
+func (t *formattedTool) Run(ctx context.Context, input string) (string, error) {
+	result, err := t.Tool.Run(ctx, input)
+	if err != nil {
+		return result, err
+	}
+	return t.format(originalToolName(t.Tool), result), nil
+}
+
+// Execute executes the tool with the given arguments
+func (t *formattedTool) Execute(ctx context.Context, args string) (string, error) {
+	result, err := interfaces.ExecuteTool(ctx, t.Tool, args)
+	if err != nil {
+		return result, err
+	}
+	return t.format(originalToolName(t.Tool), result), nil
+}
+
+// formatToolResults wraps the tools with the tool result formatter, if one is configured
+func (a *Agent) formatToolResults(tools []interfaces.Tool) []interfaces.Tool {
+	if a.toolResultFormatter == nil || len(tools) == 0 {
+		return tools
+	}
+
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &formattedTool{ToolWrapper: interfaces.ToolWrapper{Tool: tool}, format: a.toolResultFormatter}
+	}
+	return wrapped
+}
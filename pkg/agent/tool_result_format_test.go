@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestWithToolResultFormatter(t *testing.T) {
+	weather := &mockTool{
+		name: "get_weather",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "rainy", nil
+		},
+	}
+
+	var formatted []string
+	llm := &toolLoopLLM{}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithTools(weather),
+		WithRequirePlanApproval(false),
+		WithToolResultFormatter(func(toolName, result string) string {
+			formatted = append(formatted, toolName)
+			return "<" + toolName + ">" + result + "</" + toolName + ">"
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "What's the weather in Paris?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response != "Weather is <get_weather>rainy</get_weather>" {
+		t.Errorf("expected the formatted tool result to be fed to the model, got %q", response)
+	}
+	if len(formatted) != 1 || formatted[0] != "get_weather" {
+		t.Errorf("expected the formatter to be called once for get_weather, got %v", formatted)
+	}
+}
+
+func TestToolResultFormatterUsesRegisteredName(t *testing.T) {
+	search := &mockTool{
+		name: "web.search",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "found", nil
+		},
+	}
+
+	var formatted []string
+	agent, err := NewAgent(
+		WithLLM(&toolLoopLLM{}),
+		WithTools(search),
+		WithRequirePlanApproval(false),
+		WithToolResultFormatter(func(toolName, result string) string {
+			formatted = append(formatted, toolName)
+			return result
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(context.Background(), "Search the web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(formatted) != 1 || formatted[0] != "web.search" {
+		t.Errorf("expected the formatter to get the registered tool name, got %v", formatted)
+	}
+}
+
+func TestToolResultFormatterSkipsErrors(t *testing.T) {
+	failing := &mockTool{
+		name: "failing",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "", errors.New("boom")
+		},
+	}
+
+	called := false
+	agent := &Agent{toolResultFormatter: func(toolName, result string) string {
+		called = true
+		return result
+	}}
+
+	tools := agent.formatToolResults([]interfaces.Tool{failing})
+	_, err := tools[0].Execute(context.Background(), "{}")
+	if err == nil || err.Error() != "boom" {
+		t.Errorf("expected the tool error to be returned unchanged, got %v", err)
+	}
+	if called {
+		t.Error("formatter should not be called for tool errors")
+	}
+}
+
+func TestDefaultToolResultFormatters(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter ToolResultFormatter
+		result    string
+		want      string
+	}{
+		{"raw", RawToolResult, "sunny", "sunny"},
+		{"labeled", LabeledToolResult, "sunny", "Result of tool weather:\nsunny"},
+		{"json text", JSONToolResult, "sunny", `{"result":"sunny","tool":"weather"}`},
+		{"json object", JSONToolResult, `{"temp":21}`, `{"result":{"temp":21},"tool":"weather"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter("weather", tt.result); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
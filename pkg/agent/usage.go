@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// runUsage accumulates the token usage of the LLM calls of a run, which is recorded on the
+// response stored in memory so that memory.ConversationCost can report it
+type runUsage struct {
+	mu       sync.Mutex
+	calls    int
+	model    string
+	usage    interfaces.TokenUsage
+	cost     float64
+	unpriced bool
+}
+
+// add records the usage of an LLM call
+func (u *runUsage) add(model string, usage interfaces.TokenUsage) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.calls++
+	u.model = model
+	u.usage.InputTokens += usage.InputTokens
+	u.usage.OutputTokens += usage.OutputTokens
+	if price, ok := llm.LookupModelPrice(model); ok {
+		u.cost += price.Cost(usage.InputTokens, usage.OutputTokens)
+	} else {
+		u.unpriced = true
+	}
+}
+
+// observe records the usage of a completion reported with llm.WithCompletionObserver
+func (u *runUsage) observe(ctx context.Context, summary llm.CompletionSummary) {
+	u.add(summary.Model, interfaces.TokenUsage{InputTokens: summary.PromptTokens, OutputTokens: summary.ResponseTokens})
+}
+
+// metadata returns the usage metadata of the stored response, or nil if no call reported usage.
+// The cost is left out when a model has no known price.
+func (u *runUsage) metadata() map[string]interface{} {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.calls == 0 {
+		return nil
+	}
+	metadata := map[string]interface{}{
+		memory.ModelMetadataKey:        u.model,
+		memory.InputTokensMetadataKey:  u.usage.InputTokens,
+		memory.OutputTokensMetadataKey: u.usage.OutputTokens,
+	}
+	if !u.unpriced {
+		metadata[memory.CostMetadataKey] = u.cost
+	}
+	return metadata
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// usageStreamingLLM streams a response followed by its usage
+type usageStreamingLLM struct {
+	modelLLM
+}
+
+func (m *usageStreamingLLM) SupportsStreaming() bool { return true }
+
+func (m *usageStreamingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	events := make(chan interfaces.StreamEvent, 2)
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "streamed"}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventUsage, Usage: &interfaces.TokenUsage{InputTokens: 300, OutputTokens: 40}}
+	close(events)
+	return events, nil
+}
+
+func (m *usageStreamingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return m.GenerateStream(ctx, prompt, options...)
+}
+
+func TestAgentRecordsUsage(t *testing.T) {
+	mem := memory.NewConversationBuffer()
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "acme"), "conversation")
+
+	// Providers report the usage of each call to the completion observer
+	model := &modelLLM{
+		mockLLM: mockLLM{generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			llm.LogCompletion(ctx, nil, llm.CompletionLogLevelNone, llm.CompletionSummary{Model: "gpt-4o", PromptTokens: 1000, ResponseTokens: 100})
+			return "ok", nil
+		}},
+		model: "gpt-4o",
+	}
+	agent, err := NewAgent(WithLLM(model), WithMemory(mem))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if _, err := agent.Run(ctx, "question"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	streaming, err := NewAgent(WithLLM(&usageStreamingLLM{modelLLM: modelLLM{model: "gpt-4o"}}), WithMemory(mem))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	events, err := streaming.RunStream(ctx, "another question")
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	for range events {
+	}
+
+	report, err := memory.ConversationCost(ctx, mem)
+	if err != nil {
+		t.Fatalf("ConversationCost failed: %v", err)
+	}
+	assistant := report.ByRole["assistant"]
+	if assistant.Messages != 2 || assistant.InputTokens != 1300 || assistant.OutputTokens != 140 {
+		t.Fatalf("Expected the usage of both responses, got %+v", assistant)
+	}
+	// 1300 input tokens at $2.50 and 140 output tokens at $10 per million
+	expected := (1300*2.50 + 140*10) / 1e6
+	if math.Abs(report.Total.CostUSD-expected) > 1e-9 {
+		t.Errorf("Expected cost %v, got %v", expected, report.Total.CostUSD)
+	}
+	if _, ok := report.ByModel["gpt-4o"]; !ok {
+		t.Errorf("Expected usage by model, got %+v", report.ByModel)
+	}
+}
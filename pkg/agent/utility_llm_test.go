@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestUtilityLLM(t *testing.T) {
+	var primaryPrompts, utilityPrompts []string
+	primary := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			primaryPrompts = append(primaryPrompts, prompt)
+			return "Your invoice has been corrected.", nil
+		},
+	}
+	utility := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			utilityPrompts = append(utilityPrompts, prompt)
+			switch {
+			case strings.Contains(prompt, "Generate a short, descriptive title"):
+				return "Invoice correction", nil
+			case strings.Contains(prompt, "Classify the following exchange"):
+				return "billing", nil
+			}
+			t.Errorf("Unexpected utility prompt: %q", prompt)
+			return "", nil
+		},
+	}
+
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(
+		WithLLM(primary),
+		WithUtilityLLM(utility),
+		WithMemory(mem),
+		WithAutoGenerateTitle(true),
+		WithAutoTagging([]string{"billing", "technical"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "test-conversation")
+	response, err := agent.Run(ctx, "My invoice is wrong")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// The answer comes from the primary LLM, the title and tags from the utility LLM
+	if response != "Your invoice has been corrected." || len(primaryPrompts) != 1 {
+		t.Errorf("Expected a single primary call for the answer, got %q after %d calls", response, len(primaryPrompts))
+	}
+	if len(utilityPrompts) != 2 {
+		t.Errorf("Expected the title and tagging calls on the utility LLM, got %d calls", len(utilityPrompts))
+	}
+	if title, _ := memory.GetTitle(ctx, mem); title != "Invoice correction" {
+		t.Errorf("Expected the title of the utility LLM, got %q", title)
+	}
+	if tags := memory.GetTags(ctx, mem); !reflect.DeepEqual(tags, []string{"billing"}) {
+		t.Errorf("Expected the tags of the utility LLM, got %v", tags)
+	}
+}
+
+func TestUtilityLLMSummarizesMemory(t *testing.T) {
+	var primaryPrompts, utilityPrompts []string
+	primary := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			primaryPrompts = append(primaryPrompts, prompt)
+			return "Your invoice has been corrected.", nil
+		},
+	}
+	summarizer := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			t.Errorf("Unexpected call to the LLM of the memory: %q", prompt)
+			return "", nil
+		},
+	}
+	utility := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			utilityPrompts = append(utilityPrompts, prompt)
+			return "The user asked about an invoice", nil
+		},
+	}
+
+	mem := memory.NewConversationSummary(summarizer, memory.WithMaxBufferSize(2))
+	agent, err := NewAgent(WithLLM(primary), WithUtilityLLM(utility), WithMemory(mem))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = memory.WithConversationID(ctx, "test-conversation")
+	if _, err := agent.Run(ctx, "My invoice is wrong"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(primaryPrompts) != 1 {
+		t.Errorf("Expected a single primary call for the answer, got %d calls", len(primaryPrompts))
+	}
+	if len(utilityPrompts) != 1 || !strings.Contains(utilityPrompts[0], "Summarize the following conversation") {
+		t.Errorf("Expected the summarization call on the utility LLM, got %v", utilityPrompts)
+	}
+}
+
+func TestUtilityLLMDefaultsToMainLLM(t *testing.T) {
+	llm := &mockLLM{}
+	agent, err := NewAgent(WithLLM(llm))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if agent.GetUtilityLLM() != llm {
+		t.Error("Expected the main LLM for internal calls without a utility LLM")
+	}
+}
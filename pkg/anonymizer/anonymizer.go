@@ -0,0 +1,212 @@
+package anonymizer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// Anonymizer redacts or hashes sensitive content before it is emitted to traces or logs
+type Anonymizer interface {
+	// Anonymize returns the value with sensitive content masked
+	Anonymize(ctx context.Context, value string) string
+}
+
+// Fields anonymizes the values of a field map. Strings, errors and fmt.Stringer values are
+// anonymized as text. Booleans, numbers and nil are copied as is. Other values, such as maps,
+// slices and structs, are anonymized in their JSON form, kept as json.RawMessage when the
+// anonymized form is still valid JSON. The input map is not modified.
+func Fields(ctx context.Context, a Anonymizer, fields map[string]interface{}) map[string]interface{} {
+	if a == nil || len(fields) == 0 {
+		return fields
+	}
+
+	anonymized := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		anonymized[k] = Value(ctx, a, v)
+	}
+	return anonymized
+}
+
+// Value anonymizes a single value as Fields does
+func Value(ctx context.Context, a Anonymizer, v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return a.Anonymize(ctx, value)
+	case error:
+		return a.Anonymize(ctx, value.Error())
+	case fmt.Stringer:
+		return a.Anonymize(ctx, value.String())
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, nil:
+		return v
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return a.Anonymize(ctx, fmt.Sprintf("%v", v))
+	}
+	masked := a.Anonymize(ctx, string(data))
+	if json.Valid([]byte(masked)) {
+		return json.RawMessage(masked)
+	}
+	return masked
+}
+
+// NoopAnonymizer leaves values unchanged
+type NoopAnonymizer struct{}
+
+// NewNoopAnonymizer creates an anonymizer that leaves values unchanged
+func NewNoopAnonymizer() *NoopAnonymizer {
+	return &NoopAnonymizer{}
+}
+
+// Anonymize implements Anonymizer
+func (n *NoopAnonymizer) Anonymize(ctx context.Context, value string) string {
+	return value
+}
+
+// DefaultPIIPatterns contains patterns for common personally identifiable information
+var DefaultPIIPatterns = map[string]string{
+	"email":       `\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`,
+	"phone":       `\b(\+\d{1,2}\s)?\(?\d{3}\)?[\s.-]?\d{3}[\s.-]?\d{4}\b`,
+	"ssn":         `\b\d{3}-\d{2}-\d{4}\b`,
+	"credit_card": `\b\d{4}[- ]?\d{4}[- ]?\d{4}[- ]?\d{4}\b`,
+	"ip_address":  `\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`,
+}
+
+// RegexRedactor masks every match of a set of regular expressions
+type RegexRedactor struct {
+	patterns    []*regexp.Regexp
+	replacement string
+	hash        bool
+}
+
+// RedactorOption represents an option for configuring a RegexRedactor
+type RedactorOption func(*RegexRedactor)
+
+// WithReplacement sets the text that replaces each match (default: "[REDACTED]")
+func WithReplacement(replacement string) RedactorOption {
+	return func(r *RegexRedactor) {
+		r.replacement = replacement
+	}
+}
+
+// WithHashing replaces each match with a stable hash instead of a fixed placeholder,
+// so that equal values can still be correlated without being revealed
+func WithHashing() RedactorOption {
+	return func(r *RegexRedactor) {
+		r.hash = true
+	}
+}
+
+// NewRegexRedactor creates a redactor masking matches of the given patterns
+func NewRegexRedactor(patterns []string, options ...RedactorOption) (*RegexRedactor, error) {
+	redactor := &RegexRedactor{
+		replacement: "[REDACTED]",
+	}
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		redactor.patterns = append(redactor.patterns, re)
+	}
+
+	for _, option := range options {
+		option(redactor)
+	}
+
+	return redactor, nil
+}
+
+// NewPIIRedactor creates a redactor masking DefaultPIIPatterns
+func NewPIIRedactor(options ...RedactorOption) *RegexRedactor {
+	patterns := make([]string, 0, len(DefaultPIIPatterns))
+	for _, pattern := range DefaultPIIPatterns {
+		patterns = append(patterns, pattern)
+	}
+
+	redactor, err := NewRegexRedactor(patterns, options...)
+	if err != nil {
+		// DefaultPIIPatterns are known to compile
+		panic(err)
+	}
+	return redactor
+}
+
+// Anonymize implements Anonymizer
+func (r *RegexRedactor) Anonymize(ctx context.Context, value string) string {
+	for _, pattern := range r.patterns {
+		if r.hash {
+			value = pattern.ReplaceAllStringFunc(value, hashValue)
+		} else {
+			value = pattern.ReplaceAllLiteralString(value, r.replacement)
+		}
+	}
+	return value
+}
+
+// hashValue returns a short stable hash of a matched value
+func hashValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "[HASH:" + hex.EncodeToString(sum[:])[:12] + "]"
+}
+
+// OrgPolicyAnonymizer selects an anonymizer per organization resolved from the context
+type OrgPolicyAnonymizer struct {
+	mu       sync.RWMutex
+	policies map[string]Anonymizer
+	fallback Anonymizer
+}
+
+// NewOrgPolicyAnonymizer creates an anonymizer that applies fallback to organizations without a policy
+func NewOrgPolicyAnonymizer(fallback Anonymizer) *OrgPolicyAnonymizer {
+	if fallback == nil {
+		fallback = NewNoopAnonymizer()
+	}
+	return &OrgPolicyAnonymizer{
+		policies: make(map[string]Anonymizer),
+		fallback: fallback,
+	}
+}
+
+// SetPolicy sets the anonymizer used for an organization
+func (o *OrgPolicyAnonymizer) SetPolicy(orgID string, anonymizer Anonymizer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.policies[orgID] = anonymizer
+}
+
+// RemovePolicy removes the policy of an organization
+func (o *OrgPolicyAnonymizer) RemovePolicy(orgID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.policies, orgID)
+}
+
+// Anonymize implements Anonymizer
+func (o *OrgPolicyAnonymizer) Anonymize(ctx context.Context, value string) string {
+	return o.resolve(ctx).Anonymize(ctx, value)
+}
+
+// resolve returns the anonymizer of the organization in context
+func (o *OrgPolicyAnonymizer) resolve(ctx context.Context) Anonymizer {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return o.fallback
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if anonymizer, ok := o.policies[orgID]; ok {
+		return anonymizer
+	}
+	return o.fallback
+}
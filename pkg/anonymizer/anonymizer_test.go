@@ -0,0 +1,93 @@
+package anonymizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestRegexRedactor(t *testing.T) {
+	redactor := NewPIIRedactor()
+
+	got := redactor.Anonymize(context.Background(), "Contact jane@example.com or 555-123-4567")
+	if got != "Contact [REDACTED] or [REDACTED]" {
+		t.Errorf("unexpected redaction: %q", got)
+	}
+
+	if _, err := NewRegexRedactor([]string{"("}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestRegexRedactorHashing(t *testing.T) {
+	redactor, err := NewRegexRedactor([]string{`secret-\d+`}, WithHashing())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := redactor.Anonymize(context.Background(), "token secret-42")
+	second := redactor.Anonymize(context.Background(), "again secret-42")
+	if strings.Contains(first, "secret-42") || !strings.HasPrefix(first, "token [HASH:") {
+		t.Errorf("expected value to be hashed, got %q", first)
+	}
+	if strings.TrimPrefix(first, "token ") != strings.TrimPrefix(second, "again ") {
+		t.Errorf("expected stable hashes, got %q and %q", first, second)
+	}
+}
+
+func TestOrgPolicyAnonymizer(t *testing.T) {
+	policy := NewOrgPolicyAnonymizer(nil)
+	policy.SetPolicy("strict-org", NewPIIRedactor())
+
+	input := "email jane@example.com"
+	strict := multitenancy.WithOrgID(context.Background(), "strict-org")
+	relaxed := multitenancy.WithOrgID(context.Background(), "relaxed-org")
+
+	if got := policy.Anonymize(strict, input); got != "email [REDACTED]" {
+		t.Errorf("expected strict org to be redacted, got %q", got)
+	}
+	if got := policy.Anonymize(relaxed, input); got != input {
+		t.Errorf("expected org without policy to be unchanged, got %q", got)
+	}
+	if got := policy.Anonymize(context.Background(), input); got != input {
+		t.Errorf("expected context without org to be unchanged, got %q", got)
+	}
+
+	fields := Fields(strict, policy, map[string]interface{}{
+		"user":  "jane@example.com",
+		"error": errors.New("failed for jane@example.com"),
+		"count": 3,
+	})
+	if fields["user"] != "[REDACTED]" || fields["error"] != "failed for [REDACTED]" || fields["count"] != 3 {
+		t.Errorf("unexpected anonymized fields: %v", fields)
+	}
+}
+
+func TestFieldsAnonymizesNestedValues(t *testing.T) {
+	redactor := NewPIIRedactor()
+
+	fields := Fields(context.Background(), redactor, map[string]interface{}{
+		"request": map[string]interface{}{"user": "jane@example.com", "attempts": 2},
+		"emails":  []string{"jane@example.com"},
+		"profile": struct {
+			Phone string `json:"phone"`
+		}{Phone: "555-123-4567"},
+	})
+
+	for key, value := range fields {
+		raw, ok := value.(json.RawMessage)
+		if !ok {
+			t.Fatalf("expected %s to be kept as JSON, got %T", key, value)
+		}
+		if strings.Contains(string(raw), "jane@example.com") || strings.Contains(string(raw), "555-123-4567") {
+			t.Errorf("expected %s to be anonymized, got %s", key, raw)
+		}
+	}
+	if string(fields["request"].(json.RawMessage)) != `{"attempts":2,"user":"[REDACTED]"}` {
+		t.Errorf("unexpected anonymized map: %s", fields["request"])
+	}
+}
@@ -0,0 +1,280 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+const (
+	// DefaultCohereModel is the embedding model used when none is given
+	DefaultCohereModel = "embed-english-v3.0"
+
+	// DefaultCohereBaseURL is the base URL of the Cohere API
+	DefaultCohereBaseURL = "https://api.cohere.com"
+
+	// cohereMaxBatchSize is the maximum number of texts of a Cohere embed request
+	cohereMaxBatchSize = 96
+)
+
+// CohereInputType tells Cohere embedding models what the embedded texts are used for
+type CohereInputType string
+
+const (
+	// CohereInputSearchDocument is for documents stored in a vector store
+	CohereInputSearchDocument CohereInputType = "search_document"
+
+	// CohereInputSearchQuery is for queries searching a vector store
+	CohereInputSearchQuery CohereInputType = "search_query"
+
+	// CohereInputClassification is for texts passed to a classifier
+	CohereInputClassification CohereInputType = "classification"
+
+	// CohereInputClustering is for texts grouped into clusters
+	CohereInputClustering CohereInputType = "clustering"
+)
+
+// CohereEmbedder implements embedding generation using the Cohere embed API
+type CohereEmbedder struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	inputType  CohereInputType
+	config     EmbeddingConfig
+	logger     logging.Logger
+}
+
+// CohereOption configures a CohereEmbedder
+type CohereOption func(*CohereEmbedder)
+
+// WithCohereDimensions requests embeddings of n dimensions. Only embed-v4.0 and later models
+// support it, with 256, 512, 1024 or 1536 dimensions.
+func WithCohereDimensions(n int) CohereOption {
+	return func(e *CohereEmbedder) {
+		e.config.Dimensions = n
+	}
+}
+
+// WithCohereInputType sets the input type of the embedded texts (default: search_document).
+// Use CohereInputSearchQuery for an embedder of search queries.
+func WithCohereInputType(inputType CohereInputType) CohereOption {
+	return func(e *CohereEmbedder) {
+		e.inputType = inputType
+	}
+}
+
+// WithCohereBaseURL sets the base URL of the Cohere API, e.g. for a proxy
+func WithCohereBaseURL(baseURL string) CohereOption {
+	return func(e *CohereEmbedder) {
+		e.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithCohereHTTPClient sets the HTTP client of the embedder
+func WithCohereHTTPClient(client *http.Client) CohereOption {
+	return func(e *CohereEmbedder) {
+		e.httpClient = client
+	}
+}
+
+// WithCohereLogger sets the logger of the embedder
+func WithCohereLogger(logger logging.Logger) CohereOption {
+	return func(e *CohereEmbedder) {
+		e.logger = logger
+	}
+}
+
+// NewCohereEmbedder creates a new CohereEmbedder instance with default configuration
+func NewCohereEmbedder(apiKey, model string, options ...CohereOption) *CohereEmbedder {
+	if model == "" {
+		model = DefaultCohereModel
+	}
+	return NewCohereEmbedderWithConfig(apiKey, DefaultEmbeddingConfig(model), options...)
+}
+
+// NewCohereEmbedderWithConfig creates a new CohereEmbedder with custom configuration
+func NewCohereEmbedderWithConfig(apiKey string, config EmbeddingConfig, options ...CohereOption) *CohereEmbedder {
+	if config.Model == "" {
+		config.Model = DefaultCohereModel
+	}
+
+	embedder := &CohereEmbedder{
+		apiKey:     apiKey,
+		baseURL:    DefaultCohereBaseURL,
+		httpClient: http.DefaultClient,
+		inputType:  CohereInputSearchDocument,
+		config:     config,
+		logger:     logging.New(),
+	}
+
+	for _, option := range options {
+		option(embedder)
+	}
+
+	return embedder
+}
+
+// Embed generates an embedding using the Cohere API with default configuration
+func (e *CohereEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return e.EmbedWithConfig(ctx, text, e.config)
+}
+
+// EmbedWithConfig generates an embedding using the Cohere API with custom configuration
+func (e *CohereEmbedder) EmbedWithConfig(ctx context.Context, text string, config EmbeddingConfig) ([]float32, error) {
+	embeddings, err := e.EmbedBatchWithConfig(ctx, []string{text}, config)
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch generates embeddings for multiple texts using default configuration
+func (e *CohereEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	return e.EmbedBatchWithConfig(ctx, texts, e.config)
+}
+
+// EmbedBatchWithConfig generates embeddings for multiple texts with custom configuration. Texts
+// are sent in batches within the per-request limit of Cohere.
+func (e *CohereEmbedder) EmbedBatchWithConfig(ctx context.Context, texts []string, config EmbeddingConfig) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+	if config.Dimensions > 0 && !cohereSupportsDimensions(config.Model) {
+		return nil, fmt.Errorf("model %s does not support custom dimensions", config.Model)
+	}
+
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += cohereMaxBatchSize {
+		end := min(start+cohereMaxBatchSize, len(texts))
+
+		batch, err := e.embed(ctx, texts[start:end], config)
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, batch...)
+	}
+
+	e.logger.Debug(ctx, "Generated Cohere embeddings", map[string]interface{}{
+		"model":   config.Model,
+		"texts":   len(texts),
+		"batches": (len(texts) + cohereMaxBatchSize - 1) / cohereMaxBatchSize,
+	})
+
+	return embeddings, nil
+}
+
+// cohereEmbedRequest is the body of a request to the Cohere embed API
+type cohereEmbedRequest struct {
+	Model           string   `json:"model"`
+	Texts           []string `json:"texts"`
+	InputType       string   `json:"input_type"`
+	EmbeddingTypes  []string `json:"embedding_types"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+	Truncate        string   `json:"truncate,omitempty"`
+}
+
+// cohereEmbedResponse is the body of a response of the Cohere embed API
+type cohereEmbedResponse struct {
+	Embeddings struct {
+		Float [][]float32 `json:"float"`
+	} `json:"embeddings"`
+	Message string `json:"message"`
+}
+
+// embed sends a single embed request for a batch of texts
+func (e *CohereEmbedder) embed(ctx context.Context, texts []string, config EmbeddingConfig) ([][]float32, error) {
+	body, err := json.Marshal(cohereEmbedRequest{
+		Model:           config.Model,
+		Texts:           texts,
+		InputType:       string(e.inputType),
+		EmbeddingTypes:  []string{"float"},
+		OutputDimension: config.Dimensions,
+		Truncate:        cohereTruncate(config.Truncation),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/v2/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embed request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send embed request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embed response: %w", err)
+	}
+
+	var result cohereEmbedResponse
+	if err := json.Unmarshal(respBody, &result); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode embed response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		message := result.Message
+		if message == "" {
+			message = string(respBody)
+		}
+		return nil, fmt.Errorf("cohere embed request failed with status %d: %s", resp.StatusCode, message)
+	}
+
+	if len(result.Embeddings.Float) != len(texts) {
+		return nil, errors.New("cohere returned a different number of embeddings than texts")
+	}
+	for _, embedding := range result.Embeddings.Float {
+		if err := checkDimensions(embedding, config); err != nil {
+			return nil, err
+		}
+	}
+
+	return result.Embeddings.Float, nil
+}
+
+// cohereSupportsDimensions reports whether a Cohere model accepts an output dimension
+func cohereSupportsDimensions(model string) bool {
+	return strings.HasPrefix(model, "embed-v4")
+}
+
+// cohereTruncate maps the truncation strategy to the truncate parameter of Cohere. Cohere does
+// not split inputs, so the split strategy truncates as well.
+func cohereTruncate(truncation string) string {
+	switch TruncationStrategy(truncation) {
+	case TruncationNone:
+		return "NONE"
+	case TruncationTruncate, TruncationSplit:
+		return "END"
+	default:
+		return ""
+	}
+}
+
+// CalculateSimilarity calculates the similarity between two embeddings, using the configured
+// metric when metric is empty
+func (e *CohereEmbedder) CalculateSimilarity(vec1, vec2 []float32, metric string) (float32, error) {
+	if metric == "" {
+		metric = e.config.SimilarityMetric
+	}
+
+	similarity, err := Similarity(vec1, vec2, metric)
+	return float32(similarity), err
+}
+
+// GetConfig returns the current configuration
+func (e *CohereEmbedder) GetConfig() EmbeddingConfig {
+	return e.config
+}
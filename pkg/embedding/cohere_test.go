@@ -0,0 +1,135 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newCohereServer returns a server answering Cohere embed requests with vectors of the given
+// length whose first value is the index of the text overall, recording the requests
+func newCohereServer(t *testing.T, length int, requests *[]cohereEmbedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/embed" || r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Unexpected request to %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var req cohereEmbedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		offset := 0
+		for _, previous := range *requests {
+			offset += len(previous.Texts)
+		}
+		*requests = append(*requests, req)
+
+		embeddings := make([][]float32, len(req.Texts))
+		for i := range embeddings {
+			embeddings[i] = make([]float32, length)
+			embeddings[i][0] = float32(offset + i)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "embed-1",
+			"embeddings": map[string]interface{}{"float": embeddings},
+			"texts":      req.Texts,
+		})
+	}))
+}
+
+func TestCohereEmbedder(t *testing.T) {
+	var requests []cohereEmbedRequest
+	server := newCohereServer(t, 1024, &requests)
+	defer server.Close()
+
+	var embedder Client = NewCohereEmbedder("test-key", "embed-english-v3.0", WithCohereBaseURL(server.URL))
+
+	vector, err := embedder.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(vector) != 1024 {
+		t.Errorf("Expected a vector of 1024 dimensions, got %d", len(vector))
+	}
+
+	request := requests[0]
+	if request.Model != "embed-english-v3.0" || request.InputType != "search_document" || request.Truncate != "END" {
+		t.Errorf("Unexpected request %+v", request)
+	}
+	if len(request.EmbeddingTypes) != 1 || request.EmbeddingTypes[0] != "float" || request.OutputDimension != 0 {
+		t.Errorf("Expected float embeddings of the default dimensions, got %+v", request)
+	}
+}
+
+func TestCohereEmbedderBatches(t *testing.T) {
+	var requests []cohereEmbedRequest
+	server := newCohereServer(t, 8, &requests)
+	defer server.Close()
+
+	embedder := NewCohereEmbedder("test-key", "", WithCohereBaseURL(server.URL), WithCohereInputType(CohereInputSearchQuery))
+
+	texts := make([]string, 200)
+	for i := range texts {
+		texts[i] = strings.Repeat("word ", i+1)
+	}
+	vectors, err := embedder.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+
+	// 200 texts take three requests of at most 96 texts, and keep their order
+	if len(requests) != 3 || len(requests[0].Texts) != 96 || len(requests[1].Texts) != 96 || len(requests[2].Texts) != 8 {
+		t.Fatalf("Expected batches of 96, 96 and 8 texts, got %d requests", len(requests))
+	}
+	if len(vectors) != 200 {
+		t.Fatalf("Expected 200 vectors, got %d", len(vectors))
+	}
+	for i, vector := range vectors {
+		if vector[0] != float32(i) {
+			t.Fatalf("Expected vector %d in order, got the vector of text %v", i, vector[0])
+		}
+	}
+	if requests[0].Model != DefaultCohereModel || requests[0].InputType != "search_query" {
+		t.Errorf("Expected the default model and the query input type, got %+v", requests[0])
+	}
+}
+
+func TestCohereEmbedderDimensions(t *testing.T) {
+	var requests []cohereEmbedRequest
+	server := newCohereServer(t, 256, &requests)
+	defer server.Close()
+
+	embedder := NewCohereEmbedder("test-key", "embed-v4.0", WithCohereBaseURL(server.URL), WithCohereDimensions(256))
+	vector, err := embedder.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if requests[0].OutputDimension != 256 || len(vector) != 256 {
+		t.Errorf("Expected 256 dimensions to be requested and returned, got %d and %d", requests[0].OutputDimension, len(vector))
+	}
+
+	// Older models have fixed dimensions
+	v3 := NewCohereEmbedder("test-key", "embed-english-v3.0", WithCohereBaseURL(server.URL), WithCohereDimensions(256))
+	if _, err := v3.Embed(context.Background(), "hello"); err == nil || len(requests) != 1 {
+		t.Errorf("Expected an error without a request for a model without custom dimensions, got %v", err)
+	}
+}
+
+func TestCohereEmbedderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message": "invalid api token"}`))
+	}))
+	defer server.Close()
+
+	embedder := NewCohereEmbedder("bad-key", "", WithCohereBaseURL(server.URL))
+	_, err := embedder.Embed(context.Background(), "hello")
+	if err == nil || !strings.Contains(err.Error(), "status 401: invalid api token") {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+}
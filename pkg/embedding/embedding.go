@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 )
@@ -23,11 +25,16 @@ type EmbeddingConfig struct {
 	EncodingFormat string
 
 	// Truncation controls how the input text is handled if it exceeds the model's token limit
-	// Options: "none" (error on overflow), "truncate" (truncate to limit)
+	// Options: "none" (error on overflow), "truncate" (truncate to limit), "split" (embed chunks
+	// within the limit and average them)
 	Truncation string
 
+	// MaxInputTokens is the token limit of a single input, used by Truncation
+	// 0 uses DefaultMaxInputTokens, the limit of the OpenAI embedding models
+	MaxInputTokens int
+
 	// SimilarityMetric specifies the similarity metric to use when comparing embeddings
-	// Options: "cosine" (default), "euclidean", "dot_product"
+	// Options: "cosine" (default), "euclidean", "dot" (or "dot_product")
 	SimilarityMetric string
 
 	// SimilarityThreshold specifies the minimum similarity score for search results
@@ -77,31 +84,54 @@ type OpenAIEmbedder struct {
 	client openai.Client
 	model  string
 	config EmbeddingConfig
+	logger logging.Logger
+
+	retryPolicy   *retry.Policy
+	retryExecutor *retry.Executor
 }
 
-// NewOpenAIEmbedder creates a new OpenAIEmbedder instance with default configuration
-func NewOpenAIEmbedder(apiKey, model string) *OpenAIEmbedder {
-	config := DefaultEmbeddingConfig(model)
+// Option configures an OpenAIEmbedder
+type Option func(*OpenAIEmbedder)
 
-	return &OpenAIEmbedder{
-		client: openai.NewClient(option.WithAPIKey(apiKey)),
-		model:  config.Model,
-		config: config,
+// WithDimensions requests embeddings reduced to n dimensions. Only models supporting reduced
+// dimensions accept it, such as text-embedding-3-small and text-embedding-3-large.
+func WithDimensions(n int) Option {
+	return func(e *OpenAIEmbedder) {
+		e.config.Dimensions = n
+	}
+}
+
+// WithLogger sets the logger of the embedder
+func WithLogger(logger logging.Logger) Option {
+	return func(e *OpenAIEmbedder) {
+		e.logger = logger
 	}
 }
 
+// NewOpenAIEmbedder creates a new OpenAIEmbedder instance with default configuration
+func NewOpenAIEmbedder(apiKey, model string, options ...Option) *OpenAIEmbedder {
+	return NewOpenAIEmbedderWithConfig(apiKey, DefaultEmbeddingConfig(model), options...)
+}
+
 // NewOpenAIEmbedderWithConfig creates a new OpenAIEmbedder with custom configuration
-func NewOpenAIEmbedderWithConfig(apiKey string, config EmbeddingConfig) *OpenAIEmbedder {
+func NewOpenAIEmbedderWithConfig(apiKey string, config EmbeddingConfig, options ...Option) *OpenAIEmbedder {
 	// Ensure we have a valid model
 	if config.Model == "" {
 		config.Model = "text-embedding-3-small" // Default model if not specified
 	}
 
-	return &OpenAIEmbedder{
+	embedder := &OpenAIEmbedder{
 		client: openai.NewClient(option.WithAPIKey(apiKey)),
 		model:  config.Model,
 		config: config,
+		logger: logging.New(),
+	}
+
+	for _, option := range options {
+		option(embedder)
 	}
+
+	return embedder
 }
 
 // Embed generates an embedding using OpenAI API with default configuration
@@ -111,8 +141,19 @@ func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, err
 
 // EmbedWithConfig generates an embedding using OpenAI API with custom configuration
 func (e *OpenAIEmbedder) EmbedWithConfig(ctx context.Context, text string, config EmbeddingConfig) ([]float32, error) {
+	// Over-long inputs split into chunks are embedded together and averaged
+	inputs := e.fitInputs(ctx, []string{text}, config)[0]
+	if len(inputs) > 1 {
+		config.Truncation = string(TruncationNone)
+		embeddings, err := e.EmbedBatchWithConfig(ctx, inputs, config)
+		if err != nil {
+			return nil, err
+		}
+		return combineEmbeddings(embeddings, inputs), nil
+	}
+
 	req := openai.EmbeddingNewParams{
-		Input: openai.EmbeddingNewParamsInputUnion{OfString: openai.String(text)},
+		Input: openai.EmbeddingNewParamsInputUnion{OfString: openai.String(inputs[0])},
 		Model: openai.EmbeddingModel(config.Model),
 	}
 
@@ -129,7 +170,7 @@ func (e *OpenAIEmbedder) EmbedWithConfig(ctx context.Context, text string, confi
 		req.User = openai.String(config.UserID)
 	}
 
-	resp, err := e.client.Embeddings.New(ctx, req)
+	resp, err := e.createEmbeddings(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -144,9 +185,21 @@ func (e *OpenAIEmbedder) EmbedWithConfig(ctx context.Context, text string, confi
 		embedding[i] = float32(v)
 	}
 
+	if err := checkDimensions(embedding, config); err != nil {
+		return nil, err
+	}
+
 	return embedding, nil
 }
 
+// checkDimensions returns an error if an embedding does not have the requested dimensions
+func checkDimensions(embedding []float32, config EmbeddingConfig) error {
+	if config.Dimensions > 0 && len(embedding) != config.Dimensions {
+		return fmt.Errorf("embedding has %d dimensions, requested %d", len(embedding), config.Dimensions)
+	}
+	return nil
+}
+
 // EmbedBatch generates embeddings for multiple texts using default configuration
 func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	return e.EmbedBatchWithConfig(ctx, texts, e.config)
@@ -158,8 +211,51 @@ func (e *OpenAIEmbedder) EmbedBatchWithConfig(ctx context.Context, texts []strin
 		return [][]float32{}, nil
 	}
 
+	// Fit over-long inputs to the token limit, keeping track of the chunks of each text
+	fitted := e.fitInputs(ctx, texts, config)
+	var inputs []string
+	for _, chunks := range fitted {
+		inputs = append(inputs, chunks...)
+	}
+
+	// Inputs are sent in requests within the per-request limit of OpenAI, each retried on its own
+	embeddings := make([][]float32, len(inputs))
+	for start := 0; start < len(inputs); start += openAIMaxBatchSize {
+		end := min(start+openAIMaxBatchSize, len(inputs))
+		if err := e.embedBatch(ctx, inputs[start:end], config, embeddings[start:end]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(inputs) == len(texts) {
+		return embeddings, nil
+	}
+
+	// Combine the embeddings of the chunks of each text
+	combined := make([][]float32, len(texts))
+	offset := 0
+	for i, chunks := range fitted {
+		if len(chunks) == 1 {
+			combined[i] = embeddings[offset]
+		} else {
+			for _, embedding := range embeddings[offset : offset+len(chunks)] {
+				if embedding == nil {
+					return nil, fmt.Errorf("missing embedding for a chunk of input %d", i)
+				}
+			}
+			combined[i] = combineEmbeddings(embeddings[offset:offset+len(chunks)], chunks)
+		}
+		offset += len(chunks)
+	}
+
+	return combined, nil
+}
+
+// embedBatch sends a single embeddings request for inputs, storing the embedding of each input
+// at its index in embeddings
+func (e *OpenAIEmbedder) embedBatch(ctx context.Context, inputs []string, config EmbeddingConfig, embeddings [][]float32) error {
 	req := openai.EmbeddingNewParams{
-		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: texts},
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs},
 		Model: openai.EmbeddingModel(config.Model),
 	}
 
@@ -176,95 +272,59 @@ func (e *OpenAIEmbedder) EmbedBatchWithConfig(ctx context.Context, texts []strin
 		req.User = openai.String(config.UserID)
 	}
 
-	resp, err := e.client.Embeddings.New(ctx, req)
+	resp, err := e.createEmbeddings(ctx, req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(resp.Data) == 0 {
-		return nil, errors.New("no embedding data returned from API")
+		return errors.New("no embedding data returned from API")
 	}
 
 	// Sort embeddings by index to ensure correct order
-	embeddings := make([][]float32, len(texts))
 	for _, data := range resp.Data {
 		if int(data.Index) >= len(embeddings) {
-			return nil, fmt.Errorf("invalid embedding index: %d", data.Index)
+			return fmt.Errorf("invalid embedding index: %d", data.Index)
 		}
 		// Convert float64 to float32
 		embedding := make([]float32, len(data.Embedding))
 		for i, v := range data.Embedding {
 			embedding[i] = float32(v)
 		}
+		if err := checkDimensions(embedding, config); err != nil {
+			return err
+		}
 		embeddings[data.Index] = embedding
 	}
 
-	return embeddings, nil
+	return nil
 }
 
-// CalculateSimilarity calculates the similarity between two embeddings
+// CalculateSimilarity calculates the similarity between two embeddings, using the configured
+// metric when metric is empty
 func (e *OpenAIEmbedder) CalculateSimilarity(vec1, vec2 []float32, metric string) (float32, error) {
-	if len(vec1) != len(vec2) {
-		return 0, errors.New("embedding vectors must have the same dimensions")
-	}
-
 	if metric == "" {
 		metric = e.config.SimilarityMetric
 	}
 
-	switch metric {
-	case "cosine":
-		return cosineSimilarity(vec1, vec2), nil
-	case "euclidean":
-		return euclideanDistance(vec1, vec2), nil
-	case "dot_product":
-		return dotProduct(vec1, vec2), nil
-	default:
-		return 0, fmt.Errorf("unsupported similarity metric: %s", metric)
-	}
-}
-
-// cosineSimilarity calculates the cosine similarity between two vectors
-func cosineSimilarity(vec1, vec2 []float32) float32 {
-	var dotProd, mag1, mag2 float32
-
-	for i := 0; i < len(vec1); i++ {
-		dotProd += vec1[i] * vec2[i]
-		mag1 += vec1[i] * vec1[i]
-		mag2 += vec2[i] * vec2[i]
-	}
-
-	mag1 = float32(float64(mag1) + 1e-9) // Avoid division by zero
-	mag2 = float32(float64(mag2) + 1e-9) // Avoid division by zero
-
-	return dotProd / (float32(float64(mag1) * float64(mag2)))
+	similarity, err := Similarity(vec1, vec2, metric)
+	return float32(similarity), err
 }
 
-// euclideanDistance calculates the euclidean distance between two vectors
-// Returns a similarity score (1 - normalized distance)
-func euclideanDistance(vec1, vec2 []float32) float32 {
-	var sum float32
-
-	for i := 0; i < len(vec1); i++ {
-		diff := vec1[i] - vec2[i]
-		sum += diff * diff
+// SimilarityBetween embeds both texts and compares them with the configured metric. It also
+// reports whether the similarity reaches the configured threshold.
+func (e *OpenAIEmbedder) SimilarityBetween(ctx context.Context, textA, textB string) (float64, bool, error) {
+	embeddings, err := e.EmbedBatch(ctx, []string{textA, textB})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to embed texts: %w", err)
 	}
 
-	// Convert distance to similarity (1 - normalized distance)
-	// Using a simple normalization approach
-	distance := float32(float64(sum) + 1e-9)
-	return 1.0 / (1.0 + distance)
-}
-
-// dotProduct calculates the dot product between two vectors
-func dotProduct(vec1, vec2 []float32) float32 {
-	var sum float32
-
-	for i := 0; i < len(vec1); i++ {
-		sum += vec1[i] * vec2[i]
+	similarity, err := Similarity(embeddings[0], embeddings[1], e.config.SimilarityMetric)
+	if err != nil {
+		return 0, false, err
 	}
 
-	return sum
+	return similarity, similarity >= float64(e.config.SimilarityThreshold), nil
 }
 
 // GetConfig returns the current configuration
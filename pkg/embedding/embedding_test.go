@@ -0,0 +1,234 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// newDimensionsServer returns a server answering embedding requests with vectors of the
+// given length, recording the dimensions requested
+func newDimensionsServer(t *testing.T, length int, requested *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input      interface{} `json:"input"`
+			Dimensions int         `json:"dimensions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		*requested = req.Dimensions
+
+		inputs := 1
+		if list, ok := req.Input.([]interface{}); ok {
+			inputs = len(list)
+		}
+		data := make([]map[string]interface{}, 0, inputs)
+		for i := 0; i < inputs; i++ {
+			data = append(data, map[string]interface{}{"object": "embedding", "index": i, "embedding": make([]float64, length)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "data": data, "model": "test"})
+	}))
+}
+
+func TestWithDimensions(t *testing.T) {
+	var requested int
+	server := newDimensionsServer(t, 256, &requested)
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder("test-key", "text-embedding-3-small", WithDimensions(256))
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	vector, err := embedder.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if requested != 256 {
+		t.Errorf("Expected 256 dimensions to be requested, got %d", requested)
+	}
+	if len(vector) != 256 {
+		t.Errorf("Expected a vector of 256 dimensions, got %d", len(vector))
+	}
+
+	vectors, err := embedder.EmbedBatch(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+	if requested != 256 {
+		t.Errorf("Expected 256 dimensions to be requested in batch, got %d", requested)
+	}
+	for i, vector := range vectors {
+		if len(vector) != 256 {
+			t.Errorf("Expected vector %d to have 256 dimensions, got %d", i, len(vector))
+		}
+	}
+}
+
+func TestWithDimensionsMismatch(t *testing.T) {
+	var requested int
+	server := newDimensionsServer(t, 1536, &requested)
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder("test-key", "text-embedding-3-small", WithDimensions(256))
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	if _, err := embedder.Embed(context.Background(), "hello"); err == nil {
+		t.Error("Expected an error for a vector of unexpected dimensions")
+	}
+	if _, err := embedder.EmbedBatch(context.Background(), []string{"a"}); err == nil {
+		t.Error("Expected an error for batch vectors of unexpected dimensions")
+	}
+}
+
+func TestDefaultDimensionsNotSent(t *testing.T) {
+	requested := -1
+	server := newDimensionsServer(t, 1536, &requested)
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder("test-key", "text-embedding-3-small")
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	vector, err := embedder.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if requested != 0 {
+		t.Errorf("Expected no dimensions to be requested, got %d", requested)
+	}
+	if len(vector) != 1536 {
+		t.Errorf("Expected the model default of 1536 dimensions, got %d", len(vector))
+	}
+}
+
+// newInputServer returns a server answering embedding requests with two-dimensional vectors,
+// [1, 0] for the first input and [0, 1] for the others, recording the inputs of each request
+func newInputServer(t *testing.T, requests *[][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input interface{} `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		var inputs []string
+		switch input := req.Input.(type) {
+		case string:
+			inputs = []string{input}
+		case []interface{}:
+			for _, item := range input {
+				inputs = append(inputs, item.(string))
+			}
+		}
+		*requests = append(*requests, inputs)
+
+		data := make([]map[string]interface{}, 0, len(inputs))
+		for i := range inputs {
+			vector := []float64{0, 1}
+			if i == 0 {
+				vector = []float64{1, 0}
+			}
+			data = append(data, map[string]interface{}{"object": "embedding", "index": i, "embedding": vector})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "data": data, "model": "test"})
+	}))
+}
+
+func TestWithInputTruncation(t *testing.T) {
+	var requests [][]string
+	server := newInputServer(t, &requests)
+	defer server.Close()
+
+	// A limit of 10 tokens allows 30 characters
+	embedder := NewOpenAIEmbedder("test-key", "text-embedding-3-small", WithInputTruncation(TruncationTruncate), WithMaxInputTokens(10))
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	long := strings.Repeat("é", 100)
+	vector, err := embedder.Embed(context.Background(), long)
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(vector) != 2 {
+		t.Errorf("Expected an embedding to be produced, got %v", vector)
+	}
+	if len(requests) != 1 || len(requests[0]) != 1 || requests[0][0] != strings.Repeat("é", 30) {
+		t.Fatalf("Expected the input to be truncated to 30 characters, got %v", requests)
+	}
+
+	// Inputs within the limit are sent unchanged
+	vectors, err := embedder.EmbedBatch(context.Background(), []string{"short", long})
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+	if len(vectors) != 2 {
+		t.Fatalf("Expected 2 embeddings, got %d", len(vectors))
+	}
+	if got := requests[1]; len(got) != 2 || got[0] != "short" || got[1] != strings.Repeat("é", 30) {
+		t.Errorf("Expected the long batch input to be truncated, got %v", got)
+	}
+}
+
+func TestWithInputTruncationSplit(t *testing.T) {
+	var requests [][]string
+	server := newInputServer(t, &requests)
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder("test-key", "text-embedding-3-small", WithInputTruncation(TruncationSplit), WithMaxInputTokens(10))
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	// 60 characters are split into two chunks of 30, embedded as [1, 0] and [0, 1]
+	vector, err := embedder.Embed(context.Background(), strings.Repeat("a", 60))
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(requests) != 1 || len(requests[0]) != 2 {
+		t.Fatalf("Expected one request with two chunks, got %v", requests)
+	}
+	for _, chunk := range requests[0] {
+		if len(chunk) != 30 {
+			t.Errorf("Expected chunks of 30 characters, got %d", len(chunk))
+		}
+	}
+	want := float32(1 / math.Sqrt2)
+	if len(vector) != 2 || math.Abs(float64(vector[0]-want)) > 1e-6 || math.Abs(float64(vector[1]-want)) > 1e-6 {
+		t.Errorf("Expected the normalized average of the chunk embeddings, got %v", vector)
+	}
+
+	// In a batch, the chunks of each text are combined back into one embedding per text
+	vectors, err := embedder.EmbedBatch(context.Background(), []string{strings.Repeat("a", 45), "short"})
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+	if got := requests[1]; len(got) != 3 || got[2] != "short" {
+		t.Fatalf("Expected the chunks of the first text followed by the second text, got %v", got)
+	}
+	if len(vectors) != 2 || vectors[1][0] != 0 || vectors[1][1] != 1 {
+		t.Errorf("Expected one embedding per text, got %v", vectors)
+	}
+}
+
+func TestWithInputTruncationNone(t *testing.T) {
+	var requests [][]string
+	server := newInputServer(t, &requests)
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder("test-key", "text-embedding-3-small", WithInputTruncation(TruncationNone), WithMaxInputTokens(10))
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	long := strings.Repeat("a", 100)
+	if _, err := embedder.Embed(context.Background(), long); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if requests[0][0] != long {
+		t.Errorf("Expected the input to be sent unchanged")
+	}
+}
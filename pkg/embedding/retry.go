@@ -0,0 +1,98 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+const (
+	// openAIMaxBatchSize is the maximum number of inputs of an OpenAI embeddings request
+	openAIMaxBatchSize = 2048
+
+	// rateLimitBackoffFactor is how many times the initial interval of the retry policy a rate
+	// limited request waits at least before its retry, unless the server asks for a delay
+	rateLimitBackoffFactor = 4
+)
+
+// WithRetry retries failed embedding requests with a policy tuned for embedding large corpora:
+// 5 attempts, starting at 2s and backing off up to 60s. Rate limited requests wait for the
+// delay of their Retry-After header, or 4 times the initial interval. The given options
+// override the defaults. Batches are sent in requests of at most 2048 texts, and each
+// request is retried on its own so that the embeddings of completed requests are kept.
+func WithRetry(opts ...retry.Option) Option {
+	return func(e *OpenAIEmbedder) {
+		defaults := []retry.Option{
+			retry.WithInitialInterval(2 * time.Second),
+			retry.WithBackoffCoefficient(2),
+			retry.WithMaximumInterval(60 * time.Second),
+			retry.WithMaxAttempts(5),
+		}
+		e.retryPolicy = retry.NewPolicy(append(defaults, opts...)...)
+		e.retryExecutor = retry.NewExecutor(e.retryPolicy)
+	}
+}
+
+// createEmbeddings sends an embeddings request, with the retry policy of the embedder if any
+func (e *OpenAIEmbedder) createEmbeddings(ctx context.Context, req openai.EmbeddingNewParams) (*openai.CreateEmbeddingResponse, error) {
+	if e.retryExecutor == nil {
+		return e.client.Embeddings.New(ctx, req)
+	}
+
+	var resp *openai.CreateEmbeddingResponse
+	attempts := 0
+	err := e.retryExecutor.Execute(ctx, func() error {
+		attempts++
+		var err error
+		// The executor retries instead of the SDK client
+		resp, err = e.client.Embeddings.New(ctx, req, option.WithMaxRetries(0))
+		if err != nil {
+			e.logger.Debug(ctx, "Embedding request failed", map[string]interface{}{
+				"model":   req.Model,
+				"attempt": attempts,
+				"error":   err.Error(),
+			})
+			return e.classifyError(ctx, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// classifyError marks errors that must not be retried as permanent and sets the delay of rate
+// limited requests
+func (e *OpenAIEmbedder) classifyError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return retry.Permanent(err)
+	}
+
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	switch {
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+		delay := time.Duration(rateLimitBackoffFactor) * e.retryPolicy.InitialInterval
+		if apiErr.Response != nil {
+			if retryAfter := retry.ParseRetryAfter(apiErr.Response.Header.Get("Retry-After")); retryAfter > 0 {
+				delay = retryAfter
+			}
+		}
+		return retry.After(err, delay)
+	case apiErr.StatusCode >= http.StatusInternalServerError,
+		apiErr.StatusCode == http.StatusRequestTimeout,
+		apiErr.StatusCode == http.StatusConflict:
+		return err
+	default:
+		return retry.Permanent(err)
+	}
+}
@@ -0,0 +1,107 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// newRateLimitedServer fails the first request of each listed batch, identified by its first
+// input, with the given status, and embeds every other request. It records the number of
+// requests per batch.
+func newRateLimitedServer(t *testing.T, status int, failing map[string]bool, requests map[string]int) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+			return
+		}
+
+		mu.Lock()
+		batch := req.Input[0]
+		requests[batch]++
+		fail := failing[batch] && requests[batch] == 1
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if fail {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error": {"message": "Rate limit reached", "type": "requests", "code": "rate_limit_exceeded"}}`))
+			return
+		}
+
+		data := make([]map[string]interface{}, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]interface{}{"object": "embedding", "index": i, "embedding": []float64{float64(i), 1}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "data": data, "model": "test"})
+	}))
+}
+
+func TestWithRetryRateLimitedBatch(t *testing.T) {
+	texts := make([]string, openAIMaxBatchSize+2)
+	for i := range texts {
+		texts[i] = "text"
+	}
+	texts[0] = "first batch"
+	texts[openAIMaxBatchSize] = "second batch"
+
+	requests := map[string]int{}
+	server := newRateLimitedServer(t, http.StatusTooManyRequests, map[string]bool{"second batch": true}, requests)
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder("test-key", "text-embedding-3-small",
+		WithRetry(retry.WithInitialInterval(time.Millisecond), retry.WithMaximumInterval(10*time.Millisecond)))
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	embeddings, err := embedder.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+	if len(embeddings) != len(texts) {
+		t.Fatalf("Expected %d embeddings, got %d", len(texts), len(embeddings))
+	}
+	for i, embedding := range embeddings {
+		if embedding == nil {
+			t.Fatalf("Expected an embedding for text %d", i)
+		}
+	}
+	if embeddings[openAIMaxBatchSize+1][0] != 1 {
+		t.Errorf("Expected the embeddings of the second batch in order, got %v", embeddings[openAIMaxBatchSize+1])
+	}
+
+	// Only the rate limited batch is sent again
+	if requests["first batch"] != 1 {
+		t.Errorf("Expected the first batch to be sent once, got %d requests", requests["first batch"])
+	}
+	if requests["second batch"] != 2 {
+		t.Errorf("Expected the rate limited batch to be retried once, got %d requests", requests["second batch"])
+	}
+}
+
+func TestWithRetryPermanentError(t *testing.T) {
+	requests := map[string]int{}
+	server := newRateLimitedServer(t, http.StatusBadRequest, map[string]bool{"hello": true}, requests)
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder("test-key", "text-embedding-3-small", WithRetry(retry.WithInitialInterval(time.Millisecond)))
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	if _, err := embedder.EmbedBatch(context.Background(), []string{"hello", "world"}); err == nil {
+		t.Fatal("Expected the bad request to fail")
+	}
+	if requests["hello"] != 1 {
+		t.Errorf("Expected a bad request not to be retried, got %d requests", requests["hello"])
+	}
+}
@@ -0,0 +1,75 @@
+package embedding
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Supported similarity metrics
+const (
+	MetricCosine    = "cosine"
+	MetricDot       = "dot"
+	MetricEuclidean = "euclidean"
+)
+
+// Similarity compares two embeddings using the given metric, "cosine" when empty.
+// Higher scores mean more similar vectors for every metric: cosine returns the cosine of
+// the angle between the vectors, dot their dot product, and euclidean 1 / (1 + distance).
+// "dot_product" is accepted as an alias of "dot".
+func Similarity(vec1, vec2 []float32, metric string) (float64, error) {
+	if len(vec1) != len(vec2) {
+		return 0, fmt.Errorf("embedding vectors must have the same dimensions: %d != %d", len(vec1), len(vec2))
+	}
+	if len(vec1) == 0 {
+		return 0, errors.New("embedding vectors must not be empty")
+	}
+
+	switch metric {
+	case "", MetricCosine:
+		return cosineSimilarity(vec1, vec2)
+	case MetricDot, "dot_product":
+		return dotProduct(vec1, vec2), nil
+	case MetricEuclidean:
+		return euclideanSimilarity(vec1, vec2), nil
+	default:
+		return 0, fmt.Errorf("unsupported similarity metric: %s", metric)
+	}
+}
+
+// cosineSimilarity calculates the cosine similarity between two vectors, which is undefined
+// when either of them is a zero vector
+func cosineSimilarity(vec1, vec2 []float32) (float64, error) {
+	var dotProd, mag1, mag2 float64
+	for i := range vec1 {
+		dotProd += float64(vec1[i]) * float64(vec2[i])
+		mag1 += float64(vec1[i]) * float64(vec1[i])
+		mag2 += float64(vec2[i]) * float64(vec2[i])
+	}
+
+	if mag1 == 0 || mag2 == 0 {
+		return 0, errors.New("cosine similarity is undefined for zero vectors")
+	}
+
+	return dotProd / (math.Sqrt(mag1) * math.Sqrt(mag2)), nil
+}
+
+// dotProduct calculates the dot product between two vectors
+func dotProduct(vec1, vec2 []float32) float64 {
+	var sum float64
+	for i := range vec1 {
+		sum += float64(vec1[i]) * float64(vec2[i])
+	}
+	return sum
+}
+
+// euclideanSimilarity converts the euclidean distance between two vectors to a similarity
+// score in (0, 1], 1 meaning identical vectors
+func euclideanSimilarity(vec1, vec2 []float32) float64 {
+	var sum float64
+	for i := range vec1 {
+		diff := float64(vec1[i]) - float64(vec2[i])
+		sum += diff * diff
+	}
+	return 1 / (1 + math.Sqrt(sum))
+}
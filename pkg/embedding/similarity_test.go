@@ -0,0 +1,112 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		metric   string
+		vec1     []float32
+		vec2     []float32
+		expected float64
+	}{
+		{"cosine", []float32{1, 0}, []float32{1, 0}, 1},
+		{"cosine", []float32{1, 0}, []float32{0, 2}, 0},
+		{"cosine", []float32{1, 1}, []float32{-2, -2}, -1},
+		{"", []float32{3, 4}, []float32{3, 4}, 1},
+		{"dot", []float32{1, 2, 3}, []float32{4, 5, 6}, 32},
+		{"dot_product", []float32{1, 2, 3}, []float32{4, 5, 6}, 32},
+		{"euclidean", []float32{1, 2}, []float32{1, 2}, 1},
+		{"euclidean", []float32{0, 0}, []float32{3, 4}, 1.0 / 6},
+		// Zero vectors are only undefined for cosine
+		{"dot", []float32{0, 0}, []float32{1, 1}, 0},
+		{"euclidean", []float32{0, 0}, []float32{0, 0}, 1},
+	}
+
+	for _, tt := range tests {
+		got, err := Similarity(tt.vec1, tt.vec2, tt.metric)
+		if err != nil {
+			t.Errorf("Similarity(%v, %v, %q) returned error: %v", tt.vec1, tt.vec2, tt.metric, err)
+			continue
+		}
+		if math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("Similarity(%v, %v, %q) = %v, expected %v", tt.vec1, tt.vec2, tt.metric, got, tt.expected)
+		}
+	}
+}
+
+func TestSimilarityErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		vec1   []float32
+		vec2   []float32
+		metric string
+	}{
+		{"dimension mismatch", []float32{1, 2}, []float32{1, 2, 3}, "cosine"},
+		{"dimension mismatch dot", []float32{1}, []float32{1, 2}, "dot"},
+		{"empty vectors", []float32{}, []float32{}, "dot"},
+		{"zero vector", []float32{0, 0}, []float32{1, 1}, "cosine"},
+		{"both zero vectors", []float32{0, 0}, []float32{0, 0}, "cosine"},
+		{"unknown metric", []float32{1}, []float32{1}, "manhattan"},
+	}
+
+	for _, tt := range tests {
+		if _, err := Similarity(tt.vec1, tt.vec2, tt.metric); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestSimilarityBetween(t *testing.T) {
+	vectors := map[string][]float64{
+		"cat":    {1, 0, 0},
+		"kitten": {0.9, 0.1, 0},
+		"car":    {0, 0, 1},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		data := make([]map[string]interface{}, 0, len(req.Input))
+		for i, text := range req.Input {
+			data = append(data, map[string]interface{}{"object": "embedding", "index": i, "embedding": vectors[text]})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "data": data, "model": "test"})
+	}))
+	defer server.Close()
+
+	config := DefaultEmbeddingConfig("")
+	config.SimilarityThreshold = 0.9
+	embedder := NewOpenAIEmbedderWithConfig("test-key", config)
+	embedder.client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	similarity, similar, err := embedder.SimilarityBetween(context.Background(), "cat", "kitten")
+	if err != nil {
+		t.Fatalf("SimilarityBetween failed: %v", err)
+	}
+	if similarity < 0.99 || !similar {
+		t.Errorf("Expected similar texts, got similarity %v (similar: %v)", similarity, similar)
+	}
+
+	similarity, similar, err = embedder.SimilarityBetween(context.Background(), "cat", "car")
+	if err != nil {
+		t.Fatalf("SimilarityBetween failed: %v", err)
+	}
+	if similarity != 0 || similar {
+		t.Errorf("Expected dissimilar texts, got similarity %v (similar: %v)", similarity, similar)
+	}
+}
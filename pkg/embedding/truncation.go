@@ -0,0 +1,135 @@
+package embedding
+
+import (
+	"context"
+	"math"
+	"unicode/utf8"
+)
+
+// TruncationStrategy controls how inputs over the model's token limit are handled
+type TruncationStrategy string
+
+const (
+	// TruncationNone sends inputs as they are, so the API rejects over-long inputs
+	TruncationNone TruncationStrategy = "none"
+
+	// TruncationTruncate cuts over-long inputs down to the token limit
+	TruncationTruncate TruncationStrategy = "truncate"
+
+	// TruncationSplit splits over-long inputs into chunks within the token limit, embeds each
+	// chunk and averages the chunk embeddings, weighted by their length
+	TruncationSplit TruncationStrategy = "split"
+)
+
+// DefaultMaxInputTokens is the input token limit of the OpenAI embedding models
+const DefaultMaxInputTokens = 8191
+
+// charsPerToken is the conservative number of characters per token used to estimate whether
+// an input fits the token limit. English text averages about four characters per token, code
+// and other languages fewer. Chinese, Japanese and Korean text and emoji take one token or more
+// per character, so the estimate does not hold for them.
+const charsPerToken = 3
+
+// WithInputTruncation sets how inputs over the model's token limit are handled, instead of
+// failing the whole request. Inputs are truncated or split with a warning. The limit is checked
+// with an estimate of three characters per token, and inputs are not re-split when the API
+// still rejects them, so text with more tokens than characters, such as Chinese, Japanese or
+// Korean text or emoji, needs a lower limit with WithMaxInputTokens, e.g. a third of the
+// model's limit.
+func WithInputTruncation(strategy TruncationStrategy) Option {
+	return func(e *OpenAIEmbedder) {
+		e.config.Truncation = string(strategy)
+	}
+}
+
+// WithMaxInputTokens sets the input token limit of the model, for models other than the
+// OpenAI embedding models or to leave room for text with more tokens than characters
+func WithMaxInputTokens(n int) Option {
+	return func(e *OpenAIEmbedder) {
+		e.config.MaxInputTokens = n
+	}
+}
+
+// maxInputChars returns the maximum number of characters of an input
+func maxInputChars(config EmbeddingConfig) int {
+	maxTokens := config.MaxInputTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxInputTokens
+	}
+	return maxTokens * charsPerToken
+}
+
+// fitInputs applies the truncation strategy to the texts, returning the inputs to embed for
+// each text: the text itself, its truncated prefix, or its chunks
+func (e *OpenAIEmbedder) fitInputs(ctx context.Context, texts []string, config EmbeddingConfig) [][]string {
+	limit := maxInputChars(config)
+	strategy := TruncationStrategy(config.Truncation)
+
+	inputs := make([][]string, len(texts))
+	for i, text := range texts {
+		length := utf8.RuneCountInString(text)
+		if length <= limit || (strategy != TruncationTruncate && strategy != TruncationSplit) {
+			inputs[i] = []string{text}
+			continue
+		}
+
+		chunks := splitRunes(text, limit)
+		if strategy == TruncationTruncate {
+			chunks = chunks[:1]
+		}
+		e.logger.Warn(ctx, "Embedding input exceeds the token limit", map[string]interface{}{
+			"index":      i,
+			"characters": length,
+			"max_chars":  limit,
+			"strategy":   string(strategy),
+			"chunks":     len(chunks),
+		})
+		inputs[i] = chunks
+	}
+	return inputs
+}
+
+// splitRunes splits text into chunks of at most size runes
+func splitRunes(text string, size int) []string {
+	var chunks []string
+	for len(text) > 0 {
+		end, count := 0, 0
+		for end < len(text) && count < size {
+			_, width := utf8.DecodeRuneInString(text[end:])
+			end += width
+			count++
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	return chunks
+}
+
+// combineEmbeddings averages the embeddings of the chunks of a text, weighted by the length
+// of each chunk, and normalizes the result to unit length
+func combineEmbeddings(embeddings [][]float32, chunks []string) []float32 {
+	combined := make([]float64, len(embeddings[0]))
+	for i, embedding := range embeddings {
+		weight := float64(utf8.RuneCountInString(chunks[i]))
+		for j, v := range embedding {
+			if j < len(combined) {
+				combined[j] += float64(v) * weight
+			}
+		}
+	}
+
+	var norm float64
+	for _, v := range combined {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	result := make([]float32, len(combined))
+	for i, v := range combined {
+		if norm > 0 {
+			v /= norm
+		}
+		result[i] = float32(v)
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package executionplan
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// AuditAction represents a plan lifecycle event recorded in the audit log
+type AuditAction string
+
+const (
+	// AuditPlanCreated is recorded when a plan is generated
+	AuditPlanCreated AuditAction = "plan_created"
+	// AuditPlanModified is recorded when a plan is modified
+	AuditPlanModified AuditAction = "plan_modified"
+	// AuditPlanApproved is recorded when a plan is approved
+	AuditPlanApproved AuditAction = "plan_approved"
+	// AuditPlanExecuted is recorded when an approved plan completes execution
+	AuditPlanExecuted AuditAction = "plan_executed"
+	// AuditPlanFailed is recorded when the execution of a plan fails
+	AuditPlanFailed AuditAction = "plan_failed"
+	// AuditPlanCancelled is recorded when a plan is cancelled
+	AuditPlanCancelled AuditAction = "plan_cancelled"
+)
+
+// AuditRecord describes who did what to an execution plan and when
+type AuditRecord struct {
+	// Timestamp is the time of the event
+	Timestamp time.Time `json:"timestamp"`
+	// Action is the lifecycle event
+	Action AuditAction `json:"action"`
+	// TaskID identifies the plan
+	TaskID string `json:"task_id"`
+	// Actor is the user or system that triggered the event, see WithActor
+	Actor string `json:"actor,omitempty"`
+	// OrgID is the organization the plan belongs to, if any
+	OrgID string `json:"org_id,omitempty"`
+	// Status is the plan status after the event
+	Status ExecutionPlanStatus `json:"status"`
+	// Plan is a snapshot of the plan after the event
+	Plan *ExecutionPlan `json:"plan,omitempty"`
+	// Diff lists the changes made by a modification
+	Diff []string `json:"diff,omitempty"`
+	// Error is the failure reason for failed executions
+	Error string `json:"error,omitempty"`
+}
+
+// AuditSink persists plan audit records
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+type actorKey struct{}
+
+// WithActor adds the identity of the user performing plan actions to the context
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// GetActor returns the actor from the context, if any
+func GetActor(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// NewAuditRecord creates an audit record for a plan, filling in the actor and organization from the context
+func NewAuditRecord(ctx context.Context, action AuditAction, plan *ExecutionPlan) AuditRecord {
+	record := AuditRecord{
+		Timestamp: time.Now(),
+		Action:    action,
+	}
+	if plan != nil {
+		snapshot := *plan
+		snapshot.Steps = append([]ExecutionStep(nil), plan.Steps...)
+		record.TaskID = plan.TaskID
+		record.Status = plan.Status
+		record.Plan = &snapshot
+	}
+	if actor, ok := GetActor(ctx); ok {
+		record.Actor = actor
+	}
+	if orgID, err := multitenancy.GetOrgID(ctx); err == nil {
+		record.OrgID = orgID
+	}
+	return record
+}
+
+// PlanDiff lists the differences between two versions of a plan
+func PlanDiff(before, after *ExecutionPlan) []string {
+	var diff []string
+	if before.Description != after.Description {
+		diff = append(diff, fmt.Sprintf("description: %q -> %q", before.Description, after.Description))
+	}
+
+	for i := 0; i < len(before.Steps) || i < len(after.Steps); i++ {
+		switch {
+		case i >= len(after.Steps):
+			diff = append(diff, fmt.Sprintf("- step %d: %s", i+1, formatStep(before.Steps[i])))
+		case i >= len(before.Steps):
+			diff = append(diff, fmt.Sprintf("+ step %d: %s", i+1, formatStep(after.Steps[i])))
+		default:
+			oldStep, newStep := formatStep(before.Steps[i]), formatStep(after.Steps[i])
+			if oldStep != newStep {
+				diff = append(diff, fmt.Sprintf("- step %d: %s", i+1, oldStep))
+				diff = append(diff, fmt.Sprintf("+ step %d: %s", i+1, newStep))
+			}
+		}
+	}
+
+	return diff
+}
+
+// formatStep renders a step deterministically for diffs
+func formatStep(step ExecutionStep) string {
+	data, err := json.Marshal(step)
+	if err != nil {
+		return fmt.Sprintf("%+v", step)
+	}
+	return string(data)
+}
+
+// FileAuditSink appends audit records as JSON lines to a file
+type FileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditSink opens (or creates) the audit log at path for appending
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &FileAuditSink{file: file}, nil
+}
+
+// Record implements AuditSink
+func (s *FileAuditSink) Record(ctx context.Context, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return s.file.Sync()
+}
+
+// Close closes the audit log
+func (s *FileAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// ReadAuditLog reads the records of a file audit log in the order they were written
+func ReadAuditLog(path string) ([]AuditRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse audit record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return records, nil
+}
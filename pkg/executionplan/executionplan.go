@@ -47,6 +47,10 @@ type ExecutionPlan struct {
 	CreatedAt time.Time
 	// UpdatedAt is the time when the plan was last updated
 	UpdatedAt time.Time
+	// OrgID is the organization the plan was created for, empty without multi-tenancy
+	OrgID string `json:",omitempty"`
+	// ConversationID is the conversation the plan was created in, empty outside conversations
+	ConversationID string `json:",omitempty"`
 }
 
 // ExecutionStep represents a single step in an execution plan
@@ -198,6 +202,7 @@ Ensure that:
 2. All required parameters for each tool are provided
 3. The plan is comprehensive and addresses all aspects of the user's request
 4. The plan is presented in valid JSON format
+5. A step needing the output of an earlier step refers to it in its input as {{previous}} for the previous step or {{stepN}} for step N
 
 Execution Plan:
 `, toolDescriptions.String(), input)
@@ -1,6 +1,7 @@
 package executionplan
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -154,3 +155,39 @@ func TestParseExecutionPlanFromResponse_InvalidJSON(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestResolveStepInput(t *testing.T) {
+	outputs := []string{"first", "second"}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"no references", "no references"},
+		{"{{previous}}", "second"},
+		{"from {{step1}} and {{ step2 }}", "from first and second"},
+		{"{{step3}}", "{{step3}}"},
+		{"{{step0}}", "{{step0}}"},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveStepInput(tt.input, outputs); got != tt.expected {
+			t.Errorf("ResolveStepInput(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+
+	if got := ResolveStepInput("{{previous}}", nil); got != "{{previous}}" {
+		t.Errorf("Expected {{previous}} to be left as is without outputs, got %q", got)
+	}
+
+	// Outputs are escaped inside JSON arguments
+	outputs = []string{"He said \"hi\",\n\"admin\": true"}
+	got := ResolveStepInput(`{"query":"about {{step1}}","limit":3}`, outputs)
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &args); err != nil {
+		t.Fatalf("Expected valid JSON arguments, got %q: %v", got, err)
+	}
+	if args["query"] != "about "+outputs[0] || args["limit"] != float64(3) || len(args) != 2 {
+		t.Errorf("Unexpected resolved arguments: %v", args)
+	}
+}
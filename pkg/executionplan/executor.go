@@ -2,7 +2,10 @@ package executionplan
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
@@ -31,36 +34,111 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *ExecutionPlan) (string
 		return "", fmt.Errorf("execution plan has not been approved by the user")
 	}
 
+	outputs, err := e.ExecuteSteps(ctx, plan)
+	if err != nil {
+		return "", err
+	}
+
+	// Format the results
+	results := make([]string, 0, len(outputs))
+	for i, output := range outputs {
+		results = append(results, fmt.Sprintf("Step %d (%s): %s", i+1, plan.Steps[i].Description, output))
+	}
+	return fmt.Sprintf("Execution plan completed successfully!\n\n%s", strings.Join(results, "\n\n")), nil
+}
+
+// ExecuteSteps executes the steps of a plan in sequence, without requiring approval, and
+// returns the output of each step. The outputs of earlier steps are fed forward into the
+// input of later ones, see ResolveStepInput.
+func (e *Executor) ExecuteSteps(ctx context.Context, plan *ExecutionPlan) ([]string, error) {
 	// Update status to executing
 	plan.Status = StatusExecuting
 
 	// Execute each step in the plan
-	results := make([]string, 0, len(plan.Steps))
+	outputs := make([]string, 0, len(plan.Steps))
 	for i, step := range plan.Steps {
 		// Get the tool
 		tool, ok := e.tools[step.ToolName]
 		if !ok {
 			plan.Status = StatusFailed
-			return "", fmt.Errorf("unknown tool: %s", step.ToolName)
+			return nil, fmt.Errorf("unknown tool: %s", step.ToolName)
 		}
 
-		fmt.Println("step.Input", step.Input)
 		// Execute the tool
-		result, err := tool.Execute(ctx, step.Input)
+		result, err := interfaces.ExecuteTool(ctx, tool, ResolveStepInput(step.Input, outputs))
 		if err != nil {
 			plan.Status = StatusFailed
-			return "", fmt.Errorf("failed to execute step %d: %w", i+1, err)
+			return nil, fmt.Errorf("failed to execute step %d: %w", i+1, err)
 		}
 
-		// Add the result to the list of results
-		results = append(results, fmt.Sprintf("Step %d (%s): %s", i+1, step.Description, result))
+		outputs = append(outputs, result)
 	}
 
 	// Update status to completed
 	plan.Status = StatusCompleted
 
-	// Format the results
-	return fmt.Sprintf("Execution plan completed successfully!\n\n%s", strings.Join(results, "\n\n")), nil
+	return outputs, nil
+}
+
+// stepReference matches references to the output of an earlier step in a step input
+var stepReference = regexp.MustCompile(`\{\{\s*(previous|step(\d+))\s*\}\}`)
+
+// ResolveStepInput replaces references to the outputs of earlier steps in a step input:
+// {{previous}} with the output of the previous step and {{stepN}} with the output of step N,
+// starting at 1. References to steps without output are left as is. When the input is JSON,
+// references are only replaced inside its string values, so outputs containing quotes or
+// newlines can't break the arguments or inject fields.
+func ResolveStepInput(input string, outputs []string) string {
+	if !stepReference.MatchString(input) {
+		return input
+	}
+
+	var args interface{}
+	if err := json.Unmarshal([]byte(input), &args); err == nil {
+		if resolved, err := json.Marshal(resolveJSONReferences(args, outputs)); err == nil {
+			return string(resolved)
+		}
+	}
+	return resolveReferences(input, outputs)
+}
+
+// resolveJSONReferences replaces the step references in the string values of parsed JSON
+func resolveJSONReferences(value interface{}, outputs []string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return resolveReferences(v, outputs)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = resolveJSONReferences(item, outputs)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = resolveJSONReferences(item, outputs)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// resolveReferences replaces the step references in text with the outputs of the steps
+func resolveReferences(text string, outputs []string) string {
+	return stepReference.ReplaceAllStringFunc(text, func(reference string) string {
+		match := stepReference.FindStringSubmatch(reference)
+		index := len(outputs) - 1
+		if match[2] != "" {
+			n, err := strconv.Atoi(match[2])
+			if err != nil {
+				return reference
+			}
+			index = n - 1
+		}
+		if index < 0 || index >= len(outputs) {
+			return reference
+		}
+		return outputs[index]
+	})
 }
 
 // CancelPlan cancels an execution plan
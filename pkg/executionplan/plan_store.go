@@ -0,0 +1,234 @@
+package executionplan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrPlanNotFound is returned by persistent plan stores when no plan has the requested task ID
+var ErrPlanNotFound = errors.New("execution plan not found")
+
+// FilePlanStore persists execution plans as JSON files, one per plan, in a directory
+type FilePlanStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// NewFilePlanStore creates a plan store in dir, creating the directory if needed
+func NewFilePlanStore(dir string) (*FilePlanStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create plan directory: %w", err)
+	}
+	return &FilePlanStore{dir: dir}, nil
+}
+
+// Save writes the plan, replacing any previous version with the same task ID
+func (s *FilePlanStore) Save(ctx context.Context, plan *ExecutionPlan) error {
+	path, err := s.path(plan.TaskID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Write to a temporary file first so a crash never leaves a truncated plan behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// Load reads the plan with the given task ID
+func (s *FilePlanStore) Load(ctx context.Context, taskID string) (*ExecutionPlan, error) {
+	path, err := s.path(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return readPlanFile(path)
+}
+
+// List returns all plans, oldest first
+func (s *FilePlanStore) List(ctx context.Context) ([]*ExecutionPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+
+	plans := make([]*ExecutionPlan, 0, len(paths))
+	for _, path := range paths {
+		plan, err := readPlanFile(path)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	sortPlans(plans)
+	return plans, nil
+}
+
+// Delete removes the plan with the given task ID
+func (s *FilePlanStore) Delete(ctx context.Context, taskID string) error {
+	path, err := s.path(taskID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrPlanNotFound, taskID)
+		}
+		return fmt.Errorf("failed to delete plan: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of a plan, rejecting task IDs that would escape the directory
+func (s *FilePlanStore) path(taskID string) (string, error) {
+	if taskID == "" || taskID == "." || taskID == ".." || strings.ContainsAny(taskID, `/\`) {
+		return "", fmt.Errorf("invalid task ID %q", taskID)
+	}
+	return filepath.Join(s.dir, taskID+".json"), nil
+}
+
+// readPlanFile reads a plan written by FilePlanStore
+func readPlanFile(path string) (*ExecutionPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrPlanNotFound, strings.TrimSuffix(filepath.Base(path), ".json"))
+		}
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var plan ExecutionPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", filepath.Base(path), err)
+	}
+	return &plan, nil
+}
+
+// RedisPlanStore persists execution plans as JSON in a Redis hash keyed by task ID
+type RedisPlanStore struct {
+	client *redis.Client
+	key    string
+}
+
+// RedisPlanStoreOption configures a RedisPlanStore
+type RedisPlanStoreOption func(*RedisPlanStore)
+
+// WithRedisPlanKey sets the Redis key of the hash holding the plans, e.g. to separate the
+// plans of different agents sharing a Redis database
+func WithRedisPlanKey(key string) RedisPlanStoreOption {
+	return func(s *RedisPlanStore) {
+		s.key = key
+	}
+}
+
+// NewRedisPlanStore creates a plan store backed by Redis
+func NewRedisPlanStore(client *redis.Client, options ...RedisPlanStoreOption) *RedisPlanStore {
+	store := &RedisPlanStore{
+		client: client,
+		key:    "agent:execution_plans",
+	}
+	for _, option := range options {
+		option(store)
+	}
+	return store
+}
+
+// Save writes the plan, replacing any previous version with the same task ID
+func (s *RedisPlanStore) Save(ctx context.Context, plan *ExecutionPlan) error {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	if err := s.client.HSet(ctx, s.key, plan.TaskID, data).Err(); err != nil {
+		return fmt.Errorf("failed to save plan to Redis: %w", err)
+	}
+	return nil
+}
+
+// Load reads the plan with the given task ID
+func (s *RedisPlanStore) Load(ctx context.Context, taskID string) (*ExecutionPlan, error) {
+	data, err := s.client.HGet(ctx, s.key, taskID).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, fmt.Errorf("%w: %s", ErrPlanNotFound, taskID)
+		}
+		return nil, fmt.Errorf("failed to load plan from Redis: %w", err)
+	}
+
+	var plan ExecutionPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", taskID, err)
+	}
+	return &plan, nil
+}
+
+// List returns all plans, oldest first
+func (s *RedisPlanStore) List(ctx context.Context) ([]*ExecutionPlan, error) {
+	values, err := s.client.HVals(ctx, s.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans from Redis: %w", err)
+	}
+
+	plans := make([]*ExecutionPlan, 0, len(values))
+	for _, value := range values {
+		var plan ExecutionPlan
+		if err := json.Unmarshal([]byte(value), &plan); err != nil {
+			return nil, fmt.Errorf("failed to parse plan: %w", err)
+		}
+		plans = append(plans, &plan)
+	}
+	sortPlans(plans)
+	return plans, nil
+}
+
+// Delete removes the plan with the given task ID
+func (s *RedisPlanStore) Delete(ctx context.Context, taskID string) error {
+	deleted, err := s.client.HDel(ctx, s.key, taskID).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete plan from Redis: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: %s", ErrPlanNotFound, taskID)
+	}
+	return nil
+}
+
+// sortPlans orders plans by creation time, then task ID
+func sortPlans(plans []*ExecutionPlan) {
+	sort.Slice(plans, func(i, j int) bool {
+		if !plans[i].CreatedAt.Equal(plans[j].CreatedAt) {
+			return plans[i].CreatedAt.Before(plans[j].CreatedAt)
+		}
+		return plans[i].TaskID < plans[j].TaskID
+	})
+}
@@ -0,0 +1,115 @@
+package executionplan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// planStore is the interface implemented by the persistent plan stores
+type planStore interface {
+	Save(ctx context.Context, plan *ExecutionPlan) error
+	Load(ctx context.Context, taskID string) (*ExecutionPlan, error)
+	List(ctx context.Context) ([]*ExecutionPlan, error)
+	Delete(ctx context.Context, taskID string) error
+}
+
+func TestFilePlanStore(t *testing.T) {
+	store, err := NewFilePlanStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create plan store: %v", err)
+	}
+	testPlanStore(t, store)
+
+	if err := store.Save(context.Background(), &ExecutionPlan{TaskID: "../escape"}); err == nil {
+		t.Error("expected an error for a task ID outside the plan directory")
+	}
+}
+
+func TestRedisPlanStore(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	testPlanStore(t, NewRedisPlanStore(client, WithRedisPlanKey("test:plans")))
+	if !mr.Exists("test:plans") {
+		t.Error("expected plans to be stored under the configured key")
+	}
+}
+
+func testPlanStore(t *testing.T, store planStore) {
+	ctx := context.Background()
+
+	first := NewExecutionPlan("Look up the weather", []ExecutionStep{
+		{ToolName: "get_weather", Input: "Paris", Description: "Fetch weather", Parameters: map[string]interface{}{"units": "metric"}},
+	})
+	first.Status = StatusPendingApproval
+	second := NewExecutionPlan("Search the web", nil)
+	second.CreatedAt = first.CreatedAt.Add(time.Second)
+
+	for _, plan := range []*ExecutionPlan{second, first} {
+		if err := store.Save(ctx, plan); err != nil {
+			t.Fatalf("failed to save plan: %v", err)
+		}
+	}
+
+	loaded, err := store.Load(ctx, first.TaskID)
+	if err != nil {
+		t.Fatalf("failed to load plan: %v", err)
+	}
+	if loaded.Description != first.Description || loaded.Status != StatusPendingApproval || len(loaded.Steps) != 1 {
+		t.Errorf("unexpected loaded plan: %+v", loaded)
+	}
+	if loaded.Steps[0].Parameters["units"] != "metric" || !loaded.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("unexpected loaded step: %+v", loaded.Steps[0])
+	}
+
+	// Saving again replaces the plan
+	first.Status = StatusApproved
+	first.UserApproved = true
+	if err := store.Save(ctx, first); err != nil {
+		t.Fatalf("failed to update plan: %v", err)
+	}
+	loaded, err = store.Load(ctx, first.TaskID)
+	if err != nil {
+		t.Fatalf("failed to load plan: %v", err)
+	}
+	if loaded.Status != StatusApproved || !loaded.UserApproved {
+		t.Errorf("expected the updated plan, got %+v", loaded)
+	}
+
+	plans, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list plans: %v", err)
+	}
+	if len(plans) != 2 || plans[0].TaskID != first.TaskID || plans[1].TaskID != second.TaskID {
+		t.Errorf("expected plans ordered by creation time, got %+v", plans)
+	}
+
+	if err := store.Delete(ctx, first.TaskID); err != nil {
+		t.Fatalf("failed to delete plan: %v", err)
+	}
+	if _, err := store.Load(ctx, first.TaskID); !errors.Is(err, ErrPlanNotFound) {
+		t.Errorf("expected ErrPlanNotFound after delete, got %v", err)
+	}
+	if err := store.Delete(ctx, first.TaskID); !errors.Is(err, ErrPlanNotFound) {
+		t.Errorf("expected ErrPlanNotFound deleting a missing plan, got %v", err)
+	}
+
+	plans, err = store.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list plans: %v", err)
+	}
+	if len(plans) != 1 || plans[0].TaskID != second.TaskID {
+		t.Errorf("expected only the remaining plan, got %+v", plans)
+	}
+}
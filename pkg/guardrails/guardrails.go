@@ -25,6 +25,9 @@ const (
 
 	// RateLimitGuardrail limits the rate of requests
 	RateLimitGuardrail GuardrailType = "rate_limit"
+
+	// SchemaValidationGuardrail validates structured output against a JSON schema
+	SchemaValidationGuardrail GuardrailType = "schema_validation"
 )
 
 // Action represents the action to take when a guardrail is triggered
@@ -39,6 +42,9 @@ const (
 
 	// WarnAction allows the content but logs a warning
 	WarnAction Action = "warn"
+
+	// RepairAction replaces the content with a repaired version
+	RepairAction Action = "repair"
 )
 
 // Guardrail represents a guardrail that can be applied to requests and responses
@@ -60,21 +66,35 @@ type Guardrail interface {
 type Pipeline struct {
 	guardrails []Guardrail
 	logger     logging.Logger
+	authorizer OverrideAuthorizer
 }
 
 // NewPipeline creates a new guardrails pipeline
-func NewPipeline(guardrails []Guardrail, logger logging.Logger) *Pipeline {
-	return &Pipeline{
+func NewPipeline(guardrails []Guardrail, logger logging.Logger, options ...PipelineOption) *Pipeline {
+	pipeline := &Pipeline{
 		guardrails: guardrails,
 		logger:     logger,
 	}
+	for _, option := range options {
+		option(pipeline)
+	}
+	return pipeline
 }
 
 // ProcessRequest processes a request through the guardrails pipeline
 func (p *Pipeline) ProcessRequest(ctx context.Context, request string) (string, error) {
 	processedRequest := request
+	overrides := p.overrides(ctx)
 
 	for _, guardrail := range p.guardrails {
+		if overrides.bypasses(guardrail.Type()) {
+			p.logger.Info(ctx, "Guardrail bypassed", map[string]interface{}{
+				"guardrail_type": guardrail.Type(),
+			})
+			continue
+		}
+		action := overrides.action(guardrail)
+
 		triggered, modified, err := guardrail.CheckRequest(ctx, processedRequest)
 		if err != nil {
 			p.logger.Error(ctx, "Guardrail check failed", map[string]interface{}{
@@ -87,13 +107,13 @@ func (p *Pipeline) ProcessRequest(ctx context.Context, request string) (string,
 		if triggered {
 			p.logger.Info(ctx, "Guardrail triggered", map[string]interface{}{
 				"guardrail_type": guardrail.Type(),
-				"action":         guardrail.Action(),
+				"action":         action,
 			})
 
-			switch guardrail.Action() {
+			switch action {
 			case BlockAction:
 				return "", fmt.Errorf("request blocked by %s guardrail", guardrail.Type())
-			case RedactAction:
+			case RedactAction, RepairAction:
 				processedRequest = modified
 			case WarnAction:
 				// Continue with original request but log warning
@@ -112,8 +132,17 @@ func (p *Pipeline) ProcessRequest(ctx context.Context, request string) (string,
 // ProcessResponse processes a response through the guardrails pipeline
 func (p *Pipeline) ProcessResponse(ctx context.Context, response string) (string, error) {
 	processedResponse := response
+	overrides := p.overrides(ctx)
 
 	for _, guardrail := range p.guardrails {
+		if overrides.bypasses(guardrail.Type()) {
+			p.logger.Info(ctx, "Guardrail bypassed", map[string]interface{}{
+				"guardrail_type": guardrail.Type(),
+			})
+			continue
+		}
+		action := overrides.action(guardrail)
+
 		triggered, modified, err := guardrail.CheckResponse(ctx, processedResponse)
 		if err != nil {
 			p.logger.Error(ctx, "Guardrail check failed", map[string]interface{}{
@@ -126,13 +155,13 @@ func (p *Pipeline) ProcessResponse(ctx context.Context, response string) (string
 		if triggered {
 			p.logger.Info(ctx, "Guardrail triggered", map[string]interface{}{
 				"guardrail_type": guardrail.Type(),
-				"action":         guardrail.Action(),
+				"action":         action,
 			})
 
-			switch guardrail.Action() {
+			switch action {
 			case BlockAction:
 				return "", fmt.Errorf("response blocked by %s guardrail", guardrail.Type())
-			case RedactAction:
+			case RedactAction, RepairAction:
 				processedResponse = modified
 			case WarnAction:
 				// Continue with original response but log warning
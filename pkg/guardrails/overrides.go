@@ -0,0 +1,69 @@
+package guardrails
+
+import (
+	"context"
+	"slices"
+)
+
+// Overrides adjust the guardrails of a pipeline for a single run, e.g. to relax them while an
+// admin debugs a conversation, without rebuilding the pipeline
+type Overrides struct {
+	// Bypass lists the types of the guardrails to skip
+	Bypass []GuardrailType
+
+	// Actions replaces the action of the guardrails of a type, e.g. WarnAction instead of BlockAction
+	Actions map[GuardrailType]Action
+}
+
+// OverrideAuthorizer reports whether the overrides of a context are allowed, e.g. from the
+// organization or the user of the context
+type OverrideAuthorizer func(ctx context.Context, overrides Overrides) bool
+
+// PipelineOption represents an option for configuring a pipeline
+type PipelineOption func(*Pipeline)
+
+// WithOverrideAuthorizer allows the overrides of WithOverrides for the contexts the authorizer
+// accepts. Pipelines without an authorizer ignore overrides.
+func WithOverrideAuthorizer(authorizer OverrideAuthorizer) PipelineOption {
+	return func(p *Pipeline) {
+		p.authorizer = authorizer
+	}
+}
+
+type overridesKey struct{}
+
+// WithOverrides returns a context adjusting the guardrails of the pipelines it is processed by.
+// Overrides only apply to pipelines configured with WithOverrideAuthorizer, when the authorizer
+// accepts the context.
+func WithOverrides(ctx context.Context, overrides Overrides) context.Context {
+	return context.WithValue(ctx, overridesKey{}, overrides)
+}
+
+// overrides returns the overrides of a context, or no overrides if they are not authorized
+func (p *Pipeline) overrides(ctx context.Context) Overrides {
+	overrides, ok := ctx.Value(overridesKey{}).(Overrides)
+	if !ok {
+		return Overrides{}
+	}
+
+	if p.authorizer == nil || !p.authorizer(ctx, overrides) {
+		p.logger.Warn(ctx, "Ignoring unauthorized guardrail overrides", map[string]interface{}{
+			"bypass": overrides.Bypass,
+		})
+		return Overrides{}
+	}
+	return overrides
+}
+
+// bypasses reports whether the guardrails of a type are skipped
+func (o Overrides) bypasses(guardrailType GuardrailType) bool {
+	return slices.Contains(o.Bypass, guardrailType)
+}
+
+// action returns the action of a guardrail, replaced by the overrides if any
+func (o Overrides) action(guardrail Guardrail) Action {
+	if action, ok := o.Actions[guardrail.Type()]; ok {
+		return action
+	}
+	return guardrail.Action()
+}
@@ -0,0 +1,66 @@
+package guardrails
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// adminOnly authorizes the overrides of the admin organization
+func adminOnly(ctx context.Context, overrides Overrides) bool {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	return err == nil && orgID == "admin"
+}
+
+func TestPipelineOverrides(t *testing.T) {
+	pipeline := NewPipeline([]Guardrail{NewTokenLimit(3, nil, BlockAction, "")}, logging.New(), WithOverrideAuthorizer(adminOnly))
+	request := "one two three four five"
+
+	if _, err := pipeline.ProcessRequest(context.Background(), request); err == nil {
+		t.Fatal("Expected the token limit to block the request")
+	}
+
+	bypass := Overrides{Bypass: []GuardrailType{TokenLimitGuardrail}}
+
+	// The bypass applies to an authorized context
+	ctx := WithOverrides(multitenancy.WithOrgID(context.Background(), "admin"), bypass)
+	processed, err := pipeline.ProcessRequest(ctx, request)
+	if err != nil || processed != request {
+		t.Errorf("Expected the token limit to be bypassed, got %q, %v", processed, err)
+	}
+	if _, err := pipeline.ProcessResponse(ctx, request); err != nil {
+		t.Errorf("Expected the token limit to be bypassed for the response, got %v", err)
+	}
+
+	// and is ignored for an unauthorized one
+	ctx = WithOverrides(multitenancy.WithOrgID(context.Background(), "org-1"), bypass)
+	if _, err := pipeline.ProcessRequest(ctx, request); err == nil {
+		t.Error("Expected the bypass of an unauthorized context to be ignored")
+	}
+
+	// or by a pipeline without an authorizer
+	unauthorized := NewPipeline([]Guardrail{NewTokenLimit(3, nil, BlockAction, "")}, logging.New())
+	ctx = WithOverrides(multitenancy.WithOrgID(context.Background(), "admin"), bypass)
+	if _, err := unauthorized.ProcessRequest(ctx, request); err == nil {
+		t.Error("Expected overrides to be ignored without an authorizer")
+	}
+}
+
+func TestPipelineActionOverrides(t *testing.T) {
+	pipeline := NewPipeline([]Guardrail{NewTokenLimit(3, nil, BlockAction, "")}, logging.New(), WithOverrideAuthorizer(adminOnly))
+
+	// Redact the request instead of blocking it
+	ctx := WithOverrides(multitenancy.WithOrgID(context.Background(), "admin"), Overrides{
+		Actions: map[GuardrailType]Action{TokenLimitGuardrail: RedactAction},
+	})
+	processed, err := pipeline.ProcessRequest(ctx, "one two three four five")
+	if err != nil {
+		t.Fatalf("Expected the request to be truncated instead of blocked, got %v", err)
+	}
+	if !strings.HasPrefix(processed, "one two three") || strings.Contains(processed, "five") {
+		t.Errorf("Expected the truncated request, got %q", processed)
+	}
+}
@@ -0,0 +1,131 @@
+package guardrails
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/jsontext"
+)
+
+// SchemaValidator implements a guardrail that validates structured output against a JSON schema,
+// catching malformed JSON before it reaches json.Unmarshal in user code
+type SchemaValidator struct {
+	resolved  *jsonschema.Resolved
+	schemaErr error
+	action    Action
+}
+
+// NewSchemaValidator creates a new schema validation guardrail. With RepairAction, responses
+// not matching the schema are repaired when possible: the JSON is extracted from markdown code
+// fences or surrounding text, trailing commas are removed and missing properties with a
+// default are filled in. Responses that cannot be repaired are rejected with an error.
+func NewSchemaValidator(schema map[string]interface{}, action Action) *SchemaValidator {
+	resolved, err := resolveSchema(schema)
+	return &SchemaValidator{
+		resolved:  resolved,
+		schemaErr: err,
+		action:    action,
+	}
+}
+
+// resolveSchema converts a JSON schema map to a resolved schema ready for validation
+func resolveSchema(schema map[string]interface{}) (*jsonschema.Resolved, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	var s jsonschema.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	resolved, err := s.Resolve(&jsonschema.ResolveOptions{ValidateDefaults: true})
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return resolved, nil
+}
+
+// Type returns the type of guardrail
+func (s *SchemaValidator) Type() GuardrailType {
+	return SchemaValidationGuardrail
+}
+
+// CheckRequest checks if a request violates the guardrail. Requests are not structured output,
+// so they are never checked.
+func (s *SchemaValidator) CheckRequest(ctx context.Context, request string) (bool, string, error) {
+	return false, request, nil
+}
+
+// CheckResponse checks if a response is JSON matching the schema
+func (s *SchemaValidator) CheckResponse(ctx context.Context, response string) (bool, string, error) {
+	if s.schemaErr != nil {
+		return false, response, s.schemaErr
+	}
+
+	validationErr := s.validate(response)
+	if validationErr == nil {
+		return false, response, nil
+	}
+
+	if s.action != RepairAction {
+		return true, response, nil
+	}
+
+	repaired, err := s.repair(response)
+	if err != nil {
+		return false, response, fmt.Errorf("failed to repair response not matching schema: %w", validationErr)
+	}
+	return true, repaired, nil
+}
+
+// Action returns the action to take when the guardrail is triggered
+func (s *SchemaValidator) Action() Action {
+	return s.action
+}
+
+// validate parses text as JSON and validates it against the schema
+func (s *SchemaValidator) validate(text string) error {
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return s.resolved.Validate(value)
+}
+
+// repair attempts to turn a response into JSON matching the schema
+func (s *SchemaValidator) repair(response string) (string, error) {
+	text, ok := jsontext.Extract(response)
+	if !ok {
+		text = response
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		text = jsontext.Repair(text)
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return "", fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
+
+	if object, ok := value.(map[string]interface{}); ok {
+		if err := s.resolved.ApplyDefaults(&object); err != nil {
+			return "", fmt.Errorf("failed to apply defaults: %w", err)
+		}
+		value = object
+	}
+
+	if err := s.resolved.Validate(value); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal repaired response: %w", err)
+	}
+	return string(data), nil
+}
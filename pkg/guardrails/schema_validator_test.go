@@ -0,0 +1,109 @@
+package guardrails
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+var testSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"name":  map[string]interface{}{"type": "string"},
+		"score": map[string]interface{}{"type": "number"},
+		"tags":  map[string]interface{}{"type": "array", "default": []interface{}{}},
+	},
+	"required": []interface{}{"name", "score"},
+}
+
+func TestSchemaValidatorCheckResponse(t *testing.T) {
+	validator := NewSchemaValidator(testSchema, BlockAction)
+
+	tests := []struct {
+		name      string
+		response  string
+		triggered bool
+	}{
+		{"valid", `{"name": "a", "score": 1}`, false},
+		{"missing required", `{"name": "a"}`, true},
+		{"wrong type", `{"name": "a", "score": "high"}`, true},
+		{"malformed", `{"name": "a", "score": 1`, true},
+		{"not json", `The score is 1`, true},
+	}
+
+	for _, tt := range tests {
+		triggered, _, err := validator.CheckResponse(context.Background(), tt.response)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if triggered != tt.triggered {
+			t.Errorf("%s: expected triggered %v, got %v", tt.name, tt.triggered, triggered)
+		}
+	}
+}
+
+func TestSchemaValidatorRepair(t *testing.T) {
+	validator := NewSchemaValidator(testSchema, RepairAction)
+
+	responses := []string{
+		"```json\n{\"name\": \"a\", \"score\": 1}\n```",
+		`Here is the result: {"name": "a", "score": 1}. Hope it helps!`,
+		`{"name": "a", "score": 1,}`,
+		`Result: {"name": "a", "score": 1} (scores range over {0, 1})`,
+	}
+
+	for _, response := range responses {
+		triggered, repaired, err := validator.CheckResponse(context.Background(), response)
+		if err != nil {
+			t.Fatalf("Failed to repair %q: %v", response, err)
+		}
+		if !triggered {
+			t.Errorf("Expected %q to trigger the guardrail", response)
+		}
+
+		var result map[string]interface{}
+		if err := json.Unmarshal([]byte(repaired), &result); err != nil {
+			t.Fatalf("Repaired response %q is not JSON: %v", repaired, err)
+		}
+		if result["name"] != "a" || result["score"] != float64(1) {
+			t.Errorf("Unexpected repaired response: %s", repaired)
+		}
+		if _, ok := result["tags"]; !ok {
+			t.Errorf("Expected the default tags to be filled in: %s", repaired)
+		}
+	}
+
+	if _, _, err := validator.CheckResponse(context.Background(), `{"name": "a"}`); err == nil {
+		t.Error("Expected an error for a response that cannot be repaired")
+	}
+}
+
+func TestSchemaValidatorPipeline(t *testing.T) {
+	ctx := context.Background()
+
+	blocking := NewPipeline([]Guardrail{NewSchemaValidator(testSchema, BlockAction)}, logging.New())
+	if _, err := blocking.ProcessResponse(ctx, `{"name": "a"}`); err == nil {
+		t.Error("Expected the response to be blocked")
+	}
+	if _, err := blocking.ProcessResponse(ctx, `{"name": "a", "score": 1}`); err != nil {
+		t.Errorf("Expected a valid response to pass: %v", err)
+	}
+
+	repairing := NewPipeline([]Guardrail{NewSchemaValidator(testSchema, RepairAction)}, logging.New())
+	response, err := repairing.ProcessResponse(ctx, "```\n{\"name\": \"a\", \"score\": 1}\n```")
+	if err != nil {
+		t.Fatalf("Expected the response to be repaired: %v", err)
+	}
+	if response != `{"name":"a","score":1,"tags":[]}` {
+		t.Errorf("Unexpected repaired response: %s", response)
+	}
+}
+
+func TestSchemaValidatorInvalidSchema(t *testing.T) {
+	validator := NewSchemaValidator(map[string]interface{}{"type": 5}, BlockAction)
+	if _, _, err := validator.CheckResponse(context.Background(), `{}`); err == nil {
+		t.Error("Expected an error for an invalid schema")
+	}
+}
@@ -0,0 +1,85 @@
+package interfaces
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// ImageInput is an image attached to a prompt, referenced by URL or given as raw bytes.
+// Exactly one of URL and Data is set.
+type ImageInput struct {
+	// URL is the location of the image
+	URL string
+	// Data is the encoded image, such as the content of a PNG file
+	Data []byte
+	// MIMEType is the media type of the image, e.g. image/png. It is required with Data.
+	MIMEType string
+}
+
+// Validate checks that the image is referenced by URL or given as bytes with a MIME type
+func (i ImageInput) Validate() error {
+	switch {
+	case i.URL != "" && len(i.Data) > 0:
+		return fmt.Errorf("image must have either a URL or data, not both")
+	case i.URL == "" && len(i.Data) == 0:
+		return fmt.Errorf("image must have a URL or data")
+	case len(i.Data) > 0 && i.MIMEType == "":
+		return fmt.Errorf("image data requires a MIME type")
+	}
+	return nil
+}
+
+// DataURL returns the image data as a base64 data URL, e.g. data:image/png;base64,...
+func (i ImageInput) DataURL() string {
+	return "data:" + i.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(i.Data)
+}
+
+// ValidateImages checks the images of a request, see ImageInput.Validate
+func ValidateImages(images []ImageInput) error {
+	for n, image := range images {
+		if err := image.Validate(); err != nil {
+			return fmt.Errorf("invalid image %d: %w", n+1, err)
+		}
+	}
+	return nil
+}
+
+// FileInput is a file uploaded to the provider and referenced by URI, so that large inputs
+// are not sent again with every request
+type FileInput struct {
+	// URI is the location of the uploaded file, as returned by the upload
+	URI string
+	// MIMEType is the media type of the file, e.g. application/pdf or video/mp4
+	MIMEType string
+}
+
+// ValidateFiles checks that the files of a request have a URI and a MIME type
+func ValidateFiles(files []FileInput) error {
+	for n, file := range files {
+		switch {
+		case file.URI == "":
+			return fmt.Errorf("invalid file %d: file must have a URI", n+1)
+		case file.MIMEType == "":
+			return fmt.Errorf("invalid file %d: MIME type required for %s", n+1, file.URI)
+		}
+	}
+	return nil
+}
+
+// RejectFiles returns an error when files are attached to a request of a provider that cannot
+// read uploaded files, so that the request is not answered without them
+func RejectFiles(provider string, files []FileInput) error {
+	if len(files) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s does not support file input", provider)
+}
+
+// RejectImages returns an error when images are attached to a request of a provider that cannot
+// send them to the model, so that the request is not answered as if the images had been seen
+func RejectImages(provider string, images []ImageInput) error {
+	if len(images) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s does not support image input", provider)
+}
@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImageInputValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		image ImageInput
+		err   string
+	}{
+		{"url", ImageInput{URL: "https://example.com/cat.png"}, ""},
+		{"data", ImageInput{Data: []byte{1}, MIMEType: "image/png"}, ""},
+		{"both", ImageInput{URL: "https://example.com/cat.png", Data: []byte{1}, MIMEType: "image/png"}, "not both"},
+		{"empty", ImageInput{}, "must have a URL or data"},
+		{"data without MIME type", ImageInput{Data: []byte{1}}, "requires a MIME type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.image.Validate()
+			if tt.err == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+
+	err := ValidateImages([]ImageInput{{URL: "https://example.com/cat.png"}, {}})
+	if err == nil || !strings.Contains(err.Error(), "invalid image 2") {
+		t.Errorf("Expected the invalid image to be identified, got %v", err)
+	}
+}
+
+func TestImageInputDataURL(t *testing.T) {
+	image := ImageInput{Data: []byte("png"), MIMEType: "image/png"}
+	if got := image.DataURL(); got != "data:image/png;base64,cG5n" {
+		t.Errorf("Unexpected data URL: %s", got)
+	}
+}
@@ -1,6 +1,10 @@
 package interfaces
 
-import "context"
+import (
+	"context"
+	"strings"
+	"time"
+)
 
 // LLM represents a large language model provider
 type LLM interface {
@@ -22,13 +26,25 @@ type GenerateOption func(options *GenerateOptions)
 
 // GenerateOptions contains configuration for text generation
 type GenerateOptions struct {
-	LLMConfig      *LLMConfig      // LLM config for the generation
-	OrgID          string          // For multi-tenancy
-	SystemMessage  string          // System message for chat models
-	ResponseFormat *ResponseFormat // Optional expected response format
-	MaxIterations  int             // Maximum number of tool-calling iterations (0 = use default)
-	Memory         Memory          // Optional memory for storing tool calls and results
-	StreamConfig   *StreamConfig   // Optional streaming configuration
+	LLMConfig             *LLMConfig      // LLM config for the generation
+	OrgID                 string          // For multi-tenancy
+	SystemMessage         string          // System message for chat models
+	ResponseFormat        *ResponseFormat // Optional expected response format
+	MaxIterations         int             // Maximum number of tool-calling iterations (0 = use default)
+	Memory                Memory          // Optional memory for storing tool calls and results
+	StreamConfig          *StreamConfig   // Optional streaming configuration
+	ThinkingPolicy        ThinkingPolicy  // Where thinking content of thinking models is surfaced (empty = provider default)
+	NoRetry               bool            // Bypass the retry policy of the client for this call
+	MaxRetries            int             // Maximum number of attempts of this call, overriding the retry policy of the client (0 = client policy)
+	StreamRetry           int             // Maximum number of times a failed stream is re-requested and resumed (0 = disabled)
+	ValidateToolArguments bool            // Validate tool call arguments against the tool parameters before executing them
+	RepairToolArguments   bool            // Repair tool call arguments which are not valid JSON before executing them
+	ToolTimeout           time.Duration   // Maximum duration of each tool call (0 = no timeout)
+	StreamHeartbeat       time.Duration   // Interval of heartbeat events during quiet periods of a stream (0 = disabled)
+	Images                []ImageInput    // Images attached to the prompt, for models with vision support
+	ToolChoice            *ToolChoice     // Whether and which tools the model calls (nil = provider default, auto)
+	OutputRepair          int             // Maximum number of corrective re-prompts when a structured response is not valid JSON (0 = disabled)
+	Files                 []FileInput     // Files previously uploaded to the provider, attached to the prompt by URI
 }
 
 type LLMConfig struct {
@@ -43,6 +59,8 @@ type LLMConfig struct {
 	MaxCompletionTokens int      // Maximum completion tokens for reasoning models (gpt-5, o1)
 	ReasoningEffort     string   // Reasoning effort for GPT-5: "minimal", "low", "medium", "high"
 	Verbosity          string   // Response verbosity for GPT-5: "low", "medium", "high"
+	ThinkingBudget     int      // Token budget for thinking, bounded independently of the output limit (0 = provider default)
+	MaxTokens          int      // Maximum number of output tokens (0 = provider default)
 }
 
 // WithMaxIterations creates a GenerateOption to set the maximum number of tool-calling iterations
@@ -169,3 +187,164 @@ func WithVerbosity(verbosity string) GenerateOption {
 		options.LLMConfig.Verbosity = verbosity
 	}
 }
+
+// WithThinkingBudget creates a GenerateOption to cap the tokens a thinking model may spend on reasoning.
+// The budget is applied on top of the output-token limit so reasoning cannot starve the final answer.
+// Providers return an error when the configured model does not support thinking or the budget is
+// below their minimum, e.g. 1024 tokens for Anthropic. Only Anthropic and Gemini support thinking
+// budgets: OpenAI, Azure OpenAI, Ollama and vLLM reject them, and the reasoning of OpenAI
+// reasoning models is bounded with WithReasoningEffort instead.
+func WithThinkingBudget(tokens int) GenerateOption {
+	return func(options *GenerateOptions) {
+		if options.LLMConfig == nil {
+			options.LLMConfig = &LLMConfig{}
+		}
+		options.LLMConfig.ThinkingBudget = tokens
+	}
+}
+
+// ThinkingPolicy controls where the thinking content of thinking models is surfaced
+type ThinkingPolicy string
+
+const (
+	// ThinkingPolicyHidden drops thinking content entirely
+	ThinkingPolicyHidden ThinkingPolicy = "hidden"
+	// ThinkingPolicyInternal keeps thinking content in traces and logs but strips it from the user-facing answer
+	ThinkingPolicyInternal ThinkingPolicy = "internal"
+	// ThinkingPolicyVisible includes thinking content in the answer
+	ThinkingPolicyVisible ThinkingPolicy = "visible"
+)
+
+// WithThinkingPolicy creates a GenerateOption to set where thinking content is surfaced.
+// With the internal and visible policies providers return thinking content as a leading
+// thinking block of the response, see FormatThinking and SplitThinking.
+func WithThinkingPolicy(policy ThinkingPolicy) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ThinkingPolicy = policy
+	}
+}
+
+// WithNoRetry creates a GenerateOption that disables the retry policy of the client for a
+// single call, for latency-sensitive or non-idempotent requests. The first error is returned as is.
+func WithNoRetry() GenerateOption {
+	return func(options *GenerateOptions) {
+		options.NoRetry = true
+	}
+}
+
+// WithMaxRetries creates a GenerateOption that overrides the maximum number of attempts of the
+// retry policy of the client for a single call, the first attempt included: 1 fails fast on the
+// first error, while a value above the configured attempts retries more. Backoff and the errors
+// that are retried still follow the policy of the client.
+func WithMaxRetries(n int) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.MaxRetries = n
+	}
+}
+
+// WithStructuredOutputRepair creates a GenerateOption that re-prompts the model when the response
+// to a request with a response format is not valid JSON, sending the prompt again along with the
+// parse error and the invalid response, up to maxAttempts times. The parse error of the last
+// attempt is returned if none succeeds.
+func WithStructuredOutputRepair(maxAttempts int) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.OutputRepair = maxAttempts
+	}
+}
+
+// WithStreamRetry creates a GenerateOption that re-requests a stream up to max times when it
+// fails mid-stream, such as on a dropped connection. The resumed stream skips the content
+// already emitted, so consumers see the generation once, and ends with an error if it does
+// not reproduce that content. It applies to GenerateStream only,
+// since re-requesting a tool-calling stream would execute its tools again.
+func WithStreamRetry(max int) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.StreamRetry = max
+	}
+}
+
+// WithToolArgumentValidation creates a GenerateOption that validates tool call arguments
+// against the parameters of the tool before executing it. Invalid calls are not executed; the
+// model receives a JSON ToolValidationError as the tool result so it can correct the call.
+func WithToolArgumentValidation() GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ValidateToolArguments = true
+	}
+}
+
+// WithToolArgumentRepair creates a GenerateOption that repairs tool call arguments which are
+// not valid JSON, such as a truncated object or trailing commas, before executing the tool. Calls
+// whose arguments can't be repaired are not executed; the model receives a JSON
+// ToolValidationError asking it to retry the call with valid JSON.
+func WithToolArgumentRepair() GenerateOption {
+	return func(options *GenerateOptions) {
+		options.RepairToolArguments = true
+	}
+}
+
+// WithToolTimeout creates a GenerateOption that bounds the duration of each tool call. A call
+// running past the timeout is abandoned and the model receives a timeout error as the tool result.
+func WithToolTimeout(timeout time.Duration) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ToolTimeout = timeout
+	}
+}
+
+// WithStreamHeartbeat creates a GenerateOption that emits a StreamEventHeartbeat event whenever
+// a stream has been quiet for the interval, such as during long thinking phases or tool calls,
+// so that proxies and load balancers do not close the idle connection. Heartbeats stop while
+// other events flow.
+func WithStreamHeartbeat(interval time.Duration) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.StreamHeartbeat = interval
+	}
+}
+
+// WithImages creates a GenerateOption that attaches images to the prompt. Providers supporting
+// image input return an error if the model does not support vision, other providers reject the
+// request.
+func WithImages(images []ImageInput) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.Images = images
+	}
+}
+
+// WithFiles creates a GenerateOption that attaches files uploaded to the provider to the prompt,
+// such as documents or videos uploaded with the Gemini File API
+func WithFiles(files []FileInput) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.Files = files
+	}
+}
+
+// IncludesThinking returns true if the policy keeps thinking content in the response
+func (p ThinkingPolicy) IncludesThinking() bool {
+	return p == ThinkingPolicyInternal || p == ThinkingPolicyVisible
+}
+
+const (
+	thinkingOpenTag  = "<thinking>"
+	thinkingCloseTag = "</thinking>"
+)
+
+// FormatThinking prepends thinking content to a response as a thinking block
+func FormatThinking(thinking, response string) string {
+	if thinking == "" {
+		return response
+	}
+	return thinkingOpenTag + "\n" + thinking + "\n" + thinkingCloseTag + "\n\n" + response
+}
+
+// SplitThinking separates a leading thinking block, as produced by FormatThinking, from the response
+func SplitThinking(response string) (thinking, answer string) {
+	if !strings.HasPrefix(response, thinkingOpenTag) {
+		return "", response
+	}
+	end := strings.Index(response, thinkingCloseTag)
+	if end < 0 {
+		return "", response
+	}
+	thinking = strings.TrimSpace(response[len(thinkingOpenTag):end])
+	answer = strings.TrimLeft(response[end+len(thinkingCloseTag):], "\n")
+	return thinking, answer
+}
@@ -2,10 +2,18 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrMessageNotFound is returned when a message ID does not exist in the conversation
+var ErrMessageNotFound = errors.New("message not found")
+
 // Message represents a message in a conversation
 type Message struct {
+	// ID uniquely identifies the message within its conversation; memories assign one
+	// when a message is added without it
+	ID string
+
 	// Role is the role of the message sender (e.g., "user", "assistant", "system", "tool")
 	Role string
 
@@ -41,6 +49,72 @@ type Memory interface {
 
 	// Clear clears the memory
 	Clear(ctx context.Context) error
+
+	// DeleteMessage removes the message with the given ID from memory
+	DeleteMessage(ctx context.Context, id string) error
+
+	// UpdateMessage replaces the content of the message with the given ID
+	UpdateMessage(ctx context.Context, id string, content string) error
+}
+
+// ConversationMetadataStore is an optional interface that memories can implement
+// to store metadata about the current conversation, such as its title
+type ConversationMetadataStore interface {
+	// GetConversationMetadata returns the metadata of the conversation in context
+	GetConversationMetadata(ctx context.Context) (map[string]interface{}, error)
+
+	// SetConversationMetadata sets a metadata value on the conversation in context
+	SetConversationMetadata(ctx context.Context, key string, value interface{}) error
+}
+
+// ConversationLister is an optional interface that memories can implement to enumerate the
+// conversations they store
+type ConversationLister interface {
+	// ListConversations returns the IDs of the conversations of the organization in context
+	ListConversations(ctx context.Context) ([]string, error)
+}
+
+// MessageSearcher is an optional interface that memories can implement to search the
+// messages of the current conversation natively
+type MessageSearcher interface {
+	// SearchMessages returns up to k messages matching query, most relevant first
+	SearchMessages(ctx context.Context, query string, k int, options ...SearchMessagesOption) ([]Message, error)
+}
+
+// SearchMode selects how messages are matched against a search query
+type SearchMode string
+
+const (
+	// SearchModeKeyword matches the terms of the query against message content
+	SearchModeKeyword SearchMode = "keyword"
+	// SearchModeSemantic matches messages by meaning using embeddings
+	SearchModeSemantic SearchMode = "semantic"
+)
+
+// SearchMessagesOptions contains options for searching messages
+type SearchMessagesOptions struct {
+	// Mode is the search mode, keyword when empty
+	Mode SearchMode
+
+	// Roles filters messages by role
+	Roles []string
+}
+
+// SearchMessagesOption represents an option for searching messages
+type SearchMessagesOption func(*SearchMessagesOptions)
+
+// WithSearchMode sets the search mode
+func WithSearchMode(mode SearchMode) SearchMessagesOption {
+	return func(o *SearchMessagesOptions) {
+		o.Mode = mode
+	}
+}
+
+// WithSearchRoles filters searched messages by role
+func WithSearchRoles(roles ...string) SearchMessagesOption {
+	return func(o *SearchMessagesOptions) {
+		o.Roles = roles
+	}
 }
 
 // GetMessagesOptions contains options for retrieving messages
@@ -53,6 +127,15 @@ type GetMessagesOptions struct {
 
 	// Query is a search query for relevant messages
 	Query string
+
+	// Offset is the number of most recent messages to skip before applying the limit
+	Offset int
+
+	// After keeps only the messages following the message with this ID
+	After string
+
+	// Before keeps only the messages preceding the message with this ID
+	Before string
 }
 
 // GetMessagesOption represents an option for retrieving messages
@@ -78,3 +161,28 @@ func WithQuery(query string) GetMessagesOption {
 		o.Query = query
 	}
 }
+
+// WithOffset skips the n most recent messages before applying the limit, so that the next page
+// of older messages is fetched with the number of messages already loaded as offset
+func WithOffset(n int) GetMessagesOption {
+	return func(o *GetMessagesOptions) {
+		o.Offset = n
+	}
+}
+
+// WithAfter keeps only the messages following the message with the given ID, e.g. to fetch the
+// messages added since the last one loaded
+func WithAfter(messageID string) GetMessagesOption {
+	return func(o *GetMessagesOptions) {
+		o.After = messageID
+	}
+}
+
+// WithBefore keeps only the messages preceding the message with the given ID. Combined with
+// WithLimit and the ID of the oldest message loaded, it pages backward through the history
+// with a cursor that stays stable as new messages are added.
+func WithBefore(messageID string) GetMessagesOption {
+	return func(o *GetMessagesOptions) {
+		o.Before = messageID
+	}
+}
@@ -20,8 +20,44 @@ const (
 	StreamEventToolUse    StreamEventType = "tool_use"
 	StreamEventToolResult StreamEventType = "tool_result"
 
+	// StreamEventToolCallDelta reports the arguments of a tool call as they are streamed.
+	// ToolCall holds the call with the arguments received so far, which may not be valid
+	// JSON yet; the complete call follows in a StreamEventToolUse event.
+	StreamEventToolCallDelta StreamEventType = "tool_call_delta"
+
 	// Thinking/reasoning events
 	StreamEventThinking StreamEventType = "thinking"
+
+	// StreamEventUsage carries the cumulative token usage of the stream in Usage. Providers
+	// reporting usage incrementally emit it each time the counts change; providers that only
+	// report usage once emit a single event with the final counts before the stream stops.
+	StreamEventUsage StreamEventType = "usage"
+
+	// StreamEventHeartbeat carries no data. It is emitted during quiet periods of a stream to
+	// keep idle connections open, see WithStreamHeartbeat.
+	StreamEventHeartbeat StreamEventType = "heartbeat"
+)
+
+// TokenUsage is the number of tokens consumed by a request so far
+type TokenUsage struct {
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+// FinishReason is the reason a model stopped generating, normalized across providers
+type FinishReason string
+
+const (
+	// FinishReasonStop means the model finished its response or hit a stop sequence
+	FinishReasonStop FinishReason = "stop"
+	// FinishReasonLength means the response was cut at the output token limit
+	FinishReasonLength FinishReason = "length"
+	// FinishReasonToolUse means the model stopped to call tools
+	FinishReasonToolUse FinishReason = "tool_use"
+	// FinishReasonContentFilter means the response was blocked or cut by a content or safety filter
+	FinishReasonContentFilter FinishReason = "content_filter"
+	// FinishReasonUnknown is any other reason reported by the provider
+	FinishReasonUnknown FinishReason = "unknown"
 )
 
 // StreamEvent represents a single event in a stream
@@ -30,8 +66,13 @@ type StreamEvent struct {
 	Content   string                 `json:"content,omitempty"`
 	ToolCall  *ToolCall              `json:"tool_call,omitempty"`
 	Error     error                  `json:"error,omitempty"`
+	Usage     *TokenUsage            `json:"usage,omitempty"`
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 	Timestamp time.Time              `json:"timestamp"`
+
+	// FinishReason is set on the event reporting why the model stopped generating, with the
+	// raw reason of the provider in Metadata
+	FinishReason FinishReason `json:"finish_reason,omitempty"`
 }
 
 // StreamingLLM extends LLM with streaming capabilities
@@ -73,6 +114,11 @@ const (
 	AgentEventToolResult AgentEventType = "tool_result"
 	AgentEventError      AgentEventType = "error"
 	AgentEventComplete   AgentEventType = "complete"
+	AgentEventCancelled  AgentEventType = "cancelled"
+
+	// AgentEventToolCallDelta reports the arguments of a tool call as the LLM streams them, see
+	// StreamEventToolCallDelta. ToolCall holds the arguments received so far.
+	AgentEventToolCallDelta AgentEventType = "tool_call_delta"
 )
 
 // ToolCallEvent represents a tool call in streaming context
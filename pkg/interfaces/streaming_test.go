@@ -100,7 +100,9 @@ func TestStreamEventTypes(t *testing.T) {
 		StreamEventError,
 		StreamEventToolUse,
 		StreamEventToolResult,
+		StreamEventToolCallDelta,
 		StreamEventThinking,
+		StreamEventHeartbeat,
 	}
 
 	for _, eventType := range eventTypes {
@@ -7,6 +7,7 @@ type ResponseFormat struct {
 	Type   ResponseFormatType
 	Name   string     // The name of the struct/object to be returned
 	Schema JSONSchema // JSON schema representation of the struct
+	Strict bool       // Whether providers supporting it enforce the schema exactly, e.g. OpenAI strict mode
 }
 
 type JSONSchema map[string]interface{}
@@ -35,6 +35,39 @@ type InternalTool interface {
 	Internal() bool
 }
 
+// ToolWrapper is embedded by tools wrapping another tool, e.g. to validate its arguments or
+// bound its duration. It forwards the display name and internal flag of the wrapped tool, so
+// wrappers only implement the methods whose behavior they change.
+type ToolWrapper struct {
+	Tool
+}
+
+// DisplayName returns the display name of the wrapped tool, or its name
+func (w ToolWrapper) DisplayName() string {
+	if named, ok := w.Tool.(ToolWithDisplayName); ok {
+		if displayName := named.DisplayName(); displayName != "" {
+			return displayName
+		}
+	}
+	return w.Tool.Name()
+}
+
+// Internal reports whether the wrapped tool is internal
+func (w ToolWrapper) Internal() bool {
+	if internal, ok := w.Tool.(InternalTool); ok {
+		return internal.Internal()
+	}
+	return false
+}
+
+// ContextScopedTool is an optional interface that tools can implement to declare the context
+// values they read. Agents created with agent.WithRestrictedToolContext run the tools with a
+// context exposing only these values, see agent.RestrictContext.
+type ContextScopedTool interface {
+	// ContextKeys returns the keys of the context values the tool reads
+	ContextKeys() []interface{}
+}
+
 // ParameterSpec defines the specification for a tool parameter
 type ParameterSpec struct {
 	// Type is the data type of the parameter (string, number, boolean, etc.)
@@ -54,6 +87,18 @@ type ParameterSpec struct {
 
 	// Items is the type of the items in the parameter
 	Items *ParameterSpec
+
+	// Properties are the nested properties of an object parameter
+	Properties map[string]ParameterSpec
+}
+
+// SchemaType returns the JSON schema type of the parameter, inferring "object" for
+// parameters that only declare nested properties
+func (p ParameterSpec) SchemaType() string {
+	if p.Type == "" && p.Properties != nil {
+		return "object"
+	}
+	return p.Type
 }
 
 // ToolRegistry is a registry of available tools
@@ -0,0 +1,48 @@
+package interfaces
+
+// ToolChoiceMode controls whether the model calls tools
+type ToolChoiceMode string
+
+const (
+	// ToolChoiceAuto lets the model decide whether to call tools
+	ToolChoiceAuto ToolChoiceMode = "auto"
+	// ToolChoiceNone prevents the model from calling tools
+	ToolChoiceNone ToolChoiceMode = "none"
+	// ToolChoiceRequired makes the model call at least one tool
+	ToolChoiceRequired ToolChoiceMode = "required"
+	// ToolChoiceTool makes the model call the tool named by the choice
+	ToolChoiceTool ToolChoiceMode = "tool"
+)
+
+// ToolChoice controls whether and which tools the model calls
+type ToolChoice struct {
+	// Mode is the tool choice mode
+	Mode ToolChoiceMode
+
+	// Name is the name of the tool to call with ToolChoiceTool
+	Name string
+}
+
+// RequireTool returns a ToolChoice making the model call the named tool
+func RequireTool(name string) ToolChoice {
+	return ToolChoice{Mode: ToolChoiceTool, Name: name}
+}
+
+// WithToolChoice creates a GenerateOption to control whether and which tools the model calls,
+// e.g. WithToolChoice(RequireTool("calculator")) to guarantee the calculator is invoked. The
+// choice applies to the first request of a tool-calling loop; the requests following tool
+// results let the model decide, so that a forced tool is not called again and again.
+func WithToolChoice(choice ToolChoice) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ToolChoice = &choice
+	}
+}
+
+// ToolChoiceForIteration returns the tool choice of the request of a tool-calling loop
+// iteration, starting at 0: the configured choice for the first request, nil afterwards
+func (o *GenerateOptions) ToolChoiceForIteration(iteration int) *ToolChoice {
+	if iteration > 0 {
+		return nil
+	}
+	return o.ToolChoice
+}
@@ -0,0 +1,65 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/jsontext"
+)
+
+// toolArgumentsRetryMessage asks the model to retry a tool call whose arguments are not valid JSON
+const toolArgumentsRetryMessage = "%v. Retry the tool call with arguments that are a valid JSON object."
+
+// RepairToolArguments returns the JSON tool call arguments unchanged if valid, or repaired from
+// common syntax mistakes of models with jsontext.Repair: a markdown code fence or text around
+// the object, single-quoted strings and trailing commas. Truncated arguments are not completed,
+// so that a tool never runs with a cut-off value. It returns an error if the arguments can't be
+// repaired into a JSON object.
+func RepairToolArguments(args string) (string, error) {
+	var parsed map[string]interface{}
+	err := json.Unmarshal([]byte(args), &parsed)
+	if err == nil || strings.TrimSpace(args) == "" {
+		return args, nil
+	}
+
+	repaired := jsontext.Repair(args)
+	if json.Unmarshal([]byte(repaired), &parsed) != nil {
+		return "", fmt.Errorf("invalid tool arguments: %w", err)
+	}
+	return repaired, nil
+}
+
+// RepairingTools wraps tools so that tool call arguments which are not valid JSON are repaired
+// with RepairToolArguments before executing the tool. Arguments that can't be repaired are not
+// executed; the tool result is a JSON ToolValidationError asking the model to retry the call.
+func RepairingTools(tools []Tool) []Tool {
+	wrapped := make([]Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &repairingTool{ToolWrapper: ToolWrapper{Tool: tool}}
+	}
+	return wrapped
+}
+
+// repairingTool repairs the arguments of a tool before executing it
+type repairingTool struct {
+	ToolWrapper
+}
+
+// Run executes the tool with the given input
+func (t *repairingTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// Execute repairs the arguments before executing the tool
+func (t *repairingTool) Execute(ctx context.Context, args string) (string, error) {
+	repaired, err := RepairToolArguments(args)
+	if err != nil {
+		return (&ToolValidationError{
+			Reason:  ValidationReasonInvalidJSON,
+			Message: fmt.Sprintf(toolArgumentsRetryMessage, err),
+		}).Result(), nil
+	}
+	return ExecuteTool(ctx, t.Tool, repaired)
+}
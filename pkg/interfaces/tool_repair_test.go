@@ -0,0 +1,96 @@
+package interfaces
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRepairToolArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		expected string
+	}{
+		{"valid", `{"query":"go","limit":3}`, `{"query":"go","limit":3}`},
+		{"empty", ``, ``},
+		{"code fence", "```json\n{\"query\":\"go\"}\n```", `{"query":"go"}`},
+		{"surrounding text", `Here are the arguments: {"query":"go"} Let me know.`, `{"query":"go"}`},
+		{"trailing commas", `{"tags":["a","b",],"query":"go",}`, `{"tags":["a","b"],"query":"go"}`},
+		{"single quotes", `{'query':'it"s'}`, `{"query":"it\"s"}`},
+		{"newline in string", "{\"query\":\"line 1\nline 2\"}", `{"query":"line 1\nline 2"}`},
+		{"commas and quotes in strings", `{'query':"a,]",}`, `{"query":"a,]"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repaired, err := RepairToolArguments(tt.args)
+			if err != nil {
+				t.Fatalf("RepairToolArguments(%q) returned %v", tt.args, err)
+			}
+			if repaired != tt.expected {
+				t.Errorf("RepairToolArguments(%q) = %s, expected %s", tt.args, repaired, tt.expected)
+			}
+		})
+	}
+
+	// Truncated arguments are not completed
+	for _, args := range []string{`query=go`, `["go"]`, `{"query":}`, `{"query" "go"}`, `{"path":"/etc/pas`, `{"tags":["a","b",`, `{"filter":{"lang":"en"`} {
+		if repaired, err := RepairToolArguments(args); err == nil {
+			t.Errorf("RepairToolArguments(%q) = %s, expected an error", args, repaired)
+		}
+	}
+}
+
+// repairTestTool records the arguments it is executed with
+type repairTestTool struct {
+	args []string
+}
+
+func (t *repairTestTool) Name() string        { return "search" }
+func (t *repairTestTool) Description() string { return "Searches things" }
+func (t *repairTestTool) Parameters() map[string]ParameterSpec {
+	return map[string]ParameterSpec{"query": {Type: "string", Required: true}}
+}
+func (t *repairTestTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *repairTestTool) Execute(ctx context.Context, args string) (string, error) {
+	t.args = append(t.args, args)
+	return "found", nil
+}
+
+func TestRepairingTools(t *testing.T) {
+	tool := &repairTestTool{}
+	wrapped := RepairingTools([]Tool{tool})[0]
+
+	result, err := wrapped.Execute(context.Background(), `{"query":"go",}`)
+	if err != nil || result != "found" {
+		t.Fatalf("Expected a repaired call to be executed, got %q (%v)", result, err)
+	}
+	if len(tool.args) != 1 || tool.args[0] != `{"query":"go"}` {
+		t.Errorf("Expected the tool to receive the repaired arguments, got %v", tool.args)
+	}
+
+	result, err = wrapped.Execute(context.Background(), `query=go`)
+	if err != nil {
+		t.Fatalf("Expected the corrective result as the tool result, got error %v", err)
+	}
+	if len(tool.args) != 1 {
+		t.Errorf("Expected arguments that can't be repaired not to be executed")
+	}
+	validationErr, ok := ParseToolValidationError(result)
+	if !ok || validationErr.Reason != ValidationReasonInvalidJSON {
+		t.Fatalf("Expected an invalid JSON validation error, got %s", result)
+	}
+	if !strings.Contains(validationErr.Message, "Retry the tool call") {
+		t.Errorf("Expected the result to ask the model to retry, got %q", validationErr.Message)
+	}
+
+	// Repaired arguments are still validated when validation is enabled
+	validated := RepairingTools(ValidatingTools([]Tool{tool}))[0]
+	result, _ = validated.Execute(context.Background(), `{"query":1,}`)
+	if result != `{"error":"validation","field":"query","reason":"type"}` {
+		t.Errorf("Expected the repaired arguments to be validated, got %s", result)
+	}
+}
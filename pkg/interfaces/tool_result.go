@@ -0,0 +1,70 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ToolResult is the result of a StructuredTool: typed data for the model and for downstream
+// steps, along with a string for display to users
+type ToolResult struct {
+	// Data is the structured result, sent to the model as JSON
+	Data interface{}
+	// Display is a human-readable form of the result, sent to the model when Data is nil
+	Display string
+}
+
+// Content returns the content of the result sent to the model: the JSON encoding of Data, or
+// Display without data
+func (r ToolResult) Content() (string, error) {
+	if r.Data == nil {
+		return r.Display, nil
+	}
+	data, err := json.Marshal(r.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize tool result: %w", err)
+	}
+	return string(data), nil
+}
+
+// StructuredTool is an optional interface of tools returning structured results instead of
+// data embedded in text. The tool loops of the LLM clients prefer ExecuteStructured when it is
+// available; Execute should still return the result as text for other callers.
+type StructuredTool interface {
+	Tool
+
+	// ExecuteStructured executes the tool with the given arguments
+	ExecuteStructured(ctx context.Context, args string) (ToolResult, error)
+}
+
+// ExecuteTool executes a tool with the given arguments and returns the result sent to the model.
+// Structured tools are executed with ExecuteStructured and their data serialized to JSON.
+func ExecuteTool(ctx context.Context, tool Tool, args string) (string, error) {
+	structured, ok := tool.(StructuredTool)
+	if !ok {
+		return tool.Execute(ctx, args)
+	}
+
+	result, err := structured.ExecuteStructured(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	return result.Content()
+}
+
+// toolCallIDKey is the context key of the ID of the tool call being executed
+type toolCallIDKey struct{}
+
+// WithToolCallID returns a context carrying the ID of the tool call being executed, as stored in
+// memory. The tool loops of the LLM clients set it, so that tools can relate their execution to
+// the stored call.
+func WithToolCallID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, toolCallIDKey{}, id)
+}
+
+// ToolCallIDFromContext returns the ID of the tool call being executed, if the tool loop set it
+func ToolCallIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(toolCallIDKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,62 @@
+package interfaces
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// instanceTool returns the instances of a cloud account as structured data
+type instanceTool struct{}
+
+type instance struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+}
+
+func (t *instanceTool) Name() string        { return "list_instances" }
+func (t *instanceTool) Description() string { return "Lists instances" }
+func (t *instanceTool) Parameters() map[string]ParameterSpec {
+	return map[string]ParameterSpec{"region": {Type: "string", Required: true}}
+}
+func (t *instanceTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *instanceTool) Execute(ctx context.Context, args string) (string, error) {
+	return "2 instances: i-1 running, i-2 stopped", nil
+}
+func (t *instanceTool) ExecuteStructured(ctx context.Context, args string) (ToolResult, error) {
+	return ToolResult{
+		Data:    []instance{{ID: "i-1", State: "running"}, {ID: "i-2", State: "stopped"}},
+		Display: "2 instances: i-1 running, i-2 stopped",
+	}, nil
+}
+
+func TestExecuteTool(t *testing.T) {
+	const expected = `[{"id":"i-1","state":"running"},{"id":"i-2","state":"stopped"}]`
+
+	result, err := ExecuteTool(context.Background(), &instanceTool{}, `{"region":"eu-west-1"}`)
+	if err != nil || result != expected {
+		t.Errorf("Expected the structured data as JSON, got %q, %v", result, err)
+	}
+
+	// Wrapped tools keep their results structured
+	wrapped := TimeoutTools(ValidatingTools([]Tool{&instanceTool{}}), time.Second)[0]
+	if result, err := ExecuteTool(context.Background(), wrapped, `{"region":"eu-west-1"}`); err != nil || result != expected {
+		t.Errorf("Expected the wrapped tool to return the structured data, got %q, %v", result, err)
+	}
+
+	// Other tools return their text result
+	if result, err := ExecuteTool(context.Background(), &sleepTool{}, "{}"); err != nil || result != "awake" {
+		t.Errorf("Expected the text result, got %q, %v", result, err)
+	}
+}
+
+func TestToolResultContent(t *testing.T) {
+	if content, _ := (ToolResult{Display: "No instances"}).Content(); content != "No instances" {
+		t.Errorf("Expected the display string without data, got %q", content)
+	}
+	if _, err := (ToolResult{Data: make(chan int)}).Content(); err == nil {
+		t.Error("Expected an error for data that cannot be serialized")
+	}
+}
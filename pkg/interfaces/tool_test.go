@@ -0,0 +1,29 @@
+package interfaces
+
+import "testing"
+
+// displayTool is a tool with a display name and an internal flag
+type displayTool struct {
+	repairTestTool
+	displayName string
+}
+
+func (t *displayTool) DisplayName() string { return t.displayName }
+func (t *displayTool) Internal() bool      { return true }
+
+func TestToolWrapperForwardsDisplayNameAndInternal(t *testing.T) {
+	wrapped := TimeoutTools(ValidatingTools(RepairingTools([]Tool{&displayTool{displayName: "Web Search"}})), 0)[0]
+
+	if named, ok := wrapped.(ToolWithDisplayName); !ok || named.DisplayName() != "Web Search" {
+		t.Errorf("Expected the display name of the wrapped tool")
+	}
+	if internal, ok := wrapped.(InternalTool); !ok || !internal.Internal() {
+		t.Errorf("Expected the wrapped tool to stay internal")
+	}
+
+	// Tools without a display name are shown by name
+	plain := ValidatingTools([]Tool{&displayTool{}})[0].(ToolWithDisplayName)
+	if plain.DisplayName() != "search" {
+		t.Errorf("Expected the name of the tool, got %q", plain.DisplayName())
+	}
+}
@@ -0,0 +1,64 @@
+package interfaces
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TimeoutTools wraps tools so that each call is bounded by the timeout. The tool runs with a
+// context expiring after the timeout; a call still running at expiry is abandoned and returns
+// an error wrapping context.DeadlineExceeded, even if the tool ignores its context.
+func TimeoutTools(tools []Tool, timeout time.Duration) []Tool {
+	wrapped := make([]Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &timeoutTool{ToolWrapper: ToolWrapper{Tool: tool}, timeout: timeout}
+	}
+	return wrapped
+}
+
+// timeoutTool bounds the duration of the calls to a tool
+type timeoutTool struct {
+	ToolWrapper
+	timeout time.Duration
+}
+
+// Run executes the tool with the given input
+func (t *timeoutTool) Run(ctx context.Context, input string) (string, error) {
+	return t.call(ctx, func(ctx context.Context) (string, error) {
+		return t.Tool.Run(ctx, input)
+	})
+}
+
+// Execute executes the tool with the given arguments
+func (t *timeoutTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.call(ctx, func(ctx context.Context) (string, error) {
+		return ExecuteTool(ctx, t.Tool, args)
+	})
+}
+
+// call runs fn with a context expiring after the timeout, abandoning it at expiry
+func (t *timeoutTool) call(ctx context.Context, fn func(ctx context.Context) (string, error)) (string, error) {
+	callCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := fn(callCtx)
+		done <- result{output, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.output, r.err
+	case <-callCtx.Done():
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("tool %s timed out after %s: %w", t.Name(), t.timeout, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,67 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// sleepTool sleeps for its delay, ignoring the cancellation of its context unless told otherwise
+type sleepTool struct {
+	delay        time.Duration
+	honorContext bool
+}
+
+func (t *sleepTool) Name() string                         { return "sleep" }
+func (t *sleepTool) Description() string                  { return "Sleeps" }
+func (t *sleepTool) Parameters() map[string]ParameterSpec { return nil }
+func (t *sleepTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *sleepTool) Execute(ctx context.Context, args string) (string, error) {
+	if t.honorContext {
+		select {
+		case <-time.After(t.delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	} else {
+		time.Sleep(t.delay)
+	}
+	return "awake", nil
+}
+
+func TestTimeoutTools(t *testing.T) {
+	fast := TimeoutTools([]Tool{&sleepTool{delay: time.Millisecond}}, time.Second)[0]
+	if result, err := fast.Execute(context.Background(), "{}"); err != nil || result != "awake" {
+		t.Errorf("Expected a call within the timeout to succeed, got %q, %v", result, err)
+	}
+
+	// A tool ignoring its context is abandoned at expiry
+	slow := TimeoutTools([]Tool{&sleepTool{delay: 2 * time.Second}}, 20*time.Millisecond)[0]
+	start := time.Now()
+	_, err := slow.Execute(context.Background(), "{}")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a deadline exceeded error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the call to be abandoned at the timeout, took %v", elapsed)
+	}
+
+	// A tool honoring its context observes the deadline
+	honoring := TimeoutTools([]Tool{&sleepTool{delay: 2 * time.Second, honorContext: true}}, 20*time.Millisecond)[0]
+	if _, err := honoring.Run(context.Background(), "{}"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a deadline exceeded error, got %v", err)
+	}
+}
+
+func TestTimeoutToolsParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tool := TimeoutTools([]Tool{&sleepTool{delay: time.Second}}, time.Minute)[0]
+	if _, err := tool.Execute(ctx, "{}"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation of the parent context, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Reasons reported by a ToolValidationError
+const (
+	ValidationReasonInvalidJSON = "invalid_json"
+	ValidationReasonRequired    = "required"
+	ValidationReasonType        = "type"
+	ValidationReasonEnum        = "enum"
+)
+
+// toolValidationErrorKind is the value of the "error" key of validation tool results
+const toolValidationErrorKind = "validation"
+
+// ToolValidationError describes tool call arguments not matching the parameters of the tool.
+// It is returned to the model as a JSON tool result, such as
+// {"error":"validation","field":"location","reason":"required"}, so the model can correct
+// the call. Nested fields are reported as dotted paths, array items with their index.
+type ToolValidationError struct {
+	Field  string
+	Reason string
+	// Message optionally tells the model how to correct the call
+	Message string
+}
+
+// Error implements the error interface
+func (e *ToolValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("invalid tool arguments: %s", e.Reason)
+	}
+	return fmt.Sprintf("invalid tool argument %q: %s", e.Field, e.Reason)
+}
+
+// MarshalJSON encodes the error in the tool result format
+func (e *ToolValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Error   string `json:"error"`
+		Field   string `json:"field,omitempty"`
+		Reason  string `json:"reason"`
+		Message string `json:"message,omitempty"`
+	}{toolValidationErrorKind, e.Field, e.Reason, e.Message})
+}
+
+// Result returns the tool result sent to the model for the error
+func (e *ToolValidationError) Result() string {
+	data, _ := json.Marshal(e)
+	return string(data)
+}
+
+// ParseToolValidationError parses a tool result produced by ToolValidationError.Result
+func ParseToolValidationError(result string) (*ToolValidationError, bool) {
+	var parsed struct {
+		Error   string `json:"error"`
+		Field   string `json:"field"`
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil || parsed.Error != toolValidationErrorKind {
+		return nil, false
+	}
+	return &ToolValidationError{Field: parsed.Field, Reason: parsed.Reason, Message: parsed.Message}, true
+}
+
+// ValidateToolArguments checks JSON tool call arguments against the parameters of a tool,
+// returning a *ToolValidationError for the first invalid field, in name order
+func ValidateToolArguments(parameters map[string]ParameterSpec, args string) error {
+	var values map[string]interface{}
+	if strings.TrimSpace(args) != "" {
+		if err := json.Unmarshal([]byte(args), &values); err != nil {
+			return &ToolValidationError{Reason: ValidationReasonInvalidJSON}
+		}
+	}
+	return validateProperties("", parameters, values)
+}
+
+// validateProperties validates the properties of an object value
+func validateProperties(path string, parameters map[string]ParameterSpec, values map[string]interface{}) error {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		spec := parameters[name]
+		field := name
+		if path != "" {
+			field = path + "." + name
+		}
+
+		value, ok := values[name]
+		if !ok || value == nil {
+			if spec.Required {
+				return &ToolValidationError{Field: field, Reason: ValidationReasonRequired}
+			}
+			continue
+		}
+		if err := validateValue(field, spec, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateValue validates a value against its parameter specification
+func validateValue(field string, spec ParameterSpec, value interface{}) error {
+	valid := true
+	switch spec.SchemaType() {
+	case "string":
+		_, valid = value.(string)
+	case "number":
+		_, valid = value.(float64)
+	case "integer":
+		number, ok := value.(float64)
+		valid = ok && number == math.Trunc(number)
+	case "boolean":
+		_, valid = value.(bool)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			valid = false
+			break
+		}
+		if spec.Items != nil {
+			for i, item := range items {
+				if err := validateValue(fmt.Sprintf("%s[%d]", field, i), *spec.Items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		properties, ok := value.(map[string]interface{})
+		if !ok {
+			valid = false
+			break
+		}
+		if err := validateProperties(field, spec.Properties, properties); err != nil {
+			return err
+		}
+	}
+	if !valid {
+		return &ToolValidationError{Field: field, Reason: ValidationReasonType}
+	}
+
+	if len(spec.Enum) > 0 {
+		for _, allowed := range spec.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				return nil
+			}
+		}
+		return &ToolValidationError{Field: field, Reason: ValidationReasonEnum}
+	}
+
+	return nil
+}
+
+// ValidatingTools wraps tools so that calls with invalid arguments are not executed, the
+// tool result being the JSON validation error instead
+func ValidatingTools(tools []Tool) []Tool {
+	wrapped := make([]Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &validatingTool{ToolWrapper: ToolWrapper{Tool: tool}}
+	}
+	return wrapped
+}
+
+// validatingTool validates the arguments of a tool before executing it
+type validatingTool struct {
+	ToolWrapper
+}
+
+// Run executes the tool with the given input
+func (t *validatingTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// Execute validates the arguments before executing the tool
+func (t *validatingTool) Execute(ctx context.Context, args string) (string, error) {
+	if err := ValidateToolArguments(t.Parameters(), args); err != nil {
+		return err.(*ToolValidationError).Result(), nil
+	}
+	return ExecuteTool(ctx, t.Tool, args)
+}
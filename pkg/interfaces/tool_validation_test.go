@@ -0,0 +1,104 @@
+package interfaces
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidateToolArguments(t *testing.T) {
+	parameters := map[string]ParameterSpec{
+		"location": {Type: "string", Required: true},
+		"days":     {Type: "integer"},
+		"unit":     {Type: "string", Enum: []interface{}{"celsius", "fahrenheit"}},
+		"tags":     {Type: "array", Items: &ParameterSpec{Type: "string"}},
+		"address": {
+			Properties: map[string]ParameterSpec{
+				"city": {Type: "string", Required: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		args     string
+		expected string
+	}{
+		{`{"location":"Paris"}`, ""},
+		{`{"location":"Paris","days":3,"unit":"celsius","tags":["a"],"address":{"city":"Paris"}}`, ""},
+		{`{}`, `{"error":"validation","field":"location","reason":"required"}`},
+		{``, `{"error":"validation","field":"location","reason":"required"}`},
+		{`{"location":null}`, `{"error":"validation","field":"location","reason":"required"}`},
+		{`{"location":42}`, `{"error":"validation","field":"location","reason":"type"}`},
+		{`{"location":"Paris","days":1.5}`, `{"error":"validation","field":"days","reason":"type"}`},
+		{`{"location":"Paris","unit":"kelvin"}`, `{"error":"validation","field":"unit","reason":"enum"}`},
+		{`{"location":"Paris","tags":["a",1]}`, `{"error":"validation","field":"tags[1]","reason":"type"}`},
+		{`{"location":"Paris","address":{}}`, `{"error":"validation","field":"address.city","reason":"required"}`},
+		{`{"location":`, `{"error":"validation","reason":"invalid_json"}`},
+	}
+
+	for _, tt := range tests {
+		err := ValidateToolArguments(parameters, tt.args)
+		if tt.expected == "" {
+			if err != nil {
+				t.Errorf("ValidateToolArguments(%s) returned %v", tt.args, err)
+			}
+			continue
+		}
+
+		validationErr, ok := err.(*ToolValidationError)
+		if !ok {
+			t.Errorf("ValidateToolArguments(%s) = %v, expected a validation error", tt.args, err)
+			continue
+		}
+		if result := validationErr.Result(); result != tt.expected {
+			t.Errorf("ValidateToolArguments(%s) result = %s, expected %s", tt.args, result, tt.expected)
+		}
+
+		parsed, ok := ParseToolValidationError(validationErr.Result())
+		if !ok || *parsed != *validationErr {
+			t.Errorf("Failed to parse %s back, got %v", validationErr.Result(), parsed)
+		}
+	}
+
+	if _, ok := ParseToolValidationError("Sunny, 24 degrees"); ok {
+		t.Error("Expected a regular tool result not to parse as a validation error")
+	}
+	if _, ok := ParseToolValidationError(`{"error":"timeout"}`); ok {
+		t.Error("Expected other errors not to parse as a validation error")
+	}
+}
+
+// validationTestTool counts its executions
+type validationTestTool struct {
+	executions int
+}
+
+func (t *validationTestTool) Name() string        { return "lookup" }
+func (t *validationTestTool) Description() string { return "Looks things up" }
+func (t *validationTestTool) Parameters() map[string]ParameterSpec {
+	return map[string]ParameterSpec{"query": {Type: "string", Required: true}}
+}
+func (t *validationTestTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *validationTestTool) Execute(ctx context.Context, args string) (string, error) {
+	t.executions++
+	return "found", nil
+}
+
+func TestValidatingTools(t *testing.T) {
+	tool := &validationTestTool{}
+	wrapped := ValidatingTools([]Tool{tool})[0]
+
+	result, err := wrapped.Execute(context.Background(), `{"query":1}`)
+	if err != nil || result != `{"error":"validation","field":"query","reason":"type"}` {
+		t.Errorf("Expected a validation error result, got %q (%v)", result, err)
+	}
+	if tool.executions != 0 {
+		t.Errorf("Expected an invalid call not to be executed")
+	}
+
+	result, err = wrapped.Execute(context.Background(), `{"query":"go"}`)
+	if err != nil || result != "found" || tool.executions != 1 {
+		t.Errorf("Expected a valid call to be executed, got %q (%v)", result, err)
+	}
+}
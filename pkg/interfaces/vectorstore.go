@@ -108,12 +108,21 @@ type SearchOptions struct {
 	// UseBM25 indicates whether to use BM25 search instead of vector search
 	UseBM25 bool
 
+	// KeywordFields are the properties searched by BM25 search. If empty, all text properties are searched
+	KeywordFields []string
+
 	// UseNearText indicates whether to use nearText search
 	UseNearText bool
 
 	// UseKeyword indicates whether to use keyword search
 	UseKeyword bool
 
+	// UseHybrid indicates whether to use hybrid search, fusing keyword (BM25) and vector search
+	UseHybrid bool
+
+	// HybridAlpha weights hybrid search from pure keyword search (0) to pure vector search (1)
+	HybridAlpha float64
+
 	// Tenant is the tenant name for native multi-tenancy
 	Tenant string
 
@@ -186,6 +195,16 @@ func WithBM25(useBM25 bool) SearchOption {
 	}
 }
 
+// WithKeywordSearch searches with BM25 keyword search over the given properties, or all text
+// properties if none, instead of vector search. The query is not embedded, which suits exact
+// lookups such as SKUs or error codes and avoids the cost of embeddings.
+func WithKeywordSearch(fields []string) SearchOption {
+	return func(o *SearchOptions) {
+		o.UseBM25 = true
+		o.KeywordFields = fields
+	}
+}
+
 // WithNearText sets whether to use nearText search
 func WithNearText(useNearText bool) SearchOption {
 	return func(o *SearchOptions) {
@@ -200,6 +219,16 @@ func WithKeyword(useKeyword bool) SearchOption {
 	}
 }
 
+// WithHybridSearch enables hybrid search, fusing the results of keyword (BM25) and vector search.
+// alpha weights the two, from 0 for pure keyword search to 1 for pure vector search; keyword
+// matching helps with exact terms such as names and codes that embeddings handle poorly.
+func WithHybridSearch(alpha float64) SearchOption {
+	return func(o *SearchOptions) {
+		o.UseHybrid = true
+		o.HybridAlpha = alpha
+	}
+}
+
 // WithTenantSearch sets the tenant for native multi-tenancy search operations
 func WithTenantSearch(tenant string) SearchOption {
 	return func(o *SearchOptions) {
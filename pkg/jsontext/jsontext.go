@@ -0,0 +1,161 @@
+// Package jsontext cleans up the JSON written by models: markdown code fences, text around the
+// JSON and common syntax mistakes.
+package jsontext
+
+import "strings"
+
+// StripCodeFences returns the content of the markdown code block a response starts with,
+// between the opening fence and the last closing fence, without the language tag of the
+// opening fence. Fences inside the content, e.g. in JSON string values, are kept. Responses not
+// starting with a complete code block are returned with surrounding whitespace trimmed, so that
+// fences inside unfenced JSON are left alone.
+func StripCodeFences(response string) string {
+	trimmed := strings.TrimSpace(response)
+	if !strings.HasPrefix(trimmed, "```") {
+		return trimmed
+	}
+
+	content := trimmed[len("```"):]
+	newline := strings.Index(content, "\n")
+	if newline < 0 {
+		return trimmed
+	}
+	// Drop the language tag, e.g. ```json or ```yaml
+	content = content[newline+1:]
+
+	end := strings.LastIndex(content, "```")
+	if end < 0 {
+		return trimmed
+	}
+	return strings.TrimSpace(content[:end])
+}
+
+// Extract returns the first JSON object or array of text, e.g. of a model response wrapping it in
+// a markdown code block or in explanations, and whether there is one. Brackets inside string
+// values are ignored when looking for the end of the value. A value that is not closed is
+// returned up to the end of the text.
+func Extract(text string) (string, bool) {
+	return extract(text, "{[")
+}
+
+// ExtractObject is Extract for text expected to hold a JSON object: brackets before the object,
+// e.g. in a "[1/2]" or "[note]" prefix of the response, are skipped.
+func ExtractObject(text string) (string, bool) {
+	return extract(text, "{")
+}
+
+// extract returns the JSON value of text starting at the first of the opening characters
+func extract(text, opening string) (string, bool) {
+	text = StripCodeFences(text)
+
+	start := strings.IndexAny(text, opening)
+	if start < 0 {
+		return "", false
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for i := start; i < len(text); i++ {
+		c := text[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return text[start : i+1], true
+			}
+		}
+	}
+	return text[start:], true
+}
+
+// Repair rewrites text holding a JSON object or array into valid JSON, fixing the syntax
+// mistakes of models: a markdown code fence or text around the JSON, single-quoted strings,
+// raw newlines in strings and trailing commas. Commas and quotes inside string values are left
+// as they are. Unclosed strings, arrays and objects, e.g. of a truncated response, are not
+// closed, since the content is incomplete; the result then fails to parse.
+func Repair(text string) string {
+	text = StripCodeFences(text)
+
+	// Drop any text before the JSON
+	if start := strings.IndexAny(text, "{["); start >= 0 {
+		text = text[start:]
+	}
+
+	var out strings.Builder
+	depth := 0
+	var quote byte // quote of the current string, 0 outside strings
+	escaped := false
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+				out.WriteByte(c)
+			case c == '\\':
+				escaped = true
+				out.WriteByte(c)
+			case c == quote:
+				quote = 0
+				out.WriteByte('"')
+			case c == '"':
+				// A double quote inside a single-quoted string
+				out.WriteString(`\"`)
+			case c == '\n':
+				out.WriteString(`\n`)
+			default:
+				out.WriteByte(c)
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '\'':
+			quote = c
+			out.WriteByte('"')
+		case '{', '[':
+			depth++
+			out.WriteByte(c)
+		case '}', ']':
+			trimTrailingComma(&out)
+			depth--
+			out.WriteByte(c)
+			if depth == 0 {
+				// Ignore any text after the JSON
+				return out.String()
+			}
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// trimTrailingComma removes a comma, and the whitespace after it, ending the output
+func trimTrailingComma(out *strings.Builder) {
+	text := strings.TrimRight(out.String(), " \t\r\n")
+	if strings.HasSuffix(text, ",") {
+		out.Reset()
+		out.WriteString(strings.TrimSuffix(text, ","))
+	}
+}
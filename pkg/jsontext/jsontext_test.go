@@ -0,0 +1,121 @@
+package jsontext
+
+import "testing"
+
+func TestRepair(t *testing.T) {
+	tests := map[string]string{
+		`{"a":1}`:                            `{"a":1}`,
+		"```json\n[1,2,]\n```":               `[1,2]`,
+		`Result: {'a':'b'} as requested`:     `{"a":"b"}`,
+		`{"text":"x,}","list":["y,]",],}`:    `{"text":"x,}","list":["y,]"]}`,
+		`{"code":"` + "```go```" + `"}`:      `{"code":"` + "```go```" + `"}`,
+		`{"path":"/etc/pas`:                  `{"path":"/etc/pas`,
+		`{"filter":{"lang":"en"`:             `{"filter":{"lang":"en"`,
+		`{"note":"it's"} and {"other":true}`: `{"note":"it's"}`,
+	}
+	for input, expected := range tests {
+		if got := Repair(input); got != expected {
+			t.Errorf("Repair(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		found    bool
+	}{
+		{
+			name:     "JSON in markdown code block",
+			input:    "```json\n{\n  \"action\": \"create_new_task\",\n  \"reasoning\": [\n    \"User requested 'deploy eks' which is a clear deployment request\"\n  ]\n}\n```",
+			expected: "{\n  \"action\": \"create_new_task\",\n  \"reasoning\": [\n    \"User requested 'deploy eks' which is a clear deployment request\"\n  ]\n}",
+			found:    true,
+		},
+		{
+			name:     "JSON in generic code block",
+			input:    "```\n{\"test\": \"value\"}\n```",
+			expected: `{"test": "value"}`,
+			found:    true,
+		},
+		{
+			name:     "plain JSON",
+			input:    `{"test": "value"}`,
+			expected: `{"test": "value"}`,
+			found:    true,
+		},
+		{
+			name:     "JSON with text before and after",
+			input:    `Here is the response: {"test": "value"} and that's it.`,
+			expected: `{"test": "value"}`,
+			found:    true,
+		},
+		{
+			name:     "brackets in strings",
+			input:    `Result: {"text": "a } and a ]", "list": ["[x]"]} (see {note})`,
+			expected: `{"text": "a } and a ]", "list": ["[x]"]}`,
+			found:    true,
+		},
+		{
+			name:     "escaped quote in string",
+			input:    `{"text": "say \"}\" twice"} done`,
+			expected: `{"text": "say \"}\" twice"}`,
+			found:    true,
+		},
+		{
+			name:     "array",
+			input:    `Items: [1, {"a": [2]}] end`,
+			expected: `[1, {"a": [2]}]`,
+			found:    true,
+		},
+		{
+			name:     "unclosed",
+			input:    `{"path": "/etc`,
+			expected: `{"path": "/etc`,
+			found:    true,
+		},
+		{
+			name:  "empty string",
+			input: "",
+		},
+		{
+			name:  "no JSON",
+			input: "This is just text without JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := Extract(tt.input)
+			if got != tt.expected || found != tt.found {
+				t.Errorf("Extract(%q) = %q, %v, want %q, %v", tt.input, got, found, tt.expected, tt.found)
+			}
+		})
+	}
+}
+
+func TestExtractObject(t *testing.T) {
+	input := `[Step 1/2] The decision is {"agent_id": "billing", "tags": ["refund"]} as requested`
+	if got, found := ExtractObject(input); got != `{"agent_id": "billing", "tags": ["refund"]}` || !found {
+		t.Errorf("ExtractObject(%q) = %q, %v", input, got, found)
+	}
+
+	if got, found := ExtractObject(`Items: [1, 2]`); found {
+		t.Errorf("Expected no object in an array, got %q", got)
+	}
+}
+
+func TestStripCodeFences(t *testing.T) {
+	tests := map[string]string{
+		"```json\n{\"a\": 1}\n```":                              `{"a": 1}`,
+		"\n ```\n[1]\n```\n":                                    `[1]`,
+		`{"start": "` + "```" + `go", "end": "x` + "```" + `"}`: `{"start": "` + "```" + `go", "end": "x` + "```" + `"}`,
+		"Here you go:\n```json\n{}\n```":                        "Here you go:\n```json\n{}\n```",
+	}
+	for input, expected := range tests {
+		if got := StripCodeFences(input); got != expected {
+			t.Errorf("StripCodeFences(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/jsontext"
 	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
@@ -27,6 +29,7 @@ type AnthropicClient struct {
 	retryExecutor       *retry.Executor
 	vertexRetryExecutor *VertexRetryExecutor
 	VertexConfig        *VertexConfig
+	completionLogLevel  string
 }
 
 // Option represents an option for configuring the Anthropic client
@@ -85,6 +88,14 @@ func WithRetry(opts ...retry.Option) Option {
 	}
 }
 
+// WithCompletionLogLevel sets the level of the summary logged after every API call
+// ("debug", "info", "warn", "error" or "none" to disable it; default: "info")
+func WithCompletionLogLevel(level string) Option {
+	return func(c *AnthropicClient) {
+		c.completionLogLevel = level
+	}
+}
+
 // WithBaseURL sets the base URL for the Anthropic API
 func WithBaseURL(baseURL string) Option {
 	return func(c *AnthropicClient) {
@@ -251,6 +262,72 @@ func SupportsThinking(model string) bool {
 	return false
 }
 
+// formatAssistantTurn renders an assistant turn for the text-only message format.
+// Tool calls are included so that tool-only turns are kept in the outbound history
+// instead of leaving consecutive user messages behind.
+func formatAssistantTurn(text string, toolCalls []interfaces.ToolCall) string {
+	parts := []string{}
+	if strings.TrimSpace(text) != "" {
+		parts = append(parts, text)
+	}
+	for _, toolCall := range toolCalls {
+		arguments := toolCall.Arguments
+		if arguments == "" {
+			arguments = "{}"
+		}
+		parts = append(parts, fmt.Sprintf("[Called tool %s with arguments: %s]", toolCall.Name, arguments))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// minThinkingBudget is the smallest thinking budget accepted by the Anthropic API
+const minThinkingBudget = 1024
+
+// validateThinkingBudget returns an error if a thinking budget is requested for a model without
+// thinking support, or is below the minimum of the API
+func (c *AnthropicClient) validateThinkingBudget(config *interfaces.LLMConfig) error {
+	if config == nil || config.ThinkingBudget <= 0 {
+		return nil
+	}
+	if !SupportsThinking(c.Model) {
+		return fmt.Errorf("thinking budget requested but model %s does not support thinking", c.Model)
+	}
+	if config.ThinkingBudget < minThinkingBudget {
+		return fmt.Errorf("thinking budget must be at least %d tokens, got %d", minThinkingBudget, config.ThinkingBudget)
+	}
+	return nil
+}
+
+// defaultMaxTokens is the output token limit of requests without WithMaxTokens
+const defaultMaxTokens = 2048
+
+// requestMaxTokens returns the output token limit of a request
+func requestMaxTokens(config *interfaces.LLMConfig) int {
+	if config != nil && config.MaxTokens > 0 {
+		return config.MaxTokens
+	}
+	return defaultMaxTokens
+}
+
+// applyThinkingBudget enables thinking with a bounded budget on the request.
+// The budget is added on top of max_tokens so thinking cannot consume the output allowance.
+func applyThinkingBudget(req *CompletionRequest, config *interfaces.LLMConfig) {
+	if config == nil || config.ThinkingBudget <= 0 {
+		return
+	}
+	if req.Thinking != nil && req.Thinking.BudgetTokens > 0 {
+		// Remove a previously reserved reasoning budget before applying the explicit one
+		req.MaxTokens -= req.Thinking.BudgetTokens
+	}
+	req.Thinking = &ReasoningSpec{
+		Type:         "enabled",
+		BudgetTokens: config.ThinkingBudget,
+	}
+	req.MaxTokens += config.ThinkingBudget
+	// Anthropic requires temperature = 1.0 when thinking is enabled
+	req.Temperature = 1.0
+}
+
 // Message represents a message for Anthropic API
 type Message struct {
 	Role    string `json:"role"`
@@ -307,9 +384,10 @@ type Tool struct {
 
 // ContentBlock represents a content block in Anthropic API response
 type ContentBlock struct {
-	Type    string   `json:"type"`
-	Text    string   `json:"text,omitempty"`
-	ToolUse *ToolUse `json:"tool_use,omitempty"`
+	Type     string   `json:"type"`
+	Text     string   `json:"text,omitempty"`
+	Thinking string   `json:"thinking,omitempty"`
+	ToolUse  *ToolUse `json:"tool_use,omitempty"`
 	// Vertex AI direct fields for tool_use blocks
 	ID    string                 `json:"id,omitempty"`
 	Name  string                 `json:"name,omitempty"`
@@ -365,6 +443,27 @@ func (c *AnthropicClient) Generate(ctx context.Context, prompt string, options .
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("Anthropic", params.Files); err != nil {
+		return "", err
+	}
+	if err := interfaces.RejectImages("Anthropic", params.Images); err != nil {
+		return "", err
+	}
+
+	if err := c.validateThinkingBudget(params.LLMConfig); err != nil {
+		return "", err
+	}
+
+	if params.OutputRepair > 0 && params.ResponseFormat != nil {
+		return llm.RepairStructuredOutput(ctx, params, prompt, func(ctx context.Context, prompt string) (string, error) {
+			response, err := c.Generate(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStructuredOutputRepair(0))...)
+			if err != nil {
+				return "", err
+			}
+			return extractJSONFromResponse(response), nil
+		})
+	}
+
 	// Check for organization ID in context, and add a default one if missing
 	defaultOrgID := "default"
 	if id, err := multitenancy.GetOrgID(ctx); err == nil {
@@ -430,7 +529,7 @@ CRITICAL INSTRUCTIONS:
 	req := CompletionRequest{
 		Model:       c.Model,
 		Messages:    messages,
-		MaxTokens:   2048,
+		MaxTokens:   requestMaxTokens(params.LLMConfig),
 		Temperature: params.LLMConfig.Temperature,
 		TopP:        params.LLMConfig.TopP,
 	}
@@ -461,10 +560,15 @@ CRITICAL INSTRUCTIONS:
 		}
 	}
 
+	applyThinkingBudget(&req, params.LLMConfig)
+
 	var resp CompletionResponse
 	var err error
 
+	start := time.Now()
+	attempts := 0
 	operation := func() error {
+		attempts++
 		var apiType string
 		if c.VertexConfig != nil && c.VertexConfig.Enabled {
 			apiType = "Vertex AI"
@@ -531,6 +635,7 @@ CRITICAL INSTRUCTIONS:
 			})
 			return fmt.Errorf("failed to send request: %w", err)
 		}
+		recordRateLimits(ctx, httpResp)
 		defer func() {
 			if closeErr := httpResp.Body.Close(); closeErr != nil {
 				c.logger.Warn(ctx, "Failed to close response body", map[string]interface{}{
@@ -552,7 +657,7 @@ CRITICAL INSTRUCTIONS:
 				"response":    string(respBody),
 				"model":       c.Model,
 			})
-			return fmt.Errorf("error from Anthropic API: %s", string(respBody))
+			return classifyStatus(httpResp.StatusCode, fmt.Errorf("error from Anthropic API: %s", string(respBody)))
 		}
 
 		// Unmarshal response
@@ -564,24 +669,25 @@ CRITICAL INSTRUCTIONS:
 		return nil
 	}
 
-	if c.vertexRetryExecutor != nil {
+	if c.vertexRetryExecutor != nil && !params.NoRetry {
 		c.logger.Info(ctx, "Using Vertex retry mechanism with region rotation", map[string]interface{}{
 			"model":          c.Model,
 			"current_region": c.VertexConfig.GetCurrentRegion(),
 		})
-		err = c.vertexRetryExecutor.Execute(ctx, operation)
-	} else if c.retryExecutor != nil {
+		err = c.vertexRetryExecutor.ExecuteWithMaxAttempts(ctx, int32(params.MaxRetries), operation)
+	} else if c.retryExecutor != nil && !params.NoRetry {
 		c.logger.Info(ctx, "Using standard retry mechanism for Anthropic request", map[string]interface{}{
 			"model":                   c.Model,
 			"vertex_config_available": c.VertexConfig != nil,
 		})
-		err = c.retryExecutor.Execute(ctx, operation)
+		err = c.retryExecutor.ExecuteWithMaxAttempts(ctx, int32(params.MaxRetries), operation)
 	} else {
 		c.logger.Debug(ctx, "No retry mechanism configured", map[string]interface{}{
 			"model": c.Model,
 		})
 		err = operation()
 	}
+	c.logCompletion(ctx, start, attempts, &resp, err)
 
 	if err != nil {
 		return "", err
@@ -606,6 +712,10 @@ CRITICAL INSTRUCTIONS:
 		response = "{" + response
 	}
 
+	if params.ResponseFormat == nil {
+		response = c.applyThinkingPolicy(ctx, params.ThinkingPolicy, resp.Content, response)
+	}
+
 	c.logger.Debug(ctx, "Successfully received response from Anthropic", map[string]interface{}{
 		"model":             c.Model,
 		"structured_output": params.ResponseFormat != nil,
@@ -671,12 +781,16 @@ func (c *AnthropicClient) Chat(ctx context.Context, messages []llm.Message, para
 	req := CompletionRequest{
 		Model:         c.Model,
 		Messages:      filteredMessages,
-		MaxTokens:     2048,
+		MaxTokens:     defaultMaxTokens,
 		Temperature:   params.Temperature,
 		TopP:          params.TopP,
 		StopSequences: params.StopSequences,
 	}
 
+	if params.MaxTokens > 0 {
+		req.MaxTokens = params.MaxTokens
+	}
+
 	// Add system message if available
 	if systemMessage != "" {
 		req.System = systemMessage
@@ -690,7 +804,10 @@ func (c *AnthropicClient) Chat(ctx context.Context, messages []llm.Message, para
 	var resp CompletionResponse
 	var err error
 
+	start := time.Now()
+	attempts := 0
 	operation := func() error {
+		attempts++
 		var apiType string
 		if c.VertexConfig != nil && c.VertexConfig.Enabled {
 			apiType = "Vertex AI"
@@ -748,6 +865,7 @@ func (c *AnthropicClient) Chat(ctx context.Context, messages []llm.Message, para
 			})
 			return fmt.Errorf("failed to send request: %w", err)
 		}
+		recordRateLimits(ctx, httpResp)
 		defer func() {
 			if closeErr := httpResp.Body.Close(); closeErr != nil {
 				c.logger.Warn(ctx, "Failed to close response body", map[string]interface{}{
@@ -769,7 +887,7 @@ func (c *AnthropicClient) Chat(ctx context.Context, messages []llm.Message, para
 				"response":    string(respBody),
 				"model":       c.Model,
 			})
-			return fmt.Errorf("error from Anthropic API: %s", string(respBody))
+			return classifyStatus(httpResp.StatusCode, fmt.Errorf("error from Anthropic API: %s", string(respBody)))
 		}
 
 		// Log raw response before unmarshaling for debugging
@@ -826,6 +944,7 @@ func (c *AnthropicClient) Chat(ctx context.Context, messages []llm.Message, para
 		})
 		err = operation()
 	}
+	c.logCompletion(ctx, start, attempts, &resp, err)
 
 	if err != nil {
 		return "", err
@@ -878,6 +997,31 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 		}
 	}
 
+	if err := interfaces.RejectFiles("Anthropic", params.Files); err != nil {
+		return "", err
+	}
+	if err := interfaces.RejectImages("Anthropic", params.Images); err != nil {
+		return "", err
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
+	if err := c.validateThinkingBudget(params.LLMConfig); err != nil {
+		return "", err
+	}
+
 	// Set default max iterations if not provided
 	maxIterations := params.MaxIterations
 	if maxIterations == 0 {
@@ -897,45 +1041,10 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 	// Convert tools to Anthropic format
 	anthropicTools := make([]Tool, len(tools))
 	for i, tool := range tools {
-		// Convert ParameterSpec to JSON Schema
-		properties := make(map[string]interface{})
-		required := []string{}
-
-		for name, param := range tool.Parameters() {
-			properties[name] = map[string]interface{}{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				properties[name].(map[string]interface{})["default"] = param.Default
-			}
-			if param.Required {
-				required = append(required, name)
-			}
-			if param.Items != nil {
-				properties[name].(map[string]interface{})["items"] = map[string]interface{}{
-					"type": param.Items.Type,
-				}
-				if param.Items.Enum != nil {
-					properties[name].(map[string]interface{})["items"].(map[string]interface{})["enum"] = param.Items.Enum
-				}
-			}
-			if param.Enum != nil {
-				properties[name].(map[string]interface{})["enum"] = param.Enum
-			}
-		}
-
-		// Create the input schema for this tool
-		inputSchema := map[string]interface{}{
-			"type":       "object",
-			"properties": properties,
-			"required":   required,
-		}
-
 		anthropicTools[i] = Tool{
 			Name:        tool.Name(),
 			Description: tool.Description(),
-			InputSchema: inputSchema,
+			InputSchema: convertToAnthropicSchema(tool.Parameters()),
 		}
 	}
 
@@ -956,14 +1065,15 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 		req := CompletionRequest{
 			Model:       c.Model,
 			Messages:    messages,
-			MaxTokens:   2048,
+			MaxTokens:   requestMaxTokens(params.LLMConfig),
 			Temperature: params.LLMConfig.Temperature,
 			TopP:        params.LLMConfig.TopP,
 			Tools:       anthropicTools,
-			// Auto use tools when needed
-			ToolChoice: map[string]string{
-				"type": "auto",
-			},
+			ToolChoice:  toolChoice(params.ToolChoiceForIteration(iteration)),
+		}
+
+		if len(params.LLMConfig.StopSequences) > 0 {
+			req.StopSequences = params.LLMConfig.StopSequences
 		}
 
 		// Add system message if available
@@ -986,6 +1096,8 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 			c.logger.Debug(ctx, "Reasoning mode not supported in current API version", map[string]interface{}{"reasoning": params.LLMConfig.Reasoning})
 		}
 
+		applyThinkingBudget(&req, params.LLMConfig)
+
 		// Send request
 		c.logger.Debug(ctx, "Sending request with tools to Anthropic", map[string]interface{}{
 			"model":         c.Model,
@@ -1002,7 +1114,10 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 		var err error
 
 		// Define operation for retry mechanism
+		start := time.Now()
+		attempts := 0
 		operation := func() error {
+			attempts++
 			// Create HTTP request (supports both Vertex AI and standard Anthropic API)
 			httpReq, err := c.createHTTPRequest(ctx, &req, "/v1/messages")
 			if err != nil {
@@ -1019,6 +1134,7 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 				})
 				return fmt.Errorf("failed to send request (iteration %d): %w", iteration+1, err)
 			}
+			recordRateLimits(ctx, httpResp)
 			defer func() {
 				if closeErr := httpResp.Body.Close(); closeErr != nil {
 					c.logger.Warn(ctx, "Failed to close response body", map[string]interface{}{
@@ -1041,7 +1157,7 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 					"model":       c.Model,
 					"iteration":   iteration + 1,
 				})
-				return fmt.Errorf("error from Anthropic API (iteration %d): %s", iteration+1, string(respBody))
+				return classifyStatus(httpResp.StatusCode, fmt.Errorf("error from Anthropic API (iteration %d): %s", iteration+1, string(respBody)))
 			}
 
 			// Log raw response before unmarshaling for debugging
@@ -1072,20 +1188,20 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 		}
 
 		// Execute operation with retry mechanism
-		if c.vertexRetryExecutor != nil {
+		if c.vertexRetryExecutor != nil && !params.NoRetry {
 			c.logger.Info(ctx, "Using Vertex retry mechanism with region rotation for GenerateWithTools", map[string]interface{}{
 				"model":          c.Model,
 				"current_region": c.VertexConfig.GetCurrentRegion(),
 				"iteration":      iteration + 1,
 			})
-			err = c.vertexRetryExecutor.Execute(ctx, operation)
-		} else if c.retryExecutor != nil {
+			err = c.vertexRetryExecutor.ExecuteWithMaxAttempts(ctx, int32(params.MaxRetries), operation)
+		} else if c.retryExecutor != nil && !params.NoRetry {
 			c.logger.Info(ctx, "Using standard retry mechanism for GenerateWithTools", map[string]interface{}{
 				"model":                   c.Model,
 				"vertex_config_available": c.VertexConfig != nil,
 				"iteration":               iteration + 1,
 			})
-			err = c.retryExecutor.Execute(ctx, operation)
+			err = c.retryExecutor.ExecuteWithMaxAttempts(ctx, int32(params.MaxRetries), operation)
 		} else {
 			c.logger.Debug(ctx, "No retry mechanism configured for GenerateWithTools", map[string]interface{}{
 				"model":     c.Model,
@@ -1093,6 +1209,7 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 			})
 			err = operation()
 		}
+		c.logCompletion(ctx, start, attempts, &resp, err)
 
 		if err != nil {
 			return "", err
@@ -1182,6 +1299,10 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 				}
 			}
 
+			if params.ResponseFormat == nil {
+				response = c.applyThinkingPolicy(ctx, params.ThinkingPolicy, resp.Content, response)
+			}
+
 			c.logger.Debug(ctx, "Returning final response (no tool use)", map[string]interface{}{
 				"response_length": len(response),
 				"response_preview": func() string {
@@ -1200,15 +1321,29 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 			"iteration": iteration + 1,
 		})
 
-		// Add the assistant response to messages only if there's text content
-		// (Tool-only responses will have empty text content)
-		assistantContent := strings.Join(textContent, "\n")
-		if strings.TrimSpace(assistantContent) != "" {
-			messages = append(messages, Message{
-				Role:    "assistant",
-				Content: assistantContent,
+		// Add the assistant turn to messages, including its tool calls so that
+		// tool-only responses (with empty text content) are preserved
+		assistantToolCalls := make([]interfaces.ToolCall, 0, len(toolCalls))
+		for _, toolCall := range toolCalls {
+			name := toolCall.Name
+			if name == "" {
+				name = toolCall.RecipientName
+			}
+			input := toolCall.Input
+			if len(input) == 0 {
+				input = toolCall.Parameters
+			}
+			arguments, _ := json.Marshal(input)
+			assistantToolCalls = append(assistantToolCalls, interfaces.ToolCall{
+				ID:        toolCall.ID,
+				Name:      name,
+				Arguments: string(arguments),
 			})
 		}
+		messages = append(messages, Message{
+			Role:    "assistant",
+			Content: formatAssistantTurn(strings.Join(textContent, "\n"), assistantToolCalls),
+		})
 
 		// Process each tool call
 		var toolResults []ToolResult
@@ -1311,7 +1446,7 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 				"toolName":  selectedTool.Name(),
 				"iteration": iteration + 1,
 			})
-			toolResult, err := selectedTool.Execute(ctx, string(toolCallJSON))
+			toolResult, err := interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), selectedTool, string(toolCallJSON))
 
 			// Check for repetitive calls and add warning if needed
 			cacheKey := toolName + ":" + string(toolCallJSON)
@@ -1422,7 +1557,7 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 	finalReq := CompletionRequest{
 		Model:       c.Model,
 		Messages:    messages,
-		MaxTokens:   2048,
+		MaxTokens:   requestMaxTokens(params.LLMConfig),
 		Temperature: params.LLMConfig.Temperature,
 		TopP:        params.LLMConfig.TopP,
 		Tools:       nil, // No tools for final call
@@ -1487,6 +1622,7 @@ CRITICAL INSTRUCTIONS:
 	}
 
 	finalReq.Messages = messages
+	applyThinkingBudget(&finalReq, params.LLMConfig)
 
 	c.logger.Debug(ctx, "Making final request without tools", map[string]interface{}{
 		"messages": len(finalReq.Messages),
@@ -1499,11 +1635,14 @@ CRITICAL INSTRUCTIONS:
 	}
 
 	// Send final request
+	start := time.Now()
 	finalHTTPResp, err := c.HTTPClient.Do(finalHTTPReq)
 	if err != nil {
+		c.logCompletion(ctx, start, 1, nil, err)
 		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
 		return "", fmt.Errorf("failed to send final request: %w", err)
 	}
+	recordRateLimits(ctx, finalHTTPResp)
 	defer func() {
 		if closeErr := finalHTTPResp.Body.Close(); closeErr != nil {
 			c.logger.Warn(ctx, "Failed to close final response body", map[string]interface{}{
@@ -1524,7 +1663,9 @@ CRITICAL INSTRUCTIONS:
 			"status_code": finalHTTPResp.StatusCode,
 			"response":    string(finalRespBody),
 		})
-		return "", fmt.Errorf("error from Anthropic API in final call: %s", string(finalRespBody))
+		err = fmt.Errorf("error from Anthropic API in final call: %s", string(finalRespBody))
+		c.logCompletion(ctx, start, 1, nil, err)
+		return "", err
 	}
 
 	// Log raw final response before unmarshaling for debugging
@@ -1547,6 +1688,7 @@ CRITICAL INSTRUCTIONS:
 	// Unmarshal final response
 	var finalResp CompletionResponse
 	err = json.Unmarshal(finalRespBody, &finalResp)
+	c.logCompletion(ctx, start, 1, &finalResp, err)
 	if err != nil {
 		c.logger.Error(ctx, "Failed to unmarshal final response", map[string]interface{}{
 			"error":           err.Error(),
@@ -1596,6 +1738,10 @@ CRITICAL INSTRUCTIONS:
 		}
 	}
 
+	if params.ResponseFormat == nil {
+		response = c.applyThinkingPolicy(ctx, params.ThinkingPolicy, finalResp.Content, response)
+	}
+
 	c.logger.Info(ctx, "Successfully received final response without tools", map[string]interface{}{
 		"response_length": len(response),
 		"response_preview": func() string {
@@ -1607,6 +1753,76 @@ CRITICAL INSTRUCTIONS:
 	return response, nil
 }
 
+// applyThinkingPolicy prepends the thinking blocks of a response to the answer when the policy keeps thinking content
+func (c *AnthropicClient) applyThinkingPolicy(ctx context.Context, policy interfaces.ThinkingPolicy, blocks []ContentBlock, response string) string {
+	if !policy.IncludesThinking() {
+		return response
+	}
+
+	var thinking []string
+	for _, block := range blocks {
+		if block.Type == "thinking" && block.Thinking != "" {
+			thinking = append(thinking, block.Thinking)
+		}
+	}
+	if len(thinking) == 0 {
+		return response
+	}
+
+	c.logger.Debug(ctx, "Received thinking content", map[string]interface{}{
+		"model":    c.Model,
+		"policy":   string(policy),
+		"thinking": strings.Join(thinking, "\n"),
+	})
+
+	return interfaces.FormatThinking(strings.Join(thinking, "\n"), response)
+}
+
+// classifyStatus marks the error of an API response with a client error status as permanent,
+// so that it is neither retried nor counted as a failure by the circuit breaker
+func classifyStatus(statusCode int, err error) error {
+	if retry.IsClientError(statusCode) {
+		return retry.Permanent(err)
+	}
+	return err
+}
+
+// logCompletion logs the completion summary of a Messages API request
+func (c *AnthropicClient) logCompletion(ctx context.Context, start time.Time, attempts int, resp *CompletionResponse, err error) {
+	summary := llm.CompletionSummary{
+		Provider: c.Name(),
+		Model:    c.Model,
+		Latency:  time.Since(start),
+		Err:      err,
+	}
+	if attempts > 1 {
+		summary.Retries = attempts - 1
+	}
+	if resp != nil {
+		summary.PromptTokens = int64(resp.Usage.InputTokens)
+		summary.ResponseTokens = int64(resp.Usage.OutputTokens)
+		if resp.StopReason != "" {
+			summary.FinishReason = normalizeStopReason(resp.StopReason)
+		}
+	}
+	llm.LogCompletion(ctx, c.logger, c.completionLogLevel, summary)
+}
+
+// normalizeStopReason maps a stop reason of the Messages API to its normalized form
+func normalizeStopReason(reason string) interfaces.FinishReason {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return interfaces.FinishReasonStop
+	case "max_tokens", "model_context_window_exceeded":
+		return interfaces.FinishReasonLength
+	case "tool_use":
+		return interfaces.FinishReasonToolUse
+	case "refusal":
+		return interfaces.FinishReasonContentFilter
+	}
+	return interfaces.FinishReasonUnknown
+}
+
 // createHTTPRequest creates an HTTP request for either Vertex AI or standard Anthropic API
 func (c *AnthropicClient) createHTTPRequest(ctx context.Context, req *CompletionRequest, path string) (*http.Request, error) {
 	if c.VertexConfig != nil && c.VertexConfig.Enabled {
@@ -1683,6 +1899,11 @@ func (c *AnthropicClient) Name() string {
 	return "anthropic"
 }
 
+// GetModel returns the model name being used
+func (c *AnthropicClient) GetModel() string {
+	return c.Model
+}
+
 // SupportsStreaming implements interfaces.LLM.SupportsStreaming
 func (c *AnthropicClient) SupportsStreaming() bool {
 	return true
@@ -1723,6 +1944,17 @@ func WithStopSequences(stopSequences []string) interfaces.GenerateOption {
 	}
 }
 
+// WithMaxTokens creates a GenerateOption to set the maximum number of output tokens.
+// When unset, requests are limited to 2048 output tokens.
+func WithMaxTokens(maxTokens int) interfaces.GenerateOption {
+	return func(options *interfaces.GenerateOptions) {
+		if options.LLMConfig == nil {
+			options.LLMConfig = &interfaces.LLMConfig{}
+		}
+		options.LLMConfig.MaxTokens = maxTokens
+	}
+}
+
 // WithSystemMessage creates a GenerateOption to set the system message
 func WithSystemMessage(systemMessage string) interfaces.GenerateOption {
 	return func(options *interfaces.GenerateOptions) {
@@ -1803,85 +2035,73 @@ func getExampleValue(prop map[string]interface{}) interface{} {
 	}
 }
 
-// extractJSONFromResponse extracts JSON content from a response that may contain markdown or explanatory text
+// extractJSONFromResponse extracts the JSON object of a response that may contain markdown or explanatory text
 func extractJSONFromResponse(response string) string {
-	// First, try to find JSON within markdown code blocks
-	jsonStart := strings.Index(response, "```json")
-	if jsonStart >= 0 {
-		jsonStart += len("```json")
-		jsonEnd := strings.Index(response[jsonStart:], "```")
-		if jsonEnd > 0 {
-			return strings.TrimSpace(response[jsonStart : jsonStart+jsonEnd])
-		}
+	if extracted, ok := jsontext.ExtractObject(response); ok {
+		return extracted
 	}
+	return response
+}
 
-	// Try generic code blocks
-	jsonStart = strings.Index(response, "```")
-	if jsonStart >= 0 {
-		jsonStart += len("```")
-		contentAfterMarker := response[jsonStart:]
-		newlineIdx := strings.Index(contentAfterMarker, "\n")
-		if newlineIdx >= 0 {
-			contentAfterMarker = contentAfterMarker[newlineIdx+1:]
-		}
-		jsonEnd := strings.Index(contentAfterMarker, "```")
-		if jsonEnd > 0 {
-			extracted := strings.TrimSpace(contentAfterMarker[:jsonEnd])
-			if isValidJSONStart(extracted) {
-				return extracted
-			}
-		}
+// toolChoice converts a tool choice to the Anthropic format, defaulting to auto
+func toolChoice(choice *interfaces.ToolChoice) map[string]string {
+	if choice == nil {
+		return map[string]string{"type": "auto"}
 	}
 
-	// Try to find JSON object by looking for { and matching }
-	jsonStart = strings.Index(response, "{")
-	if jsonStart >= 0 {
-		// Find the matching closing brace
-		braceCount := 0
-		inString := false
-		escapeNext := false
-
-		for i := jsonStart; i < len(response); i++ {
-			char := response[i]
-
-			if escapeNext {
-				escapeNext = false
-				continue
-			}
-
-			if char == '\\' {
-				escapeNext = true
-				continue
-			}
+	switch choice.Mode {
+	case interfaces.ToolChoiceNone:
+		return map[string]string{"type": "none"}
+	case interfaces.ToolChoiceRequired:
+		return map[string]string{"type": "any"}
+	case interfaces.ToolChoiceTool:
+		return map[string]string{"type": "tool", "name": choice.Name}
+	default:
+		return map[string]string{"type": "auto"}
+	}
+}
 
-			if char == '"' {
-				inString = !inString
-				continue
-			}
+// convertToAnthropicSchema converts tool parameters to an Anthropic input schema
+func convertToAnthropicSchema(params map[string]interfaces.ParameterSpec) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
 
-			if !inString {
-				if char == '{' {
-					braceCount++
-				} else if char == '}' {
-					braceCount--
-					if braceCount == 0 {
-						extracted := strings.TrimSpace(response[jsonStart : i+1])
-						if isValidJSONStart(extracted) {
-							return extracted
-						}
-						break
-					}
-				}
-			}
+	for name, param := range params {
+		properties[name] = convertParameterToAnthropicSchema(param)
+		if param.Required {
+			required = append(required, name)
 		}
 	}
+	sort.Strings(required)
 
-	// If no JSON found, return original response
-	return response
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
 }
 
-// isValidJSONStart checks if a string starts with valid JSON
-func isValidJSONStart(s string) bool {
-	s = strings.TrimSpace(s)
-	return strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")
+// convertParameterToAnthropicSchema converts a single parameter to JSON schema, including nested array items and object properties
+func convertParameterToAnthropicSchema(param interfaces.ParameterSpec) map[string]interface{} {
+	property := map[string]interface{}{
+		"type": param.SchemaType(),
+	}
+	if param.Description != "" {
+		property["description"] = param.Description
+	}
+	if param.Default != nil {
+		property["default"] = param.Default
+	}
+	if param.Items != nil {
+		property["items"] = convertParameterToAnthropicSchema(*param.Items)
+	}
+	if param.Enum != nil {
+		property["enum"] = param.Enum
+	}
+	if param.Properties != nil {
+		nested := convertToAnthropicSchema(param.Properties)
+		property["properties"] = nested["properties"]
+		property["required"] = nested["required"]
+	}
+	return property
 }
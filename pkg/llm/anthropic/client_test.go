@@ -1,10 +1,18 @@
 package anthropic
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
 )
 
 func TestMessageFiltering(t *testing.T) {
@@ -128,4 +136,675 @@ func TestEmptyContentHandling(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestThinkingBudget(t *testing.T) {
+	var captured CompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "answer"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(ClaudeSonnet4), WithBaseURL(server.URL))
+
+	resp, err := client.Generate(context.Background(), "test prompt", interfaces.WithThinkingBudget(3000))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if resp != "answer" {
+		t.Errorf("Expected response 'answer', got %q", resp)
+	}
+
+	if captured.Thinking == nil || captured.Thinking.Type != "enabled" || captured.Thinking.BudgetTokens != 3000 {
+		t.Fatalf("Expected thinking budget of 3000, got %+v", captured.Thinking)
+	}
+	if captured.MaxTokens != 2048+3000 {
+		t.Errorf("Expected output limit to be preserved on top of the thinking budget, got max_tokens=%d", captured.MaxTokens)
+	}
+	if captured.Temperature != 1.0 {
+		t.Errorf("Expected temperature 1.0 with thinking enabled, got %v", captured.Temperature)
+	}
+}
+
+func TestMaxTokens(t *testing.T) {
+	var captured CompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "answer"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(ClaudeSonnet4), WithBaseURL(server.URL))
+
+	if _, err := client.Generate(context.Background(), "test prompt"); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if captured.MaxTokens != 2048 {
+		t.Errorf("Expected the default max_tokens of 2048, got %d", captured.MaxTokens)
+	}
+
+	if _, err := client.Generate(context.Background(), "test prompt", WithMaxTokens(500)); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if captured.MaxTokens != 500 {
+		t.Errorf("Expected max_tokens=500, got %d", captured.MaxTokens)
+	}
+
+	if _, err := client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{&echoTool{}}, WithMaxTokens(700)); err != nil {
+		t.Fatalf("GenerateWithTools failed: %v", err)
+	}
+	if captured.MaxTokens != 700 {
+		t.Errorf("Expected max_tokens=700 with tools, got %d", captured.MaxTokens)
+	}
+
+	if _, err := client.Generate(context.Background(), "test prompt", WithMaxTokens(500), interfaces.WithThinkingBudget(3000)); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if captured.MaxTokens != 500+3000 {
+		t.Errorf("Expected the thinking budget on top of max_tokens=500, got %d", captured.MaxTokens)
+	}
+}
+
+func TestThinkingBudgetUnsupportedModel(t *testing.T) {
+	client := NewClient("test-key", WithModel(Claude35Haiku), WithBaseURL("http://127.0.0.1:0"))
+
+	if _, err := client.Generate(context.Background(), "test prompt", interfaces.WithThinkingBudget(1024)); err == nil {
+		t.Error("Expected an error for a model without thinking support")
+	}
+	if _, err := client.GenerateStream(context.Background(), "test prompt", interfaces.WithThinkingBudget(1024)); err == nil {
+		t.Error("Expected an error for a model without thinking support when streaming")
+	}
+}
+
+func TestThinkingBudgetBelowMinimum(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(ClaudeSonnet4), WithBaseURL(server.URL))
+
+	_, err := client.Generate(context.Background(), "test prompt", interfaces.WithThinkingBudget(500))
+	if err == nil || !strings.Contains(err.Error(), "at least 1024") {
+		t.Errorf("Expected a minimum thinking budget error, got %v", err)
+	}
+	if _, err := client.GenerateStream(context.Background(), "test prompt", interfaces.WithThinkingBudget(500)); err == nil {
+		t.Error("Expected a minimum thinking budget error when streaming")
+	}
+	if requests != 0 {
+		t.Errorf("Expected the budget to be rejected before any request, got %d requests", requests)
+	}
+}
+
+type echoTool struct{}
+
+func (t *echoTool) Name() string        { return "echo" }
+func (t *echoTool) Description() string { return "Echoes its input" }
+func (t *echoTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"text": {Type: "string", Description: "Text to echo", Required: true},
+	}
+}
+func (t *echoTool) Run(ctx context.Context, input string) (string, error) { return input, nil }
+func (t *echoTool) Execute(ctx context.Context, args string) (string, error) {
+	return "echoed " + args, nil
+}
+
+func TestToolOnlyAssistantTurnPreserved(t *testing.T) {
+	var requests []CompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(requests) == 1 {
+			// Tool-only turn: no text content
+			_, _ = w.Write([]byte(`{"content": [{"type": "tool_use", "id": "toolu_1", "name": "echo", "input": {"text": "hi"}}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "done"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(Claude35Haiku), WithBaseURL(server.URL))
+
+	resp, err := client.GenerateWithTools(context.Background(), "say hi", []interfaces.Tool{&echoTool{}})
+	if err != nil {
+		t.Fatalf("GenerateWithTools failed: %v", err)
+	}
+	if resp != "done" {
+		t.Errorf("Expected response 'done', got %q", resp)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+
+	messages := requests[1].Messages
+	if len(messages) != 3 {
+		t.Fatalf("Expected user, assistant and tool result messages, got %+v", messages)
+	}
+	if messages[1].Role != "assistant" || !strings.Contains(messages[1].Content, "echo") || !strings.Contains(messages[1].Content, `"text":"hi"`) {
+		t.Errorf("Expected tool-only assistant turn with its tool call, got %+v", messages[1])
+	}
+	if messages[2].Role != "user" {
+		t.Errorf("Expected tool results as user message, got %+v", messages[2])
+	}
+}
+
+func TestToolChoice(t *testing.T) {
+	var requests []CompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(requests) == 1 {
+			_, _ = w.Write([]byte(`{"content": [{"type": "tool_use", "id": "toolu_1", "name": "echo", "input": {"text": "hi"}}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "done"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(Claude35Haiku), WithBaseURL(server.URL))
+	_, err := client.GenerateWithTools(context.Background(), "say hi", []interfaces.Tool{&echoTool{}},
+		interfaces.WithToolChoice(interfaces.RequireTool("echo")),
+		interfaces.WithStopSequences([]string{"END"}))
+	if err != nil {
+		t.Fatalf("GenerateWithTools failed: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+
+	// The forced tool applies to the first request, later requests let the model decide
+	if choice := requests[0].ToolChoice.(map[string]interface{}); choice["type"] != "tool" || choice["name"] != "echo" {
+		t.Errorf("Expected the echo tool to be forced, got %v", choice)
+	}
+	if choice := requests[1].ToolChoice.(map[string]interface{}); choice["type"] != "auto" {
+		t.Errorf("Expected auto tool choice after the tool result, got %v", choice)
+	}
+	if len(requests[0].StopSequences) != 1 || requests[0].StopSequences[0] != "END" {
+		t.Errorf("Expected the stop sequences in the request, got %v", requests[0].StopSequences)
+	}
+
+	tests := map[interfaces.ToolChoiceMode]string{
+		interfaces.ToolChoiceAuto:     "auto",
+		interfaces.ToolChoiceNone:     "none",
+		interfaces.ToolChoiceRequired: "any",
+	}
+	for mode, expected := range tests {
+		if choice := toolChoice(&interfaces.ToolChoice{Mode: mode}); choice["type"] != expected {
+			t.Errorf("Expected %s to convert to %s, got %v", mode, expected, choice)
+		}
+	}
+}
+
+func TestFormatAssistantTurn(t *testing.T) {
+	content := formatAssistantTurn("", []interfaces.ToolCall{{ID: "1", Name: "search", Arguments: `{"q":"go"}`}})
+	if content != `[Called tool search with arguments: {"q":"go"}]` {
+		t.Errorf("Unexpected tool-only turn format: %q", content)
+	}
+
+	content = formatAssistantTurn("Let me look", []interfaces.ToolCall{{ID: "1", Name: "search"}})
+	if !strings.HasPrefix(content, "Let me look\n") || !strings.Contains(content, "arguments: {}") {
+		t.Errorf("Unexpected mixed turn format: %q", content)
+	}
+}
+
+type nestedParamsTool struct {
+	echoTool
+}
+
+func (t *nestedParamsTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"address": {
+			Type:     "object",
+			Required: true,
+			Properties: map[string]interfaces.ParameterSpec{
+				"city": {Type: "string", Description: "City name", Required: true},
+				"zip":  {Type: "string"},
+			},
+		},
+		"items": {
+			Type: "array",
+			Items: &interfaces.ParameterSpec{
+				Type: "object",
+				Properties: map[string]interfaces.ParameterSpec{
+					"sku": {Type: "string", Required: true},
+				},
+			},
+		},
+	}
+}
+
+func TestToolSchemaNestedObjects(t *testing.T) {
+	var request CompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "done"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(Claude35Haiku), WithBaseURL(server.URL))
+	if _, err := client.GenerateWithTools(context.Background(), "ship it", []interfaces.Tool{&nestedParamsTool{}}); err != nil {
+		t.Fatalf("GenerateWithTools failed: %v", err)
+	}
+	if len(request.Tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(request.Tools))
+	}
+
+	properties := request.Tools[0].InputSchema["properties"].(map[string]interface{})
+	address := properties["address"].(map[string]interface{})
+	if address["type"] != "object" {
+		t.Errorf("Expected address to be an object, got %v", address["type"])
+	}
+	addressProperties, ok := address["properties"].(map[string]interface{})
+	if !ok || addressProperties["city"].(map[string]interface{})["type"] != "string" || addressProperties["zip"] == nil {
+		t.Errorf("Expected nested address properties, got %v", address)
+	}
+	if required, _ := address["required"].([]interface{}); len(required) != 1 || required[0] != "city" {
+		t.Errorf("Expected address to require city, got %v", address["required"])
+	}
+
+	items := properties["items"].(map[string]interface{})["items"].(map[string]interface{})
+	if itemProperties, ok := items["properties"].(map[string]interface{}); !ok || itemProperties["sku"] == nil {
+		t.Errorf("Expected object array items with properties, got %v", items)
+	}
+}
+
+// keyValueListTool is a tool taking an array of name/value objects
+type keyValueListTool struct {
+	echoTool
+}
+
+func (t *keyValueListTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"headers": {
+			Type:     "array",
+			Required: true,
+			Items: &interfaces.ParameterSpec{
+				Type: "object",
+				Properties: map[string]interfaces.ParameterSpec{
+					"value": {Type: "string", Description: "Header value", Required: true},
+					"name":  {Type: "string", Description: "Header name", Required: true},
+				},
+			},
+		},
+	}
+}
+
+func TestToolSchemaArrayOfObjects(t *testing.T) {
+	var request CompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "done"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(Claude35Haiku), WithBaseURL(server.URL))
+	if _, err := client.GenerateWithTools(context.Background(), "set them", []interfaces.Tool{&keyValueListTool{}}); err != nil {
+		t.Fatalf("GenerateWithTools failed: %v", err)
+	}
+	if len(request.Tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(request.Tools))
+	}
+
+	properties := request.Tools[0].InputSchema["properties"].(map[string]interface{})
+	headers := properties["headers"].(map[string]interface{})
+	if headers["type"] != "array" {
+		t.Errorf("Expected headers to be an array, got %v", headers["type"])
+	}
+	items := headers["items"].(map[string]interface{})
+	if items["type"] != "object" {
+		t.Errorf("Expected object array items, got %v", items["type"])
+	}
+	itemProperties, ok := items["properties"].(map[string]interface{})
+	if !ok || itemProperties["name"].(map[string]interface{})["type"] != "string" ||
+		itemProperties["value"].(map[string]interface{})["description"] != "Header value" {
+		t.Errorf("Expected name/value item properties, got %v", items)
+	}
+	if required, _ := items["required"].([]interface{}); len(required) != 2 || required[0] != "name" || required[1] != "value" {
+		t.Errorf("Expected items to require name and value, got %v", items["required"])
+	}
+}
+
+func TestThinkingPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content": [{"type": "thinking", "thinking": "Let me think.", "signature": "sig"}, {"type": "text", "text": "answer"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(ClaudeSonnet4), WithBaseURL(server.URL))
+
+	tests := []struct {
+		policy   interfaces.ThinkingPolicy
+		expected string
+	}{
+		{"", "answer"},
+		{interfaces.ThinkingPolicyHidden, "answer"},
+		{interfaces.ThinkingPolicyInternal, interfaces.FormatThinking("Let me think.", "answer")},
+		{interfaces.ThinkingPolicyVisible, interfaces.FormatThinking("Let me think.", "answer")},
+	}
+
+	for _, tt := range tests {
+		resp, err := client.Generate(context.Background(), "test prompt", interfaces.WithThinkingPolicy(tt.policy))
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if resp != tt.expected {
+			t.Errorf("policy %q: expected %q, got %q", tt.policy, tt.expected, resp)
+		}
+	}
+}
+
+// recordingLogger captures the fields of completion summary logs
+type recordingLogger struct {
+	levels    []string
+	summaries []map[string]interface{}
+}
+
+func (l *recordingLogger) record(level, msg string, fields map[string]interface{}) {
+	if msg == "LLM call completed" {
+		l.levels = append(l.levels, level)
+		l.summaries = append(l.summaries, fields)
+	}
+}
+
+func (l *recordingLogger) Info(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("info", msg, fields)
+}
+
+func (l *recordingLogger) Warn(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("warn", msg, fields)
+}
+
+func (l *recordingLogger) Error(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("error", msg, fields)
+}
+
+func (l *recordingLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("debug", msg, fields)
+}
+
+func TestCompletionSummaryLog(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "answer"}], "usage": {"input_tokens": 20, "output_tokens": 7}}`))
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := NewClient("test-key",
+		WithModel(ClaudeSonnet4),
+		WithBaseURL(server.URL),
+		WithLogger(logger),
+		WithRetry(retry.WithMaxAttempts(2), retry.WithInitialInterval(time.Millisecond)),
+		WithCompletionLogLevel("warn"),
+	)
+
+	if _, err := client.Generate(context.Background(), "test prompt"); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if len(logger.summaries) != 1 {
+		t.Fatalf("expected 1 completion summary, got %d", len(logger.summaries))
+	}
+	if logger.levels[0] != "warn" {
+		t.Errorf("expected summary at the configured level, got %s", logger.levels[0])
+	}
+	summary := logger.summaries[0]
+	expected := map[string]interface{}{
+		"provider":        "anthropic",
+		"model":           ClaudeSonnet4,
+		"prompt_tokens":   int64(20),
+		"response_tokens": int64(7),
+		"total_tokens":    int64(27),
+		"retries":         1,
+		"status":          "success",
+	}
+	for key, value := range expected {
+		if summary[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, summary[key])
+		}
+	}
+	if _, ok := summary["latency_ms"].(int64); !ok {
+		t.Errorf("expected latency_ms field, got %v", summary["latency_ms"])
+	}
+}
+
+func TestGenerateCircuitBreaker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key",
+		WithModel(ClaudeSonnet4),
+		WithBaseURL(server.URL),
+		WithRetry(
+			retry.WithMaxAttempts(5),
+			retry.WithInitialInterval(time.Millisecond),
+			retry.WithCircuitBreaker(2, time.Hour),
+		),
+	)
+
+	if _, err := client.Generate(context.Background(), "test prompt"); !errors.Is(err, retry.ErrCircuitOpen) {
+		t.Fatalf("Expected the circuit to open, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the retries to stop when the circuit opens, got %d requests", requests)
+	}
+
+	// While the circuit is open, requests fail without reaching the provider
+	requests = 0
+	if _, err := client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{&echoTool{}}); !errors.Is(err, retry.ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request while the circuit is open, got %d", requests)
+	}
+}
+
+func TestGenerateClientErrorNotRetried(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "invalid_request_error", "message": "Bad request"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key",
+		WithModel(ClaudeSonnet4),
+		WithBaseURL(server.URL),
+		WithRetry(
+			retry.WithMaxAttempts(5),
+			retry.WithInitialInterval(time.Millisecond),
+			retry.WithCircuitBreaker(2, time.Hour),
+		),
+	)
+
+	// Bad requests are neither retried nor counted as failures by the circuit breaker
+	for i := 0; i < 3; i++ {
+		if _, err := client.Generate(context.Background(), "test prompt"); err == nil || errors.Is(err, retry.ErrCircuitOpen) {
+			t.Fatalf("Expected the client error, got %v", err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected a single request per call, got %d requests", requests)
+	}
+}
+
+func TestGenerateWithNoRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key",
+		WithModel(ClaudeSonnet4),
+		WithBaseURL(server.URL),
+		WithRetry(retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond)),
+	)
+
+	if _, err := client.Generate(context.Background(), "test prompt", interfaces.WithNoRetry()); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request with retry disabled, got %d", requests)
+	}
+
+	requests = 0
+	if _, err := client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{&echoTool{}}, interfaces.WithNoRetry()); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single tools request with retry disabled, got %d", requests)
+	}
+
+	requests = 0
+	if _, err := client.Generate(context.Background(), "test prompt"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 3 {
+		t.Errorf("Expected the configured retries by default, got %d requests", requests)
+	}
+}
+
+func TestGenerateWithMaxRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key",
+		WithModel(ClaudeSonnet4),
+		WithBaseURL(server.URL),
+		WithRetry(retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond)),
+	)
+
+	if _, err := client.Generate(context.Background(), "test prompt", interfaces.WithMaxRetries(1)); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request with a single attempt, got %d", requests)
+	}
+
+	requests = 0
+	if _, err := client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{&echoTool{}}, interfaces.WithMaxRetries(1)); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single tools request with a single attempt, got %d", requests)
+	}
+
+	requests = 0
+	if _, err := client.Generate(context.Background(), "test prompt", interfaces.WithMaxRetries(4)); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", requests)
+	}
+}
+
+func TestGenerateStructuredOutputRepair(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		// The response continues the prefilled opening brace
+		w.Header().Set("Content-Type", "application/json")
+		if len(bodies) == 1 {
+			_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "\"name\": \"Ada\", \"age\": }"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "\"name\": \"Ada\", \"age\": 36}"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(Claude35Haiku), WithBaseURL(server.URL))
+	format := interfaces.ResponseFormat{
+		Type:   interfaces.ResponseFormatJSON,
+		Name:   "Person",
+		Schema: interfaces.JSONSchema{"type": "object", "properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}}},
+	}
+
+	response, err := client.Generate(context.Background(), "Who is Ada?", WithResponseFormat(format), interfaces.WithStructuredOutputRepair(2))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if response != `{"name": "Ada", "age": 36}` {
+		t.Errorf("Expected the repaired response, got %q", response)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("Expected a single repair request, got %d requests", len(bodies))
+	}
+	if !strings.Contains(bodies[1], "could not be parsed as JSON") || !strings.Contains(bodies[1], `\"age\": }`) {
+		t.Errorf("Expected the repair request to include the parse error and the invalid response, got %s", bodies[1])
+	}
+
+	// Without repair, the invalid response is returned as is
+	bodies = nil
+	response, err = client.Generate(context.Background(), "Who is Ada?", WithResponseFormat(format))
+	if err != nil || len(bodies) != 1 || response != `{"name": "Ada", "age": }` {
+		t.Errorf("Expected the invalid response without repair, got %q (%v) after %d requests", response, err, len(bodies))
+	}
+}
+
+func TestGenerateRejectsImages(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(ClaudeSonnet4), WithBaseURL(server.URL))
+
+	images := []interfaces.ImageInput{{URL: "https://example.com/cat.png"}}
+	_, err := client.Generate(context.Background(), "What is in this image?", interfaces.WithImages(images))
+	if err == nil || !strings.Contains(err.Error(), "does not support image input") {
+		t.Fatalf("Expected images to be rejected, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request to be sent, got %d", requests)
+	}
+}
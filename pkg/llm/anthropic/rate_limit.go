@@ -0,0 +1,54 @@
+package anthropic
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+// recordRateLimits records the rate limits reported in the headers of a response in the
+// llm.RateLimitStatus of ctx
+func recordRateLimits(ctx context.Context, resp *http.Response) {
+	if limits, ok := parseRateLimits(resp.Header, time.Now()); ok {
+		llm.RecordRateLimits(ctx, limits)
+	}
+}
+
+// parseRateLimits parses the anthropic-ratelimit-* headers of a response. Resets are reported
+// as RFC 3339 times.
+func parseRateLimits(header http.Header, now time.Time) (llm.RateLimits, bool) {
+	limits := llm.RateLimits{Provider: "anthropic", ReceivedAt: now}
+
+	var found bool
+	for name, rateLimit := range map[string]*llm.RateLimit{
+		"requests":      &limits.Requests,
+		"tokens":        &limits.Tokens,
+		"input-tokens":  &limits.InputTokens,
+		"output-tokens": &limits.OutputTokens,
+	} {
+		if parseRateLimit(header, name, rateLimit) {
+			found = true
+		}
+	}
+	return limits, found
+}
+
+// parseRateLimit parses the headers of one rate limit, e.g. anthropic-ratelimit-requests-remaining,
+// reporting whether the limit was present
+func parseRateLimit(header http.Header, name string, rateLimit *llm.RateLimit) bool {
+	limit, limitErr := strconv.ParseInt(header.Get("Anthropic-Ratelimit-"+name+"-Limit"), 10, 64)
+	remaining, remainingErr := strconv.ParseInt(header.Get("Anthropic-Ratelimit-"+name+"-Remaining"), 10, 64)
+	if limitErr != nil && remainingErr != nil {
+		return false
+	}
+
+	rateLimit.Limit = limit
+	rateLimit.Remaining = remaining
+	if reset, err := time.Parse(time.RFC3339, header.Get("Anthropic-Ratelimit-"+name+"-Reset")); err == nil {
+		rateLimit.Reset = reset
+	}
+	return true
+}
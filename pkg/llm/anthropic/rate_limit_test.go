@@ -0,0 +1,54 @@
+package anthropic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+func TestRateLimitStatus(t *testing.T) {
+	reset := time.Date(2025, 1, 1, 12, 0, 30, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("anthropic-ratelimit-requests-limit", "50")
+		w.Header().Set("anthropic-ratelimit-requests-remaining", "49")
+		w.Header().Set("anthropic-ratelimit-requests-reset", reset.Format(time.RFC3339))
+		w.Header().Set("anthropic-ratelimit-input-tokens-limit", "40000")
+		w.Header().Set("anthropic-ratelimit-input-tokens-remaining", "39000")
+		w.Header().Set("anthropic-ratelimit-output-tokens-limit", "8000")
+		w.Header().Set("anthropic-ratelimit-output-tokens-remaining", "7900")
+		_, _ = w.Write([]byte(`{"content": [{"type": "text", "text": "answer"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(ClaudeSonnet4), WithBaseURL(server.URL))
+
+	ctx, status := llm.WithRateLimitStatus(context.Background())
+	if _, err := client.Generate(ctx, "test prompt"); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	limits, ok := status.Last()
+	if !ok {
+		t.Fatal("Expected the rate limits of the response to be recorded")
+	}
+	if limits.Provider != "anthropic" {
+		t.Errorf("Expected provider anthropic, got %q", limits.Provider)
+	}
+	if limits.Requests.Limit != 50 || limits.Requests.Remaining != 49 || !limits.Requests.Reset.Equal(reset) {
+		t.Errorf("Unexpected request limits: %+v", limits.Requests)
+	}
+	if limits.InputTokens.Limit != 40000 || limits.InputTokens.Remaining != 39000 {
+		t.Errorf("Unexpected input token limits: %+v", limits.InputTokens)
+	}
+	if limits.OutputTokens.Limit != 8000 || limits.OutputTokens.Remaining != 7900 {
+		t.Errorf("Unexpected output token limits: %+v", limits.OutputTokens)
+	}
+	if limits.Tokens.Limit != 0 {
+		t.Errorf("Expected unreported limits to be zero, got %+v", limits.Tokens)
+	}
+}
@@ -247,6 +247,9 @@ func (c *AnthropicClient) convertAnthropicEventToStreamEvent(event *AnthropicSSE
 		}
 
 		streamEvent.Type = interfaces.StreamEventContentDelta
+		if msgDelta.Delta.StopReason != "" {
+			streamEvent.FinishReason = normalizeStopReason(msgDelta.Delta.StopReason)
+		}
 		streamEvent.Metadata["stop_reason"] = msgDelta.Delta.StopReason
 		streamEvent.Metadata["stop_sequence"] = msgDelta.Delta.StopSequence
 		streamEvent.Metadata["usage"] = msgDelta.Usage
@@ -321,6 +324,10 @@ func (c *AnthropicClient) parseSSEStreamAndCapture(ctx context.Context, scanner
 	lineCount := 0
 
 	for scanner.Scan() {
+		// Stop reading as soon as the caller cancels the stream
+		if ctx.Err() != nil {
+			break
+		}
 		lineCount++
 		line := strings.TrimSpace(scanner.Text())
 
@@ -329,16 +336,19 @@ func (c *AnthropicClient) parseSSEStreamAndCapture(ctx context.Context, scanner
 			if currentEvent != nil && len(currentEvent.Data) > 0 {
 				// Process complete event and capture content
 				if err := c.processCompleteSSEEventAndCapture(ctx, currentEvent, eventChan, thinkingBlocks, toolBlocks, &accumulatedContent); err != nil {
+					if ctx.Err() != nil {
+						break
+					}
 					c.logger.Error(ctx, "Failed to process SSE event", map[string]interface{}{
 						"error":      err.Error(),
 						"event_type": currentEvent.Type,
 						"event_data": string(currentEvent.Data),
 					})
-					eventChan <- interfaces.StreamEvent{
+					_ = sendStreamEvent(ctx, eventChan, interfaces.StreamEvent{
 						Type:      interfaces.StreamEventError,
 						Error:     fmt.Errorf("failed to process SSE event: %w", err),
 						Timestamp: time.Now(),
-					}
+					})
 					break
 				}
 				currentEvent = nil
@@ -366,10 +376,10 @@ func (c *AnthropicClient) parseSSEStreamAndCapture(ctx context.Context, scanner
 
 			// Handle end of stream
 			if dataContent == "[DONE]" {
-				eventChan <- interfaces.StreamEvent{
+				_ = sendStreamEvent(ctx, eventChan, interfaces.StreamEvent{
 					Type:      interfaces.StreamEventMessageStop,
 					Timestamp: time.Now(),
-				}
+				})
 				break
 			}
 
@@ -388,6 +398,14 @@ func (c *AnthropicClient) parseSSEStreamAndCapture(ctx context.Context, scanner
 		// Parse other SSE fields (id, retry, etc.) - can be ignored for now
 	}
 
+	// A cancelled stream is abandoned by the caller: nothing more is sent or stored
+	if ctx.Err() != nil {
+		c.logger.Debug(ctx, "SSE stream cancelled", map[string]interface{}{
+			"lines_processed": lineCount,
+		})
+		return accumulatedContent.String()
+	}
+
 	// Process any remaining event
 	if currentEvent != nil && len(currentEvent.Data) > 0 {
 		_ = c.processCompleteSSEEventAndCapture(ctx, currentEvent, eventChan, thinkingBlocks, toolBlocks, &accumulatedContent)
@@ -400,11 +418,11 @@ func (c *AnthropicClient) parseSSEStreamAndCapture(ctx context.Context, scanner
 			"error":           err.Error(),
 			"lines_processed": lineCount,
 		})
-		eventChan <- interfaces.StreamEvent{
+		_ = sendStreamEvent(ctx, eventChan, interfaces.StreamEvent{
 			Type:      interfaces.StreamEventError,
 			Error:     fmt.Errorf("scanner error after %d lines: %w", lineCount, err),
 			Timestamp: time.Now(),
-		}
+		})
 	}
 
 	// Store messages in memory if provided
@@ -443,11 +461,10 @@ func (c *AnthropicClient) processCompleteSSEEventAndCapture(ctx context.Context,
 
 	// Handle done event
 	if event.Type == "done" || event.Type == "" {
-		eventChan <- interfaces.StreamEvent{
+		return sendStreamEvent(ctx, eventChan, interfaces.StreamEvent{
 			Type:      interfaces.StreamEventMessageStop,
 			Timestamp: time.Now(),
-		}
-		return nil
+		})
 	}
 
 	// Convert to StreamEvent
@@ -463,8 +480,18 @@ func (c *AnthropicClient) processCompleteSSEEventAndCapture(ctx context.Context,
 			accumulatedContent.WriteString(streamEvent.Content)
 		}
 
-		eventChan <- *streamEvent
+		return sendStreamEvent(ctx, eventChan, *streamEvent)
 	}
 
 	return nil
 }
+
+// sendStreamEvent sends an event unless the stream is cancelled first
+func sendStreamEvent(ctx context.Context, eventChan chan<- interfaces.StreamEvent, event interfaces.StreamEvent) error {
+	select {
+	case eventChan <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -250,3 +250,33 @@ func TestContentBlockDeltaData(t *testing.T) {
 		t.Errorf("Expected delta type 'text_delta', got '%s'", blockDelta.Delta.Type)
 	}
 }
+
+func TestMessageDeltaFinishReason(t *testing.T) {
+	client := &AnthropicClient{}
+
+	tests := map[string]interfaces.FinishReason{
+		"end_turn":      interfaces.FinishReasonStop,
+		"stop_sequence": interfaces.FinishReasonStop,
+		"max_tokens":    interfaces.FinishReasonLength,
+		"tool_use":      interfaces.FinishReasonToolUse,
+		"refusal":       interfaces.FinishReasonContentFilter,
+		"pause_turn":    interfaces.FinishReasonUnknown,
+	}
+	for stopReason, expected := range tests {
+		event := &AnthropicSSEEvent{
+			Type: "message_delta",
+			Data: json.RawMessage(`{"type": "message_delta", "delta": {"stop_reason": "` + stopReason + `"}, "usage": {"output_tokens": 5}}`),
+		}
+		result, err := client.convertAnthropicEventToStreamEvent(event, make(map[int]bool), make(map[int]struct {
+			ID        string
+			Name      string
+			InputJSON strings.Builder
+		}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.FinishReason != expected {
+			t.Errorf("Expected stop reason %q to finish with %q, got %q", stopReason, expected, result.FinishReason)
+		}
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 )
 
@@ -40,6 +42,31 @@ func (c *AnthropicClient) GenerateStream(
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("Anthropic", params.Files); err != nil {
+		return nil, err
+	}
+	if err := interfaces.RejectImages("Anthropic", params.Images); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateThinkingBudget(params.LLMConfig); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Re-request the stream on mid-stream failures, each attempt streaming without resuming
+	if params.StreamRetry > 0 {
+		return llm.ResumeStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamRetry(0))...)
+		})
+	}
+
 	// Check for organization ID in context, and add a default one if missing
 	defaultOrgID := "default"
 	if id, err := multitenancy.GetOrgID(ctx); err == nil {
@@ -70,10 +97,10 @@ func (c *AnthropicClient) GenerateStream(
 						Content: msg.Content,
 					})
 				case "assistant":
-					if msg.Content != "" {
+					if msg.Content != "" || len(msg.ToolCalls) > 0 {
 						messages = append(messages, Message{
 							Role:    "assistant",
-							Content: msg.Content,
+							Content: formatAssistantTurn(msg.Content, msg.ToolCalls),
 						})
 					}
 				case "tool":
@@ -104,10 +131,14 @@ func (c *AnthropicClient) GenerateStream(
 
 	// Create request with streaming enabled
 	// Note: MaxTokens must be greater than reasoning budget_tokens
-	maxTokens := 2048 // default
+	maxTokens := requestMaxTokens(params.LLMConfig)
 	if params.LLMConfig != nil && params.LLMConfig.EnableReasoning && params.LLMConfig.ReasoningBudget > 0 {
 		// Ensure max_tokens > budget_tokens for reasoning
-		maxTokens = params.LLMConfig.ReasoningBudget + 4000 // Add buffer for actual response
+		if params.LLMConfig.MaxTokens > 0 {
+			maxTokens += params.LLMConfig.ReasoningBudget
+		} else {
+			maxTokens = params.LLMConfig.ReasoningBudget + 4000 // Add buffer for actual response
+		}
 	}
 
 	req := CompletionRequest{
@@ -154,6 +185,7 @@ func (c *AnthropicClient) GenerateStream(
 			})
 		}
 	}
+	applyThinkingBudget(&req, params.LLMConfig)
 
 	// Get buffer size from stream config
 	bufferSize := 100 // default
@@ -247,6 +279,7 @@ func (c *AnthropicClient) executeStreamingRequestWithMemory(
 			})
 			return fmt.Errorf("failed to send request: %w", err)
 		}
+		recordRateLimits(ctx, httpResp)
 		defer func() {
 			if closeErr := httpResp.Body.Close(); closeErr != nil {
 				c.logger.Warn(ctx, "Failed to close response body", map[string]interface{}{
@@ -255,6 +288,12 @@ func (c *AnthropicClient) executeStreamingRequestWithMemory(
 			}
 		}()
 
+		// Close the body on cancellation so a read blocked on a stalled stream returns immediately
+		stopCloseOnCancel := context.AfterFunc(ctx, func() {
+			_ = httpResp.Body.Close()
+		})
+		defer stopCloseOnCancel()
+
 		// Check for error response
 		if httpResp.StatusCode != http.StatusOK {
 			// Read the response body to get the actual error message
@@ -272,9 +311,9 @@ func (c *AnthropicClient) executeStreamingRequestWithMemory(
 			})
 
 			if len(errorBody) > 0 {
-				return fmt.Errorf("error from Anthropic API: HTTP %d - %s", httpResp.StatusCode, string(errorBody))
+				return classifyStatus(httpResp.StatusCode, fmt.Errorf("error from Anthropic API: HTTP %d - %s", httpResp.StatusCode, string(errorBody)))
 			}
-			return fmt.Errorf("error from Anthropic API: HTTP %d", httpResp.StatusCode)
+			return classifyStatus(httpResp.StatusCode, fmt.Errorf("error from Anthropic API: HTTP %d", httpResp.StatusCode))
 		}
 
 		// Verify content type
@@ -293,6 +332,9 @@ func (c *AnthropicClient) executeStreamingRequestWithMemory(
 
 		// Parse SSE stream
 		_ = c.parseSSEStreamAndCapture(ctx, scanner, eventChan, req, prompt, params)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
 		// Check for scanner errors (including buffer overflow)
 		if err := scanner.Err(); err != nil {
@@ -310,12 +352,16 @@ func (c *AnthropicClient) executeStreamingRequestWithMemory(
 		return nil
 	}
 
-	// Use retry executor if available
-	if c.retryExecutor != nil {
+	// Use retry executor if available and not disabled for this call
+	if c.retryExecutor != nil && (params == nil || !params.NoRetry) {
 		c.logger.Debug(ctx, "Using retry mechanism for Anthropic streaming request", map[string]interface{}{
 			"model": c.Model,
 		})
-		return c.retryExecutor.Execute(ctx, operation)
+		maxAttempts := int32(0)
+		if params != nil {
+			maxAttempts = int32(params.MaxRetries)
+		}
+		return c.retryExecutor.ExecuteWithMaxAttempts(ctx, maxAttempts, operation)
 	}
 
 	return operation()
@@ -353,6 +399,38 @@ func (c *AnthropicClient) GenerateWithToolsStream(
 		}
 	}
 
+	if err := interfaces.RejectFiles("Anthropic", params.Files); err != nil {
+		return nil, err
+	}
+	if err := interfaces.RejectImages("Anthropic", params.Images); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream, such as long tool calls
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateWithToolsStream(ctx, prompt, tools, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
+	if err := c.validateThinkingBudget(params.LLMConfig); err != nil {
+		return nil, err
+	}
+
 	// Check for organization ID in context, and add a default one if missing
 	defaultOrgID := "default"
 	if id, err := multitenancy.GetOrgID(ctx); err == nil {
@@ -366,45 +444,10 @@ func (c *AnthropicClient) GenerateWithToolsStream(
 	// Convert tools to Anthropic format
 	anthropicTools := make([]Tool, len(tools))
 	for i, tool := range tools {
-		// Convert ParameterSpec to JSON Schema
-		properties := make(map[string]interface{})
-		required := []string{}
-
-		for name, param := range tool.Parameters() {
-			properties[name] = map[string]interface{}{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				properties[name].(map[string]interface{})["default"] = param.Default
-			}
-			if param.Required {
-				required = append(required, name)
-			}
-			if param.Items != nil {
-				properties[name].(map[string]interface{})["items"] = map[string]interface{}{
-					"type": param.Items.Type,
-				}
-				if param.Items.Enum != nil {
-					properties[name].(map[string]interface{})["items"].(map[string]interface{})["enum"] = param.Items.Enum
-				}
-			}
-			if param.Enum != nil {
-				properties[name].(map[string]interface{})["enum"] = param.Enum
-			}
-		}
-
-		// Create the input schema for this tool
-		inputSchema := map[string]interface{}{
-			"type":       "object",
-			"properties": properties,
-			"required":   required,
-		}
-
 		anthropicTools[i] = Tool{
 			Name:        tool.Name(),
 			Description: tool.Description(),
-			InputSchema: inputSchema,
+			InputSchema: convertToAnthropicSchema(tool.Parameters()),
 		}
 	}
 
@@ -476,10 +519,10 @@ func (c *AnthropicClient) executeStreamingWithTools(
 						Content: msg.Content,
 					})
 				case "assistant":
-					if msg.Content != "" {
+					if msg.Content != "" || len(msg.ToolCalls) > 0 {
 						messages = append(messages, Message{
 							Role:    "assistant",
-							Content: msg.Content,
+							Content: formatAssistantTurn(msg.Content, msg.ToolCalls),
 						})
 					}
 				case "tool":
@@ -530,10 +573,14 @@ func (c *AnthropicClient) executeStreamingWithTools(
 	}
 
 	// Create base request configuration
-	maxTokens := 2048 // default
+	maxTokens := requestMaxTokens(params.LLMConfig)
 	if params.LLMConfig != nil && params.LLMConfig.EnableReasoning && params.LLMConfig.ReasoningBudget > 0 {
 		// Ensure max_tokens > budget_tokens for reasoning
-		maxTokens = params.LLMConfig.ReasoningBudget + 4000 // Add buffer for actual response
+		if params.LLMConfig.MaxTokens > 0 {
+			maxTokens += params.LLMConfig.ReasoningBudget
+		} else {
+			maxTokens = params.LLMConfig.ReasoningBudget + 4000 // Add buffer for actual response
+		}
 	}
 
 	gotCompleteResponse := false
@@ -550,11 +597,12 @@ func (c *AnthropicClient) executeStreamingWithTools(
 			Temperature: params.LLMConfig.Temperature,
 			TopP:        params.LLMConfig.TopP,
 			Tools:       anthropicTools,
-			// Auto use tools when needed
-			ToolChoice: map[string]string{
-				"type": "auto",
-			},
-			Stream: true, // Enable streaming
+			ToolChoice:  toolChoice(params.ToolChoiceForIteration(iteration)),
+			Stream:      true, // Enable streaming
+		}
+
+		if params.LLMConfig != nil && len(params.LLMConfig.StopSequences) > 0 {
+			req.StopSequences = params.LLMConfig.StopSequences
 		}
 
 		// Add system message if available
@@ -583,6 +631,7 @@ func (c *AnthropicClient) executeStreamingWithTools(
 				})
 			}
 		}
+		applyThinkingBudget(&req, params.LLMConfig)
 
 		// Execute streaming request and collect tool calls
 		c.logger.Debug(ctx, "[LLM RESPONSE DEBUG] Calling LLM for iteration", map[string]interface{}{
@@ -676,6 +725,18 @@ func (c *AnthropicClient) executeStreamingWithTools(
 			"responseType": "tool_calls",
 		})
 
+		// Record the assistant turn with its tool calls before adding the tool results
+		var assistantText strings.Builder
+		for _, contentEvent := range capturedContentEvents {
+			if contentEvent.Type == interfaces.StreamEventContentDelta {
+				assistantText.WriteString(contentEvent.Content)
+			}
+		}
+		messages = append(messages, Message{
+			Role:    "assistant",
+			Content: formatAssistantTurn(assistantText.String(), toolCalls),
+		})
+
 		// Send a line break before tool execution for clarity
 		select {
 		case eventChan <- interfaces.StreamEvent{
@@ -742,7 +803,7 @@ func (c *AnthropicClient) executeStreamingWithTools(
 				"iteration": iteration + 1,
 			})
 
-			toolResult, err := selectedTool.Execute(ctx, toolCall.Arguments)
+			toolResult, err := interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), selectedTool, toolCall.Arguments)
 			if err != nil {
 				toolResult = fmt.Sprintf("Error: %v", err)
 			}
@@ -891,6 +952,7 @@ func (c *AnthropicClient) executeStreamingWithTools(
 			})
 		}
 	}
+	applyThinkingBudget(&finalReq, params.LLMConfig)
 
 	// Execute final request to get synthesized answer with memory support
 	c.logger.Debug(ctx, "[LLM RESPONSE DEBUG] Executing final synthesis LLM call", map[string]interface{}{
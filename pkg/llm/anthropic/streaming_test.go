@@ -0,0 +1,112 @@
+package anthropic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func writeSSE(w http.ResponseWriter, event, data string) {
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func TestGenerateStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSE(w, "message_start", `{"type":"message_start","message":{"id":"msg_1","model":"claude-sonnet-4-20250514","role":"assistant"}}`)
+		writeSSE(w, "content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`)
+		writeSSE(w, "content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Considering."}}`)
+		writeSSE(w, "content_block_stop", `{"type":"content_block_stop","index":0}`)
+		writeSSE(w, "content_block_start", `{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`)
+		writeSSE(w, "content_block_delta", `{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hello"}}`)
+		writeSSE(w, "content_block_delta", `{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":" world"}}`)
+		writeSSE(w, "content_block_stop", `{"type":"content_block_stop","index":1}`)
+		writeSSE(w, "message_stop", `{"type":"message_stop"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(ClaudeSonnet4), WithBaseURL(server.URL))
+	events, err := client.GenerateStream(context.Background(), "say hello")
+	if err != nil {
+		t.Fatalf("GenerateStream failed: %v", err)
+	}
+
+	var content, thinking strings.Builder
+	var last interfaces.StreamEvent
+	for event := range events {
+		switch event.Type {
+		case interfaces.StreamEventContentDelta:
+			content.WriteString(event.Content)
+		case interfaces.StreamEventThinking:
+			thinking.WriteString(event.Content)
+		case interfaces.StreamEventError:
+			t.Fatalf("unexpected error event: %v", event.Error)
+		}
+		last = event
+	}
+
+	if content.String() != "Hello world" {
+		t.Errorf("expected content %q, got %q", "Hello world", content.String())
+	}
+	if thinking.String() != "Considering." {
+		t.Errorf("expected thinking %q, got %q", "Considering.", thinking.String())
+	}
+	if last.Type != interfaces.StreamEventMessageStop {
+		t.Errorf("expected stream to end with message stop, got %s", last.Type)
+	}
+}
+
+func TestGenerateStreamCancellation(t *testing.T) {
+	requestDone := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(requestDone)
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSE(w, "content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`)
+		writeSSE(w, "content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"partial"}}`)
+		// Stall the stream until the client goes away
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithModel(ClaudeSonnet4), WithBaseURL(server.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.GenerateStream(ctx, "say hello")
+	if err != nil {
+		t.Fatalf("GenerateStream failed: %v", err)
+	}
+
+	// Wait for the first content, then stop consuming and cancel
+	for event := range events {
+		if event.Type == interfaces.StreamEventContentDelta && event.Content == "partial" {
+			break
+		}
+	}
+	cancel()
+
+	closed := make(chan struct{})
+	go func() {
+		for range events {
+		}
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the event channel to be closed after cancellation")
+	}
+	select {
+	case <-requestDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the HTTP stream to be closed after cancellation")
+	}
+}
@@ -393,11 +393,21 @@ func NewVertexRetryExecutor(vertexConfig *VertexConfig, policy *Policy) *VertexR
 
 // Execute executes the operation with retries and region rotation
 func (e *VertexRetryExecutor) Execute(ctx context.Context, operation func() error) error {
+	return e.ExecuteWithMaxAttempts(ctx, 0, operation)
+}
+
+// ExecuteWithMaxAttempts executes the operation like Execute, making at most maxAttempts
+// attempts instead of the maximum of the policy, which applies when maxAttempts is 0 or less
+func (e *VertexRetryExecutor) ExecuteWithMaxAttempts(ctx context.Context, maxAttempts int32, operation func() error) error {
+	if maxAttempts <= 0 {
+		maxAttempts = e.policy.MaximumAttempts
+	}
+
 	var lastErr error
 	attempt := int32(0)
 	currentInterval := e.policy.InitialInterval
 
-	for attempt < e.policy.MaximumAttempts {
+	for attempt < maxAttempts {
 		select {
 		case <-ctx.Done():
 			e.logger.Debug(ctx, "Context cancelled during retry", map[string]interface{}{
@@ -409,7 +419,7 @@ func (e *VertexRetryExecutor) Execute(ctx context.Context, operation func() erro
 			currentRegion := e.vertexConfig.GetCurrentRegion()
 			e.logger.Debug(ctx, "Attempting operation", map[string]interface{}{
 				"attempt":      attempt + 1,
-				"max_attempts": e.policy.MaximumAttempts,
+				"max_attempts": maxAttempts,
 				"region":       currentRegion,
 			})
 
@@ -423,7 +433,7 @@ func (e *VertexRetryExecutor) Execute(ctx context.Context, operation func() erro
 				lastErr = err
 				attempt++
 
-				if attempt >= e.policy.MaximumAttempts {
+				if attempt >= maxAttempts {
 					e.logger.Debug(ctx, "Maximum attempts reached", map[string]interface{}{
 						"attempt": attempt,
 						"error":   err.Error(),
@@ -96,6 +96,26 @@ func isReasoningModel(model string) bool {
 	return false
 }
 
+// validateThinkingBudget returns an error if config sets a thinking budget, which Azure OpenAI
+// does not support: reasoning models are bounded with a reasoning effort instead
+func validateThinkingBudget(config *interfaces.LLMConfig) error {
+	if err := llm.RejectThinkingBudget("Azure OpenAI", config); err != nil {
+		return fmt.Errorf("%w, use WithReasoningEffort to bound the reasoning of reasoning models", err)
+	}
+	return nil
+}
+
+// maxStopSequences is the maximum number of stop sequences accepted by Azure OpenAI
+const maxStopSequences = 4
+
+// validateStopSequences returns an error if the stop sequences of config would be rejected by Azure OpenAI
+func validateStopSequences(config *interfaces.LLMConfig) error {
+	if config == nil {
+		return nil
+	}
+	return llm.ValidateStopSequences("Azure OpenAI", config.StopSequences, maxStopSequences)
+}
+
 // getTemperatureForModel returns the appropriate temperature for a model
 func (c *AzureOpenAIClient) getTemperatureForModel(requestedTemp float64) float64 {
 	if isReasoningModel(c.Model) {
@@ -231,6 +251,19 @@ func (c *AzureOpenAIClient) Generate(ctx context.Context, prompt string, options
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("Azure OpenAI", params.Files); err != nil {
+		return "", err
+	}
+	if err := interfaces.ValidateImages(params.Images); err != nil {
+		return "", err
+	}
+	if err := validateThinkingBudget(params.LLMConfig); err != nil {
+		return "", err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return "", err
+	}
+
 	// Get organization ID from context if available
 	orgID, _ := multitenancy.GetOrgID(ctx)
 	if orgID != "" {
@@ -285,7 +318,7 @@ func (c *AzureOpenAIClient) Generate(ctx context.Context, prompt string, options
 	}
 
 	// Add user message
-	messages = append(messages, openai.UserMessage(prompt))
+	messages = append(messages, userMessage(prompt, params.Images))
 
 	// Create request - use deployment name as model for Azure OpenAI
 	req := openai.ChatCompletionNewParams{
@@ -313,6 +346,9 @@ func (c *AzureOpenAIClient) Generate(ctx context.Context, prompt string, options
 			Name:   params.ResponseFormat.Name,
 			Schema: params.ResponseFormat.Schema,
 		}
+		if params.ResponseFormat.Strict {
+			jsonSchema.Strict = openai.Bool(true)
+		}
 
 		req.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
@@ -331,6 +367,12 @@ func (c *AzureOpenAIClient) Generate(ctx context.Context, prompt string, options
 	var resp *openai.ChatCompletion
 	var err error
 
+	// A bounded call is retried by the retry policy of the client only, not by the SDK as well
+	reqOptions := requestOptions(params)
+	if c.retryExecutor != nil && !params.NoRetry && params.MaxRetries > 0 {
+		reqOptions = []option.RequestOption{option.WithMaxRetries(0)}
+	}
+
 	operation := func() error {
 		var reasoningMode string
 		if params.LLMConfig != nil && params.LLMConfig.Reasoning != "" {
@@ -352,7 +394,7 @@ func (c *AzureOpenAIClient) Generate(ctx context.Context, prompt string, options
 			"reasoning":         reasoningMode,
 		})
 
-		resp, err = c.ChatService.Completions.New(ctx, req)
+		resp, err = c.ChatService.Completions.New(ctx, req, reqOptions...)
 		if err != nil {
 			c.logger.Error(ctx, "Error from Azure OpenAI API", map[string]interface{}{
 				"error":      err.Error(),
@@ -364,12 +406,12 @@ func (c *AzureOpenAIClient) Generate(ctx context.Context, prompt string, options
 		return nil
 	}
 
-	if c.retryExecutor != nil {
+	if c.retryExecutor != nil && !params.NoRetry {
 		c.logger.Debug(ctx, "Using retry mechanism for Azure OpenAI request", map[string]interface{}{
 			"model":      c.Model,
 			"deployment": c.deployment,
 		})
-		err = c.retryExecutor.Execute(ctx, operation)
+		err = c.retryExecutor.ExecuteWithMaxAttempts(ctx, int32(params.MaxRetries), operation)
 	} else {
 		err = operation()
 	}
@@ -395,6 +437,9 @@ func (c *AzureOpenAIClient) Chat(ctx context.Context, messages []llm.Message, pa
 	if params == nil {
 		params = llm.DefaultGenerateParams()
 	}
+	if err := llm.ValidateStopSequences("Azure OpenAI", params.StopSequences, maxStopSequences); err != nil {
+		return "", err
+	}
 
 	// Handle reasoning if specified
 	var systemMessage string
@@ -553,6 +598,33 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 		}
 	}
 
+	if err := interfaces.RejectFiles("Azure OpenAI", params.Files); err != nil {
+		return "", err
+	}
+	if err := interfaces.ValidateImages(params.Images); err != nil {
+		return "", err
+	}
+	if err := validateThinkingBudget(params.LLMConfig); err != nil {
+		return "", err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return "", err
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
 	// Set default values only if they're not provided
 	if params.LLMConfig == nil {
 		params.LLMConfig = &interfaces.LLMConfig{
@@ -579,42 +651,10 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 	// Convert tools to OpenAI format
 	openaiTools := make([]openai.ChatCompletionToolUnionParam, len(tools))
 	for i, tool := range tools {
-		// Convert ParameterSpec to JSON Schema
-		properties := make(map[string]interface{})
-		required := []string{}
-
-		for name, param := range tool.Parameters() {
-			properties[name] = map[string]interface{}{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				properties[name].(map[string]interface{})["default"] = param.Default
-			}
-			if param.Required {
-				required = append(required, name)
-			}
-			if param.Items != nil {
-				properties[name].(map[string]interface{})["items"] = map[string]interface{}{
-					"type": param.Items.Type,
-				}
-				if param.Items.Enum != nil {
-					properties[name].(map[string]interface{})["items"].(map[string]interface{})["enum"] = param.Items.Enum
-				}
-			}
-			if param.Enum != nil {
-				properties[name].(map[string]interface{})["enum"] = param.Enum
-			}
-		}
-
 		openaiTools[i] = openai.ChatCompletionFunctionTool(shared.FunctionDefinitionParam{
 			Name:        tool.Name(),
 			Description: openai.String(tool.Description()),
-			Parameters: map[string]interface{}{
-				"type":       "object",
-				"properties": properties,
-				"required":   required,
-			},
+			Parameters:  c.convertToOpenAISchema(tool.Parameters()),
 		})
 	}
 
@@ -670,7 +710,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 	}
 
 	// Add user message
-	messages = append(messages, openai.UserMessage(prompt))
+	messages = append(messages, userMessage(prompt, params.Images))
 
 	// Create request - use deployment name as model for Azure OpenAI
 	req := openai.ChatCompletionNewParams{
@@ -703,6 +743,9 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 			Name:   params.ResponseFormat.Name,
 			Schema: params.ResponseFormat.Schema,
 		}
+		if params.ResponseFormat.Strict {
+			jsonSchema.Strict = openai.Bool(true)
+		}
 
 		req.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
@@ -742,7 +785,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 			"iteration":         iteration + 1,
 			"maxIterations":     maxIterations,
 		})
-		resp, err := c.ChatService.Completions.New(ctx, req)
+		resp, err := c.ChatService.Completions.New(ctx, req, requestOptions(params)...)
 		if err != nil {
 			c.logger.Error(ctx, "Error from Azure OpenAI API", map[string]interface{}{
 				"error":      err.Error(),
@@ -794,12 +837,15 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 				}
 
 				type toolResult struct {
-					index  int
-					result string
-					err    error
+					arguments string
+					result    string
+					err       error
+					executed  bool
 				}
 
-				resultCh := make(chan toolResult, len(toolUsesWrapper.ToolUses))
+				// Tools run concurrently; each stores its result at the index of its call, so the
+				// results are reassembled in the order of the calls whatever order they finish in
+				toolsResults := make([]toolResult, len(toolUsesWrapper.ToolUses))
 				var wg sync.WaitGroup
 
 				// Launch goroutines for concurrent tool execution
@@ -817,7 +863,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 						paramsBytes, err := json.Marshal(parameters)
 						if err != nil {
 							c.logger.Error(ctx, "Error marshalling parameters", map[string]interface{}{"error": err.Error()})
-							resultCh <- toolResult{index: index, result: "", err: err}
+							toolsResults[index] = toolResult{err: err}
 							return
 						}
 
@@ -833,13 +879,13 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 						if tool == nil {
 							err := fmt.Errorf("tool not found: %s", toolName)
 							c.logger.Error(ctx, "Tool not found in parallel execution", map[string]interface{}{"toolName": toolName})
-							resultCh <- toolResult{index: index, result: "", err: err}
+							toolsResults[index] = toolResult{err: err}
 							return
 						}
 
 						c.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": toolName, "parameters": string(paramsBytes)})
 
-						result, err := tool.Execute(ctx, string(paramsBytes))
+						result, err := interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), tool, string(paramsBytes))
 
 						// Check for repetitive calls and add warning if needed
 						cacheKey := toolName + ":" + string(paramsBytes)
@@ -862,67 +908,49 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 							})
 						}
 
-						// Store tool call and result in memory if provided
-						if params.Memory != nil {
-							if err != nil {
-								// Store failed parallel tool call result
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:    "assistant",
-									Content: "",
-									ToolCalls: []interfaces.ToolCall{{
-										ID:        toolCall.ID,
-										Name:      toolName,
-										Arguments: string(paramsBytes),
-									}},
-								})
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:       "tool",
-									Content:    fmt.Sprintf("Error: %v", err),
-									ToolCallID: toolCall.ID,
-									Metadata: map[string]interface{}{
-										"tool_name": toolCall.Function.Name,
-									},
-								})
-							} else {
-								// Store successful parallel tool call and result
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:    "assistant",
-									Content: "",
-									ToolCalls: []interfaces.ToolCall{{
-										ID:        toolCall.ID,
-										Name:      toolName,
-										Arguments: string(paramsBytes),
-									}},
-								})
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:       "tool",
-									Content:    result,
-									ToolCallID: toolCall.ID,
-									Metadata: map[string]interface{}{
-										"tool_name": toolCall.Function.Name,
-									},
-								})
-							}
+						toolsResults[index] = toolResult{arguments: string(paramsBytes), result: result, err: err, executed: true}
+					}(i, toolUse)
+				}
+				wg.Wait()
+
+				// Store the executed tool calls and their results in memory, in the order of the calls
+				if params.Memory != nil {
+					for i, toolUse := range toolUsesWrapper.ToolUses {
+						executed := toolsResults[i]
+						if !executed.executed {
+							continue
 						}
 
-						resultCh <- toolResult{index: index, result: result, err: err}
-					}(i, toolUse)
+						content := executed.result
+						if executed.err != nil {
+							content = fmt.Sprintf("Error: %v", executed.err)
+						}
+						_ = params.Memory.AddMessage(ctx, interfaces.Message{
+							Role:    "assistant",
+							Content: "",
+							ToolCalls: []interfaces.ToolCall{{
+								ID:        toolCall.ID,
+								Name:      toolUse["recipient_name"].(string),
+								Arguments: executed.arguments,
+							}},
+						})
+						_ = params.Memory.AddMessage(ctx, interfaces.Message{
+							Role:       "tool",
+							Content:    content,
+							ToolCallID: toolCall.ID,
+							Metadata: map[string]interface{}{
+								"tool_name": toolCall.Function.Name,
+							},
+						})
+					}
 				}
 
-				// Close channel when all goroutines complete
-				go func() {
-					wg.Wait()
-					close(resultCh)
-				}()
-
-				// Collect results and check for errors
-				toolsResults := make([]string, len(toolUsesWrapper.ToolUses))
-				for result := range resultCh {
-					if result.err != nil {
-						c.logger.Error(ctx, "Error executing tool", map[string]interface{}{"error": result.err.Error()})
-						return "", fmt.Errorf("error executing tool: %s", result.err.Error())
+				// Report the error of the first failed call
+				for _, executed := range toolsResults {
+					if executed.err != nil {
+						c.logger.Error(ctx, "Error executing tool", map[string]interface{}{"error": executed.err.Error()})
+						return "", fmt.Errorf("error executing tool: %s", executed.err.Error())
 					}
-					toolsResults[result.index] = result.result
 				}
 
 				// For parallel tool use, we need to create a tool message
@@ -930,7 +958,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 				var structuredResults []string
 				for i, toolUse := range toolUsesWrapper.ToolUses {
 					toolName := toolUse["recipient_name"].(string)
-					result := toolsResults[i]
+					result := toolsResults[i].result
 					structuredResults = append(structuredResults, fmt.Sprintf("Tool: %s\nResult: %s", toolName, result))
 				}
 				messages = append(messages, openai.ToolMessage(strings.Join(structuredResults, "\n\n"), toolCall.ID))
@@ -1001,7 +1029,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 			// Execute the tool
 			c.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": selectedTool.Name()})
 			toolStartTime := time.Now()
-			toolResult, err := selectedTool.Execute(ctx, toolCall.Function.Arguments)
+			toolResult, err := interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), selectedTool, toolCall.Function.Arguments)
 			toolEndTime := time.Now()
 
 			// Check for repetitive calls and add warning if needed
@@ -1130,6 +1158,9 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 			Name:   params.ResponseFormat.Name,
 			Schema: params.ResponseFormat.Schema,
 		}
+		if params.ResponseFormat.Strict {
+			jsonSchema.Strict = openai.Bool(true)
+		}
 
 		finalReq.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
@@ -1147,7 +1178,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 		"messages": len(finalReq.Messages),
 	})
 
-	finalResp, err := c.ChatService.Completions.New(ctx, finalReq)
+	finalResp, err := c.ChatService.Completions.New(ctx, finalReq, requestOptions(params)...)
 	if err != nil {
 		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
 		return "", fmt.Errorf("failed to create final chat completion: %w", err)
@@ -1162,6 +1193,19 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 	return content, nil
 }
 
+// requestOptions returns the per-request options of a call. The SDK retries failed requests
+// on its own, so a call made with interfaces.WithNoRetry disables those retries as well, and
+// one made with interfaces.WithMaxRetries bounds them to the same number of attempts.
+func requestOptions(params *interfaces.GenerateOptions) []option.RequestOption {
+	switch {
+	case params.NoRetry:
+		return []option.RequestOption{option.WithMaxRetries(0)}
+	case params.MaxRetries > 0:
+		return []option.RequestOption{option.WithMaxRetries(params.MaxRetries - 1)}
+	}
+	return nil
+}
+
 // Name implements interfaces.LLM.Name
 func (c *AzureOpenAIClient) Name() string {
 	return "azure-openai"
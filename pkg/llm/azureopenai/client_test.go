@@ -1,6 +1,10 @@
 package azureopenai
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
@@ -516,3 +520,30 @@ func TestGenerateIntegration(t *testing.T) {
 	t.Logf("Response: %s", response)
 }
 */
+
+func TestGenerateRejectsThinkingBudget(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL, "test-deployment")
+	budget := interfaces.WithThinkingBudget(2048)
+
+	if _, err := client.Generate(context.Background(), "Count to ten", budget); err == nil || !strings.Contains(err.Error(), "Azure OpenAI does not support thinking budgets") {
+		t.Errorf("Expected a thinking budget error, got %v", err)
+	}
+	if _, err := client.GenerateWithTools(context.Background(), "Count to ten", nil, budget); err == nil {
+		t.Error("Expected a thinking budget error with tools")
+	}
+	if _, err := client.GenerateStream(context.Background(), "Count to ten", budget); err == nil {
+		t.Error("Expected a thinking budget error when streaming")
+	}
+	if _, err := client.GenerateWithToolsStream(context.Background(), "Count to ten", nil, budget); err == nil {
+		t.Error("Expected a thinking budget error when streaming with tools")
+	}
+	if requests != 0 {
+		t.Errorf("Expected the thinking budget to be rejected before any request, got %d requests", requests)
+	}
+}
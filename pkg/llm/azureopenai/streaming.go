@@ -3,10 +3,12 @@ package azureopenai
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/openai/openai-go/v2"
 	"github.com/openai/openai-go/v2/shared"
@@ -29,6 +31,33 @@ func (c *AzureOpenAIClient) GenerateStream(
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("Azure OpenAI", params.Files); err != nil {
+		return nil, err
+	}
+	if err := interfaces.ValidateImages(params.Images); err != nil {
+		return nil, err
+	}
+	if err := validateThinkingBudget(params.LLMConfig); err != nil {
+		return nil, err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Re-request the stream on mid-stream failures, each attempt streaming without resuming
+	if params.StreamRetry > 0 {
+		return llm.ResumeStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamRetry(0))...)
+		})
+	}
+
 	// Check for organization ID in context
 	defaultOrgID := "default"
 	if id, err := multitenancy.GetOrgID(ctx); err == nil {
@@ -72,9 +101,30 @@ func (c *AzureOpenAIClient) GenerateStream(
 					case "user":
 						messages = append(messages, openai.UserMessage(msg.Content))
 					case "assistant":
-						// For now, treat assistant messages with tool calls as regular assistant messages
-						// The tool results will be added separately as tool messages
-						if msg.Content != "" {
+						// Handle assistant messages with tool calls properly so that
+						// tool-only turns precede their tool messages
+						if len(msg.ToolCalls) > 0 {
+							// Create assistant message with tool calls
+							assistantMsg := openai.ChatCompletionMessage{
+								Role:    "assistant",
+								Content: msg.Content,
+							}
+
+							// Convert tool calls to OpenAI format
+							for _, tc := range msg.ToolCalls {
+								assistantMsg.ToolCalls = append(assistantMsg.ToolCalls, openai.ChatCompletionMessageToolCallUnion{
+									ID:   tc.ID,
+									Type: "function",
+									Function: openai.ChatCompletionMessageFunctionToolCallFunction{
+										Name:      tc.Name,
+										Arguments: tc.Arguments,
+									},
+								})
+							}
+
+							messages = append(messages, assistantMsg.ToParam())
+						} else if msg.Content != "" {
+							// Regular assistant message without tool calls
 							messages = append(messages, openai.AssistantMessage(msg.Content))
 						}
 					case "tool":
@@ -92,7 +142,7 @@ func (c *AzureOpenAIClient) GenerateStream(
 		}
 
 		// Add current user message
-		messages = append(messages, openai.UserMessage(prompt))
+		messages = append(messages, userMessage(prompt, params.Images))
 
 		// Create stream request - use deployment name as model for Azure OpenAI
 		streamParams := openai.ChatCompletionNewParams{
@@ -111,6 +161,9 @@ func (c *AzureOpenAIClient) GenerateStream(
 				Name:   params.ResponseFormat.Name,
 				Schema: params.ResponseFormat.Schema,
 			}
+			if params.ResponseFormat.Strict {
+				jsonSchema.Strict = openai.Bool(true)
+			}
 
 			streamParams.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 				OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
@@ -120,13 +173,13 @@ func (c *AzureOpenAIClient) GenerateStream(
 			}
 		}
 
+		// Request the token usage, sent in a final chunk
+		streamParams.StreamOptions = openai.ChatCompletionStreamOptionsParam{
+			IncludeUsage: openai.Bool(true),
+		}
+
 		// Handle reasoning models and reasoning config
 		if isReasoningModel(c.Model) || (params.LLMConfig != nil && params.LLMConfig.EnableReasoning) {
-			// o1 models or reasoning enabled - ensure we get usage info
-			streamParams.StreamOptions = openai.ChatCompletionStreamOptionsParam{
-				IncludeUsage: openai.Bool(true),
-			}
-
 			// Log reasoning support
 			if isReasoningModel(c.Model) {
 				c.logger.Debug(ctx, "Using reasoning model with built-in reasoning", map[string]interface{}{
@@ -172,7 +225,7 @@ func (c *AzureOpenAIClient) GenerateStream(
 		})
 
 		// Create stream
-		stream := c.ChatService.Completions.NewStreaming(ctx, streamParams)
+		stream := c.ChatService.Completions.NewStreaming(ctx, streamParams, requestOptions(params)...)
 
 		// Send initial message start event
 		eventChan <- interfaces.StreamEvent{
@@ -231,7 +284,8 @@ func (c *AzureOpenAIClient) GenerateStream(
 				// Check for finish reason
 				if choice.FinishReason != "" {
 					eventChan <- interfaces.StreamEvent{
-						Type: interfaces.StreamEventContentComplete,
+						Type:         interfaces.StreamEventContentComplete,
+						FinishReason: llm.ChatFinishReason(choice.FinishReason),
 						Metadata: map[string]interface{}{
 							"finish_reason": choice.FinishReason,
 							"choice_index":  choice.Index,
@@ -241,10 +295,14 @@ func (c *AzureOpenAIClient) GenerateStream(
 				}
 			}
 
-			// Handle usage information (especially for o1 models)
+			// Handle usage information, sent once in the final chunk
 			if chunk.Usage.PromptTokens > 0 || chunk.Usage.CompletionTokens > 0 || chunk.Usage.TotalTokens > 0 {
 				eventChan <- interfaces.StreamEvent{
-					Type:      interfaces.StreamEventContentDelta,
+					Type: interfaces.StreamEventUsage,
+					Usage: &interfaces.TokenUsage{
+						InputTokens:  chunk.Usage.PromptTokens,
+						OutputTokens: chunk.Usage.CompletionTokens,
+					},
 					Timestamp: time.Now(),
 					Metadata: map[string]interface{}{
 						"usage": map[string]interface{}{
@@ -330,6 +388,40 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("Azure OpenAI", params.Files); err != nil {
+		return nil, err
+	}
+	if err := interfaces.ValidateImages(params.Images); err != nil {
+		return nil, err
+	}
+	if err := validateThinkingBudget(params.LLMConfig); err != nil {
+		return nil, err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream, such as long tool calls
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateWithToolsStream(ctx, prompt, tools, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
 	// Set default max iterations if not provided
 	maxIterations := params.MaxIterations
 	if maxIterations == 0 {
@@ -391,9 +483,30 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 					case "user":
 						messages = append(messages, openai.UserMessage(msg.Content))
 					case "assistant":
-						// For now, treat assistant messages with tool calls as regular assistant messages
-						// The tool results will be added separately as tool messages
-						if msg.Content != "" {
+						// Handle assistant messages with tool calls properly so that
+						// tool-only turns precede their tool messages
+						if len(msg.ToolCalls) > 0 {
+							// Create assistant message with tool calls
+							assistantMsg := openai.ChatCompletionMessage{
+								Role:    "assistant",
+								Content: msg.Content,
+							}
+
+							// Convert tool calls to OpenAI format
+							for _, tc := range msg.ToolCalls {
+								assistantMsg.ToolCalls = append(assistantMsg.ToolCalls, openai.ChatCompletionMessageToolCallUnion{
+									ID:   tc.ID,
+									Type: "function",
+									Function: openai.ChatCompletionMessageFunctionToolCallFunction{
+										Name:      tc.Name,
+										Arguments: tc.Arguments,
+									},
+								})
+							}
+
+							messages = append(messages, assistantMsg.ToParam())
+						} else if msg.Content != "" {
+							// Regular assistant message without tool calls
 							messages = append(messages, openai.AssistantMessage(msg.Content))
 						}
 					case "tool":
@@ -411,7 +524,7 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 		}
 
 		// Add current user message
-		messages = append(messages, openai.UserMessage(prompt))
+		messages = append(messages, userMessage(prompt, params.Images))
 
 		// Store initial messages in memory
 		if params.Memory != nil {
@@ -522,7 +635,7 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 			}
 
 			// Create stream
-			stream := c.ChatService.Completions.NewStreaming(ctx, streamParams)
+			stream := c.ChatService.Completions.NewStreaming(ctx, streamParams, requestOptions(params)...)
 			if stream.Err() != nil {
 				c.logger.Error(ctx, "Failed to create Azure OpenAI streaming", map[string]interface{}{
 					"error": stream.Err().Error(),
@@ -628,9 +741,10 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 						// Finish last tool call
 						currentToolCall.Arguments = toolCallBuffer.String()
 						eventChan <- interfaces.StreamEvent{
-							Type:      interfaces.StreamEventToolUse,
-							ToolCall:  currentToolCall,
-							Timestamp: time.Now(),
+							Type:         interfaces.StreamEventToolUse,
+							ToolCall:     currentToolCall,
+							Timestamp:    time.Now(),
+							FinishReason: interfaces.FinishReasonToolUse,
 							Metadata: map[string]interface{}{
 								"finish_reason": "tool_calls",
 								"iteration":     iteration + 1,
@@ -717,7 +831,7 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 				}
 
 				// Execute the tool
-				result, err := foundTool.Execute(ctx, toolCall.Function.Arguments)
+				result, err := interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), foundTool, toolCall.Function.Arguments)
 				if err != nil {
 					c.logger.Error(ctx, "Tool execution error", map[string]interface{}{
 						"tool_name": toolCall.Function.Name,
@@ -864,7 +978,7 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 		})
 
 		// Create final stream
-		finalStream := c.ChatService.Completions.NewStreaming(ctx, finalStreamParams)
+		finalStream := c.ChatService.Completions.NewStreaming(ctx, finalStreamParams, requestOptions(params)...)
 		if finalStream.Err() != nil {
 			c.logger.Error(ctx, "Error in final streaming call without tools", map[string]interface{}{
 				"error": finalStream.Err().Error(),
@@ -902,7 +1016,8 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 				// Check for finish reason
 				if choice.FinishReason != "" {
 					eventChan <- interfaces.StreamEvent{
-						Type: interfaces.StreamEventContentComplete,
+						Type:         interfaces.StreamEventContentComplete,
+						FinishReason: llm.ChatFinishReason(choice.FinishReason),
 						Metadata: map[string]interface{}{
 							"finish_reason": choice.FinishReason,
 							"choice_index":  choice.Index,
@@ -958,34 +1073,13 @@ func (c *AzureOpenAIClient) convertToOpenAISchema(params map[string]interfaces.P
 	required := []string{}
 
 	for name, param := range params {
-		property := map[string]interface{}{
-			"type":        param.Type,
-			"description": param.Description,
-		}
-
-		if param.Default != nil {
-			property["default"] = param.Default
-		}
-
-		if param.Items != nil {
-			property["items"] = map[string]interface{}{
-				"type": param.Items.Type,
-			}
-			if param.Items.Enum != nil {
-				property["items"].(map[string]interface{})["enum"] = param.Items.Enum
-			}
-		}
-
-		if param.Enum != nil {
-			property["enum"] = param.Enum
-		}
-
-		properties[name] = property
+		properties[name] = convertParameterToOpenAISchema(param)
 
 		if param.Required {
 			required = append(required, name)
 		}
 	}
+	sort.Strings(required)
 
 	return map[string]interface{}{
 		"type":       "object",
@@ -993,3 +1087,42 @@ func (c *AzureOpenAIClient) convertToOpenAISchema(params map[string]interfaces.P
 		"required":   required,
 	}
 }
+
+// convertParameterToOpenAISchema converts a single parameter to JSON schema, including nested array items and object properties
+func convertParameterToOpenAISchema(param interfaces.ParameterSpec) map[string]interface{} {
+	property := map[string]interface{}{
+		"type": param.SchemaType(),
+	}
+
+	if param.Description != "" {
+		property["description"] = param.Description
+	}
+
+	if param.Default != nil {
+		property["default"] = param.Default
+	}
+
+	if param.Items != nil {
+		property["items"] = convertParameterToOpenAISchema(*param.Items)
+	}
+
+	if param.Enum != nil {
+		property["enum"] = param.Enum
+	}
+
+	if param.Properties != nil {
+		properties := make(map[string]interface{}, len(param.Properties))
+		required := []string{}
+		for name, nested := range param.Properties {
+			properties[name] = convertParameterToOpenAISchema(nested)
+			if nested.Required {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		property["properties"] = properties
+		property["required"] = required
+	}
+
+	return property
+}
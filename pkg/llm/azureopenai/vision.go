@@ -0,0 +1,25 @@
+package azureopenai
+
+import (
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/openai/openai-go/v2"
+)
+
+// userMessage returns the user message of a prompt with the images of the request attached.
+// Whether the deployment accepts images is checked by Azure OpenAI, since deployment names do
+// not tell the model.
+func userMessage(prompt string, images []interfaces.ImageInput) openai.ChatCompletionMessageParamUnion {
+	if len(images) == 0 {
+		return openai.UserMessage(prompt)
+	}
+
+	parts := []openai.ChatCompletionContentPartUnionParam{openai.TextContentPart(prompt)}
+	for _, image := range images {
+		url := image.URL
+		if url == "" {
+			url = image.DataURL()
+		}
+		parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: url}))
+	}
+	return openai.UserMessage(parts)
+}
@@ -0,0 +1,27 @@
+package azureopenai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestUserMessageWithImages(t *testing.T) {
+	images := []interfaces.ImageInput{
+		{URL: "https://example.com/cat.png"},
+		{Data: []byte("png"), MIMEType: "image/png"},
+	}
+
+	body, err := json.Marshal(userMessage("What is in these images?", images))
+	if err != nil {
+		t.Fatalf("Failed to marshal message: %v", err)
+	}
+
+	for _, want := range []string{`"What is in these images?"`, `"https://example.com/cat.png"`, `"data:image/png;base64,cG5n"`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected message to contain %s, got %s", want, body)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// Cache stores LLM responses by cache key
+type Cache interface {
+	// Get returns the response stored under key, reporting whether there is one
+	Get(ctx context.Context, key string) (string, bool, error)
+	// Set stores a response under key, expiring after ttl unless ttl is 0
+	Set(ctx context.Context, key string, response string, ttl time.Duration) error
+}
+
+// CacheKeyFunc computes the cache key of a Generate call from the model of the wrapped LLM,
+// the prompt and the generate options of the call
+type CacheKeyFunc func(ctx context.Context, model string, prompt string, params *interfaces.GenerateOptions) string
+
+// CachingOption configures a CachingMiddleware
+type CachingOption func(*CachingMiddleware)
+
+// WithTTL sets how long cached responses are kept (default: 0, until evicted by the cache)
+func WithTTL(ttl time.Duration) CachingOption {
+	return func(m *CachingMiddleware) {
+		m.ttl = ttl
+	}
+}
+
+// WithCacheKeyFunc replaces the computation of cache keys, see DefaultCacheKey
+func WithCacheKeyFunc(keyFunc CacheKeyFunc) CachingOption {
+	return func(m *CachingMiddleware) {
+		m.keyFunc = keyFunc
+	}
+}
+
+// cacheBypassKey is the context key of the cache bypass flag
+type cacheBypassKey struct{}
+
+// WithCacheBypass returns a context whose Generate calls skip the cache of a CachingMiddleware,
+// neither reading nor storing responses
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// CachingMiddleware wraps an LLM, returning the cached response of identical Generate calls
+// instead of calling the API again. It suits deterministic workflows, e.g. at temperature 0,
+// that ask the same question repeatedly. Calls with tools, images or files and streams are not cached,
+// and cache failures fall back to the wrapped LLM.
+type CachingMiddleware struct {
+	llm     interfaces.LLM
+	cache   Cache
+	ttl     time.Duration
+	keyFunc CacheKeyFunc
+	logger  logging.Logger
+}
+
+// NewCachingMiddleware creates a middleware caching the responses of inner in cache
+func NewCachingMiddleware(inner interfaces.LLM, cache Cache, options ...CachingOption) *CachingMiddleware {
+	m := &CachingMiddleware{
+		llm:     inner,
+		cache:   cache,
+		keyFunc: DefaultCacheKey,
+		logger:  logging.New(),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// DefaultCacheKey hashes everything of a call that affects the response: the model, the prompt,
+// the system message, the whole LLM configuration (sampling, token limits, stop sequences,
+// reasoning and thinking settings), the response format, the thinking policy and structured
+// output repair, along with the organization ID of the context so that tenants do not share
+// responses
+func DefaultCacheKey(ctx context.Context, model string, prompt string, params *interfaces.GenerateOptions) string {
+	orgID, _ := multitenancy.GetOrgID(ctx)
+	if orgID == "" {
+		orgID = params.OrgID
+	}
+
+	data, _ := json.Marshal(struct {
+		OrgID          string                     `json:"org_id"`
+		Model          string                     `json:"model"`
+		Prompt         string                     `json:"prompt"`
+		SystemMessage  string                     `json:"system_message"`
+		LLMConfig      *interfaces.LLMConfig      `json:"llm_config"`
+		ResponseFormat *interfaces.ResponseFormat `json:"response_format"`
+		ThinkingPolicy interfaces.ThinkingPolicy  `json:"thinking_policy"`
+		OutputRepair   int                        `json:"output_repair"`
+	}{orgID, model, prompt, params.SystemMessage, params.LLMConfig, params.ResponseFormat, params.ThinkingPolicy, params.OutputRepair})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Generate returns the cached response of an identical call, or generates and caches it
+func (m *CachingMiddleware) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	params := &interfaces.GenerateOptions{LLMConfig: &interfaces.LLMConfig{}}
+	for _, option := range options {
+		option(params)
+	}
+	if bypass, _ := ctx.Value(cacheBypassKey{}).(bool); bypass || len(params.Images) > 0 || len(params.Files) > 0 {
+		return m.llm.Generate(ctx, prompt, options...)
+	}
+
+	key := m.keyFunc(ctx, m.model(), prompt, params)
+	response, found, err := m.cache.Get(ctx, key)
+	if err != nil {
+		m.logger.Warn(ctx, "Failed to read LLM response cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if found {
+		m.logger.Debug(ctx, "LLM response served from cache", map[string]interface{}{
+			"model": m.model(),
+		})
+		return response, nil
+	}
+
+	response, err = m.llm.Generate(ctx, prompt, options...)
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.cache.Set(ctx, key, response, m.ttl); err != nil {
+		m.logger.Warn(ctx, "Failed to write LLM response cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return response, nil
+}
+
+// GenerateWithTools generates text from a prompt with tools, without caching, since tool calls
+// may have side effects
+func (m *CachingMiddleware) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.llm.GenerateWithTools(ctx, prompt, tools, options...)
+}
+
+// GenerateStream streams text from a prompt, without caching
+func (m *CachingMiddleware) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	streamingLLM, ok := m.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("LLM %s does not support streaming", m.llm.Name())
+	}
+	return streamingLLM.GenerateStream(ctx, prompt, options...)
+}
+
+// GenerateWithToolsStream streams text from a prompt with tools, without caching
+func (m *CachingMiddleware) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	streamingLLM, ok := m.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("LLM %s does not support streaming", m.llm.Name())
+	}
+	return streamingLLM.GenerateWithToolsStream(ctx, prompt, tools, options...)
+}
+
+// Name returns the name of the wrapped LLM
+func (m *CachingMiddleware) Name() string {
+	return m.llm.Name()
+}
+
+// SupportsStreaming returns whether the wrapped LLM supports streaming
+func (m *CachingMiddleware) SupportsStreaming() bool {
+	return m.llm.SupportsStreaming()
+}
+
+// GetModel returns the model of the wrapped LLM, if it reports one
+func (m *CachingMiddleware) GetModel() string {
+	return m.model()
+}
+
+// model returns the model of the wrapped LLM, falling back to its name
+func (m *CachingMiddleware) model() string {
+	return modelOf(m.llm)
+}
+
+// LRUCache is an in-memory Cache evicting the least recently used responses beyond its capacity
+type LRUCache struct {
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// lruEntry is a response stored in an LRUCache
+type lruEntry struct {
+	key       string
+	response  string
+	expiresAt time.Time
+}
+
+// NewLRUCache creates an in-memory cache holding at most capacity responses
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache
+func (c *LRUCache) Get(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return "", false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return "", false, nil
+	}
+
+	c.order.MoveToFront(element)
+	return entry.response, true, nil
+}
+
+// Set implements Cache
+func (c *LRUCache) Set(ctx context.Context, key string, response string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.response = response
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, response: response, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of cached responses
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// RedisCache is a Cache backed by Redis, sharing responses between processes
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// RedisCacheOption configures a RedisCache
+type RedisCacheOption func(*RedisCache)
+
+// WithRedisCachePrefix sets the prefix of the Redis keys of the cached responses
+func WithRedisCachePrefix(prefix string) RedisCacheOption {
+	return func(c *RedisCache) {
+		c.prefix = prefix
+	}
+}
+
+// NewRedisCache creates a cache backed by Redis
+func NewRedisCache(client *redis.Client, options ...RedisCacheOption) *RedisCache {
+	cache := &RedisCache{
+		client: client,
+		prefix: "llm_cache:",
+	}
+	for _, option := range options {
+		option(cache)
+	}
+	return cache
+}
+
+// Get implements Cache
+func (c *RedisCache) Get(ctx context.Context, key string) (string, bool, error) {
+	response, err := c.client.Get(ctx, c.prefix+key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read response from Redis: %w", err)
+	}
+	return response, true, nil
+}
+
+// Set implements Cache
+func (c *RedisCache) Set(ctx context.Context, key string, response string, ttl time.Duration) error {
+	if err := c.client.Set(ctx, c.prefix+key, response, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to write response to Redis: %w", err)
+	}
+	return nil
+}
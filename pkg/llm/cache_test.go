@@ -0,0 +1,212 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// countingLLM answers every call with a numbered response, counting the calls
+type countingLLM struct {
+	calls int
+}
+
+func (m *countingLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	m.calls++
+	return fmt.Sprintf("response %d", m.calls), nil
+}
+
+func (m *countingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *countingLLM) Name() string            { return "counting" }
+func (m *countingLLM) SupportsStreaming() bool { return false }
+func (m *countingLLM) GetModel() string        { return "counting-model" }
+
+func TestCachingMiddleware(t *testing.T) {
+	inner := &countingLLM{}
+	cached := NewCachingMiddleware(inner, NewLRUCache(20))
+	ctx := context.Background()
+	temperature := func(value float64) interfaces.GenerateOption {
+		return func(options *interfaces.GenerateOptions) { options.LLMConfig.Temperature = value }
+	}
+	config := func(set func(*interfaces.LLMConfig)) interfaces.GenerateOption {
+		return func(options *interfaces.GenerateOptions) { set(options.LLMConfig) }
+	}
+
+	first, err := cached.Generate(ctx, "What is 2+2?", temperature(0))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := cached.Generate(ctx, "What is 2+2?", temperature(0))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != "response 1" || second != first || inner.calls != 1 {
+		t.Errorf("Expected the identical call to be served from the cache, got %q, %q after %d calls", first, second, inner.calls)
+	}
+
+	// Any field of the key makes a different call
+	variants := []struct {
+		ctx     context.Context
+		prompt  string
+		options []interfaces.GenerateOption
+	}{
+		{ctx, "What is 3+3?", []interfaces.GenerateOption{temperature(0)}},
+		{ctx, "What is 2+2?", []interfaces.GenerateOption{temperature(0.5)}},
+		{ctx, "What is 2+2?", []interfaces.GenerateOption{temperature(0), interfaces.WithSystemMessage("Answer in French")}},
+		{ctx, "What is 2+2?", []interfaces.GenerateOption{temperature(0), interfaces.WithResponseFormat(interfaces.ResponseFormat{Type: interfaces.ResponseFormatJSON, Name: "Answer"})}},
+		{multitenancy.WithOrgID(ctx, "other-org"), "What is 2+2?", []interfaces.GenerateOption{temperature(0)}},
+		{ctx, "What is 2+2?", []interfaces.GenerateOption{temperature(0), config(func(c *interfaces.LLMConfig) { c.MaxTokens = 10 })}},
+		{ctx, "What is 2+2?", []interfaces.GenerateOption{temperature(0), config(func(c *interfaces.LLMConfig) { c.TopP = 0.5 })}},
+		{ctx, "What is 2+2?", []interfaces.GenerateOption{temperature(0), config(func(c *interfaces.LLMConfig) { c.StopSequences = []string{"4"} })}},
+		{ctx, "What is 2+2?", []interfaces.GenerateOption{temperature(0), interfaces.WithReasoningEffort("high")}},
+		{ctx, "What is 2+2?", []interfaces.GenerateOption{temperature(0), config(func(c *interfaces.LLMConfig) { c.ThinkingBudget = 1024 })}},
+	}
+	for i, variant := range variants {
+		if _, err := cached.Generate(variant.ctx, variant.prompt, variant.options...); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if inner.calls != i+2 {
+			t.Errorf("Expected variant %d to miss the cache, got %d calls", i, inner.calls)
+		}
+	}
+
+	// The bypass flag neither reads nor writes the cache
+	response, err := cached.Generate(WithCacheBypass(ctx), "What is 2+2?", temperature(0))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response == first {
+		t.Errorf("Expected the bypassed call to reach the LLM, got the cached %q", response)
+	}
+	if response, _ := cached.Generate(ctx, "What is 2+2?", temperature(0)); response != first {
+		t.Errorf("Expected the bypassed call not to replace the cached response, got %q", response)
+	}
+}
+
+func TestCachingMiddlewareMaxTokens(t *testing.T) {
+	inner := &countingLLM{}
+	cached := NewCachingMiddleware(inner, NewLRUCache(10))
+	ctx := context.Background()
+	maxTokens := func(value int) interfaces.GenerateOption {
+		return func(options *interfaces.GenerateOptions) { options.LLMConfig.MaxTokens = value }
+	}
+
+	short, _ := cached.Generate(ctx, "Describe Go", maxTokens(10))
+	long, _ := cached.Generate(ctx, "Describe Go", maxTokens(1000))
+	if short == long || inner.calls != 2 {
+		t.Errorf("Expected calls differing only in MaxTokens not to share a response, got %q and %q", short, long)
+	}
+}
+
+func TestCachingMiddlewareFiles(t *testing.T) {
+	inner := &countingLLM{}
+	cached := NewCachingMiddleware(inner, NewLRUCache(10))
+	ctx := context.Background()
+
+	// The same prompt about different files must not share a response
+	first, _ := cached.Generate(ctx, "Summarize the file", interfaces.WithFiles([]interfaces.FileInput{{URI: "files/a", MIMEType: "application/pdf"}}))
+	second, _ := cached.Generate(ctx, "Summarize the file", interfaces.WithFiles([]interfaces.FileInput{{URI: "files/b", MIMEType: "application/pdf"}}))
+	if first == second || inner.calls != 2 {
+		t.Errorf("Expected calls with files to bypass the cache, got %q and %q", first, second)
+	}
+}
+
+func TestCachingMiddlewareOptions(t *testing.T) {
+	inner := &countingLLM{}
+	var models []string
+	cached := NewCachingMiddleware(inner, NewLRUCache(10),
+		WithTTL(10*time.Millisecond),
+		WithCacheKeyFunc(func(ctx context.Context, model string, prompt string, params *interfaces.GenerateOptions) string {
+			models = append(models, model)
+			return "same-key"
+		}),
+	)
+	ctx := context.Background()
+
+	first, _ := cached.Generate(ctx, "first prompt")
+	second, _ := cached.Generate(ctx, "second prompt")
+	if second != first || inner.calls != 1 {
+		t.Errorf("Expected the custom key to make the calls identical, got %q and %q", first, second)
+	}
+	if models[0] != "counting-model" {
+		t.Errorf("Expected the model of the wrapped LLM, got %q", models[0])
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if third, _ := cached.Generate(ctx, "first prompt"); third == first || inner.calls != 2 {
+		t.Errorf("Expected the expired response to be generated again, got %q", third)
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(2)
+	_ = cache.Set(ctx, "a", "1", 0)
+	_ = cache.Set(ctx, "b", "2", 0)
+
+	// Reading a makes b the least recently used
+	if _, found, _ := cache.Get(ctx, "a"); !found {
+		t.Fatal("Expected a to be cached")
+	}
+	_ = cache.Set(ctx, "c", "3", 0)
+
+	if _, found, _ := cache.Get(ctx, "b"); found {
+		t.Error("Expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, found, _ := cache.Get(ctx, key); !found {
+			t.Errorf("Expected %s to be cached", key)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 cached responses, got %d", cache.Len())
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	inner := &countingLLM{}
+	cached := NewCachingMiddleware(inner, NewRedisCache(client, WithRedisCachePrefix("test:")), WithTTL(time.Minute))
+	ctx := context.Background()
+
+	first, err := cached.Generate(ctx, "What is 2+2?")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := cached.Generate(ctx, "What is 2+2?")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if second != first || inner.calls != 1 {
+		t.Errorf("Expected the identical call to be served from Redis, got %q and %q", first, second)
+	}
+
+	keys := mr.Keys()
+	if len(keys) != 1 || mr.TTL(keys[0]) != time.Minute {
+		t.Errorf("Expected one response stored with the TTL, got keys %v", keys)
+	}
+
+	// Responses expire with the TTL
+	mr.FastForward(2 * time.Minute)
+	if third, _ := cached.Generate(ctx, "What is 2+2?"); third == first {
+		t.Errorf("Expected the expired response to be generated again, got %q", third)
+	}
+}
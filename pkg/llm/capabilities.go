@@ -0,0 +1,77 @@
+package llm
+
+// ModelCapabilities describes the token limits of a model
+type ModelCapabilities struct {
+	// MaxInputTokens is the size of the context window of the model, the maximum number of
+	// tokens of a request including the tokens reserved for the response
+	MaxInputTokens int
+	// MaxOutputTokens is the maximum number of tokens of a single response, 0 if unknown
+	MaxOutputTokens int
+}
+
+// capabilities is the token limit table, keyed like the price table. Output limits missing
+// from the table come from the price table.
+var capabilities = map[string]ModelCapabilities{
+	// OpenAI
+	"gpt-5":         {MaxInputTokens: 400000},
+	"gpt-5-mini":    {MaxInputTokens: 400000},
+	"gpt-5-nano":    {MaxInputTokens: 400000},
+	"gpt-4.1":       {MaxInputTokens: 1047576},
+	"gpt-4.1-mini":  {MaxInputTokens: 1047576},
+	"gpt-4.1-nano":  {MaxInputTokens: 1047576},
+	"gpt-4o":        {MaxInputTokens: 128000},
+	"gpt-4o-mini":   {MaxInputTokens: 128000},
+	"gpt-4-turbo":   {MaxInputTokens: 128000},
+	"gpt-4":         {MaxInputTokens: 8192},
+	"gpt-3.5-turbo": {MaxInputTokens: 16385},
+	"o1":            {MaxInputTokens: 200000},
+	"o1-mini":       {MaxInputTokens: 128000},
+	"o3":            {MaxInputTokens: 200000},
+	"o3-pro":        {MaxInputTokens: 200000},
+	"o3-mini":       {MaxInputTokens: 200000},
+	"o4-mini":       {MaxInputTokens: 200000},
+
+	// Anthropic
+	"claude-opus-4-1":   {MaxInputTokens: 200000},
+	"claude-opus-4":     {MaxInputTokens: 200000},
+	"claude-sonnet-4":   {MaxInputTokens: 200000},
+	"claude-3-7-sonnet": {MaxInputTokens: 200000},
+	"claude-3-5-sonnet": {MaxInputTokens: 200000},
+	"claude-3-5-haiku":  {MaxInputTokens: 200000},
+	"claude-3-opus":     {MaxInputTokens: 200000},
+	"claude-3-haiku":    {MaxInputTokens: 200000},
+
+	// Gemini
+	"gemini-2.5-pro":        {MaxInputTokens: 1048576},
+	"gemini-2.5-flash":      {MaxInputTokens: 1048576},
+	"gemini-2.5-flash-lite": {MaxInputTokens: 1048576},
+	"gemini-2.0-flash":      {MaxInputTokens: 1048576},
+	"gemini-2.0-flash-lite": {MaxInputTokens: 1048576},
+	"gemini-1.5-pro":        {MaxInputTokens: 2097152},
+	"gemini-1.5-flash":      {MaxInputTokens: 1048576},
+	"gemini-1.5-flash-8b":   {MaxInputTokens: 1048576},
+}
+
+// RegisterModelCapabilities adds or replaces the token limits of a model, e.g. a fine-tuned or
+// self-hosted model. Dated versions of the model share its limits.
+func RegisterModelCapabilities(model string, modelCapabilities ModelCapabilities) {
+	pricesMu.Lock()
+	defer pricesMu.Unlock()
+	capabilities[model] = modelCapabilities
+}
+
+// GetModelCapabilities returns the token limits of a model, matched like LookupModelPrice
+func GetModelCapabilities(model string) (ModelCapabilities, bool) {
+	pricesMu.RLock()
+	defer pricesMu.RUnlock()
+
+	modelCapabilities, ok := lookupModel(capabilities, model)
+	if !ok {
+		return ModelCapabilities{}, false
+	}
+	if modelCapabilities.MaxOutputTokens == 0 {
+		price, _ := lookupModel(prices, model)
+		modelCapabilities.MaxOutputTokens = price.MaxOutputTokens
+	}
+	return modelCapabilities, true
+}
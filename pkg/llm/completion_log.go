@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// Completion log levels accepted by LogCompletion
+const (
+	CompletionLogLevelDebug = "debug"
+	CompletionLogLevelInfo  = "info"
+	CompletionLogLevelWarn  = "warn"
+	CompletionLogLevelError = "error"
+	// CompletionLogLevelNone disables completion logs
+	CompletionLogLevelNone = "none"
+)
+
+// CompletionSummary describes a single finished LLM API call
+type CompletionSummary struct {
+	// Provider is the name of the LLM provider, e.g. "openai"
+	Provider string
+	// Model is the model that served the call
+	Model string
+	// Latency is the wall time of the call, including retries
+	Latency time.Duration
+	// PromptTokens is the number of input tokens reported by the provider
+	PromptTokens int64
+	// ResponseTokens is the number of output tokens reported by the provider
+	ResponseTokens int64
+	// Retries is the number of attempts made after the first one
+	Retries int
+	// FinishReason is the reason the model stopped generating, if the provider reported one
+	FinishReason interfaces.FinishReason
+	// Err is the error the call failed with, if any
+	Err error
+}
+
+// CompletionObserver receives the summary of each finished LLM call made with a context
+// returned by WithCompletionObserver
+type CompletionObserver func(ctx context.Context, summary CompletionSummary)
+
+type completionObserverKey struct{}
+
+// WithCompletionObserver returns a context whose LLM calls report their completion summary to
+// the observer, in addition to any observer already set on ctx. Observers are notified by
+// LogCompletion regardless of the completion log level.
+func WithCompletionObserver(ctx context.Context, observer CompletionObserver) context.Context {
+	if parent, ok := ctx.Value(completionObserverKey{}).(CompletionObserver); ok {
+		child := observer
+		observer = func(ctx context.Context, summary CompletionSummary) {
+			parent(ctx, summary)
+			child(ctx, summary)
+		}
+	}
+	return context.WithValue(ctx, completionObserverKey{}, observer)
+}
+
+// LogCompletion emits the standardized summary entry of an LLM call, so that calls of every
+// provider can be aggregated the same way. An empty level logs at info.
+func LogCompletion(ctx context.Context, logger logging.Logger, level string, summary CompletionSummary) {
+	if observer, ok := ctx.Value(completionObserverKey{}).(CompletionObserver); ok {
+		observer(ctx, summary)
+	}
+
+	if logger == nil || level == CompletionLogLevelNone {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"provider":        summary.Provider,
+		"model":           summary.Model,
+		"latency_ms":      summary.Latency.Milliseconds(),
+		"prompt_tokens":   summary.PromptTokens,
+		"response_tokens": summary.ResponseTokens,
+		"total_tokens":    summary.PromptTokens + summary.ResponseTokens,
+		"retries":         summary.Retries,
+		"status":          "success",
+	}
+	if summary.FinishReason != "" {
+		fields["finish_reason"] = string(summary.FinishReason)
+	}
+	if summary.Err != nil {
+		fields["status"] = "error"
+		fields["error"] = summary.Err.Error()
+	}
+
+	const msg = "LLM call completed"
+	switch level {
+	case CompletionLogLevelDebug:
+		logger.Debug(ctx, msg, fields)
+	case CompletionLogLevelWarn:
+		logger.Warn(ctx, msg, fields)
+	case CompletionLogLevelError:
+		logger.Error(ctx, msg, fields)
+	default:
+		logger.Info(ctx, msg, fields)
+	}
+}
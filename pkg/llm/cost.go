@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ModelPrice is the list price of a model
+type ModelPrice struct {
+	// InputPerMillion is the price in USD of one million input tokens
+	InputPerMillion float64
+	// OutputPerMillion is the price in USD of one million output tokens
+	OutputPerMillion float64
+	// MaxOutputTokens is the maximum number of tokens of a single response
+	MaxOutputTokens int
+}
+
+var (
+	pricesMu sync.RWMutex
+	// prices is the list price table, keyed by model name without date or version suffix
+	prices = map[string]ModelPrice{
+		// OpenAI
+		"gpt-5":         {InputPerMillion: 1.25, OutputPerMillion: 10, MaxOutputTokens: 128000},
+		"gpt-5-mini":    {InputPerMillion: 0.25, OutputPerMillion: 2, MaxOutputTokens: 128000},
+		"gpt-5-nano":    {InputPerMillion: 0.05, OutputPerMillion: 0.40, MaxOutputTokens: 128000},
+		"gpt-4.1":       {InputPerMillion: 2, OutputPerMillion: 8, MaxOutputTokens: 32768},
+		"gpt-4.1-mini":  {InputPerMillion: 0.40, OutputPerMillion: 1.60, MaxOutputTokens: 32768},
+		"gpt-4.1-nano":  {InputPerMillion: 0.10, OutputPerMillion: 0.40, MaxOutputTokens: 32768},
+		"gpt-4o":        {InputPerMillion: 2.50, OutputPerMillion: 10, MaxOutputTokens: 16384},
+		"gpt-4o-mini":   {InputPerMillion: 0.15, OutputPerMillion: 0.60, MaxOutputTokens: 16384},
+		"gpt-4-turbo":   {InputPerMillion: 10, OutputPerMillion: 30, MaxOutputTokens: 4096},
+		"gpt-4":         {InputPerMillion: 30, OutputPerMillion: 60, MaxOutputTokens: 8192},
+		"gpt-3.5-turbo": {InputPerMillion: 0.50, OutputPerMillion: 1.50, MaxOutputTokens: 4096},
+		"o1":            {InputPerMillion: 15, OutputPerMillion: 60, MaxOutputTokens: 100000},
+		"o1-mini":       {InputPerMillion: 1.10, OutputPerMillion: 4.40, MaxOutputTokens: 65536},
+		"o3":            {InputPerMillion: 2, OutputPerMillion: 8, MaxOutputTokens: 100000},
+		"o3-pro":        {InputPerMillion: 20, OutputPerMillion: 80, MaxOutputTokens: 100000},
+		"o3-mini":       {InputPerMillion: 1.10, OutputPerMillion: 4.40, MaxOutputTokens: 100000},
+		"o4-mini":       {InputPerMillion: 1.10, OutputPerMillion: 4.40, MaxOutputTokens: 100000},
+
+		// Anthropic
+		"claude-opus-4-1":   {InputPerMillion: 15, OutputPerMillion: 75, MaxOutputTokens: 32000},
+		"claude-opus-4":     {InputPerMillion: 15, OutputPerMillion: 75, MaxOutputTokens: 32000},
+		"claude-sonnet-4":   {InputPerMillion: 3, OutputPerMillion: 15, MaxOutputTokens: 64000},
+		"claude-3-7-sonnet": {InputPerMillion: 3, OutputPerMillion: 15, MaxOutputTokens: 64000},
+		"claude-3-5-sonnet": {InputPerMillion: 3, OutputPerMillion: 15, MaxOutputTokens: 8192},
+		"claude-3-5-haiku":  {InputPerMillion: 0.80, OutputPerMillion: 4, MaxOutputTokens: 8192},
+		"claude-3-opus":     {InputPerMillion: 15, OutputPerMillion: 75, MaxOutputTokens: 4096},
+		"claude-3-haiku":    {InputPerMillion: 0.25, OutputPerMillion: 1.25, MaxOutputTokens: 4096},
+
+		// Gemini, at the prices of prompts up to 128k (1.5) or 200k (2.5) tokens
+		"gemini-2.5-pro":        {InputPerMillion: 1.25, OutputPerMillion: 10, MaxOutputTokens: 65536},
+		"gemini-2.5-flash":      {InputPerMillion: 0.30, OutputPerMillion: 2.50, MaxOutputTokens: 65536},
+		"gemini-2.5-flash-lite": {InputPerMillion: 0.10, OutputPerMillion: 0.40, MaxOutputTokens: 65536},
+		"gemini-2.0-flash":      {InputPerMillion: 0.10, OutputPerMillion: 0.40, MaxOutputTokens: 8192},
+		"gemini-2.0-flash-lite": {InputPerMillion: 0.075, OutputPerMillion: 0.30, MaxOutputTokens: 8192},
+		"gemini-1.5-pro":        {InputPerMillion: 1.25, OutputPerMillion: 5, MaxOutputTokens: 8192},
+		"gemini-1.5-flash":      {InputPerMillion: 0.075, OutputPerMillion: 0.30, MaxOutputTokens: 8192},
+		"gemini-1.5-flash-8b":   {InputPerMillion: 0.0375, OutputPerMillion: 0.15, MaxOutputTokens: 8192},
+	}
+)
+
+// RegisterModelPrice adds or replaces the price of a model, e.g. a fine-tuned model or a
+// negotiated price. Dated versions of the model, such as model-20250514, share its price.
+func RegisterModelPrice(model string, price ModelPrice) {
+	pricesMu.Lock()
+	defer pricesMu.Unlock()
+	prices[model] = price
+}
+
+// LookupModelPrice returns the price of a model. Models are matched by exact name first, then
+// by the longest known name followed by a version suffix, so that gpt-4o-2024-08-06 and
+// claude-3-5-sonnet-latest get the price of gpt-4o and claude-3-5-sonnet.
+func LookupModelPrice(model string) (ModelPrice, bool) {
+	pricesMu.RLock()
+	defer pricesMu.RUnlock()
+
+	return lookupModel(prices, model)
+}
+
+// lookupModel returns the entry of a model in a table keyed by model name, matching the exact
+// name first, then the longest known name followed by a version suffix
+func lookupModel[V any](table map[string]V, model string) (V, bool) {
+	model = strings.TrimPrefix(model, "models/")
+	if value, ok := table[model]; ok {
+		return value, true
+	}
+
+	var match string
+	for name := range table {
+		if strings.HasPrefix(model, name+"-") && len(name) > len(match) {
+			match = name
+		}
+	}
+	if match == "" {
+		var zero V
+		return zero, false
+	}
+	return table[match], true
+}
+
+// EstimateCost returns the cost in USD of a request to a model with the given number of
+// prompt tokens, assuming the response uses all of maxOutputTokens. Unknown models return
+// an error, see RegisterModelPrice.
+func EstimateCost(model string, promptTokens, maxOutputTokens int) (float64, error) {
+	if promptTokens < 0 || maxOutputTokens < 0 {
+		return 0, fmt.Errorf("token counts must not be negative")
+	}
+
+	price, ok := LookupModelPrice(model)
+	if !ok {
+		return 0, fmt.Errorf("no price known for model %q", model)
+	}
+
+	return price.Cost(int64(promptTokens), int64(maxOutputTokens)), nil
+}
+
+// Cost returns the cost in USD of a call consuming the given numbers of tokens at this price
+func (p ModelPrice) Cost(inputTokens, outputTokens int64) float64 {
+	return (float64(inputTokens)*p.InputPerMillion + float64(outputTokens)*p.OutputPerMillion) / 1e6
+}
+
+// EstimateTokens approximates the number of tokens of text for cost estimates, counting
+// one token per four characters. Actual counts depend on the tokenizer of the model.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
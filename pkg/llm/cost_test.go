@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		model    string
+		prompt   int
+		output   int
+		expected float64
+	}{
+		{"gpt-4o", 1000, 500, 0.0075},
+		{"gpt-4o-2024-08-06", 1000, 500, 0.0075},
+		{"gpt-4o-mini", 1000000, 1000000, 0.75},
+		{"claude-sonnet-4-20250514", 2000, 1000, 0.021},
+		{"claude-3-5-haiku-latest", 1000, 1000, 0.0048},
+		{"gemini-2.5-flash-lite", 1000000, 0, 0.10},
+		{"models/gemini-2.5-pro", 0, 1000, 0.01},
+	}
+
+	for _, tt := range tests {
+		cost, err := EstimateCost(tt.model, tt.prompt, tt.output)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.model, err)
+		}
+		if math.Abs(cost-tt.expected) > 1e-9 {
+			t.Errorf("%s: expected cost %v, got %v", tt.model, tt.expected, cost)
+		}
+	}
+}
+
+func TestEstimateCostUnknownModel(t *testing.T) {
+	for _, model := range []string{"my-model", "gpt-4.5-preview", "claude"} {
+		if _, err := EstimateCost(model, 100, 100); err == nil {
+			t.Errorf("Expected an error for unknown model %q", model)
+		}
+	}
+
+	if _, err := EstimateCost("gpt-4o", -1, 100); err == nil {
+		t.Error("Expected an error for negative token counts")
+	}
+}
+
+func TestRegisterModelPrice(t *testing.T) {
+	RegisterModelPrice("custom-model", ModelPrice{InputPerMillion: 1, OutputPerMillion: 2, MaxOutputTokens: 1000})
+
+	cost, err := EstimateCost("custom-model-v2", 1000000, 1000000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cost != 3 {
+		t.Errorf("Expected cost 3, got %v", cost)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if tokens := EstimateTokens("abcdefgh"); tokens != 2 {
+		t.Errorf("Expected 2 tokens, got %d", tokens)
+	}
+	if tokens := EstimateTokens("abcdefghi"); tokens != 3 {
+		t.Errorf("Expected 3 tokens, got %d", tokens)
+	}
+}
+
+func TestGetModelCapabilities(t *testing.T) {
+	capabilities, ok := GetModelCapabilities("gpt-4o-2024-08-06")
+	if !ok {
+		t.Fatal("Expected the capabilities of gpt-4o")
+	}
+	if capabilities.MaxInputTokens != 128000 || capabilities.MaxOutputTokens != 16384 {
+		t.Errorf("Expected the limits of gpt-4o, got %+v", capabilities)
+	}
+
+	if _, ok := GetModelCapabilities("my-model"); ok {
+		t.Error("Expected no capabilities for an unknown model")
+	}
+
+	RegisterModelCapabilities("my-model", ModelCapabilities{MaxInputTokens: 32768, MaxOutputTokens: 2048})
+	if capabilities, ok := GetModelCapabilities("my-model-v2"); !ok || capabilities.MaxInputTokens != 32768 {
+		t.Errorf("Expected the registered capabilities, got %+v", capabilities)
+	}
+	if _, ok := LookupModelPrice("my-model"); ok {
+		t.Error("Expected registering capabilities not to register a price")
+	}
+}
@@ -0,0 +1,245 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// ServedBy reports which LLM of a FallbackChain served a call, see WithServedBy
+type ServedBy struct {
+	mu       sync.Mutex
+	provider string
+	model    string
+}
+
+// Provider returns the name of the LLM that served the call, empty until one succeeds
+func (s *ServedBy) Provider() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.provider
+}
+
+// Model returns the model of the LLM that served the call, empty until one succeeds
+func (s *ServedBy) Model() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.model
+}
+
+func (s *ServedBy) set(provider, model string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provider = provider
+	s.model = model
+}
+
+// servedByKey is the context key of the ServedBy of a call
+type servedByKey struct{}
+
+// WithServedBy returns a context whose FallbackChain calls record the LLM that served them
+// in the returned ServedBy
+func WithServedBy(ctx context.Context) (context.Context, *ServedBy) {
+	servedBy := &ServedBy{}
+	return context.WithValue(ctx, servedByKey{}, servedBy), servedBy
+}
+
+// FallbackChain is an LLM trying a primary LLM and, when it fails, each fallback in order, so
+// that the outage of one provider does not break agents. Each LLM applies its own retry
+// policy before the next one is tried. Options and tools are passed to every LLM unchanged.
+// A call with tools is not retried with the next LLM once one of its tools ran, so that tools
+// with side effects don't run twice and the memory doesn't record the tool calls again.
+type FallbackChain struct {
+	llms   []interfaces.LLM
+	logger logging.Logger
+}
+
+// NewFallbackChain creates an LLM trying primary, then each of fallbacks in order
+func NewFallbackChain(primary interfaces.LLM, fallbacks ...interfaces.LLM) *FallbackChain {
+	return &FallbackChain{
+		llms:   append([]interfaces.LLM{primary}, fallbacks...),
+		logger: logging.New(),
+	}
+}
+
+// try calls each LLM in order until one succeeds, skipping those call reports as not attempted.
+// After a failure, the next LLM is only tried if canFallBack, when set, allows it.
+func (c *FallbackChain) try(ctx context.Context, call func(llm interfaces.LLM) (bool, error), canFallBack func() bool) error {
+	var errs []error
+	for i, llm := range c.llms {
+		attempted, err := call(llm)
+		if !attempted {
+			continue
+		}
+		if err == nil {
+			c.served(ctx, i, llm)
+			return nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", llm.Name(), err))
+		// A cancelled call fails with every provider
+		if ctx.Err() != nil {
+			break
+		}
+		if canFallBack != nil && !canFallBack() {
+			return fmt.Errorf("%s failed after executing tools, not trying the fallbacks: %w", llm.Name(), err)
+		}
+		if i < len(c.llms)-1 {
+			c.logger.Warn(ctx, "LLM failed, trying the next fallback", map[string]interface{}{
+				"provider": llm.Name(),
+				"model":    modelOf(llm),
+				"error":    err.Error(),
+			})
+		}
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no LLM of the fallback chain supports the call")
+	}
+	return fmt.Errorf("all LLMs of the fallback chain failed: %w", errors.Join(errs...))
+}
+
+// served records the LLM that served a call
+func (c *FallbackChain) served(ctx context.Context, index int, llm interfaces.LLM) {
+	if servedBy, ok := ctx.Value(servedByKey{}).(*ServedBy); ok {
+		servedBy.set(llm.Name(), modelOf(llm))
+	}
+
+	fields := map[string]interface{}{
+		"provider": llm.Name(),
+		"model":    modelOf(llm),
+	}
+	if index == 0 {
+		c.logger.Debug(ctx, "LLM response served by the primary provider", fields)
+		return
+	}
+	fields["fallback"] = index
+	c.logger.Info(ctx, "LLM response served by a fallback provider", fields)
+}
+
+// Generate generates text with the first LLM of the chain that succeeds
+func (c *FallbackChain) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	var response string
+	err := c.try(ctx, func(llm interfaces.LLM) (bool, error) {
+		var err error
+		response, err = llm.Generate(ctx, prompt, options...)
+		return true, err
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
+// GenerateWithTools generates text with tools with the first LLM of the chain that succeeds.
+// An LLM failing after executing a tool fails the call without trying the next LLM.
+func (c *FallbackChain) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	var executed atomic.Bool
+	tracked := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		tracked[i] = &trackedTool{ToolWrapper: interfaces.ToolWrapper{Tool: tool}, executed: &executed}
+	}
+
+	var response string
+	err := c.try(ctx, func(llm interfaces.LLM) (bool, error) {
+		var err error
+		response, err = llm.GenerateWithTools(ctx, prompt, tracked, options...)
+		return true, err
+	}, func() bool {
+		return !executed.Load()
+	})
+	if err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
+// GenerateStream streams text from the first streaming LLM of the chain that starts a stream.
+// Once started, a stream is not switched to another LLM if it fails.
+func (c *FallbackChain) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	var events <-chan interfaces.StreamEvent
+	err := c.try(ctx, func(llm interfaces.LLM) (bool, error) {
+		streamingLLM, ok := llm.(interfaces.StreamingLLM)
+		if !ok || !llm.SupportsStreaming() {
+			return false, nil
+		}
+		var err error
+		events, err = streamingLLM.GenerateStream(ctx, prompt, options...)
+		return true, err
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GenerateWithToolsStream streams text with tools from the first streaming LLM of the chain
+// that starts a stream
+func (c *FallbackChain) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	var events <-chan interfaces.StreamEvent
+	err := c.try(ctx, func(llm interfaces.LLM) (bool, error) {
+		streamingLLM, ok := llm.(interfaces.StreamingLLM)
+		if !ok || !llm.SupportsStreaming() {
+			return false, nil
+		}
+		var err error
+		events, err = streamingLLM.GenerateWithToolsStream(ctx, prompt, tools, options...)
+		return true, err
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// trackedTool records that a tool ran during a call of the chain
+type trackedTool struct {
+	interfaces.ToolWrapper
+	executed *atomic.Bool
+}
+
+// Run executes the tool with the given input
+func (t *trackedTool) Run(ctx context.Context, input string) (string, error) {
+	t.executed.Store(true)
+	return t.Tool.Run(ctx, input)
+}
+
+// Execute executes the tool with the given arguments
+func (t *trackedTool) Execute(ctx context.Context, args string) (string, error) {
+	t.executed.Store(true)
+	return interfaces.ExecuteTool(ctx, t.Tool, args)
+}
+
+// Name returns the name of the primary LLM
+func (c *FallbackChain) Name() string {
+	return c.llms[0].Name()
+}
+
+// SupportsStreaming returns whether any LLM of the chain supports streaming
+func (c *FallbackChain) SupportsStreaming() bool {
+	for _, llm := range c.llms {
+		if _, ok := llm.(interfaces.StreamingLLM); ok && llm.SupportsStreaming() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetModel returns the model of the primary LLM
+func (c *FallbackChain) GetModel() string {
+	return modelOf(c.llms[0])
+}
+
+// modelOf returns the model of an LLM, falling back to its name
+func modelOf(llm interfaces.LLM) string {
+	if modelLLM, ok := llm.(interface{ GetModel() string }); ok {
+		if model := modelLLM.GetModel(); model != "" {
+			return model
+		}
+	}
+	return llm.Name()
+}
@@ -0,0 +1,178 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// fallbackTestLLM fails every call with err if set, recording the prompts and options it gets
+type fallbackTestLLM struct {
+	name    string
+	err     error
+	prompts []string
+	options []*interfaces.GenerateOptions
+	tools   [][]interfaces.Tool
+	// runTools executes each tool before returning
+	runTools bool
+}
+
+func (m *fallbackTestLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	m.prompts = append(m.prompts, prompt)
+	params := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(params)
+	}
+	m.options = append(m.options, params)
+	if m.err != nil {
+		return "", m.err
+	}
+	return "answer from " + m.name, nil
+}
+
+func (m *fallbackTestLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	m.tools = append(m.tools, tools)
+	if m.runTools {
+		for _, tool := range tools {
+			if _, err := tool.Execute(ctx, "{}"); err != nil {
+				return "", err
+			}
+		}
+	}
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *fallbackTestLLM) Name() string            { return m.name }
+func (m *fallbackTestLLM) SupportsStreaming() bool { return false }
+func (m *fallbackTestLLM) GetModel() string        { return m.name + "-model" }
+
+func TestFallbackChain(t *testing.T) {
+	primary := &fallbackTestLLM{name: "openai", err: errors.New("503 service unavailable")}
+	second := &fallbackTestLLM{name: "anthropic", err: errors.New("529 overloaded")}
+	third := &fallbackTestLLM{name: "gemini"}
+	chain := NewFallbackChain(primary, second, third)
+
+	ctx, servedBy := WithServedBy(context.Background())
+	tools := []interfaces.Tool{nil}
+	response, err := chain.GenerateWithTools(ctx, "What is the weather?", tools, interfaces.WithSystemMessage("Be brief"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response != "answer from gemini" {
+		t.Errorf("Expected the response of the last fallback, got %q", response)
+	}
+	if servedBy.Provider() != "gemini" || servedBy.Model() != "gemini-model" {
+		t.Errorf("Expected the context to report gemini, got %q (%q)", servedBy.Provider(), servedBy.Model())
+	}
+
+	// Every LLM gets the same prompt, options and tools
+	for _, llm := range []*fallbackTestLLM{primary, second, third} {
+		if len(llm.prompts) != 1 || llm.prompts[0] != "What is the weather?" {
+			t.Errorf("Expected %s to get the prompt once, got %v", llm.name, llm.prompts)
+		}
+		if llm.options[0].SystemMessage != "Be brief" {
+			t.Errorf("Expected %s to get the options, got %+v", llm.name, llm.options[0])
+		}
+		if len(llm.tools) != 1 || len(llm.tools[0]) != 1 {
+			t.Errorf("Expected %s to get the tools, got %v", llm.name, llm.tools)
+		}
+	}
+
+	// The primary serves the call when it succeeds
+	primary.err = nil
+	ctx, servedBy = WithServedBy(context.Background())
+	if response, _ := chain.Generate(ctx, "What is the weather?"); response != "answer from openai" || servedBy.Provider() != "openai" {
+		t.Errorf("Expected the primary to serve the call, got %q from %q", response, servedBy.Provider())
+	}
+	if len(second.prompts) != 1 {
+		t.Errorf("Expected the fallbacks not to be called, got %d calls", len(second.prompts))
+	}
+}
+
+func TestFallbackChainAllFail(t *testing.T) {
+	outage := errors.New("503 service unavailable")
+	chain := NewFallbackChain(
+		&fallbackTestLLM{name: "openai", err: outage},
+		&fallbackTestLLM{name: "anthropic", err: errors.New("529 overloaded")},
+	)
+
+	_, err := chain.Generate(context.Background(), "What is the weather?")
+	if !errors.Is(err, outage) {
+		t.Fatalf("Expected the errors of the LLMs, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "openai: 503 service unavailable") || !strings.Contains(err.Error(), "anthropic: 529 overloaded") {
+		t.Errorf("Expected the error of each provider, got %v", err)
+	}
+}
+
+// countingTool counts its executions
+type countingTool struct {
+	calls int
+}
+
+func (t *countingTool) Name() string        { return "send_email" }
+func (t *countingTool) Description() string { return "Sends an email" }
+func (t *countingTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{}
+}
+func (t *countingTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *countingTool) Execute(ctx context.Context, args string) (string, error) {
+	t.calls++
+	return "sent", nil
+}
+
+func TestFallbackChainNoFallbackAfterToolCalls(t *testing.T) {
+	tool := &countingTool{}
+	fallback := &fallbackTestLLM{name: "anthropic", runTools: true}
+	chain := NewFallbackChain(&fallbackTestLLM{name: "openai", err: errors.New("503 service unavailable"), runTools: true}, fallback)
+
+	_, err := chain.GenerateWithTools(context.Background(), "Email the report", []interfaces.Tool{tool})
+	if err == nil || !strings.Contains(err.Error(), "503 service unavailable") {
+		t.Fatalf("Expected the error of the primary, got %v", err)
+	}
+	if tool.calls != 1 {
+		t.Errorf("Expected the tool to run once, got %d runs", tool.calls)
+	}
+	if len(fallback.prompts) != 0 {
+		t.Errorf("Expected the fallback not to be tried after a tool ran, got %v", fallback.prompts)
+	}
+
+	// Failures before any tool runs still fall back
+	chain = NewFallbackChain(&fallbackTestLLM{name: "openai", err: errors.New("503 service unavailable")}, fallback)
+	if response, err := chain.GenerateWithTools(context.Background(), "Email the report", []interfaces.Tool{tool}); err != nil || response != "answer from anthropic" {
+		t.Errorf("Expected the fallback to serve the call, got %q (%v)", response, err)
+	}
+	if tool.calls != 2 {
+		t.Errorf("Expected the fallback to run the tool, got %d runs", tool.calls)
+	}
+}
+
+func TestFallbackChainCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fallback := &fallbackTestLLM{name: "anthropic"}
+	chain := NewFallbackChain(&fallbackTestLLM{name: "openai", err: context.Canceled}, fallback)
+
+	if _, err := chain.Generate(ctx, "What is the weather?"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation error, got %v", err)
+	}
+	if len(fallback.prompts) != 0 {
+		t.Errorf("Expected a cancelled call not to be retried with the fallback, got %v", fallback.prompts)
+	}
+}
+
+func TestFallbackChainStreamSkipsNonStreamingLLMs(t *testing.T) {
+	chain := NewFallbackChain(&fallbackTestLLM{name: "openai"}, &countingLLM{})
+	if chain.SupportsStreaming() {
+		t.Error("Expected a chain without streaming LLMs not to support streaming")
+	}
+	if _, err := chain.GenerateStream(context.Background(), "What is the weather?"); err == nil {
+		t.Error("Expected an error without streaming LLMs")
+	}
+}
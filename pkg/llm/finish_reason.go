@@ -0,0 +1,19 @@
+package llm
+
+import "github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+
+// ChatFinishReason maps a finish reason of the OpenAI Chat Completions API, also served by Azure
+// OpenAI and compatible providers, to its normalized form
+func ChatFinishReason(reason string) interfaces.FinishReason {
+	switch reason {
+	case "stop":
+		return interfaces.FinishReasonStop
+	case "length":
+		return interfaces.FinishReasonLength
+	case "tool_calls", "function_call":
+		return interfaces.FinishReasonToolUse
+	case "content_filter":
+		return interfaces.FinishReasonContentFilter
+	}
+	return interfaces.FinishReasonUnknown
+}
@@ -0,0 +1,28 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestChatFinishReason(t *testing.T) {
+	tests := []struct {
+		reason   string
+		expected interfaces.FinishReason
+	}{
+		{"stop", interfaces.FinishReasonStop},
+		{"length", interfaces.FinishReasonLength},
+		{"tool_calls", interfaces.FinishReasonToolUse},
+		{"function_call", interfaces.FinishReasonToolUse},
+		{"content_filter", interfaces.FinishReasonContentFilter},
+		{"", interfaces.FinishReasonUnknown},
+	}
+
+	for _, test := range tests {
+		result := ChatFinishReason(test.reason)
+		if result != test.expected {
+			t.Errorf("For finish reason %q, expected %q, got %q", test.reason, test.expected, result)
+		}
+	}
+}
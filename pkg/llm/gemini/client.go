@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"google.golang.org/genai"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
@@ -56,6 +58,14 @@ type GeminiClient struct {
 	logger          logging.Logger
 	retryExecutor   *retry.Executor
 	thinkingConfig  *ThinkingConfig
+
+	// Vertex AI regions rotated on transient errors, with their genai clients in the same order
+	regions       []string
+	regionClients []*genai.Client
+	regionIndex   int
+	regionMu      sync.Mutex
+
+	completionLogLevel string
 }
 
 // Option represents an option for configuring the Gemini client
@@ -75,13 +85,23 @@ func WithLogger(logger logging.Logger) Option {
 	}
 }
 
-// WithRetry configures retry policy for the client
+// WithRetry configures retry policy for the client.
+// Transient errors (429 and 5xx) are retried with exponential backoff, waiting at least as long
+// as requested by the Retry-After header or the RetryInfo error detail; other errors fail immediately.
 func WithRetry(opts ...retry.Option) Option {
 	return func(c *GeminiClient) {
 		c.retryExecutor = retry.NewExecutor(retry.NewPolicy(opts...))
 	}
 }
 
+// WithCompletionLogLevel sets the level of the summary logged after every API call
+// ("debug", "info", "warn", "error" or "none" to disable it; default: "info")
+func WithCompletionLogLevel(level string) Option {
+	return func(c *GeminiClient) {
+		c.completionLogLevel = level
+	}
+}
+
 // WithAPIKey sets the API key for Gemini API backend
 func WithAPIKey(apiKey string) Option {
 	return func(c *GeminiClient) {
@@ -118,7 +138,9 @@ func WithProjectID(projectID string) Option {
 	}
 }
 
-// WithLocation sets the GCP location for Vertex AI backend
+// WithLocation sets the GCP location for Vertex AI backend. Several comma-separated locations,
+// e.g. "us-central1,europe-west4", are rotated when a request fails with a transient error,
+// see WithVertexAI.
 func WithLocation(location string) Option {
 	return func(c *GeminiClient) {
 		c.location = location
@@ -143,6 +165,28 @@ func WithCredentialsJSON(credentialsJSON []byte) Option {
 	}
 }
 
+// WithVertexAI configures the client for Vertex AI in the given region and project, using the
+// application default credentials. The region can be a comma-separated list of regions, e.g.
+// "us-central1,europe-west4,asia-northeast1": when a request fails with a transient error such as
+// a 429 or a 503, the retry is sent to the next region in round-robin order, so that regional
+// quota exhaustion or outages fail over to another region. Region failover requires WithRetry.
+func WithVertexAI(region, projectID string) Option {
+	return func(c *GeminiClient) {
+		c.backend = genai.BackendVertexAI
+		c.location = region
+		c.projectID = projectID
+	}
+}
+
+// WithVertexAICredentials configures the client for Vertex AI like WithVertexAI, authenticating
+// with the service account key file at credentialsPath
+func WithVertexAICredentials(region, projectID, credentialsPath string) Option {
+	return func(c *GeminiClient) {
+		WithVertexAI(region, projectID)(c)
+		c.credentialsFile = credentialsPath
+	}
+}
+
 // NewClient creates a new Gemini client
 func NewClient(ctx context.Context, options ...Option) (*GeminiClient, error) {
 	// Create client with default options
@@ -234,19 +278,96 @@ func NewClient(ctx context.Context, options ...Option) (*GeminiClient, error) {
 			if client.apiKey != "" {
 				config.APIKey = client.apiKey
 			}
+
+			// Create a client per region to fail over between regions
+			if regions := parseRegions(client.location); config.Project != "" && len(regions) > 0 {
+				for _, region := range regions {
+					regionConfig := *config
+					regionConfig.Location = region
+					genaiClient, err := newGenaiClient(ctx, &regionConfig)
+					if err != nil {
+						return nil, fmt.Errorf("failed to create Gemini client for region %s: %w", region, err)
+					}
+					client.regionClients = append(client.regionClients, genaiClient)
+				}
+				client.regions = regions
+				client.genaiClient = client.regionClients[0]
+				return client, nil
+			}
 		}
 
-		genaiClient, err := genai.NewClient(ctx, config)
+		genaiClient, err := newGenaiClient(ctx, config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Gemini client: %w", err)
 		}
-
 		client.genaiClient = genaiClient
 	}
 
 	return client, nil
 }
 
+// newGenaiClient creates a genai client capturing the Retry-After headers of throttled
+// responses to tune the retry backoff
+func newGenaiClient(ctx context.Context, config *genai.ClientConfig) (*genai.Client, error) {
+	genaiClient, err := genai.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if httpClient := genaiClient.ClientConfig().HTTPClient; httpClient != nil {
+		httpClient.Transport = &retryAfterTransport{base: httpClient.Transport}
+	}
+	return genaiClient, nil
+}
+
+// parseRegions splits a comma-separated list of regions
+func parseRegions(location string) []string {
+	var regions []string
+	for _, region := range strings.Split(location, ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// client returns the genai client of the current region
+func (c *GeminiClient) client() *genai.Client {
+	c.regionMu.Lock()
+	defer c.regionMu.Unlock()
+	if len(c.regionClients) == 0 {
+		return c.genaiClient
+	}
+	return c.regionClients[c.regionIndex]
+}
+
+// currentRegion returns the Vertex AI region requests are sent to, empty without regions
+func (c *GeminiClient) currentRegion() string {
+	c.regionMu.Lock()
+	defer c.regionMu.Unlock()
+	if len(c.regions) == 0 {
+		return ""
+	}
+	return c.regions[c.regionIndex]
+}
+
+// rotateRegion moves to the next Vertex AI region in round-robin order
+func (c *GeminiClient) rotateRegion(ctx context.Context) {
+	c.regionMu.Lock()
+	if len(c.regionClients) <= 1 {
+		c.regionMu.Unlock()
+		return
+	}
+	from := c.regions[c.regionIndex]
+	c.regionIndex = (c.regionIndex + 1) % len(c.regionClients)
+	to := c.regions[c.regionIndex]
+	c.regionMu.Unlock()
+
+	c.logger.Debug(ctx, "Rotating Vertex AI region after transient error", map[string]interface{}{
+		"current_region": from,
+		"next_region":    to,
+	})
+}
+
 // Generate generates text from a prompt
 func (c *GeminiClient) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
 	// Apply options
@@ -260,13 +381,22 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string, options ...i
 		option(params)
 	}
 
+	if err := c.validateInputs(params); err != nil {
+		return "", err
+	}
+
+	if err := c.validateThinkingBudget(params.LLMConfig); err != nil {
+		return "", err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return "", err
+	}
+
 	// Get organization ID from context if available
 	orgID, _ := multitenancy.GetOrgID(ctx)
 
 	// Build the request content
-	parts := []*genai.Part{
-		{Text: prompt},
-	}
+	parts := promptParts(c.structuredOutputPrompt(prompt, params.ResponseFormat), params.Images, params.Files)
 
 	contents := []*genai.Content{
 		{
@@ -320,6 +450,9 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string, options ...i
 		if len(params.LLMConfig.StopSequences) > 0 {
 			genConfig.StopSequences = params.LLMConfig.StopSequences
 		}
+		if params.LLMConfig.MaxTokens > 0 {
+			genConfig.MaxOutputTokens = int32(params.LLMConfig.MaxTokens)
+		}
 	}
 
 	// Set response format if provided
@@ -327,25 +460,14 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string, options ...i
 		if genConfig == nil {
 			genConfig = &genai.GenerationConfig{}
 		}
-
-		genConfig.ResponseMIMEType = "application/json"
-
-		// Convert schema for genai
-		if schemaBytes, err := json.Marshal(params.ResponseFormat.Schema); err == nil {
-			var schema *genai.Schema
-			if err := json.Unmarshal(schemaBytes, &schema); err != nil {
-				c.logger.Warn(ctx, "Failed to convert response schema", map[string]interface{}{"error": err.Error()})
-			} else {
-				genConfig.ResponseSchema = schema
-			}
-		}
-		c.logger.Debug(ctx, "Using response format", map[string]interface{}{"format": *params.ResponseFormat})
+		c.applyResponseFormat(ctx, genConfig, params.ResponseFormat)
 	}
 
 	var result *genai.GenerateContentResponse
 	var err error
 
-	operation := func() error {
+	start := time.Now()
+	operation := func(ctx context.Context) error {
 		c.logger.Debug(ctx, "Executing Gemini API request", map[string]interface{}{
 			"model":           c.model,
 			"temperature":     genConfig.Temperature,
@@ -370,6 +492,9 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string, options ...i
 			if len(genConfig.StopSequences) > 0 {
 				config.StopSequences = genConfig.StopSequences
 			}
+			if genConfig.MaxOutputTokens > 0 {
+				config.MaxOutputTokens = genConfig.MaxOutputTokens
+			}
 			if genConfig.ResponseMIMEType != "" {
 				config.ResponseMIMEType = genConfig.ResponseMIMEType
 			}
@@ -393,25 +518,27 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string, options ...i
 			}
 		}
 
-		result, err = c.genaiClient.Models.GenerateContent(ctx, c.model, contents, config)
+		c.applyThinkingBudget(config, params.LLMConfig)
+		c.applyThinkingPolicy(config, params.ThinkingPolicy)
+		result, err = c.client().Models.GenerateContent(ctx, c.model, contents, config)
 		if err != nil {
 			c.logger.Error(ctx, "Error from Gemini API", map[string]interface{}{
-				"error": err.Error(),
-				"model": c.model,
+				"error":  err.Error(),
+				"model":  c.model,
+				"region": c.currentRegion(),
 			})
 			return fmt.Errorf("failed to generate text: %w", err)
 		}
 		return nil
 	}
 
-	if c.retryExecutor != nil {
+	if c.retryExecutor != nil && !params.NoRetry {
 		c.logger.Debug(ctx, "Using retry mechanism for Gemini request", map[string]interface{}{
 			"model": c.model,
 		})
-		err = c.retryExecutor.Execute(ctx, operation)
-	} else {
-		err = operation()
 	}
+	attempts, err := c.withRetry(ctx, params, operation)
+	c.logCompletion(ctx, start, attempts, result, err)
 
 	if err != nil {
 		return "", err
@@ -423,35 +550,7 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string, options ...i
 			"model": c.model,
 		})
 
-		var textParts []string
-		var thinkingParts []string
-
-		for _, part := range result.Candidates[0].Content.Parts {
-			if part.Text != "" {
-				if part.Thought {
-					// This is thinking content
-					thinkingParts = append(thinkingParts, part.Text)
-					c.logger.Debug(ctx, "Received thinking content", map[string]interface{}{
-						"length": len(part.Text),
-					})
-				} else {
-					// This is final response content
-					textParts = append(textParts, part.Text)
-				}
-			}
-		}
-
-		// For non-streaming Generate, we return only the final response content
-		// The thinking content is available but not returned in this interface
-		// (it would be available in streaming through StreamEventThinking)
-		if len(thinkingParts) > 0 {
-			c.logger.Info(ctx, "Thinking content received but not included in response", map[string]interface{}{
-				"thinkingParts": len(thinkingParts),
-				"finalParts":    len(textParts),
-			})
-		}
-
-		return strings.Join(textParts, ""), nil
+		return c.responseText(ctx, result.Candidates[0].Content.Parts, "", params), nil
 	}
 
 	return "", fmt.Errorf("no response from Gemini API")
@@ -467,6 +566,31 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		}
 	}
 
+	if err := c.validateInputs(params); err != nil {
+		return "", err
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
+	if err := c.validateThinkingBudget(params.LLMConfig); err != nil {
+		return "", err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return "", err
+	}
+
 	// Set default values only if they're not provided
 	if params.LLMConfig == nil {
 		params.LLMConfig = &interfaces.LLMConfig{
@@ -496,75 +620,7 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		functionDeclaration := &genai.FunctionDeclaration{
 			Name:        tool.Name(),
 			Description: tool.Description(),
-			Parameters: &genai.Schema{
-				Type:       genai.TypeObject,
-				Properties: make(map[string]*genai.Schema),
-				Required:   make([]string, 0),
-			},
-		}
-
-		// Convert parameters
-		for name, param := range tool.Parameters() {
-			paramSchema := &genai.Schema{
-				Description: param.Description,
-			}
-
-			// Set type
-			switch param.Type {
-			case "string":
-				paramSchema.Type = genai.TypeString
-			case "number", "integer":
-				paramSchema.Type = genai.TypeNumber
-			case "boolean":
-				paramSchema.Type = genai.TypeBoolean
-			case "array":
-				paramSchema.Type = genai.TypeArray
-			case "object":
-				paramSchema.Type = genai.TypeObject
-			}
-
-			// Handle array items
-			if param.Items != nil {
-				itemSchema := &genai.Schema{}
-
-				// Set items type
-				switch param.Items.Type {
-				case "string":
-					itemSchema.Type = genai.TypeString
-				case "number", "integer":
-					itemSchema.Type = genai.TypeNumber
-				case "boolean":
-					itemSchema.Type = genai.TypeBoolean
-				case "array":
-					itemSchema.Type = genai.TypeArray
-				case "object":
-					itemSchema.Type = genai.TypeObject
-				}
-
-				// Handle items enum if present
-				if param.Items.Enum != nil {
-					enumStrings := make([]string, len(param.Items.Enum))
-					for i, e := range param.Items.Enum {
-						enumStrings[i] = fmt.Sprintf("%v", e)
-					}
-					itemSchema.Enum = enumStrings
-				}
-
-				paramSchema.Items = itemSchema
-			}
-
-			if param.Enum != nil {
-				enumStrings := make([]string, len(param.Enum))
-				for i, e := range param.Enum {
-					enumStrings[i] = fmt.Sprintf("%v", e)
-				}
-				paramSchema.Enum = enumStrings
-			}
-
-			functionDeclaration.Parameters.Properties[name] = paramSchema
-			if param.Required {
-				functionDeclaration.Parameters.Required = append(functionDeclaration.Parameters.Required, name)
-			}
+			Parameters:  convertToGeminiSchema(tool.Parameters()),
 		}
 
 		geminiTools = append(geminiTools, functionDeclaration)
@@ -608,10 +664,8 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 
 	// Add user message
 	contents = append(contents, &genai.Content{
-		Role: "user",
-		Parts: []*genai.Part{
-			{Text: prompt},
-		},
+		Role:  "user",
+		Parts: promptParts(c.structuredOutputPrompt(prompt, params.ResponseFormat), params.Images, params.Files),
 	})
 
 	// Iterative tool calling loop
@@ -632,6 +686,9 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 			if len(params.LLMConfig.StopSequences) > 0 {
 				genConfig.StopSequences = params.LLMConfig.StopSequences
 			}
+			if params.LLMConfig.MaxTokens > 0 {
+				genConfig.MaxOutputTokens = int32(params.LLMConfig.MaxTokens)
+			}
 		}
 
 		// Set response format if provided
@@ -639,18 +696,7 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 			if genConfig == nil {
 				genConfig = &genai.GenerationConfig{}
 			}
-			genConfig.ResponseMIMEType = "application/json"
-
-			// Convert schema for genai
-			if schemaBytes, err := json.Marshal(params.ResponseFormat.Schema); err == nil {
-				var schema *genai.Schema
-				if err := json.Unmarshal(schemaBytes, &schema); err != nil {
-					c.logger.Warn(ctx, "Failed to convert response schema", map[string]interface{}{"error": err.Error()})
-				} else {
-					genConfig.ResponseSchema = schema
-				}
-			}
-			c.logger.Debug(ctx, "Using response format", map[string]interface{}{"format": *params.ResponseFormat})
+			c.applyResponseFormat(ctx, genConfig, params.ResponseFormat)
 		}
 
 		logData := map[string]interface{}{
@@ -682,6 +728,7 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 					FunctionDeclarations: geminiTools,
 				},
 			},
+			ToolConfig:        toolConfig(params.ToolChoiceForIteration(iteration)),
 			SystemInstruction: systemInstruction,
 		}
 
@@ -696,6 +743,9 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 			if len(genConfig.StopSequences) > 0 {
 				config.StopSequences = genConfig.StopSequences
 			}
+			if genConfig.MaxOutputTokens > 0 {
+				config.MaxOutputTokens = genConfig.MaxOutputTokens
+			}
 			if genConfig.ResponseMIMEType != "" {
 				config.ResponseMIMEType = genConfig.ResponseMIMEType
 			}
@@ -704,7 +754,11 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 			}
 		}
 
-		result, err := c.genaiClient.Models.GenerateContent(ctx, c.model, contents, config)
+		c.applyThinkingBudget(config, params.LLMConfig)
+		c.applyThinkingPolicy(config, params.ThinkingPolicy)
+		start := time.Now()
+		result, attempts, err := c.generateContent(ctx, params, contents, config)
+		c.logCompletion(ctx, start, attempts, result, err)
 		if err != nil {
 			c.logger.Error(ctx, "Error from Gemini API", map[string]interface{}{"error": err.Error()})
 			return "", fmt.Errorf("failed to create content: %w", err)
@@ -730,13 +784,7 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 
 		// If no function calls, return the text response
 		if !hasFunctionCalls {
-			var textParts []string
-			for _, part := range candidate.Content.Parts {
-				if part.Text != "" {
-					textParts = append(textParts, part.Text)
-				}
-			}
-			return strings.Join(textParts, " "), nil
+			return c.responseText(ctx, candidate.Content.Parts, " ", params), nil
 		}
 
 		// Process function calls
@@ -778,14 +826,8 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 				})
 
 				// Add tool not found error as function response
-				functionResponses = append(functionResponses, &genai.Part{
-					FunctionResponse: &genai.FunctionResponse{
-						Name: functionCall.Name,
-						Response: map[string]any{
-							"error": fmt.Sprintf("tool not found: %s", functionCall.Name),
-						},
-					},
-				})
+				functionResponses = append(functionResponses, functionResponsePart(
+					functionCall.ID, functionCall.Name, "", fmt.Errorf("tool not found: %s", functionCall.Name)))
 
 				// Store failed tool call in memory if provided
 				if params.Memory != nil {
@@ -835,7 +877,7 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 			// Execute the tool
 			c.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": selectedTool.Name()})
 			toolStartTime := time.Now()
-			toolResult, err := selectedTool.Execute(ctx, string(argsBytes))
+			toolResult, err := interfaces.ExecuteTool(ctx, selectedTool, string(argsBytes))
 			toolEndTime := time.Now()
 
 			// Check for repetitive calls and add warning if needed
@@ -920,26 +962,12 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 				toolCallTrace.Result = fmt.Sprintf("Error: %v", err)
 
 				// Add error message as function response
-				functionResponses = append(functionResponses, &genai.Part{
-					FunctionResponse: &genai.FunctionResponse{
-						Name: functionCall.Name,
-						Response: map[string]any{
-							"error": err.Error(),
-						},
-					},
-				})
+				functionResponses = append(functionResponses, functionResponsePart(functionCall.ID, functionCall.Name, "", err))
 			} else {
 				toolCallTrace.Result = toolResult
 
-				// Add tool result as function response
-				functionResponses = append(functionResponses, &genai.Part{
-					FunctionResponse: &genai.FunctionResponse{
-						Name: functionCall.Name,
-						Response: map[string]any{
-							"result": toolResult,
-						},
-					},
-				})
+				// Add tool result as a structured function response
+				functionResponses = append(functionResponses, functionResponsePart(functionCall.ID, functionCall.Name, toolResult, nil))
 			}
 
 			// Add the tool call to the tracing context
@@ -982,6 +1010,9 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		if len(params.LLMConfig.StopSequences) > 0 {
 			genConfig.StopSequences = params.LLMConfig.StopSequences
 		}
+		if params.LLMConfig.MaxTokens > 0 {
+			genConfig.MaxOutputTokens = int32(params.LLMConfig.MaxTokens)
+		}
 	}
 
 	// Set response format if provided
@@ -989,17 +1020,7 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		if genConfig == nil {
 			genConfig = &genai.GenerationConfig{}
 		}
-		genConfig.ResponseMIMEType = "application/json"
-
-		// Convert schema for genai
-		if schemaBytes, err := json.Marshal(params.ResponseFormat.Schema); err == nil {
-			var schema *genai.Schema
-			if err := json.Unmarshal(schemaBytes, &schema); err != nil {
-				c.logger.Warn(ctx, "Failed to convert response schema", map[string]interface{}{"error": err.Error()})
-			} else {
-				genConfig.ResponseSchema = schema
-			}
-		}
+		c.applyResponseFormat(ctx, genConfig, params.ResponseFormat)
 	}
 
 	// Add a conclusion instruction to the contents
@@ -1029,6 +1050,9 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		if len(genConfig.StopSequences) > 0 {
 			config.StopSequences = genConfig.StopSequences
 		}
+		if genConfig.MaxOutputTokens > 0 {
+			config.MaxOutputTokens = genConfig.MaxOutputTokens
+		}
 		if genConfig.ResponseMIMEType != "" {
 			config.ResponseMIMEType = genConfig.ResponseMIMEType
 		}
@@ -1037,7 +1061,11 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		}
 	}
 
-	finalResult, err := c.genaiClient.Models.GenerateContent(ctx, c.model, contents, config)
+	c.applyThinkingBudget(config, params.LLMConfig)
+	c.applyThinkingPolicy(config, params.ThinkingPolicy)
+	start := time.Now()
+	finalResult, attempts, err := c.generateContent(ctx, params, contents, config)
+	c.logCompletion(ctx, start, attempts, finalResult, err)
 	if err != nil {
 		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
 		return "", fmt.Errorf("failed to create final content: %w", err)
@@ -1052,17 +1080,49 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		return "", fmt.Errorf("no content in final response")
 	}
 
-	// Extract text from all parts
-	var textParts []string
-	for _, part := range candidate.Content.Parts {
-		if part.Text != "" {
-			textParts = append(textParts, part.Text)
+	content := strings.TrimSpace(c.responseText(ctx, candidate.Content.Parts, " ", params))
+	c.logger.Info(ctx, "Successfully received final response without tools", nil)
+	return content, nil
+}
+
+// logCompletion logs the completion summary of a GenerateContent request
+func (c *GeminiClient) logCompletion(ctx context.Context, start time.Time, attempts int, result *genai.GenerateContentResponse, err error) {
+	summary := llm.CompletionSummary{
+		Provider: c.Name(),
+		Model:    c.model,
+		Latency:  time.Since(start),
+		Err:      err,
+	}
+	if attempts > 1 {
+		summary.Retries = attempts - 1
+	}
+	if result != nil && result.UsageMetadata != nil {
+		summary.PromptTokens = int64(result.UsageMetadata.PromptTokenCount)
+		summary.ResponseTokens = int64(result.UsageMetadata.CandidatesTokenCount + result.UsageMetadata.ThoughtsTokenCount)
+	}
+	if result != nil && len(result.Candidates) > 0 {
+		summary.FinishReason = normalizeFinishReason(result.Candidates[0].FinishReason)
+		// Gemini reports STOP for responses calling functions
+		if len(result.FunctionCalls()) > 0 {
+			summary.FinishReason = interfaces.FinishReasonToolUse
 		}
 	}
+	llm.LogCompletion(ctx, c.logger, c.completionLogLevel, summary)
+}
 
-	content := strings.TrimSpace(strings.Join(textParts, " "))
-	c.logger.Info(ctx, "Successfully received final response without tools", nil)
-	return content, nil
+// normalizeFinishReason maps a Gemini finish reason to the provider-independent one
+func normalizeFinishReason(reason genai.FinishReason) interfaces.FinishReason {
+	switch reason {
+	case genai.FinishReasonStop:
+		return interfaces.FinishReasonStop
+	case genai.FinishReasonMaxTokens:
+		return interfaces.FinishReasonLength
+	case genai.FinishReasonSafety, genai.FinishReasonRecitation, genai.FinishReasonBlocklist,
+		genai.FinishReasonProhibitedContent, genai.FinishReasonSPII, genai.FinishReasonImageSafety:
+		return interfaces.FinishReasonContentFilter
+	default:
+		return interfaces.FinishReasonUnknown
+	}
 }
 
 // Name implements interfaces.LLM.Name
@@ -1079,3 +1139,164 @@ func (c *GeminiClient) SupportsStreaming() bool {
 func (c *GeminiClient) GetModel() string {
 	return c.model
 }
+
+// assistantParts converts an assistant message to Gemini parts, representing
+// tool calls as function calls so that tool-only turns are kept in the history
+func assistantParts(msg interfaces.Message) []*genai.Part {
+	var parts []*genai.Part
+	if msg.Content != "" {
+		parts = append(parts, &genai.Part{Text: msg.Content})
+	}
+	for _, toolCall := range msg.ToolCalls {
+		args := map[string]any{}
+		if toolCall.Arguments != "" {
+			if err := json.Unmarshal([]byte(toolCall.Arguments), &args); err != nil {
+				args = map[string]any{"input": toolCall.Arguments}
+			}
+		}
+		parts = append(parts, &genai.Part{
+			FunctionCall: &genai.FunctionCall{
+				ID:   toolCall.ID,
+				Name: toolCall.Name,
+				Args: args,
+			},
+		})
+	}
+	return parts
+}
+
+// functionResponsePart builds a structured functionResponse part for a tool result.
+// JSON object results are passed through as the response payload, other results are
+// wrapped under "result", and failures are reported under "error".
+func functionResponsePart(id, name, result string, err error) *genai.Part {
+	var response map[string]any
+	if err != nil {
+		response = map[string]any{"error": err.Error()}
+	} else {
+		response = structuredToolResult(result)
+	}
+
+	return &genai.Part{
+		FunctionResponse: &genai.FunctionResponse{
+			ID:       id,
+			Name:     name,
+			Response: response,
+		},
+	}
+}
+
+// structuredToolResult converts a tool result into a functionResponse payload
+func structuredToolResult(result string) map[string]any {
+	trimmed := strings.TrimSpace(result)
+
+	var object map[string]any
+	if err := json.Unmarshal([]byte(trimmed), &object); err == nil && object != nil {
+		return object
+	}
+
+	var value any
+	if strings.HasPrefix(trimmed, "[") && json.Unmarshal([]byte(trimmed), &value) == nil {
+		return map[string]any{"result": value}
+	}
+
+	return map[string]any{"result": result}
+}
+
+// convertToGeminiSchema converts tool parameters to a Gemini object schema
+func convertToGeminiSchema(params map[string]interfaces.ParameterSpec) *genai.Schema {
+	schema := &genai.Schema{
+		Type:       genai.TypeObject,
+		Properties: make(map[string]*genai.Schema),
+		Required:   make([]string, 0),
+	}
+
+	for name, param := range params {
+		schema.Properties[name] = convertParameterToGeminiSchema(param)
+		if param.Required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+// convertParameterToGeminiSchema converts a single parameter to a Gemini schema, including nested array items and object properties
+func convertParameterToGeminiSchema(param interfaces.ParameterSpec) *genai.Schema {
+	schema := &genai.Schema{
+		Description: param.Description,
+	}
+
+	switch param.SchemaType() {
+	case "string":
+		schema.Type = genai.TypeString
+	case "number":
+		schema.Type = genai.TypeNumber
+	case "integer":
+		schema.Type = genai.TypeInteger
+	case "boolean":
+		schema.Type = genai.TypeBoolean
+	case "array":
+		schema.Type = genai.TypeArray
+	case "object":
+		schema.Type = genai.TypeObject
+	}
+
+	if param.Items != nil {
+		schema.Items = convertParameterToGeminiSchema(*param.Items)
+	}
+
+	if param.Enum != nil {
+		enumStrings := make([]string, len(param.Enum))
+		for i, e := range param.Enum {
+			enumStrings[i] = fmt.Sprintf("%v", e)
+		}
+		schema.Enum = enumStrings
+	}
+
+	if param.Properties != nil {
+		nested := convertToGeminiSchema(param.Properties)
+		schema.Properties = nested.Properties
+		schema.Required = nested.Required
+	}
+
+	return schema
+}
+
+// responseText joins the text parts of a response. Thought parts are only kept, as a leading
+// thinking block, when the thinking policy includes thinking and no structured output is requested.
+func (c *GeminiClient) responseText(ctx context.Context, parts []*genai.Part, separator string, params *interfaces.GenerateOptions) string {
+	var textParts []string
+	var thinkingParts []string
+	for _, part := range parts {
+		if part.Text == "" {
+			continue
+		}
+		if part.Thought {
+			thinkingParts = append(thinkingParts, part.Text)
+		} else {
+			textParts = append(textParts, part.Text)
+		}
+	}
+
+	response := strings.Join(textParts, separator)
+	if len(thinkingParts) == 0 {
+		return response
+	}
+
+	if !params.ThinkingPolicy.IncludesThinking() || params.ResponseFormat != nil {
+		c.logger.Debug(ctx, "Thinking content received but not included in response", map[string]interface{}{
+			"thinkingParts": len(thinkingParts),
+			"finalParts":    len(textParts),
+		})
+		return response
+	}
+
+	thinking := strings.Join(thinkingParts, "")
+	c.logger.Debug(ctx, "Received thinking content", map[string]interface{}{
+		"model":    c.model,
+		"policy":   string(params.ThinkingPolicy),
+		"thinking": thinking,
+	})
+	return interfaces.FormatThinking(thinking, response)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -371,6 +373,15 @@ func TestMockTool(t *testing.T) {
 // to properly test the actual API calls. For now, we focus on unit tests for
 // the configuration and setup logic.
 
+func TestNormalizeFinishReason(t *testing.T) {
+	assert.Equal(t, interfaces.FinishReasonStop, normalizeFinishReason(genai.FinishReasonStop))
+	assert.Equal(t, interfaces.FinishReasonLength, normalizeFinishReason(genai.FinishReasonMaxTokens))
+	assert.Equal(t, interfaces.FinishReasonContentFilter, normalizeFinishReason(genai.FinishReasonSafety))
+	assert.Equal(t, interfaces.FinishReasonContentFilter, normalizeFinishReason(genai.FinishReasonProhibitedContent))
+	assert.Equal(t, interfaces.FinishReasonUnknown, normalizeFinishReason(genai.FinishReasonMalformedFunctionCall))
+	assert.Equal(t, interfaces.FinishReasonUnknown, normalizeFinishReason(""))
+}
+
 func TestClientName(t *testing.T) {
 	client, err := NewClient(t.Context(), WithAPIKey("test-api-key"))
 	require.NoError(t, err)
@@ -506,6 +517,46 @@ func TestThinkingClientOptions(t *testing.T) {
 	assert.Nil(t, client.thinkingConfig.ThinkingBudget)
 }
 
+func TestPerCallThinkingBudget(t *testing.T) {
+	client := &GeminiClient{
+		model:  ModelGemini25Flash,
+		logger: logging.New(),
+	}
+	llmConfig := &interfaces.LLMConfig{ThinkingBudget: 2048}
+
+	require.NoError(t, client.validateThinkingBudget(llmConfig))
+
+	config := &genai.GenerateContentConfig{}
+	client.applyThinkingBudget(config, llmConfig)
+	require.NotNil(t, config.ThinkingConfig)
+	require.NotNil(t, config.ThinkingConfig.ThinkingBudget)
+	assert.Equal(t, int32(2048), *config.ThinkingConfig.ThinkingBudget)
+
+	// Exceeding the model maximum is rejected
+	assert.Error(t, client.validateThinkingBudget(&interfaces.LLMConfig{ThinkingBudget: 100000}))
+
+	// Non-thinking models return an error
+	client.model = ModelGemini15Flash
+	assert.Error(t, client.validateThinkingBudget(llmConfig))
+	_, err := client.Generate(context.Background(), "test", interfaces.WithThinkingBudget(1024))
+	assert.Error(t, err)
+}
+
+func TestAssistantPartsPreservesToolCalls(t *testing.T) {
+	parts := assistantParts(interfaces.Message{
+		Role: "assistant",
+		ToolCalls: []interfaces.ToolCall{
+			{ID: "call_1", Name: "search", Arguments: `{"query":"weather"}`},
+		},
+	})
+
+	require.Len(t, parts, 1)
+	require.NotNil(t, parts[0].FunctionCall)
+	assert.Equal(t, "search", parts[0].FunctionCall.Name)
+	assert.Equal(t, "weather", parts[0].FunctionCall.Args["query"])
+	assert.Empty(t, parts[0].Text)
+}
+
 func TestDefaultThinkingConfig(t *testing.T) {
 	config := DefaultThinkingConfig()
 
@@ -905,3 +956,525 @@ func TestGenerateWithTools(t *testing.T) {
 		t.Errorf("Expected 2 requests, got %d", requestCount)
 	}
 }
+
+// structuredTool returns a JSON object result
+type structuredTool struct {
+	MockTool
+}
+
+func (t *structuredTool) Execute(ctx context.Context, args string) (string, error) {
+	return `{"temperature": 21, "unit": "celsius"}`, nil
+}
+
+func TestGenerateWithToolsSendsStructuredFunctionResponses(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		requests = append(requests, reqBody)
+
+		var parts []map[string]interface{}
+		if len(requests) == 1 {
+			parts = []map[string]interface{}{
+				{"functionCall": map[string]interface{}{"id": "call-1", "name": "get_weather", "args": map[string]interface{}{"city": "Paris"}}},
+				{"functionCall": map[string]interface{}{"id": "call-2", "name": "echo", "args": map[string]interface{}{"text": "hi"}}},
+			}
+		} else {
+			parts = []map[string]interface{}{{"text": "It is 21 degrees in Paris"}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"candidates": []map[string]interface{}{
+				{"content": map[string]interface{}{"role": "model", "parts": parts}},
+			},
+		}))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
+		Backend:     genai.BackendGeminiAPI,
+		APIKey:      "test-key",
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	require.NoError(t, err)
+
+	client := &GeminiClient{model: DefaultModel, genaiClient: genaiClient, logger: logging.New()}
+	tools := []interfaces.Tool{
+		&structuredTool{MockTool{name: "get_weather", description: "Get the weather"}},
+		&MockTool{name: "echo", description: "Echo the input"},
+	}
+
+	response, err := client.GenerateWithTools(ctx, "What's the weather in Paris?", tools)
+	require.NoError(t, err)
+	assert.Equal(t, "It is 21 degrees in Paris", response)
+	require.Len(t, requests, 2)
+
+	contents := requests[1]["contents"].([]interface{})
+	require.Len(t, contents, 3)
+
+	functionResponses := contents[2].(map[string]interface{})
+	assert.Equal(t, "user", functionResponses["role"])
+	parts := functionResponses["parts"].([]interface{})
+	require.Len(t, parts, 2)
+
+	weather := parts[0].(map[string]interface{})["functionResponse"].(map[string]interface{})
+	assert.Equal(t, "call-1", weather["id"])
+	assert.Equal(t, "get_weather", weather["name"])
+	assert.Equal(t, map[string]interface{}{"temperature": float64(21), "unit": "celsius"}, weather["response"])
+	assert.Nil(t, parts[0].(map[string]interface{})["text"])
+
+	echo := parts[1].(map[string]interface{})["functionResponse"].(map[string]interface{})
+	assert.Equal(t, "echo", echo["name"])
+	assert.Equal(t, map[string]interface{}{"result": `mock result: {"text":"hi"}`}, echo["response"])
+}
+
+func TestFunctionResponsePart(t *testing.T) {
+	part := functionResponsePart("id", "tool", `[1, 2]`, nil)
+	assert.Equal(t, map[string]any{"result": []any{float64(1), float64(2)}}, part.FunctionResponse.Response)
+
+	part = functionResponsePart("id", "tool", "", assert.AnError)
+	assert.Equal(t, map[string]any{"error": assert.AnError.Error()}, part.FunctionResponse.Response)
+}
+
+func TestConvertToGeminiSchemaNestedObjects(t *testing.T) {
+	tool := &MockTool{
+		name:        "ship",
+		description: "Ship an order",
+		parameters: map[string]interfaces.ParameterSpec{
+			"address": {
+				Type:        "object",
+				Description: "Shipping address",
+				Required:    true,
+				Properties: map[string]interfaces.ParameterSpec{
+					"city": {Type: "string", Description: "City name", Required: true},
+					"geo": {
+						Type: "object",
+						Properties: map[string]interfaces.ParameterSpec{
+							"lat": {Type: "number", Required: true},
+						},
+					},
+				},
+			},
+			"items": {
+				Type: "array",
+				Items: &interfaces.ParameterSpec{
+					Type: "object",
+					Properties: map[string]interfaces.ParameterSpec{
+						"sku": {Type: "string", Required: true},
+					},
+				},
+			},
+		},
+	}
+
+	schema := convertToGeminiSchema(tool.Parameters())
+	assert.Equal(t, genai.TypeObject, schema.Type)
+	assert.Equal(t, []string{"address"}, schema.Required)
+
+	address := schema.Properties["address"]
+	require.NotNil(t, address)
+	assert.Equal(t, genai.TypeObject, address.Type)
+	assert.Equal(t, "Shipping address", address.Description)
+	assert.Equal(t, []string{"city"}, address.Required)
+	require.Contains(t, address.Properties, "city")
+	assert.Equal(t, genai.TypeString, address.Properties["city"].Type)
+
+	geo := address.Properties["geo"]
+	require.NotNil(t, geo)
+	assert.Equal(t, genai.TypeNumber, geo.Properties["lat"].Type)
+	assert.Equal(t, []string{"lat"}, geo.Required)
+
+	items := schema.Properties["items"]
+	require.NotNil(t, items.Items)
+	assert.Equal(t, genai.TypeObject, items.Items.Type)
+	assert.Contains(t, items.Items.Properties, "sku")
+}
+
+func TestConvertToGeminiSchemaArrayOfObjects(t *testing.T) {
+	schema := convertToGeminiSchema(map[string]interfaces.ParameterSpec{
+		"headers": {
+			Type:     "array",
+			Required: true,
+			Items: &interfaces.ParameterSpec{
+				// The object type is inferred from the properties
+				Properties: map[string]interfaces.ParameterSpec{
+					"value": {Type: "string", Description: "Header value", Required: true},
+					"name":  {Type: "string", Description: "Header name", Required: true},
+					"ttl":   {Type: "integer"},
+				},
+			},
+		},
+	})
+	assert.Equal(t, []string{"headers"}, schema.Required)
+
+	headers := schema.Properties["headers"]
+	require.NotNil(t, headers)
+	assert.Equal(t, genai.TypeArray, headers.Type)
+
+	items := headers.Items
+	require.NotNil(t, items)
+	assert.Equal(t, genai.TypeObject, items.Type)
+	assert.Equal(t, []string{"name", "value"}, items.Required)
+	require.Contains(t, items.Properties, "name")
+	assert.Equal(t, genai.TypeString, items.Properties["name"].Type)
+	assert.Equal(t, "Header value", items.Properties["value"].Description)
+	assert.Equal(t, genai.TypeInteger, items.Properties["ttl"].Type)
+}
+
+func TestResponseTextThinkingPolicy(t *testing.T) {
+	client := &GeminiClient{model: ModelGemini25Flash, logger: logging.New()}
+	parts := []*genai.Part{
+		{Text: "Let me think.", Thought: true},
+		{Text: "answer"},
+	}
+
+	tests := []struct {
+		policy   interfaces.ThinkingPolicy
+		expected string
+	}{
+		{"", "answer"},
+		{interfaces.ThinkingPolicyHidden, "answer"},
+		{interfaces.ThinkingPolicyInternal, interfaces.FormatThinking("Let me think.", "answer")},
+		{interfaces.ThinkingPolicyVisible, interfaces.FormatThinking("Let me think.", "answer")},
+	}
+
+	for _, tt := range tests {
+		params := &interfaces.GenerateOptions{ThinkingPolicy: tt.policy}
+		assert.Equal(t, tt.expected, client.responseText(context.Background(), parts, "", params), "policy %q", tt.policy)
+	}
+
+	// Thinking is never mixed into structured output
+	params := &interfaces.GenerateOptions{ThinkingPolicy: interfaces.ThinkingPolicyVisible, ResponseFormat: &interfaces.ResponseFormat{}}
+	assert.Equal(t, "answer", client.responseText(context.Background(), parts, "", params))
+}
+
+func TestApplyThinkingPolicy(t *testing.T) {
+	client := &GeminiClient{model: ModelGemini25Flash, logger: logging.New()}
+
+	config := &genai.GenerateContentConfig{}
+	client.applyThinkingPolicy(config, interfaces.ThinkingPolicyInternal)
+	require.NotNil(t, config.ThinkingConfig)
+	assert.True(t, config.ThinkingConfig.IncludeThoughts)
+
+	client.applyThinkingPolicy(config, interfaces.ThinkingPolicyHidden)
+	assert.False(t, config.ThinkingConfig.IncludeThoughts)
+
+	config = &genai.GenerateContentConfig{}
+	client.applyThinkingPolicy(config, interfaces.ThinkingPolicyHidden)
+	assert.Nil(t, config.ThinkingConfig)
+}
+
+// recordingLogger captures the fields of completion summary logs
+type recordingLogger struct {
+	summaries []map[string]interface{}
+}
+
+func (l *recordingLogger) record(msg string, fields map[string]interface{}) {
+	if msg == "LLM call completed" {
+		l.summaries = append(l.summaries, fields)
+	}
+}
+
+func (l *recordingLogger) Info(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record(msg, fields)
+}
+
+func (l *recordingLogger) Warn(ctx context.Context, msg string, fields map[string]interface{}) {}
+
+func (l *recordingLogger) Error(ctx context.Context, msg string, fields map[string]interface{}) {}
+
+func (l *recordingLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {}
+
+func TestGenerateLogsCompletionSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"candidates": [{"content": {"role": "model", "parts": [{"text": "test response"}]}}],
+			"usageMetadata": {"promptTokenCount": 9, "candidatesTokenCount": 4, "thoughtsTokenCount": 2, "totalTokenCount": 15}
+		}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	genaiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
+		Backend: genai.BackendGeminiAPI,
+		APIKey:  "test-key",
+		HTTPOptions: genai.HTTPOptions{
+			BaseURL: server.URL,
+		},
+	})
+	require.NoError(t, err)
+
+	logger := &recordingLogger{}
+	client := &GeminiClient{
+		model:       ModelGemini25Flash,
+		genaiClient: genaiClient,
+		logger:      logger,
+	}
+
+	resp, err := client.Generate(ctx, "test prompt")
+	require.NoError(t, err)
+	assert.Equal(t, "test response", resp)
+
+	require.Len(t, logger.summaries, 1)
+	summary := logger.summaries[0]
+	assert.Equal(t, "gemini", summary["provider"])
+	assert.Equal(t, ModelGemini25Flash, summary["model"])
+	assert.Equal(t, int64(9), summary["prompt_tokens"])
+	assert.Equal(t, int64(6), summary["response_tokens"])
+	assert.Equal(t, int64(15), summary["total_tokens"])
+	assert.Equal(t, 0, summary["retries"])
+	assert.Equal(t, "success", summary["status"])
+	assert.IsType(t, int64(0), summary["latency_ms"])
+}
+
+func TestStopSequencesReachRequest(t *testing.T) {
+	var stopSequences []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			GenerationConfig map[string]interface{} `json:"generationConfig"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		stopSequences, _ = reqBody.GenerationConfig["stopSequences"].([]interface{})
+
+		if strings.Contains(r.URL.Path, "streamGenerateContent") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: " + testContentResponse + "\n\n"))
+			return
+		}
+		writeContentResponse(w, r)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server)
+	expected := []interface{}{"END", "\n\n"}
+	option := WithStopSequences([]string{"END", "\n\n"})
+
+	_, err := client.Generate(context.Background(), "test prompt", option)
+	require.NoError(t, err)
+	assert.Equal(t, expected, stopSequences)
+
+	stopSequences = nil
+	tool := &MockTool{name: "lookup", description: "Looks things up", parameters: map[string]interfaces.ParameterSpec{}}
+	_, err = client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{tool}, option)
+	require.NoError(t, err)
+	assert.Equal(t, expected, stopSequences)
+
+	stopSequences = nil
+	events, err := client.GenerateStream(context.Background(), "test prompt", interfaces.WithStopSequences([]string{"END", "\n\n"}))
+	require.NoError(t, err)
+	for event := range events {
+		require.NoError(t, event.Error)
+	}
+	assert.Equal(t, expected, stopSequences)
+}
+
+func TestToolChoiceReachesRequest(t *testing.T) {
+	var requestConfig map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			ToolConfig map[string]interface{} `json:"toolConfig"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		requestConfig = reqBody.ToolConfig
+		writeContentResponse(w, r)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server)
+	tool := &MockTool{name: "lookup", description: "Looks things up", parameters: map[string]interfaces.ParameterSpec{}}
+
+	_, err := client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{tool},
+		interfaces.WithToolChoice(interfaces.RequireTool("lookup")))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"functionCallingConfig": map[string]interface{}{"mode": "ANY", "allowedFunctionNames": []interface{}{"lookup"}},
+	}, requestConfig)
+
+	// Without a choice the default of the API is kept
+	_, err = client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{tool})
+	require.NoError(t, err)
+	assert.Nil(t, requestConfig)
+
+	assert.Equal(t, genai.FunctionCallingConfigModeNone, toolConfig(&interfaces.ToolChoice{Mode: interfaces.ToolChoiceNone}).FunctionCallingConfig.Mode)
+}
+
+func TestMaxTokensReachRequest(t *testing.T) {
+	var maxOutputTokens interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			GenerationConfig map[string]interface{} `json:"generationConfig"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		maxOutputTokens = reqBody.GenerationConfig["maxOutputTokens"]
+
+		if strings.Contains(r.URL.Path, "streamGenerateContent") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: " + testContentResponse + "\n\n"))
+			return
+		}
+		writeContentResponse(w, r)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server)
+
+	_, err := client.Generate(context.Background(), "test prompt")
+	require.NoError(t, err)
+	assert.Nil(t, maxOutputTokens, "the model default applies when unset")
+
+	_, err = client.Generate(context.Background(), "test prompt", WithMaxTokens(256))
+	require.NoError(t, err)
+	assert.Equal(t, float64(256), maxOutputTokens)
+
+	maxOutputTokens = nil
+	tool := &MockTool{name: "lookup", description: "Looks things up", parameters: map[string]interfaces.ParameterSpec{}}
+	_, err = client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{tool}, WithMaxTokens(256))
+	require.NoError(t, err)
+	assert.Equal(t, float64(256), maxOutputTokens)
+
+	maxOutputTokens = nil
+	events, err := client.GenerateStream(context.Background(), "test prompt", WithMaxTokens(256))
+	require.NoError(t, err)
+	for event := range events {
+		require.NoError(t, event.Error)
+	}
+	assert.Equal(t, float64(256), maxOutputTokens)
+}
+
+func TestStopSequencesLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeContentResponse(w, r)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server)
+	tool := &MockTool{name: "lookup", description: "Looks things up", parameters: map[string]interfaces.ParameterSpec{}}
+
+	for _, sequences := range [][]string{
+		{"a", "b", "c", "d", "e", "f"},
+		{"END", ""},
+	} {
+		option := WithStopSequences(sequences)
+
+		_, err := client.Generate(context.Background(), "test prompt", option)
+		assert.Error(t, err)
+		_, err = client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{tool}, option)
+		assert.Error(t, err)
+		_, err = client.GenerateStream(context.Background(), "test prompt", option)
+		assert.Error(t, err)
+		_, err = client.GenerateWithToolsStream(context.Background(), "test prompt", []interfaces.Tool{tool}, option)
+		assert.Error(t, err)
+	}
+
+	_, err := client.Generate(context.Background(), "test prompt", WithStopSequences([]string{"a", "b", "c", "d", "e", "f"}))
+	assert.EqualError(t, err, "gemini accepts at most 5 stop sequences, got 6")
+	assert.Equal(t, int32(0), requests.Load())
+}
+
+func TestPromptPartsWithImages(t *testing.T) {
+	parts := promptParts("What is in these images?", []interfaces.ImageInput{
+		{Data: []byte{0x89, 'P', 'N', 'G'}, MIMEType: "image/png"},
+		{URL: "gs://bucket/photo.jpg"},
+	}, nil)
+
+	require.Len(t, parts, 3)
+	assert.Equal(t, "What is in these images?", parts[0].Text)
+	require.NotNil(t, parts[1].InlineData)
+	assert.Equal(t, "image/png", parts[1].InlineData.MIMEType)
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G'}, parts[1].InlineData.Data)
+	require.NotNil(t, parts[2].FileData)
+	assert.Equal(t, "gs://bucket/photo.jpg", parts[2].FileData.FileURI)
+	assert.Equal(t, "image/jpeg", parts[2].FileData.MIMEType)
+
+	assert.Equal(t, []*genai.Part{{Text: "hello"}}, promptParts("hello", nil, nil))
+}
+
+func TestValidateImages(t *testing.T) {
+	png := interfaces.ImageInput{Data: []byte{1}, MIMEType: "image/png"}
+
+	client := &GeminiClient{model: ModelGemini25Flash, logger: logging.New()}
+	assert.NoError(t, client.validateImages(nil))
+	assert.NoError(t, client.validateImages([]interfaces.ImageInput{png, {URL: "gs://bucket/photo.webp"}}))
+	assert.ErrorContains(t, client.validateImages([]interfaces.ImageInput{{Data: []byte{1}}}), "requires a MIME type")
+	assert.ErrorContains(t, client.validateImages([]interfaces.ImageInput{{URL: "gs://bucket/photo"}}), "MIME type required")
+	assert.ErrorContains(t, client.validateImages([]interfaces.ImageInput{{Data: []byte{1}, MIMEType: "image/gif"}}), "does not support image/gif")
+
+	lite := &GeminiClient{model: ModelGemini25FlashLite, logger: logging.New()}
+	err := lite.validateImages([]interfaces.ImageInput{png})
+	assert.ErrorContains(t, err, "model gemini-2.5-flash-lite does not support image input")
+
+	// The request is rejected before anything is sent
+	_, err = lite.Generate(context.Background(), "Describe the image", interfaces.WithImages([]interfaces.ImageInput{png}))
+	assert.ErrorContains(t, err, "does not support image input")
+	_, err = lite.GenerateStream(context.Background(), "Describe the image", interfaces.WithImages([]interfaces.ImageInput{png}))
+	assert.ErrorContains(t, err, "does not support image input")
+}
+
+func TestStructuredOutputReachesRequest(t *testing.T) {
+	var generationConfig map[string]interface{}
+	var prompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Contents []struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"contents"`
+			GenerationConfig map[string]interface{} `json:"generationConfig"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		generationConfig = reqBody.GenerationConfig
+		prompt = reqBody.Contents[0].Parts[0].Text
+		writeContentResponse(w, r)
+	}))
+	defer server.Close()
+
+	format := interfaces.ResponseFormat{
+		Type: interfaces.ResponseFormatJSON,
+		Name: "Person",
+		Schema: interfaces.JSONSchema{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]interface{}{
+				"name":  map[string]interface{}{"type": "string"},
+				"email": map[string]interface{}{"type": []interface{}{"string", "null"}},
+				"level": map[string]interface{}{"type": "integer", "enum": []interface{}{1, 2, 3}},
+			},
+			"required": []interface{}{"name"},
+		},
+	}
+
+	// Supported models enforce the schema natively
+	client := newRetryTestClient(t, server)
+	_, err := client.Generate(context.Background(), "Extract the person", WithResponseFormat(format))
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", generationConfig["responseMimeType"])
+	schema, ok := generationConfig["responseSchema"].(map[string]interface{})
+	require.True(t, ok, "expected a response schema, got %v", generationConfig)
+	assert.Equal(t, "OBJECT", schema["type"])
+	assert.Equal(t, []interface{}{"name"}, schema["required"])
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "STRING", "nullable": true}, properties["email"])
+	assert.Equal(t, []interface{}{"1", "2", "3"}, properties["level"].(map[string]interface{})["enum"])
+	assert.Equal(t, "Extract the person", prompt)
+
+	// Legacy models get the schema in the prompt instead
+	client.model = "gemini-1.0-pro"
+	_, err = client.Generate(context.Background(), "Extract the person", WithResponseFormat(format))
+	require.NoError(t, err)
+	assert.NotContains(t, generationConfig, "responseSchema")
+	assert.Contains(t, prompt, "matching this JSON schema")
+	assert.Contains(t, prompt, `"required"`)
+}
+
+func TestSupportsStructuredOutput(t *testing.T) {
+	assert.True(t, SupportsStructuredOutput(ModelGemini25Flash))
+	assert.True(t, SupportsStructuredOutput(ModelGemini15Pro))
+	assert.False(t, SupportsStructuredOutput("gemini-1.0-pro"))
+	assert.False(t, SupportsStructuredOutput(ModelGemini25FlashPreviewTTS))
+}
@@ -0,0 +1,65 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// filePollInterval is the interval between checks of an uploaded file still being processed
+const filePollInterval = time.Second
+
+// UploadFile uploads a file with the Gemini File API and returns its URI. Passing the URI to
+// Generate with interfaces.WithFiles references the file without sending it again, which suits
+// large documents and videos used across several calls. Uploads wait until the file is
+// processed and ready to use. The File API is only available with the Gemini API backend, and
+// uploaded files expire after 48 hours.
+func (c *GeminiClient) UploadFile(ctx context.Context, data []byte, mimeType string) (string, error) {
+	if mimeType == "" {
+		return "", fmt.Errorf("MIME type required to upload a file")
+	}
+
+	client := c.client()
+	file, err := client.Files.Upload(ctx, bytes.NewReader(data), &genai.UploadFileConfig{MIMEType: mimeType})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	// Large files, such as videos, are processed before they can be referenced
+	for file.State == genai.FileStateProcessing {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("failed to upload file: %w", ctx.Err())
+		case <-time.After(filePollInterval):
+		}
+
+		file, err = client.Files.Get(ctx, file.Name, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to get uploaded file: %w", err)
+		}
+	}
+	if file.State == genai.FileStateFailed {
+		return "", fmt.Errorf("failed to process uploaded file %s", file.Name)
+	}
+
+	c.logger.Debug(ctx, "Uploaded file to Gemini File API", map[string]interface{}{
+		"name":      file.Name,
+		"mime_type": mimeType,
+		"size":      len(data),
+	})
+	return file.URI, nil
+}
+
+// fileParts returns the parts referencing the uploaded files of a request
+func fileParts(files []interfaces.FileInput) []*genai.Part {
+	parts := make([]*genai.Part, 0, len(files))
+	for _, file := range files {
+		parts = append(parts, &genai.Part{FileData: &genai.FileData{FileURI: file.URI, MIMEType: file.MIMEType}})
+	}
+	return parts
+}
@@ -0,0 +1,86 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const testFileURI = "https://generativelanguage.googleapis.com/v1beta/files/report-123"
+
+// fileAPIHandler mocks the resumable upload of the File API and records the uploaded data and
+// the generate requests
+func fileAPIHandler(t *testing.T, uploaded *string, generateRequests *[]map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/upload/v1beta/files":
+			assert.Equal(t, "application/pdf", r.Header.Get("X-Goog-Upload-Header-Content-Type"))
+			w.Header().Set("X-Goog-Upload-Url", "http://"+r.Host+"/upload/session")
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/upload/session":
+			data, _ := io.ReadAll(r.Body)
+			*uploaded = string(data)
+			w.Header().Set("X-Goog-Upload-Status", "final")
+			_, _ = w.Write([]byte(`{"file": {"name": "files/report-123", "uri": "` + testFileURI + `", "mimeType": "application/pdf", "state": "ACTIVE"}}`))
+		case strings.HasSuffix(r.URL.Path, ":generateContent"):
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			*generateRequests = append(*generateRequests, body)
+			writeContentResponse(w, r)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	var uploaded string
+	var generateRequests []map[string]interface{}
+	server := httptest.NewServer(fileAPIHandler(t, &uploaded, &generateRequests))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server)
+	uri, err := client.UploadFile(context.Background(), []byte("%PDF-1.7 annual report"), "application/pdf")
+	require.NoError(t, err)
+	assert.Equal(t, testFileURI, uri)
+	assert.Equal(t, "%PDF-1.7 annual report", uploaded)
+
+	// Each call references the uploaded file by URI instead of sending its content
+	files := []interfaces.FileInput{{URI: uri, MIMEType: "application/pdf"}}
+	for _, prompt := range []string{"Summarize the report", "List the risks in the report"} {
+		resp, err := client.Generate(context.Background(), prompt, interfaces.WithFiles(files))
+		require.NoError(t, err)
+		assert.Equal(t, "test response", resp)
+	}
+
+	require.Len(t, generateRequests, 2)
+	for _, request := range generateRequests {
+		contents := request["contents"].([]interface{})
+		parts := contents[len(contents)-1].(map[string]interface{})["parts"].([]interface{})
+		require.Len(t, parts, 2)
+		fileData := parts[1].(map[string]interface{})["fileData"].(map[string]interface{})
+		assert.Equal(t, testFileURI, fileData["fileUri"])
+		assert.Equal(t, "application/pdf", fileData["mimeType"])
+	}
+}
+
+func TestUploadFileValidation(t *testing.T) {
+	client := &GeminiClient{model: ModelGemini25Flash}
+
+	_, err := client.UploadFile(context.Background(), []byte("data"), "")
+	assert.EqualError(t, err, "MIME type required to upload a file")
+
+	_, err = client.Generate(context.Background(), "Summarize the report", interfaces.WithFiles([]interfaces.FileInput{{URI: testFileURI}}))
+	assert.EqualError(t, err, "invalid file 1: MIME type required for "+testFileURI)
+}
@@ -1,7 +1,10 @@
 package gemini
 
 import (
+	"google.golang.org/genai"
+
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 )
 
 // WithTemperature creates a GenerateOption to set the temperature
@@ -34,6 +37,17 @@ func WithStopSequences(stopSequences []string) interfaces.GenerateOption {
 	}
 }
 
+// WithMaxTokens creates a GenerateOption to set the maximum number of output tokens.
+// When unset, the model default applies.
+func WithMaxTokens(maxTokens int) interfaces.GenerateOption {
+	return func(options *interfaces.GenerateOptions) {
+		if options.LLMConfig == nil {
+			options.LLMConfig = &interfaces.LLMConfig{}
+		}
+		options.LLMConfig.MaxTokens = maxTokens
+	}
+}
+
 // WithSystemMessage creates a GenerateOption to set the system message
 func WithSystemMessage(systemMessage string) interfaces.GenerateOption {
 	return func(options *interfaces.GenerateOptions) {
@@ -113,3 +127,74 @@ func WithThinkingConfig(config ThinkingConfig) Option {
 		c.thinkingConfig = &config
 	}
 }
+
+// validateThinkingBudget returns an error if a per-call thinking budget is invalid for the model
+func (c *GeminiClient) validateThinkingBudget(config *interfaces.LLMConfig) error {
+	if config == nil || config.ThinkingBudget <= 0 {
+		return nil
+	}
+	return ValidateThinkingBudget(c.model, int32(config.ThinkingBudget))
+}
+
+// toolConfig converts a tool choice to the Gemini function calling configuration, or nil to
+// keep the default
+func toolConfig(choice *interfaces.ToolChoice) *genai.ToolConfig {
+	if choice == nil {
+		return nil
+	}
+
+	config := &genai.FunctionCallingConfig{}
+	switch choice.Mode {
+	case interfaces.ToolChoiceNone:
+		config.Mode = genai.FunctionCallingConfigModeNone
+	case interfaces.ToolChoiceRequired:
+		config.Mode = genai.FunctionCallingConfigModeAny
+	case interfaces.ToolChoiceTool:
+		config.Mode = genai.FunctionCallingConfigModeAny
+		config.AllowedFunctionNames = []string{choice.Name}
+	default:
+		config.Mode = genai.FunctionCallingConfigModeAuto
+	}
+	return &genai.ToolConfig{FunctionCallingConfig: config}
+}
+
+// maxStopSequences is the maximum number of stop sequences accepted by the Gemini API
+const maxStopSequences = 5
+
+// validateStopSequences returns an error if the stop sequences would be rejected by the Gemini API
+func validateStopSequences(config *interfaces.LLMConfig) error {
+	if config == nil {
+		return nil
+	}
+	return llm.ValidateStopSequences("gemini", config.StopSequences, maxStopSequences)
+}
+
+// applyThinkingBudget overrides the thinking budget of a request with the per-call budget, if any.
+// Gemini accounts thinking tokens separately from the output limit.
+func (c *GeminiClient) applyThinkingBudget(config *genai.GenerateContentConfig, llmConfig *interfaces.LLMConfig) {
+	if llmConfig == nil || llmConfig.ThinkingBudget <= 0 || !SupportsThinking(c.model) {
+		return
+	}
+	budget := int32(llmConfig.ThinkingBudget)
+	if config.ThinkingConfig == nil {
+		config.ThinkingConfig = &genai.ThinkingConfig{
+			IncludeThoughts: c.thinkingConfig != nil && c.thinkingConfig.IncludeThoughts,
+		}
+	}
+	config.ThinkingConfig.ThinkingBudget = &budget
+}
+
+// applyThinkingPolicy requests thought summaries when the thinking policy keeps thinking content
+// and suppresses them when it is hidden
+func (c *GeminiClient) applyThinkingPolicy(config *genai.GenerateContentConfig, policy interfaces.ThinkingPolicy) {
+	if policy == "" || !SupportsThinking(c.model) {
+		return
+	}
+	if config.ThinkingConfig == nil {
+		if !policy.IncludesThinking() {
+			return
+		}
+		config.ThinkingConfig = &genai.ThinkingConfig{}
+	}
+	config.ThinkingConfig.IncludeThoughts = policy.IncludesThinking()
+}
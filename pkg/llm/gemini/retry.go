@@ -0,0 +1,162 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+)
+
+// retryAfterKey is the context key of the hint filled in by retryAfterTransport
+type retryAfterKey struct{}
+
+// retryAfterHint receives the Retry-After delay of a throttled response
+type retryAfterHint struct {
+	delay time.Duration
+}
+
+// retryAfterTransport records the Retry-After header of transient error responses into the
+// hint of the request context, since genai.APIError does not expose response headers
+type retryAfterTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err == nil && isTransientStatus(resp.StatusCode) {
+		if hint, ok := req.Context().Value(retryAfterKey{}).(*retryAfterHint); ok {
+			hint.delay = retry.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+	}
+	return resp, err
+}
+
+// retryInfoDelay returns the delay of a google.rpc.RetryInfo error detail, if any
+func retryInfoDelay(details []map[string]any) time.Duration {
+	for _, detail := range details {
+		if kind, _ := detail["@type"].(string); !strings.HasSuffix(kind, "google.rpc.RetryInfo") {
+			continue
+		}
+		if value, ok := detail["retryDelay"].(string); ok {
+			if delay, err := time.ParseDuration(value); err == nil && delay > 0 {
+				return delay
+			}
+		}
+	}
+	return 0
+}
+
+// isTransientStatus reports whether an HTTP status denotes a temporary failure worth retrying
+func isTransientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable ||
+		code == http.StatusInternalServerError || code == http.StatusBadGateway || code == http.StatusGatewayTimeout
+}
+
+// classifyError marks errors that must not be retried as permanent and attaches the delay
+// requested by the server to the others
+func classifyError(ctx context.Context, err error, retryAfter time.Duration) error {
+	if ctx.Err() != nil {
+		return retry.Permanent(err)
+	}
+
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		if !isTransientStatus(apiErr.Code) {
+			return retry.Permanent(err)
+		}
+		if retryAfter <= 0 {
+			retryAfter = retryInfoDelay(apiErr.Details)
+		}
+	}
+
+	if retryAfter > 0 {
+		return retry.After(err, retryAfter)
+	}
+	return err
+}
+
+// withRetry runs a request with the retry policy of the client, if any and not disabled for
+// the call, retrying transient errors only. It returns the number of attempts made.
+func (c *GeminiClient) withRetry(ctx context.Context, params *interfaces.GenerateOptions, operation func(ctx context.Context) error) (int, error) {
+	if c.retryExecutor == nil || params.NoRetry {
+		return 1, operation(ctx)
+	}
+
+	attempts := 0
+	err := c.retryExecutor.ExecuteWithMaxAttempts(ctx, int32(params.MaxRetries), func() error {
+		attempts++
+		hint := &retryAfterHint{}
+		if err := operation(context.WithValue(ctx, retryAfterKey{}, hint)); err != nil {
+			err = classifyError(ctx, err, hint.delay)
+			// Transient errors are retried in the next Vertex AI region, if several are configured
+			if !retry.IsPermanent(err) {
+				c.rotateRegion(ctx)
+			}
+			return err
+		}
+		return nil
+	})
+	return attempts, err
+}
+
+// generateContent calls GenerateContent with the retry policy of the client
+func (c *GeminiClient) generateContent(ctx context.Context, params *interfaces.GenerateOptions, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, int, error) {
+	var result *genai.GenerateContentResponse
+	attempts, err := c.withRetry(ctx, params, func(ctx context.Context) error {
+		var err error
+		result, err = c.client().Models.GenerateContent(ctx, c.model, contents, config)
+		return err
+	})
+	return result, attempts, err
+}
+
+// startStream opens a content stream with the retry policy of the client. Requests are only
+// retried until the first response arrives, so no partial output is ever repeated.
+func (c *GeminiClient) startStream(ctx context.Context, params *interfaces.GenerateOptions, contents []*genai.Content, config *genai.GenerateContentConfig) (iter.Seq2[*genai.GenerateContentResponse, error], error) {
+	var (
+		first *genai.GenerateContentResponse
+		next  func() (*genai.GenerateContentResponse, error, bool)
+		stop  func()
+	)
+
+	_, err := c.withRetry(ctx, params, func(ctx context.Context) error {
+		next, stop = iter.Pull2(c.client().Models.GenerateContentStream(ctx, c.model, contents, config))
+		// An empty stream yields nothing, leaving first nil
+		response, err, _ := next()
+		if err != nil {
+			stop()
+			return err
+		}
+		first = response
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(*genai.GenerateContentResponse, error) bool) {
+		defer stop()
+		if first == nil || !yield(first, nil) {
+			return
+		}
+		for {
+			response, err, ok := next()
+			if !ok || !yield(response, err) || err != nil {
+				return
+			}
+		}
+	}, nil
+}
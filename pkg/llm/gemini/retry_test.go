@@ -0,0 +1,294 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+)
+
+const testContentResponse = `{"candidates": [{"content": {"role": "model", "parts": [{"text": "test response"}]}}]}`
+
+// newRetryTestClient creates a client talking to server through the Retry-After capturing transport
+func newRetryTestClient(t *testing.T, server *httptest.Server, opts ...retry.Option) *GeminiClient {
+	genaiClient, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		Backend:     genai.BackendGeminiAPI,
+		APIKey:      "test-key",
+		HTTPClient:  &http.Client{Transport: &retryAfterTransport{}},
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	require.NoError(t, err)
+
+	client := &GeminiClient{
+		model:       ModelGemini25Flash,
+		genaiClient: genaiClient,
+		logger:      logging.New(),
+	}
+	WithCompletionLogLevel("none")(client)
+	WithRetry(opts...)(client)
+	return client
+}
+
+// failingHandler fails the first failures requests with status, then delegates to next
+func failingHandler(failures int32, status int, header http.Header, next http.HandlerFunc) (http.HandlerFunc, *atomic.Int32) {
+	var requests atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error": {"code": ` + strconv.Itoa(status) + `, "message": "failure"}}`))
+			return
+		}
+		next(w, r)
+	}, &requests
+}
+
+func writeContentResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(testContentResponse))
+}
+
+func TestGenerateRetriesTransientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		handler, requests := failingHandler(2, status, nil, writeContentResponse)
+		server := httptest.NewServer(handler)
+
+		client := newRetryTestClient(t, server, retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond))
+		resp, err := client.Generate(context.Background(), "test prompt")
+		require.NoError(t, err, "status %d", status)
+		assert.Equal(t, "test response", resp)
+		assert.Equal(t, int32(3), requests.Load())
+
+		server.Close()
+	}
+}
+
+func TestGenerateDoesNotRetryPermanentErrors(t *testing.T) {
+	handler, requests := failingHandler(1, http.StatusBadRequest, nil, writeContentResponse)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond))
+	_, err := client.Generate(context.Background(), "test prompt")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestGenerateWithToolsRetriesTransientErrors(t *testing.T) {
+	handler, requests := failingHandler(1, http.StatusServiceUnavailable, nil, writeContentResponse)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, retry.WithMaxAttempts(2), retry.WithInitialInterval(time.Millisecond))
+	tool := &MockTool{name: "lookup", description: "Looks things up", parameters: map[string]interfaces.ParameterSpec{}}
+	resp, err := client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{tool})
+	require.NoError(t, err)
+	assert.Equal(t, "test response", resp)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestGenerateStreamRetriesBeforeFirstResponse(t *testing.T) {
+	handler, requests := failingHandler(1, http.StatusTooManyRequests, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: " + testContentResponse + "\n\n"))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, retry.WithMaxAttempts(2), retry.WithInitialInterval(time.Millisecond))
+	events, err := client.GenerateStream(context.Background(), "test prompt")
+	require.NoError(t, err)
+
+	var content strings.Builder
+	for event := range events {
+		require.NoError(t, event.Error)
+		if event.Type == interfaces.StreamEventContentDelta {
+			content.WriteString(event.Content)
+		}
+	}
+	assert.Equal(t, "test response", content.String())
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestGenerateWithNoRetry(t *testing.T) {
+	handler, requests := failingHandler(10, http.StatusServiceUnavailable, nil, writeContentResponse)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond))
+	_, err := client.Generate(context.Background(), "test prompt", interfaces.WithNoRetry())
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	tool := &MockTool{name: "lookup", description: "Looks things up", parameters: map[string]interfaces.ParameterSpec{}}
+	_, err = client.GenerateWithTools(context.Background(), "test prompt", []interfaces.Tool{tool}, interfaces.WithNoRetry())
+	require.Error(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	events, err := client.GenerateStream(context.Background(), "test prompt", interfaces.WithNoRetry())
+	require.NoError(t, err)
+	var streamErr error
+	for event := range events {
+		if event.Error != nil {
+			streamErr = event.Error
+		}
+	}
+	require.Error(t, streamErr)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestGenerateWithMaxRetries(t *testing.T) {
+	handler, requests := failingHandler(10, http.StatusServiceUnavailable, nil, writeContentResponse)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond))
+	_, err := client.Generate(context.Background(), "test prompt")
+	require.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// A single attempt fails fast
+	requests.Store(0)
+	_, err = client.Generate(context.Background(), "test prompt", interfaces.WithMaxRetries(1))
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// More attempts than the client default
+	requests.Store(0)
+	_, err = client.Generate(context.Background(), "test prompt", interfaces.WithMaxRetries(5))
+	require.Error(t, err)
+	assert.Equal(t, int32(5), requests.Load())
+}
+
+func TestRetryHonorsRetryAfterHeader(t *testing.T) {
+	handler, _ := failingHandler(1, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}}, writeContentResponse)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server,
+		retry.WithMaxAttempts(2),
+		retry.WithInitialInterval(time.Millisecond),
+		retry.WithMaximumInterval(5*time.Second),
+	)
+
+	start := time.Now()
+	_, err := client.Generate(context.Background(), "test prompt")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestRetryRespectsContextCancellation(t *testing.T) {
+	handler, requests := failingHandler(10, http.StatusServiceUnavailable, nil, writeContentResponse)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, retry.WithMaxAttempts(10), retry.WithInitialInterval(time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Generate(ctx, "test prompt")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestRetryInfoDelay(t *testing.T) {
+	details := []map[string]any{
+		{"@type": "type.googleapis.com/google.rpc.QuotaFailure"},
+		{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "2.5s"},
+	}
+	assert.Equal(t, 2500*time.Millisecond, retryInfoDelay(details))
+	assert.Equal(t, time.Duration(0), retryInfoDelay(nil))
+}
+
+func TestGenerateStreamEmitsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"candidates": [{"content": {"role": "model", "parts": [{"text": "Hello"}]}}], "usageMetadata": {"promptTokenCount": 12, "candidatesTokenCount": 1}}`,
+			`{"candidates": [{"content": {"role": "model", "parts": [{"text": " there"}]}}], "usageMetadata": {"promptTokenCount": 12, "candidatesTokenCount": 1}}`,
+			`{"candidates": [{"content": {"role": "model", "parts": [{"text": "!"}]}, "finishReason": "STOP"}], "usageMetadata": {"promptTokenCount": 12, "candidatesTokenCount": 3, "thoughtsTokenCount": 2}}`,
+		} {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+		}
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server)
+	events, err := client.GenerateStream(context.Background(), "test prompt")
+	require.NoError(t, err)
+
+	var usage []interfaces.TokenUsage
+	for event := range events {
+		require.NoError(t, event.Error)
+		if event.Type == interfaces.StreamEventUsage {
+			usage = append(usage, *event.Usage)
+		}
+	}
+
+	// Usage is cumulative and only sent when it changes
+	assert.Equal(t, []interfaces.TokenUsage{
+		{InputTokens: 12, OutputTokens: 1},
+		{InputTokens: 12, OutputTokens: 5},
+	}, usage)
+}
+
+func TestGenerateRotatesVertexRegions(t *testing.T) {
+	throttled, throttledRequests := failingHandler(10, http.StatusTooManyRequests, nil, writeContentResponse)
+	throttledServer := httptest.NewServer(throttled)
+	defer throttledServer.Close()
+
+	var healthyRequests atomic.Int32
+	healthyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		healthyRequests.Add(1)
+		writeContentResponse(w, r)
+	}))
+	defer healthyServer.Close()
+
+	client := newRetryTestClient(t, throttledServer, retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond))
+	client.regions = []string{"us-central1", "europe-west4"}
+	client.regionClients = []*genai.Client{client.genaiClient, newRetryTestClient(t, healthyServer).genaiClient}
+
+	// A 429 in the first region is retried in the next one
+	resp, err := client.Generate(context.Background(), "test prompt")
+	require.NoError(t, err)
+	assert.Equal(t, "test response", resp)
+	assert.Equal(t, int32(1), throttledRequests.Load())
+	assert.Equal(t, int32(1), healthyRequests.Load())
+	assert.Equal(t, "europe-west4", client.currentRegion())
+
+	// Later requests stay in the healthy region
+	_, err = client.Generate(context.Background(), "test prompt")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), throttledRequests.Load())
+	assert.Equal(t, int32(2), healthyRequests.Load())
+}
+
+func TestWithVertexAIRegions(t *testing.T) {
+	client := &GeminiClient{}
+	WithVertexAICredentials("us-central1, europe-west4", "my-project", "/path/to/key.json")(client)
+
+	assert.Equal(t, genai.BackendVertexAI, client.backend)
+	assert.Equal(t, "my-project", client.projectID)
+	assert.Equal(t, "/path/to/key.json", client.credentialsFile)
+	assert.Equal(t, []string{"us-central1", "europe-west4"}, parseRegions(client.location))
+}
@@ -10,6 +10,7 @@ import (
 	"google.golang.org/genai"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 )
 
@@ -23,6 +24,31 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 		}
 	}
 
+	if err := c.validateInputs(params); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateThinkingBudget(params.LLMConfig); err != nil {
+		return nil, err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Re-request the stream on mid-stream failures, each attempt streaming without resuming
+	if params.StreamRetry > 0 {
+		return llm.ResumeStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamRetry(0))...)
+		})
+	}
+
 	// Get streaming config or use default
 	streamConfig := interfaces.DefaultStreamConfig()
 	if params.StreamConfig != nil {
@@ -56,10 +82,10 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 						Parts: []*genai.Part{{Text: msg.Content}},
 					})
 				case "assistant":
-					if msg.Content != "" {
+					if msg.Content != "" || len(msg.ToolCalls) > 0 {
 						contents = append(contents, &genai.Content{
 							Role:  "model",
-							Parts: []*genai.Part{{Text: msg.Content}},
+							Parts: assistantParts(msg),
 						})
 					}
 				case "tool":
@@ -72,17 +98,8 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 							}
 						}
 						contents = append(contents, &genai.Content{
-							Role: "user",
-							Parts: []*genai.Part{
-								{
-									FunctionResponse: &genai.FunctionResponse{
-										Name: toolName,
-										Response: map[string]any{
-											"result": msg.Content,
-										},
-									},
-								},
-							},
+							Role:  "user",
+							Parts: []*genai.Part{functionResponsePart(msg.ToolCallID, toolName, msg.Content, nil)},
 						})
 					}
 					// Skip system messages as they're handled separately in Gemini
@@ -94,7 +111,7 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 	// Add current user message
 	contents = append(contents, &genai.Content{
 		Role:  "user",
-		Parts: []*genai.Part{{Text: prompt}},
+		Parts: promptParts(c.structuredOutputPrompt(prompt, params.ResponseFormat), params.Images, params.Files),
 	})
 
 	// Add system instruction if provided or if reasoning is specified
@@ -142,6 +159,9 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 		if len(params.LLMConfig.StopSequences) > 0 {
 			genConfig.StopSequences = params.LLMConfig.StopSequences
 		}
+		if params.LLMConfig.MaxTokens > 0 {
+			genConfig.MaxOutputTokens = int32(params.LLMConfig.MaxTokens)
+		}
 	}
 
 	// Set response format if provided
@@ -149,18 +169,7 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 		if genConfig == nil {
 			genConfig = &genai.GenerationConfig{}
 		}
-		genConfig.ResponseMIMEType = "application/json"
-
-		// Convert schema for genai
-		if schemaBytes, err := json.Marshal(params.ResponseFormat.Schema); err == nil {
-			var schema *genai.Schema
-			if err := json.Unmarshal(schemaBytes, &schema); err != nil {
-				c.logger.Warn(ctx, "Failed to convert response schema", map[string]interface{}{"error": err.Error()})
-			} else {
-				genConfig.ResponseSchema = schema
-			}
-		}
-		c.logger.Debug(ctx, "Using response format", map[string]interface{}{"format": *params.ResponseFormat})
+		c.applyResponseFormat(ctx, genConfig, params.ResponseFormat)
 	}
 
 	// Create config
@@ -179,6 +188,9 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 		if len(genConfig.StopSequences) > 0 {
 			config.StopSequences = genConfig.StopSequences
 		}
+		if genConfig.MaxOutputTokens > 0 {
+			config.MaxOutputTokens = genConfig.MaxOutputTokens
+		}
 		if genConfig.ResponseMIMEType != "" {
 			config.ResponseMIMEType = genConfig.ResponseMIMEType
 		}
@@ -201,6 +213,8 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 			})
 		}
 	}
+	c.applyThinkingBudget(config, params.LLMConfig)
+	c.applyThinkingPolicy(config, params.ThinkingPolicy)
 
 	// Create event channel
 	eventCh := make(chan interfaces.StreamEvent, streamConfig.BufferSize)
@@ -228,7 +242,22 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 		var accumulatedContent strings.Builder
 
 		// Start streaming
-		streamIter := c.genaiClient.Models.GenerateContentStream(ctx, c.model, contents, config)
+		streamIter, err := c.startStream(ctx, params, contents, config)
+		if err != nil {
+			select {
+			case eventCh <- interfaces.StreamEvent{
+				Type:      interfaces.StreamEventError,
+				Error:     err,
+				Timestamp: time.Now(),
+			}:
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		// Gemini reports the cumulative usage with every response
+		var usage interfaces.TokenUsage
+		var finishReason genai.FinishReason
 
 		for response, err := range streamIter {
 			if err != nil {
@@ -246,6 +275,9 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 
 			// Process each candidate in the response
 			for _, candidate := range response.Candidates {
+				if candidate.FinishReason != "" {
+					finishReason = candidate.FinishReason
+				}
 				if candidate.Content == nil {
 					continue
 				}
@@ -286,6 +318,26 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 					}
 				}
 			}
+
+			// Send the running token usage whenever it changes
+			if response.UsageMetadata != nil {
+				current := interfaces.TokenUsage{
+					InputTokens:  int64(response.UsageMetadata.PromptTokenCount),
+					OutputTokens: int64(response.UsageMetadata.CandidatesTokenCount + response.UsageMetadata.ThoughtsTokenCount),
+				}
+				if current != usage {
+					usage = current
+					select {
+					case eventCh <- interfaces.StreamEvent{
+						Type:      interfaces.StreamEventUsage,
+						Usage:     &current,
+						Timestamp: time.Now(),
+					}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
 		}
 
 		// Store messages in memory if provided
@@ -316,8 +368,9 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 		// Send content complete event
 		select {
 		case eventCh <- interfaces.StreamEvent{
-			Type:      interfaces.StreamEventContentComplete,
-			Timestamp: time.Now(),
+			Type:         interfaces.StreamEventContentComplete,
+			Timestamp:    time.Now(),
+			FinishReason: normalizeFinishReason(finishReason),
 		}:
 		case <-ctx.Done():
 			return
@@ -351,6 +404,38 @@ func (c *GeminiClient) GenerateWithToolsStream(ctx context.Context, prompt strin
 		}
 	}
 
+	if err := c.validateInputs(params); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream, such as long tool calls
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateWithToolsStream(ctx, prompt, tools, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
+	if err := c.validateThinkingBudget(params.LLMConfig); err != nil {
+		return nil, err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return nil, err
+	}
+
 	// Set default values only if they're not provided
 	if params.LLMConfig == nil {
 		params.LLMConfig = &interfaces.LLMConfig{
@@ -476,10 +561,10 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 						Parts: []*genai.Part{{Text: msg.Content}},
 					})
 				case "assistant":
-					if msg.Content != "" {
+					if msg.Content != "" || len(msg.ToolCalls) > 0 {
 						contents = append(contents, &genai.Content{
 							Role:  "model",
-							Parts: []*genai.Part{{Text: msg.Content}},
+							Parts: assistantParts(msg),
 						})
 					}
 				case "tool":
@@ -492,17 +577,8 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 							}
 						}
 						contents = append(contents, &genai.Content{
-							Role: "user",
-							Parts: []*genai.Part{
-								{
-									FunctionResponse: &genai.FunctionResponse{
-										Name: toolName,
-										Response: map[string]any{
-											"result": msg.Content,
-										},
-									},
-								},
-							},
+							Role:  "user",
+							Parts: []*genai.Part{functionResponsePart(msg.ToolCallID, toolName, msg.Content, nil)},
 						})
 					}
 					// Skip system messages as they're handled separately in Gemini
@@ -514,7 +590,7 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 	// Add current user message
 	contents = append(contents, &genai.Content{
 		Role:  "user",
-		Parts: []*genai.Part{{Text: prompt}},
+		Parts: promptParts(prompt, params.Images, params.Files),
 	})
 
 	// Store initial messages in memory (only new user message and system message)
@@ -549,45 +625,7 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 		functionDeclaration := &genai.FunctionDeclaration{
 			Name:        tool.Name(),
 			Description: tool.Description(),
-			Parameters: &genai.Schema{
-				Type:       genai.TypeObject,
-				Properties: make(map[string]*genai.Schema),
-				Required:   make([]string, 0),
-			},
-		}
-
-		// Convert parameters
-		for name, param := range tool.Parameters() {
-			paramSchema := &genai.Schema{
-				Description: param.Description,
-			}
-
-			// Set type
-			switch param.Type {
-			case "string":
-				paramSchema.Type = genai.TypeString
-			case "number", "integer":
-				paramSchema.Type = genai.TypeNumber
-			case "boolean":
-				paramSchema.Type = genai.TypeBoolean
-			case "array":
-				paramSchema.Type = genai.TypeArray
-			case "object":
-				paramSchema.Type = genai.TypeObject
-			}
-
-			if param.Enum != nil {
-				enumStrings := make([]string, len(param.Enum))
-				for i, e := range param.Enum {
-					enumStrings[i] = fmt.Sprintf("%v", e)
-				}
-				paramSchema.Enum = enumStrings
-			}
-
-			functionDeclaration.Parameters.Properties[name] = paramSchema
-			if param.Required {
-				functionDeclaration.Parameters.Required = append(functionDeclaration.Parameters.Required, name)
-			}
+			Parameters:  convertToGeminiSchema(tool.Parameters()),
 		}
 
 		functionDeclarations = append(functionDeclarations, functionDeclaration)
@@ -618,12 +656,16 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 			if len(params.LLMConfig.StopSequences) > 0 {
 				genConfig.StopSequences = params.LLMConfig.StopSequences
 			}
+			if params.LLMConfig.MaxTokens > 0 {
+				genConfig.MaxOutputTokens = int32(params.LLMConfig.MaxTokens)
+			}
 		}
 
 		// Create config
 		config := &genai.GenerateContentConfig{
 			SystemInstruction: systemInstruction,
 			Tools:             geminiTools,
+			ToolConfig:        toolConfig(params.ToolChoiceForIteration(iteration)),
 		}
 
 		// Apply generation config parameters
@@ -637,6 +679,9 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 			if len(genConfig.StopSequences) > 0 {
 				config.StopSequences = genConfig.StopSequences
 			}
+			if genConfig.MaxOutputTokens > 0 {
+				config.MaxOutputTokens = genConfig.MaxOutputTokens
+			}
 		}
 
 		c.logger.Debug(ctx, "Sending request with tools for streaming", map[string]interface{}{
@@ -650,7 +695,9 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 		// Execute streaming request and collect tool calls
 		shouldFilter := filterIntermediateContent && len(tools) > 0 && iteration < maxIterations-1
 		var iterationContentEvents []interfaces.StreamEvent
-		toolCalls, hasContent, err := c.executeStreamingRequestWithToolCapture(ctx, contents, config, eventCh, shouldFilter, &iterationContentEvents)
+		c.applyThinkingBudget(config, params.LLMConfig)
+		c.applyThinkingPolicy(config, params.ThinkingPolicy)
+		toolCalls, hasContent, err := c.executeStreamingRequestWithToolCapture(ctx, params, contents, config, eventCh, shouldFilter, &iterationContentEvents)
 		if err != nil {
 			return "", err
 		}
@@ -687,6 +734,7 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 			}
 			assistantMessage.Parts = append(assistantMessage.Parts, &genai.Part{
 				FunctionCall: &genai.FunctionCall{
+					ID:   toolCall.ID,
 					Name: toolCall.Name,
 					Args: args,
 				},
@@ -716,14 +764,8 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 
 				// Add tool not found error as function response
 				errorMessage := fmt.Sprintf("Error: tool not found: %s", toolCall.Name)
-				functionResponses = append(functionResponses, &genai.Part{
-					FunctionResponse: &genai.FunctionResponse{
-						Name: toolCall.Name,
-						Response: map[string]any{
-							"error": errorMessage,
-						},
-					},
-				})
+				functionResponses = append(functionResponses, functionResponsePart(
+					toolCall.ID, toolCall.Name, "", fmt.Errorf("tool not found: %s", toolCall.Name)))
 
 				// Send tool result event with error
 				select {
@@ -751,7 +793,8 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 				"iteration": iteration + 1,
 			})
 
-			toolResult, err := selectedTool.Execute(ctx, toolCall.Arguments)
+			toolResult, err := interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), selectedTool, toolCall.Arguments)
+			functionResponses = append(functionResponses, functionResponsePart(toolCall.ID, toolCall.Name, toolResult, err))
 			if err != nil {
 				toolResult = fmt.Sprintf("Error: %v", err)
 			}
@@ -799,16 +842,6 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 				}
 			}
 
-			// Add tool result as function response
-			functionResponses = append(functionResponses, &genai.Part{
-				FunctionResponse: &genai.FunctionResponse{
-					Name: toolCall.Name,
-					Response: map[string]any{
-						"result": toolResult,
-					},
-				},
-			})
-
 			// Send tool result event
 			select {
 			case eventCh <- interfaces.StreamEvent{
@@ -881,6 +914,9 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 		if len(params.LLMConfig.StopSequences) > 0 {
 			genConfig.StopSequences = params.LLMConfig.StopSequences
 		}
+		if params.LLMConfig.MaxTokens > 0 {
+			genConfig.MaxOutputTokens = int32(params.LLMConfig.MaxTokens)
+		}
 	}
 
 	config := &genai.GenerateContentConfig{
@@ -899,10 +935,15 @@ func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt st
 		if len(genConfig.StopSequences) > 0 {
 			config.StopSequences = genConfig.StopSequences
 		}
+		if genConfig.MaxOutputTokens > 0 {
+			config.MaxOutputTokens = genConfig.MaxOutputTokens
+		}
 	}
 
 	// Execute final request to get synthesized answer using streaming (no filtering for final call)
-	_, _, err := c.executeStreamingRequestWithToolCapture(ctx, contents, config, eventCh, false, nil)
+	c.applyThinkingBudget(config, params.LLMConfig)
+	c.applyThinkingPolicy(config, params.ThinkingPolicy)
+	_, _, err := c.executeStreamingRequestWithToolCapture(ctx, params, contents, config, eventCh, false, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create final content: %w", err)
 	}
@@ -944,6 +985,7 @@ func (c *GeminiClient) streamResponse(ctx context.Context, response string, even
 // executeStreamingRequestWithToolCapture executes a streaming request and captures tool calls
 func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 	ctx context.Context,
+	params *interfaces.GenerateOptions,
 	contents []*genai.Content,
 	config *genai.GenerateContentConfig,
 	eventCh chan<- interfaces.StreamEvent,
@@ -960,7 +1002,7 @@ func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 	})
 
 	// Generate content with tools
-	result, err := c.genaiClient.Models.GenerateContent(ctx, c.model, contents, config)
+	result, _, err := c.generateContent(ctx, params, contents, config)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to generate content: %w", err)
 	}
@@ -986,6 +1028,22 @@ func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 			}
 			toolCalls = append(toolCalls, toolCall)
 
+			// Gemini returns the arguments of a call at once, reported as a single delta so
+			// consumers handle all providers alike
+			delta := toolCall
+			select {
+			case eventCh <- interfaces.StreamEvent{
+				Type:      interfaces.StreamEventToolCallDelta,
+				Timestamp: time.Now(),
+				ToolCall:  &delta,
+				Metadata: map[string]interface{}{
+					"arguments_delta": toolCall.Arguments,
+				},
+			}:
+			case <-ctx.Done():
+				return nil, false, ctx.Err()
+			}
+
 			// Send tool use event to stream
 			select {
 			case eventCh <- interfaces.StreamEvent{
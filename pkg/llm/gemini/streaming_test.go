@@ -0,0 +1,76 @@
+package gemini
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// newStreamingTestClient creates a client without retries talking to server
+func newStreamingTestClient(t *testing.T, server *httptest.Server) *GeminiClient {
+	genaiClient, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		Backend:     genai.BackendGeminiAPI,
+		APIKey:      "test-key",
+		HTTPClient:  server.Client(),
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	require.NoError(t, err)
+
+	client := &GeminiClient{
+		model:       ModelGemini25Flash,
+		genaiClient: genaiClient,
+		logger:      logging.New(),
+	}
+	WithCompletionLogLevel("none")(client)
+	return client
+}
+
+func TestGenerateWithToolsStreamEmitsToolCallDelta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), "functionResponse") {
+			_, _ = w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "It is sunny in Paris."}]}, "finishReason": "STOP"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"functionCall": {"name": "get_weather", "args": {"location": "Paris"}}}]}}]}`))
+	}))
+	defer server.Close()
+
+	client := newStreamingTestClient(t, server)
+	tool := &MockTool{name: "get_weather", description: "Gets the weather", parameters: map[string]interfaces.ParameterSpec{
+		"location": {Type: "string", Description: "The city", Required: true},
+	}}
+	events, err := client.GenerateWithToolsStream(context.Background(), "Weather in Paris?", []interfaces.Tool{tool}, interfaces.WithMaxIterations(2))
+	require.NoError(t, err)
+
+	var types []interfaces.StreamEventType
+	var deltas, toolUses []*interfaces.ToolCall
+	for event := range events {
+		require.NoError(t, event.Error)
+		switch event.Type {
+		case interfaces.StreamEventToolCallDelta:
+			deltas = append(deltas, event.ToolCall)
+		case interfaces.StreamEventToolUse:
+			toolUses = append(toolUses, event.ToolCall)
+		default:
+			continue
+		}
+		types = append(types, event.Type)
+	}
+
+	expectedCall := &interfaces.ToolCall{ID: "gemini_tool_get_weather", Name: "get_weather", Arguments: `{"location":"Paris"}`}
+	assert.Equal(t, []interfaces.StreamEventType{interfaces.StreamEventToolCallDelta, interfaces.StreamEventToolUse}, types)
+	assert.Equal(t, []*interfaces.ToolCall{expectedCall}, deltas)
+	assert.Equal(t, []*interfaces.ToolCall{expectedCall}, toolUses)
+}
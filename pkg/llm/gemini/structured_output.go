@@ -0,0 +1,168 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"google.golang.org/genai"
+)
+
+// SupportsStructuredOutput returns true if the model accepts a response schema, so that
+// structured output is enforced by the API rather than requested in the prompt. The legacy
+// 1.0 models and the live, audio and image generation models do not.
+func SupportsStructuredOutput(model string) bool {
+	switch model {
+	case ModelGeminiLive25FlashPreview, ModelGemini25FlashPreviewNativeAudio, ModelGemini25FlashExpNativeAudioThinking,
+		ModelGemini25FlashPreviewTTS, ModelGemini25ProPreviewTTS, ModelGemini20FlashLive001, ModelGemini20FlashPreviewImageGen:
+		return false
+	}
+	return !strings.HasPrefix(model, "gemini-1.0") && !strings.HasPrefix(model, "gemini-pro")
+}
+
+// applyResponseFormat configures the native structured output of the request, with the JSON
+// MIME type and the schema converted to a Gemini response schema. Models without native
+// support get the schema in the prompt instead, see structuredOutputPrompt.
+func (c *GeminiClient) applyResponseFormat(ctx context.Context, genConfig *genai.GenerationConfig, format *interfaces.ResponseFormat) {
+	if format == nil || !SupportsStructuredOutput(c.model) {
+		return
+	}
+
+	genConfig.ResponseMIMEType = "application/json"
+	if len(format.Schema) > 0 {
+		genConfig.ResponseSchema = convertResponseSchema(format.Schema)
+	}
+	c.logger.Debug(ctx, "Using response format", map[string]interface{}{"format": *format})
+}
+
+// structuredOutputPrompt appends the JSON schema of the response format to the prompt for
+// models without native structured output, returning the prompt unchanged otherwise
+func (c *GeminiClient) structuredOutputPrompt(prompt string, format *interfaces.ResponseFormat) string {
+	if format == nil || SupportsStructuredOutput(c.model) {
+		return prompt
+	}
+
+	schema, err := json.MarshalIndent(format.Schema, "", "  ")
+	if err != nil || len(format.Schema) == 0 {
+		return prompt + "\n\nRespond with a valid JSON object only, without markdown formatting or any other text."
+	}
+	return fmt.Sprintf("%s\n\nRespond with a valid JSON object only, without markdown formatting or any other text, matching this JSON schema:\n%s", prompt, schema)
+}
+
+// convertResponseSchema converts a JSON schema to a Gemini schema. Gemini uses an OpenAPI
+// subset: types are upper case, nullable types are flagged, enums are strings and keywords
+// outside the subset, such as additionalProperties, are dropped.
+func convertResponseSchema(schema map[string]interface{}) *genai.Schema {
+	converted := &genai.Schema{}
+
+	switch schemaType := schema["type"].(type) {
+	case string:
+		converted.Type = genai.Type(strings.ToUpper(schemaType))
+	case []interface{}:
+		// A list of types is only supported as a type made nullable
+		for _, t := range schemaType {
+			if name, ok := t.(string); ok {
+				if name == "null" {
+					converted.Nullable = genai.Ptr(true)
+				} else if converted.Type == "" {
+					converted.Type = genai.Type(strings.ToUpper(name))
+				}
+			}
+		}
+	}
+
+	if description, ok := schema["description"].(string); ok {
+		converted.Description = description
+	}
+	if title, ok := schema["title"].(string); ok {
+		converted.Title = title
+	}
+	if format, ok := schema["format"].(string); ok {
+		converted.Format = format
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		converted.Pattern = pattern
+	}
+	if nullable, ok := schema["nullable"].(bool); ok {
+		converted.Nullable = genai.Ptr(nullable)
+	}
+	switch enum := schema["enum"].(type) {
+	case []interface{}:
+		for _, value := range enum {
+			converted.Enum = append(converted.Enum, fmt.Sprint(value))
+		}
+	case []string:
+		converted.Enum = append(converted.Enum, enum...)
+	}
+	if minimum, ok := toFloat(schema["minimum"]); ok {
+		converted.Minimum = genai.Ptr(minimum)
+	}
+	if maximum, ok := toFloat(schema["maximum"]); ok {
+		converted.Maximum = genai.Ptr(maximum)
+	}
+	if minItems, ok := toFloat(schema["minItems"]); ok {
+		converted.MinItems = genai.Ptr(int64(minItems))
+	}
+	if maxItems, ok := toFloat(schema["maxItems"]); ok {
+		converted.MaxItems = genai.Ptr(int64(maxItems))
+	}
+
+	if items, ok := schemaMap(schema["items"]); ok {
+		converted.Items = convertResponseSchema(items)
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, option := range anyOf {
+			if optionSchema, ok := schemaMap(option); ok {
+				converted.AnyOf = append(converted.AnyOf, convertResponseSchema(optionSchema))
+			}
+		}
+	}
+	if properties, ok := schemaMap(schema["properties"]); ok {
+		converted.Properties = make(map[string]*genai.Schema, len(properties))
+		for name, property := range properties {
+			if propertySchema, ok := schemaMap(property); ok {
+				converted.Properties[name] = convertResponseSchema(propertySchema)
+			}
+		}
+	}
+	switch required := schema["required"].(type) {
+	case []interface{}:
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				converted.Required = append(converted.Required, name)
+			}
+		}
+	case []string:
+		converted.Required = append(converted.Required, required...)
+	}
+
+	return converted
+}
+
+// schemaMap returns a schema value as a map, accepting both generic maps and JSON schemas
+func schemaMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case interfaces.JSONSchema:
+		return m, true
+	}
+	return nil, false
+}
+
+// toFloat returns a numeric schema value as a float
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,60 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
+)
+
+func TestGenerateSendsEnumResponseSchema(t *testing.T) {
+	var reqBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "{\"value\":\"positive\"}"}]}}]}`))
+	}))
+	defer server.Close()
+
+	genaiClient, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		Backend:     genai.BackendGeminiAPI,
+		APIKey:      "test-key",
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create genai client: %v", err)
+	}
+	client := &GeminiClient{model: ModelGemini25Flash, genaiClient: genaiClient, logger: logging.New()}
+
+	format := structuredoutput.NewEnumFormat([]string{"positive", "negative"})
+	if _, err := client.Generate(context.Background(), "Classify: great product", interfaces.WithResponseFormat(*format)); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+
+	config, _ := reqBody["generationConfig"].(map[string]interface{})
+	schema, _ := config["responseSchema"].(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	value, _ := properties[structuredoutput.EnumField].(map[string]interface{})
+	if enum := value["enum"]; !reflect.DeepEqual(enum, []interface{}{"positive", "negative"}) {
+		t.Errorf("Expected the enum in the response schema sent, got %v", reqBody["generationConfig"])
+	}
+}
+
+func TestConvertResponseSchemaEnum(t *testing.T) {
+	for _, enum := range []interface{}{[]interface{}{"a", "b"}, []string{"a", "b"}} {
+		converted := convertResponseSchema(map[string]interface{}{"type": "string", "enum": enum})
+		if !reflect.DeepEqual(converted.Enum, []string{"a", "b"}) {
+			t.Errorf("Expected enum [a b] from %#v, got %v", enum, converted.Enum)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package gemini
+
+import (
+	"fmt"
+	"mime"
+	"path"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// validateInputs checks the images and files attached to a request
+func (c *GeminiClient) validateInputs(params *interfaces.GenerateOptions) error {
+	if err := c.validateImages(params.Images); err != nil {
+		return err
+	}
+	return interfaces.ValidateFiles(params.Files)
+}
+
+// validateImages checks the images of a request against the capabilities of the model
+func (c *GeminiClient) validateImages(images []interfaces.ImageInput) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	capabilities := GetModelCapabilities(c.model)
+	if !capabilities.SupportsVision {
+		return fmt.Errorf("model %s does not support image input", c.model)
+	}
+
+	if err := interfaces.ValidateImages(images); err != nil {
+		return err
+	}
+	for n, image := range images {
+		mimeType := imageMIMEType(image)
+		if mimeType == "" {
+			return fmt.Errorf("invalid image %d: MIME type required for %s", n+1, image.URL)
+		}
+		if !containsMIMEType(capabilities.SupportedMimeTypes, mimeType) {
+			return fmt.Errorf("invalid image %d: model %s does not support %s", n+1, c.model, mimeType)
+		}
+	}
+	return nil
+}
+
+// promptParts returns the parts of a prompt with the images and uploaded files of the request attached
+func promptParts(prompt string, images []interfaces.ImageInput, files []interfaces.FileInput) []*genai.Part {
+	parts := []*genai.Part{{Text: prompt}}
+	for _, image := range images {
+		if len(image.Data) > 0 {
+			parts = append(parts, &genai.Part{InlineData: &genai.Blob{Data: image.Data, MIMEType: image.MIMEType}})
+		} else {
+			parts = append(parts, &genai.Part{FileData: &genai.FileData{FileURI: image.URL, MIMEType: imageMIMEType(image)}})
+		}
+	}
+	return append(parts, fileParts(files)...)
+}
+
+// imageMIMEType returns the MIME type of an image, guessed from the extension of its URL if not set
+func imageMIMEType(image interfaces.ImageInput) string {
+	if image.MIMEType != "" || image.URL == "" {
+		return image.MIMEType
+	}
+	return mime.TypeByExtension(path.Ext(image.URL))
+}
+
+func containsMIMEType(mimeTypes []string, mimeType string) bool {
+	for _, supported := range mimeTypes {
+		if supported == mimeType {
+			return true
+		}
+	}
+	return false
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
@@ -124,14 +123,19 @@ type ChatRequest struct {
 	Model     string        `json:"model"`
 	Messages  []ChatMessage `json:"messages"`
 	Stream    bool          `json:"stream"`
+	Tools     []Tool        `json:"tools,omitempty"`
 	Options   *Options      `json:"options,omitempty"`
 	Format    string        `json:"format,omitempty"`
 	KeepAlive string        `json:"keep_alive,omitempty"`
 }
 
 type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	Thinking  string     `json:"thinking,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+	Images    []string   `json:"images,omitempty"`
 }
 
 type ChatResponse struct {
@@ -160,6 +164,16 @@ func (c *OllamaClient) Generate(ctx context.Context, prompt string, options ...i
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("Ollama", params.Files); err != nil {
+		return "", err
+	}
+	if err := validateImages(params.Images); err != nil {
+		return "", err
+	}
+	if err := llm.RejectThinkingBudget("Ollama", params.LLMConfig); err != nil {
+		return "", err
+	}
+
 	// Create request
 	req := GenerateRequest{
 		Model:  c.Model,
@@ -171,6 +185,7 @@ func (c *OllamaClient) Generate(ctx context.Context, prompt string, options ...i
 			Stop:        params.LLMConfig.StopSequences,
 		},
 		System: params.SystemMessage,
+		Images: encodeImages(params.Images),
 	}
 
 	// Handle structured output if provided
@@ -198,7 +213,7 @@ Ensure your response is a valid JSON object that strictly follows the schema abo
 	}
 
 	// Make request
-	resp, err := c.makeRequest(ctx, "/api/generate", req)
+	resp, err := c.makeRequest(ctx, "/api/generate", req, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate text: %w", err)
 	}
@@ -211,31 +226,6 @@ Ensure your response is a valid JSON object that strictly follows the schema abo
 	return generateResp.Response, nil
 }
 
-// GenerateWithTools generates text and can use tools
-func (c *OllamaClient) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
-	// For now, Ollama doesn't support tool calling in the same way as OpenAI/Anthropic
-	// We'll implement a basic version that includes tool descriptions in the prompt
-	if len(tools) == 0 {
-		return c.Generate(ctx, prompt, options...)
-	}
-
-	// Build tool descriptions
-	var toolDescriptions []string
-	for _, tool := range tools {
-		toolDescriptions = append(toolDescriptions, fmt.Sprintf("- %s: %s", tool.Name(), tool.Description()))
-	}
-
-	// Create enhanced prompt with tool information
-	enhancedPrompt := fmt.Sprintf(`%s
-
-Available tools:
-%s
-
-Please respond to the user's request. If you need to use any tools, describe what you would do.`, prompt, strings.Join(toolDescriptions, "\n"))
-
-	return c.Generate(ctx, enhancedPrompt, options...)
-}
-
 // Chat performs a chat completion with messages
 func (c *OllamaClient) Chat(ctx context.Context, messages []llm.Message, params *llm.GenerateParams) (string, error) {
 	// Convert messages to Ollama format
@@ -260,7 +250,7 @@ func (c *OllamaClient) Chat(ctx context.Context, messages []llm.Message, params
 	}
 
 	// Make request
-	resp, err := c.makeRequest(ctx, "/api/chat", req)
+	resp, err := c.makeRequest(ctx, "/api/chat", req, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to chat: %w", err)
 	}
@@ -278,13 +268,34 @@ func (c *OllamaClient) Name() string {
 	return "ollama"
 }
 
-// SupportsStreaming returns false as streaming is not yet implemented for Ollama
+// SupportsStreaming returns true as Ollama streams chat responses
 func (c *OllamaClient) SupportsStreaming() bool {
-	return false
+	return true
+}
+
+// makeRequest makes an HTTP request to the Ollama API, bypassing the retry policy or bounding its
+// attempts as set by the options of the call, if any
+func (c *OllamaClient) makeRequest(ctx context.Context, endpoint string, payload interface{}, params *interfaces.GenerateOptions) ([]byte, error) {
+	resp, err := c.doRequest(ctx, endpoint, payload, params)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return body, nil
 }
 
-// makeRequest makes an HTTP request to the Ollama API
-func (c *OllamaClient) makeRequest(ctx context.Context, endpoint string, payload interface{}) ([]byte, error) {
+// doRequest sends an HTTP request to the Ollama API and returns the response if it succeeded.
+// The caller must close the response body.
+func (c *OllamaClient) doRequest(ctx context.Context, endpoint string, payload interface{}, params *interfaces.GenerateOptions) (*http.Response, error) {
 	// Marshal payload
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -301,8 +312,12 @@ func (c *OllamaClient) makeRequest(ctx context.Context, endpoint string, payload
 
 	// Execute request with retry if configured
 	var resp *http.Response
-	if c.retryExecutor != nil {
-		err = c.retryExecutor.Execute(ctx, func() error {
+	if c.retryExecutor != nil && (params == nil || !params.NoRetry) {
+		maxAttempts := int32(0)
+		if params != nil {
+			maxAttempts = int32(params.MaxRetries)
+		}
+		err = c.retryExecutor.ExecuteWithMaxAttempts(ctx, maxAttempts, func() error {
 			var execErr error
 			resp, execErr = c.HTTPClient.Do(req)
 			return execErr
@@ -314,29 +329,20 @@ func (c *OllamaClient) makeRequest(ctx context.Context, endpoint string, payload
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
-	defer func() {
-		if resp != nil {
-			_ = resp.Body.Close()
-		}
-	}()
-
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
 		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	return body, nil
+	return resp, nil
 }
 
 // ListModels lists available models
 func (c *OllamaClient) ListModels(ctx context.Context) ([]string, error) {
-	resp, err := c.makeRequest(ctx, "/api/tags", nil)
+	resp, err := c.makeRequest(ctx, "/api/tags", nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list models: %w", err)
 	}
@@ -367,7 +373,7 @@ func (c *OllamaClient) PullModel(ctx context.Context, modelName string) error {
 		Name: modelName,
 	}
 
-	_, err := c.makeRequest(ctx, "/api/pull", req)
+	_, err := c.makeRequest(ctx, "/api/pull", req, nil)
 	if err != nil {
 		return fmt.Errorf("failed to pull model %s: %w", modelName, err)
 	}
@@ -83,6 +83,70 @@ func TestGenerate(t *testing.T) {
 	assert.Equal(t, "This is a test response", response)
 }
 
+func TestGenerateRejectsFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent for a prompt with files")
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithModel("test-model"),
+		WithBaseURL(server.URL),
+	)
+	files := interfaces.WithFiles([]interfaces.FileInput{{URI: "files/report", MIMEType: "application/pdf"}})
+
+	_, err := client.Generate(context.Background(), "Summarize the report", files)
+	assert.Error(t, err)
+	_, err = client.GenerateStream(context.Background(), "Summarize the report", files)
+	assert.Error(t, err)
+}
+
+func TestGenerateRejectsThinkingBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent with a thinking budget")
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithModel("qwen3"),
+		WithBaseURL(server.URL),
+	)
+	budget := interfaces.WithThinkingBudget(2048)
+
+	_, err := client.Generate(context.Background(), "Count to ten", budget)
+	assert.EqualError(t, err, "Ollama does not support thinking budgets")
+	_, err = client.GenerateWithTools(context.Background(), "Count to ten", nil, budget)
+	assert.Error(t, err)
+	_, err = client.GenerateStream(context.Background(), "Count to ten", budget)
+	assert.Error(t, err)
+	_, err = client.GenerateWithToolsStream(context.Background(), "Count to ten", nil, budget)
+	assert.Error(t, err)
+}
+
+func TestGenerateWithImages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []string{"cG5n"}, req.Images)
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(GenerateResponse{Model: "llava", Response: "A cat", Done: true}))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithModel("llava"), WithBaseURL(server.URL))
+
+	response, err := client.Generate(context.Background(), "Describe the image",
+		interfaces.WithImages([]interfaces.ImageInput{{Data: []byte("png"), MIMEType: "image/png"}}))
+	require.NoError(t, err)
+	assert.Equal(t, "A cat", response)
+
+	// Images referenced by URL cannot be sent
+	_, err = client.Generate(context.Background(), "Describe the image",
+		interfaces.WithImages([]interfaces.ImageInput{{URL: "https://example.com/cat.png"}}))
+	assert.ErrorContains(t, err, "does not support image URLs")
+}
+
 func TestGenerateWithSystemMessage(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req GenerateRequest
@@ -172,19 +236,26 @@ func TestChat(t *testing.T) {
 }
 
 func TestGenerateWithTools(t *testing.T) {
+	var requests []ChatRequest
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var req GenerateRequest
+		assert.Equal(t, "/api/chat", r.URL.Path)
+
+		var req ChatRequest
 		err := json.NewDecoder(r.Body).Decode(&req)
 		require.NoError(t, err)
-
-		// Check that the prompt includes tool descriptions
-		assert.Contains(t, req.Prompt, "Available tools:")
-		assert.Contains(t, req.Prompt, "- test-tool: A test tool")
-
-		response := GenerateResponse{
-			Model:    "test-model",
-			Response: "I can help you with that using the available tools",
-			Done:     true,
+		requests = append(requests, req)
+
+		response := ChatResponse{Model: "test-model", Done: true}
+		if len(requests) == 1 {
+			response.Message = ChatMessage{
+				Role: "assistant",
+				ToolCalls: []ToolCall{{Function: ToolCallFunction{
+					Name:      "test-tool",
+					Arguments: json.RawMessage(`{"input":"hello"}`),
+				}}},
+			}
+		} else {
+			response.Message = ChatMessage{Role: "assistant", Content: "The tool says: mock result"}
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -210,10 +281,201 @@ func TestGenerateWithTools(t *testing.T) {
 		context.Background(),
 		"Help me with something",
 		tools,
+		WithSystemMessage("You are helpful"),
+		WithTemperature(0.2),
 	)
 
 	require.NoError(t, err)
-	assert.Equal(t, "I can help you with that using the available tools", response)
+	assert.Equal(t, "The tool says: mock result", response)
+	assert.Equal(t, []string{`{"input":"hello"}`}, mockTool.calls)
+
+	require.Len(t, requests, 2)
+	first := requests[0]
+	assert.False(t, first.Stream)
+	assert.Equal(t, 0.2, first.Options.Temperature)
+	assert.Equal(t, []ChatMessage{{Role: "system", Content: "You are helpful"}, {Role: "user", Content: "Help me with something"}}, first.Messages)
+	require.Len(t, first.Tools, 1)
+	assert.Equal(t, "test-tool", first.Tools[0].Function.Name)
+	assert.Equal(t, []interface{}{"input"}, first.Tools[0].Function.Parameters["required"])
+
+	// The tool result is fed back to the model
+	messages := requests[1].Messages
+	require.Len(t, messages, 4)
+	assert.Equal(t, "test-tool", messages[2].ToolCalls[0].Function.Name)
+	assert.Equal(t, ChatMessage{Role: "tool", Content: "mock result", ToolName: "test-tool"}, messages[3])
+}
+
+func TestGenerateWithToolsUnsupportedModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/chat" {
+			http.Error(w, `{"error":"registry.ollama.ai/library/gemma:2b does not support tools"}`, http.StatusBadRequest)
+			return
+		}
+
+		var req GenerateRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		// The tools are described in the prompt instead
+		assert.Contains(t, req.Prompt, "Available tools:")
+		assert.Contains(t, req.Prompt, "- test-tool: A test tool")
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(GenerateResponse{Response: "I would use test-tool", Done: true})
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithModel("gemma:2b"), WithBaseURL(server.URL))
+	response, err := client.GenerateWithTools(context.Background(), "Help me", []interfaces.Tool{&mockTool{name: "test-tool", description: "A test tool"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "I would use test-tool", response)
+}
+
+func TestGenerateWithToolsStream(t *testing.T) {
+	var requests []ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		requests = append(requests, req)
+
+		chunks := []string{
+			`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"test-tool","arguments":{"input":"hello"}}}]},"done":false}`,
+			`{"message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":20,"eval_count":5}`,
+		}
+		if len(requests) > 1 {
+			chunks = []string{
+				`{"message":{"role":"assistant","content":"The tool "},"done":false}`,
+				`{"message":{"role":"assistant","content":"says hi"},"done":false}`,
+				`{"message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":30,"eval_count":4}`,
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, chunk := range chunks {
+			_, _ = w.Write([]byte(chunk + "\n"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithModel("test-model"), WithBaseURL(server.URL))
+	assert.True(t, client.SupportsStreaming())
+
+	tool := &mockTool{name: "test-tool", description: "A test tool"}
+	events, err := client.GenerateWithToolsStream(context.Background(), "Help me", []interfaces.Tool{tool})
+	require.NoError(t, err)
+
+	var types []interfaces.StreamEventType
+	var content string
+	var usage *interfaces.TokenUsage
+	for event := range events {
+		require.NoError(t, event.Error)
+		types = append(types, event.Type)
+		switch event.Type {
+		case interfaces.StreamEventContentDelta:
+			content += event.Content
+		case interfaces.StreamEventToolUse:
+			assert.Equal(t, "test-tool", event.ToolCall.Name)
+			assert.Equal(t, `{"input":"hello"}`, event.ToolCall.Arguments)
+		case interfaces.StreamEventToolResult:
+			assert.Equal(t, "mock result", event.Metadata["result"])
+		case interfaces.StreamEventUsage:
+			usage = event.Usage
+		}
+	}
+
+	assert.Equal(t, "The tool says hi", content)
+	assert.Equal(t, []interfaces.StreamEventType{
+		interfaces.StreamEventMessageStart,
+		interfaces.StreamEventToolUse,
+		interfaces.StreamEventToolResult,
+		interfaces.StreamEventContentDelta,
+		interfaces.StreamEventContentDelta,
+		interfaces.StreamEventContentComplete,
+		interfaces.StreamEventUsage,
+		interfaces.StreamEventMessageStop,
+	}, types)
+	assert.Equal(t, &interfaces.TokenUsage{InputTokens: 50, OutputTokens: 9}, usage)
+
+	require.Len(t, requests, 2)
+	assert.True(t, requests[0].Stream)
+	assert.Equal(t, ChatMessage{Role: "tool", Content: "mock result", ToolName: "test-tool"}, requests[1].Messages[len(requests[1].Messages)-1])
+}
+
+func TestGenerateWithToolsStreamMaxIterations(t *testing.T) {
+	var requests []ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		requests = append(requests, req)
+
+		// The model keeps calling the tool while it can
+		chunk := `{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"test-tool","arguments":{}}}]},"done":true}`
+		if len(req.Tools) == 0 {
+			chunk = `{"message":{"role":"assistant","content":"Final answer"},"done":true}`
+		}
+		_, _ = w.Write([]byte(chunk + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithModel("test-model"), WithBaseURL(server.URL))
+	tool := &mockTool{name: "test-tool", description: "A test tool"}
+	events, err := client.GenerateWithToolsStream(context.Background(), "Help me", []interfaces.Tool{tool}, interfaces.WithMaxIterations(3))
+	require.NoError(t, err)
+
+	var content string
+	for event := range events {
+		require.NoError(t, event.Error)
+		if event.Type == interfaces.StreamEventContentDelta {
+			content += event.Content
+		}
+	}
+
+	assert.Equal(t, "Final answer", content)
+	assert.Len(t, tool.calls, 3)
+	require.Len(t, requests, 4)
+	assert.Empty(t, requests[3].Tools)
+}
+
+func TestGenerateStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+
+		var req ChatRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		assert.Empty(t, req.Tools)
+
+		for _, chunk := range []string{
+			`{"message":{"role":"assistant","thinking":"Let me think"},"done":false}`,
+			`{"message":{"role":"assistant","content":"Hello"},"done":false}`,
+			`{"message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":3,"eval_count":1}`,
+		} {
+			_, _ = w.Write([]byte(chunk + "\n"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithModel("test-model"), WithBaseURL(server.URL))
+	events, err := client.GenerateStream(context.Background(), "Hi")
+	require.NoError(t, err)
+
+	var content, thinking string
+	for event := range events {
+		require.NoError(t, event.Error)
+		switch event.Type {
+		case interfaces.StreamEventContentDelta:
+			content += event.Content
+		case interfaces.StreamEventThinking:
+			thinking += event.Content
+		}
+	}
+
+	assert.Equal(t, "Hello", content)
+	assert.Equal(t, "Let me think", thinking)
 }
 
 func TestListModels(t *testing.T) {
@@ -306,10 +568,11 @@ func TestName(t *testing.T) {
 	assert.Equal(t, "ollama", client.Name())
 }
 
-// Mock tool for testing
+// Mock tool for testing, recording the arguments it was executed with
 type mockTool struct {
 	name        string
 	description string
+	calls       []string
 }
 
 func (t *mockTool) Name() string {
@@ -343,6 +606,7 @@ func (t *mockTool) Parameters() map[string]interfaces.ParameterSpec {
 }
 
 func (t *mockTool) Execute(ctx context.Context, args string) (string, error) {
+	t.calls = append(t.calls, args)
 	return "mock result", nil
 }
 
@@ -0,0 +1,240 @@
+package ollama
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+// GenerateStream generates text from a prompt, streaming the response of the /api/chat endpoint
+func (c *OllamaClient) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	params := c.generateOptions(options)
+
+	if err := interfaces.RejectFiles("Ollama", params.Files); err != nil {
+		return nil, err
+	}
+	if err := validateImages(params.Images); err != nil {
+		return nil, err
+	}
+	if err := llm.RejectThinkingBudget("Ollama", params.LLMConfig); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream, such as while the model loads
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Re-request the stream if it fails midway
+	if params.StreamRetry > 0 {
+		return llm.ResumeStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamRetry(0))...)
+		})
+	}
+
+	return c.streamChat(ctx, prompt, nil, params)
+}
+
+// GenerateWithToolsStream generates text and can use tools, streaming the response of the
+// /api/chat endpoint. Tool calls are executed as the model requests them, each reported by a
+// StreamEventToolUse event followed by a StreamEventToolResult event with the output.
+func (c *OllamaClient) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	params := c.generateOptions(options)
+
+	if err := interfaces.RejectFiles("Ollama", params.Files); err != nil {
+		return nil, err
+	}
+	if err := validateImages(params.Images); err != nil {
+		return nil, err
+	}
+	if err := llm.RejectThinkingBudget("Ollama", params.LLMConfig); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream, such as long tool calls
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateWithToolsStream(ctx, prompt, tools, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
+	return c.streamChat(ctx, prompt, tools, params)
+}
+
+// streamChat streams a chat request, executing the tool calls requested by the model
+func (c *OllamaClient) streamChat(ctx context.Context, prompt string, tools []interfaces.Tool, params *interfaces.GenerateOptions) (<-chan interfaces.StreamEvent, error) {
+	bufferSize := 100
+	if params.StreamConfig != nil && params.StreamConfig.BufferSize > 0 {
+		bufferSize = params.StreamConfig.BufferSize
+	}
+	eventChan := make(chan interfaces.StreamEvent, bufferSize)
+
+	req := c.chatRequest(chatMessages(prompt, params), params)
+	req.Tools = convertTools(tools)
+
+	go func() {
+		defer close(eventChan)
+
+		send := func(event interfaces.StreamEvent) bool {
+			event.Timestamp = time.Now()
+			select {
+			case eventChan <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		sendError := func(err error) {
+			send(interfaces.StreamEvent{Type: interfaces.StreamEventError, Error: err})
+		}
+
+		if !send(interfaces.StreamEvent{
+			Type:     interfaces.StreamEventMessageStart,
+			Metadata: map[string]interface{}{"model": c.Model, "tools": len(tools)},
+		}) {
+			return
+		}
+
+		var usage interfaces.TokenUsage
+		for iteration := 0; ; iteration++ {
+			// Once the maximum number of iterations is reached, ask for the final response without tools
+			if len(req.Tools) > 0 && iteration == maxIterations(params) {
+				c.logger.Info(ctx, "Maximum iterations reached, making final call without tools", map[string]interface{}{
+					"maxIterations": maxIterations(params),
+				})
+				req.Tools = nil
+				req.Messages = append(req.Messages, ChatMessage{Role: "user", Content: finalPrompt})
+			}
+
+			message, err := c.streamChatResponse(ctx, req, params, iteration, &usage, send)
+			if err != nil && iteration == 0 && len(req.Tools) > 0 && isToolsUnsupported(err) {
+				c.logger.Warn(ctx, "Model does not support tools, streaming without them", map[string]interface{}{
+					"model": c.Model,
+				})
+				req.Tools = nil
+				message, err = c.streamChatResponse(ctx, req, params, iteration, &usage, send)
+			}
+			if err != nil {
+				sendError(fmt.Errorf("ollama streaming error: %w", err))
+				return
+			}
+
+			// Tool calls are only executed if tools were offered
+			if len(message.ToolCalls) == 0 || len(req.Tools) == 0 {
+				break
+			}
+
+			req.Messages = append(req.Messages, *message)
+			for _, toolCall := range message.ToolCalls {
+				call := &interfaces.ToolCall{
+					Name:      toolCall.Function.Name,
+					Arguments: toolCall.Function.arguments(),
+				}
+				id, result := c.executeToolCall(ctx, toolCall, tools, params)
+				call.ID = id
+
+				if !send(interfaces.StreamEvent{Type: interfaces.StreamEventToolUse, ToolCall: call, Metadata: map[string]interface{}{"iteration": iteration + 1}}) ||
+					!send(interfaces.StreamEvent{Type: interfaces.StreamEventToolResult, ToolCall: call, Metadata: map[string]interface{}{"iteration": iteration + 1, "result": result}}) {
+					return
+				}
+				req.Messages = append(req.Messages, ChatMessage{Role: "tool", Content: result, ToolName: toolCall.Function.Name})
+			}
+		}
+
+		if !send(interfaces.StreamEvent{Type: interfaces.StreamEventContentComplete}) {
+			return
+		}
+		if usage != (interfaces.TokenUsage{}) {
+			if !send(interfaces.StreamEvent{Type: interfaces.StreamEventUsage, Usage: &usage}) {
+				return
+			}
+		}
+		send(interfaces.StreamEvent{Type: interfaces.StreamEventMessageStop})
+	}()
+
+	return eventChan, nil
+}
+
+// streamChatResponse streams the response to a chat request, sending content and thinking
+// deltas, and returns the complete message. The token usage of the response is added to usage.
+func (c *OllamaClient) streamChatResponse(ctx context.Context, req ChatRequest, params *interfaces.GenerateOptions, iteration int, usage *interfaces.TokenUsage, send func(interfaces.StreamEvent) bool) (*ChatMessage, error) {
+	req.Stream = true
+	resp, err := c.doRequest(ctx, "/api/chat", req, params)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	message := &ChatMessage{Role: "assistant"}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk struct {
+			ChatResponse
+			Error string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal chat response: %w", err)
+		}
+		if chunk.Error != "" {
+			return nil, errors.New(chunk.Error)
+		}
+
+		if chunk.Message.Thinking != "" {
+			message.Thinking += chunk.Message.Thinking
+			if !send(interfaces.StreamEvent{Type: interfaces.StreamEventThinking, Content: chunk.Message.Thinking}) {
+				return nil, ctx.Err()
+			}
+		}
+		if chunk.Message.Content != "" {
+			message.Content += chunk.Message.Content
+			if !send(interfaces.StreamEvent{
+				Type:     interfaces.StreamEventContentDelta,
+				Content:  chunk.Message.Content,
+				Metadata: map[string]interface{}{"iteration": iteration + 1},
+			}) {
+				return nil, ctx.Err()
+			}
+		}
+		message.ToolCalls = append(message.ToolCalls, chunk.Message.ToolCalls...)
+
+		if chunk.Done {
+			usage.InputTokens += int64(chunk.PromptEvalCount)
+			usage.OutputTokens += int64(chunk.EvalCount)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	return message, nil
+}
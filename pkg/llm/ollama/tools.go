@@ -0,0 +1,342 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/google/uuid"
+)
+
+// Tool is a tool definition of a chat request
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes the function of a tool
+type ToolFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// ToolCall is a tool call requested by the model
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction is the function called by a tool call, with its arguments as a JSON object
+type ToolCallFunction struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// arguments returns the arguments of the tool call as a JSON string
+func (f ToolCallFunction) arguments() string {
+	if len(f.Arguments) == 0 || string(f.Arguments) == "null" {
+		return "{}"
+	}
+	return string(f.Arguments)
+}
+
+// finalPrompt asks the model for its final response once the maximum number of iterations is reached
+const finalPrompt = "Please provide your final response based on the information available. Do not request any additional tools."
+
+// GenerateWithTools generates text and can use tools, with the native tool calling of the
+// /api/chat endpoint. Models without tool support get the tool descriptions in the prompt instead.
+func (c *OllamaClient) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	if len(tools) == 0 {
+		return c.Generate(ctx, prompt, options...)
+	}
+
+	params := c.generateOptions(options)
+
+	if err := interfaces.RejectFiles("Ollama", params.Files); err != nil {
+		return "", err
+	}
+	if err := validateImages(params.Images); err != nil {
+		return "", err
+	}
+	if err := llm.RejectThinkingBudget("Ollama", params.LLMConfig); err != nil {
+		return "", err
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
+	messages := chatMessages(prompt, params)
+	req := c.chatRequest(messages, params)
+	req.Tools = convertTools(tools)
+
+	for iteration := 0; iteration < maxIterations(params); iteration++ {
+		c.logger.Debug(ctx, "Sending Ollama chat request with tools", map[string]interface{}{
+			"model":     c.Model,
+			"tools":     len(tools),
+			"iteration": iteration + 1,
+		})
+
+		resp, err := c.chat(ctx, req, params)
+		if err != nil {
+			if iteration == 0 && isToolsUnsupported(err) {
+				c.logger.Warn(ctx, "Model does not support tools, describing them in the prompt", map[string]interface{}{
+					"model": c.Model,
+				})
+				return c.generateWithToolDescriptions(ctx, prompt, tools, options...)
+			}
+			return "", fmt.Errorf("failed to generate text: %w", err)
+		}
+
+		if len(resp.Message.ToolCalls) == 0 {
+			return resp.Message.Content, nil
+		}
+
+		req.Messages = append(req.Messages, resp.Message)
+		for _, toolCall := range resp.Message.ToolCalls {
+			_, result := c.executeToolCall(ctx, toolCall, tools, params)
+			req.Messages = append(req.Messages, ChatMessage{Role: "tool", Content: result, ToolName: toolCall.Function.Name})
+		}
+	}
+
+	// Final call without tools to get synthesis
+	c.logger.Info(ctx, "Maximum iterations reached, making final call without tools", map[string]interface{}{
+		"maxIterations": maxIterations(params),
+	})
+
+	req.Tools = nil
+	req.Messages = append(req.Messages, ChatMessage{Role: "user", Content: finalPrompt})
+	resp, err := c.chat(ctx, req, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate final response: %w", err)
+	}
+
+	return resp.Message.Content, nil
+}
+
+// generateWithToolDescriptions generates text with the tool descriptions in the prompt, for
+// models without native tool support
+func (c *OllamaClient) generateWithToolDescriptions(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	// Build tool descriptions
+	var toolDescriptions []string
+	for _, tool := range tools {
+		toolDescriptions = append(toolDescriptions, fmt.Sprintf("- %s: %s", tool.Name(), tool.Description()))
+	}
+
+	// Create enhanced prompt with tool information
+	enhancedPrompt := fmt.Sprintf(`%s
+
+Available tools:
+%s
+
+Please respond to the user's request. If you need to use any tools, describe what you would do.`, prompt, strings.Join(toolDescriptions, "\n"))
+
+	return c.Generate(ctx, enhancedPrompt, options...)
+}
+
+// generateOptions applies the generate options over the defaults
+func (c *OllamaClient) generateOptions(options []interfaces.GenerateOption) *interfaces.GenerateOptions {
+	params := &interfaces.GenerateOptions{
+		LLMConfig: &interfaces.LLMConfig{
+			Temperature: 0.7,
+		},
+	}
+
+	for _, option := range options {
+		option(params)
+	}
+
+	if params.LLMConfig == nil {
+		params.LLMConfig = &interfaces.LLMConfig{Temperature: 0.7}
+	}
+
+	return params
+}
+
+// chatRequest creates a chat request with the sampling options and response format
+func (c *OllamaClient) chatRequest(messages []ChatMessage, params *interfaces.GenerateOptions) ChatRequest {
+	req := ChatRequest{
+		Model:    c.Model,
+		Messages: messages,
+		Options: &Options{
+			Temperature: params.LLMConfig.Temperature,
+			TopP:        params.LLMConfig.TopP,
+			Stop:        params.LLMConfig.StopSequences,
+		},
+	}
+
+	if params.ResponseFormat != nil && params.ResponseFormat.Type == interfaces.ResponseFormatJSON {
+		req.Format = "json"
+	}
+
+	return req
+}
+
+// chat sends a non-streaming chat request
+func (c *OllamaClient) chat(ctx context.Context, req ChatRequest, params *interfaces.GenerateOptions) (*ChatResponse, error) {
+	req.Stream = false
+	body, err := c.makeRequest(ctx, "/api/chat", req, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var chatResp ChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat response: %w", err)
+	}
+	return &chatResp, nil
+}
+
+// chatMessages returns the messages of a chat request for the prompt
+func chatMessages(prompt string, params *interfaces.GenerateOptions) []ChatMessage {
+	var messages []ChatMessage
+	if params.SystemMessage != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: params.SystemMessage})
+	}
+	return append(messages, ChatMessage{Role: "user", Content: prompt, Images: encodeImages(params.Images)})
+}
+
+// maxIterations returns the maximum number of tool calling iterations
+func maxIterations(params *interfaces.GenerateOptions) int {
+	if params.MaxIterations > 0 {
+		return params.MaxIterations
+	}
+	return 2
+}
+
+// isToolsUnsupported reports whether a request failed because the model does not support tools
+func isToolsUnsupported(err error) bool {
+	return strings.Contains(err.Error(), "does not support tools")
+}
+
+// executeToolCall executes a tool call requested by the model, returning the ID given to the
+// call and the result reported to the model. Failures are reported to the model as results.
+// The call and its result are stored in memory, if provided.
+func (c *OllamaClient) executeToolCall(ctx context.Context, toolCall ToolCall, tools []interfaces.Tool, params *interfaces.GenerateOptions) (string, string) {
+	id := "call_" + uuid.New().String()
+	name := toolCall.Function.Name
+	arguments := toolCall.Function.arguments()
+
+	var result string
+	var err error
+	tool := findTool(tools, name)
+	if tool == nil {
+		c.logger.Error(ctx, "Tool not found", map[string]interface{}{
+			"tool_name": name,
+		})
+		err = fmt.Errorf("tool not found: %s", name)
+	} else {
+		result, err = interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, id), tool, arguments)
+	}
+	if err != nil {
+		c.logger.Error(ctx, "Tool execution error", map[string]interface{}{
+			"tool_name": name,
+			"error":     err.Error(),
+		})
+		result = fmt.Sprintf("Error executing tool: %v", err)
+	}
+
+	if params.Memory != nil {
+		_ = params.Memory.AddMessage(ctx, interfaces.Message{
+			Role:      "assistant",
+			ToolCalls: []interfaces.ToolCall{{ID: id, Name: name, Arguments: arguments}},
+		})
+		_ = params.Memory.AddMessage(ctx, interfaces.Message{
+			Role:       "tool",
+			Content:    result,
+			ToolCallID: id,
+			Metadata:   map[string]interface{}{"tool_name": name},
+		})
+	}
+
+	return id, result
+}
+
+// findTool returns the tool with the given name, or nil
+func findTool(tools []interfaces.Tool, name string) interfaces.Tool {
+	for _, tool := range tools {
+		if tool.Name() == name {
+			return tool
+		}
+	}
+	return nil
+}
+
+// convertTools converts tools to Ollama tool definitions
+func convertTools(tools []interfaces.Tool) []Tool {
+	converted := make([]Tool, len(tools))
+	for i, tool := range tools {
+		properties := make(map[string]interface{})
+		required := []string{}
+		for name, param := range tool.Parameters() {
+			properties[name] = convertParameter(param)
+			if param.Required {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+
+		converted[i] = Tool{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters: map[string]interface{}{
+					"type":       "object",
+					"properties": properties,
+					"required":   required,
+				},
+			},
+		}
+	}
+	return converted
+}
+
+// convertParameter converts a parameter to JSON schema, including nested array items and object properties
+func convertParameter(param interfaces.ParameterSpec) map[string]interface{} {
+	property := map[string]interface{}{
+		"type": param.SchemaType(),
+	}
+
+	if param.Description != "" {
+		property["description"] = param.Description
+	}
+	if param.Default != nil {
+		property["default"] = param.Default
+	}
+	if param.Items != nil {
+		property["items"] = convertParameter(*param.Items)
+	}
+	if param.Enum != nil {
+		property["enum"] = param.Enum
+	}
+	if param.Properties != nil {
+		properties := make(map[string]interface{}, len(param.Properties))
+		required := []string{}
+		for name, nested := range param.Properties {
+			properties[name] = convertParameter(nested)
+			if nested.Required {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		property["properties"] = properties
+		property["required"] = required
+	}
+
+	return property
+}
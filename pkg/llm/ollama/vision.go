@@ -0,0 +1,34 @@
+package ollama
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// validateImages checks the images of a request. Ollama takes the image data inline, images
+// referenced by URL are rejected rather than sent without the image.
+func validateImages(images []interfaces.ImageInput) error {
+	if err := interfaces.ValidateImages(images); err != nil {
+		return err
+	}
+	for n, image := range images {
+		if image.URL != "" {
+			return fmt.Errorf("invalid image %d: Ollama does not support image URLs, provide the image data", n+1)
+		}
+	}
+	return nil
+}
+
+// encodeImages returns the base64 encoded data of the images, as Ollama expects them
+func encodeImages(images []interfaces.ImageInput) []string {
+	if len(images) == 0 {
+		return nil
+	}
+	encoded := make([]string, len(images))
+	for i, image := range images {
+		encoded[i] = base64.StdEncoding.EncodeToString(image.Data)
+	}
+	return encoded
+}
@@ -3,6 +3,7 @@ package openai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -35,6 +36,8 @@ type OpenAIClient struct {
 	baseURL         string
 	logger          logging.Logger
 	retryExecutor   *retry.Executor
+
+	completionLogLevel string
 }
 
 // Option represents an option for configuring the OpenAI client
@@ -64,6 +67,50 @@ func isReasoningModel(model string) bool {
 	return false
 }
 
+// reasoningEffortLevels are the reasoning effort levels supported by reasoning models
+var reasoningEffortLevels = []string{"minimal", "low", "medium", "high"}
+
+// validateReasoningEffort returns an error if the reasoning effort of config is not a supported level
+func validateReasoningEffort(config *interfaces.LLMConfig) error {
+	if config == nil || config.ReasoningEffort == "" {
+		return nil
+	}
+	for _, level := range reasoningEffortLevels {
+		if config.ReasoningEffort == level {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid reasoning effort %q: must be one of %s", config.ReasoningEffort, strings.Join(reasoningEffortLevels, ", "))
+}
+
+// validateThinkingBudget returns an error if config sets a thinking budget, which the OpenAI API
+// does not support: reasoning models are bounded with a reasoning effort instead
+func validateThinkingBudget(config *interfaces.LLMConfig) error {
+	if err := llm.RejectThinkingBudget("OpenAI", config); err != nil {
+		return fmt.Errorf("%w, use WithReasoningEffort to bound the reasoning of reasoning models", err)
+	}
+	return nil
+}
+
+// maxStopSequences is the maximum number of stop sequences accepted by the OpenAI API
+const maxStopSequences = 4
+
+// validateStopSequences returns an error if the stop sequences of config would be rejected by the OpenAI API
+func validateStopSequences(config *interfaces.LLMConfig) error {
+	if config == nil {
+		return nil
+	}
+	return llm.ValidateStopSequences("OpenAI", config.StopSequences, maxStopSequences)
+}
+
+// setReasoningEffort sets the reasoning effort of a request to a reasoning model. Other models
+// don't support it, so it is ignored for them.
+func (c *OpenAIClient) setReasoningEffort(req *openai.ChatCompletionNewParams, config *interfaces.LLMConfig) {
+	if config != nil && config.ReasoningEffort != "" && isReasoningModel(c.Model) {
+		req.ReasoningEffort = openai.ReasoningEffort(config.ReasoningEffort)
+	}
+}
+
 // getTemperatureForModel returns the appropriate temperature for a model
 func (c *OpenAIClient) getTemperatureForModel(requestedTemp float64) float64 {
 	if isReasoningModel(c.Model) {
@@ -94,14 +141,22 @@ func WithRetry(opts ...retry.Option) Option {
 	}
 }
 
+// WithCompletionLogLevel sets the level of the summary logged after every API call
+// ("debug", "info", "warn", "error" or "none" to disable it; default: "info")
+func WithCompletionLogLevel(level string) Option {
+	return func(c *OpenAIClient) {
+		c.completionLogLevel = level
+	}
+}
+
 // WithBaseURL sets the base URL for the OpenAI client
 func WithBaseURL(baseURL string) Option {
 	return func(c *OpenAIClient) {
 		c.baseURL = baseURL
 		// Recreate the client and services with the new base URL
-		c.Client = openai.NewClient(option.WithAPIKey(c.apiKey), option.WithBaseURL(baseURL))
-		c.ChatService = openai.NewChatService(option.WithAPIKey(c.apiKey), option.WithBaseURL(baseURL))
-		c.ResponseService = openai.NewClient(option.WithAPIKey(c.apiKey), option.WithBaseURL(baseURL))
+		c.Client = openai.NewClient(clientOptions(c.apiKey, baseURL)...)
+		c.ChatService = openai.NewChatService(clientOptions(c.apiKey, baseURL)...)
+		c.ResponseService = openai.NewClient(clientOptions(c.apiKey, baseURL)...)
 	}
 }
 
@@ -109,9 +164,9 @@ func WithBaseURL(baseURL string) Option {
 func NewClient(apiKey string, options ...Option) *OpenAIClient {
 	// Create client with default options
 	client := &OpenAIClient{
-		Client:          openai.NewClient(option.WithAPIKey(apiKey), option.WithBaseURL("https://api.openai.com/v1")),
-		ChatService:     openai.NewChatService(option.WithAPIKey(apiKey), option.WithBaseURL("https://api.openai.com/v1")),
-		ResponseService: openai.NewClient(option.WithAPIKey(apiKey), option.WithBaseURL("https://api.openai.com/v1")),
+		Client:          openai.NewClient(clientOptions(apiKey, "https://api.openai.com/v1")...),
+		ChatService:     openai.NewChatService(clientOptions(apiKey, "https://api.openai.com/v1")...),
+		ResponseService: openai.NewClient(clientOptions(apiKey, "https://api.openai.com/v1")...),
 		Model:           "gpt-4o-mini",
 		apiKey:          apiKey,
 		baseURL:         "https://api.openai.com/v1",
@@ -139,6 +194,29 @@ func (c *OpenAIClient) Generate(ctx context.Context, prompt string, options ...i
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("OpenAI", params.Files); err != nil {
+		return "", err
+	}
+
+	if err := c.validateImages(params.Images); err != nil {
+		return "", err
+	}
+	if err := validateReasoningEffort(params.LLMConfig); err != nil {
+		return "", err
+	}
+	if err := validateThinkingBudget(params.LLMConfig); err != nil {
+		return "", err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return "", err
+	}
+
+	if params.OutputRepair > 0 && params.ResponseFormat != nil {
+		return llm.RepairStructuredOutput(ctx, params, prompt, func(ctx context.Context, prompt string) (string, error) {
+			return c.Generate(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStructuredOutputRepair(0))...)
+		})
+	}
+
 	// Get organization ID from context if available
 	orgID, _ := multitenancy.GetOrgID(ctx)
 	if orgID != "" {
@@ -193,7 +271,7 @@ func (c *OpenAIClient) Generate(ctx context.Context, prompt string, options ...i
 	}
 
 	// Add user message
-	messages = append(messages, openai.UserMessage(prompt))
+	messages = append(messages, userMessage(prompt, params.Images))
 
 	// Create request
 	req := openai.ChatCompletionNewParams{
@@ -213,6 +291,7 @@ func (c *OpenAIClient) Generate(ctx context.Context, prompt string, options ...i
 			req.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: params.LLMConfig.StopSequences}
 		}
 	}
+	c.setReasoningEffort(&req, params.LLMConfig)
 
 	// Set response format if provided
 	if params.ResponseFormat != nil {
@@ -221,6 +300,9 @@ func (c *OpenAIClient) Generate(ctx context.Context, prompt string, options ...i
 			Name:   params.ResponseFormat.Name,
 			Schema: params.ResponseFormat.Schema,
 		}
+		if params.ResponseFormat.Strict {
+			jsonSchema.Strict = openai.Bool(true)
+		}
 
 		req.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
@@ -239,7 +321,16 @@ func (c *OpenAIClient) Generate(ctx context.Context, prompt string, options ...i
 	var resp *openai.ChatCompletion
 	var err error
 
+	// A bounded call is retried by the retry policy of the client only, not by the SDK as well
+	reqOptions := requestOptions(params)
+	if c.retryExecutor != nil && !params.NoRetry && params.MaxRetries > 0 {
+		reqOptions = []option.RequestOption{option.WithMaxRetries(0)}
+	}
+
+	start := time.Now()
+	attempts := 0
 	operation := func() error {
+		attempts++
 		var reasoningMode string
 		if params.LLMConfig != nil && params.LLMConfig.Reasoning != "" {
 			reasoningMode = params.LLMConfig.Reasoning
@@ -259,25 +350,26 @@ func (c *OpenAIClient) Generate(ctx context.Context, prompt string, options ...i
 			"reasoning":         reasoningMode,
 		})
 
-		resp, err = c.ChatService.Completions.New(ctx, req)
+		resp, err = c.ChatService.Completions.New(ctx, req, reqOptions...)
 		if err != nil {
 			c.logger.Error(ctx, "Error from OpenAI API", map[string]interface{}{
 				"error": err.Error(),
 				"model": c.Model,
 			})
-			return fmt.Errorf("failed to generate text: %w", err)
+			return classifyError(fmt.Errorf("failed to generate text: %w", err))
 		}
 		return nil
 	}
 
-	if c.retryExecutor != nil {
+	if c.retryExecutor != nil && !params.NoRetry {
 		c.logger.Debug(ctx, "Using retry mechanism for OpenAI request", map[string]interface{}{
 			"model": c.Model,
 		})
-		err = c.retryExecutor.Execute(ctx, operation)
+		err = c.retryExecutor.ExecuteWithMaxAttempts(ctx, int32(params.MaxRetries), operation)
 	} else {
 		err = operation()
 	}
+	c.logCompletion(ctx, start, attempts, resp, err)
 
 	if err != nil {
 		return "", err
@@ -299,6 +391,9 @@ func (c *OpenAIClient) Chat(ctx context.Context, messages []llm.Message, params
 	if params == nil {
 		params = llm.DefaultGenerateParams()
 	}
+	if err := llm.ValidateStopSequences("OpenAI", params.StopSequences, maxStopSequences); err != nil {
+		return "", err
+	}
 
 	// Handle reasoning if specified
 	var systemMessage string
@@ -396,7 +491,10 @@ func (c *OpenAIClient) Chat(ctx context.Context, messages []llm.Message, params
 	var resp *openai.ChatCompletion
 	var err error
 
+	start := time.Now()
+	attempts := 0
 	operation := func() error {
+		attempts++
 		c.logger.Debug(ctx, "Executing OpenAI Chat API request", map[string]interface{}{
 			"model":             c.Model,
 			"temperature":       req.Temperature,
@@ -414,7 +512,7 @@ func (c *OpenAIClient) Chat(ctx context.Context, messages []llm.Message, params
 				"error": err.Error(),
 				"model": c.Model,
 			})
-			return fmt.Errorf("failed to create chat completion: %w", err)
+			return classifyError(fmt.Errorf("failed to create chat completion: %w", err))
 		}
 		return nil
 	}
@@ -427,6 +525,7 @@ func (c *OpenAIClient) Chat(ctx context.Context, messages []llm.Message, params
 	} else {
 		err = operation()
 	}
+	c.logCompletion(ctx, start, attempts, resp, err)
 
 	if err != nil {
 		return "", err
@@ -453,6 +552,37 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 		}
 	}
 
+	if err := interfaces.RejectFiles("OpenAI", params.Files); err != nil {
+		return "", err
+	}
+
+	if err := c.validateImages(params.Images); err != nil {
+		return "", err
+	}
+	if err := validateReasoningEffort(params.LLMConfig); err != nil {
+		return "", err
+	}
+	if err := validateThinkingBudget(params.LLMConfig); err != nil {
+		return "", err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return "", err
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
 	// Set default values only if they're not provided
 	if params.LLMConfig == nil {
 		params.LLMConfig = &interfaces.LLMConfig{
@@ -479,42 +609,10 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 	// Convert tools to OpenAI format
 	openaiTools := make([]openai.ChatCompletionToolUnionParam, len(tools))
 	for i, tool := range tools {
-		// Convert ParameterSpec to JSON Schema
-		properties := make(map[string]interface{})
-		required := []string{}
-
-		for name, param := range tool.Parameters() {
-			properties[name] = map[string]interface{}{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				properties[name].(map[string]interface{})["default"] = param.Default
-			}
-			if param.Required {
-				required = append(required, name)
-			}
-			if param.Items != nil {
-				properties[name].(map[string]interface{})["items"] = map[string]interface{}{
-					"type": param.Items.Type,
-				}
-				if param.Items.Enum != nil {
-					properties[name].(map[string]interface{})["items"].(map[string]interface{})["enum"] = param.Items.Enum
-				}
-			}
-			if param.Enum != nil {
-				properties[name].(map[string]interface{})["enum"] = param.Enum
-			}
-		}
-
 		openaiTools[i] = openai.ChatCompletionFunctionTool(shared.FunctionDefinitionParam{
 			Name:        tool.Name(),
 			Description: openai.String(tool.Description()),
-			Parameters: map[string]interface{}{
-				"type":       "object",
-				"properties": properties,
-				"required":   required,
-			},
+			Parameters:  c.convertToOpenAISchema(tool.Parameters()),
 		})
 	}
 
@@ -570,7 +668,7 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 	}
 
 	// Add user message
-	messages = append(messages, openai.UserMessage(prompt))
+	messages = append(messages, userMessage(prompt, params.Images))
 
 	req := openai.ChatCompletionNewParams{
 		Model:            openai.ChatModel(c.Model),
@@ -592,13 +690,11 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 	}
 
 	// Set reasoning model specific parameters
+	c.setReasoningEffort(&req, params.LLMConfig)
 	if isReasoningModel(c.Model) {
 		if params.LLMConfig.MaxCompletionTokens > 0 {
 			req.MaxCompletionTokens = openai.Int(int64(params.LLMConfig.MaxCompletionTokens))
 		}
-		if params.LLMConfig.ReasoningEffort != "" {
-			req.ReasoningEffort = openai.ReasoningEffort(params.LLMConfig.ReasoningEffort)
-		}
 		if params.LLMConfig.Verbosity != "" {
 			req.Verbosity = openai.ChatCompletionNewParamsVerbosity(params.LLMConfig.Verbosity)
 		}
@@ -615,6 +711,9 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 			Name:   params.ResponseFormat.Name,
 			Schema: params.ResponseFormat.Schema,
 		}
+		if params.ResponseFormat.Strict {
+			jsonSchema.Strict = openai.Bool(true)
+		}
 
 		req.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
@@ -629,6 +728,7 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 	for iteration := 0; iteration < maxIterations; iteration++ {
 		// Update request with current messages
 		req.Messages = messages
+		req.ToolChoice = toolChoiceParam(params.ToolChoiceForIteration(iteration))
 
 		// Send request
 		var reasoningMode string
@@ -653,7 +753,9 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 			"iteration":         iteration + 1,
 			"maxIterations":     maxIterations,
 		})
-		resp, err := c.ChatService.Completions.New(ctx, req)
+		start := time.Now()
+		resp, err := c.ChatService.Completions.New(ctx, req, requestOptions(params)...)
+		c.logCompletion(ctx, start, 1, resp, err)
 		if err != nil {
 			c.logger.Error(ctx, "Error from OpenAI API", map[string]interface{}{"error": err.Error()})
 			return "", fmt.Errorf("failed to create chat completion: %w", err)
@@ -702,12 +804,15 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 				}
 
 				type toolResult struct {
-					index  int
-					result string
-					err    error
+					arguments string
+					result    string
+					err       error
+					executed  bool
 				}
 
-				resultCh := make(chan toolResult, len(toolUsesWrapper.ToolUses))
+				// Tools run concurrently; each stores its result at the index of its call, so the
+				// results are reassembled in the order of the calls whatever order they finish in
+				toolsResults := make([]toolResult, len(toolUsesWrapper.ToolUses))
 				var wg sync.WaitGroup
 
 				// Launch goroutines for concurrent tool execution
@@ -725,7 +830,7 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 						paramsBytes, err := json.Marshal(parameters)
 						if err != nil {
 							c.logger.Error(ctx, "Error marshalling parameters", map[string]interface{}{"error": err.Error()})
-							resultCh <- toolResult{index: index, result: "", err: err}
+							toolsResults[index] = toolResult{err: err}
 							return
 						}
 
@@ -741,13 +846,13 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 						if tool == nil {
 							err := fmt.Errorf("tool not found: %s", toolName)
 							c.logger.Error(ctx, "Tool not found in parallel execution", map[string]interface{}{"toolName": toolName})
-							resultCh <- toolResult{index: index, result: "", err: err}
+							toolsResults[index] = toolResult{err: err}
 							return
 						}
 
 						c.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": toolName, "parameters": string(paramsBytes)})
 
-						result, err := tool.Execute(ctx, string(paramsBytes))
+						result, err := interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), tool, string(paramsBytes))
 
 						// Check for repetitive calls and add warning if needed
 						cacheKey := toolName + ":" + string(paramsBytes)
@@ -770,67 +875,49 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 							})
 						}
 
-						// Store tool call and result in memory if provided
-						if params.Memory != nil {
-							if err != nil {
-								// Store failed parallel tool call result
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:    "assistant",
-									Content: "",
-									ToolCalls: []interfaces.ToolCall{{
-										ID:        toolCall.ID,
-										Name:      toolName,
-										Arguments: string(paramsBytes),
-									}},
-								})
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:       "tool",
-									Content:    fmt.Sprintf("Error: %v", err),
-									ToolCallID: toolCall.ID,
-									Metadata: map[string]interface{}{
-										"tool_name": toolCall.Function.Name,
-									},
-								})
-							} else {
-								// Store successful parallel tool call and result
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:    "assistant",
-									Content: "",
-									ToolCalls: []interfaces.ToolCall{{
-										ID:        toolCall.ID,
-										Name:      toolName,
-										Arguments: string(paramsBytes),
-									}},
-								})
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:       "tool",
-									Content:    result,
-									ToolCallID: toolCall.ID,
-									Metadata: map[string]interface{}{
-										"tool_name": toolCall.Function.Name,
-									},
-								})
-							}
+						toolsResults[index] = toolResult{arguments: string(paramsBytes), result: result, err: err, executed: true}
+					}(i, toolUse)
+				}
+				wg.Wait()
+
+				// Store the executed tool calls and their results in memory, in the order of the calls
+				if params.Memory != nil {
+					for i, toolUse := range toolUsesWrapper.ToolUses {
+						executed := toolsResults[i]
+						if !executed.executed {
+							continue
 						}
 
-						resultCh <- toolResult{index: index, result: result, err: err}
-					}(i, toolUse)
+						content := executed.result
+						if executed.err != nil {
+							content = fmt.Sprintf("Error: %v", executed.err)
+						}
+						_ = params.Memory.AddMessage(ctx, interfaces.Message{
+							Role:    "assistant",
+							Content: "",
+							ToolCalls: []interfaces.ToolCall{{
+								ID:        toolCall.ID,
+								Name:      toolUse["recipient_name"].(string),
+								Arguments: executed.arguments,
+							}},
+						})
+						_ = params.Memory.AddMessage(ctx, interfaces.Message{
+							Role:       "tool",
+							Content:    content,
+							ToolCallID: toolCall.ID,
+							Metadata: map[string]interface{}{
+								"tool_name": toolCall.Function.Name,
+							},
+						})
+					}
 				}
 
-				// Close channel when all goroutines complete
-				go func() {
-					wg.Wait()
-					close(resultCh)
-				}()
-
-				// Collect results and check for errors
-				toolsResults := make([]string, len(toolUsesWrapper.ToolUses))
-				for result := range resultCh {
-					if result.err != nil {
-						c.logger.Error(ctx, "Error executing tool", map[string]interface{}{"error": result.err.Error()})
-						return "", fmt.Errorf("error executing tool: %s", result.err.Error())
+				// Report the error of the first failed call
+				for _, executed := range toolsResults {
+					if executed.err != nil {
+						c.logger.Error(ctx, "Error executing tool", map[string]interface{}{"error": executed.err.Error()})
+						return "", fmt.Errorf("error executing tool: %s", executed.err.Error())
 					}
-					toolsResults[result.index] = result.result
 				}
 
 				// For parallel tool use, we need to create a tool message
@@ -839,7 +926,7 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 				var structuredResults []string
 				for i, toolUse := range toolUsesWrapper.ToolUses {
 					toolName := toolUse["recipient_name"].(string)
-					result := toolsResults[i]
+					result := toolsResults[i].result
 					structuredResults = append(structuredResults, fmt.Sprintf("Tool: %s\nResult: %s", toolName, result))
 				}
 				messages = append(messages, openai.ToolMessage(strings.Join(structuredResults, "\n\n"), toolCall.ID))
@@ -910,7 +997,7 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 			// Execute the tool
 			c.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": selectedTool.Name()})
 			toolStartTime := time.Now()
-			toolResult, err := selectedTool.Execute(ctx, toolCall.Function.Arguments)
+			toolResult, err := interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), selectedTool, toolCall.Function.Arguments)
 			toolEndTime := time.Now()
 
 			// Check for repetitive calls and add warning if needed
@@ -1025,26 +1112,22 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 	}
 
 	// Set reasoning model specific parameters for final call
+	c.setReasoningEffort(&finalReq, params.LLMConfig)
 	if isReasoningModel(c.Model) {
 		if params.LLMConfig.MaxCompletionTokens > 0 {
 			finalReq.MaxCompletionTokens = openai.Int(int64(params.LLMConfig.MaxCompletionTokens))
 		}
-		if params.LLMConfig.ReasoningEffort != "" {
-			finalReq.ReasoningEffort = openai.ReasoningEffort(params.LLMConfig.ReasoningEffort)
-		}
 		if params.LLMConfig.Verbosity != "" {
 			finalReq.Verbosity = openai.ChatCompletionNewParamsVerbosity(params.LLMConfig.Verbosity)
 		}
 	}
 
 	// Set reasoning model specific parameters for final call
+	c.setReasoningEffort(&finalReq, params.LLMConfig)
 	if isReasoningModel(c.Model) {
 		if params.LLMConfig.MaxCompletionTokens > 0 {
 			finalReq.MaxCompletionTokens = openai.Int(int64(params.LLMConfig.MaxCompletionTokens))
 		}
-		if params.LLMConfig.ReasoningEffort != "" {
-			finalReq.ReasoningEffort = openai.ReasoningEffort(params.LLMConfig.ReasoningEffort)
-		}
 		if params.LLMConfig.Verbosity != "" {
 			finalReq.Verbosity = openai.ChatCompletionNewParamsVerbosity(params.LLMConfig.Verbosity)
 		}
@@ -1065,6 +1148,9 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 			Name:   params.ResponseFormat.Name,
 			Schema: params.ResponseFormat.Schema,
 		}
+		if params.ResponseFormat.Strict {
+			jsonSchema.Strict = openai.Bool(true)
+		}
 
 		finalReq.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
@@ -1082,7 +1168,9 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 		"messages": len(finalReq.Messages),
 	})
 
-	finalResp, err := c.ChatService.Completions.New(ctx, finalReq)
+	start := time.Now()
+	finalResp, err := c.ChatService.Completions.New(ctx, finalReq, requestOptions(params)...)
+	c.logCompletion(ctx, start, 1, finalResp, err)
 	if err != nil {
 		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
 		return "", fmt.Errorf("failed to create final chat completion: %w", err)
@@ -1097,6 +1185,50 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 	return content, nil
 }
 
+// requestOptions returns the per-request options of a call. The SDK retries failed requests
+// on its own, so a call made with interfaces.WithNoRetry disables those retries as well, and
+// one made with interfaces.WithMaxRetries bounds them to the same number of attempts.
+func requestOptions(params *interfaces.GenerateOptions) []option.RequestOption {
+	switch {
+	case params.NoRetry:
+		return []option.RequestOption{option.WithMaxRetries(0)}
+	case params.MaxRetries > 0:
+		return []option.RequestOption{option.WithMaxRetries(params.MaxRetries - 1)}
+	}
+	return nil
+}
+
+// classifyError marks client errors of the API as permanent, so that they are neither retried
+// nor counted as failures by the circuit breaker
+func classifyError(err error) error {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) && retry.IsClientError(apiErr.StatusCode) {
+		return retry.Permanent(err)
+	}
+	return err
+}
+
+// logCompletion logs the completion summary of a chat completion request
+func (c *OpenAIClient) logCompletion(ctx context.Context, start time.Time, attempts int, resp *openai.ChatCompletion, err error) {
+	summary := llm.CompletionSummary{
+		Provider: c.Name(),
+		Model:    c.Model,
+		Latency:  time.Since(start),
+		Err:      err,
+	}
+	if attempts > 1 {
+		summary.Retries = attempts - 1
+	}
+	if resp != nil {
+		summary.PromptTokens = resp.Usage.PromptTokens
+		summary.ResponseTokens = resp.Usage.CompletionTokens
+		if len(resp.Choices) > 0 && resp.Choices[0].FinishReason != "" {
+			summary.FinishReason = llm.ChatFinishReason(resp.Choices[0].FinishReason)
+		}
+	}
+	llm.LogCompletion(ctx, c.logger, c.completionLogLevel, summary)
+}
+
 // Name implements interfaces.LLM.Name
 func (c *OpenAIClient) Name() string {
 	return "openai"
@@ -1183,7 +1315,9 @@ func WithMaxCompletionTokens(maxTokens int) interfaces.GenerateOption {
 	}
 }
 
-// WithReasoningEffort creates a GenerateOption to set the reasoning effort for GPT-5 models
+// WithReasoningEffort creates a GenerateOption to set the reasoning effort of reasoning models
+// (o1, o3, o4 and GPT-5): "minimal", "low", "medium" or "high". It is ignored for other models,
+// and generating with any other level returns an error.
 func WithReasoningEffort(effort string) interfaces.GenerateOption {
 	return func(options *interfaces.GenerateOptions) {
 		if options.LLMConfig == nil {
@@ -3,15 +3,22 @@ package openai_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	openai_client "github.com/Ingenimax/agent-sdk-go/pkg/llm/openai"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
 	"github.com/openai/openai-go/v2"
 	"github.com/openai/openai-go/v2/option"
 )
@@ -230,6 +237,53 @@ func TestGenerateWithResponseFormat(t *testing.T) {
 	}
 }
 
+func TestGenerateWithStrictResponseFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		// The enum is sent in a strict json_schema
+		responseFormat, _ := reqBody["response_format"].(map[string]interface{})
+		jsonSchema, _ := responseFormat["json_schema"].(map[string]interface{})
+		if jsonSchema["strict"] != true {
+			t.Errorf("Expected a strict json_schema, got %v", responseFormat)
+		}
+		if !strings.Contains(fmt.Sprint(jsonSchema["schema"]), "enum:[positive negative]") {
+			t.Errorf("Expected the enum in the schema, got %v", jsonSchema["schema"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Content: `{"value": "positive"}`, Role: "assistant"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4o"), openai_client.WithLogger(logging.New()))
+	client.ChatService = openai.NewChatService(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	format := interfaces.ResponseFormat{
+		Type: interfaces.ResponseFormatJSON,
+		Name: "Enum",
+		Schema: interfaces.JSONSchema{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"value": map[string]interface{}{"type": "string", "enum": []string{"positive", "negative"}},
+			},
+			"required":             []string{"value"},
+			"additionalProperties": false,
+		},
+		Strict: true,
+	}
+	if _, err := client.Generate(context.Background(), "I love it", openai_client.WithResponseFormat(format)); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+}
+
 func TestChatWithToolMessages(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -478,3 +532,1422 @@ func (m *mockTool) Execute(ctx context.Context, args string) (string, error) {
 func (m *mockTool) Run(ctx context.Context, input string) (string, error) {
 	return m.Execute(ctx, input)
 }
+
+// nestedParamsTool is a tool with a nested object parameter
+type nestedParamsTool struct {
+	mockTool
+}
+
+func (m *nestedParamsTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"address": {
+			Type:        "object",
+			Description: "Shipping address",
+			Required:    true,
+			Properties: map[string]interfaces.ParameterSpec{
+				"city": {Type: "string", Description: "City name", Required: true},
+				"geo": {
+					Type: "object",
+					Properties: map[string]interfaces.ParameterSpec{
+						"lat": {Type: "number", Required: true},
+					},
+				},
+			},
+		},
+		"items": {
+			Type: "array",
+			Items: &interfaces.ParameterSpec{
+				Type: "object",
+				Properties: map[string]interfaces.ParameterSpec{
+					"sku": {Type: "string", Required: true},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateWithToolsNestedObjectSchema(t *testing.T) {
+	var parameters map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Tools []struct {
+				Function struct {
+					Parameters map[string]interface{} `json:"parameters"`
+				} `json:"function"`
+			} `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if len(reqBody.Tools) == 1 {
+			parameters = reqBody.Tools[0].Function.Parameters
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Content: "done", Role: "assistant"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	tool := &nestedParamsTool{mockTool{name: "ship", description: "Ship an order"}}
+	if _, err := client.GenerateWithTools(context.Background(), "ship it", []interfaces.Tool{tool}); err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+
+	data, _ := json.Marshal(parameters)
+	var schema struct {
+		Properties struct {
+			Address struct {
+				Type       string   `json:"type"`
+				Required   []string `json:"required"`
+				Properties struct {
+					City struct {
+						Type string `json:"type"`
+					} `json:"city"`
+					Geo struct {
+						Type       string   `json:"type"`
+						Required   []string `json:"required"`
+						Properties map[string]struct {
+							Type string `json:"type"`
+						} `json:"properties"`
+					} `json:"geo"`
+				} `json:"properties"`
+			} `json:"address"`
+			Items struct {
+				Items struct {
+					Type       string                     `json:"type"`
+					Properties map[string]json.RawMessage `json:"properties"`
+				} `json:"items"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Failed to parse tool schema: %v", err)
+	}
+
+	address := schema.Properties.Address
+	if address.Type != "object" || address.Properties.City.Type != "string" {
+		t.Errorf("Expected nested address properties, got %s", data)
+	}
+	if len(address.Required) != 1 || address.Required[0] != "city" {
+		t.Errorf("Expected address to require city, got %v", address.Required)
+	}
+	if address.Properties.Geo.Type != "object" || address.Properties.Geo.Properties["lat"].Type != "number" {
+		t.Errorf("Expected doubly nested geo properties, got %s", data)
+	}
+	if items := schema.Properties.Items.Items; items.Type != "object" || items.Properties["sku"] == nil {
+		t.Errorf("Expected object array items with properties, got %s", data)
+	}
+}
+
+// keyValueListTool is a tool taking an array of name/value objects
+type keyValueListTool struct {
+	mockTool
+}
+
+func (m *keyValueListTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"headers": {
+			Type:        "array",
+			Description: "Headers to set",
+			Required:    true,
+			Items: &interfaces.ParameterSpec{
+				Type: "object",
+				Properties: map[string]interfaces.ParameterSpec{
+					"value": {Type: "string", Description: "Header value", Required: true},
+					"name":  {Type: "string", Description: "Header name", Required: true},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateWithToolsArrayOfObjectsSchema(t *testing.T) {
+	var parameters json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Tools []struct {
+				Function struct {
+					Parameters json.RawMessage `json:"parameters"`
+				} `json:"function"`
+			} `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if len(reqBody.Tools) == 1 {
+			parameters = reqBody.Tools[0].Function.Parameters
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Content: "done", Role: "assistant"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	tool := &keyValueListTool{mockTool{name: "set_headers", description: "Set request headers"}}
+	if _, err := client.GenerateWithTools(context.Background(), "set them", []interfaces.Tool{tool}); err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties struct {
+			Headers struct {
+				Type  string `json:"type"`
+				Items struct {
+					Type       string   `json:"type"`
+					Required   []string `json:"required"`
+					Properties map[string]struct {
+						Type        string `json:"type"`
+						Description string `json:"description"`
+					} `json:"properties"`
+				} `json:"items"`
+			} `json:"headers"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(parameters, &schema); err != nil {
+		t.Fatalf("Failed to parse tool schema: %v", err)
+	}
+
+	headers := schema.Properties.Headers
+	if headers.Type != "array" || headers.Items.Type != "object" {
+		t.Errorf("Expected an array of objects, got %s", parameters)
+	}
+	if headers.Items.Properties["name"].Type != "string" || headers.Items.Properties["value"].Description != "Header value" {
+		t.Errorf("Expected name/value item properties, got %s", parameters)
+	}
+	if !reflect.DeepEqual(headers.Items.Required, []string{"name", "value"}) {
+		t.Errorf("Expected items to require name and value, got %v", headers.Items.Required)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"headers"}) {
+		t.Errorf("Expected headers to be required, got %v", schema.Required)
+	}
+}
+
+// logEntry is a log call captured by recordingLogger
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// recordingLogger captures log calls for assertions
+type recordingLogger struct {
+	entries []logEntry
+}
+
+func (l *recordingLogger) record(level, msg string, fields map[string]interface{}) {
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: fields})
+}
+
+func (l *recordingLogger) Info(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("info", msg, fields)
+}
+
+func (l *recordingLogger) Warn(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("warn", msg, fields)
+}
+
+func (l *recordingLogger) Error(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("error", msg, fields)
+}
+
+func (l *recordingLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.record("debug", msg, fields)
+}
+
+// completions returns the completion summary entries
+func (l *recordingLogger) completions() []logEntry {
+	var entries []logEntry
+	for _, entry := range l.entries {
+		if entry.msg == "LLM call completed" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestGenerateLogsCompletionSummary(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":{"message":"temporary failure"}}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"model": "gpt-4",
+			"choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "test response"}}],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 5, "total_tokens": 17}
+		}`))
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4"),
+		openai_client.WithLogger(logger),
+		openai_client.WithRetry(retry.WithMaxAttempts(2), retry.WithInitialInterval(time.Millisecond)),
+	)
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+
+	if _, err := client.Generate(context.Background(), "test prompt"); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+
+	entries := logger.completions()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 completion summary, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.level != "info" {
+		t.Errorf("Expected summary at info level, got %s", entry.level)
+	}
+	expected := map[string]interface{}{
+		"provider":        "openai",
+		"model":           "gpt-4",
+		"prompt_tokens":   int64(12),
+		"response_tokens": int64(5),
+		"total_tokens":    int64(17),
+		"retries":         1,
+		"status":          "success",
+		"finish_reason":   "stop",
+	}
+	for key, value := range expected {
+		if entry.fields[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, entry.fields[key])
+		}
+	}
+	if _, ok := entry.fields["latency_ms"].(int64); !ok {
+		t.Errorf("Expected latency_ms field, got %v", entry.fields["latency_ms"])
+	}
+
+	// The summary level is configurable and can be disabled
+	logger.entries = nil
+	openai_client.WithCompletionLogLevel("debug")(client)
+	if _, err := client.Generate(context.Background(), "test prompt"); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if entries := logger.completions(); len(entries) != 1 || entries[0].level != "debug" {
+		t.Errorf("Expected a debug completion summary, got %+v", entries)
+	}
+
+	logger.entries = nil
+	openai_client.WithCompletionLogLevel("none")(client)
+	if _, err := client.Generate(context.Background(), "test prompt"); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if entries := logger.completions(); len(entries) != 0 {
+		t.Errorf("Expected no completion summary, got %+v", entries)
+	}
+}
+
+func TestGenerateWithNoRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":{"message":"temporary failure"}}`))
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4"),
+		openai_client.WithRetry(retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond)),
+	)
+	// Keep the SDK retries enabled, they must be bypassed as well
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	if _, err := client.Generate(context.Background(), "test prompt", interfaces.WithNoRetry()); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+}
+
+func TestGenerateWithMaxRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":{"message":"temporary failure"}}`))
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4"),
+		openai_client.WithRetry(retry.WithMaxAttempts(3), retry.WithInitialInterval(time.Millisecond)),
+	)
+	// Keep the SDK retries enabled, they must not add attempts
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	if _, err := client.Generate(context.Background(), "test prompt", interfaces.WithMaxRetries(1)); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+
+	requests = 0
+	if _, err := client.Generate(context.Background(), "test prompt", interfaces.WithMaxRetries(2)); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestGenerateCircuitBreaker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":{"message":"temporary failure"}}`))
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4"),
+		openai_client.WithRetry(
+			retry.WithMaxAttempts(5),
+			retry.WithInitialInterval(time.Millisecond),
+			retry.WithCircuitBreaker(2, time.Hour),
+		),
+	)
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+
+	if _, err := client.Generate(context.Background(), "test prompt"); !errors.Is(err, retry.ErrCircuitOpen) {
+		t.Fatalf("Expected the circuit to open, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the retries to stop when the circuit opens, got %d requests", requests)
+	}
+
+	// While the circuit is open, requests fail without reaching the provider
+	requests = 0
+	if _, err := client.Generate(context.Background(), "test prompt"); !errors.Is(err, retry.ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request while the circuit is open, got %d", requests)
+	}
+}
+
+func TestGenerateClientErrorNotRetried(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"invalid request"}}`))
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4"),
+		openai_client.WithRetry(
+			retry.WithMaxAttempts(5),
+			retry.WithInitialInterval(time.Millisecond),
+			retry.WithCircuitBreaker(2, time.Hour),
+		),
+	)
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+
+	// Bad requests are neither retried nor counted as failures by the circuit breaker
+	for i := 0; i < 3; i++ {
+		if _, err := client.Generate(context.Background(), "test prompt"); err == nil || errors.Is(err, retry.ErrCircuitOpen) {
+			t.Fatalf("Expected the client error, got %v", err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected a single request per call, got %d requests", requests)
+	}
+}
+
+func TestGenerateStreamEmitsUsage(t *testing.T) {
+	var includeUsage interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if streamOptions, ok := reqBody["stream_options"].(map[string]interface{}); ok {
+			includeUsage = streamOptions["include_usage"]
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"id":"1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}`,
+			`{"id":"1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`,
+			`{"id":"1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`,
+		} {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	events, err := client.GenerateStream(context.Background(), "test prompt")
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+
+	var usage []interfaces.TokenUsage
+	for event := range events {
+		if event.Error != nil {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+		if event.Type == interfaces.StreamEventUsage {
+			usage = append(usage, *event.Usage)
+		}
+	}
+
+	if includeUsage != true {
+		t.Errorf("Expected stream_options.include_usage to be requested, got %v", includeUsage)
+	}
+	expected := []interfaces.TokenUsage{{InputTokens: 12, OutputTokens: 3}}
+	if !reflect.DeepEqual(usage, expected) {
+		t.Errorf("Expected usage events %v, got %v", expected, usage)
+	}
+}
+
+func TestGenerateStreamResumesAfterDisconnect(t *testing.T) {
+	chunk := func(content string) string {
+		return fmt.Sprintf(`data: {"id":"1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{"content":%q}}]}`+"\n\n", content)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+
+		if requests == 1 {
+			// Drop the connection in the middle of the stream
+			_, _ = fmt.Fprint(w, chunk("Once upon"), chunk(" a ti"))
+			flusher.Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Fatalf("Failed to hijack connection: %v", err)
+			}
+			_ = conn.Close()
+			return
+		}
+
+		_, _ = fmt.Fprint(w, chunk("Once upon a time"), chunk(", the end."), "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	events, err := client.GenerateStream(context.Background(), "Tell me a story", interfaces.WithStreamRetry(2))
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+
+	var content string
+	for event := range events {
+		if event.Error != nil {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+		if event.Type == interfaces.StreamEventContentDelta {
+			content += event.Content
+		}
+	}
+
+	if content != "Once upon a time, the end." {
+		t.Errorf("Expected the story without duplicates, got %q", content)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the stream to be re-requested once, got %d requests", requests)
+	}
+}
+
+// weatherTool is a tool requiring a location, recording the arguments it was executed with
+type weatherTool struct {
+	mockTool
+	calls []string
+}
+
+func (m *weatherTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"location": {Type: "string", Description: "City name", Required: true},
+		"unit":     {Type: "string", Description: "Temperature unit", Enum: []interface{}{"celsius", "fahrenheit"}},
+	}
+}
+
+func (m *weatherTool) Execute(ctx context.Context, args string) (string, error) {
+	m.calls = append(m.calls, args)
+	return "Sunny, 24 degrees", nil
+}
+
+func TestGenerateWithToolsValidationErrorResult(t *testing.T) {
+	toolCall := func(id, arguments string) openai.ChatCompletion {
+		return openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{{
+				Message: openai.ChatCompletionMessage{
+					Role: "assistant",
+					ToolCalls: []openai.ChatCompletionMessageToolCallUnion{{
+						ID:       id,
+						Type:     "function",
+						Function: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "get_weather", Arguments: arguments},
+					}},
+				},
+			}},
+		}
+	}
+
+	var toolResults []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		toolResults = toolResults[:0]
+		for _, message := range reqBody.Messages {
+			if message.Role == "tool" {
+				toolResults = append(toolResults, message.Content)
+			}
+		}
+
+		var response openai.ChatCompletion
+		switch len(toolResults) {
+		case 0:
+			// The model forgets the required location
+			response = toolCall("call_1", `{"unit":"celsius"}`)
+		case 1:
+			// The model corrects the call from the validation error
+			response = toolCall("call_2", `{"location":"Paris","unit":"celsius"}`)
+		default:
+			response = openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "It is sunny in Paris."}},
+			}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	tool := &weatherTool{mockTool: mockTool{name: "get_weather", description: "Get the weather"}}
+	resp, err := client.GenerateWithTools(context.Background(), "What's the weather?", []interfaces.Tool{tool},
+		interfaces.WithToolArgumentValidation(), interfaces.WithMaxIterations(3))
+	if err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+
+	if len(toolResults) != 2 || toolResults[0] != `{"error":"validation","field":"location","reason":"required"}` {
+		t.Fatalf("Expected a structured validation error as the first tool result, got %v", toolResults)
+	}
+	if validationErr, ok := interfaces.ParseToolValidationError(toolResults[0]); !ok || validationErr.Field != "location" {
+		t.Errorf("Expected the tool result to parse as a validation error, got %v", validationErr)
+	}
+	if !reflect.DeepEqual(tool.calls, []string{`{"location":"Paris","unit":"celsius"}`}) {
+		t.Errorf("Expected only the corrected call to be executed, got %v", tool.calls)
+	}
+	if resp != "It is sunny in Paris." {
+		t.Errorf("Unexpected response: %q", resp)
+	}
+}
+
+func TestGenerateWithToolsToolChoice(t *testing.T) {
+	var toolChoices []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			ToolChoice interface{} `json:"tool_choice"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		toolChoices = append(toolChoices, reqBody.ToolChoice)
+
+		response := openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "It is sunny in Paris."}},
+		}}
+		if len(toolChoices) == 1 {
+			response = openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{
+				Message: openai.ChatCompletionMessage{
+					Role: "assistant",
+					ToolCalls: []openai.ChatCompletionMessageToolCallUnion{{
+						ID:       "call_1",
+						Type:     "function",
+						Function: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "get_weather", Arguments: `{"location":"Paris"}`},
+					}},
+				},
+			}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	tool := &weatherTool{mockTool: mockTool{name: "get_weather", description: "Get the weather"}}
+	_, err := client.GenerateWithTools(context.Background(), "What's the weather?", []interfaces.Tool{tool},
+		interfaces.WithToolChoice(interfaces.RequireTool("get_weather")))
+	if err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "get_weather"}},
+		"auto",
+	}
+	if !reflect.DeepEqual(toolChoices, expected) {
+		t.Errorf("Expected the tool to be forced on the first request only, got %v", toolChoices)
+	}
+
+	toolChoices = nil
+	_, err = client.GenerateWithTools(context.Background(), "What's the weather?", []interfaces.Tool{tool},
+		interfaces.WithToolChoice(interfaces.ToolChoice{Mode: interfaces.ToolChoiceRequired}))
+	if err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+	if len(toolChoices) == 0 || toolChoices[0] != "required" {
+		t.Errorf("Expected a required tool choice, got %v", toolChoices)
+	}
+}
+
+// hangingTool blocks until released, ignoring the cancellation of its context
+type hangingTool struct {
+	mockTool
+	release chan struct{}
+}
+
+func (m *hangingTool) Execute(ctx context.Context, args string) (string, error) {
+	<-m.release
+	return "too late", nil
+}
+
+func TestGenerateWithToolsTimeout(t *testing.T) {
+	var toolResults []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		toolResults = toolResults[:0]
+		for _, message := range reqBody.Messages {
+			if message.Role == "tool" {
+				toolResults = append(toolResults, message.Content)
+			}
+		}
+
+		response := openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "The search timed out."}},
+		}}
+		if len(toolResults) == 0 {
+			response = openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{
+				Message: openai.ChatCompletionMessage{
+					Role: "assistant",
+					ToolCalls: []openai.ChatCompletionMessageToolCallUnion{{
+						ID:       "call_1",
+						Type:     "function",
+						Function: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "search", Arguments: `{"input":"news"}`},
+					}},
+				},
+			}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	tool := &hangingTool{mockTool: mockTool{name: "search", description: "Searches the web"}, release: make(chan struct{})}
+	defer close(tool.release)
+
+	start := time.Now()
+	resp, err := client.GenerateWithTools(context.Background(), "What's new?", []interfaces.Tool{tool},
+		interfaces.WithToolTimeout(50*time.Millisecond), interfaces.WithMaxIterations(2))
+	if err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected the hanging tool to be abandoned, took %v", elapsed)
+	}
+
+	if len(toolResults) != 1 || !strings.Contains(toolResults[0], "timed out") {
+		t.Fatalf("Expected a timeout error as the tool result, got %v", toolResults)
+	}
+	if resp != "The search timed out." {
+		t.Errorf("Unexpected response: %q", resp)
+	}
+}
+
+type delayedTool struct {
+	mockTool
+	delay time.Duration
+}
+
+func (m *delayedTool) Execute(ctx context.Context, args string) (string, error) {
+	time.Sleep(m.delay)
+	return "result of " + m.name, nil
+}
+
+func TestParallelToolResultsKeepCallOrder(t *testing.T) {
+	var toolMessage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		toolMessage = ""
+		for _, message := range reqBody.Messages {
+			if message.Role == "tool" {
+				toolMessage = message.Content
+			}
+		}
+
+		response := openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "All done."}},
+		}}
+		if toolMessage == "" {
+			response = openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{
+				Message: openai.ChatCompletionMessage{
+					Role: "assistant",
+					ToolCalls: []openai.ChatCompletionMessageToolCallUnion{{
+						ID:   "call_1",
+						Type: "function",
+						Function: openai.ChatCompletionMessageFunctionToolCallFunction{
+							Name: "parallel_tool_use",
+							Arguments: `{"tool_uses": [
+								{"recipient_name": "slow", "parameters": {"param": "a"}},
+								{"recipient_name": "medium", "parameters": {"param": "b"}},
+								{"recipient_name": "fast", "parameters": {"param": "c"}}
+							]}`,
+						},
+					}},
+				},
+			}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	// The tools finish in the reverse order of the calls
+	tools := []interfaces.Tool{
+		&delayedTool{mockTool: mockTool{name: "slow", description: "Slow tool"}, delay: 60 * time.Millisecond},
+		&delayedTool{mockTool: mockTool{name: "medium", description: "Medium tool"}, delay: 30 * time.Millisecond},
+		&delayedTool{mockTool: mockTool{name: "fast", description: "Fast tool"}, delay: 0},
+	}
+
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org-1"), "conv-1")
+	mem := memory.NewConversationBuffer()
+	resp, err := client.GenerateWithTools(ctx, "Run all tools", tools, interfaces.WithMemory(mem))
+	if err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+	if resp != "All done." {
+		t.Errorf("Unexpected response: %q", resp)
+	}
+
+	expected := "Tool: slow\nResult: result of slow\n\nTool: medium\nResult: result of medium\n\nTool: fast\nResult: result of fast"
+	if toolMessage != expected {
+		t.Errorf("Expected the tool results in call order, got %q", toolMessage)
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get messages: %v", err)
+	}
+	var called []string
+	for _, message := range messages {
+		for _, toolCall := range message.ToolCalls {
+			called = append(called, toolCall.Name)
+		}
+	}
+	if !reflect.DeepEqual(called, []string{"slow", "medium", "fast"}) {
+		t.Errorf("Expected the tool calls stored in call order, got %v", called)
+	}
+}
+
+func TestGenerateWithImages(t *testing.T) {
+	var content []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Messages []struct {
+				Content interface{} `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		content, _ = reqBody.Messages[len(reqBody.Messages)-1].Content.([]interface{})
+
+		w.Header().Set("Content-Type", "application/json")
+		response := openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Content: "a cat", Role: "assistant"}},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4o"),
+		openai_client.WithLogger(logging.New()),
+	)
+	client.Client = openai.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+	client.ChatService = openai.NewChatService(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	images := []interfaces.ImageInput{
+		{URL: "https://example.com/cat.jpg"},
+		{Data: []byte("png"), MIMEType: "image/png"},
+	}
+	resp, err := client.Generate(context.Background(), "What is in these images?", interfaces.WithImages(images))
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if resp != "a cat" {
+		t.Errorf("Expected response 'a cat', got '%s'", resp)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"type": "text", "text": "What is in these images?"},
+		map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "https://example.com/cat.jpg"}},
+		map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "data:image/png;base64,cG5n"}},
+	}
+	if !reflect.DeepEqual(content, expected) {
+		t.Errorf("Unexpected user message content: %#v", content)
+	}
+}
+
+func TestGenerateWithImagesUnsupportedModel(t *testing.T) {
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-3.5-turbo"),
+		openai_client.WithLogger(logging.New()),
+	)
+
+	images := []interfaces.ImageInput{{URL: "https://example.com/cat.jpg"}}
+	_, err := client.Generate(context.Background(), "What is in this image?", interfaces.WithImages(images))
+	if err == nil || !strings.Contains(err.Error(), "does not support image input") {
+		t.Errorf("Expected an unsupported model error, got %v", err)
+	}
+
+	client = openai_client.NewClient("test-key", openai_client.WithModel("gpt-4o"))
+	_, err = client.Generate(context.Background(), "What is in this image?", interfaces.WithImages([]interfaces.ImageInput{{Data: []byte("png")}}))
+	if err == nil || !strings.Contains(err.Error(), "requires a MIME type") {
+		t.Errorf("Expected an invalid image error, got %v", err)
+	}
+}
+
+// toolCallStreamServer streams a weather tool call split across chunks, then an answer
+func toolCallStreamServer(t *testing.T, requests *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		*requests = append(*requests, reqBody)
+
+		chunks := []string{
+			`{"choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+			`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"location\":"}}]}}]}`,
+			`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`,
+			`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		}
+		if len(*requests) > 1 {
+			chunks = []string{
+				`{"choices":[{"index":0,"delta":{"role":"assistant","content":"It is sunny in Paris."}}]}`,
+				`{"choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`,
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func TestGenerateWithToolsStreamAssemblesToolCalls(t *testing.T) {
+	var requests []map[string]interface{}
+	server := toolCallStreamServer(t, &requests)
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	tool := &weatherTool{mockTool: mockTool{name: "get_weather", description: "Get the weather"}}
+	events, err := client.GenerateWithToolsStream(context.Background(), "Weather in Paris?", []interfaces.Tool{tool})
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+
+	var partialArguments []string
+	var toolUses, toolResults []*interfaces.ToolCall
+	var results []interface{}
+	var content string
+	for event := range events {
+		switch event.Type {
+		case interfaces.StreamEventError:
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		case interfaces.StreamEventToolCallDelta:
+			partialArguments = append(partialArguments, event.ToolCall.Arguments)
+		case interfaces.StreamEventToolUse:
+			toolUses = append(toolUses, event.ToolCall)
+		case interfaces.StreamEventToolResult:
+			toolResults = append(toolResults, event.ToolCall)
+			results = append(results, event.Metadata["result"])
+		case interfaces.StreamEventContentDelta:
+			content += event.Content
+		}
+	}
+
+	expectedPartial := []string{`{"location":`, `{"location":"Paris"}`}
+	if !reflect.DeepEqual(partialArguments, expectedPartial) {
+		t.Errorf("Expected partial arguments %v, got %v", expectedPartial, partialArguments)
+	}
+	expectedCall := &interfaces.ToolCall{ID: "call_1", Name: "get_weather", Arguments: `{"location":"Paris"}`}
+	if len(toolUses) != 1 || !reflect.DeepEqual(toolUses[0], expectedCall) {
+		t.Errorf("Expected one tool use %+v, got %+v", expectedCall, toolUses)
+	}
+	if len(toolResults) != 1 || !reflect.DeepEqual(toolResults[0], expectedCall) || results[0] != "Sunny, 24 degrees" {
+		t.Errorf("Expected one tool result for %+v, got %+v with results %v", expectedCall, toolResults, results)
+	}
+	if !reflect.DeepEqual(tool.calls, []string{`{"location":"Paris"}`}) {
+		t.Errorf("Expected the tool to be executed with the assembled arguments, got %v", tool.calls)
+	}
+	if content != "It is sunny in Paris." {
+		t.Errorf("Expected the answer to be streamed once, got %q", content)
+	}
+
+	// The continuation is requested with the tool result and nothing more once the model answers
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	messages := requests[1]["messages"].([]interface{})
+	last := messages[len(messages)-1].(map[string]interface{})
+	if last["role"] != "tool" || last["tool_call_id"] != "call_1" || last["content"] != "Sunny, 24 degrees" {
+		t.Errorf("Expected the tool result to be fed back, got %v", last)
+	}
+}
+
+func TestGenerateWithToolsStreamMaxIterations(t *testing.T) {
+	var requests []map[string]interface{}
+	server := toolCallStreamServer(t, &requests)
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	tool := &weatherTool{mockTool: mockTool{name: "get_weather", description: "Get the weather"}}
+	events, err := client.GenerateWithToolsStream(context.Background(), "Weather in Paris?", []interfaces.Tool{tool}, interfaces.WithMaxIterations(1))
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+
+	var content string
+	for event := range events {
+		if event.Error != nil {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+		if event.Type == interfaces.StreamEventContentDelta {
+			content += event.Content
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	if _, ok := requests[1]["tools"]; ok {
+		t.Error("Expected the final request after the last iteration to be sent without tools")
+	}
+	if content != "It is sunny in Paris." {
+		t.Errorf("Expected the final answer, got %q", content)
+	}
+}
+
+func TestGenerateStructuredOutputRepair(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		prompts = append(prompts, reqBody.Messages[len(reqBody.Messages)-1].Content)
+
+		// The model keeps answering with prose
+		response := openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "Ada Lovelace was a mathematician."}},
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	format := interfaces.ResponseFormat{
+		Type:   interfaces.ResponseFormatJSON,
+		Name:   "Person",
+		Schema: interfaces.JSONSchema{"type": "object", "properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}}},
+	}
+	_, err := client.Generate(context.Background(), "Who is Ada?", openai_client.WithResponseFormat(format), interfaces.WithStructuredOutputRepair(2))
+	if err == nil || !strings.Contains(err.Error(), "after 2 repair attempts") {
+		t.Fatalf("Expected an error once the repair attempts are exhausted, got %v", err)
+	}
+
+	// The initial request and two corrective re-prompts
+	if len(prompts) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(prompts))
+	}
+	if prompts[0] != "Who is Ada?" {
+		t.Errorf("Expected the original prompt first, got %q", prompts[0])
+	}
+	for _, prompt := range prompts[1:] {
+		if !strings.HasPrefix(prompt, "Who is Ada?") || !strings.Contains(prompt, "Ada Lovelace was a mathematician.") {
+			t.Errorf("Expected a re-prompt with the invalid response, got %q", prompt)
+		}
+	}
+}
+
+func TestGenerateStreamFinishReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"id":"1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{"role":"assistant","content":"Once upon"}}]}`,
+			`{"id":"1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{},"finish_reason":"length"}]}`,
+		} {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	events, err := client.GenerateStream(context.Background(), "Tell me a story")
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+
+	var finishReason interfaces.FinishReason
+	for event := range events {
+		if event.Error != nil {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+		if event.Type == interfaces.StreamEventContentComplete {
+			finishReason = event.FinishReason
+		}
+	}
+
+	if finishReason != interfaces.FinishReasonLength {
+		t.Errorf("Expected the truncated response to finish with %q, got %q", interfaces.FinishReasonLength, finishReason)
+	}
+}
+
+// structuredWeatherTool is a weather tool returning a structured result
+type structuredWeatherTool struct {
+	weatherTool
+}
+
+func (m *structuredWeatherTool) ExecuteStructured(ctx context.Context, args string) (interfaces.ToolResult, error) {
+	m.calls = append(m.calls, args)
+	return interfaces.ToolResult{
+		Data:    map[string]interface{}{"condition": "sunny", "temperature": 24},
+		Display: "Sunny, 24 degrees",
+	}, nil
+}
+
+func TestGenerateWithToolsStructuredResult(t *testing.T) {
+	var toolResults []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		toolResults = toolResults[:0]
+		for _, message := range reqBody.Messages {
+			if message.Role == "tool" {
+				toolResults = append(toolResults, message.Content)
+			}
+		}
+
+		response := openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "It is sunny in Paris."}},
+		}}
+		if len(toolResults) == 0 {
+			response.Choices[0].Message = openai.ChatCompletionMessage{
+				Role: "assistant",
+				ToolCalls: []openai.ChatCompletionMessageToolCallUnion{{
+					ID:       "call_1",
+					Type:     "function",
+					Function: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "get_weather", Arguments: `{"location":"Paris"}`},
+				}},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	tool := &structuredWeatherTool{weatherTool{mockTool: mockTool{name: "get_weather", description: "Get the weather"}}}
+	if _, err := client.GenerateWithTools(context.Background(), "What's the weather?", []interfaces.Tool{tool}); err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+
+	// The structured data reaches the model as JSON, instead of the text result of Execute
+	if len(toolResults) != 1 || toolResults[0] != `{"condition":"sunny","temperature":24}` {
+		t.Errorf("Expected the structured result as the tool result, got %v", toolResults)
+	}
+}
+
+func TestGenerateWithReasoningEffort(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		requests = append(requests, reqBody)
+
+		w.Header().Set("Content-Type", "application/json")
+		response := openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Content: "42", Role: "assistant"}},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	newClient := func(model string) *openai_client.OpenAIClient {
+		client := openai_client.NewClient("test-key", openai_client.WithModel(model))
+		client.ChatService = openai.NewChatService(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+		return client
+	}
+
+	// Reasoning models receive the reasoning effort
+	if _, err := newClient("o3-mini").Generate(context.Background(), "What is 6x7?", openai_client.WithReasoningEffort("high")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if effort := requests[0]["reasoning_effort"]; effort != "high" {
+		t.Errorf("Expected reasoning_effort high, got %v", effort)
+	}
+
+	// Other models don't support it, so it is left out
+	if _, err := newClient("gpt-4o").Generate(context.Background(), "What is 6x7?", openai_client.WithReasoningEffort("high")); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if effort, ok := requests[1]["reasoning_effort"]; ok {
+		t.Errorf("Expected no reasoning_effort for a non-reasoning model, got %v", effort)
+	}
+
+	// Invalid levels are rejected before any request
+	_, err := newClient("o3-mini").Generate(context.Background(), "What is 6x7?", openai_client.WithReasoningEffort("extreme"))
+	if err == nil || !strings.Contains(err.Error(), "invalid reasoning effort") {
+		t.Errorf("Expected an invalid reasoning effort error, got %v", err)
+	}
+	_, err = newClient("o3-mini").GenerateStream(context.Background(), "What is 6x7?", openai_client.WithReasoningEffort("extreme"))
+	if err == nil || !strings.Contains(err.Error(), "invalid reasoning effort") {
+		t.Errorf("Expected an invalid reasoning effort error when streaming, got %v", err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected invalid levels not to be sent, got %d requests", len(requests))
+	}
+}
+
+func TestGenerateWithTooManyStopSequences(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4o"))
+	client.ChatService = openai.NewChatService(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	stopSequences := openai_client.WithStopSequences([]string{"a", "b", "c", "d", "e"})
+	_, err := client.Generate(context.Background(), "Count to ten", stopSequences)
+	if err == nil || err.Error() != "OpenAI accepts at most 4 stop sequences, got 5" {
+		t.Errorf("Expected a stop sequence limit error, got %v", err)
+	}
+	_, err = client.GenerateStream(context.Background(), "Count to ten", stopSequences)
+	if err == nil || err.Error() != "OpenAI accepts at most 4 stop sequences, got 5" {
+		t.Errorf("Expected a stop sequence limit error when streaming, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected the stop sequences to be rejected before any request, got %d requests", requests)
+	}
+}
+
+func TestGenerateRejectsThinkingBudget(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("o4-mini"))
+	client.ChatService = openai.NewChatService(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	budget := interfaces.WithThinkingBudget(2048)
+	tool := &weatherTool{mockTool: mockTool{name: "get_weather", description: "Get the weather"}}
+	if _, err := client.Generate(context.Background(), "Count to ten", budget); err == nil || !strings.Contains(err.Error(), "OpenAI does not support thinking budgets") {
+		t.Errorf("Expected a thinking budget error, got %v", err)
+	}
+	if _, err := client.GenerateWithTools(context.Background(), "What's the weather in Paris?", []interfaces.Tool{tool}, budget); err == nil {
+		t.Error("Expected a thinking budget error with tools")
+	}
+	if _, err := client.GenerateStream(context.Background(), "Count to ten", budget); err == nil {
+		t.Error("Expected a thinking budget error when streaming")
+	}
+	if _, err := client.GenerateWithToolsStream(context.Background(), "What's the weather in Paris?", []interfaces.Tool{tool}, budget); err == nil {
+		t.Error("Expected a thinking budget error when streaming with tools")
+	}
+	if requests != 0 {
+		t.Errorf("Expected the thinking budget to be rejected before any request, got %d requests", requests)
+	}
+}
+
+func TestGenerateWithToolsStreamReasoningEffort(t *testing.T) {
+	var requests []map[string]interface{}
+	server := toolCallStreamServer(t, &requests)
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("o4-mini"))
+	client.ChatService = openai.NewChatService(option.WithAPIKey("test-key"), option.WithBaseURL(server.URL))
+
+	tool := &weatherTool{mockTool: mockTool{name: "get_weather", description: "Get the weather"}}
+	events, err := client.GenerateWithToolsStream(context.Background(), "What's the weather in Paris?", []interfaces.Tool{tool}, openai_client.WithReasoningEffort("low"))
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+	for range events {
+	}
+
+	if len(requests) == 0 {
+		t.Fatal("Expected a request")
+	}
+	for i, request := range requests {
+		if effort := request["reasoning_effort"]; effort != "low" {
+			t.Errorf("Expected request %d to have reasoning_effort low, got %v", i, effort)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package openai
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/openai/openai-go/v2/option"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+// rateLimitMiddleware records the rate limits reported in the headers of every response in the
+// llm.RateLimitStatus of the request context
+func rateLimitMiddleware(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	resp, err := next(req)
+	if resp != nil {
+		if limits, ok := parseRateLimits(resp.Header, time.Now()); ok {
+			llm.RecordRateLimits(req.Context(), limits)
+		}
+	}
+	return resp, err
+}
+
+// clientOptions returns the options of the SDK clients of the OpenAI client
+func clientOptions(apiKey, baseURL string) []option.RequestOption {
+	return []option.RequestOption{
+		option.WithAPIKey(apiKey),
+		option.WithBaseURL(baseURL),
+		option.WithMiddleware(rateLimitMiddleware),
+	}
+}
+
+// parseRateLimits parses the x-ratelimit-* headers of a response. Resets are reported as
+// durations, e.g. "6m0s", and converted to times relative to now.
+func parseRateLimits(header http.Header, now time.Time) (llm.RateLimits, bool) {
+	requests, hasRequests := parseRateLimit(header, "requests", now)
+	tokens, hasTokens := parseRateLimit(header, "tokens", now)
+	if !hasRequests && !hasTokens {
+		return llm.RateLimits{}, false
+	}
+
+	return llm.RateLimits{
+		Provider:   "openai",
+		Requests:   requests,
+		Tokens:     tokens,
+		ReceivedAt: now,
+	}, true
+}
+
+// parseRateLimit parses the headers of one rate limit, e.g. x-ratelimit-remaining-requests
+func parseRateLimit(header http.Header, name string, now time.Time) (llm.RateLimit, bool) {
+	limit, hasLimit := parseHeaderInt(header, "X-Ratelimit-Limit-"+name)
+	remaining, hasRemaining := parseHeaderInt(header, "X-Ratelimit-Remaining-"+name)
+
+	rateLimit := llm.RateLimit{Limit: limit, Remaining: remaining}
+	if reset, err := time.ParseDuration(header.Get("X-Ratelimit-Reset-" + name)); err == nil {
+		rateLimit.Reset = now.Add(reset)
+	}
+	return rateLimit, hasLimit || hasRemaining
+}
+
+// parseHeaderInt parses an integer header, reporting whether it was present and valid
+func parseHeaderInt(header http.Header, key string) (int64, bool) {
+	value, err := strconv.ParseInt(header.Get(key), 10, 64)
+	return value, err == nil
+}
@@ -0,0 +1,83 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	openai_client "github.com/Ingenimax/agent-sdk-go/pkg/llm/openai"
+	"github.com/openai/openai-go/v2"
+)
+
+func TestRateLimitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ratelimit-limit-requests", "500")
+		w.Header().Set("x-ratelimit-remaining-requests", "499")
+		w.Header().Set("x-ratelimit-reset-requests", "120ms")
+		w.Header().Set("x-ratelimit-limit-tokens", "30000")
+		w.Header().Set("x-ratelimit-remaining-tokens", "29950")
+		w.Header().Set("x-ratelimit-reset-tokens", "6m0s")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Content: "Hello", Role: "assistant"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithBaseURL(server.URL))
+
+	ctx, status := llm.WithRateLimitStatus(context.Background())
+	if _, ok := status.Last(); ok {
+		t.Fatal("Expected no rate limits before the call")
+	}
+
+	start := time.Now()
+	if _, err := client.Generate(ctx, "Hi"); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+
+	limits, ok := status.Last()
+	if !ok {
+		t.Fatal("Expected the rate limits of the response to be recorded")
+	}
+	if limits.Provider != "openai" {
+		t.Errorf("Expected provider openai, got %q", limits.Provider)
+	}
+	if limits.Requests.Limit != 500 || limits.Requests.Remaining != 499 {
+		t.Errorf("Unexpected request limits: %+v", limits.Requests)
+	}
+	if limits.Tokens.Limit != 30000 || limits.Tokens.Remaining != 29950 {
+		t.Errorf("Unexpected token limits: %+v", limits.Tokens)
+	}
+	if reset := limits.Tokens.Reset.Sub(start); reset < 6*time.Minute || reset > 6*time.Minute+time.Minute {
+		t.Errorf("Expected the token limit to reset in about 6 minutes, got %v", reset)
+	}
+}
+
+func TestRateLimitStatusWithoutHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Content: "Hello", Role: "assistant"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithBaseURL(server.URL))
+
+	ctx, status := llm.WithRateLimitStatus(context.Background())
+	if _, err := client.Generate(ctx, "Hi"); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if limits, ok := status.Last(); ok {
+		t.Errorf("Expected no rate limits without headers, got %+v", limits)
+	}
+}
@@ -3,10 +3,12 @@ package openai
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/openai/openai-go/v2"
 	"github.com/openai/openai-go/v2/shared"
@@ -29,6 +31,37 @@ func (c *OpenAIClient) GenerateStream(
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("OpenAI", params.Files); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateImages(params.Images); err != nil {
+		return nil, err
+	}
+	if err := validateReasoningEffort(params.LLMConfig); err != nil {
+		return nil, err
+	}
+	if err := validateThinkingBudget(params.LLMConfig); err != nil {
+		return nil, err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Re-request the stream on mid-stream failures, each attempt streaming without resuming
+	if params.StreamRetry > 0 {
+		return llm.ResumeStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateStream(ctx, prompt, append(options[:len(options):len(options)], interfaces.WithStreamRetry(0))...)
+		})
+	}
+
 	// Check for organization ID in context
 	defaultOrgID := "default"
 	if id, err := multitenancy.GetOrgID(ctx); err == nil {
@@ -112,7 +145,7 @@ func (c *OpenAIClient) GenerateStream(
 		}
 
 		// Add current user message
-		messages = append(messages, openai.UserMessage(prompt))
+		messages = append(messages, userMessage(prompt, params.Images))
 
 		// Create stream request
 		streamParams := openai.ChatCompletionNewParams{
@@ -131,6 +164,9 @@ func (c *OpenAIClient) GenerateStream(
 				Name:   params.ResponseFormat.Name,
 				Schema: params.ResponseFormat.Schema,
 			}
+			if params.ResponseFormat.Strict {
+				jsonSchema.Strict = openai.Bool(true)
+			}
 
 			streamParams.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 				OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
@@ -140,13 +176,15 @@ func (c *OpenAIClient) GenerateStream(
 			}
 		}
 
+		c.setReasoningEffort(&streamParams, params.LLMConfig)
+
+		// Request the token usage, sent in a final chunk
+		streamParams.StreamOptions = openai.ChatCompletionStreamOptionsParam{
+			IncludeUsage: openai.Bool(true),
+		}
+
 		// Handle reasoning models and reasoning config
 		if isReasoningModel(c.Model) || (params.LLMConfig != nil && params.LLMConfig.EnableReasoning) {
-			// o1 models or reasoning enabled - ensure we get usage info
-			streamParams.StreamOptions = openai.ChatCompletionStreamOptionsParam{
-				IncludeUsage: openai.Bool(true),
-			}
-
 			// Log reasoning support
 			if isReasoningModel(c.Model) {
 				c.logger.Debug(ctx, "Using reasoning model with built-in reasoning", map[string]interface{}{
@@ -189,7 +227,7 @@ func (c *OpenAIClient) GenerateStream(
 		})
 
 		// Create stream
-		stream := c.ChatService.Completions.NewStreaming(ctx, streamParams)
+		stream := c.ChatService.Completions.NewStreaming(ctx, streamParams, requestOptions(params)...)
 
 		// Send initial message start event
 		eventChan <- interfaces.StreamEvent{
@@ -247,7 +285,8 @@ func (c *OpenAIClient) GenerateStream(
 				// Check for finish reason
 				if choice.FinishReason != "" {
 					eventChan <- interfaces.StreamEvent{
-						Type: interfaces.StreamEventContentComplete,
+						Type:         interfaces.StreamEventContentComplete,
+						FinishReason: llm.ChatFinishReason(choice.FinishReason),
 						Metadata: map[string]interface{}{
 							"finish_reason": choice.FinishReason,
 							"choice_index":  choice.Index,
@@ -257,10 +296,14 @@ func (c *OpenAIClient) GenerateStream(
 				}
 			}
 
-			// Handle usage information (especially for o1 models)
+			// Handle usage information, sent once in the final chunk
 			if chunk.Usage.PromptTokens > 0 || chunk.Usage.CompletionTokens > 0 || chunk.Usage.TotalTokens > 0 {
 				eventChan <- interfaces.StreamEvent{
-					Type:      interfaces.StreamEventContentDelta,
+					Type: interfaces.StreamEventUsage,
+					Usage: &interfaces.TokenUsage{
+						InputTokens:  chunk.Usage.PromptTokens,
+						OutputTokens: chunk.Usage.CompletionTokens,
+					},
 					Timestamp: time.Now(),
 					Metadata: map[string]interface{}{
 						"usage": map[string]interface{}{
@@ -344,6 +387,44 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 		option(params)
 	}
 
+	if err := interfaces.RejectFiles("OpenAI", params.Files); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateImages(params.Images); err != nil {
+		return nil, err
+	}
+	if err := validateReasoningEffort(params.LLMConfig); err != nil {
+		return nil, err
+	}
+	if err := validateThinkingBudget(params.LLMConfig); err != nil {
+		return nil, err
+	}
+	if err := validateStopSequences(params.LLMConfig); err != nil {
+		return nil, err
+	}
+
+	// Emit heartbeats during quiet periods of the stream, such as long tool calls
+	if params.StreamHeartbeat > 0 {
+		return llm.HeartbeatStream(ctx, params, func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+			return c.GenerateWithToolsStream(ctx, prompt, tools, append(options[:len(options):len(options)], interfaces.WithStreamHeartbeat(0))...)
+		})
+	}
+
+	// Validate tool call arguments before executing the tools
+	if params.ValidateToolArguments {
+		tools = interfaces.ValidatingTools(tools)
+	}
+	// Repair tool call arguments which are not valid JSON before validating them
+	if params.RepairToolArguments {
+		tools = interfaces.RepairingTools(tools)
+	}
+
+	// Bound the duration of each tool call
+	if params.ToolTimeout > 0 {
+		tools = interfaces.TimeoutTools(tools, params.ToolTimeout)
+	}
+
 	// Set default max iterations if not provided
 	maxIterations := params.MaxIterations
 	if maxIterations == 0 {
@@ -445,7 +526,7 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 		}
 
 		// Add current user message
-		messages = append(messages, openai.UserMessage(prompt))
+		messages = append(messages, userMessage(prompt, params.Images))
 
 		// Store initial messages in memory
 		if params.Memory != nil {
@@ -486,13 +567,14 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 				Model:      openai.ChatModel(c.Model),
 				Messages:   messages,
 				Tools:      openaiTools,
-				ToolChoice: openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String("auto")},
+				ToolChoice: toolChoiceParam(params.ToolChoiceForIteration(iteration)),
 			}
 
 			// Reasoning models only support temperature=1 (default), so don't set it
 			if !isReasoningModel(c.Model) {
 				streamParams.Temperature = openai.Float(params.LLMConfig.Temperature)
 			}
+			c.setReasoningEffort(&streamParams, params.LLMConfig)
 
 			// Handle reasoning models
 			if isReasoningModel(c.Model) || (params.LLMConfig != nil && params.LLMConfig.EnableReasoning) {
@@ -525,6 +607,9 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 				if params.LLMConfig.PresencePenalty != 0 {
 					streamParams.PresencePenalty = openai.Float(params.LLMConfig.PresencePenalty)
 				}
+				if len(params.LLMConfig.StopSequences) > 0 {
+					streamParams.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: params.LLMConfig.StopSequences}
+				}
 			}
 
 			c.logger.Debug(ctx, "Creating OpenAI streaming request with tools", map[string]interface{}{
@@ -551,7 +636,7 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 			}
 
 			// Create stream
-			stream := c.ChatService.Completions.NewStreaming(ctx, streamParams)
+			stream := c.ChatService.Completions.NewStreaming(ctx, streamParams, requestOptions(params)...)
 			if stream.Err() != nil {
 				c.logger.Error(ctx, "Failed to create OpenAI streaming", map[string]interface{}{
 					"error": stream.Err().Error(),
@@ -565,10 +650,10 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 			}
 
 			// Track streaming state
-			var currentToolCall *interfaces.ToolCall
-			var toolCallBuffer strings.Builder
 			var assistantResponse openai.ChatCompletionMessage
 			var hasContent bool
+			// toolCallPositions maps the index of a streamed tool call to its position in assistantResponse
+			toolCallPositions := make(map[int64]int)
 
 			// Process stream chunks
 			for stream.Next() {
@@ -600,78 +685,49 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 						}
 					}
 
-					// Handle tool calls - OpenAI streams them incrementally
-					if len(choice.Delta.ToolCalls) > 0 {
-						for _, toolCall := range choice.Delta.ToolCalls {
-							if toolCall.Function.Name != "" || toolCall.Function.Arguments != "" {
-								// Check if this is a new tool call or continuation
-								if toolCall.Function.Name != "" {
-									// New tool call started
-									if currentToolCall != nil && toolCallBuffer.Len() > 0 {
-										// Finish previous tool call
-										currentToolCall.Arguments = toolCallBuffer.String()
-										eventChan <- interfaces.StreamEvent{
-											Type:      interfaces.StreamEventToolUse,
-											ToolCall:  currentToolCall,
-											Timestamp: time.Now(),
-										}
-									}
-
-									// Start new tool call
-									currentToolCall = &interfaces.ToolCall{
-										ID:   toolCall.ID,
-										Name: toolCall.Function.Name,
-									}
-									toolCallBuffer.Reset()
-
-									// Add to assistant response
-									assistantResponse.ToolCalls = append(assistantResponse.ToolCalls, openai.ChatCompletionMessageToolCallUnion{
-										ID:   toolCall.ID,
-										Type: "function",
-										Function: openai.ChatCompletionMessageFunctionToolCallFunction{
-											Name: toolCall.Function.Name,
-										},
-									})
-
-									c.logger.Debug(ctx, "Started new tool call", map[string]interface{}{
-										"tool_id":   toolCall.ID,
-										"tool_name": toolCall.Function.Name,
-									})
-								}
-
-								// Accumulate arguments
-								if toolCall.Function.Arguments != "" {
-									toolCallBuffer.WriteString(toolCall.Function.Arguments)
-									// Update the last tool call arguments
-									if len(assistantResponse.ToolCalls) > 0 {
-										lastIdx := len(assistantResponse.ToolCalls) - 1
-										assistantResponse.ToolCalls[lastIdx].Function.Arguments += toolCall.Function.Arguments
-									}
-								}
-							}
+					// Handle tool calls - OpenAI streams them incrementally, identified by their index
+					for _, delta := range choice.Delta.ToolCalls {
+						pos, ok := toolCallPositions[delta.Index]
+						if !ok {
+							// New tool call started
+							pos = len(assistantResponse.ToolCalls)
+							toolCallPositions[delta.Index] = pos
+							assistantResponse.ToolCalls = append(assistantResponse.ToolCalls, openai.ChatCompletionMessageToolCallUnion{
+								Type: "function",
+							})
+
+							c.logger.Debug(ctx, "Started new tool call", map[string]interface{}{
+								"tool_id":   delta.ID,
+								"tool_name": delta.Function.Name,
+							})
 						}
-					}
 
-					// Check for finish reason
-					if choice.FinishReason == "tool_calls" && currentToolCall != nil {
-						// Finish last tool call
-						currentToolCall.Arguments = toolCallBuffer.String()
-						eventChan <- interfaces.StreamEvent{
-							Type:      interfaces.StreamEventToolUse,
-							ToolCall:  currentToolCall,
-							Timestamp: time.Now(),
-							Metadata: map[string]interface{}{
-								"finish_reason": "tool_calls",
-								"iteration":     iteration + 1,
-							},
+						toolCall := &assistantResponse.ToolCalls[pos]
+						if delta.ID != "" {
+							toolCall.ID = delta.ID
+						}
+						if delta.Function.Name != "" {
+							toolCall.Function.Name = delta.Function.Name
 						}
-						currentToolCall = nil
-						toolCallBuffer.Reset()
 
-						c.logger.Debug(ctx, "Finished tool calls", map[string]interface{}{
-							"finish_reason": choice.FinishReason,
-							"iteration":     iteration + 1,
-						})
+						// Accumulate arguments and report the partial call
+						if delta.Function.Arguments != "" {
+							toolCall.Function.Arguments += delta.Function.Arguments
+							eventChan <- interfaces.StreamEvent{
+								Type: interfaces.StreamEventToolCallDelta,
+								ToolCall: &interfaces.ToolCall{
+									ID:        toolCall.ID,
+									Name:      toolCall.Function.Name,
+									Arguments: toolCall.Function.Arguments,
+								},
+								Timestamp: time.Now(),
+								Metadata: map[string]interface{}{
+									"arguments_delta": delta.Function.Arguments,
+									"tool_call_index": delta.Index,
+									"iteration":       iteration + 1,
+								},
+							}
+						}
 					}
 				}
 			}
@@ -692,7 +748,10 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 
 			// Check if the model wants to use tools
 			if len(assistantResponse.ToolCalls) == 0 {
-				// No tool calls, we're done
+				// No tool calls, the model answered: send the content held back so far
+				for _, contentEvent := range append(capturedContentEvents, iterationContentEvents...) {
+					eventChan <- contentEvent
+				}
 				if hasContent {
 					eventChan <- interfaces.StreamEvent{
 						Type:      interfaces.StreamEventContentComplete,
@@ -702,7 +761,35 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 						},
 					}
 				}
-				break // Exit the iteration loop
+
+				if params.Memory != nil && assistantResponse.Content != "" {
+					_ = params.Memory.AddMessage(ctx, interfaces.Message{
+						Role:    "assistant",
+						Content: assistantResponse.Content,
+					})
+				}
+
+				eventChan <- interfaces.StreamEvent{
+					Type:      interfaces.StreamEventMessageStop,
+					Timestamp: time.Now(),
+				}
+				return
+			}
+
+			// Report the assembled tool calls
+			for _, toolCall := range assistantResponse.ToolCalls {
+				eventChan <- interfaces.StreamEvent{
+					Type: interfaces.StreamEventToolUse,
+					ToolCall: &interfaces.ToolCall{
+						ID:        toolCall.ID,
+						Name:      toolCall.Function.Name,
+						Arguments: toolCall.Function.Arguments,
+					},
+					Timestamp: time.Now(),
+					Metadata: map[string]interface{}{
+						"iteration": iteration + 1,
+					},
+				}
 			}
 
 			// The model wants to use tools
@@ -737,15 +824,17 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 					}
 				}
 
+				// Execute the tool. Every tool call needs a result, so unknown tools are reported to the model.
+				var result string
+				var err error
 				if foundTool == nil {
 					c.logger.Error(ctx, "Tool not found", map[string]interface{}{
 						"tool_name": toolCall.Function.Name,
 					})
-					continue
+					err = fmt.Errorf("tool not found: %s", toolCall.Function.Name)
+				} else {
+					result, err = interfaces.ExecuteTool(interfaces.WithToolCallID(ctx, toolCall.ID), foundTool, toolCall.Function.Arguments)
 				}
-
-				// Execute the tool
-				result, err := foundTool.Execute(ctx, toolCall.Function.Arguments)
 				if err != nil {
 					c.logger.Error(ctx, "Tool execution error", map[string]interface{}{
 						"tool_name": toolCall.Function.Name,
@@ -871,6 +960,7 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 		if !isReasoningModel(c.Model) {
 			finalStreamParams.Temperature = openai.Float(params.LLMConfig.Temperature)
 		}
+		c.setReasoningEffort(&finalStreamParams, params.LLMConfig)
 
 		// Add other parameters
 		if params.LLMConfig != nil {
@@ -891,7 +981,7 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 		})
 
 		// Create final stream
-		finalStream := c.ChatService.Completions.NewStreaming(ctx, finalStreamParams)
+		finalStream := c.ChatService.Completions.NewStreaming(ctx, finalStreamParams, requestOptions(params)...)
 		if finalStream.Err() != nil {
 			c.logger.Error(ctx, "Error in final streaming call without tools", map[string]interface{}{
 				"error": finalStream.Err().Error(),
@@ -929,7 +1019,8 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 				// Check for finish reason
 				if choice.FinishReason != "" {
 					eventChan <- interfaces.StreamEvent{
-						Type: interfaces.StreamEventContentComplete,
+						Type:         interfaces.StreamEventContentComplete,
+						FinishReason: llm.ChatFinishReason(choice.FinishReason),
 						Metadata: map[string]interface{}{
 							"finish_reason": choice.FinishReason,
 							"choice_index":  choice.Index,
@@ -977,40 +1068,39 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 	return eventChan, nil
 }
 
+// toolChoiceParam converts a tool choice to the OpenAI format, defaulting to auto
+func toolChoiceParam(choice *interfaces.ToolChoice) openai.ChatCompletionToolChoiceOptionUnionParam {
+	if choice == nil {
+		return openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String("auto")}
+	}
+
+	switch choice.Mode {
+	case interfaces.ToolChoiceNone, interfaces.ToolChoiceRequired:
+		return openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String(string(choice.Mode))}
+	case interfaces.ToolChoiceTool:
+		return openai.ChatCompletionToolChoiceOptionUnionParam{
+			OfFunctionToolChoice: &openai.ChatCompletionNamedToolChoiceParam{
+				Function: openai.ChatCompletionNamedToolChoiceFunctionParam{Name: choice.Name},
+			},
+		}
+	default:
+		return openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String("auto")}
+	}
+}
+
 // convertToOpenAISchema converts tool parameters to OpenAI function schema
 func (c *OpenAIClient) convertToOpenAISchema(params map[string]interfaces.ParameterSpec) map[string]interface{} {
 	properties := make(map[string]interface{})
 	required := []string{}
 
 	for name, param := range params {
-		property := map[string]interface{}{
-			"type":        param.Type,
-			"description": param.Description,
-		}
-
-		if param.Default != nil {
-			property["default"] = param.Default
-		}
-
-		if param.Items != nil {
-			property["items"] = map[string]interface{}{
-				"type": param.Items.Type,
-			}
-			if param.Items.Enum != nil {
-				property["items"].(map[string]interface{})["enum"] = param.Items.Enum
-			}
-		}
-
-		if param.Enum != nil {
-			property["enum"] = param.Enum
-		}
-
-		properties[name] = property
+		properties[name] = convertParameterToOpenAISchema(param)
 
 		if param.Required {
 			required = append(required, name)
 		}
 	}
+	sort.Strings(required)
 
 	return map[string]interface{}{
 		"type":       "object",
@@ -1018,3 +1108,42 @@ func (c *OpenAIClient) convertToOpenAISchema(params map[string]interfaces.Parame
 		"required":   required,
 	}
 }
+
+// convertParameterToOpenAISchema converts a single parameter to JSON schema, including nested array items and object properties
+func convertParameterToOpenAISchema(param interfaces.ParameterSpec) map[string]interface{} {
+	property := map[string]interface{}{
+		"type": param.SchemaType(),
+	}
+
+	if param.Description != "" {
+		property["description"] = param.Description
+	}
+
+	if param.Default != nil {
+		property["default"] = param.Default
+	}
+
+	if param.Items != nil {
+		property["items"] = convertParameterToOpenAISchema(*param.Items)
+	}
+
+	if param.Enum != nil {
+		property["enum"] = param.Enum
+	}
+
+	if param.Properties != nil {
+		properties := make(map[string]interface{}, len(param.Properties))
+		required := []string{}
+		for name, nested := range param.Properties {
+			properties[name] = convertParameterToOpenAISchema(nested)
+			if nested.Required {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		property["properties"] = properties
+		property["required"] = required
+	}
+
+	return property
+}
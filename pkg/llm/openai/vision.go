@@ -0,0 +1,56 @@
+package openai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/openai/openai-go/v2"
+)
+
+// nonVisionModels lists the models, or model prefixes ending in "-", without image input
+var nonVisionModels = []string{
+	"gpt-3.5-",
+	"gpt-4", "gpt-4-0314", "gpt-4-0613", "gpt-4-32k",
+	"o1-mini", "o1-preview",
+	"o3-mini",
+}
+
+// supportsVision returns false for the models known not to accept images
+func supportsVision(model string) bool {
+	for _, name := range nonVisionModels {
+		if model == name || (strings.HasSuffix(name, "-") && strings.HasPrefix(model, name)) ||
+			strings.HasPrefix(model, name+"-20") {
+			return false
+		}
+	}
+	return true
+}
+
+// validateImages checks the images of a request and that the model accepts images
+func (c *OpenAIClient) validateImages(images []interfaces.ImageInput) error {
+	if len(images) == 0 {
+		return nil
+	}
+	if !supportsVision(c.Model) {
+		return fmt.Errorf("model %s does not support image input", c.Model)
+	}
+	return interfaces.ValidateImages(images)
+}
+
+// userMessage returns the user message of a prompt with the images of the request attached
+func userMessage(prompt string, images []interfaces.ImageInput) openai.ChatCompletionMessageParamUnion {
+	if len(images) == 0 {
+		return openai.UserMessage(prompt)
+	}
+
+	parts := []openai.ChatCompletionContentPartUnionParam{openai.TextContentPart(prompt)}
+	for _, image := range images {
+		url := image.URL
+		if url == "" {
+			url = image.DataURL()
+		}
+		parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: url}))
+	}
+	return openai.UserMessage(parts)
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit is the state of one rate limit of a provider. Limit is zero when the provider did
+// not report the limit.
+type RateLimit struct {
+	// Limit is the maximum allowed in the current window
+	Limit int64
+	// Remaining is what is left in the current window
+	Remaining int64
+	// Reset is when the limit is fully replenished
+	Reset time.Time
+}
+
+// RateLimits holds the rate limits a provider reported in the headers of a response
+type RateLimits struct {
+	// Provider is the name of the LLM provider, e.g. "openai"
+	Provider string
+	// Requests is the limit on the number of requests
+	Requests RateLimit
+	// Tokens is the limit on the number of tokens
+	Tokens RateLimit
+	// InputTokens is the limit on the number of input tokens, for providers limiting them separately
+	InputTokens RateLimit
+	// OutputTokens is the limit on the number of output tokens, for providers limiting them separately
+	OutputTokens RateLimit
+	// ReceivedAt is when the response was received
+	ReceivedAt time.Time
+}
+
+// RateLimitStatus records the rate limits reported by the provider after each LLM call made
+// with a context returned by WithRateLimitStatus
+type RateLimitStatus struct {
+	mu     sync.Mutex
+	limits RateLimits
+	ok     bool
+}
+
+// Last returns the rate limits of the last response reporting them, and false until one did
+func (s *RateLimitStatus) Last() (RateLimits, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limits, s.ok
+}
+
+func (s *RateLimitStatus) set(limits RateLimits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+	s.ok = true
+}
+
+// rateLimitStatusKey is the context key of the RateLimitStatus of a call
+type rateLimitStatusKey struct{}
+
+// WithRateLimitStatus returns a context whose LLM calls record the rate limits reported by the
+// provider in the returned RateLimitStatus, e.g. to pace requests on the client side
+func WithRateLimitStatus(ctx context.Context) (context.Context, *RateLimitStatus) {
+	status := &RateLimitStatus{}
+	return context.WithValue(ctx, rateLimitStatusKey{}, status), status
+}
+
+// RecordRateLimits stores the rate limits of a response in the RateLimitStatus of ctx, if any.
+// LLM clients call it after each response reporting rate limits.
+func RecordRateLimits(ctx context.Context, limits RateLimits) {
+	if status, ok := ctx.Value(rateLimitStatusKey{}).(*RateLimitStatus); ok {
+		status.set(limits)
+	}
+}
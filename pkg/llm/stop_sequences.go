@@ -0,0 +1,18 @@
+package llm
+
+import "fmt"
+
+// ValidateStopSequences returns an error if stop sequences would be rejected by a provider
+// accepting at most max of them, so that the request fails before it is sent. Empty sequences
+// are rejected by every provider.
+func ValidateStopSequences(provider string, stopSequences []string, max int) error {
+	if len(stopSequences) > max {
+		return fmt.Errorf("%s accepts at most %d stop sequences, got %d", provider, max, len(stopSequences))
+	}
+	for i, sequence := range stopSequences {
+		if sequence == "" {
+			return fmt.Errorf("stop sequence %d is empty", i)
+		}
+	}
+	return nil
+}
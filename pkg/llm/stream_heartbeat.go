@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// HeartbeatStream relays the events of a streaming request, emitting a StreamEventHeartbeat
+// event whenever no event was relayed for params.StreamHeartbeat. Providers call it from
+// GenerateStream and GenerateWithToolsStream when interfaces.WithStreamHeartbeat is set.
+func HeartbeatStream(ctx context.Context, params *interfaces.GenerateOptions, start StreamStarter) (<-chan interfaces.StreamEvent, error) {
+	bufferSize := interfaces.DefaultStreamConfig().BufferSize
+	if params.StreamConfig != nil {
+		bufferSize = params.StreamConfig.BufferSize
+	}
+
+	events, err := start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan interfaces.StreamEvent, bufferSize)
+
+	go func() {
+		defer close(out)
+		// Let the producer exit if the consumer goes away
+		defer func() { go drain(events) }()
+
+		ticker := time.NewTicker(params.StreamHeartbeat)
+		defer ticker.Stop()
+
+		for {
+			var event interfaces.StreamEvent
+			select {
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				event = e
+				ticker.Reset(params.StreamHeartbeat)
+			case now := <-ticker.C:
+				event = interfaces.StreamEvent{
+					Type:      interfaces.StreamEventHeartbeat,
+					Timestamp: now,
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
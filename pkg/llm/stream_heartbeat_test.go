@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// pacedStream returns a stream starter that waits for the quiet period, then emits the deltas
+// with the given spacing
+func pacedStream(quiet, spacing time.Duration, deltas ...string) StreamStarter {
+	return func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+		events := make(chan interfaces.StreamEvent)
+		go func() {
+			defer close(events)
+			time.Sleep(quiet)
+			for _, content := range deltas {
+				select {
+				case events <- delta(content):
+				case <-ctx.Done():
+					return
+				}
+				time.Sleep(spacing)
+			}
+		}()
+		return events, nil
+	}
+}
+
+func TestHeartbeatStreamDuringQuietPeriod(t *testing.T) {
+	params := &interfaces.GenerateOptions{StreamHeartbeat: 20 * time.Millisecond}
+	events, err := HeartbeatStream(context.Background(), params,
+		pacedStream(150*time.Millisecond, 2*time.Millisecond, "The ", "quick ", "brown ", "fox"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var before, after int
+	var content string
+	for event := range events {
+		switch event.Type {
+		case interfaces.StreamEventHeartbeat:
+			if event.Timestamp.IsZero() {
+				t.Error("expected heartbeats to have a timestamp")
+			}
+			if content == "" {
+				before++
+			} else {
+				after++
+			}
+		case interfaces.StreamEventContentDelta:
+			content += event.Content
+		}
+	}
+
+	if content != "The quick brown fox" {
+		t.Errorf("expected all content to be relayed, got %q", content)
+	}
+	if before < 3 {
+		t.Errorf("expected heartbeats during the quiet period, got %d", before)
+	}
+	if after != 0 {
+		t.Errorf("expected no heartbeats while content flows, got %d", after)
+	}
+}
+
+func TestHeartbeatStreamStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	params := &interfaces.GenerateOptions{StreamHeartbeat: 5 * time.Millisecond}
+	events, err := HeartbeatStream(ctx, params, pacedStream(time.Hour, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if event := <-events; event.Type != interfaces.StreamEventHeartbeat {
+		t.Fatalf("expected a heartbeat, got %s", event.Type)
+	}
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("expected the stream to close after cancellation")
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+)
+
+// StreamStarter starts a new attempt of a streaming request
+type StreamStarter func(ctx context.Context) (<-chan interfaces.StreamEvent, error)
+
+// streamRetryPolicy is the backoff between the attempts of a resumed stream
+var streamRetryPolicy = retry.NewPolicy(
+	retry.WithInitialInterval(500*time.Millisecond),
+	retry.WithMaximumInterval(10*time.Second),
+)
+
+// errStreamDiverged reports a re-requested stream whose content differs from the content
+// already emitted, which cannot be taken back
+var errStreamDiverged = errors.New("re-requested stream diverged from the content already emitted")
+
+// ResumeStream relays the events of a streaming request, re-requesting it up to
+// params.StreamRetry times, with exponential backoff, when the stream fails with an error
+// event. Errors marked with retry.Permanent are not retried. Providers call it from
+// GenerateStream when interfaces.WithStreamRetry is set.
+//
+// A re-requested stream starts over from the beginning, so the content and thinking already
+// emitted are skipped, as well as repeated message start events. The new attempt must
+// reproduce the emitted text, which holds for deterministic settings such as a zero
+// temperature; when it does not, the stream ends with an error event since the emitted text
+// cannot be taken back.
+func ResumeStream(ctx context.Context, params *interfaces.GenerateOptions, start StreamStarter) (<-chan interfaces.StreamEvent, error) {
+	bufferSize := interfaces.DefaultStreamConfig().BufferSize
+	if params.StreamConfig != nil {
+		bufferSize = params.StreamConfig.BufferSize
+	}
+
+	attemptCtx, cancel := context.WithCancel(ctx)
+	events, err := start(attemptCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	out := make(chan interfaces.StreamEvent, bufferSize)
+
+	go func() {
+		defer close(out)
+
+		var content, thinking strings.Builder
+		var started bool
+		var failure *interfaces.StreamEvent
+
+		send := func(event interfaces.StreamEvent) bool {
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// attempt relays the events of one attempt, the first one being already started
+		attempt := func() error {
+			var err error
+			if events == nil {
+				attemptCtx, cancel = context.WithCancel(ctx)
+				events, err = start(attemptCtx)
+				if err != nil {
+					cancel()
+					events = nil
+					failure = nil
+					return err
+				}
+			}
+			defer func() {
+				// Abandon the attempt, draining it so its producer can exit
+				cancel()
+				go drain(events)
+				events = nil
+			}()
+
+			// Lengths of the emitted text this attempt has reproduced
+			var matchedContent, matchedThinking int
+			for event := range events {
+				switch event.Type {
+				case interfaces.StreamEventError:
+					failure = &event
+					if event.Error == nil {
+						return fmt.Errorf("stream failed")
+					}
+					return event.Error
+				case interfaces.StreamEventMessageStart:
+					if started {
+						continue
+					}
+					started = true
+				case interfaces.StreamEventContentDelta:
+					event.Content, matchedContent, err = resumeText(content.String(), matchedContent, event.Content)
+					if err != nil {
+						failure = nil
+						return retry.Permanent(err)
+					}
+					if event.Content == "" {
+						continue
+					}
+					content.WriteString(event.Content)
+					matchedContent = content.Len()
+				case interfaces.StreamEventThinking:
+					event.Content, matchedThinking, err = resumeText(thinking.String(), matchedThinking, event.Content)
+					if err != nil {
+						failure = nil
+						return retry.Permanent(err)
+					}
+					if event.Content == "" {
+						continue
+					}
+					thinking.WriteString(event.Content)
+					matchedThinking = thinking.Len()
+				}
+
+				if !send(event) {
+					return retry.Permanent(ctx.Err())
+				}
+			}
+			return nil
+		}
+
+		err := retry.NewExecutor(streamRetryPolicy).ExecuteWithMaxAttempts(ctx, int32(params.StreamRetry)+1, attempt)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if failure != nil {
+			send(*failure)
+			return
+		}
+		send(interfaces.StreamEvent{
+			Type:      interfaces.StreamEventError,
+			Error:     err,
+			Timestamp: time.Now(),
+		})
+	}()
+
+	return out, nil
+}
+
+// resumeText returns the part of a delta of a re-requested stream extending the text emitted so
+// far, given the length of the emitted text the attempt has already reproduced, and the new
+// reproduced length. It fails when the delta differs from the emitted text.
+func resumeText(emitted string, matched int, delta string) (string, int, error) {
+	pending := emitted[matched:]
+	if len(delta) <= len(pending) {
+		if !strings.HasPrefix(pending, delta) {
+			return "", matched, errStreamDiverged
+		}
+		return "", matched + len(delta), nil
+	}
+	if !strings.HasPrefix(delta, pending) {
+		return "", matched, errStreamDiverged
+	}
+	return delta[len(pending):], len(emitted), nil
+}
+
+func drain(events <-chan interfaces.StreamEvent) {
+	for range events {
+	}
+}
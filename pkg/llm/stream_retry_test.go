@@ -0,0 +1,171 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+)
+
+func init() {
+	// Retry streams without waiting in the tests
+	streamRetryPolicy = retry.NewPolicy(retry.WithInitialInterval(time.Millisecond), retry.WithMaximumInterval(time.Millisecond))
+}
+
+// scriptedStream returns a stream starter replaying one scripted attempt per call
+func scriptedStream(attempts ...[]interfaces.StreamEvent) (StreamStarter, *int) {
+	calls := 0
+	return func(ctx context.Context) (<-chan interfaces.StreamEvent, error) {
+		if calls >= len(attempts) {
+			return nil, errors.New("no more attempts")
+		}
+		events := make(chan interfaces.StreamEvent, len(attempts[calls]))
+		for _, event := range attempts[calls] {
+			events <- event
+		}
+		close(events)
+		calls++
+		return events, nil
+	}, &calls
+}
+
+func delta(content string) interfaces.StreamEvent {
+	return interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: content}
+}
+
+var (
+	messageStart  = interfaces.StreamEvent{Type: interfaces.StreamEventMessageStart}
+	messageStop   = interfaces.StreamEvent{Type: interfaces.StreamEventMessageStop}
+	disconnection = interfaces.StreamEvent{Type: interfaces.StreamEventError, Error: errors.New("unexpected EOF")}
+)
+
+func collect(t *testing.T, events <-chan interfaces.StreamEvent) (string, []interfaces.StreamEvent) {
+	t.Helper()
+	var content strings.Builder
+	var all []interfaces.StreamEvent
+	for event := range events {
+		all = append(all, event)
+		if event.Type == interfaces.StreamEventContentDelta {
+			content.WriteString(event.Content)
+		}
+	}
+	return content.String(), all
+}
+
+func TestResumeStreamRecoversFromDisconnect(t *testing.T) {
+	start, calls := scriptedStream(
+		[]interfaces.StreamEvent{messageStart, delta("The quick "), delta("bro"), disconnection},
+		// The second attempt drops in the middle of a delta boundary of the first one
+		[]interfaces.StreamEvent{messageStart, delta("The qu"), delta("ick brown f"), disconnection},
+		[]interfaces.StreamEvent{messageStart, delta("The quick brown fox"), delta(" jumps."), messageStop},
+	)
+
+	events, err := ResumeStream(context.Background(), &interfaces.GenerateOptions{StreamRetry: 3}, start)
+	if err != nil {
+		t.Fatalf("ResumeStream failed: %v", err)
+	}
+
+	content, all := collect(t, events)
+	if content != "The quick brown fox jumps." {
+		t.Errorf("Expected the content once, got %q", content)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *calls)
+	}
+
+	starts := 0
+	for _, event := range all {
+		if event.Type == interfaces.StreamEventError {
+			t.Errorf("Unexpected error event: %v", event.Error)
+		}
+		if event.Type == interfaces.StreamEventMessageStart {
+			starts++
+		}
+	}
+	if starts != 1 {
+		t.Errorf("Expected a single message start event, got %d", starts)
+	}
+	if last := all[len(all)-1]; last.Type != interfaces.StreamEventMessageStop {
+		t.Errorf("Expected the stream to end with a message stop, got %s", last.Type)
+	}
+}
+
+func TestResumeStreamGivesUpAfterMaxRetries(t *testing.T) {
+	start, calls := scriptedStream(
+		[]interfaces.StreamEvent{messageStart, delta("Hello"), disconnection},
+		[]interfaces.StreamEvent{messageStart, delta("Hello wor"), disconnection},
+		[]interfaces.StreamEvent{messageStart, delta("Hello world"), messageStop},
+	)
+
+	events, err := ResumeStream(context.Background(), &interfaces.GenerateOptions{StreamRetry: 1}, start)
+	if err != nil {
+		t.Fatalf("ResumeStream failed: %v", err)
+	}
+
+	content, all := collect(t, events)
+	if content != "Hello wor" {
+		t.Errorf("Expected the content emitted before the last failure, got %q", content)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", *calls)
+	}
+	if last := all[len(all)-1]; last.Type != interfaces.StreamEventError || last.Error == nil {
+		t.Errorf("Expected the stream to end with the error, got %s", last.Type)
+	}
+}
+
+func TestResumeStreamStartError(t *testing.T) {
+	start, _ := scriptedStream()
+	if _, err := ResumeStream(context.Background(), &interfaces.GenerateOptions{StreamRetry: 2}, start); err == nil {
+		t.Error("Expected the error of the first attempt to be returned")
+	}
+}
+
+func TestResumeStreamDivergedContent(t *testing.T) {
+	start, calls := scriptedStream(
+		[]interfaces.StreamEvent{messageStart, delta("Héllo "), disconnection},
+		// The new attempt generates another text, which cannot follow the emitted one
+		[]interfaces.StreamEvent{messageStart, delta("Hi there"), messageStop},
+	)
+
+	events, err := ResumeStream(context.Background(), &interfaces.GenerateOptions{StreamRetry: 3}, start)
+	if err != nil {
+		t.Fatalf("ResumeStream failed: %v", err)
+	}
+
+	content, all := collect(t, events)
+	if content != "Héllo " {
+		t.Errorf("Expected only the content of the first attempt, got %q", content)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected no retry after the divergence, got %d attempts", *calls)
+	}
+	if last := all[len(all)-1]; last.Type != interfaces.StreamEventError || !errors.Is(last.Error, errStreamDiverged) {
+		t.Errorf("Expected the stream to end with the divergence error, got %s (%v)", last.Type, last.Error)
+	}
+}
+
+func TestResumeStreamPermanentError(t *testing.T) {
+	rejected := interfaces.StreamEvent{Type: interfaces.StreamEventError, Error: retry.Permanent(errors.New("invalid request"))}
+	start, calls := scriptedStream(
+		[]interfaces.StreamEvent{messageStart, delta("Hello"), rejected},
+		[]interfaces.StreamEvent{messageStart, delta("Hello world"), messageStop},
+	)
+
+	events, err := ResumeStream(context.Background(), &interfaces.GenerateOptions{StreamRetry: 3}, start)
+	if err != nil {
+		t.Fatalf("ResumeStream failed: %v", err)
+	}
+
+	_, all := collect(t, events)
+	if *calls != 1 {
+		t.Errorf("Expected a permanent error not to be retried, got %d attempts", *calls)
+	}
+	if last := all[len(all)-1]; last.Type != interfaces.StreamEventError || last.Error == nil {
+		t.Errorf("Expected the stream to end with the error, got %s", last.Type)
+	}
+}
@@ -0,0 +1,55 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// structuredOutputRepairPrompt asks the model to fix a response that is not valid JSON
+const structuredOutputRepairPrompt = `%s
+
+Your previous response could not be parsed as JSON: %s
+
+Previous response:
+%s
+
+Respond again with only the corrected JSON, matching the requested format, without any other text.`
+
+// StructuredOutputGenerator generates the response to a prompt of a structured output request
+type StructuredOutputGenerator func(ctx context.Context, prompt string) (string, error)
+
+// RepairStructuredOutput generates the response to a structured output request and, while it is
+// not valid JSON, re-sends the prompt along with the parse error and the invalid response, asking
+// the model for a fix, up to params.OutputRepair times. Providers call it from Generate
+// when interfaces.WithStructuredOutputRepair is set. If every attempt fails, the parse error of
+// the last one is returned.
+func RepairStructuredOutput(ctx context.Context, params *interfaces.GenerateOptions, prompt string, generate StructuredOutputGenerator) (string, error) {
+	response, err := generate(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	parseErr := validateJSON(response)
+	for attempt := 1; parseErr != nil && attempt <= params.OutputRepair; attempt++ {
+		response, err = generate(ctx, fmt.Sprintf(structuredOutputRepairPrompt, prompt, parseErr, response))
+		if err != nil {
+			return "", err
+		}
+		parseErr = validateJSON(response)
+	}
+
+	if parseErr != nil {
+		return "", fmt.Errorf("structured output is not valid JSON after %d repair attempts: %w", params.OutputRepair, parseErr)
+	}
+	return response, nil
+}
+
+// validateJSON returns the error of parsing the response as JSON, if any
+func validateJSON(response string) error {
+	var v interface{}
+	return json.Unmarshal([]byte(strings.TrimSpace(response)), &v)
+}
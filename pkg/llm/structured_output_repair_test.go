@@ -0,0 +1,67 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// scriptedResponses returns a generator replaying one response per call, recording the prompts
+func scriptedResponses(responses ...string) (StructuredOutputGenerator, *[]string) {
+	var prompts []string
+	return func(ctx context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		if len(prompts) > len(responses) {
+			return "", errors.New("no more responses")
+		}
+		return responses[len(prompts)-1], nil
+	}, &prompts
+}
+
+func TestRepairStructuredOutput(t *testing.T) {
+	generate, prompts := scriptedResponses(`{"name": "Ada",}`, `{"name": "Ada"}`)
+
+	response, err := RepairStructuredOutput(context.Background(), &interfaces.GenerateOptions{OutputRepair: 2}, "Who is Ada?", generate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response != `{"name": "Ada"}` {
+		t.Errorf("Expected the repaired response, got %q", response)
+	}
+
+	if len(*prompts) != 2 {
+		t.Fatalf("Expected a single repair attempt, got %d prompts", len(*prompts))
+	}
+	repair := (*prompts)[1]
+	for _, expected := range []string{"Who is Ada?", "invalid character", `{"name": "Ada",}`} {
+		if !strings.Contains(repair, expected) {
+			t.Errorf("Expected the repair prompt to contain %q, got %q", expected, repair)
+		}
+	}
+}
+
+func TestRepairStructuredOutputValidResponse(t *testing.T) {
+	generate, prompts := scriptedResponses(`{"name": "Ada"}`)
+
+	if _, err := RepairStructuredOutput(context.Background(), &interfaces.GenerateOptions{OutputRepair: 2}, "Who is Ada?", generate); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(*prompts) != 1 {
+		t.Errorf("Expected no repair of a valid response, got %d prompts", len(*prompts))
+	}
+}
+
+func TestRepairStructuredOutputExhausted(t *testing.T) {
+	generate, prompts := scriptedResponses("Ada was a mathematician.", "Sure! Ada was...", "{")
+
+	_, err := RepairStructuredOutput(context.Background(), &interfaces.GenerateOptions{OutputRepair: 2}, "Who is Ada?", generate)
+	if err == nil || !strings.Contains(err.Error(), "after 2 repair attempts") || !strings.Contains(err.Error(), "unexpected end of JSON input") {
+		t.Errorf("Expected the parse error of the last attempt, got %v", err)
+	}
+	if len(*prompts) != 3 {
+		t.Errorf("Expected the initial attempt and 2 repairs, got %d prompts", len(*prompts))
+	}
+}
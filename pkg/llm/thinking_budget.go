@@ -0,0 +1,16 @@
+package llm
+
+import (
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// RejectThinkingBudget returns an error when a thinking budget is set on a request of a provider
+// that cannot bound the thinking tokens of its models, so that the budget is not silently ignored
+func RejectThinkingBudget(provider string, config *interfaces.LLMConfig) error {
+	if config == nil || config.ThinkingBudget <= 0 {
+		return nil
+	}
+	return fmt.Errorf("%s does not support thinking budgets", provider)
+}
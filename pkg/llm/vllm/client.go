@@ -175,6 +175,13 @@ func (c *VLLMClient) Generate(ctx context.Context, prompt string, options ...int
 		option(params)
 	}
 
+	if err := interfaces.RejectImages("vLLM", params.Images); err != nil {
+		return "", err
+	}
+	if err := llm.RejectThinkingBudget("vLLM", params.LLMConfig); err != nil {
+		return "", err
+	}
+
 	// Create request
 	req := GenerateRequest{
 		Model:       c.Model,
@@ -210,7 +217,7 @@ Ensure your response is a valid JSON object that strictly follows the schema abo
 	}
 
 	// Make request
-	resp, err := c.makeRequest(ctx, "/v1/completions", req)
+	resp, err := c.makeRequest(ctx, "/v1/completions", req, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate text: %w", err)
 	}
@@ -278,7 +285,7 @@ func (c *VLLMClient) Chat(ctx context.Context, messages []llm.Message, params *l
 	}
 
 	// Make request
-	resp, err := c.makeRequest(ctx, "/v1/chat/completions", req)
+	resp, err := c.makeRequest(ctx, "/v1/chat/completions", req, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to chat: %w", err)
 	}
@@ -305,8 +312,9 @@ func (c *VLLMClient) SupportsStreaming() bool {
 	return false
 }
 
-// makeRequest makes an HTTP request to the vLLM API
-func (c *VLLMClient) makeRequest(ctx context.Context, endpoint string, payload interface{}) ([]byte, error) {
+// makeRequest makes an HTTP request to the vLLM API, bypassing the retry policy or bounding its
+// attempts as set by the options of the call, if any
+func (c *VLLMClient) makeRequest(ctx context.Context, endpoint string, payload interface{}, params *interfaces.GenerateOptions) ([]byte, error) {
 	// Marshal payload
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -323,8 +331,12 @@ func (c *VLLMClient) makeRequest(ctx context.Context, endpoint string, payload i
 
 	// Execute request with retry if configured
 	var resp *http.Response
-	if c.retryExecutor != nil {
-		err = c.retryExecutor.Execute(ctx, func() error {
+	if c.retryExecutor != nil && (params == nil || !params.NoRetry) {
+		maxAttempts := int32(0)
+		if params != nil {
+			maxAttempts = int32(params.MaxRetries)
+		}
+		err = c.retryExecutor.ExecuteWithMaxAttempts(ctx, maxAttempts, func() error {
 			var execErr error
 			resp, execErr = c.HTTPClient.Do(req)
 			return execErr
@@ -2,6 +2,8 @@ package vllm
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
@@ -382,3 +384,18 @@ func TestClientWithAllOptions(t *testing.T) {
 	assert.NotNil(t, client.retryExecutor)
 	assert.NotNil(t, client.HTTPClient)
 }
+
+func TestGenerateRejectsThinkingBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent with a thinking budget")
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	budget := interfaces.WithThinkingBudget(2048)
+
+	_, err := client.Generate(context.Background(), "Count to ten", budget)
+	assert.EqualError(t, err, "vLLM does not support thinking budgets")
+	_, err = client.GenerateWithTools(context.Background(), "Count to ten", nil, budget)
+	assert.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
 	"github.com/rs/zerolog"
 )
 
@@ -28,7 +29,8 @@ type Logger interface {
 
 // ZeroLogger implements Logger using zerolog
 type ZeroLogger struct {
-	logger zerolog.Logger
+	logger     zerolog.Logger
+	anonymizer anonymizer.Anonymizer
 }
 
 // New creates a new ZeroLogger
@@ -64,8 +66,24 @@ func WithLevel(level string) func(*ZeroLogger) {
 	}
 }
 
+// WithAnonymizer sets an anonymizer applied to log messages and fields before they are written
+func WithAnonymizer(a anonymizer.Anonymizer) func(*ZeroLogger) {
+	return func(l *ZeroLogger) {
+		l.anonymizer = a
+	}
+}
+
+// anonymize masks sensitive content of a log message and its fields
+func (l *ZeroLogger) anonymize(ctx context.Context, msg string, fields map[string]interface{}) (string, map[string]interface{}) {
+	if l.anonymizer == nil {
+		return msg, fields
+	}
+	return l.anonymizer.Anonymize(ctx, msg), anonymizer.Fields(ctx, l.anonymizer, fields)
+}
+
 // Info logs an info message
 func (l *ZeroLogger) Info(ctx context.Context, msg string, fields map[string]interface{}) {
+	msg, fields = l.anonymize(ctx, msg, fields)
 	event := l.logger.Info()
 
 	// Add trace ID if available
@@ -88,6 +106,7 @@ func (l *ZeroLogger) Info(ctx context.Context, msg string, fields map[string]int
 
 // Warn logs a warning message
 func (l *ZeroLogger) Warn(ctx context.Context, msg string, fields map[string]interface{}) {
+	msg, fields = l.anonymize(ctx, msg, fields)
 	event := l.logger.Warn()
 
 	// Add trace ID if available
@@ -110,6 +129,7 @@ func (l *ZeroLogger) Warn(ctx context.Context, msg string, fields map[string]int
 
 // Error logs an error message
 func (l *ZeroLogger) Error(ctx context.Context, msg string, fields map[string]interface{}) {
+	msg, fields = l.anonymize(ctx, msg, fields)
 	event := l.logger.Error()
 
 	// Add trace ID if available
@@ -132,6 +152,7 @@ func (l *ZeroLogger) Error(ctx context.Context, msg string, fields map[string]in
 
 // Debug logs a debug message
 func (l *ZeroLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	msg, fields = l.anonymize(ctx, msg, fields)
 	event := l.logger.Debug()
 
 	// Add trace ID if available
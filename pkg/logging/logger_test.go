@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/rs/zerolog"
+)
+
+func TestLoggerAnonymizer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &ZeroLogger{logger: zerolog.New(&buf)}
+
+	policy := anonymizer.NewOrgPolicyAnonymizer(nil)
+	policy.SetPolicy("strict-org", anonymizer.NewPIIRedactor())
+	WithAnonymizer(policy)(logger)
+
+	ctx := multitenancy.WithOrgID(context.Background(), "strict-org")
+	logger.Info(ctx, "Message from jane@example.com", map[string]interface{}{"ip": "10.0.0.1"})
+
+	output := buf.String()
+	if strings.Contains(output, "jane@example.com") || strings.Contains(output, "10.0.0.1") {
+		t.Errorf("expected sensitive content to be masked, got %s", output)
+	}
+	if !strings.Contains(output, "[REDACTED]") {
+		t.Errorf("expected redaction placeholder, got %s", output)
+	}
+
+	buf.Reset()
+	logger.Info(multitenancy.WithOrgID(context.Background(), "other-org"), "Message from jane@example.com", nil)
+	if !strings.Contains(buf.String(), "jane@example.com") {
+		t.Errorf("expected org without policy to be logged as is, got %s", buf.String())
+	}
+}
@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ClientTools are the tools of an MCP server connected by NewClientTools
+type ClientTools struct {
+	// Tools forward their calls to the server, ready for agent.WithTools
+	Tools []interfaces.Tool
+
+	server interfaces.MCPServer
+}
+
+// Close closes the connection to the server; the tools cannot be called afterwards
+func (c *ClientTools) Close() error {
+	return c.server.Close()
+}
+
+// NewClientTools connects to an MCP server over a transport, e.g. an mcp.CommandTransport for a
+// stdio server or an mcp.StreamableClientTransport for an HTTP server, and returns its tools.
+// Calls to the tools are forwarded to the server, and the connection stays open until the
+// returned ClientTools are closed; use ServerTools to manage the connection yourself.
+func NewClientTools(ctx context.Context, transport mcp.Transport) (*ClientTools, error) {
+	server, err := NewMCPServer(ctx, transport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
+	}
+
+	tools, err := ServerTools(ctx, server)
+	if err != nil {
+		_ = server.Close()
+		return nil, err
+	}
+	return &ClientTools{Tools: tools, server: server}, nil
+}
+
+// ServerTools lists the tools of a connected MCP server and returns them as tools forwarding
+// their calls to the server
+func ServerTools(ctx context.Context, server interfaces.MCPServer) ([]interfaces.Tool, error) {
+	mcpTools, err := server.ListTools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP tools: %w", err)
+	}
+
+	tools := make([]interfaces.Tool, 0, len(mcpTools))
+	for _, mcpTool := range mcpTools {
+		tools = append(tools, NewMCPTool(mcpTool.Name, mcpTool.Description, mcpTool.Schema, server))
+	}
+	return tools, nil
+}
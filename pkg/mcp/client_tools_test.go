@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type forecastArgs struct {
+	City string   `json:"city" jsonschema:"the city to forecast"`
+	Days int      `json:"days,omitempty" jsonschema:"the number of days"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+type forecastResult struct {
+	Forecast string `json:"forecast"`
+}
+
+func newForecastServer(t *testing.T) mcp.Transport {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "forecast", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "forecast", Description: "Forecast the weather"},
+		func(ctx context.Context, req *mcp.CallToolRequest, args forecastArgs) (*mcp.CallToolResult, forecastResult, error) {
+			if args.City == "Atlantis" {
+				return nil, forecastResult{}, errors.New("unknown city")
+			}
+			forecast := "sunny in " + args.City
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: forecast}}}, forecastResult{Forecast: forecast}, nil
+		})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return clientTransport
+}
+
+func TestNewClientTools(t *testing.T) {
+	ctx := context.Background()
+	clientTools, err := NewClientTools(ctx, newForecastServer(t))
+	if err != nil {
+		t.Fatalf("NewClientTools failed: %v", err)
+	}
+	tools := clientTools.Tools
+	if len(tools) != 1 || tools[0].Name() != "forecast" || tools[0].Description() != "Forecast the weather" {
+		t.Fatalf("Unexpected tools: %v", tools)
+	}
+
+	params := tools[0].Parameters()
+	if city := params["city"]; city.Type != "string" || !city.Required || city.Description != "the city to forecast" {
+		t.Errorf("Unexpected city parameter: %+v", city)
+	}
+	if days := params["days"]; days.Type != "integer" || days.Required {
+		t.Errorf("Unexpected days parameter: %+v", days)
+	}
+	if tags := params["tags"]; tags.Type != "array" || tags.Items == nil || tags.Items.Type != "string" {
+		t.Errorf("Unexpected tags parameter: %+v", tags)
+	}
+
+	result, err := tools[0].Execute(ctx, `{"city": "Lisbon"}`)
+	if err != nil || result != "sunny in Lisbon" {
+		t.Errorf("Expected the text content of the result, got %q, %v", result, err)
+	}
+
+	_, err = tools[0].Execute(ctx, `{"city": "Atlantis"}`)
+	if err == nil || !strings.Contains(err.Error(), "unknown city") {
+		t.Errorf("Expected the error of the tool, got %v", err)
+	}
+	// Closing disconnects the tools from the server
+	if err := clientTools.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := tools[0].Execute(ctx, `{"city": "Lisbon"}`); err == nil {
+		t.Error("Expected calls to fail after closing")
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"slices"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
-	"github.com/google/jsonschema-go/jsonschema"
 )
 
 // MCPTool implements interfaces.Tool for MCP tools
@@ -54,85 +53,114 @@ func (t *MCPTool) Internal() bool {
 func (t *MCPTool) Run(ctx context.Context, input string) (string, error) {
 	// Parse the input as JSON to get the arguments
 	var args map[string]interface{}
-	if err := json.Unmarshal([]byte(input), &args); err != nil {
-		return "", fmt.Errorf("failed to parse input as JSON: %w", err)
+	if input != "" {
+		if err := json.Unmarshal([]byte(input), &args); err != nil {
+			return "", fmt.Errorf("failed to parse input as JSON: %w", err)
+		}
 	}
 
 	// Call the tool on the MCP server
 	resp, err := t.server.CallTool(ctx, t.name, args)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to call MCP tool %s: %w", t.name, err)
 	}
 
-	// Convert the response to a string
 	if resp.IsError {
-		return "", fmt.Errorf("MCP tool error: %v", resp.Content)
-	}
-
-	// Try to convert the content to a string
-	switch content := resp.Content.(type) {
-	case string:
-		return content, nil
-	case []byte:
-		return string(content), nil
-	default:
-		// Try to JSON marshal the content
-		bytes, err := json.Marshal(content)
-		if err != nil {
-			return fmt.Sprintf("%v", content), nil
-		}
-		return string(bytes), nil
+		return "", fmt.Errorf("MCP tool error: %s", extractTextFromMCPContent(resp.Content))
 	}
+	return extractTextFromMCPContent(resp.Content), nil
 }
 
 // Parameters returns the parameters that the tool accepts
 func (t *MCPTool) Parameters() map[string]interfaces.ParameterSpec {
-	// Convert the schema to a map of ParameterSpec
-	// This is a simplified implementation; in a real implementation,
-	// we would parse the JSON schema to extract parameter information
+	return schemaParameters(t.schema)
+}
+
+// schemaParameters converts the JSON schema of the input of an MCP tool, a map or a
+// *jsonschema.Schema, to parameter specs
+func schemaParameters(schema interface{}) map[string]interfaces.ParameterSpec {
 	params := make(map[string]interfaces.ParameterSpec)
-	switch toolSchema := t.schema.(type) {
-	// For backward compatibility
-	//TODO remove in future releases
-	case map[string]interface{}:
-		// If the schema is a string, try to parse it as JSON
-		// Try to convert the schema to a map
-		if properties, ok := toolSchema["properties"].(map[string]interface{}); ok {
-			for name, prop := range properties {
-				if propMap, ok := prop.(map[string]interface{}); ok {
-					paramSpec := interfaces.ParameterSpec{
-						Type:        fmt.Sprintf("%v", propMap["type"]),
-						Description: fmt.Sprintf("%v", propMap["description"]),
-					}
-
-					// Check if the parameter is required
-					if required, ok := toolSchema["required"].([]interface{}); ok {
-						for _, req := range required {
-							if req == name {
-								paramSpec.Required = true
-								break
-							}
-						}
-					}
-
-					params[name] = paramSpec
-				}
+
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		data, err := json.Marshal(schema)
+		if err != nil || json.Unmarshal(data, &schemaMap) != nil {
+			return params
+		}
+	}
+
+	properties, _ := schemaMap["properties"].(map[string]interface{})
+	required := requiredProperties(schemaMap)
+	for name, prop := range properties {
+		if propMap, ok := prop.(map[string]interface{}); ok {
+			params[name] = schemaParameter(propMap, slices.Contains(required, name))
+		}
+	}
+	return params
+}
+
+// schemaParameter converts the JSON schema of a property to a parameter spec
+func schemaParameter(schema map[string]interface{}, required bool) interfaces.ParameterSpec {
+	spec := interfaces.ParameterSpec{
+		Type:     schemaType(schema),
+		Required: required,
+		Default:  schema["default"],
+	}
+	spec.Description, _ = schema["description"].(string)
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		spec.Enum = enum
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		item := schemaParameter(items, false)
+		spec.Items = &item
+	}
+	if _, ok := schema["properties"].(map[string]interface{}); ok {
+		spec.Properties = schemaParameters(schema)
+	}
+	return spec
+}
+
+// schemaType returns the type of a JSON schema, the first non-null one of nullable and anyOf
+// schemas, or string if it has none
+func schemaType(schema map[string]interface{}) string {
+	switch typ := schema["type"].(type) {
+	case string:
+		return typ
+	case []interface{}:
+		for _, option := range typ {
+			if name, ok := option.(string); ok && name != "null" {
+				return name
 			}
 		}
-	case *jsonschema.Schema:
-		for name, prop := range toolSchema.Properties {
-			paramSpec := interfaces.ParameterSpec{
-				Type:        prop.Type,
-				Description: prop.Description,
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, option := range anyOf {
+			if optionMap, ok := option.(map[string]interface{}); ok {
+				if name := schemaType(optionMap); name != "null" {
+					return name
+				}
 			}
+		}
+	}
+	return "string"
+}
 
-			if slices.Contains(toolSchema.Required, name) {
-				paramSpec.Required = true
+// requiredProperties returns the required properties of a JSON schema
+func requiredProperties(schema map[string]interface{}) []string {
+	var required []string
+	switch values := schema["required"].(type) {
+	case []string:
+		required = values
+	case []interface{}:
+		for _, value := range values {
+			if name, ok := value.(string); ok {
+				required = append(required, name)
 			}
-			params[name] = paramSpec
 		}
 	}
-	return params
+	return required
 }
 
 // Execute executes the tool with the given arguments
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+// CompactionReport describes a compaction of a conversation, in which messages of the history
+// were replaced by a summary
+type CompactionReport struct {
+	// ConversationID is the ID of the compacted conversation
+	ConversationID string
+	// RemovedMessages are the messages removed from the history, in order
+	RemovedMessages []interfaces.Message
+	// Summary is the content of the summary message replacing them
+	Summary string
+	// TokensBefore is the estimated number of tokens of the removed messages
+	TokensBefore int
+	// TokensAfter is the estimated number of tokens of the summary
+	TokensAfter int
+	// TokensSaved is TokensBefore minus TokensAfter
+	TokensSaved int
+	// Timestamp is the time of the compaction
+	Timestamp time.Time
+}
+
+// CompactionCallback receives the report of each compaction of a memory, e.g. to log what the
+// agent no longer sees in full
+type CompactionCallback func(ctx context.Context, report CompactionReport)
+
+// newCompactionReport builds the report of messages of the conversation of ctx replaced by a summary
+func newCompactionReport(ctx context.Context, removed []interfaces.Message, summary string) CompactionReport {
+	conversationID, _ := GetConversationID(ctx)
+
+	tokensBefore := 0
+	for _, message := range removed {
+		tokensBefore += llm.EstimateTokens(message.Content)
+	}
+	tokensAfter := llm.EstimateTokens(summary)
+
+	return CompactionReport{
+		ConversationID:  conversationID,
+		RemovedMessages: removed,
+		Summary:         summary,
+		TokensBefore:    tokensBefore,
+		TokensAfter:     tokensAfter,
+		TokensSaved:     tokensBefore - tokensAfter,
+		Timestamp:       time.Now(),
+	}
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestConversationSummaryCompactionReport(t *testing.T) {
+	mockLLM := new(MockLLM)
+	mockLLM.On("Generate", mock.Anything, mock.Anything, mock.Anything).Return("User likes Go", nil)
+
+	var reports []CompactionReport
+	var memory *ConversationSummary
+	memory = NewConversationSummary(mockLLM, WithMaxBufferSize(3), WithCompactionCallback(func(ctx context.Context, report CompactionReport) {
+		// The memory can be used from the callback
+		_, err := memory.GetMessages(ctx)
+		assert.NoError(t, err)
+		reports = append(reports, report)
+	}))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "conv-1")
+	contents := []string{strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 40)}
+	for _, content := range contents {
+		assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: content}))
+	}
+
+	assert.Len(t, reports, 1)
+	report := reports[0]
+	assert.Equal(t, "conv-1", report.ConversationID)
+	assert.Equal(t, "User likes Go", report.Summary)
+	assert.Len(t, report.RemovedMessages, 3)
+	for i, content := range contents {
+		assert.Equal(t, content, report.RemovedMessages[i].Content)
+	}
+	// Three messages of 10 tokens replaced by a summary of 4 tokens
+	assert.Equal(t, 30, report.TokensBefore)
+	assert.Equal(t, 4, report.TokensAfter)
+	assert.Equal(t, 26, report.TokensSaved)
+	assert.False(t, report.Timestamp.IsZero())
+
+	// The next summarization replaces the previous summary too
+	for _, content := range contents {
+		assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: content}))
+	}
+	assert.Len(t, reports, 2)
+	assert.Len(t, reports[1].RemovedMessages, 4)
+	assert.Equal(t, "User likes Go", reports[1].RemovedMessages[0].Content)
+	assert.Equal(t, 34, reports[1].TokensBefore)
+}
+
+func TestRedisMemoryCompactionReport(t *testing.T) {
+	client, mr := setupTestRedisClient(t)
+	defer mr.Close()
+
+	mockLLM := new(MockLLM)
+	mockLLM.On("Generate", mock.Anything, mock.Anything, mock.Anything).Return("Summary", nil)
+
+	var reports []CompactionReport
+	memory := NewRedisMemory(client,
+		WithSummarization(mockLLM, 6, 2),
+		WithRedisCompactionCallback(func(ctx context.Context, report CompactionReport) {
+			reports = append(reports, report)
+		}),
+	)
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "conv-1")
+	content := func(i int) string { return fmt.Sprintf("Message %d %s", i, strings.Repeat("x", 31)) }
+	for i := 0; i < 6; i++ {
+		assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: content(i)}))
+	}
+
+	// The 4 oldest messages are summarized, the 2 most recent kept
+	assert.Len(t, reports, 1)
+	report := reports[0]
+	assert.Len(t, report.RemovedMessages, 4)
+	for i, message := range report.RemovedMessages {
+		assert.Equal(t, content(i), message.Content)
+	}
+	assert.Equal(t, "Previous conversation summary (4 messages): Summary", report.Summary)
+	assert.Equal(t, 44, report.TokensBefore)
+	assert.Equal(t, 13, report.TokensAfter)
+	assert.Equal(t, 31, report.TokensSaved)
+}
@@ -2,6 +2,8 @@ package memory
 
 import (
 	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
 
 // Key type for context values
@@ -20,3 +22,20 @@ func GetConversationID(ctx context.Context) (string, bool) {
 	id, ok := ctx.Value(ConversationIDKey).(string)
 	return id, ok
 }
+
+// summaryLLMKey is the key used to store the summarization LLM in context
+const summaryLLMKey contextKey = "summary_llm"
+
+// WithSummaryLLM sets the LLM summarizing the conversation in context, overriding the LLM of
+// summarizing memories such as ConversationSummary. Agents set it to their utility LLM.
+func WithSummaryLLM(ctx context.Context, llm interfaces.LLM) context.Context {
+	return context.WithValue(ctx, summaryLLMKey, llm)
+}
+
+// summaryLLM returns the summarization LLM in context, or fallback if none is set
+func summaryLLM(ctx context.Context, fallback interfaces.LLM) interfaces.LLM {
+	if llm, ok := ctx.Value(summaryLLMKey).(interfaces.LLM); ok && llm != nil {
+		return llm
+	}
+	return fallback
+}
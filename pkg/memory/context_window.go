@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+// messageOverheadTokens approximates the tokens a chat API spends on the role and delimiters of
+// each message
+const messageOverheadTokens = 4
+
+// FitToWindow returns the messages that fit the context window of model, leaving reserveOutput
+// tokens for the response. The oldest non-system messages are dropped first; system messages
+// and the last message are always kept. Tool results are dropped along with the assistant
+// message calling the tool, so the history never starts with an orphaned tool result. Tokens
+// are estimated, see llm.EstimateTokens; messages are returned unchanged for models without
+// known limits, see llm.GetModelCapabilities.
+func FitToWindow(messages []interfaces.Message, model string, reserveOutput int) []interfaces.Message {
+	capabilities, ok := llm.GetModelCapabilities(model)
+	if !ok || len(messages) == 0 {
+		return messages
+	}
+	budget := capabilities.MaxInputTokens - reserveOutput
+
+	total := 0
+	for _, message := range messages {
+		total += messageTokens(message)
+	}
+	if total <= budget {
+		return messages
+	}
+
+	// Drop the oldest non-system messages, keeping the last one
+	dropped := make([]bool, len(messages))
+	last := len(messages) - 1
+	for i := 0; i < last && total > budget; i++ {
+		if messages[i].Role == "system" {
+			continue
+		}
+		dropped[i] = true
+		total -= messageTokens(messages[i])
+
+		// Drop the results of the tool calls of a dropped message
+		for i+1 < last && messages[i+1].Role == "tool" {
+			i++
+			dropped[i] = true
+			total -= messageTokens(messages[i])
+		}
+	}
+
+	fitted := make([]interfaces.Message, 0, len(messages))
+	for i, message := range messages {
+		if !dropped[i] {
+			fitted = append(fitted, message)
+		}
+	}
+	return fitted
+}
+
+// messageTokens estimates the tokens of a message as sent to a chat API
+func messageTokens(message interfaces.Message) int {
+	tokens := messageOverheadTokens + llm.EstimateTokens(message.Content)
+	for _, call := range message.ToolCalls {
+		tokens += llm.EstimateTokens(call.Name) + llm.EstimateTokens(call.Arguments)
+	}
+	return tokens
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+func TestFitToWindow(t *testing.T) {
+	llm.RegisterModelCapabilities("window-test-model", llm.ModelCapabilities{MaxInputTokens: 1000})
+
+	// Each message is about 254 tokens
+	long := strings.Repeat("a", 1000)
+	messages := []interfaces.Message{
+		{Role: "system", Content: long},
+		{Role: "user", Content: long},
+		{Role: "assistant", Content: long},
+		{Role: "user", Content: long},
+	}
+
+	fitted := FitToWindow(messages, "window-test-model", 300)
+	assert.Equal(t, []interfaces.Message{messages[0], messages[3]}, fitted)
+
+	// Messages within the window are kept
+	assert.Equal(t, messages[:3], FitToWindow(messages[:3], "window-test-model", 0))
+
+	// Unknown models are not trimmed
+	assert.Equal(t, messages, FitToWindow(messages, "unknown-model", 200))
+}
+
+func TestFitToWindowDropsToolResults(t *testing.T) {
+	llm.RegisterModelCapabilities("window-test-model", llm.ModelCapabilities{MaxInputTokens: 1000})
+
+	long := strings.Repeat("a", 1000)
+	messages := []interfaces.Message{
+		{Role: "user", Content: "What is the weather?"},
+		{Role: "assistant", ToolCalls: []interfaces.ToolCall{{ID: "call_1", Name: "weather", Arguments: `{}`}}},
+		{Role: "tool", ToolCallID: "call_1", Content: long},
+		{Role: "assistant", Content: long},
+		{Role: "user", Content: long},
+	}
+
+	// Dropping the tool call drops its result along with it
+	fitted := FitToWindow(messages, "window-test-model", 300)
+	assert.Equal(t, []interfaces.Message{messages[3], messages[4]}, fitted)
+}
+
+func TestFitToWindowKeepsLastMessage(t *testing.T) {
+	llm.RegisterModelCapabilities("window-test-model", llm.ModelCapabilities{MaxInputTokens: 1000})
+
+	messages := []interfaces.Message{
+		{Role: "user", Content: "Hello"},
+		{Role: "user", Content: strings.Repeat("a", 8000)},
+	}
+	assert.Equal(t, messages[1:], FitToWindow(messages, "window-test-model", 0))
+}
@@ -3,15 +3,25 @@ package memory
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/google/uuid"
+
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 )
 
+// DefaultMaxMessages is the hard cap on the number of messages kept per conversation by Redis
+// memories configured without one, so that long-lived conversations cannot grow without bound.
+// Setting it to 0 before creating a memory disables the default cap.
+var DefaultMaxMessages = 1000
+
 // ConversationBuffer implements a simple in-memory conversation buffer
 type ConversationBuffer struct {
 	messages map[string][]interfaces.Message
+	metadata map[string]map[string]interface{}
 	maxSize  int
 	mu       sync.RWMutex
 }
@@ -19,7 +29,8 @@ type ConversationBuffer struct {
 // Option represents an option for configuring the conversation buffer
 type Option func(*ConversationBuffer)
 
-// WithMaxSize sets the maximum number of messages to store
+// WithMaxSize sets the maximum number of messages to store per conversation (default: 100). The
+// oldest messages are evicted beyond it. A size of 0 or less keeps all messages.
 func WithMaxSize(size int) Option {
 	return func(c *ConversationBuffer) {
 		c.maxSize = size
@@ -30,6 +41,7 @@ func WithMaxSize(size int) Option {
 func NewConversationBuffer(options ...Option) *ConversationBuffer {
 	buffer := &ConversationBuffer{
 		messages: make(map[string][]interfaces.Message),
+		metadata: make(map[string]map[string]interface{}),
 		maxSize:  100, // Default max size
 	}
 
@@ -52,6 +64,7 @@ func (c *ConversationBuffer) AddMessage(ctx context.Context, message interfaces.
 	}
 
 	// Add message to buffer
+	ensureMessageID(&message)
 	c.messages[conversationID] = append(c.messages[conversationID], message)
 
 	// Trim buffer if it exceeds max size
@@ -85,30 +98,95 @@ func (c *ConversationBuffer) GetMessages(ctx context.Context, options ...interfa
 		option(opts)
 	}
 
-	// Filter by role if specified
-	if len(opts.Roles) > 0 {
-		var filtered []interfaces.Message
-		for _, msg := range messages {
-			for _, role := range opts.Roles {
-				if msg.Role == role {
-					filtered = append(filtered, msg)
-					break
-				}
-			}
+	return selectMessages(messages, opts)
+}
+
+// Clear clears the buffer for a conversation
+func (c *ConversationBuffer) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Get conversation ID from context
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Clear messages and metadata for conversation
+	delete(c.messages, conversationID)
+	delete(c.metadata, conversationID)
+
+	return nil
+}
+
+// DeleteMessage removes a message from the buffer
+func (c *ConversationBuffer) DeleteMessage(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Get conversation ID from context
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	messages := c.messages[conversationID]
+	for i, msg := range messages {
+		if msg.ID == id {
+			c.messages[conversationID] = append(messages[:i:i], messages[i+1:]...)
+			return nil
 		}
-		messages = filtered
 	}
 
-	// Apply limit if specified
-	if opts.Limit > 0 && opts.Limit < len(messages) {
-		messages = messages[len(messages)-opts.Limit:]
+	return fmt.Errorf("%w: %s", interfaces.ErrMessageNotFound, id)
+}
+
+// UpdateMessage replaces the content of a message in the buffer
+func (c *ConversationBuffer) UpdateMessage(ctx context.Context, id string, content string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Get conversation ID from context
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return err
 	}
 
-	return messages, nil
+	messages := c.messages[conversationID]
+	for i, msg := range messages {
+		if msg.ID == id {
+			// Copy on write, since GetMessages hands out the stored slice
+			updated := append([]interfaces.Message(nil), messages...)
+			updated[i].Content = content
+			c.messages[conversationID] = updated
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", interfaces.ErrMessageNotFound, id)
 }
 
-// Clear clears the buffer for a conversation
-func (c *ConversationBuffer) Clear(ctx context.Context) error {
+// GetConversationMetadata returns a copy of the metadata for a conversation
+func (c *ConversationBuffer) GetConversationMetadata(ctx context.Context) (map[string]interface{}, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Get conversation ID from context
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]interface{}, len(c.metadata[conversationID]))
+	for key, value := range c.metadata[conversationID] {
+		metadata[key] = value
+	}
+
+	return metadata, nil
+}
+
+// SetConversationMetadata sets a metadata value for a conversation
+func (c *ConversationBuffer) SetConversationMetadata(ctx context.Context, key string, value interface{}) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -118,12 +196,45 @@ func (c *ConversationBuffer) Clear(ctx context.Context) error {
 		return err
 	}
 
-	// Clear messages for conversation
-	delete(c.messages, conversationID)
+	if c.metadata[conversationID] == nil {
+		c.metadata[conversationID] = make(map[string]interface{})
+	}
+	c.metadata[conversationID][key] = value
 
 	return nil
 }
 
+// ListConversations returns the IDs of the conversations of the organization in context
+func (c *ConversationBuffer) ListConversations(ctx context.Context) ([]string, error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("organization ID not found in context: %w", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	prefix := orgID + ":"
+	seen := make(map[string]bool)
+	var conversationIDs []string
+	add := func(key string) {
+		conversationID, ok := strings.CutPrefix(key, prefix)
+		if ok && !seen[conversationID] {
+			seen[conversationID] = true
+			conversationIDs = append(conversationIDs, conversationID)
+		}
+	}
+	for key := range c.messages {
+		add(key)
+	}
+	for key := range c.metadata {
+		add(key)
+	}
+	sort.Strings(conversationIDs)
+
+	return conversationIDs, nil
+}
+
 // Helper function to get conversation ID from context
 func getConversationID(ctx context.Context) (string, error) {
 	// Get organization ID from context
@@ -141,3 +252,74 @@ func getConversationID(ctx context.Context) (string, error) {
 	// Combine organization ID and conversation ID
 	return fmt.Sprintf("%s:%s", orgID, conversationID), nil
 }
+
+// ensureMessageID assigns a unique ID to a message that doesn't have one
+func ensureMessageID(message *interfaces.Message) {
+	if message.ID == "" {
+		message.ID = uuid.New().String()
+	}
+}
+
+// selectMessages applies the cursors, role filter, offset and limit of opts to messages in
+// chronological order. The cursors are looked up among all messages, the limit keeps the most
+// recent messages.
+func selectMessages(messages []interfaces.Message, opts *interfaces.GetMessagesOptions) ([]interfaces.Message, error) {
+	start, end := 0, len(messages)
+	if opts.After != "" {
+		index := messageIndex(messages, opts.After)
+		if index < 0 {
+			return nil, fmt.Errorf("message %s not found", opts.After)
+		}
+		start = index + 1
+	}
+	if opts.Before != "" {
+		index := messageIndex(messages, opts.Before)
+		if index < 0 {
+			return nil, fmt.Errorf("message %s not found", opts.Before)
+		}
+		end = index
+	}
+	if end <= start {
+		return []interfaces.Message{}, nil
+	}
+	messages = messages[start:end]
+
+	// Filter by role if specified
+	if len(opts.Roles) > 0 {
+		var filtered []interfaces.Message
+		for _, msg := range messages {
+			for _, role := range opts.Roles {
+				if msg.Role == role {
+					filtered = append(filtered, msg)
+					break
+				}
+			}
+		}
+		messages = filtered
+	}
+
+	// Skip the most recent messages if an offset is specified
+	if opts.Offset > 0 {
+		if opts.Offset >= len(messages) {
+			return []interfaces.Message{}, nil
+		}
+		messages = messages[:len(messages)-opts.Offset]
+	}
+
+	// Apply limit if specified
+	if opts.Limit > 0 && opts.Limit < len(messages) {
+		messages = messages[len(messages)-opts.Limit:]
+	}
+
+	return messages, nil
+}
+
+// messageIndex returns the index of the message with the given ID, or -1
+func messageIndex(messages []interfaces.Message, id string) int {
+	for i, msg := range messages {
+		if msg.ID == id {
+			return i
+		}
+	}
+	return -1
+}
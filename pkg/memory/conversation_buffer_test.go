@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// testEditMessages exercises message IDs, DeleteMessage and UpdateMessage on an empty conversation
+func testEditMessages(t *testing.T, mem interfaces.Memory, ctx context.Context) {
+	t.Helper()
+
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "Hello"}))
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{ID: "custom", Role: "assistant", Content: "Hi"}))
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "My card is 4111"}))
+
+	messages, err := mem.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	assert.NotEmpty(t, messages[0].ID)
+	assert.Equal(t, "custom", messages[1].ID)
+	assert.NotEqual(t, messages[0].ID, messages[2].ID)
+
+	require.NoError(t, mem.UpdateMessage(ctx, messages[2].ID, "My card is [REDACTED]"))
+	require.NoError(t, mem.DeleteMessage(ctx, "custom"))
+
+	got, err := mem.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, messages[0], got[0])
+	assert.Equal(t, messages[2].ID, got[1].ID)
+	assert.Equal(t, "My card is [REDACTED]", got[1].Content)
+
+	assert.True(t, errors.Is(mem.DeleteMessage(ctx, "custom"), interfaces.ErrMessageNotFound))
+	assert.True(t, errors.Is(mem.UpdateMessage(ctx, "missing", "x"), interfaces.ErrMessageNotFound))
+}
+
+func TestConversationBufferEditMessages(t *testing.T) {
+	ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+	buffer := NewConversationBuffer()
+
+	testEditMessages(t, buffer, ctx)
+
+	// Messages of other conversations can't be edited
+	other := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv2")
+	messages, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(buffer.DeleteMessage(other, messages[0].ID), interfaces.ErrMessageNotFound))
+}
+
+// testPaginateMessages exercises the offset and cursor options of GetMessages on an empty conversation
+func testPaginateMessages(t *testing.T, mem interfaces.Memory, ctx context.Context) {
+	t.Helper()
+
+	for i := 1; i <= 7; i++ {
+		role := "user"
+		if i%2 == 0 {
+			role = "assistant"
+		}
+		require.NoError(t, mem.AddMessage(ctx, interfaces.Message{ID: fmt.Sprintf("m%d", i), Role: role, Content: fmt.Sprintf("message %d", i)}))
+	}
+
+	ids := func(options ...interfaces.GetMessagesOption) []string {
+		messages, err := mem.GetMessages(ctx, options...)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, message := range messages {
+			ids = append(ids, message.ID)
+		}
+		return ids
+	}
+
+	// Pages of older messages by offset
+	assert.Equal(t, []string{"m5", "m6", "m7"}, ids(interfaces.WithLimit(3)))
+	assert.Equal(t, []string{"m2", "m3", "m4"}, ids(interfaces.WithLimit(3), interfaces.WithOffset(3)))
+	assert.Equal(t, []string{"m1"}, ids(interfaces.WithLimit(3), interfaces.WithOffset(6)))
+	assert.Equal(t, []string{}, ids(interfaces.WithLimit(3), interfaces.WithOffset(7)))
+	assert.Equal(t, []string{"m1", "m2", "m3", "m4", "m5"}, ids(interfaces.WithOffset(2)))
+
+	// Pages of older messages by cursor
+	assert.Equal(t, []string{"m2", "m3", "m4"}, ids(interfaces.WithLimit(3), interfaces.WithBefore("m5")))
+	assert.Equal(t, []string{"m1"}, ids(interfaces.WithLimit(3), interfaces.WithBefore("m2")))
+	assert.Equal(t, []string{"m6", "m7"}, ids(interfaces.WithAfter("m5")))
+	assert.Equal(t, []string{"m3", "m4"}, ids(interfaces.WithAfter("m2"), interfaces.WithBefore("m5")))
+	assert.Equal(t, []string{}, ids(interfaces.WithAfter("m5"), interfaces.WithBefore("m2")))
+
+	// Cursors combine with the role filter, even on messages of other roles
+	assert.Equal(t, []string{"m3", "m5"}, ids(interfaces.WithRoles("user"), interfaces.WithBefore("m6"), interfaces.WithLimit(2)))
+	assert.Equal(t, []string{"m1"}, ids(interfaces.WithRoles("user"), interfaces.WithBefore("m6"), interfaces.WithLimit(2), interfaces.WithOffset(2)))
+
+	_, err := mem.GetMessages(ctx, interfaces.WithAfter("missing"))
+	assert.Error(t, err)
+}
+
+func TestConversationBufferPaginateMessages(t *testing.T) {
+	ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+	testPaginateMessages(t, NewConversationBuffer(), ctx)
+}
+
+func TestConversationBufferMaxSize(t *testing.T) {
+	ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+	buffer := NewConversationBuffer(WithMaxSize(3))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: "user", Content: string(rune('A' + i))}))
+	}
+
+	// The oldest messages are evicted
+	messages, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	assert.Equal(t, []string{"H", "I", "J"}, []string{messages[0].Content, messages[1].Content, messages[2].Content})
+}
+
+func TestConversationBufferUnbounded(t *testing.T) {
+	defer func(previous int) { DefaultMaxMessages = previous }(DefaultMaxMessages)
+	DefaultMaxMessages = 5
+
+	ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+	buffer := NewConversationBuffer(WithMaxSize(0))
+
+	for i := 0; i < 50; i++ {
+		require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: "user", Content: "message"}))
+	}
+
+	// A size of 0 keeps all messages, regardless of DefaultMaxMessages
+	messages, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Len(t, messages, 50)
+}
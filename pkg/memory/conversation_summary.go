@@ -6,16 +6,25 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/guardrails"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
 
+// defaultMaxBufferSize is the maximum number of messages before summarizing, unless a
+// message or token limit is set
+const defaultMaxBufferSize = 10
+
 // ConversationSummary implements a memory that summarizes old messages
 type ConversationSummary struct {
 	buffer          *ConversationBuffer
 	llmClient       interfaces.LLM
 	maxBufferSize   int
+	maxBufferTokens int
+	tokenCounter    guardrails.TokenCounter
 	summaryMessages map[string]interfaces.Message
 	summaryParams   map[string]interface{}
+	metadata        map[string]map[string]interface{}
+	onCompaction    CompactionCallback
 	mu              sync.RWMutex
 }
 
@@ -29,6 +38,24 @@ func WithMaxBufferSize(size int) SummaryOption {
 	}
 }
 
+// WithMaxBufferTokens sets the maximum number of tokens of the buffered messages before
+// summarizing, so that a few long messages cannot overflow the context window. Without
+// WithMaxBufferSize, the buffered messages are also summarized once they reach the cap of 100
+// messages of the buffer. Tokens are counted with the counter set by WithTokenCounter, by
+// default the guardrails.SimpleTokenCounter.
+func WithMaxBufferTokens(n int) SummaryOption {
+	return func(c *ConversationSummary) {
+		c.maxBufferTokens = n
+	}
+}
+
+// WithTokenCounter sets the counter of the tokens of the buffered messages, see WithMaxBufferTokens
+func WithTokenCounter(counter guardrails.TokenCounter) SummaryOption {
+	return func(c *ConversationSummary) {
+		c.tokenCounter = counter
+	}
+}
+
 // WithSummaryLength sets the maximum word count target for summaries
 func WithSummaryLength(wordCount int) SummaryOption {
 	return func(c *ConversationSummary) {
@@ -40,50 +67,92 @@ func WithSummaryLength(wordCount int) SummaryOption {
 	}
 }
 
+// WithCompactionCallback sets a callback receiving the report of each summarization
+func WithCompactionCallback(callback CompactionCallback) SummaryOption {
+	return func(c *ConversationSummary) {
+		c.onCompaction = callback
+	}
+}
+
 // NewConversationSummary creates a new conversation summary memory
 func NewConversationSummary(llmClient interfaces.LLM, options ...SummaryOption) *ConversationSummary {
 	summary := &ConversationSummary{
 		buffer:          NewConversationBuffer(),
 		llmClient:       llmClient,
-		maxBufferSize:   10, // Default max buffer size
 		summaryMessages: make(map[string]interfaces.Message),
 		summaryParams:   make(map[string]interface{}),
+		metadata:        make(map[string]map[string]interface{}),
 	}
 
 	for _, option := range options {
 		option(summary)
 	}
 
+	// The default message limit only applies without a token limit
+	if summary.maxBufferSize == 0 && summary.maxBufferTokens <= 0 {
+		summary.maxBufferSize = defaultMaxBufferSize
+	}
+	if summary.tokenCounter == nil {
+		summary.tokenCounter = &guardrails.SimpleTokenCounter{}
+	}
+
 	return summary
 }
 
 // AddMessage adds a message to the memory
 func (c *ConversationSummary) AddMessage(ctx context.Context, message interfaces.Message) error {
+	report, err := c.addMessage(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	// Report the summarization outside the lock, so that the callback can use the memory
+	if report != nil && c.onCompaction != nil {
+		c.onCompaction(ctx, *report)
+	}
+
+	return nil
+}
+
+// addMessage adds a message to the memory, summarizing the buffer when it is full. It returns
+// the report of the summarization, if any.
+func (c *ConversationSummary) addMessage(ctx context.Context, message interfaces.Message) (*CompactionReport, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Add message to buffer
 	if err := c.buffer.AddMessage(ctx, message); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get conversation ID
 	conversationID, err := getConversationID(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Check if we need to summarize
 	messages, err := c.buffer.GetMessages(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(messages) >= c.maxBufferSize {
+	full, err := c.bufferFull(messages)
+	if err != nil {
+		return nil, err
+	}
+
+	if full {
 		// Summarize messages
 		summary, err := c.summarize(ctx, messages)
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		// The previous summary is replaced as well
+		removed := messages
+		if previous, ok := c.summaryMessages[conversationID]; ok {
+			removed = append([]interfaces.Message{previous}, messages...)
 		}
 
 		// Store summary
@@ -98,11 +167,39 @@ func (c *ConversationSummary) AddMessage(ctx context.Context, message interfaces
 
 		// Clear buffer
 		if err := c.buffer.Clear(ctx); err != nil {
-			return err
+			return nil, err
 		}
+
+		report := newCompactionReport(ctx, removed, summary)
+		return &report, nil
 	}
 
-	return nil
+	return nil, nil
+}
+
+// bufferFull reports whether the buffered messages reach the message limit or the cap of the
+// buffer, or exceed the token limit
+func (c *ConversationSummary) bufferFull(messages []interfaces.Message) (bool, error) {
+	if c.maxBufferSize > 0 && len(messages) >= c.maxBufferSize {
+		return true, nil
+	}
+	// Summarize at the cap of the buffer rather than let it evict messages
+	if c.buffer.maxSize > 0 && len(messages) >= c.buffer.maxSize {
+		return true, nil
+	}
+	if c.maxBufferTokens <= 0 {
+		return false, nil
+	}
+
+	tokens := 0
+	for _, message := range messages {
+		count, err := c.tokenCounter.CountTokens(message.Content)
+		if err != nil {
+			return false, fmt.Errorf("failed to count tokens: %w", err)
+		}
+		tokens += count
+	}
+	return tokens > c.maxBufferTokens, nil
 }
 
 // GetMessages retrieves messages from the memory
@@ -151,8 +248,64 @@ func (c *ConversationSummary) Clear(ctx context.Context) error {
 		return err
 	}
 
-	// Clear summary
+	// Clear summary and metadata
 	delete(c.summaryMessages, conversationID)
+	delete(c.metadata, conversationID)
+
+	return nil
+}
+
+// DeleteMessage removes a message that has not been summarized yet
+func (c *ConversationSummary) DeleteMessage(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.buffer.DeleteMessage(ctx, id)
+}
+
+// UpdateMessage replaces the content of a message that has not been summarized yet
+func (c *ConversationSummary) UpdateMessage(ctx context.Context, id string, content string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.buffer.UpdateMessage(ctx, id, content)
+}
+
+// GetConversationMetadata returns a copy of the metadata for a conversation.
+// Metadata is kept separately from the buffer so it survives summarization.
+func (c *ConversationSummary) GetConversationMetadata(ctx context.Context) (map[string]interface{}, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Get conversation ID
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]interface{}, len(c.metadata[conversationID]))
+	for key, value := range c.metadata[conversationID] {
+		metadata[key] = value
+	}
+
+	return metadata, nil
+}
+
+// SetConversationMetadata sets a metadata value for a conversation
+func (c *ConversationSummary) SetConversationMetadata(ctx context.Context, key string, value interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Get conversation ID
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.metadata[conversationID] == nil {
+		c.metadata[conversationID] = make(map[string]interface{})
+	}
+	c.metadata[conversationID][key] = value
 
 	return nil
 }
@@ -177,7 +330,7 @@ func (c *ConversationSummary) summarize(ctx context.Context, messages []interfac
 	sb.WriteString("\nSummary:")
 
 	// Generate summary with default options instead of nil
-	summary, err := c.llmClient.Generate(ctx, sb.String(), func(o *interfaces.GenerateOptions) {
+	summary, err := summaryLLM(ctx, c.llmClient).Generate(ctx, sb.String(), func(o *interfaces.GenerateOptions) {
 		o.LLMConfig.Temperature = 0.7
 	})
 	if err != nil {
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestConversationSummaryMaxBufferTokens(t *testing.T) {
+	mockLLM := new(MockLLM)
+	mockLLM.On("Generate", mock.Anything, mock.Anything, mock.Anything).Return("Long discussion about Go", nil)
+
+	memory := NewConversationSummary(mockLLM, WithMaxBufferTokens(100))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "conv-1")
+
+	// Short messages stay below the token limit, even beyond the default message limit
+	for i := 0; i < 12; i++ {
+		assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: "hi there"}))
+	}
+	messages, err := memory.GetMessages(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 12)
+	mockLLM.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything, mock.Anything)
+
+	// A single long message pushes the buffer over the token limit
+	long := strings.TrimSpace(strings.Repeat("word ", 90))
+	assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: long}))
+
+	messages, err = memory.GetMessages(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "Long discussion about Go", messages[0].Content)
+	assert.Equal(t, 13, messages[0].Metadata["count"])
+}
+
+func TestConversationSummaryMaxBufferSizeAndTokens(t *testing.T) {
+	mockLLM := new(MockLLM)
+	mockLLM.On("Generate", mock.Anything, mock.Anything, mock.Anything).Return("Summary", nil)
+
+	// Whichever limit is reached first triggers the summarization
+	memory := NewConversationSummary(mockLLM, WithMaxBufferSize(3), WithMaxBufferTokens(1000))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "conv-1")
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: "hi"}))
+	}
+
+	messages, err := memory.GetMessages(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "Summary", messages[0].Content)
+}
+
+// failingCounter is a token counter that always fails
+type failingCounter struct{}
+
+func (f *failingCounter) CountTokens(text string) (int, error) {
+	return 0, errors.New("tokenizer unavailable")
+}
+
+func TestConversationSummaryTokenCounter(t *testing.T) {
+	memory := NewConversationSummary(new(MockLLM), WithMaxBufferTokens(100), WithTokenCounter(&failingCounter{}))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "conv-1")
+	err := memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: "hi"})
+	assert.ErrorContains(t, err, "tokenizer unavailable")
+}
+
+func TestConversationSummaryBufferCap(t *testing.T) {
+	mockLLM := new(MockLLM)
+	mockLLM.On("Generate", mock.Anything, mock.Anything, mock.Anything).Return("Small talk", nil)
+
+	memory := NewConversationSummary(mockLLM, WithMaxBufferTokens(1000000))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "conv-1")
+
+	// Short messages never reach the token limit, so the cap of the buffer triggers the
+	// summarization instead of evicting messages
+	for i := 0; i < 150; i++ {
+		assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: "hi"}))
+	}
+
+	messages, err := memory.GetMessages(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 51)
+	assert.Equal(t, "Small talk", messages[0].Content)
+	assert.Equal(t, 100, messages[0].Metadata["count"])
+	mockLLM.AssertNumberOfCalls(t, "Generate", 1)
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+// Message metadata keys of the token usage and cost of the LLM calls that produced a message,
+// aggregated by ConversationCost. Agents record them on the responses they store.
+const (
+	ModelMetadataKey        = "model"
+	InputTokensMetadataKey  = "input_tokens"
+	OutputTokensMetadataKey = "output_tokens"
+	CostMetadataKey         = "cost_usd"
+)
+
+// UsageTotals is the token usage and cost of a set of messages
+type UsageTotals struct {
+	// Messages is the number of messages carrying usage metadata
+	Messages int
+	// InputTokens is the number of input tokens
+	InputTokens int64
+	// OutputTokens is the number of output tokens
+	OutputTokens int64
+	// CostUSD is the cost in USD
+	CostUSD float64
+}
+
+// CostReport is the token usage and cost of a conversation
+type CostReport struct {
+	// Total is the usage of the whole conversation
+	Total UsageTotals
+	// ByRole is the usage by message role
+	ByRole map[string]UsageTotals
+	// ByModel is the usage by model, for messages naming their model
+	ByModel map[string]UsageTotals
+	// UnpricedModels lists the models of messages without a cost annotation whose price is
+	// unknown; their tokens are counted but not their cost
+	UnpricedModels []string
+}
+
+// ConversationCost aggregates the token usage and cost stored in the metadata of the messages of
+// the conversation in context, see the *MetadataKey constants. Messages without a cost
+// annotation are priced from their model and tokens with llm.LookupModelPrice, and messages
+// without usage metadata are ignored.
+func ConversationCost(ctx context.Context, mem interfaces.Memory) (CostReport, error) {
+	report := CostReport{
+		ByRole:  make(map[string]UsageTotals),
+		ByModel: make(map[string]UsageTotals),
+	}
+	if mem == nil {
+		return report, fmt.Errorf("memory is required")
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	unpriced := make(map[string]bool)
+	for _, message := range messages {
+		usage, ok := messageUsage(message.Metadata)
+		if !ok {
+			continue
+		}
+
+		model, _ := message.Metadata[ModelMetadataKey].(string)
+		if _, hasCost := message.Metadata[CostMetadataKey]; !hasCost && model != "" {
+			if price, known := llm.LookupModelPrice(model); known {
+				usage.CostUSD = price.Cost(usage.InputTokens, usage.OutputTokens)
+			} else {
+				unpriced[model] = true
+			}
+		}
+
+		report.Total = report.Total.add(usage)
+		report.ByRole[message.Role] = report.ByRole[message.Role].add(usage)
+		if model != "" {
+			report.ByModel[model] = report.ByModel[model].add(usage)
+		}
+	}
+
+	for model := range unpriced {
+		report.UnpricedModels = append(report.UnpricedModels, model)
+	}
+	sort.Strings(report.UnpricedModels)
+
+	return report, nil
+}
+
+func (u UsageTotals) add(other UsageTotals) UsageTotals {
+	return UsageTotals{
+		Messages:     u.Messages + other.Messages,
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+		CostUSD:      u.CostUSD + other.CostUSD,
+	}
+}
+
+// messageUsage reads the usage metadata of a message, reporting whether it has any
+func messageUsage(metadata map[string]interface{}) (UsageTotals, bool) {
+	input, hasInput := metadataNumber(metadata[InputTokensMetadataKey])
+	output, hasOutput := metadataNumber(metadata[OutputTokensMetadataKey])
+	cost, hasCost := metadataNumber(metadata[CostMetadataKey])
+	if !hasInput && !hasOutput && !hasCost {
+		return UsageTotals{}, false
+	}
+
+	return UsageTotals{
+		Messages:     1,
+		InputTokens:  int64(input),
+		OutputTokens: int64(output),
+		CostUSD:      cost,
+	}, true
+}
+
+// metadataNumber converts a numeric metadata value; memories persisting messages as JSON return
+// numbers as float64
+func metadataNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestConversationCost(t *testing.T) {
+	for name, mem := range map[string]interfaces.Memory{
+		"buffer": NewConversationBuffer(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+			ctx = WithConversationID(ctx, "test-conversation")
+
+			messages := []interfaces.Message{
+				{Role: "user", Content: "Summarize the report"},
+				{Role: "assistant", Content: "The report says...", Metadata: map[string]interface{}{
+					ModelMetadataKey:        "gpt-4o",
+					InputTokensMetadataKey:  1000,
+					OutputTokensMetadataKey: 200,
+					CostMetadataKey:         0.5,
+				}},
+				// Priced from the model, at 2.50 and 10 USD per million tokens
+				{Role: "assistant", Content: "In short...", Metadata: map[string]interface{}{
+					ModelMetadataKey:        "gpt-4o-2024-08-06",
+					InputTokensMetadataKey:  int64(2000),
+					OutputTokensMetadataKey: int64(100),
+				}},
+				{Role: "tool", Content: "42", Metadata: map[string]interface{}{
+					ModelMetadataKey:        "custom-model",
+					InputTokensMetadataKey:  300,
+					OutputTokensMetadataKey: 50,
+				}},
+			}
+			for _, message := range messages {
+				require.NoError(t, mem.AddMessage(ctx, message))
+			}
+
+			report, err := ConversationCost(ctx, mem)
+			require.NoError(t, err)
+
+			assert.Equal(t, 3, report.Total.Messages)
+			assert.Equal(t, int64(1000+2000+300), report.Total.InputTokens)
+			assert.Equal(t, int64(200+100+50), report.Total.OutputTokens)
+			assert.InDelta(t, 0.5+0.006, report.Total.CostUSD, 1e-9)
+
+			assert.Len(t, report.ByRole, 2)
+			assert.Equal(t, 2, report.ByRole["assistant"].Messages)
+			assert.Equal(t, int64(3000), report.ByRole["assistant"].InputTokens)
+			assert.InDelta(t, 0.506, report.ByRole["assistant"].CostUSD, 1e-9)
+			assert.Equal(t, UsageTotals{Messages: 1, InputTokens: 300, OutputTokens: 50}, report.ByRole["tool"])
+
+			assert.Len(t, report.ByModel, 3)
+			assert.Equal(t, int64(200), report.ByModel["gpt-4o"].OutputTokens)
+			assert.InDelta(t, 0.006, report.ByModel["gpt-4o-2024-08-06"].CostUSD, 1e-9)
+			assert.Equal(t, []string{"custom-model"}, report.UnpricedModels)
+		})
+	}
+}
+
+func TestConversationCostWithoutUsage(t *testing.T) {
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "test-conversation")
+
+	mem := NewConversationBuffer()
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "Hello"}))
+
+	report, err := ConversationCost(ctx, mem)
+	require.NoError(t, err)
+	assert.Equal(t, UsageTotals{}, report.Total)
+	assert.Empty(t, report.ByRole)
+	assert.Empty(t, report.UnpricedModels)
+
+	_, err = ConversationCost(ctx, nil)
+	assert.Error(t, err)
+}
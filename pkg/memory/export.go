@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ExportedMessage is a message in the export format of ExportMessages, an OpenAI chat
+// message extended with the message ID and metadata. Consumers of the OpenAI API can send
+// the exported array as is after dropping the id and metadata keys.
+type ExportedMessage struct {
+	ID         string                 `json:"id,omitempty"`
+	Role       string                 `json:"role"`
+	Content    string                 `json:"content"`
+	ToolCalls  []ExportedToolCall     `json:"tool_calls,omitempty"`
+	ToolCallID string                 `json:"tool_call_id,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ExportedToolCall is a tool call in the OpenAI chat format
+type ExportedToolCall struct {
+	ID       string               `json:"id"`
+	Type     string               `json:"type"`
+	Function ExportedToolFunction `json:"function"`
+}
+
+// ExportedToolFunction is the function called by an exported tool call
+type ExportedToolFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ExportMessages serializes all messages of the conversation in context as a JSON array of
+// ExportedMessage, compatible with the OpenAI chat messages array
+func ExportMessages(ctx context.Context, mem interfaces.Memory) ([]byte, error) {
+	if mem == nil {
+		return nil, fmt.Errorf("memory is required")
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	exported := make([]ExportedMessage, 0, len(messages))
+	for _, message := range messages {
+		exported = append(exported, exportMessage(message))
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal messages: %w", err)
+	}
+	return data, nil
+}
+
+// exportMessage converts a message to the export format
+func exportMessage(message interfaces.Message) ExportedMessage {
+	e := ExportedMessage{
+		ID:         message.ID,
+		Role:       message.Role,
+		Content:    message.Content,
+		ToolCallID: message.ToolCallID,
+		Metadata:   message.Metadata,
+	}
+	for _, toolCall := range message.ToolCalls {
+		e.ToolCalls = append(e.ToolCalls, ExportedToolCall{
+			ID:   toolCall.ID,
+			Type: "function",
+			Function: ExportedToolFunction{
+				Name:      toolCall.Name,
+				Arguments: toolCall.Arguments,
+			},
+		})
+	}
+	return e
+}
+
+// ImportMessages adds the messages of a JSON array produced by ExportMessages, or a plain
+// OpenAI chat messages array, to the conversation in context. Messages keep their exported
+// IDs; messages without one get a new ID. The data is validated before any message is added.
+func ImportMessages(ctx context.Context, mem interfaces.Memory, data []byte) error {
+	if mem == nil {
+		return fmt.Errorf("memory is required")
+	}
+
+	var exported []ExportedMessage
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return fmt.Errorf("failed to parse messages: %w", err)
+	}
+
+	messages := make([]interfaces.Message, 0, len(exported))
+	for i, e := range exported {
+		if e.Role == "" {
+			return fmt.Errorf("message %d has no role", i)
+		}
+
+		message := interfaces.Message{
+			ID:         e.ID,
+			Role:       e.Role,
+			Content:    e.Content,
+			ToolCallID: e.ToolCallID,
+			Metadata:   e.Metadata,
+		}
+		for _, toolCall := range e.ToolCalls {
+			if toolCall.Type != "" && toolCall.Type != "function" {
+				return fmt.Errorf("message %d has an unsupported tool call type %q", i, toolCall.Type)
+			}
+			message.ToolCalls = append(message.ToolCalls, interfaces.ToolCall{
+				ID:        toolCall.ID,
+				Name:      toolCall.Function.Name,
+				Arguments: toolCall.Function.Arguments,
+			})
+		}
+		messages = append(messages, message)
+	}
+
+	for i, message := range messages {
+		if err := mem.AddMessage(ctx, message); err != nil {
+			return fmt.Errorf("failed to import message %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestExportImportMessages(t *testing.T) {
+	ctx := searchTestContext("conv1")
+	buffer := NewConversationBuffer()
+
+	messages := []interfaces.Message{
+		{ID: "m1", Role: "system", Content: "You are a weather bot"},
+		{ID: "m2", Role: "user", Content: "Weather in Paris?", Metadata: map[string]interface{}{"channel": "web", "priority": float64(2)}},
+		{ID: "m3", Role: "assistant", ToolCalls: []interfaces.ToolCall{
+			{ID: "call_1", Name: "get_weather", Arguments: `{"location":"Paris"}`},
+		}},
+		{ID: "m4", Role: "tool", Content: "Sunny", ToolCallID: "call_1"},
+		{ID: "m5", Role: "assistant", Content: "It is sunny in Paris."},
+	}
+	for _, message := range messages {
+		require.NoError(t, buffer.AddMessage(ctx, message))
+	}
+
+	data, err := ExportMessages(ctx, buffer)
+	require.NoError(t, err)
+
+	// The export is an OpenAI chat messages array
+	var chat []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &chat))
+	require.Len(t, chat, 5)
+	assert.Equal(t, "assistant", chat[2]["role"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"id":       "call_1",
+		"type":     "function",
+		"function": map[string]interface{}{"name": "get_weather", "arguments": `{"location":"Paris"}`},
+	}}, chat[2]["tool_calls"])
+	assert.Equal(t, "call_1", chat[3]["tool_call_id"])
+
+	// Reload the snapshot into another memory backend
+	client, mr := setupTestRedisClient(t)
+	defer mr.Close()
+	redisMemory := NewRedisMemory(client)
+
+	require.NoError(t, ImportMessages(ctx, redisMemory, data))
+
+	imported, err := redisMemory.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, messages, imported)
+}
+
+func TestImportOpenAIMessages(t *testing.T) {
+	ctx := searchTestContext("conv1")
+	buffer := NewConversationBuffer()
+
+	data := []byte(`[
+		{"role": "user", "content": "Hi"},
+		{"role": "assistant", "content": null, "tool_calls": [{"id": "call_9", "type": "function", "function": {"name": "lookup", "arguments": "{}"}}]},
+		{"role": "tool", "tool_call_id": "call_9", "content": "found"}
+	]`)
+	require.NoError(t, ImportMessages(ctx, buffer, data))
+
+	imported, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, imported, 3)
+	assert.NotEmpty(t, imported[0].ID)
+	assert.Equal(t, "", imported[1].Content)
+	assert.Equal(t, []interfaces.ToolCall{{ID: "call_9", Name: "lookup", Arguments: "{}"}}, imported[1].ToolCalls)
+	assert.Equal(t, "call_9", imported[2].ToolCallID)
+
+	// Invalid data is rejected without importing anything
+	other := NewConversationBuffer()
+	assert.Error(t, ImportMessages(ctx, other, []byte(`{"role": "user"}`)))
+	assert.Error(t, ImportMessages(ctx, other, []byte(`[{"role": "user", "content": "Hi"}, {"content": "no role"}]`)))
+	assert.Error(t, ImportMessages(ctx, other, []byte(`[{"role": "assistant", "tool_calls": [{"id": "c", "type": "custom"}]}]`)))
+	empty, err := other.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
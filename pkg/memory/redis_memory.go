@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,6 +23,7 @@ type RedisMemory struct {
 	compressionEnabled bool
 	encryptionKey      []byte
 	maxMessageSize     int
+	maxMessages        int
 	retryOptions       *RetryOptions
 
 	// Summarization fields
@@ -30,6 +32,7 @@ type RedisMemory struct {
 	messageThreshold     int
 	summaryCount         int
 	summaryKeyPrefix     string
+	onCompaction         CompactionCallback
 }
 
 // RetryOptions configures retry behavior for Redis operations
@@ -77,6 +80,15 @@ func WithMaxMessageSize(size int) RedisOption {
 	}
 }
 
+// WithMaxMessages sets the hard cap on the number of messages stored per conversation (default:
+// DefaultMaxMessages). Beyond it, the oldest messages are summarized if WithSummarization is
+// enabled, and evicted otherwise.
+func WithMaxMessages(n int) RedisOption {
+	return func(r *RedisMemory) {
+		r.maxMessages = n
+	}
+}
+
 // WithRetryOptions configures retry behavior for Redis operations
 func WithRetryOptions(options *RetryOptions) RedisOption {
 	return func(r *RedisMemory) {
@@ -95,6 +107,13 @@ func WithSummarization(llm interfaces.LLM, messageThreshold int, summaryCount in
 	}
 }
 
+// WithRedisCompactionCallback sets a callback receiving the report of each summarization
+func WithRedisCompactionCallback(callback CompactionCallback) RedisOption {
+	return func(r *RedisMemory) {
+		r.onCompaction = callback
+	}
+}
+
 // RedisConfig contains configuration for Redis
 type RedisConfig struct {
 	// URL is the Redis URL (e.g., "localhost:6379")
@@ -115,6 +134,7 @@ func NewRedisMemory(client *redis.Client, options ...RedisOption) *RedisMemory {
 		keyPrefix:          "agent:memory:", // Default prefix
 		compressionEnabled: false,
 		maxMessageSize:     1024 * 1024, // 1MB default max size
+		maxMessages:        DefaultMaxMessages,
 		retryOptions: &RetryOptions{
 			MaxRetries:    3,
 			RetryInterval: 100 * time.Millisecond,
@@ -157,6 +177,8 @@ func (r *RedisMemory) AddMessage(ctx context.Context, message interfaces.Message
 	// Create Redis key with org and conversation IDs for proper isolation
 	key := fmt.Sprintf("%s%s:%s", r.keyPrefix, orgID, conversationID)
 
+	ensureMessageID(&message)
+
 	// Validate message size if configured
 	if r.maxMessageSize > 0 {
 		messageBytes, err := json.Marshal(message)
@@ -208,6 +230,13 @@ func (r *RedisMemory) AddMessage(ctx context.Context, message interfaces.Message
 				}
 			}
 
+			// Evict the oldest messages beyond the cap, e.g. if summarization failed
+			if r.maxMessages > 0 {
+				if err := r.client.LTrim(ctx, key, int64(-r.maxMessages), -1).Err(); err != nil {
+					return fmt.Errorf("failed to trim messages: %w", err)
+				}
+			}
+
 			return nil
 		}
 
@@ -292,26 +321,7 @@ func (r *RedisMemory) GetMessages(ctx context.Context, options ...interfaces.Get
 		allMessages = append(allMessages, message)
 	}
 
-	// Filter by role if specified
-	if len(opts.Roles) > 0 {
-		var filtered []interfaces.Message
-		for _, msg := range allMessages {
-			for _, role := range opts.Roles {
-				if msg.Role == role {
-					filtered = append(filtered, msg)
-					break
-				}
-			}
-		}
-		allMessages = filtered
-	}
-
-	// Apply limit if specified
-	if opts.Limit > 0 && opts.Limit < len(allMessages) {
-		allMessages = allMessages[len(allMessages)-opts.Limit:]
-	}
-
-	return allMessages, nil
+	return selectMessages(allMessages, opts)
 }
 
 // Clear clears the memory for a conversation
@@ -338,6 +348,11 @@ func (r *RedisMemory) Clear(ctx context.Context) error {
 		return fmt.Errorf("failed to clear memory in Redis: %w", err)
 	}
 
+	// Delete the conversation metadata
+	if err := r.client.Del(ctx, r.metadataKey(orgID, conversationID)).Err(); err != nil {
+		return fmt.Errorf("failed to clear conversation metadata in Redis: %w", err)
+	}
+
 	// Clear summaries if summarization is enabled
 	if r.summarizationEnabled {
 		summaryKey := fmt.Sprintf("%s%s:%s", r.summaryKeyPrefix, orgID, conversationID)
@@ -353,6 +368,191 @@ func (r *RedisMemory) Clear(ctx context.Context) error {
 	return nil
 }
 
+// DeleteMessage removes a message from the memory
+func (r *RedisMemory) DeleteMessage(ctx context.Context, id string) error {
+	return r.editMessage(ctx, id, func(tx *redis.Tx, key string, index int64, raw string) error {
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.LRem(ctx, key, 1, raw)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete message from Redis: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateMessage replaces the content of a message in the memory
+func (r *RedisMemory) UpdateMessage(ctx context.Context, id string, content string) error {
+	return r.editMessage(ctx, id, func(tx *redis.Tx, key string, index int64, raw string) error {
+		var message interfaces.Message
+		if err := json.Unmarshal([]byte(raw), &message); err != nil {
+			return fmt.Errorf("failed to unmarshal message: %w", err)
+		}
+		message.Content = content
+
+		messageJSON, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+		if r.maxMessageSize > 0 && len(messageJSON) > r.maxMessageSize {
+			return fmt.Errorf("message size exceeds maximum allowed size of %d bytes", r.maxMessageSize)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.LSet(ctx, key, index, messageJSON)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update message in Redis: %w", err)
+		}
+		return nil
+	})
+}
+
+// editMessage looks up a message by ID and applies edit to it within a transaction watching
+// the conversation key, so that concurrent writes such as summarization can't shift the list
+func (r *RedisMemory) editMessage(ctx context.Context, id string, edit func(tx *redis.Tx, key string, index int64, raw string) error) error {
+	// Get conversation ID from context
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation ID: %w", err)
+	}
+
+	// Get organization ID from context for multi-tenancy support
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		// If no organization ID is found, use a default
+		orgID = "default"
+	}
+
+	// Create Redis key with org and conversation IDs
+	key := fmt.Sprintf("%s%s:%s", r.keyPrefix, orgID, conversationID)
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		results, err := tx.LRange(ctx, key, 0, -1).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get messages from Redis: %w", err)
+		}
+
+		for i, result := range results {
+			var message interfaces.Message
+			if err := json.Unmarshal([]byte(result), &message); err != nil {
+				return fmt.Errorf("failed to unmarshal message: %w", err)
+			}
+			if message.ID == id {
+				return edit(tx, key, int64(i), result)
+			}
+		}
+
+		return fmt.Errorf("%w: %s", interfaces.ErrMessageNotFound, id)
+	}, key)
+}
+
+// metadataKey returns the Redis key holding the metadata of a conversation
+func (r *RedisMemory) metadataKey(orgID, conversationID string) string {
+	return fmt.Sprintf("%smetadata:%s:%s", r.keyPrefix, orgID, conversationID)
+}
+
+// GetConversationMetadata returns the metadata for a conversation
+func (r *RedisMemory) GetConversationMetadata(ctx context.Context) (map[string]interface{}, error) {
+	// Get conversation ID from context
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation ID: %w", err)
+	}
+
+	// Get organization ID from context for multi-tenancy support
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		// If no organization ID is found, use a default
+		orgID = "default"
+	}
+
+	fields, err := r.client.HGetAll(ctx, r.metadataKey(orgID, conversationID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation metadata from Redis: %w", err)
+	}
+
+	metadata := make(map[string]interface{}, len(fields))
+	for key, raw := range fields {
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata value %s: %w", key, err)
+		}
+		metadata[key] = value
+	}
+
+	return metadata, nil
+}
+
+// SetConversationMetadata sets a metadata value for a conversation
+func (r *RedisMemory) SetConversationMetadata(ctx context.Context, key string, value interface{}) error {
+	// Get conversation ID from context
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation ID: %w", err)
+	}
+
+	// Get organization ID from context for multi-tenancy support
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		// If no organization ID is found, use a default
+		orgID = "default"
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata value %s: %w", key, err)
+	}
+
+	metaKey := r.metadataKey(orgID, conversationID)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, metaKey, key, raw)
+	pipe.Expire(ctx, metaKey, r.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set conversation metadata in Redis: %w", err)
+	}
+
+	return nil
+}
+
+// ListConversations returns the IDs of the conversations of the organization in context, from
+// their message and metadata keys
+func (r *RedisMemory) ListConversations(ctx context.Context) ([]string, error) {
+	// Get organization ID from context for multi-tenancy support
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		// If no organization ID is found, use a default
+		orgID = "default"
+	}
+
+	// Keys embed the conversation ID of getConversationID, which is prefixed with the organization ID
+	conversationPrefix := orgID + ":"
+
+	seen := make(map[string]bool)
+	var conversationIDs []string
+	for _, prefix := range []string{
+		fmt.Sprintf("%s%s:%s", r.keyPrefix, orgID, conversationPrefix),
+		r.metadataKey(orgID, conversationPrefix),
+	} {
+		iter := r.client.Scan(ctx, 0, prefix+"*", 0).Iterator()
+		for iter.Next(ctx) {
+			conversationID := strings.TrimPrefix(iter.Val(), prefix)
+			if !seen[conversationID] {
+				seen[conversationID] = true
+				conversationIDs = append(conversationIDs, conversationID)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list conversations in Redis: %w", err)
+		}
+	}
+	sort.Strings(conversationIDs)
+
+	return conversationIDs, nil
+}
+
 // ... additional methods for advanced Redis operations ...
 
 // NewRedisMemoryFromConfig creates a new Redis memory from configuration
@@ -397,13 +597,19 @@ func (r *RedisMemory) checkAndSummarize(ctx context.Context) error {
 		return fmt.Errorf("failed to get message count: %w", err)
 	}
 
+	// Summarize at the threshold, or before the cap evicts messages
+	threshold := r.messageThreshold
+	if r.maxMessages > 0 && r.maxMessages < threshold {
+		threshold = r.maxMessages + 1
+	}
+
 	// Check if we need to summarize
-	if count < int64(r.messageThreshold) {
+	if count < int64(threshold) {
 		return nil
 	}
 
 	// Get messages to summarize (all but the most recent ones)
-	keepRecent := r.messageThreshold / 3 // Keep 1/3 of threshold as recent messages
+	keepRecent := threshold / 3 // Keep 1/3 of threshold as recent messages
 	summarizeCount := int(count) - keepRecent
 
 	// Get messages to summarize
@@ -445,6 +651,10 @@ func (r *RedisMemory) checkAndSummarize(ctx context.Context) error {
 		return fmt.Errorf("failed to rotate summaries: %w", err)
 	}
 
+	if r.onCompaction != nil {
+		r.onCompaction(ctx, newCompactionReport(ctx, messages, summary.Content))
+	}
+
 	return nil
 }
 
@@ -461,7 +671,7 @@ func (r *RedisMemory) createSummary(ctx context.Context, messages []interfaces.M
 	sb.WriteString("\nProvide a concise summary that captures the essential information from this conversation.")
 
 	// Generate summary
-	summary, err := r.llmClient.Generate(ctx, sb.String(), func(o *interfaces.GenerateOptions) {
+	summary, err := summaryLLM(ctx, r.llmClient).Generate(ctx, sb.String(), func(o *interfaces.GenerateOptions) {
 		o.LLMConfig = &interfaces.LLMConfig{
 			Temperature: 0.3, // Lower temperature for more consistent summaries
 		}
@@ -241,3 +241,75 @@ func TestRedisMemoryOptions(t *testing.T) {
 		assert.Equal(t, "custom:summary:", memory.summaryKeyPrefix)
 	})
 }
+
+func TestRedisMemoryEditMessages(t *testing.T) {
+	client, mr := setupTestRedisClient(t)
+	defer mr.Close()
+
+	ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+	testEditMessages(t, NewRedisMemory(client), ctx)
+}
+
+func TestRedisMemoryPaginateMessages(t *testing.T) {
+	client, mr := setupTestRedisClient(t)
+	defer mr.Close()
+
+	ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+	testPaginateMessages(t, NewRedisMemory(client), ctx)
+}
+
+func TestRedisMemoryMaxMessages(t *testing.T) {
+	client, mr := setupTestRedisClient(t)
+	defer mr.Close()
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "test-conversation")
+
+	t.Run("Eviction", func(t *testing.T) {
+		memory := NewRedisMemory(client, WithMaxMessages(3), WithKeyPrefix("evict:"))
+
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: string(rune('A' + i))}))
+		}
+
+		messages, err := memory.GetMessages(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 3)
+		assert.Equal(t, "H", messages[0].Content)
+		assert.Equal(t, "J", messages[2].Content)
+	})
+
+	t.Run("Summarization", func(t *testing.T) {
+		mockLLM := new(MockLLM)
+		mockLLM.On("Generate", mock.Anything, mock.Anything, mock.Anything).Return("Summary of A to D", nil)
+
+		// The cap is below the summarization threshold, so it triggers the summarization
+		memory := NewRedisMemory(client, WithKeyPrefix("summarize:"), WithSummarization(mockLLM, 50, 2), WithMaxMessages(5))
+
+		for i := 0; i < 6; i++ {
+			assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: string(rune('A' + i))}))
+		}
+
+		messages, err := memory.GetMessages(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 3)
+		assert.Contains(t, messages[0].Content, "Summary of A to D")
+		assert.Equal(t, "E", messages[1].Content)
+		assert.Equal(t, "F", messages[2].Content)
+		mockLLM.AssertNumberOfCalls(t, "Generate", 1)
+	})
+
+	t.Run("DefaultCap", func(t *testing.T) {
+		defer func(previous int) { DefaultMaxMessages = previous }(DefaultMaxMessages)
+		DefaultMaxMessages = 4
+
+		memory := NewRedisMemory(client, WithKeyPrefix("default:"))
+		for i := 0; i < 20; i++ {
+			assert.NoError(t, memory.AddMessage(ctx, interfaces.Message{Role: "user", Content: "message"}))
+		}
+
+		messages, err := memory.GetMessages(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 4)
+	})
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// SearchMessages returns up to k messages of the conversation in context matching query, most
+// relevant first. A k of zero or less returns every match. Memories implementing
+// interfaces.MessageSearcher search natively; other memories only support keyword search,
+// which is run over their stored messages.
+func SearchMessages(ctx context.Context, mem interfaces.Memory, query string, k int, options ...interfaces.SearchMessagesOption) ([]interfaces.Message, error) {
+	if mem == nil {
+		return nil, fmt.Errorf("memory is required")
+	}
+	if searcher, ok := mem.(interfaces.MessageSearcher); ok {
+		return searcher.SearchMessages(ctx, query, k, options...)
+	}
+
+	opts := searchOptions(options)
+	if opts.Mode != interfaces.SearchModeKeyword {
+		return nil, fmt.Errorf("search mode %q is not supported by this memory", opts.Mode)
+	}
+
+	messages, err := mem.GetMessages(ctx, interfaces.WithRoles(opts.Roles...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	return keywordSearch(messages, query, k), nil
+}
+
+// searchOptions applies search options over the defaults
+func searchOptions(options []interfaces.SearchMessagesOption) *interfaces.SearchMessagesOptions {
+	opts := &interfaces.SearchMessagesOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	if opts.Mode == "" {
+		opts.Mode = interfaces.SearchModeKeyword
+	}
+	return opts
+}
+
+// searchTerms splits text into lowercase words
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// keywordSearch ranks the messages containing terms of the query by the number of distinct
+// terms they contain, then by the number of occurrences, then by recency
+func keywordSearch(messages []interfaces.Message, query string, k int) []interfaces.Message {
+	terms := make(map[string]bool)
+	for _, term := range searchTerms(query) {
+		terms[term] = true
+	}
+
+	type match struct {
+		message interfaces.Message
+		index   int
+		terms   int
+		hits    int
+	}
+
+	var matches []match
+	for i, message := range messages {
+		m := match{message: message, index: i}
+		seen := make(map[string]bool)
+		for _, word := range searchTerms(message.Content) {
+			if !terms[word] {
+				continue
+			}
+			m.hits++
+			if !seen[word] {
+				seen[word] = true
+				m.terms++
+			}
+		}
+		if m.terms > 0 {
+			matches = append(matches, m)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].terms != matches[j].terms {
+			return matches[i].terms > matches[j].terms
+		}
+		if matches[i].hits != matches[j].hits {
+			return matches[i].hits > matches[j].hits
+		}
+		return matches[i].index > matches[j].index
+	})
+
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+
+	results := make([]interfaces.Message, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, m.message)
+	}
+	return results
+}
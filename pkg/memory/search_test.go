@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func searchTestContext(conversationID string) context.Context {
+	return WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), conversationID)
+}
+
+func contents(messages []interfaces.Message) []string {
+	result := make([]string, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, message.Content)
+	}
+	return result
+}
+
+func addSearchTestMessages(t *testing.T, mem interfaces.Memory, ctx context.Context) {
+	t.Helper()
+	for _, message := range []interfaces.Message{
+		{Role: "user", Content: "My invoice shows a double charge"},
+		{Role: "assistant", Content: "I can help with the invoice. Which charge is wrong?"},
+		{Role: "user", Content: "The charge from March, please refund the charge"},
+		{Role: "assistant", Content: "The weather is nice today"},
+	} {
+		require.NoError(t, mem.AddMessage(ctx, message))
+	}
+}
+
+func TestSearchMessagesKeyword(t *testing.T) {
+	client, mr := setupTestRedisClient(t)
+	defer mr.Close()
+
+	backends := map[string]interfaces.Memory{
+		"buffer": NewConversationBuffer(),
+		"redis":  NewRedisMemory(client),
+	}
+
+	for name, mem := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := searchTestContext("conv1")
+			addSearchTestMessages(t, mem, ctx)
+
+			// Messages matching both terms come first, then by occurrences and recency
+			got, err := SearchMessages(ctx, mem, "Invoice charge", 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				"I can help with the invoice. Which charge is wrong?",
+				"My invoice shows a double charge",
+				"The charge from March, please refund the charge",
+			}, contents(got))
+
+			got, err = SearchMessages(ctx, mem, "invoice charge", 1)
+			require.NoError(t, err)
+			assert.Len(t, got, 1)
+
+			got, err = SearchMessages(ctx, mem, "charge", 0, interfaces.WithSearchRoles("user"))
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				"The charge from March, please refund the charge",
+				"My invoice shows a double charge",
+			}, contents(got))
+
+			got, err = SearchMessages(ctx, mem, "shipping", 0)
+			require.NoError(t, err)
+			assert.Empty(t, got)
+
+			_, err = SearchMessages(ctx, mem, "charge", 0, interfaces.WithSearchMode(interfaces.SearchModeSemantic))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// conceptVectorStore is a vector store embedding text on a few hand-picked concepts, so that
+// messages can match a query by meaning without sharing its words
+type conceptVectorStore struct {
+	interfaces.VectorStore
+	documents []interfaces.Document
+}
+
+var testConcepts = []map[string]bool{
+	{"refund": true, "money": true, "charge": true, "invoice": true, "paid": true},
+	{"weather": true, "sunny": true, "rain": true, "nice": true},
+}
+
+func (s *conceptVectorStore) embed(text string) []float64 {
+	vector := make([]float64, len(testConcepts))
+	for _, word := range searchTerms(text) {
+		for i, concept := range testConcepts {
+			if concept[word] {
+				vector[i]++
+			}
+		}
+	}
+	return vector
+}
+
+func (s *conceptVectorStore) Store(ctx context.Context, documents []interfaces.Document, options ...interfaces.StoreOption) error {
+	s.documents = append(s.documents, documents...)
+	return nil
+}
+
+func (s *conceptVectorStore) Search(ctx context.Context, query string, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	opts := &interfaces.SearchOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	queryVector := s.embed(query)
+	var results []interfaces.SearchResult
+	for _, doc := range s.documents {
+		if opts.Filters["conversation_id"] != doc.Metadata["conversation_id"] {
+			continue
+		}
+		if score := cosine(queryVector, s.embed(doc.Content)); score > 0 {
+			results = append(results, interfaces.SearchResult{Document: doc, Score: float32(score)})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func cosine(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func TestSearchMessagesSemantic(t *testing.T) {
+	store := &conceptVectorStore{}
+	retriever := NewVectorStoreRetriever(store)
+	ctx := searchTestContext("conv1")
+
+	require.NoError(t, retriever.AddMessage(ctx, interfaces.Message{Role: "user", Content: "Is it sunny outside?"}))
+	require.NoError(t, retriever.AddMessage(ctx, interfaces.Message{Role: "user", Content: "I paid twice, I want a refund"}))
+	require.NoError(t, retriever.AddMessage(ctx, interfaces.Message{Role: "assistant", Content: "The refund for the invoice was issued, sunny days ahead"}))
+	// Messages of other conversations are never returned
+	require.NoError(t, retriever.AddMessage(searchTestContext("conv2"), interfaces.Message{Role: "user", Content: "Refund my money"}))
+
+	semantic := interfaces.WithSearchMode(interfaces.SearchModeSemantic)
+
+	got, err := SearchMessages(ctx, retriever, "money back", 0, semantic)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"I paid twice, I want a refund",
+		"The refund for the invoice was issued, sunny days ahead",
+	}, contents(got))
+	assert.NotEmpty(t, got[0].ID)
+
+	got, err = SearchMessages(ctx, retriever, "money back", 1, semantic)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"I paid twice, I want a refund"}, contents(got))
+
+	got, err = SearchMessages(ctx, retriever, "money back", 0, semantic, interfaces.WithSearchRoles("assistant"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"The refund for the invoice was issued, sunny days ahead"}, contents(got))
+
+	// Keyword search finds no shared words
+	got, err = SearchMessages(ctx, retriever, "money back", 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = SearchMessages(ctx, retriever, "sunny", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"The refund for the invoice was issued, sunny days ahead",
+		"Is it sunny outside?",
+	}, contents(got))
+}
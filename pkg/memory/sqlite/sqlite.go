@@ -0,0 +1,493 @@
+// Package sqlite provides a memory persisting conversations to a local SQLite database. It
+// requires cgo, which is why it lives outside of the memory package: New is the SQLite
+// counterpart of memory.NewRedisMemory, so that programs not using SQLite build without cgo.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS memory_messages (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	org_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	message_id TEXT NOT NULL,
+	role TEXT NOT NULL,
+	message TEXT NOT NULL,
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_memory_messages_conversation ON memory_messages (org_id, conversation_id, id);
+CREATE INDEX IF NOT EXISTS idx_memory_messages_message_id ON memory_messages (org_id, conversation_id, message_id);
+CREATE INDEX IF NOT EXISTS idx_memory_messages_created_at ON memory_messages (created_at);
+CREATE TABLE IF NOT EXISTS memory_metadata (
+	org_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	updated_at INTEGER NOT NULL,
+	PRIMARY KEY (org_id, conversation_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_memory_metadata_updated_at ON memory_metadata (updated_at);
+`
+
+// Memory implements a SQLite-backed memory store that persists conversations to a local file
+type Memory struct {
+	db  *sql.DB
+	ttl time.Duration
+
+	schemaMu    sync.Mutex
+	schemaReady bool
+}
+
+// Option represents an option for configuring the SQLite memory
+type Option func(*Memory)
+
+// WithTTL sets how long messages and conversation metadata are kept after they are written.
+// Expired rows are no longer read, and are deleted when the memory is written to.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Memory) {
+		s.ttl = ttl
+	}
+}
+
+// New creates a new SQLite-backed memory stored at dbPath.
+// The schema is created on first use.
+func New(dbPath string, options ...Option) (*Memory, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	// SQLite allows a single writer; serializing access avoids "database is locked" errors
+	// and keeps in-memory databases on a single connection
+	db.SetMaxOpenConns(1)
+
+	mem := &Memory{
+		db: db,
+	}
+
+	for _, option := range options {
+		option(mem)
+	}
+
+	return mem, nil
+}
+
+// ensureSchema creates the tables if they don't exist yet
+func (s *Memory) ensureSchema(ctx context.Context) error {
+	s.schemaMu.Lock()
+	defer s.schemaMu.Unlock()
+
+	if s.schemaReady {
+		return nil
+	}
+	if _, err := s.db.ExecContext(ctx, sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+	s.schemaReady = true
+	return nil
+}
+
+// conversationKey returns the organization and conversation IDs rows are keyed by
+func (s *Memory) conversationKey(ctx context.Context) (string, string, error) {
+	conversationID, ok := memory.GetConversationID(ctx)
+	if !ok {
+		return "", "", fmt.Errorf("conversation ID not found in context")
+	}
+
+	// Get organization ID from context for multi-tenancy support
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		// If no organization ID is found, use a default
+		orgID = "default"
+	}
+
+	return orgID, conversationID, nil
+}
+
+// cutoff returns the write time of the oldest rows that have not expired
+func (s *Memory) cutoff() int64 {
+	if s.ttl <= 0 {
+		return 0
+	}
+	return time.Now().Add(-s.ttl).UnixNano()
+}
+
+// prepareWrite creates the schema and deletes the expired rows
+func (s *Memory) prepareWrite(ctx context.Context) error {
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+	if s.ttl <= 0 {
+		return nil
+	}
+
+	cutoff := s.cutoff()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM memory_messages WHERE created_at < ?`, cutoff); err != nil {
+		return fmt.Errorf("failed to prune expired messages: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM memory_metadata WHERE updated_at < ?`, cutoff); err != nil {
+		return fmt.Errorf("failed to prune expired metadata: %w", err)
+	}
+	return nil
+}
+
+// AddMessage adds a message to the memory
+func (s *Memory) AddMessage(ctx context.Context, message interfaces.Message) error {
+	orgID, conversationID, err := s.conversationKey(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.prepareWrite(ctx); err != nil {
+		return err
+	}
+
+	if message.ID == "" {
+		message.ID = uuid.New().String()
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO memory_messages (org_id, conversation_id, message_id, role, message, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		orgID, conversationID, message.ID, message.Role, string(data), time.Now().UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add message to SQLite: %w", err)
+	}
+
+	return nil
+}
+
+// GetMessages retrieves messages from the memory
+func (s *Memory) GetMessages(ctx context.Context, options ...interfaces.GetMessagesOption) ([]interfaces.Message, error) {
+	orgID, conversationID, err := s.conversationKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	// Apply options
+	opts := &interfaces.GetMessagesOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	query := `SELECT message FROM memory_messages WHERE org_id = ? AND conversation_id = ? AND created_at >= ?`
+	args := []interface{}{orgID, conversationID, s.cutoff()}
+
+	// Keep the messages between the cursors if specified
+	if opts.After != "" {
+		rowID, err := s.messageRowID(ctx, orgID, conversationID, opts.After)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND id > ?`
+		args = append(args, rowID)
+	}
+	if opts.Before != "" {
+		rowID, err := s.messageRowID(ctx, orgID, conversationID, opts.Before)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND id < ?`
+		args = append(args, rowID)
+	}
+
+	// Filter by role if specified
+	if len(opts.Roles) > 0 {
+		query += ` AND role IN (?` + strings.Repeat(`, ?`, len(opts.Roles)-1) + `)`
+		for _, role := range opts.Roles {
+			args = append(args, role)
+		}
+	}
+
+	// Apply limit and offset if specified, keeping the most recent messages
+	query += ` ORDER BY id DESC`
+	if opts.Limit > 0 || opts.Offset > 0 {
+		limit := opts.Limit
+		if limit <= 0 {
+			// A negative limit is no limit in SQLite
+			limit = -1
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, max(opts.Offset, 0))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages from SQLite: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []interfaces.Message
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read message: %w", err)
+		}
+
+		var message interfaces.Message
+		if err := json.Unmarshal([]byte(data), &message); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get messages from SQLite: %w", err)
+	}
+
+	// Restore chronological order
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, nil
+}
+
+// messageRowID returns the row ID of the message with the given ID, which orders the messages
+func (s *Memory) messageRowID(ctx context.Context, orgID, conversationID, messageID string) (int64, error) {
+	var rowID int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id FROM memory_messages WHERE org_id = ? AND conversation_id = ? AND message_id = ? AND created_at >= ?`,
+		orgID, conversationID, messageID, s.cutoff(),
+	).Scan(&rowID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("message %s not found", messageID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get message %s from SQLite: %w", messageID, err)
+	}
+	return rowID, nil
+}
+
+// Clear clears the memory for a conversation
+func (s *Memory) Clear(ctx context.Context) error {
+	orgID, conversationID, err := s.conversationKey(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM memory_messages WHERE org_id = ? AND conversation_id = ?`,
+		orgID, conversationID,
+	); err != nil {
+		return fmt.Errorf("failed to clear messages from SQLite: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM memory_metadata WHERE org_id = ? AND conversation_id = ?`,
+		orgID, conversationID,
+	); err != nil {
+		return fmt.Errorf("failed to clear conversation metadata from SQLite: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMessage removes a message from the memory
+func (s *Memory) DeleteMessage(ctx context.Context, id string) error {
+	orgID, conversationID, err := s.conversationKey(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM memory_messages WHERE org_id = ? AND conversation_id = ? AND message_id = ? AND created_at >= ?`,
+		orgID, conversationID, id, s.cutoff(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete message from SQLite: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return fmt.Errorf("%w: %s", interfaces.ErrMessageNotFound, id)
+	}
+
+	return nil
+}
+
+// UpdateMessage replaces the content of a message in the memory
+func (s *Memory) UpdateMessage(ctx context.Context, id string, content string) error {
+	orgID, conversationID, err := s.conversationKey(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin SQLite transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var data string
+	err = tx.QueryRowContext(ctx,
+		`SELECT message FROM memory_messages WHERE org_id = ? AND conversation_id = ? AND message_id = ? AND created_at >= ?`,
+		orgID, conversationID, id, s.cutoff(),
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", interfaces.ErrMessageNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get message from SQLite: %w", err)
+	}
+
+	var message interfaces.Message
+	if err := json.Unmarshal([]byte(data), &message); err != nil {
+		return fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+	message.Content = content
+
+	updated, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE memory_messages SET message = ? WHERE org_id = ? AND conversation_id = ? AND message_id = ?`,
+		string(updated), orgID, conversationID, id,
+	); err != nil {
+		return fmt.Errorf("failed to update message in SQLite: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit SQLite transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetConversationMetadata returns the metadata for a conversation
+func (s *Memory) GetConversationMetadata(ctx context.Context) (map[string]interface{}, error) {
+	orgID, conversationID, err := s.conversationKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT key, value FROM memory_metadata WHERE org_id = ? AND conversation_id = ? AND updated_at >= ?`,
+		orgID, conversationID, s.cutoff(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation metadata from SQLite: %w", err)
+	}
+	defer rows.Close()
+
+	metadata := make(map[string]interface{})
+	for rows.Next() {
+		var key, raw string
+		if err := rows.Scan(&key, &raw); err != nil {
+			return nil, fmt.Errorf("failed to read conversation metadata: %w", err)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata value %s: %w", key, err)
+		}
+		metadata[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get conversation metadata from SQLite: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// SetConversationMetadata sets a metadata value for a conversation
+func (s *Memory) SetConversationMetadata(ctx context.Context, key string, value interface{}) error {
+	orgID, conversationID, err := s.conversationKey(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.prepareWrite(ctx); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata value %s: %w", key, err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO memory_metadata (org_id, conversation_id, key, value, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (org_id, conversation_id, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		orgID, conversationID, key, string(raw), time.Now().UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set conversation metadata in SQLite: %w", err)
+	}
+
+	return nil
+}
+
+// ListConversations returns the IDs of the conversations of the organization in context
+func (s *Memory) ListConversations(ctx context.Context) ([]string, error) {
+	// Get organization ID from context for multi-tenancy support
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		// If no organization ID is found, use a default
+		orgID = "default"
+	}
+	if err := s.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	cutoff := s.cutoff()
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT conversation_id FROM memory_messages WHERE org_id = ? AND created_at >= ?
+		UNION SELECT conversation_id FROM memory_metadata WHERE org_id = ? AND updated_at >= ?
+		ORDER BY conversation_id`,
+		orgID, cutoff, orgID, cutoff,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations in SQLite: %w", err)
+	}
+	defer rows.Close()
+
+	var conversationIDs []string
+	for rows.Next() {
+		var conversationID string
+		if err := rows.Scan(&conversationID); err != nil {
+			return nil, fmt.Errorf("failed to read conversation ID: %w", err)
+		}
+		conversationIDs = append(conversationIDs, conversationID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list conversations in SQLite: %w", err)
+	}
+
+	return conversationIDs, nil
+}
+
+// Close closes the underlying database
+func (s *Memory) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,422 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func sqliteTestContext(orgID, conversationID string) context.Context {
+	ctx := multitenancy.WithOrgID(context.Background(), orgID)
+	return memory.WithConversationID(ctx, conversationID)
+}
+
+func TestMemory(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+	mem, err := New(dbPath)
+	require.NoError(t, err)
+
+	ctx := sqliteTestContext("org1", "conv1")
+	messages := []interfaces.Message{
+		{ID: "m1", Role: "system", Content: "You are helpful"},
+		{ID: "m2", Role: "user", Content: "Hello"},
+		{ID: "m3", Role: "assistant", Content: "Hi", ToolCalls: []interfaces.ToolCall{{ID: "call_1", Name: "search", Arguments: `{"q":"x"}`}}},
+		{ID: "m4", Role: "tool", Content: "result", ToolCallID: "call_1"},
+		{ID: "m5", Role: "user", Content: "Thanks"},
+	}
+	for _, msg := range messages {
+		require.NoError(t, mem.AddMessage(ctx, msg))
+	}
+
+	t.Run("GetMessages", func(t *testing.T) {
+		got, err := mem.GetMessages(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, messages, got)
+	})
+
+	t.Run("WithRoles", func(t *testing.T) {
+		got, err := mem.GetMessages(ctx, interfaces.WithRoles("user"))
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "Hello", got[0].Content)
+		assert.Equal(t, "Thanks", got[1].Content)
+	})
+
+	t.Run("WithLimit", func(t *testing.T) {
+		got, err := mem.GetMessages(ctx, interfaces.WithLimit(2))
+		require.NoError(t, err)
+		assert.Equal(t, messages[3:], got)
+	})
+
+	t.Run("Isolation", func(t *testing.T) {
+		got, err := mem.GetMessages(sqliteTestContext("org2", "conv1"))
+		require.NoError(t, err)
+		assert.Empty(t, got)
+
+		got, err = mem.GetMessages(sqliteTestContext("org1", "conv2"))
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		reopened, err := New(dbPath)
+		require.NoError(t, err)
+		defer reopened.Close()
+
+		got, err := reopened.GetMessages(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, messages, got)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		require.NoError(t, mem.SetConversationMetadata(ctx, "title", "Greetings"))
+		require.NoError(t, mem.SetConversationMetadata(ctx, "title", "Friendly greetings"))
+
+		metadata, err := mem.GetConversationMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"title": "Friendly greetings"}, metadata)
+	})
+
+	t.Run("EditMessages", func(t *testing.T) {
+		ctx := sqliteTestContext("org1", "conv3")
+		require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "Hello"}))
+		require.NoError(t, mem.AddMessage(ctx, interfaces.Message{ID: "custom", Role: "assistant", Content: "Hi"}))
+		require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "My card is 4111"}))
+
+		messages, err := mem.GetMessages(ctx)
+		require.NoError(t, err)
+		require.Len(t, messages, 3)
+		assert.NotEmpty(t, messages[0].ID)
+		assert.Equal(t, "custom", messages[1].ID)
+
+		require.NoError(t, mem.UpdateMessage(ctx, messages[2].ID, "My card is [REDACTED]"))
+		require.NoError(t, mem.DeleteMessage(ctx, "custom"))
+
+		got, err := mem.GetMessages(ctx)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, messages[0], got[0])
+		assert.Equal(t, "My card is [REDACTED]", got[1].Content)
+
+		assert.True(t, errors.Is(mem.DeleteMessage(ctx, "custom"), interfaces.ErrMessageNotFound))
+		assert.True(t, errors.Is(mem.UpdateMessage(ctx, "missing", "x"), interfaces.ErrMessageNotFound))
+	})
+
+	t.Run("PaginateMessages", func(t *testing.T) {
+		ctx := sqliteTestContext("org1", "conv4")
+		for i := 1; i <= 7; i++ {
+			role := "user"
+			if i%2 == 0 {
+				role = "assistant"
+			}
+			require.NoError(t, mem.AddMessage(ctx, interfaces.Message{ID: fmt.Sprintf("m%d", i), Role: role, Content: fmt.Sprintf("message %d", i)}))
+		}
+
+		ids := func(options ...interfaces.GetMessagesOption) []string {
+			messages, err := mem.GetMessages(ctx, options...)
+			require.NoError(t, err)
+			ids := []string{}
+			for _, message := range messages {
+				ids = append(ids, message.ID)
+			}
+			return ids
+		}
+
+		assert.Equal(t, []string{"m2", "m3", "m4"}, ids(interfaces.WithLimit(3), interfaces.WithOffset(3)))
+		assert.Equal(t, []string{"m2", "m3", "m4"}, ids(interfaces.WithLimit(3), interfaces.WithBefore("m5")))
+		assert.Equal(t, []string{"m3", "m4"}, ids(interfaces.WithAfter("m2"), interfaces.WithBefore("m5")))
+		assert.Equal(t, []string{"m3", "m5"}, ids(interfaces.WithRoles("user"), interfaces.WithBefore("m6"), interfaces.WithLimit(2)))
+
+		_, err := mem.GetMessages(ctx, interfaces.WithAfter("missing"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		other := sqliteTestContext("org1", "conv2")
+		require.NoError(t, mem.AddMessage(other, interfaces.Message{Role: "user", Content: "Other"}))
+
+		require.NoError(t, mem.Clear(ctx))
+
+		got, err := mem.GetMessages(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+
+		metadata, err := mem.GetConversationMetadata(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, metadata)
+
+		got, err = mem.GetMessages(other)
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+	})
+
+	require.NoError(t, mem.Close())
+}
+
+func TestMemoryTTL(t *testing.T) {
+	mem, err := New(filepath.Join(t.TempDir(), "memory.db"), WithTTL(200*time.Millisecond))
+	require.NoError(t, err)
+	defer mem.Close()
+
+	ctx := sqliteTestContext("org1", "conv1")
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{ID: "old", Role: "user", Content: "Old"}))
+	require.NoError(t, mem.SetConversationMetadata(ctx, "title", "Old title"))
+
+	time.Sleep(300 * time.Millisecond)
+
+	// Expired rows are not read, even before the next write prunes them
+	got, err := mem.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	metadata, err := mem.GetConversationMetadata(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, metadata)
+	conversations, err := mem.ListConversations(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, conversations)
+	assert.True(t, errors.Is(mem.UpdateMessage(ctx, "old", "x"), interfaces.ErrMessageNotFound))
+
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "New"}))
+
+	got, err = mem.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "New", got[0].Content)
+
+	var count int
+	require.NoError(t, mem.db.QueryRow(`SELECT COUNT(*) FROM memory_messages`).Scan(&count))
+	assert.Equal(t, 1, count, "expired messages should be pruned")
+	require.NoError(t, mem.db.QueryRow(`SELECT COUNT(*) FROM memory_metadata`).Scan(&count))
+	assert.Equal(t, 0, count, "expired metadata should be pruned")
+}
+
+func TestMemoryRequiresConversation(t *testing.T) {
+	mem, err := New(filepath.Join(t.TempDir(), "memory.db"))
+	require.NoError(t, err)
+	defer mem.Close()
+
+	err = mem.AddMessage(multitenancy.WithOrgID(context.Background(), "org1"), interfaces.Message{Role: "user", Content: "Hi"})
+	assert.Error(t, err)
+}
+
+// sequenceLLM answers prompts with its responses in order
+type sequenceLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (m *sequenceLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	m.prompts = append(m.prompts, prompt)
+	response := m.responses[0]
+	m.responses = m.responses[1:]
+	return response, nil
+}
+
+func (m *sequenceLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *sequenceLLM) Name() string            { return "sequence" }
+func (m *sequenceLLM) SupportsStreaming() bool { return false }
+
+func newTestMemory(t *testing.T) *Memory {
+	t.Helper()
+	mem, err := New(filepath.Join(t.TempDir(), "memory.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mem.Close() })
+	return mem
+}
+
+func contents(messages []interfaces.Message) []string {
+	result := make([]string, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, message.Content)
+	}
+	return result
+}
+
+func TestSearchMessages(t *testing.T) {
+	mem := newTestMemory(t)
+	ctx := sqliteTestContext("org1", "conv1")
+	for _, message := range []interfaces.Message{
+		{Role: "user", Content: "My invoice shows a double charge"},
+		{Role: "assistant", Content: "I can help with the invoice. Which charge is wrong?"},
+		{Role: "user", Content: "The charge from March, please refund the charge"},
+		{Role: "assistant", Content: "The weather is nice today"},
+	} {
+		require.NoError(t, mem.AddMessage(ctx, message))
+	}
+
+	// Messages matching both terms come first, then by occurrences and recency
+	got, err := memory.SearchMessages(ctx, mem, "Invoice charge", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"I can help with the invoice. Which charge is wrong?",
+		"My invoice shows a double charge",
+		"The charge from March, please refund the charge",
+	}, contents(got))
+
+	got, err = memory.SearchMessages(ctx, mem, "invoice charge", 1)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	got, err = memory.SearchMessages(ctx, mem, "charge", 0, interfaces.WithSearchRoles("user"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"The charge from March, please refund the charge",
+		"My invoice shows a double charge",
+	}, contents(got))
+
+	got, err = memory.SearchMessages(ctx, mem, "shipping", 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestConversationCost(t *testing.T) {
+	mem := newTestMemory(t)
+	ctx := sqliteTestContext("test-org", "test-conversation")
+
+	messages := []interfaces.Message{
+		{Role: "user", Content: "Summarize the report"},
+		{Role: "assistant", Content: "The report says...", Metadata: map[string]interface{}{
+			memory.ModelMetadataKey:        "gpt-4o",
+			memory.InputTokensMetadataKey:  1000,
+			memory.OutputTokensMetadataKey: 200,
+			memory.CostMetadataKey:         0.5,
+		}},
+		// Priced from the model, at 2.50 and 10 USD per million tokens
+		{Role: "assistant", Content: "In short...", Metadata: map[string]interface{}{
+			memory.ModelMetadataKey:        "gpt-4o-2024-08-06",
+			memory.InputTokensMetadataKey:  int64(2000),
+			memory.OutputTokensMetadataKey: int64(100),
+		}},
+		{Role: "tool", Content: "42", Metadata: map[string]interface{}{
+			memory.ModelMetadataKey:        "custom-model",
+			memory.InputTokensMetadataKey:  300,
+			memory.OutputTokensMetadataKey: 50,
+		}},
+	}
+	for _, message := range messages {
+		require.NoError(t, mem.AddMessage(ctx, message))
+	}
+
+	// Usage metadata survives the JSON round trip of the stored messages
+	report, err := memory.ConversationCost(ctx, mem)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Total.Messages)
+	assert.Equal(t, int64(1000+2000+300), report.Total.InputTokens)
+	assert.Equal(t, int64(200+100+50), report.Total.OutputTokens)
+	assert.InDelta(t, 0.5+0.006, report.Total.CostUSD, 1e-9)
+	assert.Equal(t, memory.UsageTotals{Messages: 1, InputTokens: 300, OutputTokens: 50}, report.ByRole["tool"])
+	assert.Equal(t, []string{"custom-model"}, report.UnpricedModels)
+}
+
+func TestTagConversation(t *testing.T) {
+	mem := newTestMemory(t)
+	ctx := sqliteTestContext("test-org", "test-conversation")
+	categories := []string{"billing", "technical", "sales"}
+	llm := &sequenceLLM{responses: []string{"Billing, refunds\n", "technical, billing", "none"}}
+
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "I was charged twice"}))
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "assistant", Content: "I will refund the charge."}))
+
+	// Unknown categories are dropped and known ones use their canonical spelling
+	tags, err := memory.TagConversation(ctx, mem, llm, categories)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing"}, tags)
+	assert.Contains(t, llm.prompts[0], "billing, technical, sales")
+
+	// Tags accumulate across turns without duplicates
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "And the app crashes on login"}))
+	tags, err = memory.TagConversation(ctx, mem, llm, categories)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing", "technical"}, tags)
+
+	tags, err = memory.TagConversation(ctx, mem, llm, categories)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing", "technical"}, tags)
+
+	assert.Equal(t, []string{"billing", "technical"}, memory.GetTags(ctx, mem))
+}
+
+func TestToolAllowlist(t *testing.T) {
+	mem := newTestMemory(t)
+	ctx := sqliteTestContext("test-org", "test-conversation")
+
+	_, ok, err := memory.GetToolAllowlist(ctx, mem)
+	require.NoError(t, err)
+	assert.False(t, ok, "expected no allowlist by default")
+
+	require.NoError(t, memory.SetToolAllowlist(ctx, mem, []string{"lookup", "search"}))
+	allowed, ok, err := memory.GetToolAllowlist(ctx, mem)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"lookup", "search"}, allowed)
+
+	// An empty allowlist allows no tool, unlike no allowlist
+	require.NoError(t, memory.SetToolAllowlist(ctx, mem, nil))
+	allowed, ok, err = memory.GetToolAllowlist(ctx, mem)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, allowed)
+
+	// Other conversations are not restricted
+	_, ok, err = memory.GetToolAllowlist(memory.WithConversationID(ctx, "other-conversation"), mem)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, memory.ClearToolAllowlist(ctx, mem))
+	_, ok, err = memory.GetToolAllowlist(ctx, mem)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestExportUserData(t *testing.T) {
+	mem := newTestMemory(t)
+
+	conversations := []struct {
+		orgID, conversationID, userID, content string
+	}{
+		{"org1", "alice-1", "alice", "Where is my order?"},
+		{"org1", "alice-2", "alice", "Cancel my subscription"},
+		{"org1", "bob-1", "bob", "Reset my password"},
+		{"org2", "alice-other-org", "alice", "Hello from another tenant"},
+	}
+	for _, c := range conversations {
+		ctx := sqliteTestContext(c.orgID, c.conversationID)
+		require.NoError(t, memory.SetConversationUser(ctx, mem, c.userID))
+		require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: c.content}))
+		require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "assistant", Content: "Answer to " + c.conversationID}))
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org1")
+	data, err := memory.ExportUserData(ctx, mem, "alice")
+	require.NoError(t, err)
+
+	var export memory.UserDataExport
+	require.NoError(t, json.Unmarshal(data, &export))
+	assert.Equal(t, "alice", export.UserID)
+	assert.Equal(t, "org1", export.OrgID)
+
+	// All conversations of the user are exported, without those of other users or tenants
+	require.Len(t, export.Conversations, 2)
+	assert.Equal(t, "alice-1", export.Conversations[0].ID)
+	assert.Equal(t, "alice-2", export.Conversations[1].ID)
+	assert.Equal(t, "alice", export.Conversations[0].Metadata[memory.UserIDMetadataKey])
+	require.Len(t, export.Conversations[0].Messages, 2)
+	assert.Equal(t, "Where is my order?", export.Conversations[0].Messages[0].Content)
+	assert.NotContains(t, string(data), "Reset my password")
+	assert.NotContains(t, string(data), "another tenant")
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// TagsMetadataKey is the conversation metadata key under which classification tags are stored
+const TagsMetadataKey = "tags"
+
+// tagTurns is the number of latest messages classified by TagConversation
+const tagTurns = 2
+
+// TagConversation classifies the latest exchange of the conversation in context into zero or
+// more of the given categories. New tags are added to those already stored, so tags accumulate
+// across turns; the memory must implement interfaces.ConversationMetadataStore. It returns the
+// tags of the conversation after the update.
+func TagConversation(ctx context.Context, mem interfaces.Memory, llm interfaces.LLM, categories []string) ([]string, error) {
+	if mem == nil {
+		return nil, fmt.Errorf("memory is required")
+	}
+	if llm == nil {
+		return nil, fmt.Errorf("LLM is required")
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("at least one category is required")
+	}
+	store, ok := mem.(interfaces.ConversationMetadataStore)
+	if !ok {
+		return nil, fmt.Errorf("memory does not support conversation metadata")
+	}
+
+	messages, err := mem.GetMessages(ctx, interfaces.WithRoles("user", "assistant"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages to classify")
+	}
+	if len(messages) > tagTurns {
+		messages = messages[len(messages)-tagTurns:]
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Classify the following exchange into zero or more of these categories: ")
+	sb.WriteString(strings.Join(categories, ", "))
+	sb.WriteString(".\nRespond with only the matching categories separated by commas, or \"none\" if no category applies.\n\n")
+	for _, msg := range messages {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, msg.Content))
+	}
+	sb.WriteString("\nCategories:")
+
+	response, err := llm.Generate(ctx, sb.String(), interfaces.WithTemperature(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to classify conversation: %w", err)
+	}
+
+	tags := GetTags(ctx, mem)
+	added := false
+	for _, tag := range parseTags(response, categories) {
+		if !containsTag(tags, tag) {
+			tags = append(tags, tag)
+			added = true
+		}
+	}
+
+	if added {
+		if err := store.SetConversationMetadata(ctx, TagsMetadataKey, tags); err != nil {
+			return tags, fmt.Errorf("failed to store tags: %w", err)
+		}
+	}
+
+	return tags, nil
+}
+
+// GetTags returns the stored tags of the conversation in context
+func GetTags(ctx context.Context, mem interfaces.Memory) []string {
+	store, ok := mem.(interfaces.ConversationMetadataStore)
+	if !ok {
+		return nil
+	}
+
+	metadata, err := store.GetConversationMetadata(ctx)
+	if err != nil {
+		return nil
+	}
+
+	// Memories persisting metadata as JSON return the tags as a generic slice
+	switch value := metadata[TagsMetadataKey].(type) {
+	case []string:
+		return append([]string(nil), value...)
+	case []interface{}:
+		tags := make([]string, 0, len(value))
+		for _, item := range value {
+			if tag, ok := item.(string); ok {
+				tags = append(tags, tag)
+			}
+		}
+		return tags
+	}
+
+	return nil
+}
+
+// parseTags extracts the known categories from an LLM classification, ignoring case and
+// anything that is not one of the categories
+func parseTags(response string, categories []string) []string {
+	var tags []string
+	for _, field := range strings.FieldsFunc(response, func(r rune) bool { return r == ',' || r == '\n' }) {
+		field = strings.Trim(strings.TrimSpace(field), "\"'`*-.")
+		for _, category := range categories {
+			if strings.EqualFold(field, category) && !containsTag(tags, category) {
+				tags = append(tags, category)
+			}
+		}
+	}
+	return tags
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
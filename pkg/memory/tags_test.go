@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestTagConversation(t *testing.T) {
+	categories := []string{"billing", "technical", "sales"}
+
+	for name, mem := range map[string]interfaces.Memory{
+		"buffer": NewConversationBuffer(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+			ctx = WithConversationID(ctx, "test-conversation")
+
+			mockLLM := new(MockLLM)
+			mockLLM.On("Generate", mock.Anything, mock.MatchedBy(func(prompt string) bool {
+				return assert.Contains(t, prompt, "billing, technical, sales")
+			}), mock.Anything).Return("Billing, refunds\n", nil).Once()
+			mockLLM.On("Generate", mock.Anything, mock.Anything, mock.Anything).Return("technical, billing", nil).Once()
+			mockLLM.On("Generate", mock.Anything, mock.Anything, mock.Anything).Return("none", nil).Once()
+
+			require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "I was charged twice"}))
+			require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "assistant", Content: "I will refund the charge."}))
+
+			// Unknown categories are dropped and known ones use their canonical spelling
+			tags, err := TagConversation(ctx, mem, mockLLM, categories)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"billing"}, tags)
+
+			// Tags accumulate across turns without duplicates
+			require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: "And the app crashes on login"}))
+			tags, err = TagConversation(ctx, mem, mockLLM, categories)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"billing", "technical"}, tags)
+
+			tags, err = TagConversation(ctx, mem, mockLLM, categories)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"billing", "technical"}, tags)
+
+			assert.Equal(t, []string{"billing", "technical"}, GetTags(ctx, mem))
+			mockLLM.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagConversationRequiresMetadataStore(t *testing.T) {
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "test-conversation")
+
+	retriever := NewVectorStoreRetriever(&conceptVectorStore{})
+	require.NoError(t, retriever.AddMessage(ctx, interfaces.Message{Role: "user", Content: "Hello"}))
+
+	_, err := TagConversation(ctx, retriever, new(MockLLM), []string{"greeting"})
+	assert.Error(t, err)
+	assert.Nil(t, GetTags(ctx, retriever))
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// TitleMetadataKey is the conversation metadata key under which generated titles are stored
+const TitleMetadataKey = "title"
+
+// maxTitleLength is the maximum number of characters kept from a generated title
+const maxTitleLength = 80
+
+// titleTurns is the number of opening messages used to generate a title
+const titleTurns = 4
+
+// GenerateTitle summarizes the opening turns of the conversation in context into a short title.
+// If the memory implements interfaces.ConversationMetadataStore, the title is stored in the
+// conversation metadata under TitleMetadataKey.
+func GenerateTitle(ctx context.Context, mem interfaces.Memory, llm interfaces.LLM) (string, error) {
+	if mem == nil {
+		return "", fmt.Errorf("memory is required")
+	}
+	if llm == nil {
+		return "", fmt.Errorf("LLM is required")
+	}
+
+	messages, err := mem.GetMessages(ctx, interfaces.WithRoles("user", "assistant"))
+	if err != nil {
+		return "", fmt.Errorf("failed to get messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return "", fmt.Errorf("no messages to generate a title from")
+	}
+	if len(messages) > titleTurns {
+		messages = messages[:titleTurns]
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Generate a short, descriptive title (at most 6 words) for the following conversation. ")
+	sb.WriteString("Respond with only the title, without quotes or punctuation at the end.\n\n")
+	for _, msg := range messages {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, msg.Content))
+	}
+	sb.WriteString("\nTitle:")
+
+	response, err := llm.Generate(ctx, sb.String(), interfaces.WithTemperature(0.2))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate title: %w", err)
+	}
+
+	title := cleanTitle(response)
+	if title == "" {
+		return "", fmt.Errorf("LLM returned an empty title")
+	}
+
+	if store, ok := mem.(interfaces.ConversationMetadataStore); ok {
+		if err := store.SetConversationMetadata(ctx, TitleMetadataKey, title); err != nil {
+			return title, fmt.Errorf("failed to store title: %w", err)
+		}
+	}
+
+	return title, nil
+}
+
+// GetTitle returns the stored title of the conversation in context, if any
+func GetTitle(ctx context.Context, mem interfaces.Memory) (string, bool) {
+	store, ok := mem.(interfaces.ConversationMetadataStore)
+	if !ok {
+		return "", false
+	}
+
+	metadata, err := store.GetConversationMetadata(ctx)
+	if err != nil {
+		return "", false
+	}
+
+	title, ok := metadata[TitleMetadataKey].(string)
+	return title, ok && title != ""
+}
+
+// cleanTitle normalizes an LLM-generated title to a single short line
+func cleanTitle(response string) string {
+	title := strings.TrimSpace(response)
+	if idx := strings.IndexByte(title, '\n'); idx >= 0 {
+		title = title[:idx]
+	}
+	title = strings.TrimPrefix(title, "Title:")
+	title = strings.Trim(strings.TrimSpace(title), "\"'`*#")
+	title = strings.TrimRight(strings.TrimSpace(title), ".")
+
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = strings.TrimSpace(string(runes[:maxTitleLength]))
+	}
+
+	return title
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestGenerateTitle(t *testing.T) {
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "test-conversation")
+
+	buffer := NewConversationBuffer()
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: "user", Content: "How do I reset my router?"}))
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: "assistant", Content: "Hold the reset button for 10 seconds."}))
+
+	mockLLM := new(MockLLM)
+	mockLLM.On("Generate", mock.Anything, mock.MatchedBy(func(prompt string) bool {
+		return assert.Contains(t, prompt, "How do I reset my router?")
+	}), mock.Anything).Return("\"Resetting a Home Router.\"\n", nil)
+
+	title, err := GenerateTitle(ctx, buffer, mockLLM)
+	require.NoError(t, err)
+	assert.Equal(t, "Resetting a Home Router", title)
+
+	stored, ok := GetTitle(ctx, buffer)
+	assert.True(t, ok)
+	assert.Equal(t, title, stored)
+
+	metadata, err := buffer.GetConversationMetadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, title, metadata[TitleMetadataKey])
+
+	// Metadata is scoped to the conversation and removed on Clear
+	require.NoError(t, buffer.Clear(ctx))
+	_, ok = GetTitle(ctx, buffer)
+	assert.False(t, ok)
+}
+
+func TestRedisConversationMetadata(t *testing.T) {
+	client, mr := setupTestRedisClient(t)
+	defer mr.Close()
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "test-conversation")
+
+	redisMemory := NewRedisMemory(client)
+	require.NoError(t, redisMemory.SetConversationMetadata(ctx, TitleMetadataKey, "Router help"))
+
+	title, ok := GetTitle(ctx, redisMemory)
+	assert.True(t, ok)
+	assert.Equal(t, "Router help", title)
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ToolAllowlistMetadataKey is the conversation metadata key under which the tool allowlist is stored
+const ToolAllowlistMetadataKey = "tool_allowlist"
+
+// GetToolAllowlist returns the names of the tools allowed in the conversation in context. It
+// returns false when the conversation has no allowlist, in which case every tool is allowed. An
+// error reading the metadata is returned, so that callers can deny the tools rather than allow
+// them all.
+func GetToolAllowlist(ctx context.Context, mem interfaces.Memory) ([]string, bool, error) {
+	store, ok := mem.(interfaces.ConversationMetadataStore)
+	if !ok {
+		return nil, false, nil
+	}
+
+	metadata, err := store.GetConversationMetadata(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get tool allowlist: %w", err)
+	}
+
+	// Memories persisting metadata as JSON return the allowlist as a generic slice
+	switch value := metadata[ToolAllowlistMetadataKey].(type) {
+	case []string:
+		return append([]string{}, value...), true, nil
+	case []interface{}:
+		names := make([]string, 0, len(value))
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// SetToolAllowlist restricts the tools of the conversation in context to the named ones; an
+// empty list allows no tool. The memory must implement interfaces.ConversationMetadataStore.
+func SetToolAllowlist(ctx context.Context, mem interfaces.Memory, names []string) error {
+	store, ok := mem.(interfaces.ConversationMetadataStore)
+	if !ok {
+		return fmt.Errorf("memory does not support conversation metadata")
+	}
+
+	if err := store.SetConversationMetadata(ctx, ToolAllowlistMetadataKey, append([]string{}, names...)); err != nil {
+		return fmt.Errorf("failed to store tool allowlist: %w", err)
+	}
+	return nil
+}
+
+// ClearToolAllowlist removes the tool allowlist of the conversation in context, allowing every tool again
+func ClearToolAllowlist(ctx context.Context, mem interfaces.Memory) error {
+	store, ok := mem.(interfaces.ConversationMetadataStore)
+	if !ok {
+		return fmt.Errorf("memory does not support conversation metadata")
+	}
+
+	if err := store.SetConversationMetadata(ctx, ToolAllowlistMetadataKey, nil); err != nil {
+		return fmt.Errorf("failed to clear tool allowlist: %w", err)
+	}
+	return nil
+}
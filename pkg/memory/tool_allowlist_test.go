@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestToolAllowlist(t *testing.T) {
+	for name, mem := range map[string]interfaces.Memory{
+		"buffer": NewConversationBuffer(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+			ctx = WithConversationID(ctx, "test-conversation")
+
+			_, ok, err := GetToolAllowlist(ctx, mem)
+			require.NoError(t, err)
+			assert.False(t, ok, "expected no allowlist by default")
+
+			require.NoError(t, SetToolAllowlist(ctx, mem, []string{"lookup", "search"}))
+			allowed, ok, err := GetToolAllowlist(ctx, mem)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []string{"lookup", "search"}, allowed)
+
+			// An empty allowlist allows no tool, unlike no allowlist
+			require.NoError(t, SetToolAllowlist(ctx, mem, nil))
+			allowed, ok, err = GetToolAllowlist(ctx, mem)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Empty(t, allowed)
+
+			// Other conversations are not restricted
+			other := WithConversationID(ctx, "other-conversation")
+			_, ok, err = GetToolAllowlist(other, mem)
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, ClearToolAllowlist(ctx, mem))
+			_, ok, err = GetToolAllowlist(ctx, mem)
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestToolAllowlistRequiresMetadataStore(t *testing.T) {
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	ctx = WithConversationID(ctx, "test-conversation")
+
+	retriever := NewVectorStoreRetriever(&conceptVectorStore{})
+	assert.Error(t, SetToolAllowlist(ctx, retriever, []string{"lookup"}))
+	_, ok, err := GetToolAllowlist(ctx, retriever)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
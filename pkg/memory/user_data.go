@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// UserIDMetadataKey is the conversation metadata key under which the ID of the user owning the
+// conversation is stored, see SetConversationUser
+const UserIDMetadataKey = "user_id"
+
+// UserDataExport is the archive of the conversations of a user produced by ExportUserData
+type UserDataExport struct {
+	UserID        string                 `json:"user_id"`
+	OrgID         string                 `json:"org_id,omitempty"`
+	ExportedAt    time.Time              `json:"exported_at"`
+	Conversations []ExportedConversation `json:"conversations"`
+}
+
+// ExportedConversation is a conversation of a UserDataExport, with its metadata and messages
+// in the format of ExportMessages
+type ExportedConversation struct {
+	ID       string                 `json:"id"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Messages []ExportedMessage      `json:"messages"`
+}
+
+// SetConversationUser records the user owning the conversation in context in its metadata, so
+// that ExportUserData finds the conversation. The memory must implement
+// interfaces.ConversationMetadataStore.
+func SetConversationUser(ctx context.Context, mem interfaces.Memory, userID string) error {
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	store, ok := mem.(interfaces.ConversationMetadataStore)
+	if !ok {
+		return fmt.Errorf("memory does not support conversation metadata")
+	}
+
+	return store.SetConversationMetadata(ctx, UserIDMetadataKey, userID)
+}
+
+// ExportUserData gathers all conversations of a user in the organization in context, with their
+// metadata and messages, into a JSON UserDataExport, e.g. to answer a data subject access
+// request. Conversations belong to the user recorded with SetConversationUser. The memory must
+// implement interfaces.ConversationLister and interfaces.ConversationMetadataStore.
+func ExportUserData(ctx context.Context, mem interfaces.Memory, userID string) ([]byte, error) {
+	if mem == nil {
+		return nil, fmt.Errorf("memory is required")
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	lister, ok := mem.(interfaces.ConversationLister)
+	if !ok {
+		return nil, fmt.Errorf("memory does not support listing conversations")
+	}
+	store, ok := mem.(interfaces.ConversationMetadataStore)
+	if !ok {
+		return nil, fmt.Errorf("memory does not support conversation metadata")
+	}
+
+	conversationIDs, err := lister.ListConversations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+
+	orgID, _ := multitenancy.GetOrgID(ctx)
+	export := UserDataExport{
+		UserID:        userID,
+		OrgID:         orgID,
+		ExportedAt:    time.Now().UTC(),
+		Conversations: []ExportedConversation{},
+	}
+	for _, conversationID := range conversationIDs {
+		conversationCtx := WithConversationID(ctx, conversationID)
+
+		metadata, err := store.GetConversationMetadata(conversationCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata of conversation %s: %w", conversationID, err)
+		}
+		if owner, _ := metadata[UserIDMetadataKey].(string); owner != userID {
+			continue
+		}
+
+		messages, err := mem.GetMessages(conversationCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages of conversation %s: %w", conversationID, err)
+		}
+
+		conversation := ExportedConversation{
+			ID:       conversationID,
+			Metadata: metadata,
+			Messages: make([]ExportedMessage, 0, len(messages)),
+		}
+		for _, message := range messages {
+			conversation.Messages = append(conversation.Messages, exportMessage(message))
+		}
+		export.Conversations = append(export.Conversations, conversation)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestExportUserData(t *testing.T) {
+	client, mr := setupTestRedisClient(t)
+	defer mr.Close()
+
+	for name, mem := range map[string]interfaces.Memory{
+		"buffer": NewConversationBuffer(),
+		"redis":  NewRedisMemory(client),
+	} {
+		t.Run(name, func(t *testing.T) {
+			conversations := []struct {
+				orgID, conversationID, userID, content string
+			}{
+				{"org1", "alice-1", "alice", "Where is my order?"},
+				{"org1", "alice-2", "alice", "Cancel my subscription"},
+				{"org1", "bob-1", "bob", "Reset my password"},
+				{"org2", "alice-other-org", "alice", "Hello from another tenant"},
+			}
+			for _, c := range conversations {
+				ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), c.orgID), c.conversationID)
+				require.NoError(t, SetConversationUser(ctx, mem, c.userID))
+				require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "user", Content: c.content}))
+				require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: "assistant", Content: "Answer to " + c.conversationID}))
+			}
+
+			ctx := multitenancy.WithOrgID(context.Background(), "org1")
+			data, err := ExportUserData(ctx, mem, "alice")
+			require.NoError(t, err)
+
+			var export UserDataExport
+			require.NoError(t, json.Unmarshal(data, &export))
+			assert.Equal(t, "alice", export.UserID)
+			assert.Equal(t, "org1", export.OrgID)
+			assert.False(t, export.ExportedAt.IsZero())
+
+			// All conversations of the user are exported, without those of other users or tenants
+			require.Len(t, export.Conversations, 2)
+			assert.Equal(t, "alice-1", export.Conversations[0].ID)
+			assert.Equal(t, "alice-2", export.Conversations[1].ID)
+			assert.Equal(t, "alice", export.Conversations[0].Metadata[UserIDMetadataKey])
+			assert.Equal(t, []string{"Where is my order?", "Answer to alice-1"}, exportedContents(export.Conversations[0].Messages))
+			assert.Equal(t, []string{"Cancel my subscription", "Answer to alice-2"}, exportedContents(export.Conversations[1].Messages))
+			assert.NotContains(t, string(data), "Reset my password")
+			assert.NotContains(t, string(data), "another tenant")
+
+			// Users without conversations get an empty archive
+			data, err = ExportUserData(ctx, mem, "carol")
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(data, &export))
+			assert.Empty(t, export.Conversations)
+		})
+	}
+}
+
+func TestExportUserDataUnsupportedMemory(t *testing.T) {
+	_, err := ExportUserData(context.Background(), &ConversationSummary{}, "alice")
+	assert.Error(t, err)
+
+	_, err = ExportUserData(context.Background(), NewConversationBuffer(), "")
+	assert.Error(t, err)
+}
+
+func exportedContents(messages []ExportedMessage) []string {
+	result := make([]string, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, message.Content)
+	}
+	return result
+}
@@ -37,18 +37,29 @@ func (v *VectorStoreRetriever) AddMessage(ctx context.Context, message interface
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
-	// Add message to buffer
+	// Add message to buffer, keying the document by the message ID so it can be edited later
+	ensureMessageID(&message)
 	if err := v.buffer.AddMessage(ctx, message); err != nil {
 		return err
 	}
 
-	// Store message in vector store
+	return v.storeDocument(ctx, message)
+}
+
+// storeDocument stores a message in the vector store
+func (v *VectorStoreRetriever) storeDocument(ctx context.Context, message interfaces.Message) error {
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return err
+	}
+
 	doc := interfaces.Document{
-		ID:      fmt.Sprintf("%s-%d", message.Role, message.Metadata["timestamp"]),
+		ID:      message.ID,
 		Content: message.Content,
 		Metadata: map[string]interface{}{
-			"role":      message.Role,
-			"timestamp": message.Metadata["timestamp"],
+			"role":            message.Role,
+			"timestamp":       message.Metadata["timestamp"],
+			"conversation_id": conversationID,
 		},
 	}
 
@@ -88,6 +99,7 @@ func (v *VectorStoreRetriever) GetMessages(ctx context.Context, options ...inter
 		timestamp, _ := result.Document.Metadata["timestamp"].(float64)
 
 		messages = append(messages, interfaces.Message{
+			ID:      result.Document.ID,
 			Role:    role,
 			Content: result.Document.Content,
 			Metadata: map[string]interface{}{
@@ -100,6 +112,107 @@ func (v *VectorStoreRetriever) GetMessages(ctx context.Context, options ...inter
 	return messages, nil
 }
 
+// SearchMessages searches the messages of the conversation in context. Keyword searches run
+// over the buffered messages, semantic searches query the vector store.
+func (v *VectorStoreRetriever) SearchMessages(ctx context.Context, query string, k int, options ...interfaces.SearchMessagesOption) ([]interfaces.Message, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	opts := searchOptions(options)
+	messages, err := v.buffer.GetMessages(ctx, interfaces.WithRoles(opts.Roles...))
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Mode == interfaces.SearchModeKeyword {
+		return keywordSearch(messages, query, k), nil
+	}
+	if opts.Mode != interfaces.SearchModeSemantic {
+		return nil, fmt.Errorf("unsupported search mode %q", opts.Mode)
+	}
+
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages of other roles are filtered out after the search, so rank the whole
+	// conversation when filtering by role
+	limit := k
+	if limit <= 0 || len(opts.Roles) > 0 {
+		all, err := v.buffer.GetMessages(ctx)
+		if err != nil {
+			return nil, err
+		}
+		limit = len(all)
+	}
+	if limit == 0 {
+		return []interfaces.Message{}, nil
+	}
+
+	results, err := v.vectorStore.Search(ctx, query, limit,
+		interfaces.WithFilters(map[string]interface{}{"conversation_id": conversationID}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search vector store: %w", err)
+	}
+
+	// Resolve documents to the buffered messages, which also drops those of other roles
+	byID := make(map[string]interfaces.Message, len(messages))
+	for _, message := range messages {
+		byID[message.ID] = message
+	}
+
+	found := make([]interfaces.Message, 0, len(results))
+	for _, result := range results {
+		if message, ok := byID[result.Document.ID]; ok {
+			found = append(found, message)
+		}
+	}
+	if k > 0 && len(found) > k {
+		found = found[:k]
+	}
+
+	return found, nil
+}
+
+// DeleteMessage removes a message from the buffer and the vector store
+func (v *VectorStoreRetriever) DeleteMessage(ctx context.Context, id string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.buffer.DeleteMessage(ctx, id); err != nil {
+		return err
+	}
+
+	if err := v.vectorStore.Delete(ctx, []string{id}); err != nil {
+		return fmt.Errorf("failed to delete message from vector store: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateMessage replaces the content of a message and re-indexes it in the vector store
+func (v *VectorStoreRetriever) UpdateMessage(ctx context.Context, id string, content string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.buffer.UpdateMessage(ctx, id, content); err != nil {
+		return err
+	}
+
+	messages, err := v.buffer.GetMessages(ctx)
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		if message.ID == id {
+			return v.storeDocument(ctx, message)
+		}
+	}
+
+	return nil
+}
+
 // Clear clears the memory
 func (v *VectorStoreRetriever) Clear(ctx context.Context) error {
 	v.mu.Lock()
@@ -293,6 +293,8 @@ func (h *HTTPServer) handleStream(w http.ResponseWriter, r *http.Request) {
 			sseEventType = "thinking"
 		case interfaces.AgentEventToolCall:
 			sseEventType = "tool_call"
+		case interfaces.AgentEventToolCallDelta:
+			sseEventType = "tool_call_delta"
 		case interfaces.AgentEventToolResult:
 			sseEventType = "tool_result"
 		case interfaces.AgentEventError:
@@ -22,6 +22,12 @@ func WithOrgID(ctx context.Context, orgID string) context.Context {
 	return context.WithValue(ctx, orgIDKey, orgID)
 }
 
+// OrgIDContextKey returns the context key of the organization ID, e.g. for tools declaring
+// the context values they read with interfaces.ContextScopedTool
+func OrgIDContextKey() interface{} {
+	return orgIDKey
+}
+
 // GetOrgID returns the organization ID from the context
 func GetOrgID(ctx context.Context) (string, error) {
 	orgID, ok := ctx.Value(orgIDKey).(string)
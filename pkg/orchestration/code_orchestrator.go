@@ -3,7 +3,9 @@ package orchestration
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"text/template"
 )
 
 // TaskStatus represents the status of a task
@@ -21,6 +23,10 @@ const (
 
 	// TaskFailed indicates the task failed
 	TaskFailed TaskStatus = "failed"
+
+	// TaskSkipped indicates the task was not run, because a dependency failed or the
+	// workflow was cancelled
+	TaskSkipped TaskStatus = "skipped"
 )
 
 // Task represents a task to be executed by an agent
@@ -31,9 +37,13 @@ type Task struct {
 	// AgentID is the ID of the agent to execute the task
 	AgentID string
 
-	// Input is the input to provide to the agent
+	// Input is the input to provide to the agent. For tasks added with AddTaskTemplated, it is
+	// the input template.
 	Input string
 
+	// inputTemplate is the parsed Input of tasks added with AddTaskTemplated
+	inputTemplate *template.Template
+
 	// Dependencies are the IDs of tasks that must complete before this one
 	Dependencies []string
 
@@ -60,6 +70,13 @@ type Workflow struct {
 
 	// FinalTaskID is the ID of the task that produces the final result
 	FinalTaskID string
+
+	// FailFast cancels the remaining tasks via their context as soon as a task fails,
+	// instead of continuing with the tasks not depending on the failed one
+	FailFast bool
+
+	// failureOrder holds the IDs of the failed tasks in the order they failed
+	failureOrder []string
 }
 
 // NewWorkflow creates a new workflow
@@ -84,11 +101,43 @@ func (w *Workflow) AddTask(id string, agentID string, input string, dependencies
 	w.Tasks = append(w.Tasks, task)
 }
 
+// AddTaskTemplated adds a task whose input is a text/template referencing the results of its
+// dependencies by task ID, e.g. "Summarize {{.research}} for {{.audience}}", or
+// {{index . "web-search"}} for IDs that are not identifiers. The input is rendered with the
+// results before the agent runs, instead of appending them to the input; referencing a task that
+// is not a dependency fails the task.
+func (w *Workflow) AddTaskTemplated(id string, agentID string, inputTemplate string, dependencies []string) error {
+	tmpl, err := template.New(id).Option("missingkey=error").Parse(inputTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse input template of task %s: %w", id, err)
+	}
+
+	w.Tasks = append(w.Tasks, &Task{
+		ID:            id,
+		AgentID:       agentID,
+		Input:         inputTemplate,
+		Dependencies:  dependencies,
+		Status:        TaskPending,
+		inputTemplate: tmpl,
+	})
+	return nil
+}
+
 // SetFinalTask sets the final task
 func (w *Workflow) SetFinalTask(id string) {
 	w.FinalTaskID = id
 }
 
+// dependenciesCompleted reports whether all dependencies of a task have completed
+func (w *Workflow) dependenciesCompleted(task *Task) bool {
+	for _, depID := range task.Dependencies {
+		if _, ok := w.Results[depID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // CodeOrchestrator orchestrates agents using code-defined workflows
 type CodeOrchestrator struct {
 	registry *AgentRegistry
@@ -101,84 +150,77 @@ func NewCodeOrchestrator(registry *AgentRegistry) *CodeOrchestrator {
 	}
 }
 
-// ExecuteWorkflow executes a workflow
-func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workflow) (string, error) {
-	// Create a wait group to wait for all tasks
-	var wg sync.WaitGroup
-
-	// Create a channel to signal task completion
-	taskCompletionCh := make(chan string)
-
-	// Create a map to track completed tasks
-	completedTasks := make(map[string]bool)
-	var completedTasksMu sync.Mutex
+// WithRateLimiter bounds the LLM calls of all agents in the registry with a shared limiter.
+// Call it before the agents run, see AgentRegistry.SetRateLimiter.
+func (o *CodeOrchestrator) WithRateLimiter(limiter *RateLimiter) *CodeOrchestrator {
+	o.registry.SetRateLimiter(limiter)
+	return o
+}
 
+// ExecuteWorkflow executes a workflow. Tasks run as soon as all their dependencies have
+// completed; tasks depending on a failed task are skipped. See Workflow.Summary for a report
+// of the execution.
+func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workflow) (string, error) {
 	// Create a context with cancellation
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Start a goroutine to monitor task completion
-	go func() {
-		for {
-			select {
-			case taskID := <-taskCompletionCh:
-				// Mark task as completed
-				completedTasksMu.Lock()
-				completedTasks[taskID] = true
-				completedTasksMu.Unlock()
-
-				// Check if all tasks are completed
-				allCompleted := true
-				for _, task := range workflow.Tasks {
-					if task.Status != TaskCompleted && task.Status != TaskFailed {
-						allCompleted = false
-						break
-					}
-				}
-
-				if allCompleted {
-					// All tasks are completed, cancel the context
-					cancel()
-					return
-				}
+	// mu guards the workflow while tasks run concurrently
+	var mu sync.Mutex
+	var wg sync.WaitGroup
 
-				// Check if any tasks can now be executed
-				for _, task := range workflow.Tasks {
-					if task.Status == TaskPending {
-						// Check if all dependencies are completed
-						allDepsCompleted := true
-						for _, depID := range task.Dependencies {
-							if !completedTasks[depID] {
-								allDepsCompleted = false
-								break
-							}
-						}
-
-						if allDepsCompleted {
-							// All dependencies are completed, execute the task
-							wg.Add(1)
-							go o.executeTask(ctx, task, workflow, &wg, taskCompletionCh)
-						}
-					}
-				}
-			case <-ctx.Done():
-				// Context is cancelled, exit
-				return
-			}
+	// schedule starts the pending tasks whose dependencies have completed. It is called with mu held.
+	var schedule func()
+	schedule = func() {
+		if ctx.Err() != nil {
+			return
 		}
-	}()
+		for _, task := range workflow.Tasks {
+			if task.Status != TaskPending || !workflow.dependenciesCompleted(task) {
+				continue
+			}
 
-	// Start tasks with no dependencies
-	for _, task := range workflow.Tasks {
-		if len(task.Dependencies) == 0 {
+			task.Status = TaskRunning
 			wg.Add(1)
-			go o.executeTask(ctx, task, workflow, &wg, taskCompletionCh)
+			go func(task *Task) {
+				defer wg.Done()
+
+				result, err := o.executeTask(ctx, task, workflow, &mu)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					task.Status = TaskFailed
+					task.Error = err
+					workflow.Errors[task.ID] = err
+					workflow.failureOrder = append(workflow.failureOrder, task.ID)
+					if workflow.FailFast {
+						cancel()
+					}
+				} else {
+					task.Status = TaskCompleted
+					task.Result = result
+					workflow.Results[task.ID] = result
+				}
+				schedule()
+			}(task)
 		}
 	}
 
+	mu.Lock()
+	schedule()
+	mu.Unlock()
+
 	// Wait for all tasks to complete
 	wg.Wait()
 
+	// Tasks that never became ready were skipped
+	for _, task := range workflow.Tasks {
+		if task.Status == TaskPending {
+			task.Status = TaskSkipped
+		}
+	}
+
 	// Check if the final task completed successfully
 	if workflow.FinalTaskID != "" {
 		if err, ok := workflow.Errors[workflow.FinalTaskID]; ok {
@@ -196,46 +238,40 @@ func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workfl
 	return "", nil
 }
 
-// executeTask executes a task
-func (o *CodeOrchestrator) executeTask(ctx context.Context, task *Task, workflow *Workflow, wg *sync.WaitGroup, completionCh chan<- string) {
-	defer wg.Done()
-
-	// Update task status
-	task.Status = TaskRunning
-
-	// Get the agent
-	agent, ok := o.registry.Get(task.AgentID)
-	if !ok {
-		task.Status = TaskFailed
-		task.Error = fmt.Errorf("agent not found: %s", task.AgentID)
-		workflow.Errors[task.ID] = task.Error
-		completionCh <- task.ID
-		return
-	}
-
+// executeTask executes a task with the results of its dependencies
+func (o *CodeOrchestrator) executeTask(ctx context.Context, task *Task, workflow *Workflow, mu *sync.Mutex) (string, error) {
 	// Prepare input with results from dependencies
 	input := task.Input
+	mu.Lock()
+	results := make(map[string]string, len(task.Dependencies))
 	for _, depID := range task.Dependencies {
 		if result, ok := workflow.Results[depID]; ok {
-			input = fmt.Sprintf("%s\n\nResult from %s: %s", input, depID, result)
+			results[depID] = result
+			if task.inputTemplate == nil {
+				input = fmt.Sprintf("%s\n\nResult from %s: %s", input, depID, result)
+			}
+		}
+	}
+	mu.Unlock()
+
+	if task.inputTemplate != nil {
+		var sb strings.Builder
+		if err := task.inputTemplate.Execute(&sb, results); err != nil {
+			return "", fmt.Errorf("failed to render input of task %s: %w", task.ID, err)
 		}
+		input = sb.String()
+	}
+
+	// Get the agent
+	agent, err := o.registry.Acquire(ctx, task.AgentID)
+	if err != nil {
+		return "", err
 	}
 
 	// Execute the agent
 	result, err := agent.Run(ctx, input)
 	if err != nil {
-		task.Status = TaskFailed
-		task.Error = fmt.Errorf("agent execution failed: %w", err)
-		workflow.Errors[task.ID] = task.Error
-		completionCh <- task.ID
-		return
+		return "", fmt.Errorf("agent execution failed: %w", err)
 	}
-
-	// Update task status and result
-	task.Status = TaskCompleted
-	task.Result = result
-	workflow.Results[task.ID] = result
-
-	// Signal task completion
-	completionCh <- task.ID
+	return result, nil
 }
@@ -0,0 +1,175 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+)
+
+// newWorkflowAgent creates an agent running fn on its input
+func newWorkflowAgent(t *testing.T, fn agent.CustomRunFunction) *agent.Agent {
+	t.Helper()
+
+	a, err := agent.NewAgent(
+		agent.WithLLM(&concurrencyLLM{}),
+		agent.WithCustomRunFunction(fn),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	return a
+}
+
+// newWorkflowRegistry registers an agent echoing its input, an agent failing and an agent
+// blocking until its context is cancelled
+func newWorkflowRegistry(t *testing.T) *AgentRegistry {
+	registry := NewAgentRegistry()
+	registry.Register("echo", newWorkflowAgent(t, func(ctx context.Context, input string, a *agent.Agent) (string, error) {
+		return "done: " + input, nil
+	}))
+	registry.Register("failing", newWorkflowAgent(t, func(ctx context.Context, input string, a *agent.Agent) (string, error) {
+		return "", errors.New("boom")
+	}))
+	registry.Register("blocking", newWorkflowAgent(t, func(ctx context.Context, input string, a *agent.Agent) (string, error) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(5 * time.Second):
+			return "too late", nil
+		}
+	}))
+	return registry
+}
+
+func TestExecuteWorkflowDependencies(t *testing.T) {
+	workflow := NewWorkflow()
+	workflow.AddTask("research", "echo", "research", nil)
+	workflow.AddTask("draft", "echo", "draft", []string{"research"})
+	workflow.AddTask("review", "echo", "review", []string{"research", "draft"})
+	workflow.SetFinalTask("review")
+
+	result, err := NewCodeOrchestrator(newWorkflowRegistry(t)).ExecuteWorkflow(context.Background(), workflow)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow failed: %v", err)
+	}
+	if !strings.Contains(result, "Result from draft: done: draft") {
+		t.Errorf("Expected the final task to receive the result of its dependencies, got %q", result)
+	}
+
+	summary := workflow.Summary()
+	if summary.Completed != 3 || summary.Failed != 0 || summary.Skipped != 0 || len(summary.ChainErrors) != 0 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if !summary.FinalDependencies["research"] || !summary.FinalDependencies["draft"] {
+		t.Errorf("Expected the final dependencies to be satisfied, got %v", summary.FinalDependencies)
+	}
+}
+
+func TestExecuteWorkflowTemplatedInput(t *testing.T) {
+	workflow := NewWorkflow()
+	workflow.AddTask("research", "echo", "research", nil)
+	workflow.AddTask("web-search", "echo", "search", nil)
+	if err := workflow.AddTaskTemplated("draft", "echo", `Write about {{.research}} using {{index . "web-search"}}`, []string{"research", "web-search"}); err != nil {
+		t.Fatalf("AddTaskTemplated failed: %v", err)
+	}
+	workflow.SetFinalTask("draft")
+
+	result, err := NewCodeOrchestrator(newWorkflowRegistry(t)).ExecuteWorkflow(context.Background(), workflow)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow failed: %v", err)
+	}
+	if expected := "done: Write about done: research using done: search"; result != expected {
+		t.Errorf("Expected the results to be substituted in the input, got %q", result)
+	}
+}
+
+func TestExecuteWorkflowTemplatedInputErrors(t *testing.T) {
+	workflow := NewWorkflow()
+	if err := workflow.AddTaskTemplated("draft", "echo", "Write about {{.research", nil); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+	if len(workflow.Tasks) != 0 {
+		t.Errorf("Expected the invalid task not to be added, got %d tasks", len(workflow.Tasks))
+	}
+
+	// Referencing a task that is not a dependency fails the task
+	workflow.AddTask("research", "echo", "research", nil)
+	if err := workflow.AddTaskTemplated("draft", "echo", "Write about {{.review}}", []string{"research"}); err != nil {
+		t.Fatalf("AddTaskTemplated failed: %v", err)
+	}
+	workflow.SetFinalTask("draft")
+
+	_, err := NewCodeOrchestrator(newWorkflowRegistry(t)).ExecuteWorkflow(context.Background(), workflow)
+	if err == nil || !strings.Contains(err.Error(), "failed to render input of task draft") {
+		t.Errorf("Expected the task to fail on the missing result, got %v", err)
+	}
+}
+
+func TestWorkflowSummary(t *testing.T) {
+	workflow := NewWorkflow()
+	workflow.AddTask("fetch", "failing", "fetch", nil)
+	workflow.AddTask("parse", "echo", "parse", []string{"fetch"})
+	workflow.AddTask("stats", "echo", "stats", nil)
+	workflow.AddTask("report", "echo", "report", []string{"parse", "stats"})
+	workflow.AddTask("notify", "missing", "notify", nil)
+	workflow.SetFinalTask("report")
+
+	_, err := NewCodeOrchestrator(newWorkflowRegistry(t)).ExecuteWorkflow(context.Background(), workflow)
+	if err == nil {
+		t.Fatal("Expected the workflow to fail")
+	}
+
+	summary := workflow.Summary()
+	if summary.Completed != 1 || summary.Failed != 2 || summary.Skipped != 2 {
+		t.Errorf("Expected 1 completed, 2 failed and 2 skipped tasks, got %+v", summary)
+	}
+
+	if first, ok := summary.ChainErrors["report"]; !ok || first.TaskID != "fetch" || !strings.Contains(first.Err.Error(), "boom") {
+		t.Errorf("Expected the report chain to fail at fetch, got %+v", summary.ChainErrors["report"])
+	}
+	if first, ok := summary.ChainErrors["notify"]; !ok || first.TaskID != "notify" || !strings.Contains(first.Err.Error(), "agent not found") {
+		t.Errorf("Expected the notify chain to fail at notify, got %+v", summary.ChainErrors["notify"])
+	}
+	if len(summary.ChainErrors) != 2 {
+		t.Errorf("Expected 2 chain errors, got %+v", summary.ChainErrors)
+	}
+
+	expected := map[string]bool{"parse": false, "stats": true}
+	if len(summary.FinalDependencies) != 2 || summary.FinalDependencies["parse"] != expected["parse"] || summary.FinalDependencies["stats"] != expected["stats"] {
+		t.Errorf("Expected final dependencies %v, got %v", expected, summary.FinalDependencies)
+	}
+
+	if !strings.HasPrefix(summary.String(), "1 completed, 2 failed, 2 skipped") {
+		t.Errorf("Unexpected summary string: %q", summary.String())
+	}
+}
+
+func TestExecuteWorkflowFailFast(t *testing.T) {
+	workflow := NewWorkflow()
+	workflow.AddTask("fail", "failing", "fail", nil)
+	workflow.AddTask("slow", "blocking", "slow", nil)
+	workflow.FailFast = true
+
+	start := time.Now()
+	if _, err := NewCodeOrchestrator(newWorkflowRegistry(t)).ExecuteWorkflow(context.Background(), workflow); err != nil {
+		t.Fatalf("Unexpected error without final task: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the failure to cancel the remaining tasks, took %v", elapsed)
+	}
+	if err := workflow.Errors["slow"]; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the slow task to be cancelled, got %v", err)
+	}
+
+	summary := workflow.Summary()
+	if first := summary.ChainErrors["slow"]; first.TaskID != "slow" {
+		t.Errorf("Expected the slow chain to report its cancellation, got %+v", first)
+	}
+	if first := summary.ChainErrors["fail"]; first.TaskID != "fail" {
+		t.Errorf("Expected the fail chain to report its error, got %+v", first)
+	}
+}
@@ -26,9 +26,9 @@ func NewDelegationAgent(baseAgent *agent.Agent, registry *AgentRegistry) *Delega
 // Delegate delegates a task to another agent
 func (a *DelegationAgent) Delegate(ctx context.Context, targetAgentID string, query string, preserveMemory bool) (string, error) {
 	// Get the target agent
-	targetAgent, ok := a.registry.Get(targetAgentID)
-	if !ok {
-		return "", fmt.Errorf("agent not found: %s", targetAgentID)
+	targetAgent, err := a.registry.Acquire(ctx, targetAgentID)
+	if err != nil {
+		return "", err
 	}
 
 	// Copy memory if needed
@@ -2,16 +2,29 @@ package orchestration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/guardrails"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/jsontext"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 )
 
+// ErrRateLimited is returned when running an agent would exceed its rate limit, see
+// AgentRegistry.RegisterWithLimit
+var ErrRateLimited = errors.New("agent rate limit exceeded")
+
+// ErrHandoffCycle is returned when a request is handed off back to an agent that already
+// handled it, e.g. general → research → general
+var ErrHandoffCycle = errors.New("handoff cycle detected")
+
 // HandoffRequest represents a request to hand off to another agent
 type HandoffRequest struct {
 	// TargetAgentID is the ID of the agent to hand off to
@@ -43,23 +56,67 @@ type HandoffResult struct {
 
 	// NextHandoff is the next handoff request, if any
 	NextHandoff *HandoffRequest
+
+	// HandoffChain is the IDs of the agents that handled the request, in order, set by
+	// HandleRequest, e.g. ["general", "research", "math"]
+	HandoffChain []string
 }
 
 // AgentRegistry maintains a registry of available agents
 type AgentRegistry struct {
-	agents map[string]*agent.Agent
+	agents  map[string]*agent.Agent
+	limits  map[string]*guardrails.RateLimit
+	limiter *RateLimiter
 }
 
 // NewAgentRegistry creates a new agent registry
 func NewAgentRegistry() *AgentRegistry {
 	return &AgentRegistry{
 		agents: make(map[string]*agent.Agent),
+		limits: make(map[string]*guardrails.RateLimit),
 	}
 }
 
 // Register registers an agent with the registry
 func (r *AgentRegistry) Register(id string, agent *agent.Agent) {
+	r.applyRateLimiter(agent)
 	r.agents[id] = agent
+	delete(r.limits, id)
+}
+
+// RegisterWithLimit registers an agent whose runs are limited per organization, such as an
+// expensive specialized agent that one tenant should not monopolize. The organization is taken
+// from the context with multitenancy.GetOrgID; runs without one share a default limit.
+// Orchestrators acquire the agent with Acquire, which fails with ErrRateLimited once the limit
+// is reached.
+func (r *AgentRegistry) RegisterWithLimit(id string, agent *agent.Agent, limit *guardrails.RateLimit) {
+	r.Register(id, agent)
+	if limit != nil {
+		r.limits[id] = limit
+	}
+}
+
+// SetRateLimiter bounds the LLM calls of all registered and future agents with a shared limiter.
+// It replaces the LLM of the agents with SetLLM, so it must be called before any of them runs.
+// Setting another limiter replaces the previous one.
+func (r *AgentRegistry) SetRateLimiter(limiter *RateLimiter) {
+	r.limiter = limiter
+	for _, agent := range r.agents {
+		r.applyRateLimiter(agent)
+	}
+}
+
+// RateLimiter returns the shared rate limiter of the registry, if any
+func (r *AgentRegistry) RateLimiter() *RateLimiter {
+	return r.limiter
+}
+
+// applyRateLimiter wraps the LLM of a local agent with the registry limiter
+func (r *AgentRegistry) applyRateLimiter(agent *agent.Agent) {
+	if r.limiter == nil || agent == nil || agent.IsRemote() || agent.GetLLM() == nil {
+		return
+	}
+	agent.SetLLM(r.limiter.WrapLLM(agent.GetLLM()))
 }
 
 // Get retrieves an agent from the registry
@@ -68,6 +125,32 @@ func (r *AgentRegistry) Get(id string) (*agent.Agent, bool) {
 	return agent, ok
 }
 
+// Acquire retrieves an agent from the registry to run it, counting the run against the rate
+// limit of the agent for the organization in context. It returns an error wrapping
+// ErrRateLimited when the limit is reached.
+func (r *AgentRegistry) Acquire(ctx context.Context, id string) (*agent.Agent, error) {
+	agent, ok := r.agents[id]
+	if !ok {
+		return nil, fmt.Errorf("agent not found: %s", id)
+	}
+
+	if limit, ok := r.limits[id]; ok {
+		limited, _, err := limit.CheckRequest(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check rate limit of agent %s: %w", id, err)
+		}
+		if limited {
+			orgID, err := multitenancy.GetOrgID(ctx)
+			if err != nil {
+				orgID = "default"
+			}
+			return nil, fmt.Errorf("%w: agent %s for organization %s", ErrRateLimited, id, orgID)
+		}
+	}
+
+	return agent, nil
+}
+
 // List returns all registered agents
 func (r *AgentRegistry) List() map[string]*agent.Agent {
 	return r.agents
@@ -75,12 +158,17 @@ func (r *AgentRegistry) List() map[string]*agent.Agent {
 
 // Orchestrator orchestrates handoffs between agents
 type Orchestrator struct {
-	registry *AgentRegistry
-	router   Router
-	logger   logging.Logger
+	registry     *AgentRegistry
+	router       Router
+	logger       logging.Logger
+	agentTimeout time.Duration
 }
 
-// Router determines which agent should handle a request
+// DefaultAgentTimeout is the default time an agent has to handle its part of a request
+const DefaultAgentTimeout = 30 * time.Second
+
+// Router determines which agent should handle a request. Custom routers can be injected with
+// NewOrchestrator; the context is the routing context passed to HandleRequest.
 type Router interface {
 	Route(ctx context.Context, query string, context map[string]interface{}) (string, error)
 }
@@ -137,6 +225,12 @@ func NewLLMRouter(llm interfaces.LLM) *LLMRouter {
 	}
 }
 
+// NewAgentLLMRouter creates a new LLM router routing with the utility LLM of the agent, see
+// agent.WithUtilityLLM, or its main LLM when no utility LLM is set
+func NewAgentLLMRouter(a *agent.Agent) *LLMRouter {
+	return NewLLMRouter(a.GetUtilityLLM())
+}
+
 // WithLogger sets the logger for the router
 func (r *LLMRouter) WithLogger(logger logging.Logger) *LLMRouter {
 	r.logger = logger
@@ -149,55 +243,117 @@ func (r *LLMRouter) Route(ctx context.Context, query string, context map[string]
 		"query": query,
 	})
 
-	// Create a prompt for the LLM
+	decision, err := r.decide(ctx, query, context)
+	if err != nil {
+		return "", err
+	}
+
+	r.logger.Info(ctx, "Query routed to agent", map[string]interface{}{
+		"agent_id": decision.AgentID,
+		"query":    query,
+	})
+
+	return decision.AgentID, nil
+}
+
+// RoutingDecision is the agent chosen by a router for a query and the reasoning behind the choice
+type RoutingDecision struct {
+	// AgentID is the ID of the agent that should handle the query
+	AgentID string `json:"agent_id"`
+
+	// Rationale explains why the agent was chosen
+	Rationale string `json:"rationale"`
+}
+
+// Explain determines which agent should handle a request and explains the choice, without
+// dispatching the request to the agent. It is meant for auditing routing quality: the decision
+// comes from the same prompt as Route, whose choice it explains. The rationale is empty when the
+// LLM answers with the agent ID only.
+func (r *LLMRouter) Explain(ctx context.Context, query string, context map[string]interface{}) (RoutingDecision, error) {
+	r.logger.Debug(ctx, "Explaining routing decision", map[string]interface{}{
+		"query": query,
+	})
+
+	decision, err := r.decide(ctx, query, context)
+	if err != nil {
+		return RoutingDecision{}, err
+	}
+
+	r.logger.Info(ctx, "Routing decision explained", map[string]interface{}{
+		"agent_id":  decision.AgentID,
+		"rationale": decision.Rationale,
+		"query":     query,
+	})
+
+	return decision, nil
+}
+
+// decide asks the LLM which agent should handle a request and why, for Route and Explain
+func (r *LLMRouter) decide(ctx context.Context, query string, context map[string]interface{}) (RoutingDecision, error) {
+	agents, ok := context["agents"].(map[string]string)
+	if !ok || len(agents) == 0 {
+		return RoutingDecision{}, fmt.Errorf("no agents to route to in context")
+	}
+
 	prompt := fmt.Sprintf(`You are a router that determines which specialized agent should handle a user query.
 Available agents:
 %s
 
 User query: %s
 
-Respond with only the ID of the agent that should handle this query.`, formatAgents(context["agents"].(map[string]string)), query)
+Respond with a JSON object with the ID of the agent that should handle this query and the reasoning behind the choice:
+{"agent_id": "<agent ID>", "rationale": "<why this agent is the best fit>"}`, formatAgents(agents), query)
 
 	r.logger.Debug(ctx, "Generated routing prompt", map[string]interface{}{
 		"prompt": prompt,
 	})
 
-	// Generate a response
 	response, err := r.llm.Generate(ctx, prompt)
 	if err != nil {
 		r.logger.Error(ctx, "Failed to generate routing response", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return "", fmt.Errorf("failed to generate response: %w", err)
+		return RoutingDecision{}, fmt.Errorf("failed to generate response: %w", err)
 	}
 
-	// Clean up the response
-	response = strings.TrimSpace(response)
 	r.logger.Debug(ctx, "Received routing response", map[string]interface{}{
 		"raw_response": response,
 	})
 
-	// Validate the response
-	if _, ok := context["agents"].(map[string]string)[response]; !ok {
+	// Accept a bare agent ID, as some models ignore the requested format
+	decision := RoutingDecision{AgentID: strings.TrimSpace(response)}
+	if _, isAgent := agents[decision.AgentID]; !isAgent {
+		decision = RoutingDecision{}
+		text, _ := jsontext.ExtractObject(response)
+		if err := json.Unmarshal([]byte(text), &decision); err != nil {
+			return RoutingDecision{}, fmt.Errorf("failed to parse routing decision: %w", err)
+		}
+	}
+	decision.AgentID = strings.TrimSpace(decision.AgentID)
+	decision.Rationale = strings.TrimSpace(decision.Rationale)
+
+	if _, ok := agents[decision.AgentID]; !ok {
 		r.logger.Error(ctx, "Invalid agent ID returned by router", map[string]interface{}{
-			"agent_id": response,
+			"agent_id": decision.AgentID,
 		})
-		return "", fmt.Errorf("invalid agent ID: %s", response)
+		return RoutingDecision{}, fmt.Errorf("invalid agent ID: %s", decision.AgentID)
 	}
 
-	r.logger.Info(ctx, "Query routed to agent", map[string]interface{}{
-		"agent_id": response,
-		"query":    query,
-	})
-
-	return response, nil
+	return decision, nil
 }
 
-// formatAgents formats a map of agent IDs to descriptions
+// formatAgents formats a map of agent IDs to descriptions, sorted by ID so that the prompt is
+// the same for the same agents
 func formatAgents(agents map[string]string) string {
+	ids := make([]string, 0, len(agents))
+	for id := range agents {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
 	var result strings.Builder
-	for id, desc := range agents {
-		result.WriteString(fmt.Sprintf("- %s: %s\n", id, desc))
+	for _, id := range ids {
+		result.WriteString(fmt.Sprintf("- %s: %s\n", id, agents[id]))
 	}
 	return result.String()
 }
@@ -205,9 +361,10 @@ func formatAgents(agents map[string]string) string {
 // NewOrchestrator creates a new orchestrator
 func NewOrchestrator(registry *AgentRegistry, router Router) *Orchestrator {
 	return &Orchestrator{
-		registry: registry,
-		router:   router,
-		logger:   logging.New(), // Default logger
+		registry:     registry,
+		router:       router,
+		logger:       logging.New(), // Default logger
+		agentTimeout: DefaultAgentTimeout,
 	}
 }
 
@@ -217,7 +374,33 @@ func (o *Orchestrator) WithLogger(logger logging.Logger) *Orchestrator {
 	return o
 }
 
-// HandleRequest handles a request, potentially routing it through multiple agents
+// WithAgentTimeout sets the time each agent has to handle its part of a request, from the
+// routing or handoff to its response. Zero or less disables the timeout. Defaults to
+// DefaultAgentTimeout.
+func (o *Orchestrator) WithAgentTimeout(timeout time.Duration) *Orchestrator {
+	o.agentTimeout = timeout
+	return o
+}
+
+// agentContext returns the context of an agent run, bounded by the agent timeout
+func (o *Orchestrator) agentContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.agentTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, o.agentTimeout)
+}
+
+// WithRateLimiter bounds the LLM calls of all agents in the registry with a shared limiter.
+// Call it before the agents run, see AgentRegistry.SetRateLimiter.
+func (o *Orchestrator) WithRateLimiter(limiter *RateLimiter) *Orchestrator {
+	o.registry.SetRateLimiter(limiter)
+	return o
+}
+
+// HandleRequest handles a request, potentially routing it through multiple agents. The agents
+// that handled the request are recorded in the HandoffChain of the result. A handoff back to an
+// agent of the chain stops the request: the result of the last agent is returned along with an
+// error wrapping ErrHandoffCycle.
 func (o *Orchestrator) HandleRequest(ctx context.Context, query string, initialContext map[string]interface{}) (*HandoffResult, error) {
 	// Determine which agent should handle the request
 	agentID, err := o.router.Route(ctx, query, initialContext)
@@ -239,6 +422,7 @@ func (o *Orchestrator) HandleRequest(ctx context.Context, query string, initialC
 	}
 
 	// Process handoffs until completion or max iterations
+	var chain []string
 	maxIterations := 5
 	for i := 0; i < maxIterations; i++ {
 		// Check if context is done
@@ -263,6 +447,8 @@ func (o *Orchestrator) HandleRequest(ctx context.Context, query string, initialC
 			})
 			return nil, fmt.Errorf("failed to process handoff: %w", err)
 		}
+		chain = append(chain, result.AgentID)
+		result.HandoffChain = chain
 
 		// Check if completed or no next handoff
 		if result.Completed || result.NextHandoff == nil {
@@ -281,6 +467,14 @@ func (o *Orchestrator) HandleRequest(ctx context.Context, query string, initialC
 			"preserve_mem": result.NextHandoff.PreserveMemory,
 		})
 
+		if err := checkHandoffCycle(chain, result.NextHandoff.TargetAgentID); err != nil {
+			o.logger.Warn(ctx, "Stopping handoff cycle", map[string]interface{}{
+				"handoff_chain": chain,
+				"to_agent":      result.NextHandoff.TargetAgentID,
+			})
+			return result, err
+		}
+
 		// Prepare for next handoff
 		handoffReq = result.NextHandoff
 	}
@@ -291,15 +485,25 @@ func (o *Orchestrator) HandleRequest(ctx context.Context, query string, initialC
 	return nil, fmt.Errorf("exceeded maximum number of handoffs")
 }
 
+// checkHandoffCycle returns an error wrapping ErrHandoffCycle if the next agent of a request
+// already handled it
+func checkHandoffCycle(chain []string, nextAgentID string) error {
+	if !slices.Contains(chain, nextAgentID) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrHandoffCycle, strings.Join(chain, " -> "), nextAgentID)
+}
+
 // processHandoff processes a single handoff
 func (o *Orchestrator) processHandoff(ctx context.Context, req *HandoffRequest) (*HandoffResult, error) {
 	// Get the target agent
-	targetAgent, ok := o.registry.Get(req.TargetAgentID)
-	if !ok {
-		o.logger.Error(ctx, "Agent not found", map[string]interface{}{
+	targetAgent, err := o.registry.Acquire(ctx, req.TargetAgentID)
+	if err != nil {
+		o.logger.Error(ctx, "Failed to acquire agent", map[string]interface{}{
 			"agent_id": req.TargetAgentID,
+			"error":    err.Error(),
 		})
-		return nil, fmt.Errorf("agent not found: %s", req.TargetAgentID)
+		return nil, err
 	}
 
 	o.logger.Info(ctx, "Processing request with agent", map[string]interface{}{
@@ -307,8 +511,7 @@ func (o *Orchestrator) processHandoff(ctx context.Context, req *HandoffRequest)
 		"query":    req.Query,
 	})
 
-	// Create a new context with timeout
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := o.agentContext(ctx)
 	defer cancel()
 
 	// Run the agent
@@ -346,8 +549,7 @@ func (o *Orchestrator) processHandoff(ctx context.Context, req *HandoffRequest)
 func (o *Orchestrator) parseHandoffRequest(response string) *HandoffRequest {
 	// Look for a handoff marker in the response
 	// Format: [HANDOFF:agent_id:reason]
-	re := regexp.MustCompile(`\[HANDOFF:([a-zA-Z0-9_-]+):([^\]]+)\]`)
-	matches := re.FindStringSubmatch(response)
+	matches := handoffMarker.FindStringSubmatch(response)
 	if len(matches) < 3 {
 		return nil
 	}
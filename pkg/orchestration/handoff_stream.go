@@ -0,0 +1,295 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// HandoffEvent reports a handoff from one agent to another during a streamed request
+type HandoffEvent struct {
+	// FromAgent is the ID of the agent handing off the request
+	FromAgent string
+
+	// ToAgent is the ID of the agent taking over the request
+	ToAgent string
+
+	// Reason explains why the handoff is happening
+	Reason string
+}
+
+// OrchestratorStreamEvent is an event of a request streamed by HandleRequestStream. Exactly one
+// of Event and Handoff is set.
+type OrchestratorStreamEvent struct {
+	// AgentID is the ID of the agent handling the request when the event was produced
+	AgentID string
+
+	// Event is a stream event of the agent handling the request
+	Event *interfaces.AgentStreamEvent
+
+	// Handoff is set when the request is handed off to another agent
+	Handoff *HandoffEvent
+}
+
+var (
+	// handoffMarker matches a complete handoff directive
+	handoffMarker = regexp.MustCompile(`\[HANDOFF:([a-zA-Z0-9_-]+):([^\]]+)\]`)
+
+	// partialHandoffMarker matches the start of a handoff directive at the end of streamed content
+	partialHandoffMarker = regexp.MustCompile(`\[(?:H(?:A(?:N(?:D(?:O(?:F(?:F(?::[a-zA-Z0-9_-]*(?::[^\]]*)?)?)?)?)?)?)?)?)?$`)
+)
+
+// HandleRequestStream handles a request like HandleRequest, streaming the events of the agents
+// as they are produced. Handoff directives are detected in the streamed content: the directive
+// is not forwarded, a HandoffEvent is emitted as soon as it is complete, and the stream
+// continues with the events of the target agent. The completion event of an agent handing off
+// the request is not forwarded. A handoff back to an agent that already handled the request
+// fails with an error wrapping ErrHandoffCycle. An agent streaming for longer than the agent
+// timeout of the orchestrator fails the request. Errors are sent as events of type
+// AgentEventError, after which the channel is closed.
+func (o *Orchestrator) HandleRequestStream(ctx context.Context, query string, initialContext map[string]interface{}) (<-chan OrchestratorStreamEvent, error) {
+	// Determine which agent should handle the request
+	agentID, err := o.router.Route(ctx, query, initialContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route request: %w", err)
+	}
+
+	o.logger.Info(ctx, "Initial routing decision", map[string]interface{}{
+		"agent_id": agentID,
+		"query":    query,
+	})
+
+	events := make(chan OrchestratorStreamEvent, 100)
+
+	go func() {
+		defer close(events)
+
+		handoffReq := &HandoffRequest{
+			TargetAgentID:  agentID,
+			Query:          query,
+			Context:        initialContext,
+			PreserveMemory: true,
+		}
+
+		// Process handoffs until completion or max iterations
+		var chain []string
+		maxIterations := 5
+		for i := 0; i < maxIterations; i++ {
+			nextHandoff, err := o.streamHandoff(ctx, handoffReq, events)
+			if err != nil {
+				o.logger.Error(ctx, "Failed to process handoff", map[string]interface{}{
+					"error":    err.Error(),
+					"agent_id": handoffReq.TargetAgentID,
+					"query":    handoffReq.Query,
+				})
+				sendStreamError(ctx, events, handoffReq.TargetAgentID, fmt.Errorf("failed to process handoff: %w", err))
+				return
+			}
+
+			if nextHandoff == nil {
+				o.logger.Info(ctx, "Request completed", map[string]interface{}{
+					"agent_id": handoffReq.TargetAgentID,
+				})
+				return
+			}
+
+			o.logger.Info(ctx, "Handoff detected", map[string]interface{}{
+				"from_agent":   handoffReq.TargetAgentID,
+				"to_agent":     nextHandoff.TargetAgentID,
+				"reason":       nextHandoff.Reason,
+				"preserve_mem": nextHandoff.PreserveMemory,
+			})
+
+			chain = append(chain, handoffReq.TargetAgentID)
+			if err := checkHandoffCycle(chain, nextHandoff.TargetAgentID); err != nil {
+				o.logger.Warn(ctx, "Stopping handoff cycle", map[string]interface{}{
+					"handoff_chain": chain,
+					"to_agent":      nextHandoff.TargetAgentID,
+				})
+				sendStreamError(ctx, events, handoffReq.TargetAgentID, err)
+				return
+			}
+
+			handoffReq = nextHandoff
+		}
+
+		o.logger.Warn(ctx, "Exceeded maximum number of handoffs", map[string]interface{}{
+			"max_iterations": maxIterations,
+		})
+		sendStreamError(ctx, events, handoffReq.TargetAgentID, fmt.Errorf("exceeded maximum number of handoffs"))
+	}()
+
+	return events, nil
+}
+
+// streamHandoff streams the response of the target agent of a handoff request to events and
+// returns the next handoff request, if the agent hands off the request
+func (o *Orchestrator) streamHandoff(ctx context.Context, req *HandoffRequest, events chan<- OrchestratorStreamEvent) (*HandoffRequest, error) {
+	targetAgent, err := o.registry.Acquire(ctx, req.TargetAgentID)
+	if err != nil {
+		o.logger.Error(ctx, "Failed to acquire agent", map[string]interface{}{
+			"agent_id": req.TargetAgentID,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	o.logger.Info(ctx, "Streaming request with agent", map[string]interface{}{
+		"agent_id": req.TargetAgentID,
+		"query":    req.Query,
+	})
+
+	ctx, cancel := o.agentContext(ctx)
+	defer cancel()
+
+	agentEvents, err := targetAgent.RunStream(ctx, req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("agent execution failed: %w", err)
+	}
+	// Drain the events left when returning early, e.g. on timeout, so the agent does not block
+	// sending them
+	defer func() {
+		go func() {
+			for range agentEvents {
+			}
+		}()
+	}()
+
+	// pending holds the streamed content that may be the start of a handoff directive
+	var pending string
+	// handoff holds the content from the handoff directive on, once it is detected
+	var handoff *strings.Builder
+
+	send := func(event interfaces.AgentStreamEvent) error {
+		select {
+		case events <- OrchestratorStreamEvent{AgentID: req.TargetAgentID, Event: &event}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	sendContent := func(event interfaces.AgentStreamEvent, content string) error {
+		if content == "" {
+			return nil
+		}
+		event.Content = content
+		return send(event)
+	}
+
+	for {
+		event, ok, err := nextAgentEvent(ctx, agentEvents)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		if event.Error != nil || event.Type == interfaces.AgentEventError {
+			if event.Error == nil {
+				event.Error = errors.New(event.Content)
+			}
+			return nil, fmt.Errorf("agent execution failed: %w", event.Error)
+		}
+
+		// After a handoff directive, collect the rest of the response as the query of the target agent
+		if handoff != nil {
+			if event.Type == interfaces.AgentEventContent {
+				handoff.WriteString(event.Content)
+			}
+			continue
+		}
+
+		if event.Type != interfaces.AgentEventContent {
+			if err := sendContent(interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Timestamp: event.Timestamp}, pending); err != nil {
+				return nil, err
+			}
+			pending = ""
+			if err := send(event); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		pending += event.Content
+		if loc := handoffMarker.FindStringIndex(pending); loc != nil {
+			if err := sendContent(event, pending[:loc[0]]); err != nil {
+				return nil, err
+			}
+
+			handoff = &strings.Builder{}
+			handoff.WriteString(pending[loc[0]:])
+			pending = ""
+
+			matches := handoffMarker.FindStringSubmatch(handoff.String())
+			select {
+			case events <- OrchestratorStreamEvent{
+				AgentID: req.TargetAgentID,
+				Handoff: &HandoffEvent{FromAgent: req.TargetAgentID, ToAgent: matches[1], Reason: matches[2]},
+			}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		// Hold back the content that may be the start of a handoff directive
+		held := len(pending)
+		if loc := partialHandoffMarker.FindStringIndex(pending); loc != nil {
+			held = loc[0]
+		}
+		if err := sendContent(event, pending[:held]); err != nil {
+			return nil, err
+		}
+		pending = pending[held:]
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if handoff == nil {
+		// The held back content was not a handoff directive after all
+		if err := sendContent(interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Timestamp: time.Now()}, pending); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	nextHandoff := o.parseHandoffRequest(handoff.String())
+	if nextHandoff.Query == "" {
+		nextHandoff.Query = req.Query
+	}
+	return nextHandoff, nil
+}
+
+// nextAgentEvent receives the next event of an agent, reporting false once the agent is done.
+// It fails when the context is done first, e.g. when the agent timeout expires.
+func nextAgentEvent(ctx context.Context, events <-chan interfaces.AgentStreamEvent) (interfaces.AgentStreamEvent, bool, error) {
+	select {
+	case event, ok := <-events:
+		return event, ok, nil
+	case <-ctx.Done():
+		return interfaces.AgentStreamEvent{}, false, ctx.Err()
+	}
+}
+
+// sendStreamError sends an error event, unless the context is done
+func sendStreamError(ctx context.Context, events chan<- OrchestratorStreamEvent, agentID string, err error) {
+	select {
+	case events <- OrchestratorStreamEvent{
+		AgentID: agentID,
+		Event: &interfaces.AgentStreamEvent{
+			Type:      interfaces.AgentEventError,
+			Error:     err,
+			Timestamp: time.Now(),
+		},
+	}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,159 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// newStreamingAgent creates an agent streaming the content chunks, recording its inputs
+func newStreamingAgent(t *testing.T, inputs *[]string, chunks ...string) *agent.Agent {
+	t.Helper()
+
+	a, err := agent.NewAgent(
+		agent.WithLLM(&concurrencyLLM{}),
+		agent.WithCustomRunStreamFunction(func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+			*inputs = append(*inputs, input)
+			events := make(chan interfaces.AgentStreamEvent, len(chunks)+1)
+			for _, chunk := range chunks {
+				events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: chunk, Timestamp: time.Now()}
+			}
+			events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventComplete, Timestamp: time.Now()}
+			close(events)
+			return events, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	return a
+}
+
+func TestHandleRequestStreamHandoff(t *testing.T) {
+	var generalInputs, researchInputs []string
+
+	registry := NewAgentRegistry()
+	registry.Register("general", newStreamingAgent(t, &generalInputs,
+		"Let me check. [HAND", "OFF:research:needs speci", "alized research] Find the ", "latest Go release"))
+	registry.Register("research", newStreamingAgent(t, &researchInputs, "Go 1.24 [is", "] the latest release."))
+
+	router := NewSimpleRouter()
+	router.AddRoute("go", "general")
+	orchestrator := NewOrchestrator(registry, router)
+
+	events, err := orchestrator.HandleRequestStream(context.Background(), "What is the latest Go release?", nil)
+	if err != nil {
+		t.Fatalf("HandleRequestStream failed: %v", err)
+	}
+
+	var content strings.Builder
+	var handoffs []HandoffEvent
+	var completions []string
+	for event := range events {
+		switch {
+		case event.Handoff != nil:
+			if content.String() != "Let me check. " {
+				t.Errorf("Expected the handoff right after the content preceding it, got %q", content.String())
+			}
+			handoffs = append(handoffs, *event.Handoff)
+		case event.Event.Type == interfaces.AgentEventError:
+			t.Fatalf("Unexpected error: %v", event.Event.Error)
+		case event.Event.Type == interfaces.AgentEventContent:
+			content.WriteString(event.Event.Content)
+		case event.Event.Type == interfaces.AgentEventComplete:
+			completions = append(completions, event.AgentID)
+		}
+	}
+
+	if len(handoffs) != 1 || handoffs[0] != (HandoffEvent{FromAgent: "general", ToAgent: "research", Reason: "needs specialized research"}) {
+		t.Errorf("Unexpected handoffs: %+v", handoffs)
+	}
+	if content.String() != "Let me check. Go 1.24 [is] the latest release." {
+		t.Errorf("Unexpected content: %q", content.String())
+	}
+	if len(completions) != 1 || completions[0] != "research" {
+		t.Errorf("Expected only the completion of the target agent, got %v", completions)
+	}
+	if len(researchInputs) != 1 || researchInputs[0] != "Find the latest Go release" {
+		t.Errorf("Expected the text after the directive as query of the target agent, got %v", researchInputs)
+	}
+}
+
+func TestHandleRequestStreamUnknownTarget(t *testing.T) {
+	var inputs []string
+
+	registry := NewAgentRegistry()
+	registry.Register("general", newStreamingAgent(t, &inputs, "[HANDOFF:missing:no reason]"))
+
+	router := NewSimpleRouter()
+	router.AddRoute("go", "general")
+	orchestrator := NewOrchestrator(registry, router)
+
+	events, err := orchestrator.HandleRequestStream(context.Background(), "go", nil)
+	if err != nil {
+		t.Fatalf("HandleRequestStream failed: %v", err)
+	}
+
+	var last OrchestratorStreamEvent
+	for event := range events {
+		last = event
+	}
+	if last.Event == nil || last.Event.Type != interfaces.AgentEventError || last.AgentID != "missing" {
+		t.Errorf("Expected an error event for the unknown agent, got %+v", last)
+	}
+}
+
+func TestHandleRequestStreamAgentTimeout(t *testing.T) {
+	done := make(chan struct{})
+	slow, err := agent.NewAgent(
+		agent.WithLLM(&concurrencyLLM{}),
+		agent.WithCustomRunStreamFunction(func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+			// An agent ignoring cancellation, sending on an unbuffered channel
+			events := make(chan interfaces.AgentStreamEvent)
+			go func() {
+				defer close(done)
+				defer close(events)
+				events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: "Thinking", Timestamp: time.Now()}
+				time.Sleep(50 * time.Millisecond)
+				for i := 0; i < 3; i++ {
+					events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: " more", Timestamp: time.Now()}
+				}
+			}()
+			return events, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	registry := NewAgentRegistry()
+	registry.Register("slow", slow)
+	router := NewSimpleRouter()
+	router.AddRoute("go", "slow")
+	orchestrator := NewOrchestrator(registry, router).WithAgentTimeout(10 * time.Millisecond)
+
+	events, err := orchestrator.HandleRequestStream(context.Background(), "go", nil)
+	if err != nil {
+		t.Fatalf("HandleRequestStream failed: %v", err)
+	}
+
+	var last OrchestratorStreamEvent
+	for event := range events {
+		last = event
+	}
+	if last.Event == nil || last.Event.Type != interfaces.AgentEventError || !errors.Is(last.Event.Error, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout error event, got %+v", last)
+	}
+
+	// The events sent by the agent after the timeout are drained
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected the agent to finish sending its events")
+	}
+}
@@ -0,0 +1,280 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/guardrails"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestLLMRouterExplain(t *testing.T) {
+	var billingInputs, generalInputs []string
+	registry := NewAgentRegistry()
+	registry.Register("billing", newStreamingAgent(t, &billingInputs, "Your invoice is on its way."))
+	registry.Register("general", newStreamingAgent(t, &generalInputs, "Hello!"))
+
+	llm := &routingLLM{agentID: "```json\n{\"agent_id\": \"billing\", \"rationale\": \"The user asks about an invoice.\"}\n```"}
+	router := NewLLMRouter(llm)
+
+	routingContext := map[string]interface{}{
+		"agents": map[string]string{"billing": "Handles invoices", "general": "Handles everything else"},
+	}
+
+	decision, err := router.Explain(context.Background(), "Where is my invoice?", routingContext)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+
+	expected := RoutingDecision{AgentID: "billing", Rationale: "The user asks about an invoice."}
+	if decision != expected {
+		t.Errorf("Expected %+v, got %+v", expected, decision)
+	}
+	if llm.calls != 1 {
+		t.Errorf("Expected one routing call, got %d", llm.calls)
+	}
+	if len(billingInputs) != 0 || len(generalInputs) != 0 {
+		t.Errorf("Expected no agent to be invoked, got billing %v and general %v", billingInputs, generalInputs)
+	}
+}
+
+func TestLLMRouterExplainSkipsBracketsBeforeDecision(t *testing.T) {
+	registry := NewAgentRegistry()
+	registry.Register("billing", newStreamingAgent(t, nil, "Your invoice is on its way."))
+
+	llm := &routingLLM{agentID: `[routing] {"agent_id": "billing", "rationale": "The user asks about an invoice."}`}
+	routingContext := map[string]interface{}{"agents": map[string]string{"billing": "Handles invoices"}}
+
+	decision, err := NewLLMRouter(llm).Explain(context.Background(), "Where is my invoice?", routingContext)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if decision.AgentID != "billing" {
+		t.Errorf("Expected billing, got %+v", decision)
+	}
+}
+
+func TestAgentLLMRouterUsesUtilityLLM(t *testing.T) {
+	primary := &routingLLM{agentID: "general"}
+	utility := &routingLLM{agentID: "billing"}
+	coordinator, err := agent.NewAgent(agent.WithLLM(primary), agent.WithUtilityLLM(utility))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	routingContext := map[string]interface{}{
+		"agents": map[string]string{"billing": "Handles invoices", "general": "Handles everything else"},
+	}
+	agentID, err := NewAgentLLMRouter(coordinator).Route(context.Background(), "Where is my invoice?", routingContext)
+	if err != nil || agentID != "billing" {
+		t.Fatalf("Expected billing, got %q (%v)", agentID, err)
+	}
+	if utility.calls != 1 || primary.calls != 0 {
+		t.Errorf("Expected the routing call on the utility LLM, got %d utility and %d primary calls", utility.calls, primary.calls)
+	}
+}
+
+func TestLLMRouterRouteMatchesExplain(t *testing.T) {
+	routingContext := map[string]interface{}{
+		"agents": map[string]string{"billing": "Handles invoices", "general": "Handles everything else"},
+	}
+	llm := &routingLLM{agentID: `{"agent_id": "general", "rationale": "Small talk"}`}
+	router := NewLLMRouter(llm)
+
+	agentID, err := router.Route(context.Background(), "Hello there", routingContext)
+	if err != nil || agentID != "general" {
+		t.Fatalf("Expected general, got %q (%v)", agentID, err)
+	}
+	decision, err := router.Explain(context.Background(), "Hello there", routingContext)
+	if err != nil || decision.AgentID != agentID {
+		t.Fatalf("Expected the explained decision to match the route, got %+v (%v)", decision, err)
+	}
+
+	// Both send the same prompt, so the explanation describes the decision of Route
+	if len(llm.prompts) != 2 || llm.prompts[0] != llm.prompts[1] {
+		t.Errorf("Expected Route and Explain to send the same prompt, got %q", llm.prompts)
+	}
+
+	// A bare agent ID is accepted without a rationale
+	llm.agentID = "billing"
+	decision, err = router.Explain(context.Background(), "Where is my invoice?", routingContext)
+	if err != nil || decision != (RoutingDecision{AgentID: "billing"}) {
+		t.Errorf("Expected billing without a rationale, got %+v (%v)", decision, err)
+	}
+}
+
+func TestLLMRouterExplainInvalidDecision(t *testing.T) {
+	routingContext := map[string]interface{}{
+		"agents": map[string]string{"billing": "Handles invoices"},
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		context  map[string]interface{}
+		err      string
+	}{
+		{"unknown agent", `{"agent_id": "sales", "rationale": "Pricing question"}`, routingContext, "invalid agent ID: sales"},
+		{"not JSON", "Probably billing", routingContext, "failed to parse routing decision"},
+		{"no agents", `{"agent_id": "billing"}`, nil, "no agents to route to"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewLLMRouter(&routingLLM{agentID: tt.response})
+			_, err := router.Explain(context.Background(), "How much does it cost?", tt.context)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestAgentRegistryRegisterWithLimit(t *testing.T) {
+	var inputs []string
+
+	registry := NewAgentRegistry()
+	registry.RegisterWithLimit("expert", newStreamingAgent(t, &inputs, "Done."), guardrails.NewRateLimit(2, guardrails.BlockAction))
+	registry.Register("general", newStreamingAgent(t, &inputs, "Hello!"))
+
+	tenantA := multitenancy.WithOrgID(context.Background(), "tenant-a")
+	tenantB := multitenancy.WithOrgID(context.Background(), "tenant-b")
+
+	for i := 0; i < 2; i++ {
+		if _, err := registry.Acquire(tenantA, "expert"); err != nil {
+			t.Fatalf("Acquire %d failed: %v", i+1, err)
+		}
+	}
+	_, err := registry.Acquire(tenantA, "expert")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited once the limit is reached, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "tenant-a") {
+		t.Errorf("Expected the error to name the organization, got %q", err.Error())
+	}
+
+	// Limits are kept per organization, and agents registered without a limit are not limited
+	if _, err := registry.Acquire(tenantB, "expert"); err != nil {
+		t.Errorf("Expected another organization to keep its own limit, got %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := registry.Acquire(tenantA, "general"); err != nil {
+			t.Errorf("Expected an unlimited agent, got %v", err)
+		}
+	}
+	if _, err := registry.Acquire(tenantA, "missing"); err == nil || errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected an agent not found error, got %v", err)
+	}
+
+	// Orchestrators enforce the limit when running the agent
+	router := NewSimpleRouter()
+	router.AddRoute("expert", "expert")
+	events, err := NewOrchestrator(registry, router).HandleRequestStream(tenantA, "Ask the expert", nil)
+	if err != nil {
+		t.Fatalf("HandleRequestStream failed: %v", err)
+	}
+	var streamErr error
+	for event := range events {
+		if event.Event != nil && event.Event.Type == interfaces.AgentEventError {
+			streamErr = event.Event.Error
+		}
+	}
+	if !errors.Is(streamErr, ErrRateLimited) {
+		t.Errorf("Expected the stream to fail with ErrRateLimited, got %v", streamErr)
+	}
+	if len(inputs) != 0 {
+		t.Errorf("Expected no agent to run, got inputs %v", inputs)
+	}
+}
+
+// newRespondingAgent creates an agent answering each run with the next of its responses
+func newRespondingAgent(t *testing.T, inputs *[]string, responses ...string) *agent.Agent {
+	t.Helper()
+
+	a, err := agent.NewAgent(
+		agent.WithLLM(&concurrencyLLM{}),
+		agent.WithCustomRunFunction(func(ctx context.Context, input string, a *agent.Agent) (string, error) {
+			*inputs = append(*inputs, input)
+			response := responses[0]
+			if len(responses) > 1 {
+				responses = responses[1:]
+			}
+			return response, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	return a
+}
+
+func TestHandleRequestHandoffChain(t *testing.T) {
+	var inputs []string
+
+	registry := NewAgentRegistry()
+	registry.Register("general", newRespondingAgent(t, &inputs, "[HANDOFF:research:needs research] Find the formula"))
+	registry.Register("research", newRespondingAgent(t, &inputs, "[HANDOFF:math:needs a computation] Compute 2^10"))
+	registry.Register("math", newRespondingAgent(t, &inputs, "1024"))
+
+	router := NewSimpleRouter()
+	router.AddRoute("compute", "general")
+
+	result, err := NewOrchestrator(registry, router).HandleRequest(context.Background(), "Please compute this", nil)
+	if err != nil {
+		t.Fatalf("HandleRequest failed: %v", err)
+	}
+	if result.Response != "1024" || !result.Completed {
+		t.Errorf("Expected the response of the math agent, got %+v", result)
+	}
+	if strings.Join(result.HandoffChain, ",") != "general,research,math" {
+		t.Errorf("Expected the handoff chain general,research,math, got %v", result.HandoffChain)
+	}
+}
+
+func TestHandleRequestHandoffCycle(t *testing.T) {
+	var inputs []string
+
+	registry := NewAgentRegistry()
+	registry.Register("general", newRespondingAgent(t, &inputs, "[HANDOFF:research:needs research] Look it up"))
+	registry.Register("research", newRespondingAgent(t, &inputs, "[HANDOFF:general:not my field] Answer it"))
+
+	router := NewSimpleRouter()
+	router.AddRoute("question", "general")
+	orchestrator := NewOrchestrator(registry, router)
+
+	result, err := orchestrator.HandleRequest(context.Background(), "A question", nil)
+	if !errors.Is(err, ErrHandoffCycle) {
+		t.Fatalf("Expected ErrHandoffCycle, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "general -> research -> general") {
+		t.Errorf("Expected the error to show the cycle, got %q", err.Error())
+	}
+	if result == nil || strings.Join(result.HandoffChain, ",") != "general,research" || result.AgentID != "research" {
+		t.Errorf("Expected the result of the last agent with the chain, got %+v", result)
+	}
+	if len(inputs) != 2 {
+		t.Errorf("Expected the cycle to stop after 2 runs, got %v", inputs)
+	}
+
+	// Streamed requests stop as well
+	var streamInputs []string
+	registry.Register("general", newStreamingAgent(t, &streamInputs, "[HANDOFF:research:needs research] Look it up"))
+	registry.Register("research", newStreamingAgent(t, &streamInputs, "[HANDOFF:general:not my field] Answer it"))
+	events, err := orchestrator.HandleRequestStream(context.Background(), "A question", nil)
+	if err != nil {
+		t.Fatalf("HandleRequestStream failed: %v", err)
+	}
+	var streamErr error
+	for event := range events {
+		if event.Event != nil && event.Event.Type == interfaces.AgentEventError {
+			streamErr = event.Event.Error
+		}
+	}
+	if !errors.Is(streamErr, ErrHandoffCycle) || len(streamInputs) != 2 {
+		t.Errorf("Expected the stream to stop with ErrHandoffCycle after 2 runs, got %v after %v", streamErr, streamInputs)
+	}
+}
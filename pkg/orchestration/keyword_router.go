@@ -0,0 +1,84 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// KeywordRouter routes requests to agents by matching the query against keyword and regular
+// expression patterns, without calling an LLM. Routes are evaluated in the order they were
+// added and the first match wins, so routing is deterministic. Queries matching no route go
+// to the fallback router, if any, such as an LLMRouter for the less obvious cases.
+type KeywordRouter struct {
+	routes   []keywordRoute
+	fallback Router
+}
+
+// keywordRoute maps a pattern to the agent handling the queries matching it
+type keywordRoute struct {
+	pattern *regexp.Regexp
+	agentID string
+}
+
+// NewKeywordRouter creates a new keyword router
+func NewKeywordRouter() *KeywordRouter {
+	return &KeywordRouter{}
+}
+
+// WithFallback sets the router used for queries matching no route
+func (r *KeywordRouter) WithFallback(router Router) *KeywordRouter {
+	r.fallback = router
+	return r
+}
+
+// AddKeywords routes queries containing any of the keywords to the agent. Keywords match
+// whole words, ignoring case.
+func (r *KeywordRouter) AddKeywords(agentID string, keywords ...string) {
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		r.routes = append(r.routes, keywordRoute{pattern: keywordPattern(keyword), agentID: agentID})
+	}
+}
+
+// keywordPattern matches keyword as a whole word, ignoring case. Word boundaries are only
+// required next to word characters, so keywords such as "c++" still match.
+func keywordPattern(keyword string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(keyword)
+	if wordChar.MatchString(keyword[:1]) {
+		pattern = `\b` + pattern
+	}
+	if wordChar.MatchString(keyword[len(keyword)-1:]) {
+		pattern += `\b`
+	}
+	return regexp.MustCompile(`(?i)` + pattern)
+}
+
+var wordChar = regexp.MustCompile(`\w`)
+
+// AddPattern routes queries matching the regular expression to the agent
+func (r *KeywordRouter) AddPattern(agentID string, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid routing pattern %q: %w", pattern, err)
+	}
+	r.routes = append(r.routes, keywordRoute{pattern: re, agentID: agentID})
+	return nil
+}
+
+// Route determines which agent should handle a request
+func (r *KeywordRouter) Route(ctx context.Context, query string, context map[string]interface{}) (string, error) {
+	for _, route := range r.routes {
+		if route.pattern.MatchString(query) {
+			return route.agentID, nil
+		}
+	}
+
+	if r.fallback != nil {
+		return r.fallback.Route(ctx, query, context)
+	}
+
+	return "", fmt.Errorf("no agent found for query: %s", query)
+}
@@ -0,0 +1,94 @@
+package orchestration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// routingLLM answers every routing prompt with the same response, recording the prompts
+type routingLLM struct {
+	agentID string
+	calls   int
+	prompts []string
+}
+
+func (m *routingLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	m.calls++
+	m.prompts = append(m.prompts, prompt)
+	return m.agentID, nil
+}
+
+func (m *routingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *routingLLM) Name() string            { return "routing-mock" }
+func (m *routingLLM) SupportsStreaming() bool { return false }
+
+func TestKeywordRouter(t *testing.T) {
+	router := NewKeywordRouter()
+	router.AddKeywords("billing", "invoice", "refund")
+	router.AddKeywords("engineering", "C++", "bug")
+	if err := router.AddPattern("support", `(?i)order\s+#?\d+`); err != nil {
+		t.Fatalf("AddPattern failed: %v", err)
+	}
+	// Earlier routes win over later ones
+	router.AddKeywords("sales", "refund", "pricing")
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"I need a REFUND for my order", "billing"},
+		{"Where is order #1234?", "support"},
+		{"My C++ build has a bug", "engineering"},
+		{"What is your pricing?", "sales"},
+	}
+	for _, tt := range tests {
+		agentID, err := router.Route(context.Background(), tt.query, nil)
+		if err != nil {
+			t.Errorf("Route(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if agentID != tt.expected {
+			t.Errorf("Route(%q) = %q, expected %q", tt.query, agentID, tt.expected)
+		}
+	}
+
+	// Keywords only match whole words
+	if agentID, err := router.Route(context.Background(), "Tell me about debugging", nil); err == nil {
+		t.Errorf("Expected no route, got %q", agentID)
+	}
+
+	if err := router.AddPattern("support", `order(`); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
+
+func TestKeywordRouterFallback(t *testing.T) {
+	llm := &routingLLM{agentID: "general"}
+	router := NewKeywordRouter().WithFallback(NewLLMRouter(llm))
+	router.AddKeywords("billing", "invoice")
+
+	routingContext := map[string]interface{}{
+		"agents": map[string]string{"billing": "Handles invoices", "general": "Handles everything else"},
+	}
+
+	agentID, err := router.Route(context.Background(), "Send me my invoice", routingContext)
+	if err != nil || agentID != "billing" {
+		t.Fatalf("Expected billing, got %q (%v)", agentID, err)
+	}
+	if llm.calls != 0 {
+		t.Errorf("Expected obvious queries to be routed without the LLM, got %d calls", llm.calls)
+	}
+
+	agentID, err = router.Route(context.Background(), "Tell me a joke", routingContext)
+	if err != nil || agentID != "general" {
+		t.Fatalf("Expected general, got %q (%v)", agentID, err)
+	}
+	if llm.calls != 1 {
+		t.Errorf("Expected the fallback to be called once, got %d calls", llm.calls)
+	}
+}
@@ -3,6 +3,7 @@ package orchestration
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/jsontext"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 )
 
@@ -59,6 +61,14 @@ func (o *LLMOrchestrator) WithLogger(logger logging.Logger) *LLMOrchestrator {
 	return o
 }
 
+// WithRateLimiter bounds the LLM calls of the planner and of all agents in the registry with a shared limiter.
+// Call it before the agents run, see AgentRegistry.SetRateLimiter.
+func (o *LLMOrchestrator) WithRateLimiter(limiter *RateLimiter) *LLMOrchestrator {
+	o.planner = limiter.WrapLLM(o.planner)
+	o.registry.SetRateLimiter(limiter)
+	return o
+}
+
 // Execute executes a query using the orchestrator
 func (o *LLMOrchestrator) Execute(ctx context.Context, query string) (string, error) {
 	o.logger.Info(ctx, "Starting execution for query", map[string]interface{}{"query": query})
@@ -152,8 +162,8 @@ Respond with only the JSON plan.`, formatAgentDescriptions(agentDescriptions), q
 	}
 
 	// Extract JSON from response
-	jsonStr := extractJSON(response)
-	if jsonStr == "" {
+	jsonStr, ok := jsontext.ExtractObject(response)
+	if !ok {
 		o.logger.Error(ctx, "Failed to extract JSON from response", nil)
 		return nil, fmt.Errorf("failed to extract JSON from response: %s", response)
 	}
@@ -280,10 +290,10 @@ func (o *LLMOrchestrator) executePlan(ctx context.Context, plan *Plan) (map[stri
 			o.logger.Info(ctx, "Executing step", map[string]interface{}{"step": stepID, "agent": step.AgentID})
 
 			// Execute step
-			agent, ok := o.registry.Get(step.AgentID)
-			if !ok {
-				o.logger.Error(ctx, "Agent not found", map[string]interface{}{"agent": step.AgentID})
-				return nil, fmt.Errorf("agent not found: %s", step.AgentID)
+			agent, err := o.registry.Acquire(ctx, step.AgentID)
+			if err != nil {
+				o.logger.Error(ctx, "Failed to acquire agent", map[string]interface{}{"agent": step.AgentID, "error": err.Error()})
+				return nil, err
 			}
 
 			// Prepare input with context from dependencies
@@ -373,18 +383,25 @@ func (o *LLMOrchestrator) generateFinalResponse(ctx context.Context, plan *Plan,
 	o.logger.Info(ctx, "Generating final response using agent", map[string]interface{}{"agent": plan.FinalAgentID})
 
 	// Get the final agent
-	finalAgent, ok := o.registry.Get(plan.FinalAgentID)
-	if !ok {
+	finalAgent, err := o.registry.Acquire(ctx, plan.FinalAgentID)
+	if errors.Is(err, ErrRateLimited) {
+		return "", err
+	}
+	if err != nil {
 		// If the specified final agent is not available, try to use a fallback
 		o.logger.Info(ctx, "Final agent not found, trying to use a fallback", map[string]interface{}{"agent": plan.FinalAgentID})
 
 		// Try to use summary agent as fallback
-		if summaryAgent, ok := o.registry.Get("summary"); ok {
-			finalAgent = summaryAgent
+		if _, ok := o.registry.Get("summary"); ok {
+			if finalAgent, err = o.registry.Acquire(ctx, "summary"); err != nil {
+				return "", err
+			}
 			o.logger.Info(ctx, "Using summary agent as fallback for final response", nil)
-		} else if creativeAgent, ok := o.registry.Get("creative"); ok {
+		} else if _, ok := o.registry.Get("creative"); ok {
 			// Try creative agent as second fallback
-			finalAgent = creativeAgent
+			if finalAgent, err = o.registry.Acquire(ctx, "creative"); err != nil {
+				return "", err
+			}
 			o.logger.Info(ctx, "Using creative agent as fallback for final response", nil)
 		} else {
 			// No suitable fallback found
@@ -427,45 +444,3 @@ func formatAgentDescriptions(descriptions map[string]string) string {
 	}
 	return result.String()
 }
-
-// extractJSON extracts JSON from a string, handling markdown code blocks
-func extractJSON(s string) string {
-	// First, try to extract from markdown code blocks
-	if strings.Contains(s, "```json") {
-		start := strings.Index(s, "```json")
-		if start != -1 {
-			start += 7 // Move past "```json"
-			end := strings.Index(s[start:], "```")
-			if end != -1 {
-				jsonContent := strings.TrimSpace(s[start : start+end])
-				return jsonContent
-			}
-		}
-	}
-
-	// Also handle generic code blocks that might contain JSON
-	if strings.Contains(s, "```") {
-		parts := strings.Split(s, "```")
-		for i := 1; i < len(parts); i += 2 { // Check odd indices (inside code blocks)
-			content := strings.TrimSpace(parts[i])
-			// Remove language identifier if present (e.g., "json\n{...}")
-			if strings.Contains(content, "\n") && strings.HasPrefix(content, "json") {
-				content = strings.TrimSpace(content[4:])
-			}
-			// Check if this looks like JSON
-			if strings.HasPrefix(content, "{") && strings.HasSuffix(content, "}") {
-				return content
-			}
-		}
-	}
-
-	// Fallback to original logic - find the first { and the last }
-	start := strings.Index(s, "{")
-	end := strings.LastIndex(s, "}")
-
-	if start == -1 || end == -1 || end <= start {
-		return ""
-	}
-
-	return s[start : end+1]
-}
@@ -0,0 +1,228 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BusMessage is a message posted on a channel of a MessageBus
+type BusMessage struct {
+	// Channel is the channel the message was published on
+	Channel string `json:"channel"`
+	// Sequence numbers the messages of a channel in publishing order, from 1, so a subscriber can
+	// tell when messages were dropped. It is 0 for a message dropped because the channel had no
+	// subscribers, and restarts from 1 once every subscriber of the channel has unsubscribed.
+	Sequence int64 `json:"sequence"`
+	// From identifies the sender, e.g. an agent ID
+	From string `json:"from,omitempty"`
+	// Content is the body of the message
+	Content string `json:"content"`
+	// Metadata carries optional structured data
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Timestamp is when the message was published
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MessageBus lets agents exchange messages asynchronously on named channels. Every subscriber
+// of a channel receives each message published on it after subscribing, in publishing order.
+// Messages published on a channel without subscribers are dropped, and the bus only keeps the
+// channels that have subscribers.
+type MessageBus struct {
+	mu       sync.Mutex
+	channels map[string]*busChannel
+	capacity int
+}
+
+// DefaultSubscriptionCapacity is the default number of messages a subscription keeps until they
+// are received
+const DefaultSubscriptionCapacity = 1000
+
+// MessageBusOption configures a MessageBus
+type MessageBusOption func(*MessageBus)
+
+// WithSubscriptionCapacity sets how many messages each subscription keeps until they are
+// received. When a subscription is full, its oldest message is dropped for the new one, so a
+// subscriber that stops receiving does not grow memory without limit. Defaults to
+// DefaultSubscriptionCapacity.
+func WithSubscriptionCapacity(capacity int) MessageBusOption {
+	return func(b *MessageBus) {
+		b.capacity = capacity
+	}
+}
+
+// busChannel holds the subscribers and the sequence of a channel
+type busChannel struct {
+	sequence    int64
+	subscribers map[*Subscription]bool
+}
+
+// NewMessageBus creates a new message bus
+func NewMessageBus(options ...MessageBusOption) *MessageBus {
+	bus := &MessageBus{
+		channels: make(map[string]*busChannel),
+		capacity: DefaultSubscriptionCapacity,
+	}
+	for _, option := range options {
+		option(bus)
+	}
+	return bus
+}
+
+// Publish posts a message on a channel and delivers it to every subscriber of the channel. It
+// never blocks on slow subscribers. The published message, with its channel, sequence and
+// timestamp set, is returned; its sequence is 0 when the channel has no subscribers.
+func (b *MessageBus) Publish(channel string, msg BusMessage) (BusMessage, error) {
+	if channel == "" {
+		return BusMessage{}, fmt.Errorf("channel is required")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	msg.Channel = channel
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	ch, exists := b.channels[channel]
+	if !exists {
+		return msg, nil
+	}
+	ch.sequence++
+	msg.Sequence = ch.sequence
+
+	// Deliver under the bus lock, so every subscriber receives the messages in sequence order
+	for subscription := range ch.subscribers {
+		subscription.deliver(msg)
+	}
+	return msg, nil
+}
+
+// Subscribe returns a subscription receiving the messages published on a channel from now on
+func (b *MessageBus) Subscribe(channel string) (*Subscription, error) {
+	if channel == "" {
+		return nil, fmt.Errorf("channel is required")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscription := &Subscription{
+		bus:      b,
+		channel:  channel,
+		capacity: b.capacity,
+		notify:   make(chan struct{}, 1),
+	}
+	ch, exists := b.channels[channel]
+	if !exists {
+		ch = &busChannel{subscribers: make(map[*Subscription]bool)}
+		b.channels[channel] = ch
+	}
+	ch.subscribers[subscription] = true
+	return subscription, nil
+}
+
+// Subscription receives the messages of a channel of a MessageBus
+type Subscription struct {
+	bus      *MessageBus
+	channel  string
+	capacity int
+	notify   chan struct{}
+
+	mu      sync.Mutex
+	pending []BusMessage
+	dropped int64
+	closed  bool
+}
+
+// Channel returns the channel of the subscription
+func (s *Subscription) Channel() string {
+	return s.channel
+}
+
+// deliver queues a message for the subscriber, dropping the oldest pending message when the
+// subscription is full
+func (s *Subscription) deliver(msg BusMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	if s.capacity > 0 && len(s.pending) >= s.capacity {
+		s.pending = s.pending[1:]
+		s.dropped++
+	}
+	s.pending = append(s.pending, msg)
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Dropped returns the number of messages dropped because the subscription was full
+func (s *Subscription) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dropped
+}
+
+// Receive returns the next message of the channel, waiting for one until the context is done
+func (s *Subscription) Receive(ctx context.Context) (BusMessage, error) {
+	for {
+		s.mu.Lock()
+		if len(s.pending) > 0 {
+			msg := s.pending[0]
+			s.pending = s.pending[1:]
+			s.mu.Unlock()
+			return msg, nil
+		}
+		closed := s.closed
+		s.mu.Unlock()
+
+		if closed {
+			return BusMessage{}, fmt.Errorf("subscription to channel %s is closed", s.channel)
+		}
+
+		select {
+		case <-ctx.Done():
+			return BusMessage{}, ctx.Err()
+		case <-s.notify:
+		}
+	}
+}
+
+// Drain returns the messages received and not yet consumed, without waiting
+func (s *Subscription) Drain() []BusMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := s.pending
+	s.pending = nil
+	return messages
+}
+
+// Unsubscribe stops the delivery of messages and discards the pending ones. Waiting Receive
+// calls return an error.
+func (s *Subscription) Unsubscribe() {
+	s.bus.mu.Lock()
+	if ch, exists := s.bus.channels[s.channel]; exists {
+		delete(ch.subscribers, s)
+		if len(ch.subscribers) == 0 {
+			delete(s.bus.channels, s.channel)
+		}
+	}
+	s.bus.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.pending = nil
+	close(s.notify)
+}
@@ -0,0 +1,266 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestMessageBusDeliversInOrder(t *testing.T) {
+	bus := NewMessageBus()
+	first, err := bus.Subscribe("updates")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	second, _ := bus.Subscribe("updates")
+	other, _ := bus.Subscribe("other")
+
+	for i := 1; i <= 3; i++ {
+		msg, err := bus.Publish("updates", BusMessage{From: "manager", Content: fmt.Sprintf("update %d", i)})
+		if err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+		if msg.Sequence != int64(i) || msg.Channel != "updates" || msg.Timestamp.IsZero() {
+			t.Errorf("Unexpected published message: %+v", msg)
+		}
+	}
+
+	ctx := context.Background()
+	for _, subscription := range []*Subscription{first, second} {
+		for i := 1; i <= 3; i++ {
+			msg, err := subscription.Receive(ctx)
+			if err != nil {
+				t.Fatalf("Receive failed: %v", err)
+			}
+			if msg.Content != fmt.Sprintf("update %d", i) {
+				t.Errorf("Expected update %d, got %q", i, msg.Content)
+			}
+		}
+	}
+	if pending := other.Drain(); len(pending) != 0 {
+		t.Errorf("Expected no messages on another channel, got %v", pending)
+	}
+
+	// Receive waits for the next message
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_, _ = bus.Publish("updates", BusMessage{Content: "late"})
+	}()
+	msg, err := first.Receive(ctx)
+	if err != nil || msg.Content != "late" {
+		t.Errorf("Expected the late message, got %+v (%v)", msg, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := other.Receive(timeoutCtx); err != context.DeadlineExceeded {
+		t.Errorf("Expected the receive to time out, got %v", err)
+	}
+
+	second.Unsubscribe()
+	_, _ = bus.Publish("updates", BusMessage{Content: "after unsubscribe"})
+	if _, err := second.Receive(ctx); err == nil {
+		t.Error("Expected receiving on a closed subscription to fail")
+	}
+
+	if _, err := bus.Publish("", BusMessage{Content: "nowhere"}); err == nil {
+		t.Error("Expected publishing without a channel to fail")
+	}
+}
+
+// busLLM calls the message bus tool with the next of its scripted calls on each run, then
+// answers with the tool results
+type busLLM struct {
+	runs [][]string
+}
+
+func (m *busLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return "no tools", nil
+}
+
+func (m *busLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	if len(m.runs) == 0 {
+		return "nothing to do", nil
+	}
+	calls := m.runs[0]
+	m.runs = m.runs[1:]
+
+	var results []string
+	for _, tool := range tools {
+		if tool.Name() != "message_bus" {
+			continue
+		}
+		for _, call := range calls {
+			result, err := tool.Execute(ctx, call)
+			if err != nil {
+				return "", err
+			}
+			results = append(results, result)
+		}
+	}
+	return strings.Join(results, "\n"), nil
+}
+
+func (m *busLLM) Name() string            { return "bus-mock" }
+func (m *busLLM) SupportsStreaming() bool { return false }
+
+func newBusAgent(t *testing.T, tool *MessageBusTool, runs ...[]string) *agent.Agent {
+	t.Helper()
+
+	a, err := agent.NewAgent(
+		agent.WithLLM(&busLLM{runs: runs}),
+		agent.WithTools(tool),
+		agent.WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	return a
+}
+
+func TestMessageBusToolAgentsExchangeMessages(t *testing.T) {
+	bus := NewMessageBus()
+
+	managerTool, err := NewMessageBusTool(bus, "manager", []string{"results"})
+	if err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+	defer managerTool.Close()
+	workerTool, err := NewMessageBusTool(bus, "worker", []string{"tasks"})
+	if err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+	defer workerTool.Close()
+
+	manager := newBusAgent(t, managerTool,
+		[]string{
+			`{"action":"send","channel":"tasks","content":"research pricing"}`,
+			`{"action":"send","channel":"tasks","content":"research competitors"}`,
+		},
+		[]string{`{"action":"receive","channel":"results"}`},
+	)
+	worker := newBusAgent(t, workerTool,
+		[]string{`{"action":"receive","channel":"tasks"}`},
+		[]string{
+			`{"action":"send","channel":"results","content":"pricing done"}`,
+			`{"action":"send","channel":"results","content":"competitors done"}`,
+		},
+	)
+
+	ctx := context.Background()
+
+	// The tasks wait on the bus until the worker checks for them
+	if _, err := manager.Run(ctx, "Delegate the research"); err != nil {
+		t.Fatalf("Manager run failed: %v", err)
+	}
+	response, err := worker.Run(ctx, "Check for tasks")
+	if err != nil {
+		t.Fatalf("Worker run failed: %v", err)
+	}
+
+	var tasks []BusMessage
+	if err := json.Unmarshal([]byte(response), &tasks); err != nil {
+		t.Fatalf("Failed to parse the tasks received by the worker: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Content != "research pricing" || tasks[1].Content != "research competitors" || tasks[0].From != "manager" {
+		t.Fatalf("Expected the tasks in order from the manager, got %+v", tasks)
+	}
+
+	if _, err := worker.Run(ctx, "Report the results"); err != nil {
+		t.Fatalf("Worker run failed: %v", err)
+	}
+
+	response, err = manager.Run(ctx, "Collect the results")
+	if err != nil {
+		t.Fatalf("Manager run failed: %v", err)
+	}
+	var results []BusMessage
+	if err := json.Unmarshal([]byte(response), &results); err != nil {
+		t.Fatalf("Failed to parse the results received by the manager: %v", err)
+	}
+	if len(results) != 2 || results[0].Content != "pricing done" || results[1].Content != "competitors done" || results[0].Sequence != 1 || results[1].Sequence != 2 {
+		t.Errorf("Expected the results in order from the worker, got %+v", results)
+	}
+
+	// Nothing is left to receive, and unknown channels are rejected
+	if result, _ := managerTool.Execute(ctx, `{"action":"receive","channel":"results"}`); !strings.Contains(result, "No new messages") {
+		t.Errorf("Expected no new messages, got %s", result)
+	}
+	if _, err := managerTool.Execute(ctx, `{"action":"receive","channel":"tasks"}`); err == nil {
+		t.Error("Expected receiving on a channel the agent does not listen to to fail")
+	}
+
+	// Receiving waits up to the receive timeout for a message
+	waitingTool, err := NewMessageBusTool(bus, "manager", []string{"results"}, WithReceiveTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+	defer waitingTool.Close()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_, _ = bus.Publish("results", BusMessage{From: "worker", Content: "late result"})
+	}()
+	if result, err := waitingTool.Execute(ctx, `{"action":"receive","channel":"results"}`); err != nil || !strings.Contains(result, "late result") {
+		t.Errorf("Expected the late result, got %s (%v)", result, err)
+	}
+}
+
+func TestMessageBusSubscriptionCapacity(t *testing.T) {
+	bus := NewMessageBus(WithSubscriptionCapacity(2))
+	subscription, err := bus.Subscribe("updates")
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	// A subscriber that does not receive keeps only the latest messages
+	for i := 1; i <= 5; i++ {
+		_, _ = bus.Publish("updates", BusMessage{Content: fmt.Sprintf("update %d", i)})
+	}
+	pending := subscription.Drain()
+	if len(pending) != 2 || pending[0].Content != "update 4" || pending[1].Content != "update 5" {
+		t.Errorf("Expected the 2 latest messages, got %+v", pending)
+	}
+	if dropped := subscription.Dropped(); dropped != 3 {
+		t.Errorf("Expected 3 dropped messages, got %d", dropped)
+	}
+}
+
+func TestMessageBusKeepsOnlyChannelsWithSubscribers(t *testing.T) {
+	bus := NewMessageBus()
+
+	// Publishing on a channel nobody subscribed to drops the message without keeping the channel
+	msg, err := bus.Publish("nobody", BusMessage{Content: "hello"})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if msg.Sequence != 0 || len(bus.channels) != 0 {
+		t.Errorf("Expected a dropped message and no channel, got %+v and %d channels", msg, len(bus.channels))
+	}
+
+	first, _ := bus.Subscribe("updates")
+	second, _ := bus.Subscribe("updates")
+	_, _ = bus.Publish("updates", BusMessage{Content: "update 1"})
+
+	first.Unsubscribe()
+	if len(bus.channels) != 1 {
+		t.Errorf("Expected the channel to be kept while it has a subscriber, got %d channels", len(bus.channels))
+	}
+	second.Unsubscribe()
+	second.Unsubscribe()
+	if len(bus.channels) != 0 {
+		t.Errorf("Expected the channel to be deleted without subscribers, got %d channels", len(bus.channels))
+	}
+
+	// A new subscription starts a new sequence
+	third, _ := bus.Subscribe("updates")
+	if msg, _ := bus.Publish("updates", BusMessage{Content: "update 2"}); msg.Sequence != 1 {
+		t.Errorf("Expected the sequence to restart from 1, got %d", msg.Sequence)
+	}
+	third.Unsubscribe()
+}
@@ -0,0 +1,199 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// MessageBusTool lets an agent send messages on the channels of a MessageBus and receive the
+// messages of the channels it listens to. Listening starts when the tool is created, so messages
+// published on those channels from then on are kept until the agent receives them, up to the
+// subscription capacity of the bus.
+type MessageBusTool struct {
+	bus            *MessageBus
+	agentID        string
+	subscriptions  map[string]*Subscription
+	receiveTimeout time.Duration
+}
+
+// MessageBusToolOption configures a MessageBusTool
+type MessageBusToolOption func(*MessageBusTool)
+
+// WithReceiveTimeout sets how long receiving waits for a message when none is pending. By
+// default receiving returns immediately.
+func WithReceiveTimeout(timeout time.Duration) MessageBusToolOption {
+	return func(t *MessageBusTool) {
+		t.receiveTimeout = timeout
+	}
+}
+
+// NewMessageBusTool creates a message bus tool for the given agent, listening to the given
+// channels. Messages are sent on behalf of the agent, and its own messages are not received.
+func NewMessageBusTool(bus *MessageBus, agentID string, channels []string, options ...MessageBusToolOption) (*MessageBusTool, error) {
+	tool := &MessageBusTool{
+		bus:           bus,
+		agentID:       agentID,
+		subscriptions: make(map[string]*Subscription),
+	}
+	for _, option := range options {
+		option(tool)
+	}
+
+	for _, channel := range channels {
+		if _, exists := tool.subscriptions[channel]; exists {
+			continue
+		}
+		subscription, err := bus.Subscribe(channel)
+		if err != nil {
+			tool.Close()
+			return nil, fmt.Errorf("failed to listen to channel %s: %w", channel, err)
+		}
+		tool.subscriptions[channel] = subscription
+	}
+
+	return tool, nil
+}
+
+// Close stops listening to the channels of the tool
+func (t *MessageBusTool) Close() {
+	for _, subscription := range t.subscriptions {
+		subscription.Unsubscribe()
+	}
+}
+
+// Name returns the name of the tool
+func (t *MessageBusTool) Name() string {
+	return "message_bus"
+}
+
+// DisplayName implements interfaces.ToolWithDisplayName.DisplayName
+func (t *MessageBusTool) DisplayName() string {
+	return "Message Bus"
+}
+
+// Description returns a description of what the tool does
+func (t *MessageBusTool) Description() string {
+	return "Send messages to other agents on a named channel, or receive the new messages other agents sent on a channel"
+}
+
+// Internal implements interfaces.InternalTool.Internal
+func (t *MessageBusTool) Internal() bool {
+	return false
+}
+
+// Parameters returns the parameters that the tool accepts
+func (t *MessageBusTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"action": {
+			Type:        "string",
+			Description: "Whether to send a message or receive the new messages of the channel",
+			Required:    true,
+			Enum:        []interface{}{"send", "receive"},
+		},
+		"channel": {
+			Type:        "string",
+			Description: "The name of the channel",
+			Required:    true,
+		},
+		"content": {
+			Type:        "string",
+			Description: "The message to send, required to send",
+		},
+	}
+}
+
+// Run executes the tool with the given input
+func (t *MessageBusTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// Execute sends or receives messages of a channel
+func (t *MessageBusTool) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Action  string `json:"action"`
+		Channel string `json:"channel"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse message bus arguments: %w", err)
+	}
+	if params.Channel == "" {
+		return "", fmt.Errorf("channel parameter is required")
+	}
+
+	switch params.Action {
+	case "send":
+		if params.Content == "" {
+			return "", fmt.Errorf("content parameter is required to send a message")
+		}
+		msg, err := t.bus.Publish(params.Channel, BusMessage{From: t.agentID, Content: params.Content})
+		if err != nil {
+			return "", fmt.Errorf("failed to send message: %w", err)
+		}
+		if msg.Sequence == 0 {
+			return fmt.Sprintf("No agent is subscribed to channel %s, the message was dropped", params.Channel), nil
+		}
+		return fmt.Sprintf("Message %d sent on channel %s", msg.Sequence, params.Channel), nil
+	case "receive":
+		return t.receive(ctx, params.Channel)
+	default:
+		return "", fmt.Errorf("unknown action %q: must be send or receive", params.Action)
+	}
+}
+
+// receive returns the pending messages of a channel as JSON, waiting up to the receive timeout
+// for one if none is pending
+func (t *MessageBusTool) receive(ctx context.Context, channel string) (string, error) {
+	subscription, exists := t.subscriptions[channel]
+	if !exists {
+		return "", fmt.Errorf("not listening to channel %s", channel)
+	}
+
+	messages := t.fromOthers(subscription.Drain())
+	if len(messages) == 0 && t.receiveTimeout > 0 {
+		waitCtx, cancel := context.WithTimeout(ctx, t.receiveTimeout)
+		defer cancel()
+
+		for len(messages) == 0 {
+			msg, err := subscription.Receive(waitCtx)
+			if err != nil {
+				break
+			}
+			messages = t.fromOthers(append([]BusMessage{msg}, subscription.Drain()...))
+		}
+	}
+
+	if len(messages) == 0 {
+		return fmt.Sprintf("No new messages on channel %s", channel), nil
+	}
+
+	type receivedMessage struct {
+		Sequence int64  `json:"sequence"`
+		From     string `json:"from,omitempty"`
+		Content  string `json:"content"`
+	}
+	received := make([]receivedMessage, len(messages))
+	for i, msg := range messages {
+		received[i] = receivedMessage{Sequence: msg.Sequence, From: msg.From, Content: msg.Content}
+	}
+	result, err := json.Marshal(received)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode messages: %w", err)
+	}
+	return string(result), nil
+}
+
+// fromOthers drops the messages sent by the agent of the tool
+func (t *MessageBusTool) fromOthers(messages []BusMessage) []BusMessage {
+	filtered := messages[:0]
+	for _, msg := range messages {
+		if msg.From != t.agentID {
+			filtered = append(filtered, msg)
+		}
+	}
+	return filtered
+}
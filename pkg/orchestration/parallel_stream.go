@@ -0,0 +1,70 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// StreamParallel runs the input with the agents concurrently, such as for an ensemble of agents
+// or a request with several intents, and merges their stream events into a single channel. Each
+// event carries the ID of the agent that produced it in AgentID, and the events of an agent keep
+// their order. An agent failing to start its stream produces an event of type AgentEventError,
+// without stopping the others. The channel is closed once every agent stream has ended.
+func StreamParallel(ctx context.Context, input string, agents map[string]interfaces.StreamingAgent) (<-chan OrchestratorStreamEvent, error) {
+	if len(agents) == 0 {
+		return nil, errors.New("no agents to stream from")
+	}
+
+	// Start the agents in a stable order
+	agentIDs := make([]string, 0, len(agents))
+	for agentID := range agents {
+		agentIDs = append(agentIDs, agentID)
+	}
+	sort.Strings(agentIDs)
+
+	events := make(chan OrchestratorStreamEvent, 100)
+
+	var wg sync.WaitGroup
+	for _, agentID := range agentIDs {
+		wg.Add(1)
+		go func(agentID string, streamingAgent interfaces.StreamingAgent) {
+			defer wg.Done()
+			forwardAgentStream(ctx, agentID, streamingAgent, input, events)
+		}(agentID, agents[agentID])
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	return events, nil
+}
+
+// forwardAgentStream runs the input with the agent and forwards its stream events to events,
+// labeled with the agent ID, until the stream ends or the context is done
+func forwardAgentStream(ctx context.Context, agentID string, streamingAgent interfaces.StreamingAgent, input string, events chan<- OrchestratorStreamEvent) {
+	agentEvents, err := streamingAgent.RunStream(ctx, input)
+	if err != nil {
+		sendStreamError(ctx, events, agentID, fmt.Errorf("agent execution failed: %w", err))
+		return
+	}
+
+	for event := range agentEvents {
+		select {
+		case events <- OrchestratorStreamEvent{AgentID: agentID, Event: &event}:
+		case <-ctx.Done():
+			// Let the agent stream end without blocking on the remaining events
+			go func() {
+				for range agentEvents {
+				}
+			}()
+			return
+		}
+	}
+}
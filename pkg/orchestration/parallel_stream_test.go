@@ -0,0 +1,148 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestStreamParallel(t *testing.T) {
+	var mathInputs, historyInputs []string
+	agents := map[string]interfaces.StreamingAgent{
+		"math":    newStreamingAgent(t, &mathInputs, "2 + 2 ", "= 4"),
+		"history": newStreamingAgent(t, &historyInputs, "Rome was ", "not built ", "in a day"),
+	}
+
+	events, err := StreamParallel(context.Background(), "Answer both questions", agents)
+	if err != nil {
+		t.Fatalf("StreamParallel failed: %v", err)
+	}
+
+	content := map[string]*strings.Builder{"math": {}, "history": {}}
+	completions := map[string]int{}
+	for event := range events {
+		if event.Event == nil {
+			t.Fatalf("Expected an agent event, got %+v", event)
+		}
+		builder, ok := content[event.AgentID]
+		if !ok {
+			t.Fatalf("Unexpected agent ID %q", event.AgentID)
+		}
+		switch event.Event.Type {
+		case interfaces.AgentEventContent:
+			builder.WriteString(event.Event.Content)
+		case interfaces.AgentEventComplete:
+			completions[event.AgentID]++
+		case interfaces.AgentEventError:
+			t.Fatalf("Unexpected error: %v", event.Event.Error)
+		}
+	}
+
+	if content["math"].String() != "2 + 2 = 4" {
+		t.Errorf("Unexpected math content: %q", content["math"].String())
+	}
+	if content["history"].String() != "Rome was not built in a day" {
+		t.Errorf("Unexpected history content: %q", content["history"].String())
+	}
+	if completions["math"] != 1 || completions["history"] != 1 {
+		t.Errorf("Expected one completion per agent, got %v", completions)
+	}
+	if len(mathInputs) != 1 || len(historyInputs) != 1 || mathInputs[0] != "Answer both questions" {
+		t.Errorf("Expected each agent to run the input once, got %v and %v", mathInputs, historyInputs)
+	}
+}
+
+func TestStreamParallelConcurrent(t *testing.T) {
+	// Each agent waits for the other to start, which only completes if they run concurrently
+	started := map[string]chan struct{}{"first": make(chan struct{}), "second": make(chan struct{})}
+	newWaitingAgent := func(self, other string) *agent.Agent {
+		a, err := agent.NewAgent(
+			agent.WithLLM(&concurrencyLLM{}),
+			agent.WithCustomRunStreamFunction(func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+				events := make(chan interfaces.AgentStreamEvent)
+				go func() {
+					defer close(events)
+					close(started[self])
+					select {
+					case <-started[other]:
+					case <-time.After(time.Second):
+						events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventError, Error: errors.New("agents ran sequentially")}
+						return
+					}
+					events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: self, Timestamp: time.Now()}
+				}()
+				return events, nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+		return a
+	}
+
+	events, err := StreamParallel(context.Background(), "query", map[string]interfaces.StreamingAgent{
+		"first":  newWaitingAgent("first", "second"),
+		"second": newWaitingAgent("second", "first"),
+	})
+	if err != nil {
+		t.Fatalf("StreamParallel failed: %v", err)
+	}
+
+	for event := range events {
+		if event.Event.Type == interfaces.AgentEventError {
+			t.Fatalf("Unexpected error from %s: %v", event.AgentID, event.Event.Error)
+		}
+		if event.Event.Content != event.AgentID {
+			t.Errorf("Expected the event of %s to be labeled with its agent ID, got %s", event.Event.Content, event.AgentID)
+		}
+	}
+}
+
+func TestStreamParallelAgentFailure(t *testing.T) {
+	var inputs []string
+	failing, err := agent.NewAgent(
+		agent.WithLLM(&concurrencyLLM{}),
+		agent.WithCustomRunStreamFunction(func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+			return nil, errors.New("model unavailable")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	events, err := StreamParallel(context.Background(), "query", map[string]interfaces.StreamingAgent{
+		"failing": failing,
+		"working": newStreamingAgent(t, &inputs, "done"),
+	})
+	if err != nil {
+		t.Fatalf("StreamParallel failed: %v", err)
+	}
+
+	var failures []string
+	var content string
+	for event := range events {
+		switch event.Event.Type {
+		case interfaces.AgentEventError:
+			failures = append(failures, event.AgentID)
+		case interfaces.AgentEventContent:
+			content += event.Event.Content
+		}
+	}
+
+	// The failing agent does not stop the other one
+	if len(failures) != 1 || failures[0] != "failing" {
+		t.Errorf("Expected a single error labeled with the failing agent, got %v", failures)
+	}
+	if content != "done" {
+		t.Errorf("Expected the content of the working agent, got %q", content)
+	}
+
+	if _, err := StreamParallel(context.Background(), "query", nil); err == nil {
+		t.Error("Expected an error without agents")
+	}
+}
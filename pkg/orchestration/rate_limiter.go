@@ -0,0 +1,507 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// RateLimiter bounds the number of concurrent LLM calls shared by all agents of an orchestrator.
+// When any call is rate limited by the provider, every caller backs off until the shared
+// backoff window has elapsed, so a single limit hit slows the fleet instead of failing tasks.
+type RateLimiter struct {
+	slots          chan struct{}
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	maxRetries     int
+	logger         logging.Logger
+
+	mu           sync.Mutex
+	pausedUntil  time.Time
+	nextBackoff  time.Duration
+	waiting      int
+	rateLimitHit int64
+}
+
+// RateLimiterStats is a snapshot of the limiter state, usable as a backpressure signal
+type RateLimiterStats struct {
+	// MaxConcurrent is the configured ceiling of concurrent LLM calls
+	MaxConcurrent int
+	// InFlight is the number of LLM calls currently running
+	InFlight int
+	// Waiting is the number of callers waiting for a slot or for the backoff window
+	Waiting int
+	// BackoffUntil is the end of the current global backoff window, zero when not backing off
+	BackoffUntil time.Time
+	// RateLimitHits is the total number of rate limit errors observed
+	RateLimitHits int64
+}
+
+// RateLimiterOption represents an option for configuring a RateLimiter
+type RateLimiterOption func(*RateLimiter)
+
+// WithInitialBackoff sets the backoff applied after the first rate limit error
+func WithInitialBackoff(backoff time.Duration) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.initialBackoff = backoff
+	}
+}
+
+// WithMaxBackoff sets the upper bound of the global backoff window
+func WithMaxBackoff(backoff time.Duration) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.maxBackoff = backoff
+	}
+}
+
+// WithMaxRateLimitRetries sets how many times a rate limited call is retried before failing
+func WithMaxRateLimitRetries(retries int) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.maxRetries = retries
+	}
+}
+
+// WithRateLimiterLogger sets the logger for the rate limiter
+func WithRateLimiterLogger(logger logging.Logger) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.logger = logger
+	}
+}
+
+// NewRateLimiter creates a rate limiter allowing at most maxConcurrent LLM calls at a time
+func NewRateLimiter(maxConcurrent int, options ...RateLimiterOption) *RateLimiter {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	limiter := &RateLimiter{
+		slots:          make(chan struct{}, maxConcurrent),
+		initialBackoff: time.Second,
+		maxBackoff:     time.Minute,
+		maxRetries:     5,
+		logger:         logging.New(),
+	}
+
+	for _, option := range options {
+		option(limiter)
+	}
+
+	limiter.nextBackoff = limiter.initialBackoff
+	return limiter
+}
+
+// Stats returns a snapshot of the limiter state
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := RateLimiterStats{
+		MaxConcurrent: cap(l.slots),
+		InFlight:      len(l.slots),
+		Waiting:       l.waiting,
+		RateLimitHits: l.rateLimitHit,
+	}
+	if time.Now().Before(l.pausedUntil) {
+		stats.BackoffUntil = l.pausedUntil
+	}
+	return stats
+}
+
+// Acquire blocks until a slot is available and no global backoff is in effect.
+// Every successful Acquire must be paired with a call to Release.
+func (l *RateLimiter) Acquire(ctx context.Context) error {
+	l.mu.Lock()
+	l.waiting++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.waiting--
+		l.mu.Unlock()
+	}()
+
+	if err := l.waitForBackoff(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// The backoff window may have been extended while waiting for a slot
+	if err := l.waitForBackoff(ctx); err != nil {
+		<-l.slots
+		return err
+	}
+	return nil
+}
+
+// Release frees a slot acquired with Acquire
+func (l *RateLimiter) Release() {
+	<-l.slots
+}
+
+// Do runs fn within a slot, retrying after a global backoff when fn fails with a rate limit error
+func (l *RateLimiter) Do(ctx context.Context, fn func() error) error {
+	return l.do(ctx, func(*limitedCall) error {
+		return fn()
+	})
+}
+
+// do runs fn within a slot held by a limitedCall, retrying after a global backoff when fn fails
+// with a rate limit error before any tool of the call ran
+func (l *RateLimiter) do(ctx context.Context, fn func(call *limitedCall) error) error {
+	for attempt := 0; ; attempt++ {
+		call := &limitedCall{limiter: l}
+		if err := call.acquire(ctx); err != nil {
+			return err
+		}
+		err := fn(call)
+		call.release()
+
+		if err == nil {
+			l.recordSuccess()
+			return nil
+		}
+		if !IsRateLimitError(err) {
+			return err
+		}
+
+		backoff := l.recordRateLimit()
+		// Retrying would run the tools again and record their calls in memory twice
+		if call.ranTools() {
+			return err
+		}
+		if attempt >= l.maxRetries {
+			return fmt.Errorf("rate limit retries exhausted after %d attempts: %w", attempt+1, err)
+		}
+		l.logger.Warn(ctx, "LLM call rate limited, backing off", map[string]interface{}{
+			"attempt": attempt + 1,
+			"backoff": backoff.String(),
+			"error":   err.Error(),
+		})
+	}
+}
+
+// waitForBackoff blocks until the global backoff window has elapsed
+func (l *RateLimiter) waitForBackoff(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := time.Until(l.pausedUntil)
+		l.mu.Unlock()
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// recordRateLimit extends the global backoff window and returns its length
+func (l *RateLimiter) recordRateLimit() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rateLimitHit++
+	now := time.Now()
+
+	// Concurrent callers hitting the same limit share one window instead of compounding it
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	backoff := l.nextBackoff
+	l.pausedUntil = now.Add(backoff)
+	l.nextBackoff *= 2
+	if l.maxBackoff > 0 && l.nextBackoff > l.maxBackoff {
+		l.nextBackoff = l.maxBackoff
+	}
+	return backoff
+}
+
+// recordSuccess resets the backoff growth after a successful call
+func (l *RateLimiter) recordSuccess() {
+	l.mu.Lock()
+	l.nextBackoff = l.initialBackoff
+	l.mu.Unlock()
+}
+
+// rateLimitStatusPattern matches the HTTP 429 status in the errors of the providers, such as
+// "HTTP 429", "status code: 429", "Error 429" or "429 Too Many Requests"
+var rateLimitStatusPattern = regexp.MustCompile(`(?i)\b(?:http|status|status code|error)[ :=]*429\b|\b429 too many requests\b`)
+
+// IsRateLimitError reports whether err looks like a provider rate limit (HTTP 429) error
+func IsRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return rateLimitStatusPattern.MatchString(msg) ||
+		strings.Contains(msg, "rate limit") ||
+		strings.Contains(msg, "rate_limit") ||
+		strings.Contains(msg, "too many requests") ||
+		strings.Contains(msg, "resource_exhausted")
+}
+
+// WrapLLM returns an LLM whose calls are bounded by the limiter.
+// Streaming support and the model of the wrapped LLM are preserved. Wrapping an LLM already
+// bounded by a limiter replaces that limiter instead of adding a second one.
+func (l *RateLimiter) WrapLLM(llm interfaces.LLM) interfaces.LLM {
+	if llm == nil {
+		return nil
+	}
+	if limited, ok := llm.(rateLimited); ok {
+		if limited.limiterOf() == l {
+			return llm
+		}
+		llm = limited.unwrap()
+	}
+
+	base := &rateLimitedLLM{llm: llm, limiter: l}
+	if streaming, ok := llm.(interfaces.StreamingLLM); ok {
+		return &rateLimitedStreamingLLM{rateLimitedLLM: base, streaming: streaming}
+	}
+	return base
+}
+
+// rateLimited is implemented by LLMs wrapped by a RateLimiter
+type rateLimited interface {
+	limiterOf() *RateLimiter
+	unwrap() interfaces.LLM
+}
+
+// limitedCall is an LLM call holding a slot of a RateLimiter. The slot is released while the
+// tools of the call run, so that it is only held by the requests of the tool loop: a tool calling
+// an agent bounded by the same limiter can't wait forever for a slot held by its caller.
+type limitedCall struct {
+	limiter *RateLimiter
+
+	mu       sync.Mutex
+	held     bool
+	running  int
+	toolsRan bool
+}
+
+// acquire takes a slot for the call
+func (c *limitedCall) acquire(ctx context.Context) error {
+	if err := c.limiter.Acquire(ctx); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.held = true
+	c.mu.Unlock()
+	return nil
+}
+
+// release frees the slot of the call, if held
+func (c *limitedCall) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.held {
+		c.limiter.Release()
+		c.held = false
+	}
+}
+
+// ranTools reports whether a tool of the call ran
+func (c *limitedCall) ranTools() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.toolsRan
+}
+
+// runTool runs a tool of the call without the slot, taking the slot back for the next request
+// once no tool of the call is running
+func (c *limitedCall) runTool(ctx context.Context, run func() (string, error)) (string, error) {
+	c.mu.Lock()
+	c.toolsRan = true
+	c.running++
+	if c.held {
+		c.limiter.Release()
+		c.held = false
+	}
+	c.mu.Unlock()
+
+	result, err := run()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running--
+	if c.running == 0 && !c.held {
+		if acquireErr := c.limiter.Acquire(ctx); acquireErr != nil {
+			return "", acquireErr
+		}
+		c.held = true
+	}
+	return result, err
+}
+
+// wrapTools returns the tools of the call, releasing the slot while they run
+func (c *limitedCall) wrapTools(tools []interfaces.Tool) []interfaces.Tool {
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &limitedTool{ToolWrapper: interfaces.ToolWrapper{Tool: tool}, call: c}
+	}
+	return wrapped
+}
+
+// limitedTool runs a tool of a limitedCall without the slot of the call
+type limitedTool struct {
+	interfaces.ToolWrapper
+	call *limitedCall
+}
+
+// Run executes the tool with the given input
+func (t *limitedTool) Run(ctx context.Context, input string) (string, error) {
+	return t.call.runTool(ctx, func() (string, error) {
+		return t.Tool.Run(ctx, input)
+	})
+}
+
+// Execute executes the tool with the given arguments
+func (t *limitedTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.call.runTool(ctx, func() (string, error) {
+		return interfaces.ExecuteTool(ctx, t.Tool, args)
+	})
+}
+
+// rateLimitedLLM bounds the calls of an LLM with a RateLimiter
+type rateLimitedLLM struct {
+	llm     interfaces.LLM
+	limiter *RateLimiter
+}
+
+func (r *rateLimitedLLM) limiterOf() *RateLimiter {
+	return r.limiter
+}
+
+func (r *rateLimitedLLM) unwrap() interfaces.LLM {
+	return r.llm
+}
+
+// Generate implements interfaces.LLM
+func (r *rateLimitedLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	var response string
+	err := r.limiter.Do(ctx, func() error {
+		var err error
+		response, err = r.llm.Generate(ctx, prompt, options...)
+		return err
+	})
+	return response, err
+}
+
+// GenerateWithTools implements interfaces.LLM. The slot is held by each request of the tool
+// loop, not while the tools run, and the call is not retried once a tool ran.
+func (r *rateLimitedLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	var response string
+	err := r.limiter.do(ctx, func(call *limitedCall) error {
+		var err error
+		response, err = r.llm.GenerateWithTools(ctx, prompt, call.wrapTools(tools), options...)
+		return err
+	})
+	return response, err
+}
+
+// Name implements interfaces.LLM
+func (r *rateLimitedLLM) Name() string {
+	return r.llm.Name()
+}
+
+// SupportsStreaming implements interfaces.LLM
+func (r *rateLimitedLLM) SupportsStreaming() bool {
+	return r.llm.SupportsStreaming()
+}
+
+// GetModel returns the model of the wrapped LLM, falling back to its name, so that features
+// keyed on the model such as context window trimming and cost estimation keep working
+func (r *rateLimitedLLM) GetModel() string {
+	if modelLLM, ok := r.llm.(interface{ GetModel() string }); ok {
+		if model := modelLLM.GetModel(); model != "" {
+			return model
+		}
+	}
+	return r.llm.Name()
+}
+
+// rateLimitedStreamingLLM bounds the calls of a streaming LLM with a RateLimiter.
+// A slot is held until the stream is closed, except while the tools of the stream run.
+type rateLimitedStreamingLLM struct {
+	*rateLimitedLLM
+	streaming interfaces.StreamingLLM
+}
+
+// GenerateStream implements interfaces.StreamingLLM
+func (r *rateLimitedStreamingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return r.stream(ctx, func(*limitedCall) (<-chan interfaces.StreamEvent, error) {
+		return r.streaming.GenerateStream(ctx, prompt, options...)
+	})
+}
+
+// GenerateWithToolsStream implements interfaces.StreamingLLM
+func (r *rateLimitedStreamingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return r.stream(ctx, func(call *limitedCall) (<-chan interfaces.StreamEvent, error) {
+		return r.streaming.GenerateWithToolsStream(ctx, prompt, call.wrapTools(tools), options...)
+	})
+}
+
+// stream starts a stream within a slot, retrying rate limited starts, and releases the slot when the stream ends
+func (r *rateLimitedStreamingLLM) stream(ctx context.Context, start func(call *limitedCall) (<-chan interfaces.StreamEvent, error)) (<-chan interfaces.StreamEvent, error) {
+	l := r.limiter
+	for attempt := 0; ; attempt++ {
+		call := &limitedCall{limiter: l}
+		if err := call.acquire(ctx); err != nil {
+			return nil, err
+		}
+
+		events, err := start(call)
+		if err == nil {
+			out := make(chan interfaces.StreamEvent)
+			go func() {
+				defer close(out)
+				defer call.release()
+				for event := range events {
+					if event.Type == interfaces.StreamEventError && IsRateLimitError(event.Error) {
+						l.recordRateLimit()
+					}
+					select {
+					case out <- event:
+					case <-ctx.Done():
+						// Keep draining so the underlying stream can finish
+					}
+				}
+			}()
+			return out, nil
+		}
+
+		call.release()
+		if !IsRateLimitError(err) {
+			return nil, err
+		}
+
+		backoff := l.recordRateLimit()
+		if call.ranTools() {
+			return nil, err
+		}
+		if attempt >= l.maxRetries {
+			return nil, fmt.Errorf("rate limit retries exhausted after %d attempts: %w", attempt+1, err)
+		}
+		l.logger.Warn(ctx, "LLM stream rate limited, backing off", map[string]interface{}{
+			"attempt": attempt + 1,
+			"backoff": backoff.String(),
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// concurrencyLLM records the peak number of concurrent calls, failing the first rateLimited calls with a 429
+type concurrencyLLM struct {
+	delay       time.Duration
+	rateLimited int32
+	running     int32
+	peak        int32
+	calls       int32
+}
+
+func (m *concurrencyLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	atomic.AddInt32(&m.calls, 1)
+	current := atomic.AddInt32(&m.running, 1)
+	defer atomic.AddInt32(&m.running, -1)
+	for {
+		peak := atomic.LoadInt32(&m.peak)
+		if current <= peak || atomic.CompareAndSwapInt32(&m.peak, peak, current) {
+			break
+		}
+	}
+
+	time.Sleep(m.delay)
+	if atomic.AddInt32(&m.rateLimited, -1) >= 0 {
+		return "", fmt.Errorf("API error: status code 429: Too Many Requests")
+	}
+	return "ok", nil
+}
+
+func (m *concurrencyLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *concurrencyLLM) Name() string            { return "concurrency-mock" }
+func (m *concurrencyLLM) SupportsStreaming() bool { return false }
+
+func TestRateLimiterCapsConcurrentCallsAcrossAgents(t *testing.T) {
+	llm := &concurrencyLLM{delay: 10 * time.Millisecond}
+	registry := NewAgentRegistry()
+	for i := 0; i < 4; i++ {
+		a, err := agent.NewAgent(agent.WithLLM(llm), agent.WithName(fmt.Sprintf("agent-%d", i)))
+		if err != nil {
+			t.Fatalf("failed to create agent: %v", err)
+		}
+		registry.Register(fmt.Sprintf("agent-%d", i), a)
+	}
+
+	limiter := NewRateLimiter(3)
+	NewCodeOrchestrator(registry).WithRateLimiter(limiter)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			a, _ := registry.Get(fmt.Sprintf("agent-%d", i%4))
+			if _, err := a.Run(context.Background(), "hello"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if llm.calls < 20 {
+		t.Errorf("expected at least 20 LLM calls, got %d", llm.calls)
+	}
+	if llm.peak > 3 {
+		t.Errorf("expected at most 3 concurrent LLM calls, got %d", llm.peak)
+	}
+	if stats := limiter.Stats(); stats.InFlight != 0 || stats.Waiting != 0 {
+		t.Errorf("expected limiter to be idle, got %+v", stats)
+	}
+}
+
+func TestRateLimiterBacksOffGloballyOnRateLimit(t *testing.T) {
+	llm := &concurrencyLLM{rateLimited: 1}
+	limiter := NewRateLimiter(2, WithInitialBackoff(50*time.Millisecond))
+	wrapped := limiter.WrapLLM(llm)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Stagger the callers so later ones arrive during the backoff window
+			time.Sleep(time.Duration(i) * 10 * time.Millisecond)
+			if _, err := wrapped.Generate(context.Background(), "hello"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected callers to wait for the backoff window, finished in %v", elapsed)
+	}
+	if hits := limiter.Stats().RateLimitHits; hits != 1 {
+		t.Errorf("expected 1 rate limit hit, got %d", hits)
+	}
+	if llm.calls != 5 {
+		t.Errorf("expected the rate limited call to be retried once, got %d calls", llm.calls)
+	}
+}
+
+func TestRateLimiterRetriesExhausted(t *testing.T) {
+	llm := &concurrencyLLM{rateLimited: 10}
+	limiter := NewRateLimiter(1, WithInitialBackoff(time.Millisecond), WithMaxRateLimitRetries(2))
+
+	_, err := limiter.WrapLLM(llm).Generate(context.Background(), "hello")
+	if err == nil || !IsRateLimitError(err) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if llm.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", llm.calls)
+	}
+	if IsRateLimitError(fmt.Errorf("invalid request")) {
+		t.Errorf("expected non rate limit error to be ignored")
+	}
+}
+
+// modelLLM reports the model it runs
+type modelLLM struct {
+	concurrencyLLM
+}
+
+func (m *modelLLM) GetModel() string { return "gpt-4o-mini" }
+
+func TestRateLimiterPreservesModel(t *testing.T) {
+	limiter := NewRateLimiter(1)
+
+	wrapped, ok := limiter.WrapLLM(&modelLLM{}).(interface{ GetModel() string })
+	if !ok || wrapped.GetModel() != "gpt-4o-mini" {
+		t.Errorf("expected the model of the wrapped LLM to be reported")
+	}
+
+	wrapped, ok = limiter.WrapLLM(&concurrencyLLM{}).(interface{ GetModel() string })
+	if !ok || wrapped.GetModel() != "concurrency-mock" {
+		t.Errorf("expected the name of a wrapped LLM without a model")
+	}
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	for _, msg := range []string{
+		"error from Anthropic API: HTTP 429 - overloaded",
+		"API request failed with status 429: slow down",
+		"API error: status code: 429",
+		`POST "https://api.openai.com/v1/chat/completions": 429 Too Many Requests`,
+		"Error 429, Message: Resource has been exhausted",
+		`{"type":"rate_limit_error"}`,
+	} {
+		if !IsRateLimitError(fmt.Errorf("%s", msg)) {
+			t.Errorf("expected %q to be a rate limit error", msg)
+		}
+	}
+	for _, msg := range []string{
+		"prompt is too long: 14290 tokens",
+		"tool lookup_order failed for order 429",
+		"invalid request",
+	} {
+		if IsRateLimitError(fmt.Errorf("%s", msg)) {
+			t.Errorf("expected %q not to be a rate limit error", msg)
+		}
+	}
+}
+
+// toolCallingLLM runs each tool once, then fails with err
+type toolCallingLLM struct {
+	concurrencyLLM
+	err error
+}
+
+func (m *toolCallingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	atomic.AddInt32(&m.calls, 1)
+	for _, tool := range tools {
+		if _, err := tool.Execute(ctx, "{}"); err != nil {
+			return "", err
+		}
+	}
+	if m.err != nil {
+		return "", m.err
+	}
+	return "ok", nil
+}
+
+// funcTool runs fn
+type funcTool struct {
+	fn func(ctx context.Context) (string, error)
+}
+
+func (t *funcTool) Name() string                                             { return "func_tool" }
+func (t *funcTool) Description() string                                      { return "Runs a function" }
+func (t *funcTool) Parameters() map[string]interfaces.ParameterSpec          { return nil }
+func (t *funcTool) Run(ctx context.Context, input string) (string, error)    { return t.fn(ctx) }
+func (t *funcTool) Execute(ctx context.Context, args string) (string, error) { return t.fn(ctx) }
+
+func TestRateLimiterReleasesSlotWhileToolsRun(t *testing.T) {
+	limiter := NewRateLimiter(1, WithInitialBackoff(time.Millisecond))
+	inner := limiter.WrapLLM(&concurrencyLLM{})
+
+	// A tool calling an LLM bounded by the same limiter gets the slot of its caller
+	var inFlight int
+	tool := &funcTool{fn: func(ctx context.Context) (string, error) {
+		inFlight = limiter.Stats().InFlight
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		return inner.Generate(ctx, "nested")
+	}}
+
+	wrapped := limiter.WrapLLM(&toolCallingLLM{})
+	if _, err := wrapped.GenerateWithTools(context.Background(), "hello", []interfaces.Tool{tool}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inFlight != 0 {
+		t.Errorf("expected no slot held while the tool runs, got %d", inFlight)
+	}
+	if stats := limiter.Stats(); stats.InFlight != 0 {
+		t.Errorf("expected every slot to be released, got %d", stats.InFlight)
+	}
+}
+
+func TestRateLimiterDoesNotRetryAfterTools(t *testing.T) {
+	limiter := NewRateLimiter(1, WithInitialBackoff(time.Millisecond))
+	llm := &toolCallingLLM{err: fmt.Errorf("HTTP 429")}
+
+	var runs int32
+	tool := &funcTool{fn: func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&runs, 1)
+		return "done", nil
+	}}
+
+	_, err := limiter.WrapLLM(llm).GenerateWithTools(context.Background(), "hello", []interfaces.Tool{tool})
+	if err == nil || !IsRateLimitError(err) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if llm.calls != 1 || runs != 1 {
+		t.Errorf("expected a single attempt running the tool once, got %d calls and %d runs", llm.calls, runs)
+	}
+	if hits := limiter.Stats().RateLimitHits; hits != 1 {
+		t.Errorf("expected the rate limit to be recorded, got %d hits", hits)
+	}
+}
+
+func TestRateLimiterWrapReplacesLimiter(t *testing.T) {
+	llm := &concurrencyLLM{}
+	first := NewRateLimiter(1)
+	second := NewRateLimiter(1)
+
+	wrapped := first.WrapLLM(llm)
+	if first.WrapLLM(wrapped) != wrapped {
+		t.Error("expected wrapping twice with the same limiter to be a no-op")
+	}
+
+	rewrapped := second.WrapLLM(wrapped)
+	limited, ok := rewrapped.(rateLimited)
+	if !ok || limited.limiterOf() != second || limited.unwrap() != interfaces.LLM(llm) {
+		t.Errorf("expected the limiter to be replaced, got %#v", rewrapped)
+	}
+}
@@ -0,0 +1,163 @@
+package orchestration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TaskError is the error of a failed task
+type TaskError struct {
+	// TaskID is the ID of the failed task
+	TaskID string
+
+	// Err is the error of the task
+	Err error
+}
+
+// WorkflowSummary reports the outcome of an executed workflow
+type WorkflowSummary struct {
+	// Completed is the number of completed tasks
+	Completed int
+
+	// Failed is the number of failed tasks
+	Failed int
+
+	// Skipped is the number of tasks that were not run
+	Skipped int
+
+	// ChainErrors holds the first error of each dependency chain that did not complete, keyed
+	// by the ID of the task ending the chain: the final task, or a task no other task depends
+	// on. The first error is the earliest failure among the task and its transitive dependencies.
+	ChainErrors map[string]TaskError
+
+	// FinalDependencies reports for each dependency of the final task whether it completed
+	FinalDependencies map[string]bool
+}
+
+// Summary reports the outcome of the workflow once it has been executed
+func (w *Workflow) Summary() WorkflowSummary {
+	summary := WorkflowSummary{
+		ChainErrors:       make(map[string]TaskError),
+		FinalDependencies: make(map[string]bool),
+	}
+
+	tasks := make(map[string]*Task, len(w.Tasks))
+	dependedOn := make(map[string]bool)
+	for _, task := range w.Tasks {
+		tasks[task.ID] = task
+		for _, depID := range task.Dependencies {
+			dependedOn[depID] = true
+		}
+
+		switch task.Status {
+		case TaskCompleted:
+			summary.Completed++
+		case TaskFailed:
+			summary.Failed++
+		case TaskSkipped:
+			summary.Skipped++
+		}
+	}
+
+	// Rank the failures by the order they happened in
+	failureRank := make(map[string]int, len(w.failureOrder))
+	for i, taskID := range w.failureOrder {
+		failureRank[taskID] = i
+	}
+
+	for _, task := range w.Tasks {
+		if task.Status == TaskCompleted || (dependedOn[task.ID] && task.ID != w.FinalTaskID) {
+			continue
+		}
+		if first, ok := w.firstChainError(task, tasks, failureRank); ok {
+			summary.ChainErrors[task.ID] = first
+		}
+	}
+
+	if final, ok := tasks[w.FinalTaskID]; ok {
+		for _, depID := range final.Dependencies {
+			dep, ok := tasks[depID]
+			summary.FinalDependencies[depID] = ok && dep.Status == TaskCompleted
+		}
+	}
+
+	return summary
+}
+
+// firstChainError returns the earliest failure among the task and its transitive dependencies
+func (w *Workflow) firstChainError(task *Task, tasks map[string]*Task, failureRank map[string]int) (TaskError, bool) {
+	var first TaskError
+	found := false
+	firstRank := 0
+
+	visited := make(map[string]bool)
+	stack := []*Task{task}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[current.ID] {
+			continue
+		}
+		visited[current.ID] = true
+
+		if current.Status == TaskFailed {
+			rank, ranked := failureRank[current.ID]
+			if !ranked {
+				rank = len(failureRank)
+			}
+			if !found || rank < firstRank {
+				first = TaskError{TaskID: current.ID, Err: w.taskError(current)}
+				firstRank = rank
+				found = true
+			}
+		}
+
+		for _, depID := range current.Dependencies {
+			if dep, ok := tasks[depID]; ok {
+				stack = append(stack, dep)
+			}
+		}
+	}
+
+	return first, found
+}
+
+// taskError returns the error of a failed task
+func (w *Workflow) taskError(task *Task) error {
+	if task.Error != nil {
+		return task.Error
+	}
+	return w.Errors[task.ID]
+}
+
+// String formats the summary for logging
+func (s WorkflowSummary) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d completed, %d failed, %d skipped", s.Completed, s.Failed, s.Skipped))
+
+	for _, taskID := range sortedKeys(s.ChainErrors) {
+		first := s.ChainErrors[taskID]
+		sb.WriteString(fmt.Sprintf("\n- %s did not complete: task %s failed: %v", taskID, first.TaskID, first.Err))
+	}
+
+	for _, depID := range sortedKeys(s.FinalDependencies) {
+		status := "satisfied"
+		if !s.FinalDependencies[depID] {
+			status = "not satisfied"
+		}
+		sb.WriteString(fmt.Sprintf("\n- final task dependency %s %s", depID, status))
+	}
+
+	return sb.String()
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,216 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// SpendRecord reports the cost of an LLM call made through an LLMMiddleware
+type SpendRecord struct {
+	// ConversationID is the conversation of the call, from memory.WithConversationID, or empty
+	ConversationID string
+	// Model is the model that served the call
+	Model string
+	// Usage is the number of tokens consumed by the call
+	Usage interfaces.TokenUsage
+	// Cost is the cost of the call in USD
+	Cost float64
+	// TotalCost is the spend of the conversation in USD so far, including this call
+	TotalCost float64
+	// Err is set when the cost of the call could not be estimated, e.g. for an unknown model
+	Err error
+	// Timestamp is the time the call finished
+	Timestamp time.Time
+}
+
+// Sink receives the spend of the LLM calls made through an LLMMiddleware
+type Sink interface {
+	Record(ctx context.Context, record SpendRecord)
+}
+
+// SinkFunc adapts a function to a Sink
+type SinkFunc func(ctx context.Context, record SpendRecord)
+
+// Record implements Sink
+func (f SinkFunc) Record(ctx context.Context, record SpendRecord) {
+	f(ctx, record)
+}
+
+// LLMMiddleware wraps an LLM, accumulating the spend of its calls per conversation and
+// reporting each call to a sink. Calls are priced from the token usage the provider reports:
+// the completion summaries of non-streaming calls, see llm.WithCompletionObserver, and the
+// usage events of streams. Calls without reported usage are not charged.
+type LLMMiddleware struct {
+	llm  interfaces.LLM
+	sink Sink
+
+	mu     sync.Mutex
+	totals map[string]float64
+}
+
+// NewLLMMiddleware creates a middleware reporting the spend of the calls to llm to sink.
+// sink may be nil to only accumulate the spend, see TotalSpend.
+func NewLLMMiddleware(llm interfaces.LLM, sink Sink) *LLMMiddleware {
+	return &LLMMiddleware{
+		llm:    llm,
+		sink:   sink,
+		totals: make(map[string]float64),
+	}
+}
+
+// TotalSpend returns the spend in USD of the calls made for a conversation. Calls made without
+// a conversation ID are accumulated under the empty ID.
+func (m *LLMMiddleware) TotalSpend(conversationID string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.totals[conversationID]
+}
+
+// Generate generates text from a prompt, recording its spend
+func (m *LLMMiddleware) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return m.llm.Generate(m.observe(ctx), prompt, options...)
+}
+
+// GenerateWithTools generates text from a prompt with tools, recording the spend of every call
+// of the tool calling loop
+func (m *LLMMiddleware) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.llm.GenerateWithTools(m.observe(ctx), prompt, tools, options...)
+}
+
+// GenerateStream streams text from a prompt, recording its spend when the stream reports usage
+func (m *LLMMiddleware) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	streamingLLM, ok := m.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("LLM %s does not support streaming", m.llm.Name())
+	}
+
+	events, err := streamingLLM.GenerateStream(ctx, prompt, options...)
+	if err != nil {
+		return nil, err
+	}
+	return m.relay(ctx, events), nil
+}
+
+// GenerateWithToolsStream streams text from a prompt with tools, recording its spend when the
+// stream reports usage
+func (m *LLMMiddleware) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	streamingLLM, ok := m.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("LLM %s does not support streaming", m.llm.Name())
+	}
+
+	events, err := streamingLLM.GenerateWithToolsStream(ctx, prompt, tools, options...)
+	if err != nil {
+		return nil, err
+	}
+	return m.relay(ctx, events), nil
+}
+
+// Name returns the name of the wrapped LLM
+func (m *LLMMiddleware) Name() string {
+	return m.llm.Name()
+}
+
+// SupportsStreaming returns whether the wrapped LLM supports streaming
+func (m *LLMMiddleware) SupportsStreaming() bool {
+	return m.llm.SupportsStreaming()
+}
+
+// GetModel returns the model of the wrapped LLM, if it reports one
+func (m *LLMMiddleware) GetModel() string {
+	return m.model()
+}
+
+// model returns the model of the wrapped LLM, falling back to its name
+func (m *LLMMiddleware) model() string {
+	if modelLLM, ok := m.llm.(interface{ GetModel() string }); ok {
+		if model := modelLLM.GetModel(); model != "" {
+			return model
+		}
+	}
+	return m.llm.Name()
+}
+
+// observe returns a context recording the spend of the LLM calls reported with it
+func (m *LLMMiddleware) observe(ctx context.Context) context.Context {
+	return llm.WithCompletionObserver(ctx, func(ctx context.Context, summary llm.CompletionSummary) {
+		if summary.PromptTokens == 0 && summary.ResponseTokens == 0 {
+			return
+		}
+		model := summary.Model
+		if model == "" {
+			model = m.model()
+		}
+		m.record(ctx, model, interfaces.TokenUsage{
+			InputTokens:  summary.PromptTokens,
+			OutputTokens: summary.ResponseTokens,
+		})
+	})
+}
+
+// relay forwards the events of a stream, recording its spend from the last usage event, which
+// holds the cumulative usage of the stream, once the stream ends
+func (m *LLMMiddleware) relay(ctx context.Context, events <-chan interfaces.StreamEvent) <-chan interfaces.StreamEvent {
+	out := make(chan interfaces.StreamEvent, cap(events))
+
+	go func() {
+		defer close(out)
+
+		var usage *interfaces.TokenUsage
+		defer func() {
+			if usage != nil {
+				m.record(ctx, m.model(), *usage)
+			}
+		}()
+
+		for event := range events {
+			if event.Type == interfaces.StreamEventUsage && event.Usage != nil {
+				current := *event.Usage
+				usage = &current
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				go func() {
+					for range events {
+					}
+				}()
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// record prices a call, adds it to the spend of its conversation and reports it to the sink
+func (m *LLMMiddleware) record(ctx context.Context, model string, usage interfaces.TokenUsage) {
+	conversationID, _ := memory.GetConversationID(ctx)
+	record := SpendRecord{
+		ConversationID: conversationID,
+		Model:          model,
+		Usage:          usage,
+		Timestamp:      time.Now(),
+	}
+
+	cost, err := Estimate(model, usage)
+	if err != nil {
+		record.Err = err
+	}
+	record.Cost = cost
+
+	m.mu.Lock()
+	m.totals[conversationID] += cost
+	record.TotalCost = m.totals[conversationID]
+	m.mu.Unlock()
+
+	if m.sink != nil {
+		m.sink.Record(ctx, record)
+	}
+}
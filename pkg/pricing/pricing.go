@@ -0,0 +1,36 @@
+// Package pricing estimates the cost in USD of LLM calls from their token usage and tracks
+// the spend of conversations.
+package pricing
+
+import (
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+// Estimate returns the cost in USD of a call to the model that consumed the given tokens.
+// Prices come from the built-in price table of OpenAI, Anthropic and Gemini models, see
+// llm.LookupModelPrice, and from models added with RegisterModel. Unknown models return an error.
+func Estimate(model string, usage interfaces.TokenUsage) (float64, error) {
+	if usage.InputTokens < 0 || usage.OutputTokens < 0 {
+		return 0, fmt.Errorf("token counts must not be negative")
+	}
+
+	price, ok := llm.LookupModelPrice(model)
+	if !ok {
+		return 0, fmt.Errorf("no price known for model %q", model)
+	}
+
+	return price.Cost(usage.InputTokens, usage.OutputTokens), nil
+}
+
+// RegisterModel adds or replaces the price of a model, e.g. a fine-tuned model or a negotiated
+// price, given in USD per million input and output tokens. The price applies to llm.EstimateCost
+// as well.
+func RegisterModel(model string, inputPer1M, outputPer1M float64) {
+	price, _ := llm.LookupModelPrice(model)
+	price.InputPerMillion = inputPer1M
+	price.OutputPerMillion = outputPer1M
+	llm.RegisterModelPrice(model, price)
+}
@@ -0,0 +1,192 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+func TestEstimate(t *testing.T) {
+	cost, err := Estimate("gpt-4o-2024-08-06", interfaces.TokenUsage{InputTokens: 1000000, OutputTokens: 500000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(cost-7.5) > 1e-9 {
+		t.Errorf("expected $7.50, got %f", cost)
+	}
+
+	if _, err := Estimate("unknown-model", interfaces.TokenUsage{InputTokens: 10}); err == nil {
+		t.Error("expected an error for an unknown model")
+	}
+	if _, err := Estimate("gpt-4o", interfaces.TokenUsage{InputTokens: -1}); err == nil {
+		t.Error("expected an error for negative token counts")
+	}
+}
+
+func TestRegisterModel(t *testing.T) {
+	RegisterModel("ft:gpt-4o-mini:acme", 0.30, 1.20)
+
+	cost, err := Estimate("ft:gpt-4o-mini:acme", interfaces.TokenUsage{InputTokens: 1000000, OutputTokens: 1000000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(cost-1.5) > 1e-9 {
+		t.Errorf("expected $1.50, got %f", cost)
+	}
+}
+
+// usageLLM reports the given usage for each call, like the providers do
+type usageLLM struct {
+	usage  interfaces.TokenUsage
+	stream bool
+}
+
+func (l *usageLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	llm.LogCompletion(ctx, nil, llm.CompletionLogLevelNone, llm.CompletionSummary{
+		Provider:       "test",
+		Model:          "gpt-4o",
+		PromptTokens:   l.usage.InputTokens,
+		ResponseTokens: l.usage.OutputTokens,
+	})
+	return "done", nil
+}
+
+func (l *usageLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	// A tool calling loop making two requests
+	if _, err := l.Generate(ctx, prompt, options...); err != nil {
+		return "", err
+	}
+	return l.Generate(ctx, prompt, options...)
+}
+
+func (l *usageLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	events := make(chan interfaces.StreamEvent, 3)
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "done", Timestamp: time.Now()}
+	// Usage events are cumulative
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventUsage, Usage: &interfaces.TokenUsage{InputTokens: l.usage.InputTokens}, Timestamp: time.Now()}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventUsage, Usage: &l.usage, Timestamp: time.Now()}
+	close(events)
+	return events, nil
+}
+
+func (l *usageLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return l.GenerateStream(ctx, prompt, options...)
+}
+
+func (l *usageLLM) Name() string            { return "test" }
+func (l *usageLLM) SupportsStreaming() bool { return l.stream }
+func (l *usageLLM) GetModel() string        { return "gpt-4o" }
+
+func TestLLMMiddlewareAccumulatesSpendPerConversation(t *testing.T) {
+	var mu sync.Mutex
+	var records []SpendRecord
+	sink := SinkFunc(func(ctx context.Context, record SpendRecord) {
+		mu.Lock()
+		defer mu.Unlock()
+		records = append(records, record)
+	})
+
+	// $0.0025 input and $0.01 output per call
+	middleware := NewLLMMiddleware(&usageLLM{usage: interfaces.TokenUsage{InputTokens: 1000, OutputTokens: 1000}, stream: true}, sink)
+
+	first := memory.WithConversationID(context.Background(), "first")
+	second := memory.WithConversationID(context.Background(), "second")
+
+	if _, err := middleware.Generate(first, "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := middleware.GenerateWithTools(first, "hello", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events, err := middleware.GenerateStream(second, "hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var relayed int
+	for range events {
+		relayed++
+	}
+	if relayed != 3 {
+		t.Errorf("expected all stream events to be relayed, got %d", relayed)
+	}
+
+	if got := middleware.TotalSpend("first"); math.Abs(got-0.0375) > 1e-9 {
+		t.Errorf("expected $0.0375 for the first conversation, got %f", got)
+	}
+	if got := middleware.TotalSpend("second"); math.Abs(got-0.0125) > 1e-9 {
+		t.Errorf("expected $0.0125 for the second conversation, got %f", got)
+	}
+	if got := middleware.TotalSpend("unknown"); got != 0 {
+		t.Errorf("expected no spend for an unknown conversation, got %f", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(records) != 4 {
+		t.Fatalf("expected 4 spend records, got %d", len(records))
+	}
+	last := records[2]
+	if last.ConversationID != "first" || last.Model != "gpt-4o" || last.Err != nil {
+		t.Errorf("unexpected spend record: %+v", last)
+	}
+	if math.Abs(last.Cost-0.0125) > 1e-9 || math.Abs(last.TotalCost-0.0375) > 1e-9 {
+		t.Errorf("expected cost $0.0125 and total $0.0375, got %+v", last)
+	}
+}
+
+func TestLLMMiddlewareUnknownModel(t *testing.T) {
+	var record SpendRecord
+	middleware := NewLLMMiddleware(&unknownModelLLM{usageLLM{usage: interfaces.TokenUsage{InputTokens: 10}}}, SinkFunc(func(ctx context.Context, r SpendRecord) {
+		record = r
+	}))
+
+	if _, err := middleware.Generate(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Err == nil || record.Cost != 0 {
+		t.Errorf("expected an estimation error without cost, got %+v", record)
+	}
+}
+
+// unknownModelLLM reports a model without a price
+type unknownModelLLM struct {
+	usageLLM
+}
+
+func (l *unknownModelLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	llm.LogCompletion(ctx, nil, llm.CompletionLogLevelNone, llm.CompletionSummary{
+		Model:        "in-house-model",
+		PromptTokens: l.usage.InputTokens,
+	})
+	return "done", nil
+}
+
+func TestLLMMiddlewareStreamingUnsupported(t *testing.T) {
+	middleware := NewLLMMiddleware(&plainLLM{}, nil)
+	if middleware.SupportsStreaming() {
+		t.Error("expected the middleware to report the streaming support of the wrapped LLM")
+	}
+	if _, err := middleware.GenerateStream(context.Background(), "hello"); err == nil {
+		t.Error("expected an error streaming with an LLM without streaming support")
+	}
+}
+
+// plainLLM does not implement streaming
+type plainLLM struct{}
+
+func (l *plainLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return "done", nil
+}
+
+func (l *plainLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return "done", nil
+}
+
+func (l *plainLLM) Name() string            { return "plain" }
+func (l *plainLLM) SupportsStreaming() bool { return false }
@@ -0,0 +1,110 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Execute without running the operation while the circuit
+// breaker of the executor is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitState is the state of a circuit breaker
+type circuitState int
+
+const (
+	circuitClosed   circuitState = iota // Operations run normally
+	circuitOpen                         // Operations are rejected until the cooldown ends
+	circuitHalfOpen                     // A single trial operation is running
+)
+
+// circuitBreaker rejects operations for a cooldown window after consecutive failures. A nil
+// circuit breaker allows every operation.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     circuitState
+	openedAt  time.Time
+}
+
+// newCircuitBreaker creates a circuit breaker opening after threshold consecutive failures
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow reports whether an operation may run. Once the cooldown has ended, the first caller
+// runs the trial operation of the half-open state and the others are rejected until its outcome
+// is recorded.
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// success records an operation reaching the service, closing the circuit
+func (b *circuitBreaker) success() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = circuitClosed
+	b.failures = 0
+}
+
+// failure records a failed operation and reports whether it opened the circuit
+func (b *circuitBreaker) failure() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// abort records an operation ending without an outcome, such as a cancelled one. A trial
+// operation is given back so that the next caller runs a new trial.
+func (b *circuitBreaker) abort() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitHalfOpen {
+		b.state = circuitOpen
+	}
+}
@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so that Execute returns it immediately instead of retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked as permanent with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// IsClientError reports whether an HTTP status is a client error that retrying the same request
+// cannot fix, i.e. a 4xx status other than 408 Request Timeout, 409 Conflict and 429 Too Many
+// Requests. Such errors should be marked with Permanent, so that they are neither retried nor
+// counted as failures by the circuit breaker.
+func IsClientError(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= 400 && statusCode < 500
+}
+
+// delayedError carries the delay requested by the server before the next attempt
+type delayedError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *delayedError) Error() string { return e.err.Error() }
+func (e *delayedError) Unwrap() error { return e.err }
+
+// After wraps a retryable error with the delay the server asked to wait before retrying,
+// such as the value of a Retry-After header. Execute waits at least that long, bounded by
+// the maximum interval of the policy.
+func After(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &delayedError{err: err, delay: delay}
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, given in seconds or as an HTTP date,
+// returning 0 when it is missing, invalid or in the past
+func ParseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// unwrapRetryError returns the underlying error, whether it is permanent and the requested delay
+func unwrapRetryError(err error) (error, bool, time.Duration) {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return permanent.err, true, 0
+	}
+	var delayed *delayedError
+	if errors.As(err, &delayed) {
+		return delayed.err, false, delayed.delay
+	}
+	return err, false, 0
+}
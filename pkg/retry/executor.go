@@ -2,6 +2,7 @@ package retry
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
@@ -9,25 +10,40 @@ import (
 
 // Executor handles the execution of operations with retries
 type Executor struct {
-	policy *Policy
-	logger logging.Logger
+	policy  *Policy
+	logger  logging.Logger
+	breaker *circuitBreaker
 }
 
 // NewExecutor creates a new retry executor with the given policy
 func NewExecutor(policy *Policy) *Executor {
-	return &Executor{
+	executor := &Executor{
 		policy: policy,
 		logger: logging.New(),
 	}
+	if policy.CircuitBreakerThreshold > 0 {
+		executor.breaker = newCircuitBreaker(policy.CircuitBreakerThreshold, policy.CircuitBreakerCooldown)
+	}
+	return executor
 }
 
 // Execute executes the given operation with retries based on the policy
 func (e *Executor) Execute(ctx context.Context, operation func() error) error {
+	return e.ExecuteWithMaxAttempts(ctx, 0, operation)
+}
+
+// ExecuteWithMaxAttempts executes the given operation like Execute, making at most maxAttempts
+// attempts instead of the maximum of the policy, which applies when maxAttempts is 0 or less
+func (e *Executor) ExecuteWithMaxAttempts(ctx context.Context, maxAttempts int32, operation func() error) error {
+	if maxAttempts <= 0 {
+		maxAttempts = e.policy.MaximumAttempts
+	}
+
 	var lastErr error
 	attempt := int32(0)
 	currentInterval := e.policy.InitialInterval
 
-	for attempt < e.policy.MaximumAttempts {
+	for attempt < maxAttempts {
 		select {
 		case <-ctx.Done():
 			e.logger.Debug(ctx, "Context cancelled during retry", map[string]interface{}{
@@ -36,21 +52,56 @@ func (e *Executor) Execute(ctx context.Context, operation func() error) error {
 			})
 			return ctx.Err()
 		default:
+			if !e.breaker.allow() {
+				e.logger.Debug(ctx, "Circuit breaker open, skipping operation", map[string]interface{}{
+					"attempt": attempt,
+				})
+				if lastErr != nil {
+					return fmt.Errorf("%w: %w", ErrCircuitOpen, lastErr)
+				}
+				return ErrCircuitOpen
+			}
+
 			e.logger.Debug(ctx, "Attempting operation", map[string]interface{}{
 				"attempt":      attempt + 1,
-				"max_attempts": e.policy.MaximumAttempts,
+				"max_attempts": maxAttempts,
 			})
 
 			if err := operation(); err == nil {
+				e.breaker.success()
 				e.logger.Debug(ctx, "Operation succeeded", map[string]interface{}{
 					"attempt": attempt + 1,
 				})
 				return nil
 			} else {
+				err, permanent, requestedDelay := unwrapRetryError(err)
 				lastErr = err
 				attempt++
 
-				if attempt >= e.policy.MaximumAttempts {
+				// Permanent errors come from a service that is up, cancellations say nothing about it
+				switch {
+				case ctx.Err() != nil:
+					e.breaker.abort()
+				case permanent:
+					e.breaker.success()
+				case e.breaker.failure():
+					e.logger.Warn(ctx, "Circuit breaker opened", map[string]interface{}{
+						"attempt":  attempt,
+						"error":    err.Error(),
+						"cooldown": e.policy.CircuitBreakerCooldown,
+					})
+					return fmt.Errorf("%w: %w", ErrCircuitOpen, err)
+				}
+
+				if permanent {
+					e.logger.Debug(ctx, "Operation failed with a non-retryable error", map[string]interface{}{
+						"attempt": attempt,
+						"error":   err.Error(),
+					})
+					return err
+				}
+
+				if attempt >= maxAttempts {
 					e.logger.Debug(ctx, "Maximum attempts reached", map[string]interface{}{
 						"attempt": attempt,
 						"error":   err.Error(),
@@ -64,10 +115,16 @@ func (e *Executor) Execute(ctx context.Context, operation func() error) error {
 					nextInterval = e.policy.MaximumInterval
 				}
 
+				// Wait at least as long as the server asked for
+				wait := currentInterval
+				if requestedDelay > wait {
+					wait = min(requestedDelay, e.policy.MaximumInterval)
+				}
+
 				e.logger.Debug(ctx, "Operation failed, scheduling retry", map[string]interface{}{
 					"attempt":          attempt,
 					"error":            err.Error(),
-					"current_interval": currentInterval,
+					"current_interval": wait,
 					"next_interval":    nextInterval,
 				})
 
@@ -78,7 +135,7 @@ func (e *Executor) Execute(ctx context.Context, operation func() error) error {
 						"error":   ctx.Err(),
 					})
 					return ctx.Err()
-				case <-time.After(currentInterval):
+				case <-time.After(wait):
 					currentInterval = nextInterval
 				}
 			}
@@ -0,0 +1,188 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestExecutePermanentError(t *testing.T) {
+	executor := NewExecutor(NewPolicy(WithMaxAttempts(3), WithInitialInterval(time.Millisecond)))
+	cause := errors.New("bad request")
+
+	attempts := 0
+	err := executor.Execute(context.Background(), func() error {
+		attempts++
+		return Permanent(cause)
+	})
+	if err != cause {
+		t.Errorf("expected the unwrapped error, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts)
+	}
+}
+
+func TestExecuteAfterDelay(t *testing.T) {
+	executor := NewExecutor(NewPolicy(
+		WithMaxAttempts(2),
+		WithInitialInterval(time.Millisecond),
+		WithMaximumInterval(50*time.Millisecond),
+	))
+
+	attempts := 0
+	start := time.Now()
+	err := executor.Execute(context.Background(), func() error {
+		attempts++
+		if attempts == 1 {
+			// The requested delay is capped by the maximum interval
+			return After(errors.New("throttled"), time.Hour)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected to wait for the capped delay, waited %v", elapsed)
+	}
+}
+
+func TestExecuteCircuitBreaker(t *testing.T) {
+	executor := NewExecutor(NewPolicy(
+		WithMaxAttempts(5),
+		WithInitialInterval(time.Millisecond),
+		WithCircuitBreaker(3, 50*time.Millisecond),
+	))
+	down := errors.New("service unavailable")
+
+	// Consecutive failures open the circuit without burning the remaining attempts
+	attempts := 0
+	err := executor.Execute(context.Background(), func() error {
+		attempts++
+		return down
+	})
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, down) {
+		t.Errorf("expected the circuit to open on the last failure, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts before the circuit opens, got %d", attempts)
+	}
+
+	// While open, operations do not run
+	attempts = 0
+	err = executor.Execute(context.Background(), func() error {
+		attempts++
+		return nil
+	})
+	if err != ErrCircuitOpen || attempts != 0 {
+		t.Errorf("expected an immediate ErrCircuitOpen, got %v after %d attempts", err, attempts)
+	}
+
+	// After the cooldown a failed trial opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	attempts = 0
+	err = executor.Execute(context.Background(), func() error {
+		attempts++
+		return down
+	})
+	if !errors.Is(err, ErrCircuitOpen) || attempts != 1 {
+		t.Errorf("expected a single failed trial, got %v after %d attempts", err, attempts)
+	}
+
+	// A successful trial closes the circuit
+	time.Sleep(60 * time.Millisecond)
+	if err := executor.Execute(context.Background(), func() error { return nil }); err != nil {
+		t.Fatalf("expected the trial to succeed, got %v", err)
+	}
+
+	attempts = 0
+	err = executor.Execute(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return down
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("expected retries to resume once closed, got %v after %d attempts", err, attempts)
+	}
+}
+
+func TestExecuteCircuitBreakerIgnoresPermanentErrors(t *testing.T) {
+	executor := NewExecutor(NewPolicy(
+		WithMaxAttempts(1),
+		WithCircuitBreaker(2, time.Hour),
+	))
+
+	for i := 0; i < 3; i++ {
+		err := executor.Execute(context.Background(), func() error {
+			return Permanent(errors.New("bad request"))
+		})
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected permanent errors not to open the circuit")
+		}
+	}
+}
+
+func TestExecuteWithMaxAttempts(t *testing.T) {
+	executor := NewExecutor(NewPolicy(WithMaxAttempts(3), WithInitialInterval(time.Millisecond)))
+	failure := errors.New("service unavailable")
+
+	for _, tc := range []struct {
+		maxAttempts int32
+		expected    int
+	}{
+		{maxAttempts: 0, expected: 3}, // The policy applies
+		{maxAttempts: 1, expected: 1},
+		{maxAttempts: 5, expected: 5},
+	} {
+		attempts := 0
+		err := executor.ExecuteWithMaxAttempts(context.Background(), tc.maxAttempts, func() error {
+			attempts++
+			return failure
+		})
+		if err != failure {
+			t.Errorf("expected the last error, got %v", err)
+		}
+		if attempts != tc.expected {
+			t.Errorf("expected %d attempts with a maximum of %d, got %d", tc.expected, tc.maxAttempts, attempts)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if delay := ParseRetryAfter("3"); delay != 3*time.Second {
+		t.Errorf("expected 3s, got %v", delay)
+	}
+	if delay := ParseRetryAfter(""); delay != 0 {
+		t.Errorf("expected no delay without header, got %v", delay)
+	}
+	if delay := ParseRetryAfter("soon"); delay != 0 {
+		t.Errorf("expected no delay for an invalid header, got %v", delay)
+	}
+
+	delay := ParseRetryAfter(time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat))
+	if delay <= 8*time.Second || delay > 10*time.Second {
+		t.Errorf("expected about 10s for an HTTP date, got %v", delay)
+	}
+}
+
+func TestIsClientError(t *testing.T) {
+	for status, expected := range map[int]bool{
+		http.StatusBadRequest:          true,
+		http.StatusUnauthorized:        true,
+		http.StatusNotFound:            true,
+		http.StatusRequestTimeout:      false,
+		http.StatusConflict:            false,
+		http.StatusTooManyRequests:     false,
+		http.StatusInternalServerError: false,
+		http.StatusOK:                  false,
+	} {
+		if got := IsClientError(status); got != expected {
+			t.Errorf("IsClientError(%d) = %v, want %v", status, got, expected)
+		}
+	}
+}
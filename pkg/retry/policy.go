@@ -8,6 +8,11 @@ type Policy struct {
 	BackoffCoefficient float64
 	MaximumInterval    time.Duration
 	MaximumAttempts    int32
+
+	// CircuitBreakerThreshold is the number of consecutive failures opening the circuit breaker (0 = disabled)
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is how long the circuit breaker stays open before a trial operation
+	CircuitBreakerCooldown time.Duration
 }
 
 // Option represents a retry policy option
@@ -41,6 +46,17 @@ func WithMaxAttempts(attempts int32) Option {
 	}
 }
 
+// WithCircuitBreaker enables a circuit breaker: after failureThreshold consecutive failed attempts,
+// Execute returns ErrCircuitOpen without running the operation for the cooldown window, then
+// lets a single trial operation through. A successful trial closes the circuit, a failed one
+// opens it for another cooldown. Permanent errors do not count as failures.
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(p *Policy) {
+		p.CircuitBreakerThreshold = failureThreshold
+		p.CircuitBreakerCooldown = cooldown
+	}
+}
+
 // NewPolicy creates a new retry policy with default values
 func NewPolicy(opts ...Option) *Policy {
 	policy := &Policy{
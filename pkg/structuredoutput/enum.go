@@ -0,0 +1,117 @@
+package structuredoutput
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// EnumField is the property of the object holding the value of an enum response
+const EnumField = "value"
+
+// enumRepairPrompt asks the model to answer again with one of the allowed values
+const enumRepairPrompt = `%s
+
+Your previous response %q is not one of the allowed values: %s.
+
+Respond again with only one of the allowed values.`
+
+// NewEnumFormat creates a ResponseFormat constraining the response to one of values, for
+// classification tasks. The response is an object with the chosen value in its EnumField
+// property, e.g. {"value": "positive"}. Providers enforce the enum natively where they can:
+// OpenAI and Azure OpenAI with a strict json_schema, Gemini with its response schema. Use
+// ParseEnum to validate the response of other providers.
+func NewEnumFormat(values []string) *interfaces.ResponseFormat {
+	return &interfaces.ResponseFormat{
+		Type: interfaces.ResponseFormatJSON,
+		Name: "Enum",
+		Schema: interfaces.JSONSchema{
+			"type": "object",
+			"properties": map[string]any{
+				EnumField: map[string]any{
+					"type": "string",
+					"enum": enumValues(values),
+				},
+			},
+			"required":             []string{EnumField},
+			"additionalProperties": false,
+		},
+		Strict: true,
+	}
+}
+
+// enumValues converts values to the []interface{} form of decoded JSON schemas
+func enumValues(values []string) []interface{} {
+	enum := make([]interface{}, len(values))
+	for i, value := range values {
+		enum[i] = value
+	}
+	return enum
+}
+
+// ParseEnum returns the value of an enum response, which must be one of values. Both the object
+// of NewEnumFormat and a bare value, quoted or not, are accepted, ignoring markdown code fences.
+func ParseEnum(response string, values []string) (string, error) {
+	content := StripCodeFences(response)
+
+	var object map[string]interface{}
+	var quoted string
+	value := content
+	if json.Unmarshal([]byte(content), &object) == nil {
+		if v, ok := object[EnumField].(string); ok {
+			value = v
+		}
+	} else if json.Unmarshal([]byte(content), &quoted) == nil {
+		value = quoted
+	}
+
+	for _, allowed := range values {
+		if value == allowed {
+			return allowed, nil
+		}
+	}
+	return "", fmt.Errorf("response %q is not one of the allowed values: %s", value, strings.Join(values, ", "))
+}
+
+// GenerateEnum generates a response constrained to one of values with NewEnumFormat and returns
+// the chosen value. When the LLM answers with a value out of the set, it is asked for one of the
+// allowed values again, up to the number of attempts of interfaces.WithStructuredOutputRepair,
+// before the error of ParseEnum is returned.
+func GenerateEnum(ctx context.Context, llm interfaces.LLM, prompt string, values []string, options ...interfaces.GenerateOption) (string, error) {
+	if len(values) == 0 {
+		return "", fmt.Errorf("enum values are required")
+	}
+
+	params := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(params)
+	}
+
+	// The out-of-set responses are repaired here, so the LLM must not repair them as well
+	generateOptions := append([]interfaces.GenerateOption{}, options...)
+	generateOptions = append(generateOptions,
+		interfaces.WithResponseFormat(*NewEnumFormat(values)),
+		interfaces.WithStructuredOutputRepair(0),
+	)
+
+	response, err := llm.Generate(ctx, prompt, generateOptions...)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate enum: %w", err)
+	}
+
+	value, parseErr := ParseEnum(response, values)
+	for attempt := 1; parseErr != nil && attempt <= params.OutputRepair; attempt++ {
+		response, err = llm.Generate(ctx, fmt.Sprintf(enumRepairPrompt, prompt, response, strings.Join(values, ", ")), generateOptions...)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate enum: %w", err)
+		}
+		value, parseErr = ParseEnum(response, values)
+	}
+	if parseErr != nil {
+		return "", parseErr
+	}
+	return value, nil
+}
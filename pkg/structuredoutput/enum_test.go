@@ -0,0 +1,96 @@
+package structuredoutput
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// sequenceLLM returns its responses in turn, recording the prompts
+type sequenceLLM struct {
+	fixedLLM
+	responses []string
+	prompts   []string
+}
+
+func (m *sequenceLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	m.prompts = append(m.prompts, prompt)
+	m.response = m.responses[0]
+	if len(m.responses) > 1 {
+		m.responses = m.responses[1:]
+	}
+	return m.fixedLLM.Generate(ctx, prompt, options...)
+}
+
+func TestNewEnumFormat(t *testing.T) {
+	format := NewEnumFormat([]string{"positive", "negative"})
+	if !format.Strict || format.Type != interfaces.ResponseFormatJSON {
+		t.Errorf("Expected a strict JSON format, got %+v", format)
+	}
+
+	property := format.Schema["properties"].(map[string]any)[EnumField].(map[string]any)
+	if enum := property["enum"].([]interface{}); len(enum) != 2 || enum[0] != "positive" {
+		t.Errorf("Expected the values in the enum of the schema, got %v", property)
+	}
+	if format.Schema["additionalProperties"] != false {
+		t.Error("Expected no additional properties, as strict mode requires")
+	}
+}
+
+func TestParseEnum(t *testing.T) {
+	values := []string{"positive", "negative", "neutral"}
+	for _, response := range []string{`{"value": "negative"}`, "```json\n{\"value\": \"negative\"}\n```", `"negative"`, "negative"} {
+		value, err := ParseEnum(response, values)
+		if err != nil || value != "negative" {
+			t.Errorf("ParseEnum(%q) = %q, %v", response, value, err)
+		}
+	}
+
+	for _, response := range []string{`{"value": "mixed"}`, "Negative", `{"label": "negative"}`} {
+		if _, err := ParseEnum(response, values); err == nil {
+			t.Errorf("Expected ParseEnum(%q) to be rejected", response)
+		}
+	}
+}
+
+func TestGenerateEnum(t *testing.T) {
+	values := []string{"positive", "negative"}
+
+	llm := &sequenceLLM{responses: []string{`{"value": "positive"}`}}
+	value, err := GenerateEnum(context.Background(), llm, "I love it", values)
+	if err != nil || value != "positive" {
+		t.Fatalf("GenerateEnum = %q, %v", value, err)
+	}
+	if format := llm.options.ResponseFormat; format == nil || !format.Strict {
+		t.Errorf("Expected the enum format to be requested, got %+v", format)
+	}
+
+	// Out-of-set responses are rejected without repair
+	llm = &sequenceLLM{responses: []string{`{"value": "mixed"}`, `{"value": "negative"}`}}
+	if _, err := GenerateEnum(context.Background(), llm, "It is fine", values); err == nil || !strings.Contains(err.Error(), "mixed") {
+		t.Errorf("Expected the out-of-set response to be rejected, got %v", err)
+	}
+
+	// and repaired with WithStructuredOutputRepair
+	llm = &sequenceLLM{responses: []string{`{"value": "mixed"}`, `{"value": "negative"}`}}
+	value, err = GenerateEnum(context.Background(), llm, "It is fine", values, interfaces.WithStructuredOutputRepair(2))
+	if err != nil || value != "negative" {
+		t.Fatalf("Expected the repaired value, got %q, %v", value, err)
+	}
+	if len(llm.prompts) != 2 || !strings.Contains(llm.prompts[1], "positive, negative") {
+		t.Errorf("Expected the repair prompt to list the allowed values, got %q", llm.prompts)
+	}
+	if llm.options.OutputRepair != 0 {
+		t.Errorf("Expected the repair not to be requested from the LLM as well, got %d attempts", llm.options.OutputRepair)
+	}
+
+	llm = &sequenceLLM{responses: []string{`{"value": "mixed"}`}}
+	if _, err := GenerateEnum(context.Background(), llm, "It is fine", values, interfaces.WithStructuredOutputRepair(2)); err == nil {
+		t.Error("Expected an error after the repair attempts")
+	}
+	if len(llm.prompts) != 3 {
+		t.Errorf("Expected 3 attempts, got %d", len(llm.prompts))
+	}
+}
@@ -0,0 +1,39 @@
+package structuredoutput
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Generate generates a structured response of type T, which must be a struct or a pointer to a
+// struct. The response format is built from T and passed to the LLM along with the options, and
+// the response is decoded into T, ignoring markdown code fences. If the LLM does not return valid
+// JSON, such as when it answers in prose, the error includes the raw response.
+func Generate[T any](ctx context.Context, llm interfaces.LLM, prompt string, options ...interfaces.GenerateOption) (T, error) {
+	var result T
+
+	t := reflect.TypeOf(&result).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return result, fmt.Errorf("structured output type must be a struct, got %s", t)
+	}
+
+	format := NewResponseFormat(reflect.New(t).Interface())
+	generateOptions := append([]interfaces.GenerateOption{}, options...)
+	generateOptions = append(generateOptions, interfaces.WithResponseFormat(*format))
+
+	response, err := llm.Generate(ctx, prompt, generateOptions...)
+	if err != nil {
+		return result, fmt.Errorf("failed to generate %s: %w", format.Name, err)
+	}
+
+	if err := Parse(response, FormatJSON, &result); err != nil {
+		return result, fmt.Errorf("failed to decode %s from the LLM response: %w; raw response: %q", format.Name, err, response)
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package structuredoutput
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// fixedLLM returns a fixed response, recording the options of the last call
+type fixedLLM struct {
+	response string
+	err      error
+	options  *interfaces.GenerateOptions
+}
+
+func (m *fixedLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	m.options = &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(m.options)
+	}
+	return m.response, m.err
+}
+
+func (m *fixedLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *fixedLLM) Name() string { return "fixed" }
+
+func (m *fixedLLM) SupportsStreaming() bool { return false }
+
+func TestGenerate(t *testing.T) {
+	llm := &fixedLLM{response: "```json\n{\"name\": \"Ada\", \"age\": 36}\n```"}
+
+	person, err := Generate[parsedPerson](context.Background(), llm, "Who wrote the first program?",
+		interfaces.WithSystemMessage("You are a historian"))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if person != (parsedPerson{Name: "Ada", Age: 36}) {
+		t.Errorf("Unexpected result: %+v", person)
+	}
+
+	// The response format is built from the type, next to the other options
+	format := llm.options.ResponseFormat
+	if format == nil || format.Name != "parsedPerson" || format.Type != interfaces.ResponseFormatJSON {
+		t.Fatalf("Expected the response format of parsedPerson, got %+v", format)
+	}
+	if _, ok := format.Schema["properties"].(map[string]any)["name"]; !ok {
+		t.Errorf("Expected the schema to describe the fields, got %v", format.Schema)
+	}
+	if llm.options.SystemMessage != "You are a historian" {
+		t.Errorf("Expected the options to be passed on, got %q", llm.options.SystemMessage)
+	}
+
+	// Pointers to structs are supported
+	pointer, err := Generate[*parsedPerson](context.Background(), llm, "Who wrote the first program?")
+	if err != nil || pointer == nil || pointer.Name != "Ada" {
+		t.Errorf("Expected a decoded pointer, got %+v (%v)", pointer, err)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	llm := &fixedLLM{response: "Ada Lovelace wrote the first program."}
+	_, err := Generate[parsedPerson](context.Background(), llm, "Who wrote the first program?")
+	if err == nil {
+		t.Fatal("Expected an error for a prose response")
+	}
+	if !strings.Contains(err.Error(), "Ada Lovelace wrote the first program.") {
+		t.Errorf("Expected the error to include the raw response, got %v", err)
+	}
+
+	failure := errors.New("rate limited")
+	_, err = Generate[parsedPerson](context.Background(), &fixedLLM{err: failure}, "prompt")
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the generation error, got %v", err)
+	}
+
+	if _, err := Generate[string](context.Background(), llm, "prompt"); err == nil {
+		t.Error("Expected an error for a non-struct type")
+	}
+}
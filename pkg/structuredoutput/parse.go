@@ -0,0 +1,75 @@
+package structuredoutput
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/jsontext"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of a structured response
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatXML  Format = "xml"
+)
+
+// ParseOption represents an option for parsing a structured response
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	stripCodeFences bool
+}
+
+// WithStripCodeFences sets whether markdown code fences around the response are removed before parsing (default: true)
+func WithStripCodeFences(strip bool) ParseOption {
+	return func(o *parseOptions) {
+		o.stripCodeFences = strip
+	}
+}
+
+// Parse decodes a structured response in the given format into v
+func Parse(response string, format Format, v interface{}, options ...ParseOption) error {
+	opts := parseOptions{stripCodeFences: true}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if opts.stripCodeFences {
+		response = StripCodeFences(response)
+		// JSON is also found in a code block following an explanation
+		if format == FormatJSON {
+			if text, ok := jsontext.Extract(response); ok {
+				response = text
+			}
+		}
+	}
+
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal([]byte(response), v)
+	case FormatYAML:
+		err = yaml.Unmarshal([]byte(response), v)
+	case FormatXML:
+		err = xml.Unmarshal([]byte(response), v)
+	default:
+		return fmt.Errorf("unsupported structured output format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", format, err)
+	}
+	return nil
+}
+
+// StripCodeFences returns the content of the markdown code block a response starts with,
+// between the opening fence and the last closing fence, without the language tag of the opening
+// fence. Responses not starting with a complete code block are returned with surrounding
+// whitespace trimmed.
+func StripCodeFences(response string) string {
+	return jsontext.StripCodeFences(response)
+}
@@ -0,0 +1,94 @@
+package structuredoutput
+
+import (
+	"testing"
+)
+
+type parsedPerson struct {
+	Name string `json:"name" yaml:"name" xml:"name"`
+	Age  int    `json:"age" yaml:"age" xml:"age"`
+}
+
+func TestParseStripsCodeFences(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		response string
+	}{
+		{
+			name:     "fenced json",
+			format:   FormatJSON,
+			response: "Here you go:\n```json\n{\"name\": \"Ada\", \"age\": 36}\n```\n",
+		},
+		{
+			name:     "fenced yaml",
+			format:   FormatYAML,
+			response: "```yaml\nname: Ada\nage: 36\n```",
+		},
+		{
+			name:     "fence without language tag",
+			format:   FormatYAML,
+			response: "```\nname: Ada\nage: 36\n```",
+		},
+		{
+			name:     "fenced xml",
+			format:   FormatXML,
+			response: "```xml\n<person><name>Ada</name><age>36</age></person>\n```",
+		},
+		{
+			name:     "unfenced json",
+			format:   FormatJSON,
+			response: "  {\"name\": \"Ada\", \"age\": 36}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var person parsedPerson
+			if err := Parse(tt.response, tt.format, &person); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if person.Name != "Ada" || person.Age != 36 {
+				t.Errorf("unexpected result: %+v", person)
+			}
+		})
+	}
+}
+
+func TestParseWithoutStrippingCodeFences(t *testing.T) {
+	var person parsedPerson
+	err := Parse("```json\n{\"name\": \"Ada\", \"age\": 36}\n```", FormatJSON, &person, WithStripCodeFences(false))
+	if err == nil {
+		t.Fatal("expected fenced JSON to fail to parse when fence stripping is disabled")
+	}
+
+	if err := Parse("{\"name\": \"Ada\", \"age\": 36}", FormatJSON, &person, WithStripCodeFences(false)); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if person.Name != "Ada" {
+		t.Errorf("unexpected result: %+v", person)
+	}
+}
+
+func TestParseUnsupportedFormat(t *testing.T) {
+	var person parsedPerson
+	if err := Parse("name=Ada", Format("toml"), &person); err == nil {
+		t.Fatal("expected an error for an unsupported format")
+	}
+}
+
+func TestStripCodeFences(t *testing.T) {
+	tests := map[string]string{
+		"```json\n{}\n```":         "{}",
+		"  ```yaml\na: 1\n```\n":   "a: 1",
+		"  no fences  ":            "no fences",
+		"```json\n{} unterminated": "```json\n{} unterminated",
+		"```json\n{\"code\": \"```go\\nfmt.Println()\\n```\"}\n```": "{\"code\": \"```go\\nfmt.Println()\\n```\"}",
+		"{\"a\": \"```x\", \"b\": \"y```\"}":                        "{\"a\": \"```x\", \"b\": \"y```\"}",
+	}
+	for input, expected := range tests {
+		if got := StripCodeFences(input); got != expected {
+			t.Errorf("StripCodeFences(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
@@ -52,7 +52,7 @@ func getJSONSchema(t reflect.Type) map[string]any {
 
 			properties[jsonTag] = map[string]any{
 				"type":        "object",
-				"description": field.Tag.Get("description"),
+				"description": fieldDescription(field),
 				"properties":  getJSONSchema(fieldType),
 				"required":    requiredFields,
 			}
@@ -68,7 +68,7 @@ func getJSONSchema(t reflect.Type) map[string]any {
 			if itemType.Kind() == reflect.Struct {
 				properties[jsonTag] = map[string]any{
 					"type":        "array",
-					"description": field.Tag.Get("description"),
+					"description": fieldDescription(field),
 					"items": map[string]any{
 						"type":       "object",
 						"properties": getJSONSchema(itemType),
@@ -78,7 +78,7 @@ func getJSONSchema(t reflect.Type) map[string]any {
 			} else {
 				properties[jsonTag] = map[string]any{
 					"type":        "array",
-					"description": field.Tag.Get("description"),
+					"description": fieldDescription(field),
 					"items": map[string]string{
 						"type": getJSONType(itemType),
 					},
@@ -89,7 +89,7 @@ func getJSONSchema(t reflect.Type) map[string]any {
 			valueType := fieldType.Elem()
 			properties[jsonTag] = map[string]any{
 				"type":        "object",
-				"description": field.Tag.Get("description"),
+				"description": fieldDescription(field),
 				"additionalProperties": map[string]string{
 					"type": getJSONType(valueType),
 				},
@@ -97,13 +97,22 @@ func getJSONSchema(t reflect.Type) map[string]any {
 		} else {
 			properties[jsonTag] = map[string]interface{}{
 				"type":        getJSONType(fieldType),
-				"description": field.Tag.Get("description"),
+				"description": fieldDescription(field),
 			}
 		}
 	}
 	return properties
 }
 
+// fieldDescription returns the description tag of a field, or its jsonschema tag as used by the
+// MCP SDK, e.g. `jsonschema:"the city to get the weather for"`
+func fieldDescription(field reflect.StructField) string {
+	if description := field.Tag.Get("description"); description != "" {
+		return description
+	}
+	return field.Tag.Get("jsonschema")
+}
+
 func getJSONType(t reflect.Type) string {
 	// Handle pointer types
 	if t.Kind() == reflect.Ptr {
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// FuncTool is a tool calling a Go function, see FromFunc
+type FuncTool struct {
+	name        string
+	description string
+	parameters  map[string]interfaces.ParameterSpec
+	fn          reflect.Value
+	argsType    reflect.Type
+}
+
+// FromFunc creates a tool from a function of the form func(context.Context, Args) (Result, error),
+// where Args is a struct or a pointer to a struct. The parameters of the tool are generated from
+// the fields of Args with the structuredoutput schema generator: their json tags name them,
+// omitempty makes them optional and their description or jsonschema tags describe them, e.g.
+//
+//	type WeatherArgs struct {
+//		City  string `json:"city" jsonschema:"the city to get the weather for"`
+//		Units string `json:"units,omitempty" description:"celsius or fahrenheit"`
+//	}
+//
+// The arguments of a call are decoded into Args, and the result is sent to the model as JSON,
+// or as is when it is a string.
+func FromFunc(name, description string, fn any) (*FuncTool, error) {
+	if fn == nil {
+		return nil, fmt.Errorf("tool %s has no function", name)
+	}
+	value := reflect.ValueOf(fn)
+	t := value.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 2 || t.NumOut() != 2 || t.In(0) != contextType || t.Out(1) != errorType {
+		return nil, fmt.Errorf("tool %s must be a func(context.Context, Args) (Result, error), got %s", name, t)
+	}
+
+	argsType := t.In(1)
+	structType := argsType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("arguments of tool %s must be a struct, got %s", name, argsType)
+	}
+
+	schema := structuredoutput.NewResponseFormat(reflect.New(structType).Interface()).Schema
+	properties, _ := schema["properties"].(map[string]any)
+	required, _ := schema["required"].([]string)
+
+	return &FuncTool{
+		name:        name,
+		description: description,
+		parameters:  parameterSpecs(properties, required),
+		fn:          value,
+		argsType:    argsType,
+	}, nil
+}
+
+// Name returns the name of the tool
+func (t *FuncTool) Name() string {
+	return t.name
+}
+
+// Description returns a description of what the tool does
+func (t *FuncTool) Description() string {
+	return t.description
+}
+
+// Parameters returns the parameters generated from the arguments of the function
+func (t *FuncTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.parameters
+}
+
+// Run executes the tool with the given input
+func (t *FuncTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// Execute executes the tool with the given arguments
+func (t *FuncTool) Execute(ctx context.Context, args string) (string, error) {
+	result, err := t.ExecuteStructured(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	return result.Content()
+}
+
+// ExecuteStructured implements interfaces.StructuredTool, decoding the arguments and calling the function
+func (t *FuncTool) ExecuteStructured(ctx context.Context, args string) (interfaces.ToolResult, error) {
+	if strings.TrimSpace(args) == "" {
+		args = "{}"
+	}
+
+	argsValue := reflect.New(t.argsType)
+	if err := json.Unmarshal([]byte(args), argsValue.Interface()); err != nil {
+		return interfaces.ToolResult{}, fmt.Errorf("failed to parse arguments of tool %s: %w", t.name, err)
+	}
+
+	out := t.fn.Call([]reflect.Value{reflect.ValueOf(ctx), argsValue.Elem()})
+	if err, _ := out[1].Interface().(error); err != nil {
+		return interfaces.ToolResult{}, err
+	}
+
+	if text, ok := out[0].Interface().(string); ok {
+		return interfaces.ToolResult{Display: text}, nil
+	}
+	return interfaces.ToolResult{Data: out[0].Interface()}, nil
+}
+
+// parameterSpecs converts the properties of a JSON schema to parameter specs
+func parameterSpecs(properties map[string]any, required []string) map[string]interfaces.ParameterSpec {
+	specs := make(map[string]interfaces.ParameterSpec, len(properties))
+	for name, property := range properties {
+		schema, _ := property.(map[string]any)
+		specs[name] = parameterSpec(schema, slices.Contains(required, name))
+	}
+	return specs
+}
+
+// parameterSpec converts a JSON schema to a parameter spec
+func parameterSpec(schema map[string]any, required bool) interfaces.ParameterSpec {
+	spec := interfaces.ParameterSpec{Required: required}
+	spec.Type, _ = schema["type"].(string)
+	spec.Description, _ = schema["description"].(string)
+
+	switch items := schema["items"].(type) {
+	case map[string]any:
+		item := parameterSpec(items, false)
+		spec.Items = &item
+	case map[string]string:
+		spec.Items = &interfaces.ParameterSpec{Type: items["type"]}
+	}
+
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		nestedRequired, _ := schema["required"].([]string)
+		spec.Properties = parameterSpecs(properties, nestedRequired)
+	}
+	return spec
+}
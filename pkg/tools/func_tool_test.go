@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+type weatherArgs struct {
+	City     string   `json:"city" jsonschema:"the city to get the weather for"`
+	Units    string   `json:"units,omitempty" description:"celsius or fahrenheit"`
+	Days     int      `json:"days,omitempty"`
+	Stations []string `json:"stations,omitempty"`
+}
+
+type weatherResult struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+func getWeather(ctx context.Context, args weatherArgs) (weatherResult, error) {
+	if args.City == "Atlantis" {
+		return weatherResult{}, errors.New("unknown city")
+	}
+	return weatherResult{City: args.City, Temperature: 21.5}, nil
+}
+
+func TestFromFunc(t *testing.T) {
+	tool, err := FromFunc("get_weather", "Get the weather of a city", getWeather)
+	if err != nil {
+		t.Fatalf("FromFunc failed: %v", err)
+	}
+	var _ interfaces.StructuredTool = tool
+
+	params := tool.Parameters()
+	if city := params["city"]; city.Type != "string" || !city.Required || city.Description != "the city to get the weather for" {
+		t.Errorf("Unexpected city parameter: %+v", city)
+	}
+	if units := params["units"]; units.Required || units.Description != "celsius or fahrenheit" {
+		t.Errorf("Unexpected units parameter: %+v", units)
+	}
+	if params["days"].Type != "integer" {
+		t.Errorf("Expected an integer days parameter, got %+v", params["days"])
+	}
+	if stations := params["stations"]; stations.Type != "array" || stations.Items == nil || stations.Items.Type != "string" {
+		t.Errorf("Unexpected stations parameter: %+v", stations)
+	}
+
+	response, err := tool.Execute(context.Background(), `{"city": "Lisbon"}`)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	var result weatherResult
+	if err := json.Unmarshal([]byte(response), &result); err != nil || result != (weatherResult{City: "Lisbon", Temperature: 21.5}) {
+		t.Errorf("Expected the JSON result, got %q", response)
+	}
+
+	if _, err := tool.Execute(context.Background(), `{"city": "Atlantis"}`); err == nil || err.Error() != "unknown city" {
+		t.Errorf("Expected the error of the function, got %v", err)
+	}
+	if _, err := tool.Execute(context.Background(), `{"city": 42}`); err == nil {
+		t.Error("Expected an error for invalid arguments")
+	}
+}
+
+func TestFromFuncStringResult(t *testing.T) {
+	greet := func(ctx context.Context, args *struct {
+		Name string `json:"name"`
+	}) (string, error) {
+		return "Hello, " + args.Name, nil
+	}
+
+	tool, err := FromFunc("greet", "Greet someone", greet)
+	if err != nil {
+		t.Fatalf("FromFunc failed: %v", err)
+	}
+	response, err := tool.Run(context.Background(), `{"name": "Ada"}`)
+	if err != nil || response != "Hello, Ada" {
+		t.Errorf("Expected the string result as is, got %q, %v", response, err)
+	}
+}
+
+func TestFromFuncInvalidSignature(t *testing.T) {
+	invalid := []any{
+		"not a function",
+		func(args weatherArgs) (weatherResult, error) { return weatherResult{}, nil },
+		func(ctx context.Context, city string) (string, error) { return city, nil },
+		func(ctx context.Context, args weatherArgs) weatherResult { return weatherResult{} },
+	}
+	for _, fn := range invalid {
+		if _, err := FromFunc("invalid", "", fn); err == nil {
+			t.Errorf("Expected an error for %T", fn)
+		}
+	}
+}
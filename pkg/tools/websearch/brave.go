@@ -0,0 +1,61 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// braveMaxResults is the maximum number of results of a Brave search request
+const braveMaxResults = 20
+
+// BraveProvider searches with the Brave Search API, which offers a free plan
+type BraveProvider struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewBraveProvider creates a Brave Search provider with a subscription token of the Brave Search API
+func NewBraveProvider(apiKey string, options ...ProviderOption) *BraveProvider {
+	config := newProviderConfig(options)
+	return &BraveProvider{
+		apiKey:     apiKey,
+		httpClient: config.httpClient,
+	}
+}
+
+// Search implements SearchProvider
+func (p *BraveProvider) Search(ctx context.Context, query string, n int) ([]SearchHit, error) {
+	searchURL := fmt.Sprintf(
+		"https://api.search.brave.com/res/v1/web/search?q=%s&count=%d",
+		url.QueryEscape(query),
+		min(max(n, 1), braveMaxResults),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Subscription-Token", p.apiKey)
+
+	var result struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := doSearchRequest(p.httpClient, req, &result); err != nil {
+		return nil, err
+	}
+
+	hits := make([]SearchHit, 0, len(result.Web.Results))
+	for _, item := range result.Web.Results {
+		hits = append(hits, SearchHit{Title: item.Title, URL: item.URL, Snippet: item.Description})
+	}
+	return hits, nil
+}
@@ -0,0 +1,87 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DuckDuckGoProvider searches with the DuckDuckGo Instant Answer API, which requires no API key.
+// It returns the instant answer and the related topics of a query rather than a full page of web
+// results, which suits factual lookups better than broad searches.
+type DuckDuckGoProvider struct {
+	httpClient *http.Client
+}
+
+// NewDuckDuckGoProvider creates a DuckDuckGo provider
+func NewDuckDuckGoProvider(options ...ProviderOption) *DuckDuckGoProvider {
+	config := newProviderConfig(options)
+	return &DuckDuckGoProvider{httpClient: config.httpClient}
+}
+
+// duckDuckGoTopic is a related topic of an instant answer, or a group of topics
+type duckDuckGoTopic struct {
+	Text     string            `json:"Text"`
+	FirstURL string            `json:"FirstURL"`
+	Topics   []duckDuckGoTopic `json:"Topics"`
+}
+
+// Search implements SearchProvider
+func (p *DuckDuckGoProvider) Search(ctx context.Context, query string, n int) ([]SearchHit, error) {
+	searchURL := fmt.Sprintf(
+		"https://api.duckduckgo.com/?q=%s&format=json&no_html=1&skip_disambig=1",
+		url.QueryEscape(query),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var result struct {
+		Heading       string            `json:"Heading"`
+		AbstractText  string            `json:"AbstractText"`
+		AbstractURL   string            `json:"AbstractURL"`
+		Answer        string            `json:"Answer"`
+		RelatedTopics []duckDuckGoTopic `json:"RelatedTopics"`
+	}
+	if err := doSearchRequest(p.httpClient, req, &result); err != nil {
+		return nil, err
+	}
+
+	var hits []SearchHit
+	if result.AbstractText != "" {
+		hits = append(hits, SearchHit{Title: result.Heading, URL: result.AbstractURL, Snippet: result.AbstractText})
+	} else if result.Answer != "" {
+		hits = append(hits, SearchHit{Title: result.Heading, Snippet: result.Answer})
+	}
+	hits = appendTopics(hits, result.RelatedTopics, n)
+
+	if len(hits) > n {
+		hits = hits[:n]
+	}
+	return hits, nil
+}
+
+// appendTopics appends related topics, flattening groups, until there are n hits
+func appendTopics(hits []SearchHit, topics []duckDuckGoTopic, n int) []SearchHit {
+	for _, topic := range topics {
+		if len(hits) >= n {
+			break
+		}
+		if len(topic.Topics) > 0 {
+			hits = appendTopics(hits, topic.Topics, n)
+			continue
+		}
+		if topic.FirstURL == "" {
+			continue
+		}
+
+		// The text of a topic starts with its title, e.g. "Go (programming language) - A language..."
+		title, _, _ := strings.Cut(topic.Text, " - ")
+		hits = append(hits, SearchHit{Title: title, URL: topic.FirstURL, Snippet: topic.Text})
+	}
+	return hits
+}
@@ -0,0 +1,120 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// SearchHit is a result of a web search
+type SearchHit struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
+// SearchProvider is a web search backend
+type SearchProvider interface {
+	// Search returns up to n results for a query
+	Search(ctx context.Context, query string, n int) ([]SearchHit, error)
+}
+
+// ProviderOption represents an option for configuring a search provider
+type ProviderOption func(*providerConfig)
+
+type providerConfig struct {
+	httpClient *http.Client
+}
+
+// WithProviderHTTPClient sets the HTTP client of a search provider
+func WithProviderHTTPClient(client *http.Client) ProviderOption {
+	return func(c *providerConfig) {
+		c.httpClient = client
+	}
+}
+
+func newProviderConfig(options []ProviderOption) providerConfig {
+	config := providerConfig{httpClient: &http.Client{Timeout: 10 * time.Second}}
+	for _, option := range options {
+		option(&config)
+	}
+	return config
+}
+
+// GoogleProvider searches with Google Custom Search, the default provider of New
+type GoogleProvider struct {
+	credentials Credentials
+	httpClient  *http.Client
+}
+
+// NewGoogleProvider creates a Google Custom Search provider
+func NewGoogleProvider(apiKey, engineID string, options ...ProviderOption) *GoogleProvider {
+	config := newProviderConfig(options)
+	return &GoogleProvider{
+		credentials: Credentials{APIKey: apiKey, EngineID: engineID},
+		httpClient:  config.httpClient,
+	}
+}
+
+// Search implements SearchProvider
+func (p *GoogleProvider) Search(ctx context.Context, query string, n int) ([]SearchHit, error) {
+	searchURL := fmt.Sprintf(
+		"https://www.googleapis.com/customsearch/v1?key=%s&cx=%s&q=%s&num=%d",
+		url.QueryEscape(p.credentials.APIKey),
+		url.QueryEscape(p.credentials.EngineID),
+		url.QueryEscape(query),
+		n,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add organization ID to request headers if available
+	if orgID, _ := multitenancy.GetOrgID(ctx); orgID != "" {
+		req.Header.Set("X-Organization-ID", orgID)
+	}
+
+	var result struct {
+		Items []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+		} `json:"items"`
+	}
+	if err := doSearchRequest(p.httpClient, req, &result); err != nil {
+		return nil, err
+	}
+
+	hits := make([]SearchHit, 0, len(result.Items))
+	for _, item := range result.Items {
+		hits = append(hits, SearchHit{Title: item.Title, URL: item.Link, Snippet: item.Snippet})
+	}
+	return hits, nil
+}
+
+// doSearchRequest executes a search request and decodes its JSON response into v
+func doSearchRequest(client *http.Client, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("search API returned status code %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
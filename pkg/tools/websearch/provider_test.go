@@ -0,0 +1,96 @@
+package websearch_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/websearch"
+)
+
+func TestBraveProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/res/v1/web/search" || r.URL.Query().Get("q") != "golang" || r.URL.Query().Get("count") != "2" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		if r.Header.Get("X-Subscription-Token") != "brave-key" {
+			t.Errorf("Expected the subscription token, got %q", r.Header.Get("X-Subscription-Token"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"web": map[string]interface{}{
+				"results": []map[string]interface{}{
+					{"title": "The Go Programming Language", "url": "https://go.dev", "description": "Build simple, secure, scalable systems with Go"},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &mockTransport{server: server}}
+	tool := websearch.NewWithProvider(websearch.NewBraveProvider("brave-key", websearch.WithProviderHTTPClient(client)))
+
+	result, err := tool.Run(context.Background(), `{"query": "golang", "num_results": 2}`)
+	if err != nil {
+		t.Fatalf("Failed to run tool: %v", err)
+	}
+	if !contains(result, "1. The Go Programming Language") || !contains(result, "URL: https://go.dev") {
+		t.Errorf("Unexpected result: %q", result)
+	}
+}
+
+func TestDuckDuckGoProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "golang" || r.URL.Query().Get("format") != "json" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Heading":      "Go (programming language)",
+			"AbstractText": "Go is a statically typed, compiled programming language.",
+			"AbstractURL":  "https://en.wikipedia.org/wiki/Go_(programming_language)",
+			"RelatedTopics": []map[string]interface{}{
+				{"Text": "Gopher - The mascot of Go", "FirstURL": "https://duckduckgo.com/Gopher"},
+				{"Name": "See also", "Topics": []map[string]interface{}{
+					{"Text": "Rob Pike - A co-designer of Go", "FirstURL": "https://duckduckgo.com/Rob_Pike"},
+					{"Text": "Ken Thompson - A co-designer of Go", "FirstURL": "https://duckduckgo.com/Ken_Thompson"},
+				}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &mockTransport{server: server}}
+	provider := websearch.NewDuckDuckGoProvider(websearch.WithProviderHTTPClient(client))
+
+	hits, err := provider.Search(context.Background(), "golang", 3)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(hits) != 3 {
+		t.Fatalf("Expected 3 hits, got %d: %+v", len(hits), hits)
+	}
+	if hits[0].Title != "Go (programming language)" || hits[0].URL != "https://en.wikipedia.org/wiki/Go_(programming_language)" {
+		t.Errorf("Expected the abstract first, got %+v", hits[0])
+	}
+	if hits[1].Title != "Gopher" || hits[2].Title != "Rob Pike" || hits[2].URL != "https://duckduckgo.com/Rob_Pike" {
+		t.Errorf("Expected the related topics, flattened, got %+v", hits[1:])
+	}
+}
+
+func TestProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &mockTransport{server: server}}
+	tool := websearch.NewWithProvider(websearch.NewBraveProvider("brave-key", websearch.WithProviderHTTPClient(client)))
+	if _, err := tool.Run(context.Background(), "golang"); err == nil || !contains(err.Error(), "429") {
+		t.Errorf("Expected the status code in the error, got %v", err)
+	}
+}
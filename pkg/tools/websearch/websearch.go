@@ -5,20 +5,37 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
-	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 )
 
 // Tool implements a web search tool
 type Tool struct {
-	apiKey     string
-	engineID   string
-	httpClient *http.Client
-	cache      map[string]cacheEntry
+	apiKey              string
+	engineID            string
+	provider            SearchProvider
+	httpClient          *http.Client
+	credentialsResolver CredentialsResolver
+	cache               map[cacheKey]cacheEntry
+	cacheMu             sync.Mutex
+}
+
+// Credentials are the Google Custom Search credentials used to run a search
+type Credentials struct {
+	APIKey   string
+	EngineID string
+}
+
+// CredentialsResolver resolves the credentials of a search from its context, e.g. from the organization ID
+type CredentialsResolver func(ctx context.Context) (Credentials, error)
+
+// cacheKey scopes cached results to the credentials they were fetched with
+type cacheKey struct {
+	credentials Credentials
+	query       string
 }
 
 type cacheEntry struct {
@@ -29,20 +46,28 @@ type cacheEntry struct {
 // Option represents an option for configuring the tool
 type Option func(*Tool)
 
-// WithHTTPClient sets the HTTP client for the tool
+// WithHTTPClient sets the HTTP client of the default Google provider of New
 func WithHTTPClient(client *http.Client) Option {
 	return func(t *Tool) {
 		t.httpClient = client
 	}
 }
 
-// New creates a new web search tool
+// WithCredentialsResolver resolves the credentials at execution time instead of using the ones
+// given to New, so that a single registered tool can serve many tenants with their own credentials
+func WithCredentialsResolver(resolver CredentialsResolver) Option {
+	return func(t *Tool) {
+		t.credentialsResolver = resolver
+	}
+}
+
+// New creates a new web search tool searching with Google Custom Search
 func New(apiKey, engineID string, options ...Option) *Tool {
 	tool := &Tool{
 		apiKey:     apiKey,
 		engineID:   engineID,
 		httpClient: &http.Client{Timeout: 10 * time.Second},
-		cache:      make(map[string]cacheEntry),
+		cache:      make(map[cacheKey]cacheEntry),
 	}
 
 	for _, option := range options {
@@ -52,6 +77,14 @@ func New(apiKey, engineID string, options ...Option) *Tool {
 	return tool
 }
 
+// NewWithProvider creates a new web search tool searching with the given provider, e.g.
+// NewBraveProvider or NewDuckDuckGoProvider. Credentials resolvers do not apply to it.
+func NewWithProvider(provider SearchProvider, options ...Option) *Tool {
+	tool := New("", "", options...)
+	tool.provider = provider
+	return tool
+}
+
 // Name returns the name of the tool
 func (t *Tool) Name() string {
 	return "web_search"
@@ -112,83 +145,64 @@ func (t *Tool) Run(ctx context.Context, input string) (string, error) {
 		numResults = int(num)
 	}
 
-	// Check cache
-	if entry, ok := t.cache[query]; ok {
-		if time.Since(entry.timestamp) < 1*time.Hour {
-			return entry.result, nil
-		}
-	}
-
-	// Get organization ID for API key management
-	orgID, _ := multitenancy.GetOrgID(ctx)
-
-	// Build request URL
-	searchURL := fmt.Sprintf(
-		"https://www.googleapis.com/customsearch/v1?key=%s&cx=%s&q=%s&num=%d",
-		t.apiKey,
-		t.engineID,
-		url.QueryEscape(query),
-		numResults,
-	)
-
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add organization ID to request headers if available
-	if orgID != "" {
-		req.Header.Set("X-Organization-ID", orgID)
-	}
-
-	// Execute request
-	resp, err := t.httpClient.Do(req)
+	// Resolve the credentials of this search
+	credentials, err := t.credentials(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute request: %w", err)
+		return "", err
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			err = fmt.Errorf("failed to close response body: %w", closeErr)
-		}
-	}()
+	key := cacheKey{credentials: credentials, query: query}
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("search API returned status code %d: %s", resp.StatusCode, resp.Status)
+	// Check cache
+	t.cacheMu.Lock()
+	entry, ok := t.cache[key]
+	t.cacheMu.Unlock()
+	if ok && time.Since(entry.timestamp) < 1*time.Hour {
+		return entry.result, nil
 	}
 
-	// Parse response
-	var result struct {
-		Items []struct {
-			Title       string `json:"title"`
-			Link        string `json:"link"`
-			Snippet     string `json:"snippet"`
-			DisplayLink string `json:"displayLink"`
-		} `json:"items"`
+	// Search with the configured provider, Google Custom Search by default
+	provider := t.provider
+	if provider == nil {
+		provider = &GoogleProvider{credentials: credentials, httpClient: t.httpClient}
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	hits, err := provider.Search(ctx, query, numResults)
+	if err != nil {
+		return "", err
 	}
 
 	// Format results
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Search results for '%s':\n\n", query))
-	for i, item := range result.Items {
-		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, item.Title))
-		sb.WriteString(fmt.Sprintf("   URL: %s\n", item.Link))
-		sb.WriteString(fmt.Sprintf("   %s\n\n", item.Snippet))
+	for i, hit := range hits {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, hit.Title))
+		sb.WriteString(fmt.Sprintf("   URL: %s\n", hit.URL))
+		sb.WriteString(fmt.Sprintf("   %s\n\n", hit.Snippet))
 	}
 
 	// Cache result
-	t.cache[query] = cacheEntry{
+	t.cacheMu.Lock()
+	t.cache[key] = cacheEntry{
 		result:    sb.String(),
 		timestamp: time.Now(),
 	}
+	t.cacheMu.Unlock()
 
 	return sb.String(), nil
 }
 
+// credentials returns the credentials to search with, resolving them from the context if configured
+func (t *Tool) credentials(ctx context.Context) (Credentials, error) {
+	if t.credentialsResolver == nil || t.provider != nil {
+		return Credentials{APIKey: t.apiKey, EngineID: t.engineID}, nil
+	}
+
+	credentials, err := t.credentialsResolver(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to resolve credentials: %w", err)
+	}
+	return credentials, nil
+}
+
 func (t *Tool) Execute(ctx context.Context, args string) (string, error) {
 	// Parse args as JSON
 	var params struct {
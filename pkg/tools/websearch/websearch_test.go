@@ -3,6 +3,7 @@ package websearch_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -125,3 +126,59 @@ func (t *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestWebSearchCredentialsResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		engine := r.URL.Query().Get("cx")
+
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"title": "Result for " + key + "/" + engine, "link": "https://example.com"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	credentials := map[string]websearch.Credentials{
+		"org-a": {APIKey: "key-a", EngineID: "engine-a"},
+		"org-b": {APIKey: "key-b", EngineID: "engine-b"},
+	}
+	tool := websearch.New(
+		"",
+		"",
+		websearch.WithHTTPClient(&http.Client{Transport: &mockTransport{server: server}}),
+		websearch.WithCredentialsResolver(func(ctx context.Context) (websearch.Credentials, error) {
+			orgID, err := multitenancy.GetOrgID(ctx)
+			if err != nil {
+				return websearch.Credentials{}, err
+			}
+			creds, ok := credentials[orgID]
+			if !ok {
+				return websearch.Credentials{}, fmt.Errorf("no credentials for organization %s", orgID)
+			}
+			return creds, nil
+		}),
+	)
+
+	for orgID, creds := range credentials {
+		ctx := multitenancy.WithOrgID(context.Background(), orgID)
+		result, err := tool.Run(ctx, "test query")
+		if err != nil {
+			t.Fatalf("Failed to run tool for %s: %v", orgID, err)
+		}
+		expected := "Result for " + creds.APIKey + "/" + creds.EngineID
+		if !contains(result, expected) {
+			t.Errorf("Expected result for %s to contain %q, got %q", orgID, expected, result)
+		}
+	}
+
+	_, err := tool.Run(multitenancy.WithOrgID(context.Background(), "org-c"), "test query")
+	if err == nil || !contains(err.Error(), "failed to resolve credentials") {
+		t.Errorf("Expected credentials resolution error, got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package tracing
 
 import (
 	"context"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Context keys for tracing
@@ -83,3 +85,19 @@ func GetSpanNameOrDefault(ctx context.Context, defaultName string) string {
 	}
 	return defaultName
 }
+
+// WithTraceContext returns ctx with the tracing values of parent: the current span of the
+// OpenTelemetry, Langfuse and JSON tracers, the trace name, the trace and request IDs and the
+// agent name. It keeps spans connected across contexts that do not derive from parent, such as
+// the restricted contexts of tools.
+func WithTraceContext(ctx, parent context.Context) context.Context {
+	if span := trace.SpanFromContext(parent); span.SpanContext().IsValid() {
+		ctx = trace.ContextWithSpan(ctx, span)
+	}
+	for _, key := range []interface{}{TraceNameKey, TraceIDKey, RequestIDKey, AgentNameKey, jsonSpanKey{}, toolCallsKey{}} {
+		if value := parent.Value(key); value != nil {
+			ctx = context.WithValue(ctx, key, value)
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,325 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// Kinds of the spans written by a JSONTracer
+const (
+	SpanKindSpan  = "span"
+	SpanKindLLM   = "llm"
+	SpanKindTool  = "tool"
+	SpanKindEvent = "event"
+)
+
+// SpanRecord is a span written by a JSONTracer, one JSON object per line
+type SpanRecord struct {
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Name       string                 `json:"name"`
+	Kind       string                 `json:"kind"`
+	StartTime  time.Time              `json:"start_time"`
+	EndTime    time.Time              `json:"end_time"`
+	DurationMs int64                  `json:"duration_ms"`
+	Model      string                 `json:"model,omitempty"`
+	Input      string                 `json:"input,omitempty"`
+	Output     string                 `json:"output,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	OrgID      string                 `json:"org_id,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Events     []SpanEventRecord      `json:"events,omitempty"`
+}
+
+// SpanEventRecord is an event added to a span written by a JSONTracer
+type SpanEventRecord struct {
+	Name       string                 `json:"name"`
+	Time       time.Time              `json:"time"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// JSONTracer writes spans as JSON lines, so that traces can be inspected locally, in CI or
+// offline without a tracing backend. It implements interfaces.Tracer for agents and
+// GenerationTracer for NewOTELLLMMiddleware, which traces LLM calls and their tool calls.
+type JSONTracer struct {
+	mu         sync.Mutex
+	encoder    *json.Encoder
+	closer     io.Closer
+	anonymizer anonymizer.Anonymizer
+}
+
+// JSONTracerOption configures a JSONTracer
+type JSONTracerOption func(*JSONTracer)
+
+// WithJSONAnonymizer masks sensitive content of the inputs, outputs, errors and attributes of
+// the spans before they are written
+func WithJSONAnonymizer(a anonymizer.Anonymizer) JSONTracerOption {
+	return func(t *JSONTracer) {
+		t.anonymizer = a
+	}
+}
+
+// NewJSONTracer creates a tracer writing spans as JSON lines to w
+func NewJSONTracer(w io.Writer, options ...JSONTracerOption) *JSONTracer {
+	tracer := &JSONTracer{encoder: json.NewEncoder(w)}
+	for _, option := range options {
+		option(tracer)
+	}
+	return tracer
+}
+
+// NewFileTracer creates a tracer appending spans as JSON lines to the file at path, which is
+// created if needed. Close the tracer to close the file.
+func NewFileTracer(path string, options ...JSONTracerOption) (*JSONTracer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+
+	tracer := NewJSONTracer(file, options...)
+	tracer.closer = file
+	return tracer, nil
+}
+
+// NewStdoutTracer creates a tracer writing spans as JSON lines to standard output
+func NewStdoutTracer(options ...JSONTracerOption) *JSONTracer {
+	return NewJSONTracer(os.Stdout, options...)
+}
+
+// jsonSpanKey is the context key of the current span of a JSONTracer
+type jsonSpanKey struct{}
+
+// jsonSpanContext identifies the current span of a context
+type jsonSpanContext struct {
+	traceID string
+	spanID  string
+}
+
+// newSpanRecord starts the record of a span, child of the current span of ctx if any
+func (t *JSONTracer) newSpanRecord(ctx context.Context, name, kind string, start time.Time) *SpanRecord {
+	record := &SpanRecord{
+		SpanID:    newID(8),
+		Name:      name,
+		Kind:      kind,
+		StartTime: start,
+	}
+	if parent, ok := ctx.Value(jsonSpanKey{}).(jsonSpanContext); ok {
+		record.TraceID = parent.traceID
+		record.ParentID = parent.spanID
+	} else {
+		record.TraceID = newID(16)
+	}
+	record.OrgID, _ = multitenancy.GetOrgID(ctx)
+	return record
+}
+
+// write writes a finished span record
+func (t *JSONTracer) write(record *SpanRecord) error {
+	record.DurationMs = record.EndTime.Sub(record.StartTime).Milliseconds()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.encoder.Encode(record); err != nil {
+		return fmt.Errorf("failed to write span: %w", err)
+	}
+	return nil
+}
+
+// StartSpan implements interfaces.Tracer
+func (t *JSONTracer) StartSpan(ctx context.Context, name string) (context.Context, interfaces.Span) {
+	record := t.newSpanRecord(ctx, name, SpanKindSpan, time.Now())
+	if agentName, ok := GetAgentName(ctx); ok {
+		record.Attributes = map[string]interface{}{"agent_name": agentName}
+	}
+
+	ctx = context.WithValue(ctx, jsonSpanKey{}, jsonSpanContext{traceID: record.TraceID, spanID: record.SpanID})
+	return ctx, &jsonSpan{tracer: t, ctx: ctx, record: record}
+}
+
+// StartTraceSession implements interfaces.Tracer
+func (t *JSONTracer) StartTraceSession(ctx context.Context, contextID string) (context.Context, interfaces.Span) {
+	ctx, span := t.StartSpan(ctx, "request-session")
+	span.SetAttribute("context_id", contextID)
+
+	ctx = WithTraceName(ctx, contextID)
+	ctx = WithRequestID(ctx, contextID)
+	return ctx, span
+}
+
+// TraceGeneration writes the span of an LLM generation, with a child span for each tool call
+// collected in ctx, see WithToolCallsCollection. It returns the ID of the span.
+func (t *JSONTracer) TraceGeneration(ctx context.Context, modelName string, prompt string, response string, startTime time.Time, endTime time.Time, metadata map[string]interface{}) (string, error) {
+	record := t.newSpanRecord(ctx, "llm.generate", SpanKindLLM, startTime)
+	record.EndTime = endTime
+	record.Model = modelName
+	record.Input = t.anonymize(ctx, prompt)
+	record.Output = t.anonymize(ctx, response)
+	record.Attributes = t.anonymizeFields(ctx, metadata)
+
+	for _, toolCall := range GetToolCallsFromContext(ctx) {
+		toolStart := toolCall.StartTime
+		if toolStart.IsZero() {
+			toolStart = startTime
+		}
+		tool := &SpanRecord{
+			TraceID:   record.TraceID,
+			SpanID:    newID(8),
+			ParentID:  record.SpanID,
+			Name:      "tool." + toolCall.Name,
+			Kind:      SpanKindTool,
+			StartTime: toolStart,
+			EndTime:   toolStart.Add(toolCall.Duration),
+			Input:     t.anonymize(ctx, toolCall.Arguments),
+			Output:    t.anonymize(ctx, toolCall.Result),
+			Error:     t.anonymize(ctx, toolCall.Error),
+			OrgID:     record.OrgID,
+		}
+		if toolCall.ID != "" {
+			tool.Attributes = map[string]interface{}{"tool_call_id": toolCall.ID}
+		}
+		if err := t.write(tool); err != nil {
+			return "", err
+		}
+	}
+
+	if err := t.write(record); err != nil {
+		return "", err
+	}
+	return record.SpanID, nil
+}
+
+// TraceEvent writes an event, such as a failed LLM call, as a span without duration. The
+// "error" entry of the metadata of error events is written as the error of the span. It
+// returns the ID of the span.
+func (t *JSONTracer) TraceEvent(ctx context.Context, name string, input interface{}, output interface{}, level string, metadata map[string]interface{}, parentID string) (string, error) {
+	now := time.Now()
+	record := t.newSpanRecord(ctx, name, SpanKindEvent, now)
+	record.EndTime = now
+	if parentID != "" {
+		record.ParentID = parentID
+	}
+	if input != nil {
+		record.Input = t.anonymize(ctx, fmt.Sprintf("%v", input))
+	}
+	if output != nil {
+		record.Output = t.anonymize(ctx, fmt.Sprintf("%v", output))
+	}
+
+	fields := make(map[string]interface{}, len(metadata)+1)
+	for key, value := range metadata {
+		fields[key] = value
+	}
+	if errorMessage, ok := fields["error"]; ok && level == "error" {
+		record.Error = t.anonymize(ctx, fmt.Sprintf("%v", errorMessage))
+		delete(fields, "error")
+	}
+	if level != "" {
+		fields["level"] = level
+	}
+	record.Attributes = t.anonymizeFields(ctx, fields)
+
+	if err := t.write(record); err != nil {
+		return "", err
+	}
+	return record.SpanID, nil
+}
+
+// Close closes the file of a tracer created with NewFileTracer
+func (t *JSONTracer) Close() error {
+	if t.closer == nil {
+		return nil
+	}
+	return t.closer.Close()
+}
+
+// anonymize masks sensitive content according to the configured anonymizer
+func (t *JSONTracer) anonymize(ctx context.Context, value string) string {
+	if t.anonymizer == nil || value == "" {
+		return value
+	}
+	return t.anonymizer.Anonymize(ctx, value)
+}
+
+// anonymizeFields masks the values of span attributes, see anonymizer.Fields
+func (t *JSONTracer) anonymizeFields(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	return anonymizer.Fields(ctx, t.anonymizer, fields)
+}
+
+// jsonSpan is a span of a JSONTracer, written when it ends
+type jsonSpan struct {
+	tracer *JSONTracer
+	ctx    context.Context
+
+	mu     sync.Mutex
+	record *SpanRecord
+	ended  bool
+}
+
+// End implements interfaces.Span
+func (s *jsonSpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.record.EndTime = time.Now()
+	s.mu.Unlock()
+
+	_ = s.tracer.write(s.record)
+}
+
+// AddEvent implements interfaces.Span
+func (s *jsonSpan) AddEvent(name string, attributes map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.record.Events = append(s.record.Events, SpanEventRecord{
+		Name:       name,
+		Time:       time.Now(),
+		Attributes: s.tracer.anonymizeFields(s.ctx, attributes),
+	})
+}
+
+// SetAttribute implements interfaces.Span. The input, output and error attributes are written
+// as the input, output and error of the span.
+func (s *jsonSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch key {
+	case "input":
+		s.record.Input = s.tracer.anonymize(s.ctx, fmt.Sprintf("%v", value))
+	case "output":
+		s.record.Output = s.tracer.anonymize(s.ctx, fmt.Sprintf("%v", value))
+	case "error":
+		s.record.Error = s.tracer.anonymize(s.ctx, fmt.Sprintf("%v", value))
+	default:
+		if s.record.Attributes == nil {
+			s.record.Attributes = make(map[string]interface{})
+		}
+		for k, v := range s.tracer.anonymizeFields(s.ctx, map[string]interface{}{key: value}) {
+			s.record.Attributes[k] = v
+		}
+	}
+}
+
+// newID returns a random hexadecimal ID of n bytes
+func newID(n int) string {
+	id := make([]byte, n)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,162 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// toolCallingLLM records a tool call in the tracing context, like the clients do
+type toolCallingLLM struct {
+	err error
+}
+
+func (l *toolCallingLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	if l.err != nil {
+		return "", l.err
+	}
+	AddToolCallToContext(ctx, ToolCall{
+		Name:      "lookup",
+		ID:        "call_1",
+		Arguments: `{"email":"jane@example.com"}`,
+		Result:    "found",
+		StartTime: time.Now(),
+		Duration:  5 * time.Millisecond,
+	})
+	return "Sent to jane@example.com", nil
+}
+
+func (l *toolCallingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return l.Generate(ctx, prompt, options...)
+}
+
+func (l *toolCallingLLM) Name() string            { return "test" }
+func (l *toolCallingLLM) SupportsStreaming() bool { return false }
+func (l *toolCallingLLM) GetModel() string        { return "gpt-4o" }
+
+func readSpanRecords(t *testing.T, data []byte) []SpanRecord {
+	t.Helper()
+	var records []SpanRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record SpanRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestJSONTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewJSONTracer(&buf, WithJSONAnonymizer(anonymizer.NewPIIRedactor()))
+	var _ interfaces.Tracer = tracer
+
+	ctx, agentSpan := tracer.StartSpan(context.Background(), "agent.Run")
+	agentSpan.SetAttribute("input", "Email jane@example.com")
+	agentSpan.SetAttribute("iterations", 2)
+
+	middleware := NewOTELLLMMiddleware(&toolCallingLLM{}, tracer)
+	if _, err := middleware.Generate(ctx, "Email jane@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	agentSpan.End()
+
+	records := readSpanRecords(t, buf.Bytes())
+	if len(records) != 3 {
+		t.Fatalf("expected 3 spans, got %d: %s", len(records), buf.String())
+	}
+	tool, generation, agent := records[0], records[1], records[2]
+
+	if agent.Name != "agent.Run" || agent.Kind != SpanKindSpan || agent.ParentID != "" {
+		t.Errorf("unexpected agent span: %+v", agent)
+	}
+	if agent.Attributes["iterations"] != float64(2) {
+		t.Errorf("expected the attributes of the agent span, got %v", agent.Attributes)
+	}
+	if generation.Kind != SpanKindLLM || generation.Model != "gpt-4o" || generation.ParentID != agent.SpanID {
+		t.Errorf("unexpected generation span: %+v", generation)
+	}
+	if tool.Name != "tool.lookup" || tool.Kind != SpanKindTool || tool.ParentID != generation.SpanID || tool.Output != "found" {
+		t.Errorf("unexpected tool span: %+v", tool)
+	}
+	if tool.DurationMs != 5 {
+		t.Errorf("expected the duration of the tool call, got %dms", tool.DurationMs)
+	}
+	for _, record := range records {
+		if record.TraceID != agent.TraceID {
+			t.Errorf("expected span %s in the trace of the agent", record.Name)
+		}
+	}
+
+	// Inputs and outputs are redacted
+	if strings.Contains(buf.String(), "jane@example.com") {
+		t.Errorf("expected sensitive content to be redacted: %s", buf.String())
+	}
+	if agent.Input == "" || generation.Input == "" || generation.Output == "" || tool.Input == "" {
+		t.Error("expected the inputs and outputs to be written")
+	}
+}
+
+func TestJSONTracerError(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewJSONTracer(&buf)
+
+	failure := errors.New("503 service unavailable")
+	middleware := NewOTELLLMMiddleware(&toolCallingLLM{err: failure}, tracer)
+	if _, err := middleware.Generate(context.Background(), "What is the capital of France?"); !errors.Is(err, failure) {
+		t.Fatalf("expected the error of the LLM, got %v", err)
+	}
+
+	records := readSpanRecords(t, buf.Bytes())
+	if len(records) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(records))
+	}
+	if records[0].Error != "503 service unavailable" || records[0].Input != "What is the capital of France?" {
+		t.Errorf("unexpected error span: %+v", records[0])
+	}
+	if records[0].Attributes["level"] != "error" {
+		t.Errorf("expected the level of the event, got %v", records[0].Attributes)
+	}
+}
+
+func TestFileTracer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	tracer, err := NewFileTracer(path)
+	if err != nil {
+		t.Fatalf("NewFileTracer failed: %v", err)
+	}
+
+	ctx, session := tracer.StartTraceSession(context.Background(), "request-1")
+	_, span := tracer.StartSpan(ctx, "agent.Run")
+	span.End()
+	span.End()
+	session.End()
+	if err := tracer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read traces: %v", err)
+	}
+	records := readSpanRecords(t, data)
+	if len(records) != 2 {
+		t.Fatalf("expected each span to be written once, got %d spans", len(records))
+	}
+	if records[1].Name != "request-session" || records[1].Attributes["context_id"] != "request-1" {
+		t.Errorf("unexpected session span: %+v", records[1])
+	}
+	if records[0].ParentID != records[1].SpanID {
+		t.Error("expected the span to be a child of the session")
+	}
+}
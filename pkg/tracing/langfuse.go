@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
 	"github.com/Ingenimax/agent-sdk-go/pkg/config"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
@@ -32,6 +33,9 @@ type LangfuseConfig struct {
 
 	// Environment is the environment name (e.g., "production", "staging")
 	Environment string
+
+	// Anonymizer masks sensitive content of spans before they are sent (optional)
+	Anonymizer anonymizer.Anonymizer
 }
 
 // NewLangfuseTracer creates a new Langfuse tracer (backward compatibility wrapper)
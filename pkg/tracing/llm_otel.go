@@ -3,27 +3,53 @@ package tracing
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // LLMOTelMiddleware wraps an LLM with OpenTelemetry tracing
 type LLMOTelMiddleware struct {
 	llm    interfaces.LLM
 	tracer *OTelTracer
+	genAI  bool
+}
+
+// LLMOTelOption configures an LLMOTelMiddleware
+type LLMOTelOption func(*LLMOTelMiddleware)
+
+// WithGenAISemanticConventions traces LLM calls with the attributes of the OpenTelemetry GenAI
+// semantic conventions (gen_ai.system, gen_ai.request.model, gen_ai.usage.input_tokens, ...)
+// instead of the SDK-specific ones, so that traces are understood by any OTEL backend
+func WithGenAISemanticConventions() LLMOTelOption {
+	return func(m *LLMOTelMiddleware) {
+		m.genAI = true
+	}
 }
 
 // NewLLMOTelMiddleware creates a new LLMOTelMiddleware
-func NewLLMOTelMiddleware(llm interfaces.LLM, tracer *OTelTracer) *LLMOTelMiddleware {
-	return &LLMOTelMiddleware{
+func NewLLMOTelMiddleware(llm interfaces.LLM, tracer *OTelTracer, options ...LLMOTelOption) *LLMOTelMiddleware {
+	m := &LLMOTelMiddleware{
 		llm:    llm,
 		tracer: tracer,
 	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
 }
 
 // Generate implements interfaces.LLM.Generate
 func (m *LLMOTelMiddleware) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	if m.genAI {
+		return m.traceGenAI(ctx, options, func(ctx context.Context) (string, error) {
+			return m.llm.Generate(ctx, prompt, options...)
+		})
+	}
+
 	// Create attributes
 	attributes := map[string]string{
 		"prompt.length": fmt.Sprintf("%d", len(prompt)),
@@ -43,7 +69,7 @@ func (m *LLMOTelMiddleware) Generate(ctx context.Context, prompt string, options
 	if err == nil {
 		span.SetAttributes(attribute.Int("response.length", len(response)))
 	} else {
-		span.RecordError(err)
+		m.tracer.recordError(ctx, span, err)
 	}
 
 	return response, err
@@ -51,6 +77,12 @@ func (m *LLMOTelMiddleware) Generate(ctx context.Context, prompt string, options
 
 // GenerateWithTools implements interfaces.LLM.GenerateWithTools
 func (m *LLMOTelMiddleware) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	if m.genAI {
+		return m.traceGenAI(ctx, options, func(ctx context.Context) (string, error) {
+			return m.llm.GenerateWithTools(ctx, prompt, tools, options...)
+		})
+	}
+
 	// Create attributes
 	attributes := map[string]string{
 		"prompt.length": fmt.Sprintf("%d", len(prompt)),
@@ -70,7 +102,7 @@ func (m *LLMOTelMiddleware) GenerateWithTools(ctx context.Context, prompt string
 	if err == nil {
 		span.SetAttributes(attribute.Int("response.length", len(response)))
 	} else {
-		span.RecordError(err)
+		m.tracer.recordError(ctx, span, err)
 	}
 
 	return response, err
@@ -85,3 +117,97 @@ func (m *LLMOTelMiddleware) Name() string {
 func (m *LLMOTelMiddleware) SupportsStreaming() bool {
 	return m.llm.SupportsStreaming()
 }
+
+// genAISystems maps the names of the LLM clients to the gen_ai.system values of the GenAI
+// semantic conventions, for the clients whose name differs
+var genAISystems = map[string]string{
+	"azure-openai": "az.ai.openai",
+	"gemini":       "gcp.gemini",
+}
+
+// traceGenAI traces an LLM call in a span following the GenAI semantic conventions. The usage,
+// response model and finish reason come from the completion summaries the client reports; the
+// usage of the calls of a tool loop is summed up.
+func (m *LLMOTelMiddleware) traceGenAI(ctx context.Context, options []interfaces.GenerateOption, call func(ctx context.Context) (string, error)) (string, error) {
+	system := m.llm.Name()
+	if mapped, ok := genAISystems[system]; ok {
+		system = mapped
+	}
+	model := m.llm.Name()
+	if modelLLM, ok := m.llm.(interface{ GetModel() string }); ok && modelLLM.GetModel() != "" {
+		model = modelLLM.GetModel()
+	}
+
+	ctx, span := m.tracer.StartSpan(ctx, "chat "+model, map[string]string{
+		"gen_ai.operation.name": "chat",
+		"gen_ai.system":         system,
+		"gen_ai.request.model":  model,
+	})
+	defer func() {
+		m.tracer.EndSpan(span, nil)
+	}()
+
+	params := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(params)
+	}
+	if config := params.LLMConfig; config != nil {
+		if config.Temperature != 0 {
+			span.SetAttributes(attribute.Float64("gen_ai.request.temperature", config.Temperature))
+		}
+		if config.TopP != 0 {
+			span.SetAttributes(attribute.Float64("gen_ai.request.top_p", config.TopP))
+		}
+		if maxTokens := max(config.MaxTokens, config.MaxCompletionTokens); maxTokens != 0 {
+			span.SetAttributes(attribute.Int("gen_ai.request.max_tokens", maxTokens))
+		}
+		if len(config.StopSequences) > 0 {
+			span.SetAttributes(attribute.StringSlice("gen_ai.request.stop_sequences", config.StopSequences))
+		}
+	}
+
+	var (
+		mu                        sync.Mutex
+		inputTokens, outputTokens int64
+		responseModel             string
+		finishReason              interfaces.FinishReason
+		reported                  bool
+	)
+	ctx = llm.WithCompletionObserver(ctx, func(ctx context.Context, summary llm.CompletionSummary) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = true
+		inputTokens += summary.PromptTokens
+		outputTokens += summary.ResponseTokens
+		if summary.Model != "" {
+			responseModel = summary.Model
+		}
+		if summary.FinishReason != "" {
+			finishReason = summary.FinishReason
+		}
+	})
+
+	response, err := call(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if reported {
+		span.SetAttributes(
+			attribute.Int64("gen_ai.usage.input_tokens", inputTokens),
+			attribute.Int64("gen_ai.usage.output_tokens", outputTokens),
+		)
+	}
+	if responseModel != "" {
+		span.SetAttributes(attribute.String("gen_ai.response.model", responseModel))
+	}
+	if finishReason != "" {
+		span.SetAttributes(attribute.StringSlice("gen_ai.response.finish_reasons", []string{string(finishReason)}))
+	}
+	if err != nil {
+		span.SetAttributes(attribute.String("error.type", fmt.Sprintf("%T", err)))
+		m.tracer.recordError(ctx, span, err)
+		span.SetStatus(codes.Error, m.tracer.anonymize(ctx, err.Error()))
+	}
+
+	return response, err
+}
@@ -0,0 +1,192 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// completionLLM reports a completion summary for each call, like the clients do
+type completionLLM struct {
+	name string
+	err  error
+}
+
+func (l *completionLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	llm.LogCompletion(ctx, nil, llm.CompletionLogLevelNone, llm.CompletionSummary{
+		Provider:       l.name,
+		Model:          "gpt-4o-2024-08-06",
+		PromptTokens:   120,
+		ResponseTokens: 30,
+		FinishReason:   interfaces.FinishReasonStop,
+		Err:            l.err,
+	})
+	if l.err != nil {
+		return "", l.err
+	}
+	return "Paris", nil
+}
+
+func (l *completionLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	// A tool loop reports a completion per call to the model
+	llm.LogCompletion(ctx, nil, llm.CompletionLogLevelNone, llm.CompletionSummary{
+		Model:          "gpt-4o-2024-08-06",
+		PromptTokens:   100,
+		ResponseTokens: 10,
+		FinishReason:   interfaces.FinishReasonToolUse,
+	})
+	return l.Generate(ctx, prompt, options...)
+}
+
+func (l *completionLLM) Name() string            { return l.name }
+func (l *completionLLM) SupportsStreaming() bool { return false }
+func (l *completionLLM) GetModel() string        { return "gpt-4o" }
+
+func newRecordingOTelTracer() (*OTelTracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return &OTelTracer{tracer: provider.Tracer("test"), enabled: true}, exporter
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestLLMOTelMiddlewareGenAISemanticConventions(t *testing.T) {
+	tracer, exporter := newRecordingOTelTracer()
+	middleware := NewLLMOTelMiddleware(&completionLLM{name: "openai"}, tracer, WithGenAISemanticConventions())
+
+	_, err := middleware.Generate(context.Background(), "What is the capital of France?",
+		interfaces.WithTemperature(0.2), interfaces.WithMaxCompletionTokens(256))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name != "chat gpt-4o" {
+		t.Errorf("expected span name %q, got %q", "chat gpt-4o", spans[0].Name)
+	}
+
+	attrs := spanAttributes(spans[0])
+	expected := map[attribute.Key]interface{}{
+		"gen_ai.operation.name":          "chat",
+		"gen_ai.system":                  "openai",
+		"gen_ai.request.model":           "gpt-4o",
+		"gen_ai.request.temperature":     0.2,
+		"gen_ai.request.max_tokens":      int64(256),
+		"gen_ai.response.model":          "gpt-4o-2024-08-06",
+		"gen_ai.response.finish_reasons": []string{"stop"},
+		"gen_ai.usage.input_tokens":      int64(120),
+		"gen_ai.usage.output_tokens":     int64(30),
+	}
+	for key, want := range expected {
+		value, ok := attrs[key]
+		if !ok {
+			t.Errorf("expected attribute %s to be set", key)
+			continue
+		}
+		if want := keyValue(key, want).Value; value != want {
+			t.Errorf("expected %s to be %s, got %s", key, want.Emit(), value.Emit())
+		}
+	}
+	if _, ok := attrs["prompt.length"]; ok {
+		t.Error("expected the SDK-specific attributes not to be set")
+	}
+}
+
+func TestLLMOTelMiddlewareGenAIToolLoop(t *testing.T) {
+	tracer, exporter := newRecordingOTelTracer()
+	middleware := NewLLMOTelMiddleware(&completionLLM{name: "azure-openai"}, tracer, WithGenAISemanticConventions())
+
+	if _, err := middleware.GenerateWithTools(context.Background(), "What is the weather?", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	attrs := spanAttributes(exporter.GetSpans()[0])
+	if got := attrs["gen_ai.system"].AsString(); got != "az.ai.openai" {
+		t.Errorf("expected the well-known system of Azure OpenAI, got %q", got)
+	}
+	if got := attrs["gen_ai.usage.input_tokens"].AsInt64(); got != 220 {
+		t.Errorf("expected the input tokens of both calls, got %d", got)
+	}
+	if got := attrs["gen_ai.usage.output_tokens"].AsInt64(); got != 40 {
+		t.Errorf("expected the output tokens of both calls, got %d", got)
+	}
+	if got := attrs["gen_ai.response.finish_reasons"].AsStringSlice(); len(got) != 1 || got[0] != "stop" {
+		t.Errorf("expected the finish reason of the last call, got %v", got)
+	}
+}
+
+func TestLLMOTelMiddlewareGenAIError(t *testing.T) {
+	tracer, exporter := newRecordingOTelTracer()
+	failure := errors.New("503 service unavailable")
+	middleware := NewLLMOTelMiddleware(&completionLLM{name: "anthropic", err: failure}, tracer, WithGenAISemanticConventions())
+
+	if _, err := middleware.Generate(context.Background(), "What is the capital of France?"); !errors.Is(err, failure) {
+		t.Fatalf("expected the error of the LLM, got %v", err)
+	}
+
+	span := exporter.GetSpans()[0]
+	if span.Status.Code != codes.Error {
+		t.Errorf("expected an error status, got %v", span.Status.Code)
+	}
+	if got := spanAttributes(span)["error.type"].AsString(); got == "" {
+		t.Error("expected error.type to be set")
+	}
+}
+
+func TestLLMOTelMiddlewareAnonymizesErrors(t *testing.T) {
+	tracer, exporter := newRecordingOTelTracer()
+	tracer.anonymizer = anonymizer.NewPIIRedactor()
+	failure := errors.New("invalid recipient jane@example.com")
+
+	for _, option := range [][]LLMOTelOption{nil, {WithGenAISemanticConventions()}} {
+		middleware := NewLLMOTelMiddleware(&completionLLM{name: "openai", err: failure}, tracer, option...)
+		if _, err := middleware.Generate(context.Background(), "Send the invoice"); !errors.Is(err, failure) {
+			t.Fatalf("expected the error of the LLM, got %v", err)
+		}
+	}
+
+	for _, span := range exporter.GetSpans() {
+		if strings.Contains(span.Status.Description, "jane@example.com") {
+			t.Errorf("expected the status of %s to be anonymized, got %q", span.Name, span.Status.Description)
+		}
+		for _, event := range span.Events {
+			for _, attr := range event.Attributes {
+				if strings.Contains(attr.Value.Emit(), "jane@example.com") {
+					t.Errorf("expected the error of %s to be anonymized, got %q", span.Name, attr.Value.Emit())
+				}
+			}
+		}
+	}
+}
+
+func keyValue(key attribute.Key, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return key.String(v)
+	case float64:
+		return key.Float64(v)
+	case int64:
+		return key.Int64(v)
+	case []string:
+		return key.StringSlice(v)
+	}
+	return key.String("")
+}
@@ -2,8 +2,10 @@ package tracing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"go.opentelemetry.io/otel"
@@ -21,6 +23,7 @@ type OTelTracer struct {
 	tracer      trace.Tracer
 	enabled     bool
 	serviceName string
+	anonymizer  anonymizer.Anonymizer
 }
 
 // OTelConfig contains configuration for OpenTelemetry
@@ -33,6 +36,10 @@ type OTelConfig struct {
 
 	// CollectorEndpoint is the endpoint of the OpenTelemetry collector
 	CollectorEndpoint string
+
+	// Anonymizer masks sensitive content of span attributes and errors before they are
+	// exported (optional)
+	Anonymizer anonymizer.Anonymizer
 }
 
 // NewOTelTracer creates a new OpenTelemetry tracer
@@ -80,6 +87,7 @@ func NewOTelTracer(config OTelConfig) (*OTelTracer, error) {
 		tracer:      tracer,
 		enabled:     true,
 		serviceName: config.ServiceName,
+		anonymizer:  config.Anonymizer,
 	}, nil
 }
 
@@ -92,7 +100,7 @@ func (t *OTelTracer) StartSpan(ctx context.Context, name string, attributes map[
 	// Convert attributes to OpenTelemetry attributes
 	attrs := make([]attribute.KeyValue, 0, len(attributes))
 	for k, v := range attributes {
-		attrs = append(attrs, attribute.String(k, v))
+		attrs = append(attrs, attribute.String(k, t.anonymize(ctx, v)))
 	}
 
 	// Get organization ID from context
@@ -112,11 +120,27 @@ func (t *OTelTracer) EndSpan(span trace.Span, err error) {
 	}
 
 	if err != nil {
-		span.RecordError(err)
+		t.recordError(context.Background(), span, err)
 	}
 	span.End()
 }
 
+// recordError records an error on a span, masked by the anonymizer of the tracer
+func (t *OTelTracer) recordError(ctx context.Context, span trace.Span, err error) {
+	if t.anonymizer != nil {
+		err = errors.New(t.anonymize(ctx, err.Error()))
+	}
+	span.RecordError(err)
+}
+
+// anonymize masks sensitive content according to the anonymizer of the tracer
+func (t *OTelTracer) anonymize(ctx context.Context, value string) string {
+	if t.anonymizer == nil || value == "" {
+		return value
+	}
+	return t.anonymizer.Anonymize(ctx, value)
+}
+
 // MemoryOTelMiddleware implements middleware for memory operations with OpenTelemetry tracing
 type MemoryOTelMiddleware struct {
 	memory interfaces.Memory
@@ -148,7 +172,7 @@ func (m *MemoryOTelMiddleware) AddMessage(ctx context.Context, message interface
 	// Call the underlying memory
 	err := m.memory.AddMessage(ctx, message)
 	if err != nil {
-		span.RecordError(err)
+		m.tracer.recordError(ctx, span, err)
 	}
 
 	return err
@@ -165,7 +189,7 @@ func (m *MemoryOTelMiddleware) GetMessages(ctx context.Context, options ...inter
 	// Call the underlying memory
 	messages, err := m.memory.GetMessages(ctx, options...)
 	if err != nil {
-		span.RecordError(err)
+		m.tracer.recordError(ctx, span, err)
 	} else {
 		span.SetAttributes(attribute.Int("messages.count", len(messages)))
 	}
@@ -173,6 +197,45 @@ func (m *MemoryOTelMiddleware) GetMessages(ctx context.Context, options ...inter
 	return messages, err
 }
 
+// DeleteMessage deletes a message from memory with OpenTelemetry tracing
+func (m *MemoryOTelMiddleware) DeleteMessage(ctx context.Context, id string) error {
+	// Start span
+	ctx, span := m.tracer.StartSpan(ctx, "memory.delete_message", map[string]string{
+		"message.id": id,
+	})
+	defer func() {
+		m.tracer.EndSpan(span, nil)
+	}()
+
+	// Call the underlying memory
+	err := m.memory.DeleteMessage(ctx, id)
+	if err != nil {
+		m.tracer.recordError(ctx, span, err)
+	}
+
+	return err
+}
+
+// UpdateMessage updates a message in memory with OpenTelemetry tracing
+func (m *MemoryOTelMiddleware) UpdateMessage(ctx context.Context, id string, content string) error {
+	// Start span
+	ctx, span := m.tracer.StartSpan(ctx, "memory.update_message", map[string]string{
+		"message.id":      id,
+		"message.content": fmt.Sprintf("%d bytes", len(content)),
+	})
+	defer func() {
+		m.tracer.EndSpan(span, nil)
+	}()
+
+	// Call the underlying memory
+	err := m.memory.UpdateMessage(ctx, id, content)
+	if err != nil {
+		m.tracer.recordError(ctx, span, err)
+	}
+
+	return err
+}
+
 // Clear clears memory with OpenTelemetry tracing
 func (m *MemoryOTelMiddleware) Clear(ctx context.Context) error {
 	// Start span
@@ -184,7 +247,7 @@ func (m *MemoryOTelMiddleware) Clear(ctx context.Context) error {
 	// Call the underlying memory
 	err := m.memory.Clear(ctx)
 	if err != nil {
-		span.RecordError(err)
+		m.tracer.recordError(ctx, span, err)
 	}
 
 	return err
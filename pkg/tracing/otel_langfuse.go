@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
 	"github.com/Ingenimax/agent-sdk-go/pkg/config"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
@@ -32,7 +33,9 @@ type OTELLangfuseTracer struct {
 
 // OTELLangfuseSpan wraps an OTEL span to implement the interfaces.Span interface
 type OTELLangfuseSpan struct {
-	span trace.Span
+	span       trace.Span
+	ctx        context.Context
+	anonymizer anonymizer.Anonymizer
 }
 
 // End implements interfaces.Span
@@ -44,14 +47,22 @@ func (s *OTELLangfuseSpan) End() {
 func (s *OTELLangfuseSpan) AddEvent(name string, attributes map[string]interface{}) {
 	attrs := make([]attribute.KeyValue, 0, len(attributes))
 	for k, v := range attributes {
-		attrs = append(attrs, attribute.String(k, fmt.Sprintf("%v", v)))
+		attrs = append(attrs, attribute.String(k, s.anonymize(fmt.Sprintf("%v", v))))
 	}
 	s.span.AddEvent(name, trace.WithAttributes(attrs...))
 }
 
 // SetAttribute implements interfaces.Span
 func (s *OTELLangfuseSpan) SetAttribute(key string, value interface{}) {
-	s.span.SetAttributes(attribute.String(key, fmt.Sprintf("%v", value)))
+	s.span.SetAttributes(attribute.String(key, s.anonymize(fmt.Sprintf("%v", value))))
+}
+
+// anonymize masks sensitive content of a span value
+func (s *OTELLangfuseSpan) anonymize(value string) string {
+	if s.anonymizer == nil || s.ctx == nil {
+		return value
+	}
+	return s.anonymizer.Anonymize(s.ctx, value)
 }
 
 // NewOTELLangfuseTracer creates a new OTEL-based Langfuse tracer
@@ -188,7 +199,31 @@ func (t *OTELLangfuseTracer) StartSpan(ctx context.Context, name string) (contex
 	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
 
 	// Return wrapped span
-	return ctx, &OTELLangfuseSpan{span: span}
+	return ctx, &OTELLangfuseSpan{span: span, ctx: ctx, anonymizer: t.config.Anonymizer}
+}
+
+// anonymize masks sensitive content according to the configured anonymizer
+func (t *OTELLangfuseTracer) anonymize(ctx context.Context, value string) string {
+	if t.config.Anonymizer == nil {
+		return value
+	}
+	return t.config.Anonymizer.Anonymize(ctx, value)
+}
+
+// anonymizeToolCalls returns a copy of the tool calls with arguments, results and errors masked
+func (t *OTELLangfuseTracer) anonymizeToolCalls(ctx context.Context, toolCalls []ToolCall) []ToolCall {
+	if t.config.Anonymizer == nil || len(toolCalls) == 0 {
+		return toolCalls
+	}
+
+	anonymized := make([]ToolCall, len(toolCalls))
+	for i, toolCall := range toolCalls {
+		toolCall.Arguments = t.anonymize(ctx, toolCall.Arguments)
+		toolCall.Result = t.anonymize(ctx, toolCall.Result)
+		toolCall.Error = t.anonymize(ctx, toolCall.Error)
+		anonymized[i] = toolCall
+	}
+	return anonymized
 }
 
 // promptToAttributes converts a prompt string to GenAI semantic convention attributes
@@ -269,8 +304,15 @@ func (t *OTELLangfuseTracer) TraceGeneration(ctx context.Context, modelName stri
 	// Get span name from agent context or use default
 	spanName := GetSpanNameOrDefault(ctx, "llm.generation")
 
+	// Estimate token usage before anonymization changes the content length
+	promptLen, responseLen := len(prompt), len(response)
+
+	// Mask sensitive content before it leaves the process
+	prompt = t.anonymize(ctx, prompt)
+	response = t.anonymize(ctx, response)
+
 	// Check for tool calls from context
-	toolCalls := GetToolCallsFromContext(ctx)
+	toolCalls := t.anonymizeToolCalls(ctx, GetToolCallsFromContext(ctx))
 
 	var outputWithToolCalls string
 	if len(toolCalls) > 0 {
@@ -312,9 +354,9 @@ func (t *OTELLangfuseTracer) TraceGeneration(ctx context.Context, modelName stri
 		attribute.String("langfuse.observation.output", outputWithToolCalls),
 
 		// Token usage with proper GenAI attributes (based on last user message only)
-		attribute.Int64("gen_ai.usage.prompt_tokens", int64(len(prompt)/4)), // Rough estimate
-		attribute.Int64("gen_ai.usage.completion_tokens", int64(len(response)/4)),
-		attribute.Int64("gen_ai.usage.total_tokens", int64((len(prompt)+len(response))/4)),
+		attribute.Int64("gen_ai.usage.prompt_tokens", int64(promptLen/4)), // Rough estimate
+		attribute.Int64("gen_ai.usage.completion_tokens", int64(responseLen/4)),
+		attribute.Int64("gen_ai.usage.total_tokens", int64((promptLen+responseLen)/4)),
 	}
 
 	// Add organization ID if available
@@ -372,7 +414,7 @@ func (t *OTELLangfuseTracer) TraceGeneration(ctx context.Context, modelName stri
 
 	// Add metadata as span attributes using proper Langfuse namespace
 	for k, v := range metadata {
-		span.SetAttributes(attribute.String("langfuse.observation.metadata."+k, fmt.Sprintf("%v", v)))
+		span.SetAttributes(attribute.String("langfuse.observation.metadata."+k, t.anonymize(ctx, fmt.Sprintf("%v", v))))
 	}
 
 	return span.SpanContext().SpanID().String(), nil
@@ -552,7 +594,7 @@ func (t *OTELLangfuseTracer) TraceSpan(ctx context.Context, name string, startTi
 
 	// Add metadata as span attributes
 	for k, v := range metadata {
-		span.SetAttributes(attribute.String(k, fmt.Sprintf("%v", v)))
+		span.SetAttributes(attribute.String(k, t.anonymize(ctx, fmt.Sprintf("%v", v))))
 	}
 
 	return span.SpanContext().SpanID().String(), nil
@@ -591,7 +633,7 @@ func (t *OTELLangfuseTracer) TraceEvent(ctx context.Context, name string, input
 
 	// Add trace-level input/output if provided
 	if input != nil {
-		inputStr := fmt.Sprintf("%v", input)
+		inputStr := t.anonymize(ctx, fmt.Sprintf("%v", input))
 		span.SetAttributes(
 			attribute.String("langfuse.trace.input", inputStr),
 			attribute.String("langfuse.observation.input", inputStr),
@@ -602,7 +644,7 @@ func (t *OTELLangfuseTracer) TraceEvent(ctx context.Context, name string, input
 	}
 
 	if output != nil {
-		outputStr := fmt.Sprintf("%v", output)
+		outputStr := t.anonymize(ctx, fmt.Sprintf("%v", output))
 		span.SetAttributes(
 			attribute.String("langfuse.trace.output", outputStr),
 			attribute.String("langfuse.observation.output", outputStr),
@@ -614,7 +656,7 @@ func (t *OTELLangfuseTracer) TraceEvent(ctx context.Context, name string, input
 
 	// Add metadata as span attributes
 	for k, v := range metadata {
-		span.SetAttributes(attribute.String(k, fmt.Sprintf("%v", v)))
+		span.SetAttributes(attribute.String(k, t.anonymize(ctx, fmt.Sprintf("%v", v))))
 	}
 
 	return span.SpanContext().SpanID().String(), nil
@@ -661,7 +703,7 @@ func (t *OTELLangfuseTracer) StartTraceSession(ctx context.Context, contextID st
 	ctx = WithRequestID(ctx, contextID)
 
 	// Return wrapped span
-	return ctx, &OTELLangfuseSpan{span: span}
+	return ctx, &OTELLangfuseSpan{span: span, ctx: ctx, anonymizer: t.config.Anonymizer}
 }
 
 // Flush flushes the OTEL tracer provider
@@ -2,7 +2,14 @@ package tracing
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/anonymizer"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestExtractLastUserMessage(t *testing.T) {
@@ -127,3 +134,59 @@ func TestAgentNameFlow(t *testing.T) {
 		t.Errorf("Expected agent name 'TestAgent' after operations, got '%s'", agentName2)
 	}
 }
+
+func TestOTELLangfuseTracerAnonymizer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	policy := anonymizer.NewOrgPolicyAnonymizer(nil)
+	policy.SetPolicy("strict-org", anonymizer.NewPIIRedactor())
+
+	tracer := &OTELLangfuseTracer{
+		tracer:  provider.Tracer("test"),
+		enabled: true,
+		config:  LangfuseConfig{Anonymizer: policy},
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "strict-org")
+	ctx = WithToolCallsCollection(ctx)
+	AddToolCallToContext(ctx, ToolCall{Name: "lookup", Arguments: `{"email":"jane@example.com"}`, Result: "found"})
+
+	_, err := tracer.TraceGeneration(ctx, "model", "Email jane@example.com", "Sent to jane@example.com", time.Now(), time.Now(),
+		map[string]interface{}{"contact": "jane@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, span := tracer.StartSpan(ctx, "agent.Run")
+	span.SetAttribute("input", "jane@example.com")
+	span.End()
+
+	_, err = tracer.TraceGeneration(multitenancy.WithOrgID(context.Background(), "other-org"), "model", "Email jane@example.com", "ok", time.Now(), time.Now(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(spans))
+	}
+
+	for _, span := range spans[:3] {
+		for _, attr := range span.Attributes() {
+			if strings.Contains(attr.Value.Emit(), "jane@example.com") {
+				t.Errorf("span %s leaked sensitive content in %s: %s", span.Name(), attr.Key, attr.Value.Emit())
+			}
+		}
+	}
+
+	var unmasked bool
+	for _, attr := range spans[3].Attributes() {
+		if attr.Key == "langfuse.observation.input" && attr.Value.AsString() == "Email jane@example.com" {
+			unmasked = true
+		}
+	}
+	if !unmasked {
+		t.Error("expected org without policy to be traced as is")
+	}
+}
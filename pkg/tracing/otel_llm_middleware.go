@@ -8,14 +8,25 @@ import (
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
 
+// GenerationTracer records LLM generations and events. It is implemented by OTELLangfuseTracer,
+// LangfuseTracer and JSONTracer.
+type GenerationTracer interface {
+	// TraceGeneration records an LLM generation and the tool calls collected in ctx
+	TraceGeneration(ctx context.Context, modelName string, prompt string, response string, startTime time.Time, endTime time.Time, metadata map[string]interface{}) (string, error)
+
+	// TraceEvent records an event such as a failed LLM call
+	TraceEvent(ctx context.Context, name string, input interface{}, output interface{}, level string, metadata map[string]interface{}, parentID string) (string, error)
+}
+
 // OTELLLMMiddleware implements middleware for LLM calls with OTEL-based Langfuse tracing
 type OTELLLMMiddleware struct {
 	llm    interfaces.LLM
-	tracer *OTELLangfuseTracer
+	tracer GenerationTracer
 }
 
-// NewOTELLLMMiddleware creates a new LLM middleware with OTEL-based Langfuse tracing
-func NewOTELLLMMiddleware(llm interfaces.LLM, tracer *OTELLangfuseTracer) *OTELLLMMiddleware {
+// NewOTELLLMMiddleware creates a new LLM middleware with OTEL-based Langfuse tracing, or with
+// any other GenerationTracer such as a JSONTracer
+func NewOTELLLMMiddleware(llm interfaces.LLM, tracer GenerationTracer) *OTELLLMMiddleware {
 	return &OTELLLMMiddleware{
 		llm:    llm,
 		tracer: tracer,
@@ -0,0 +1,625 @@
+package pgvector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lib/pq"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/embedding"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+const (
+	// DistanceCosine ranks documents by cosine similarity
+	DistanceCosine = "cosine"
+	// DistanceInnerProduct ranks documents by inner product, for normalized vectors
+	DistanceInnerProduct = "inner_product"
+)
+
+// tableNamePattern restricts table names to plain, optionally schema qualified, identifiers as
+// they are interpolated in the queries
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Store implements interfaces.VectorStore on PostgreSQL with the pgvector extension. Documents
+// are stored in a table with their content, embedding and JSONB metadata, keyed by tenant and
+// ID. The tenant is the tenant of the options, or the organization in context.
+type Store struct {
+	db             *sql.DB
+	table          string
+	embedder       embedding.Client
+	distanceMetric string
+	logger         logging.Logger
+
+	// dimensions is the length of the vectors in the store, 0 until known
+	dimensionsMu sync.Mutex
+	dimensions   int
+}
+
+// Option represents an option for configuring the store
+type Option func(*Store)
+
+// WithTable sets the default table of the documents, used when no class is given
+func WithTable(table string) Option {
+	return func(s *Store) {
+		s.table = table
+	}
+}
+
+// WithEmbedder sets the embedder generating the vectors of documents and queries
+func WithEmbedder(embedder embedding.Client) Option {
+	return func(s *Store) {
+		s.embedder = embedder
+	}
+}
+
+// WithDistanceMetric sets the similarity metric, DistanceCosine or DistanceInnerProduct
+func WithDistanceMetric(metric string) Option {
+	return func(s *Store) {
+		s.distanceMetric = metric
+	}
+}
+
+// WithDimensions sets the length of the vectors of the store, required by EnsureSchema
+func WithDimensions(dimensions int) Option {
+	return func(s *Store) {
+		s.dimensions = dimensions
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger logging.Logger) Option {
+	return func(s *Store) {
+		s.logger = logger
+	}
+}
+
+// New creates a new pgvector store on a PostgreSQL connection, e.g. opened with
+// sql.Open("postgres", dsn)
+func New(db *sql.DB, options ...Option) *Store {
+	store := &Store{
+		db:             db,
+		table:          "documents",
+		distanceMetric: DistanceCosine,
+		logger:         logging.New(),
+	}
+
+	for _, option := range options {
+		option(store)
+	}
+
+	return store
+}
+
+// Dimensions returns the length of the vectors in the store, or 0 if not yet known
+func (s *Store) Dimensions() int {
+	s.dimensionsMu.Lock()
+	defer s.dimensionsMu.Unlock()
+	return s.dimensions
+}
+
+// checkDimensions records the length of the first vector and rejects vectors of any other
+// length, which could not be compared with the stored ones
+func (s *Store) checkDimensions(vector []float32) error {
+	s.dimensionsMu.Lock()
+	defer s.dimensionsMu.Unlock()
+
+	if s.dimensions == 0 {
+		s.dimensions = len(vector)
+		return nil
+	}
+	if len(vector) != s.dimensions {
+		return fmt.Errorf("vector has %d dimensions, store expects %d", len(vector), s.dimensions)
+	}
+	return nil
+}
+
+// EnsureSchema creates the pgvector extension, the table of the documents and the index of the
+// vectors for the distance metric if they do not exist. The table is the given class, or the
+// default table if empty. The dimensions must be set with WithDimensions.
+func (s *Store) EnsureSchema(ctx context.Context, class string) error {
+	table, err := s.getTableName(class)
+	if err != nil {
+		return err
+	}
+	operatorClass, err := s.operatorClass()
+	if err != nil {
+		return err
+	}
+	dimensions := s.Dimensions()
+	if dimensions <= 0 {
+		return fmt.Errorf("dimensions are required to create the schema, see WithDimensions")
+	}
+
+	indexName := strings.ReplaceAll(table, ".", "_") + "_embedding_idx"
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS vector`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			tenant TEXT NOT NULL DEFAULT '',
+			id TEXT NOT NULL,
+			content TEXT NOT NULL,
+			embedding vector(%d) NOT NULL,
+			metadata JSONB NOT NULL DEFAULT '{}',
+			PRIMARY KEY (tenant, id)
+		)`, table, dimensions),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING hnsw (embedding %s)`, indexName, table, operatorClass),
+	}
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create schema: %w", err)
+		}
+	}
+
+	s.logger.Info(ctx, "Ensured pgvector schema", map[string]interface{}{
+		"table":      table,
+		"dimensions": dimensions,
+		"distance":   s.distanceMetric,
+	})
+	return nil
+}
+
+// Store upserts documents by ID, generating their vectors with the embedder unless set
+func (s *Store) Store(ctx context.Context, documents []interfaces.Document, options ...interfaces.StoreOption) error {
+	opts := &interfaces.StoreOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	table, err := s.getTableName(opts.Class)
+	if err != nil {
+		return err
+	}
+	tenant := s.getTenant(ctx, opts.Tenant)
+
+	query := fmt.Sprintf(`INSERT INTO %s (tenant, id, content, embedding, metadata)
+		VALUES ($1, $2, $3, $4::vector, $5::jsonb)
+		ON CONFLICT (tenant, id) DO UPDATE SET
+			content = EXCLUDED.content,
+			embedding = EXCLUDED.embedding,
+			metadata = EXCLUDED.metadata`, table)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, doc := range documents {
+		if doc.ID == "" {
+			return fmt.Errorf("document ID is required")
+		}
+
+		vector := doc.Vector
+		if vector == nil {
+			if s.embedder == nil {
+				return fmt.Errorf("embedder is required to generate the vector of document %s", doc.ID)
+			}
+			vector, err = s.embedder.Embed(ctx, doc.Content)
+			if err != nil {
+				return fmt.Errorf("failed to generate embedding: %w", err)
+			}
+		}
+		if err := s.checkDimensions(vector); err != nil {
+			return err
+		}
+
+		metadata := doc.Metadata
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		metadataJSON, err := json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata of document %s: %w", doc.ID, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, tenant, doc.ID, doc.Content, vectorLiteral(vector), string(metadataJSON)); err != nil {
+			return fmt.Errorf("failed to store document %s: %w", doc.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit documents: %w", err)
+	}
+	return nil
+}
+
+// Search searches for documents similar to the query, embedded with the embedder
+func (s *Store) Search(ctx context.Context, query string, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	opts := &interfaces.SearchOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	if opts.UseBM25 || opts.UseHybrid {
+		return nil, fmt.Errorf("keyword and hybrid search are not supported by the pgvector store")
+	}
+	if s.embedder == nil {
+		return nil, fmt.Errorf("embedder is required to search by text")
+	}
+
+	vector, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding for query: %w", err)
+	}
+	return s.SearchByVector(ctx, vector, limit, options...)
+}
+
+// SearchByVector searches for documents similar to the vector
+func (s *Store) SearchByVector(ctx context.Context, vector []float32, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	if err := s.checkDimensions(vector); err != nil {
+		return nil, err
+	}
+
+	opts := &interfaces.SearchOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	table, err := s.getTableName(opts.Class)
+	if err != nil {
+		return nil, err
+	}
+	distanceOperator, err := s.distanceOperator()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{vectorLiteral(vector), s.getTenant(ctx, opts.Tenant)}
+	where := "tenant = $2"
+	filterClause, args, err := buildFilterClause(opts.Filters, args)
+	if err != nil {
+		return nil, err
+	}
+	if filterClause != "" {
+		where += " AND " + filterClause
+	}
+
+	query := fmt.Sprintf(`SELECT id, content, metadata, embedding %s $1::vector AS distance
+		FROM %s WHERE %s ORDER BY distance`, distanceOperator, table, where)
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search documents: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results := []interfaces.SearchResult{}
+	for rows.Next() {
+		var (
+			doc      interfaces.Document
+			metadata []byte
+			distance float64
+		)
+		if err := rows.Scan(&doc.ID, &doc.Content, &metadata, &distance); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		if err := json.Unmarshal(metadata, &doc.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata of document %s: %w", doc.ID, err)
+		}
+
+		score := s.score(distance)
+		if score < opts.MinScore {
+			continue
+		}
+		results = append(results, interfaces.SearchResult{Document: doc, Score: score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search results: %w", err)
+	}
+
+	return results, nil
+}
+
+// Delete removes documents by ID
+func (s *Store) Delete(ctx context.Context, ids []string, options ...interfaces.DeleteOption) error {
+	opts := &interfaces.DeleteOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	table, err := s.getTableName(opts.Class)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE tenant = $1 AND id = ANY($2)`, table)
+	if _, err := s.db.ExecContext(ctx, query, s.getTenant(ctx, opts.Tenant), pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to delete documents: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a single document by ID
+func (s *Store) Get(ctx context.Context, id string, options ...interfaces.StoreOption) (*interfaces.Document, error) {
+	opts := &interfaces.StoreOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	table, err := s.getTableName(opts.Class)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		doc      = interfaces.Document{ID: id}
+		metadata []byte
+		vector   string
+	)
+	query := fmt.Sprintf(`SELECT content, metadata, embedding::text FROM %s WHERE tenant = $1 AND id = $2`, table)
+	err = s.db.QueryRowContext(ctx, query, s.getTenant(ctx, opts.Tenant), id).Scan(&doc.Content, &metadata, &vector)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("document %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document %s: %w", id, err)
+	}
+
+	if err := json.Unmarshal(metadata, &doc.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata of document %s: %w", id, err)
+	}
+	if doc.Vector, err = parseVector(vector); err != nil {
+		return nil, fmt.Errorf("failed to parse vector of document %s: %w", id, err)
+	}
+
+	return &doc, nil
+}
+
+// GlobalStore stores documents without tenant context (for shared data)
+func (s *Store) GlobalStore(ctx context.Context, documents []interfaces.Document, options ...interfaces.StoreOption) error {
+	return s.Store(context.Background(), documents, options...)
+}
+
+// GlobalSearch searches for documents without tenant context (for shared data)
+func (s *Store) GlobalSearch(ctx context.Context, query string, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	return s.Search(context.Background(), query, limit, options...)
+}
+
+// GlobalSearchByVector searches for documents by vector without tenant context (for shared data)
+func (s *Store) GlobalSearchByVector(ctx context.Context, vector []float32, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	return s.SearchByVector(context.Background(), vector, limit, options...)
+}
+
+// GlobalDelete deletes documents without tenant context (for shared data)
+func (s *Store) GlobalDelete(ctx context.Context, ids []string, options ...interfaces.DeleteOption) error {
+	return s.Delete(context.Background(), ids, options...)
+}
+
+// CreateTenant does nothing, as tenants are created with their first document
+func (s *Store) CreateTenant(ctx context.Context, tenantName string) error {
+	if tenantName == "" {
+		return fmt.Errorf("tenant name is required")
+	}
+	return nil
+}
+
+// DeleteTenant deletes all documents of a tenant from the default table
+func (s *Store) DeleteTenant(ctx context.Context, tenantName string) error {
+	if tenantName == "" {
+		return fmt.Errorf("tenant name is required")
+	}
+
+	table, err := s.getTableName("")
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE tenant = $1`, table)
+	if _, err := s.db.ExecContext(ctx, query, tenantName); err != nil {
+		return fmt.Errorf("failed to delete tenant %s: %w", tenantName, err)
+	}
+	return nil
+}
+
+// ListTenants lists the tenants having documents in the default table
+func (s *Store) ListTenants(ctx context.Context) ([]string, error) {
+	table, err := s.getTableName("")
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`SELECT DISTINCT tenant FROM %s WHERE tenant <> '' ORDER BY tenant`, table)
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tenants []string
+	for rows.Next() {
+		var tenant string
+		if err := rows.Scan(&tenant); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// Helper functions
+
+// getTableName returns the table of the class, or the default table
+func (s *Store) getTableName(class string) (string, error) {
+	if class == "" {
+		class = s.table
+	}
+	if !tableNamePattern.MatchString(class) {
+		return "", fmt.Errorf("invalid table name: %q", class)
+	}
+	return class, nil
+}
+
+// getTenant returns the tenant of the options, or the organization in context, or "" for
+// shared data
+func (s *Store) getTenant(ctx context.Context, tenant string) string {
+	if tenant != "" {
+		return tenant
+	}
+	orgID, _ := multitenancy.GetOrgID(ctx)
+	return orgID
+}
+
+// distanceOperator returns the pgvector operator of the distance metric
+func (s *Store) distanceOperator() (string, error) {
+	switch s.distanceMetric {
+	case DistanceCosine:
+		return "<=>", nil
+	case DistanceInnerProduct:
+		return "<#>", nil
+	default:
+		return "", fmt.Errorf("unsupported distance metric: %s", s.distanceMetric)
+	}
+}
+
+// operatorClass returns the index operator class of the distance metric
+func (s *Store) operatorClass() (string, error) {
+	switch s.distanceMetric {
+	case DistanceCosine:
+		return "vector_cosine_ops", nil
+	case DistanceInnerProduct:
+		return "vector_ip_ops", nil
+	default:
+		return "", fmt.Errorf("unsupported distance metric: %s", s.distanceMetric)
+	}
+}
+
+// score converts a pgvector distance into a similarity score between 0 and 1. The cosine
+// distance ranges from 0 to 2 and the inner product operator returns the negated inner product,
+// between -1 and 1 for normalized vectors.
+func (s *Store) score(distance float64) float32 {
+	var score float64
+	if s.distanceMetric == DistanceInnerProduct {
+		score = (1 - distance) / 2
+	} else {
+		score = 1 - distance/2
+	}
+	return float32(min(max(score, 0), 1))
+}
+
+// buildFilterClause translates metadata filters into a SQL condition on the metadata column,
+// appending its parameters to args. A filter maps a metadata key to a value, matched with
+// equality, a list of values, matched with any of them, or a map with an "operator" (equals,
+// notEquals, greaterThan, greaterThanEqual, lessThan, lessThanEqual, like, contains or in) and a
+// "value".
+func buildFilterClause(filters map[string]interface{}, args []interface{}) (string, []interface{}, error) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var conditions []string
+	for _, key := range keys {
+		args = append(args, key)
+		field := fmt.Sprintf("metadata->>$%d", len(args))
+
+		operator, value := "equals", filters[key]
+		if condition, ok := value.(map[string]interface{}); ok {
+			operator, _ = condition["operator"].(string)
+			value = condition["value"]
+		} else if _, ok := value.([]interface{}); ok {
+			operator = "in"
+		} else if _, ok := value.([]string); ok {
+			operator = "in"
+		}
+
+		switch operator {
+		case "equals":
+			if text, ok := value.(string); ok {
+				args = append(args, text)
+				conditions = append(conditions, fmt.Sprintf("%s = $%d", field, len(args)))
+			} else {
+				// Match numbers and booleans with their JSON type
+				containment, err := json.Marshal(map[string]interface{}{key: value})
+				if err != nil {
+					return "", nil, fmt.Errorf("invalid value of filter %s: %w", key, err)
+				}
+				args = append(args, string(containment))
+				conditions = append(conditions, fmt.Sprintf("metadata @> $%d::jsonb", len(args)))
+			}
+		case "notEquals":
+			args = append(args, fmt.Sprint(value))
+			conditions = append(conditions, fmt.Sprintf("%s IS DISTINCT FROM $%d", field, len(args)))
+		case "greaterThan", "greaterThanEqual", "lessThan", "lessThanEqual":
+			number, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+			if err != nil {
+				return "", nil, fmt.Errorf("filter %s requires a number, got %v", key, value)
+			}
+			comparison := map[string]string{"greaterThan": ">", "greaterThanEqual": ">=", "lessThan": "<", "lessThanEqual": "<="}[operator]
+			args = append(args, number)
+			conditions = append(conditions, fmt.Sprintf("(%s)::numeric %s $%d", field, comparison, len(args)))
+		case "like":
+			args = append(args, fmt.Sprint(value))
+			conditions = append(conditions, fmt.Sprintf("%s LIKE $%d", field, len(args)))
+		case "contains":
+			args = append(args, "%"+fmt.Sprint(value)+"%")
+			conditions = append(conditions, fmt.Sprintf("%s LIKE $%d", field, len(args)))
+		case "in":
+			var values []string
+			switch v := value.(type) {
+			case []string:
+				values = v
+			case []interface{}:
+				for _, item := range v {
+					values = append(values, fmt.Sprint(item))
+				}
+			default:
+				return "", nil, fmt.Errorf("filter %s requires a list of values, got %v", key, value)
+			}
+			args = append(args, pq.Array(values))
+			conditions = append(conditions, fmt.Sprintf("%s = ANY($%d)", field, len(args)))
+		default:
+			return "", nil, fmt.Errorf("unsupported operator %q in filter %s", operator, key)
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// vectorLiteral formats a vector in the text representation of pgvector, e.g. [1,2,3]
+func vectorLiteral(vector []float32) string {
+	values := make([]string, len(vector))
+	for i, value := range vector {
+		values[i] = strconv.FormatFloat(float64(value), 'f', -1, 32)
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+// parseVector parses the text representation of a pgvector vector
+func parseVector(text string) ([]float32, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[") || !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("invalid vector: %q", text)
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "["), "]")
+	if text == "" {
+		return []float32{}, nil
+	}
+
+	parts := strings.Split(text, ",")
+	vector := make([]float32, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector value %q: %w", part, err)
+		}
+		vector[i] = float32(value)
+	}
+	return vector, nil
+}
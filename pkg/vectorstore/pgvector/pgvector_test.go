@@ -0,0 +1,155 @@
+package pgvector
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestBuildFilterClause(t *testing.T) {
+	args := []interface{}{"[1,2]", "org-1"}
+	clause, args, err := buildFilterClause(map[string]interface{}{
+		"conversation_id": "conv-1",
+		"page":            3,
+		"year":            map[string]interface{}{"operator": "greaterThanEqual", "value": 2020},
+		"lang":            []interface{}{"en", "fr"},
+		"title":           map[string]interface{}{"operator": "contains", "value": "report"},
+	}, args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedClause := "metadata->>$3 = $4" +
+		" AND metadata->>$5 = ANY($6)" +
+		" AND metadata @> $8::jsonb" +
+		" AND metadata->>$9 LIKE $10" +
+		" AND (metadata->>$11)::numeric >= $12"
+	if clause != expectedClause {
+		t.Errorf("unexpected clause:\n got %s\nwant %s", clause, expectedClause)
+	}
+
+	expectedArgs := []interface{}{
+		"[1,2]", "org-1",
+		"conversation_id", "conv-1",
+		"lang", pq.Array([]string{"en", "fr"}),
+		"page", `{"page":3}`,
+		"title", "%report%",
+		"year", float64(2020),
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("unexpected args:\n got %#v\nwant %#v", args, expectedArgs)
+	}
+}
+
+func TestBuildFilterClauseErrors(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"unknown operator": {"year": map[string]interface{}{"operator": "between", "value": 1}},
+		"not a number":     {"year": map[string]interface{}{"operator": "lessThan", "value": "recent"}},
+		"not a list":       {"lang": map[string]interface{}{"operator": "in", "value": "en"}},
+	}
+	for name, filters := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := buildFilterClause(filters, nil); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	clause, args, err := buildFilterClause(nil, nil)
+	if err != nil || clause != "" || len(args) != 0 {
+		t.Errorf("expected no condition without filters, got %q, %v, %v", clause, args, err)
+	}
+}
+
+func TestVectorLiteral(t *testing.T) {
+	vector := []float32{0.1, -2, 3.5}
+	literal := vectorLiteral(vector)
+	if literal != "[0.1,-2,3.5]" {
+		t.Errorf("unexpected literal: %s", literal)
+	}
+
+	parsed, err := parseVector(literal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, vector) {
+		t.Errorf("expected %v, got %v", vector, parsed)
+	}
+
+	if _, err := parseVector("0.1,0.2"); err == nil {
+		t.Error("expected an error for a vector without brackets")
+	}
+}
+
+func TestScore(t *testing.T) {
+	cosine := New(nil)
+	if score := cosine.score(0); score != 1 {
+		t.Errorf("expected identical vectors to score 1, got %v", score)
+	}
+	if score := cosine.score(2); score != 0 {
+		t.Errorf("expected opposite vectors to score 0, got %v", score)
+	}
+
+	innerProduct := New(nil, WithDistanceMetric(DistanceInnerProduct))
+	if score := innerProduct.score(-1); score != 1 {
+		t.Errorf("expected an inner product of 1 to score 1, got %v", score)
+	}
+	if score := innerProduct.score(1); score != 0 {
+		t.Errorf("expected an inner product of -1 to score 0, got %v", score)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	ctx := context.Background()
+
+	store := New(nil, WithTable("docs; DROP TABLE users"))
+	if err := store.Store(ctx, []interfaces.Document{{ID: "1", Vector: []float32{1}}}); err == nil || !strings.Contains(err.Error(), "invalid table name") {
+		t.Errorf("expected an invalid table name error, got %v", err)
+	}
+	if err := store.DeleteTenant(ctx, "org-1"); err == nil || !strings.Contains(err.Error(), "invalid table name") {
+		t.Errorf("expected an invalid table name error, got %v", err)
+	}
+	if _, err := store.ListTenants(ctx); err == nil || !strings.Contains(err.Error(), "invalid table name") {
+		t.Errorf("expected an invalid table name error, got %v", err)
+	}
+
+	if err := New(nil).EnsureSchema(ctx, ""); err == nil || !strings.Contains(err.Error(), "dimensions") {
+		t.Errorf("expected a dimensions error, got %v", err)
+	}
+	if err := New(nil, WithDimensions(3), WithDistanceMetric("euclidean")).EnsureSchema(ctx, ""); err == nil {
+		t.Error("expected an unsupported distance metric error")
+	}
+
+	if _, err := New(nil).Search(ctx, "query", 5); err == nil || !strings.Contains(err.Error(), "embedder") {
+		t.Errorf("expected an embedder error, got %v", err)
+	}
+	if _, err := New(nil).Search(ctx, "query", 5, interfaces.WithBM25(true)); err == nil {
+		t.Error("expected keyword search to be rejected")
+	}
+
+	dimensioned := New(nil, WithDimensions(3))
+	if _, err := dimensioned.SearchByVector(ctx, []float32{1, 2}, 5); err == nil {
+		t.Error("expected a dimensions mismatch error")
+	}
+}
+
+func TestGetTenant(t *testing.T) {
+	store := New(nil)
+	ctx := multitenancy.WithOrgID(context.Background(), "org-1")
+
+	if tenant := store.getTenant(ctx, ""); tenant != "org-1" {
+		t.Errorf("expected the organization in context, got %q", tenant)
+	}
+	if tenant := store.getTenant(ctx, "tenant-1"); tenant != "tenant-1" {
+		t.Errorf("expected the tenant of the options, got %q", tenant)
+	}
+	if tenant := store.getTenant(context.Background(), ""); tenant != "" {
+		t.Errorf("expected no tenant for shared data, got %q", tenant)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package weaviate_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	weaviatestore "github.com/Ingenimax/agent-sdk-go/pkg/vectorstore/weaviate"
+)
+
+// TestKeywordSearchIntegration tests keyword search against a real Weaviate instance
+// Run with: WEAVIATE_HOST=localhost:8080 go test -tags=integration ./pkg/vectorstore/weaviate -run TestKeywordSearchIntegration
+func TestKeywordSearchIntegration(t *testing.T) {
+	host := os.Getenv("WEAVIATE_HOST")
+	if host == "" {
+		t.Skip("WEAVIATE_HOST not set, skipping integration test")
+	}
+
+	embedder := &countingEmbedder{}
+	store := weaviatestore.New(&interfaces.VectorStoreConfig{
+		Host:   host,
+		Scheme: "http",
+		APIKey: os.Getenv("WEAVIATE_API_KEY"),
+	}, weaviatestore.WithClassPrefix("KeywordSearchTest"), weaviatestore.WithEmbedder(embedder))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "keyword-search-test")
+	docs := []interfaces.Document{
+		{ID: "5f0c1a4e-0000-4000-8000-000000000001", Content: "Error E-4012: the disk is full"},
+		{ID: "5f0c1a4e-0000-4000-8000-000000000002", Content: "Error E-5001: the network is unreachable"},
+		{ID: "5f0c1a4e-0000-4000-8000-000000000003", Content: "SKU AB-778 is out of stock"},
+	}
+	if err := store.Store(ctx, docs); err != nil {
+		t.Fatalf("Failed to store documents: %v", err)
+	}
+	defer func() {
+		_ = store.Delete(ctx, []string{docs[0].ID, docs[1].ID, docs[2].ID})
+	}()
+
+	embedded := embedder.calls
+	results, err := store.Search(ctx, "E-4012", 5, interfaces.WithKeywordSearch([]string{"content"}))
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+
+	if embedder.calls != embedded {
+		t.Errorf("Expected keyword search to skip the embedder, got %d calls", embedder.calls-embedded)
+	}
+	if len(results) == 0 || !strings.Contains(results[0].Document.Content, "E-4012") {
+		t.Fatalf("Expected the exact token match first, got %+v", results)
+	}
+	for _, result := range results {
+		if strings.Contains(result.Document.Content, "AB-778") {
+			t.Errorf("Expected no match without a shared token, got %q", result.Document.Content)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/auth"
@@ -17,6 +18,13 @@ import (
 	"github.com/go-openapi/strfmt"
 )
 
+const (
+	// vectorAdditionalFields are the metadata fields of vector search results
+	vectorAdditionalFields = "_additional { certainty id }"
+	// hybridAdditionalFields are the metadata fields of hybrid and keyword search results, scored by fusion or BM25
+	hybridAdditionalFields = "_additional { score id }"
+)
+
 // Store implements the VectorStore interface for Weaviate
 type Store struct {
 	client         *weaviate.Client
@@ -24,6 +32,10 @@ type Store struct {
 	embedder       embedding.Client
 	distanceMetric string
 	logger         logging.Logger
+
+	// dimensionsMu guards dimensions, the length of the vectors in the store
+	dimensionsMu sync.Mutex
+	dimensions   int
 }
 
 // Option represents an option for configuring the Weaviate store
@@ -50,6 +62,15 @@ func WithDistanceMetric(metric string) Option {
 	}
 }
 
+// WithDimensions sets the expected length of the vectors in the Weaviate store, such as the
+// dimensions requested with embedding.WithDimensions. Without it the length of the first
+// vector stored or searched is recorded.
+func WithDimensions(dimensions int) Option {
+	return func(s *Store) {
+		s.dimensions = dimensions
+	}
+}
+
 // WithLogger sets the logger for the Weaviate store
 func WithLogger(logger logging.Logger) Option {
 	return func(s *Store) {
@@ -93,6 +114,29 @@ func New(config *interfaces.VectorStoreConfig, options ...Option) *Store {
 	return store
 }
 
+// Dimensions returns the length of the vectors in the store, or 0 if not yet known
+func (s *Store) Dimensions() int {
+	s.dimensionsMu.Lock()
+	defer s.dimensionsMu.Unlock()
+	return s.dimensions
+}
+
+// checkDimensions records the length of the first vector and rejects vectors of any other
+// length, which could not be compared with the stored ones
+func (s *Store) checkDimensions(vector []float32) error {
+	s.dimensionsMu.Lock()
+	defer s.dimensionsMu.Unlock()
+
+	if s.dimensions == 0 {
+		s.dimensions = len(vector)
+		return nil
+	}
+	if len(vector) != s.dimensions {
+		return fmt.Errorf("vector has %d dimensions, store expects %d", len(vector), s.dimensions)
+	}
+	return nil
+}
+
 // getClassName returns the class name
 // Uses metadata-based multi-tenancy (single class, orgId as field) instead of class proliferation
 func (s *Store) getClassName(ctx context.Context, class string) (string, error) {
@@ -136,6 +180,9 @@ func (s *Store) Store(ctx context.Context, documents []interfaces.Document, opti
 		if err != nil {
 			return fmt.Errorf("failed to generate embedding: %w", err)
 		}
+		if err := s.checkDimensions(vector); err != nil {
+			return err
+		}
 
 		properties := map[string]interface{}{
 			"content": doc.Content,
@@ -190,16 +237,29 @@ func (s *Store) Search(ctx context.Context, query string, limit int, options ...
 		option(opts)
 	}
 
+	if opts.UseHybrid && (opts.HybridAlpha < 0 || opts.HybridAlpha > 1) {
+		return nil, fmt.Errorf("hybrid search alpha must be between 0 and 1, got %v", opts.HybridAlpha)
+	}
+	if opts.UseHybrid && opts.UseBM25 {
+		return nil, fmt.Errorf("hybrid search and keyword search cannot be combined")
+	}
+
 	// Get class name
 	className, err := s.getClassName(ctx, opts.Class)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate embedding for the query
-	vector, err := s.embedder.Embed(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate embedding for query: %w", err)
+	// Generate embedding for the query, unless searching by keyword only
+	var vector []float32
+	if !opts.UseBM25 {
+		vector, err = s.embedder.Embed(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embedding for query: %w", err)
+		}
+		if err := s.checkDimensions(vector); err != nil {
+			return nil, err
+		}
 	}
 
 	// Build query
@@ -220,10 +280,18 @@ func (s *Store) Search(ctx context.Context, query string, limit int, options ...
 		"className": className,
 		"limit":     limit,
 		"query":     query,
+		"hybrid":    opts.UseHybrid,
+		"keyword":   opts.UseBM25,
 	})
 
+	// Hybrid and keyword search report a score instead of the certainty
+	additionalFields := vectorAdditionalFields
+	if opts.UseHybrid || opts.UseBM25 {
+		additionalFields = hybridAdditionalFields
+	}
+
 	// Build dynamic field list
-	fieldList, err := s.buildFieldList(ctx, className, opts.Fields)
+	fieldList, err := s.buildFieldList(ctx, className, opts.Fields, additionalFields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build field list: %w", err)
 	}
@@ -239,10 +307,24 @@ func (s *Store) Search(ctx context.Context, query string, limit int, options ...
 		WithFields(graphql.Field{
 			Name: fieldList,
 		}).
-		WithNearVector(s.client.GraphQL().NearVectorArgBuilder().
-			WithVector(vector)).
 		WithLimit(limit)
 
+	if opts.UseHybrid {
+		queryBuilder = queryBuilder.WithHybrid(s.client.GraphQL().HybridArgumentBuilder().
+			WithQuery(query).
+			WithVector(vector).
+			WithAlpha(float32(opts.HybridAlpha)))
+	} else if opts.UseBM25 {
+		bm25 := s.client.GraphQL().Bm25ArgBuilder().WithQuery(query)
+		if len(opts.KeywordFields) > 0 {
+			bm25 = bm25.WithProperties(opts.KeywordFields...)
+		}
+		queryBuilder = queryBuilder.WithBM25(bm25)
+	} else {
+		queryBuilder = queryBuilder.WithNearVector(s.client.GraphQL().NearVectorArgBuilder().
+			WithVector(vector))
+	}
+
 	// Add where filter if specified
 	if whereFilter != nil {
 		queryBuilder = queryBuilder.WithWhere(whereFilter)
@@ -287,6 +369,10 @@ func (s *Store) Search(ctx context.Context, query string, limit int, options ...
 
 // SearchByVector searches for similar documents using a vector
 func (s *Store) SearchByVector(ctx context.Context, vector []float32, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	if err := s.checkDimensions(vector); err != nil {
+		return nil, err
+	}
+
 	// Apply options
 	opts := &interfaces.SearchOptions{
 		MinScore: 0.0,
@@ -305,7 +391,7 @@ func (s *Store) SearchByVector(ctx context.Context, vector []float32, limit int,
 	whereFilter := s.buildWhereFilter(opts.Filters)
 
 	// Build dynamic field list
-	fieldList, err := s.buildFieldList(ctx, className, opts.Fields)
+	fieldList, err := s.buildFieldList(ctx, className, opts.Fields, vectorAdditionalFields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build field list: %w", err)
 	}
@@ -536,9 +622,10 @@ func (s *Store) ListTenants(ctx context.Context) ([]string, error) {
 
 // Helper functions
 
-// buildFieldList constructs the GraphQL field specification for queries
+// buildFieldList constructs the GraphQL field specification for queries, ending with the given
+// _additional metadata fields
 // If fields are specified in options, uses those; otherwise discovers all fields from schema
-func (s *Store) buildFieldList(ctx context.Context, className string, fields []string) (string, error) {
+func (s *Store) buildFieldList(ctx context.Context, className string, fields []string, additionalFields string) (string, error) {
 	// If specific fields are requested, use them
 	if len(fields) > 0 {
 		fieldList := ""
@@ -549,7 +636,7 @@ func (s *Store) buildFieldList(ctx context.Context, className string, fields []s
 			fieldList += field
 		}
 		// Always include _additional metadata
-		fieldList += " _additional { certainty id }"
+		fieldList += " " + additionalFields
 		return fieldList, nil
 	}
 
@@ -561,7 +648,7 @@ func (s *Store) buildFieldList(ctx context.Context, className string, fields []s
 			"className": className,
 		})
 		// Fallback to basic fields if schema discovery fails
-		return "content _additional { certainty id }", nil
+		return "content " + additionalFields, nil
 	}
 
 	// Find the target class
@@ -578,7 +665,7 @@ func (s *Store) buildFieldList(ctx context.Context, className string, fields []s
 			"className": className,
 		})
 		// Fallback to basic fields if class not found
-		return "content _additional { certainty id }", nil
+		return "content " + additionalFields, nil
 	}
 
 	// Build field list from all properties
@@ -591,7 +678,7 @@ func (s *Store) buildFieldList(ctx context.Context, className string, fields []s
 	}
 
 	// Always include _additional metadata
-	fieldList += " _additional { certainty id }"
+	fieldList += " " + additionalFields
 
 	s.logger.Debug(ctx, "Built dynamic field list", map[string]interface{}{
 		"className":  className,
@@ -966,6 +1053,10 @@ func (s *Store) parseSearchResults(result *models.GraphQLResponse, className str
 		}
 
 		certainty, ok := additional["certainty"].(float64)
+		if score, found := additional["score"]; !ok && found && score != nil {
+			// Hybrid and keyword search report a score, as a string
+			certainty, ok = toFloat64(score), true
+		}
 		if !ok {
 			s.logger.Warn(context.Background(), "Missing certainty field in result", map[string]interface{}{
 				"additional": additional,
@@ -2,6 +2,10 @@ package weaviate_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/embedding"
@@ -122,3 +126,155 @@ func TestStore(t *testing.T) {
 		t.Errorf("Expected 0 results after deletion, got %d", len(results))
 	}
 }
+
+func TestDimensionsMismatch(t *testing.T) {
+	config := &interfaces.VectorStoreConfig{
+		Host:   "localhost:8080",
+		Scheme: "http",
+	}
+
+	store := weaviatestore.New(config,
+		weaviatestore.WithEmbedder(&MockEmbedder{}),
+		weaviatestore.WithDimensions(256),
+	)
+	if store.Dimensions() != 256 {
+		t.Fatalf("Expected 256 dimensions, got %d", store.Dimensions())
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+
+	// The mock embedder returns vectors of 3 dimensions, rejected before reaching Weaviate
+	if err := store.Store(ctx, []interfaces.Document{{ID: "doc1", Content: "test"}}); err == nil {
+		t.Error("Expected an error storing a vector of unexpected dimensions")
+	}
+	if _, err := store.Search(ctx, "test", 5); err == nil {
+		t.Error("Expected an error searching with a vector of unexpected dimensions")
+	}
+	if _, err := store.SearchByVector(ctx, []float32{0.1, 0.2}, 5); err == nil {
+		t.Error("Expected an error searching by a vector of unexpected dimensions")
+	}
+}
+
+func TestHybridSearch(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/graphql" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		query = body.Query
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"Get": {"Document": [
+			{"content": "Order ABC-123 shipped", "_additional": {"id": "doc1", "score": "0.9"}},
+			{"content": "Order ABD-124 pending", "_additional": {"id": "doc2", "score": "0.2"}}
+		]}}}`))
+	}))
+	defer server.Close()
+
+	config := &interfaces.VectorStoreConfig{
+		Host:   strings.TrimPrefix(server.URL, "http://"),
+		Scheme: "http",
+	}
+	store := weaviatestore.New(config, weaviatestore.WithEmbedder(&MockEmbedder{}))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	results, err := store.Search(ctx, "ABC-123", 5,
+		interfaces.WithHybridSearch(0.25),
+		interfaces.WithFilters(map[string]interface{}{"path": "source", "operator": "Equal", "valueString": "orders"}),
+		interfaces.WithFields("content"),
+		interfaces.WithMinScore(0.5),
+	)
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+
+	for _, expected := range []string{`hybrid:{query: "ABC-123"`, "alpha: 0.25", "limit: 5", "where:", "_additional { score id }"} {
+		if !strings.Contains(query, expected) {
+			t.Errorf("Expected the query to contain %q, got %s", expected, query)
+		}
+	}
+	if strings.Contains(query, "nearVector") {
+		t.Errorf("Expected no vector search in a hybrid query, got %s", query)
+	}
+
+	// The fused score is returned, and the minimum score still applies
+	if len(results) != 1 || results[0].Document.ID != "doc1" || results[0].Score != 0.9 {
+		t.Errorf("Expected doc1 with its fused score, got %+v", results)
+	}
+
+	if _, err := store.Search(ctx, "ABC-123", 5, interfaces.WithHybridSearch(1.5)); err == nil {
+		t.Error("Expected an error with an alpha outside [0, 1]")
+	}
+}
+
+// countingEmbedder counts the texts it embeds
+type countingEmbedder struct {
+	MockEmbedder
+	calls int
+}
+
+func (m *countingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	m.calls++
+	return m.MockEmbedder.Embed(ctx, text)
+}
+
+func TestKeywordSearch(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/graphql" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		query = body.Query
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"Get": {"Document": [
+			{"content": "Error E-4012: disk full", "_additional": {"id": "doc1", "score": "2.4"}}
+		]}}}`))
+	}))
+	defer server.Close()
+
+	embedder := &countingEmbedder{}
+	store := weaviatestore.New(&interfaces.VectorStoreConfig{
+		Host:   strings.TrimPrefix(server.URL, "http://"),
+		Scheme: "http",
+	}, weaviatestore.WithEmbedder(embedder))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "test-org")
+	results, err := store.Search(ctx, "E-4012", 5, interfaces.WithKeywordSearch([]string{"content", "title"}), interfaces.WithFields("content"))
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+
+	for _, expected := range []string{`bm25:{query: "E-4012"`, `properties: ["content","title"]`, "_additional { score id }"} {
+		if !strings.Contains(query, expected) {
+			t.Errorf("Expected the query to contain %q, got %s", expected, query)
+		}
+	}
+	if strings.Contains(query, "nearVector") {
+		t.Errorf("Expected no vector search in a keyword query, got %s", query)
+	}
+	if embedder.calls != 0 {
+		t.Errorf("Expected the query not to be embedded, got %d calls", embedder.calls)
+	}
+	if len(results) != 1 || results[0].Document.ID != "doc1" || results[0].Score != 2.4 {
+		t.Errorf("Expected doc1 with its BM25 score, got %+v", results)
+	}
+
+	if _, err := store.Search(ctx, "E-4012", 5, interfaces.WithKeywordSearch(nil), interfaces.WithHybridSearch(0.5)); err == nil {
+		t.Error("Expected an error combining keyword and hybrid search")
+	}
+}